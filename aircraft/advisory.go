@@ -0,0 +1,128 @@
+package aircraft
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+)
+
+// Severity ranks how urgently an advisory needs attention
+type Severity int
+
+const (
+	// Info is a technique reminder
+	Info Severity = iota
+	// Caution calls for extra action before flight
+	Caution
+	// Warning means an operating limit is reached
+	Warning
+)
+
+// String returns the label used when printing advisories
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "INFO"
+	case Caution:
+		return "CAUTION"
+	case Warning:
+		return "WARNING"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Advisory is an operational note raised by the conditions of a flight
+type Advisory struct {
+	Severity Severity
+	Message  string
+	Code     string // Identifies the kind of advisory for programs, e.g. CodeGustSpread; empty for profile limits
+}
+
+// String formats the advisory with its severity label
+func (a Advisory) String() string {
+	return a.Severity.String() + ": " + a.Message
+}
+
+// Conditions are the ambient conditions advisories are checked against
+type Conditions struct {
+	PressureAltitude float64 // in feet
+	Temperature      float64 // in °C
+}
+
+// Limits are the profile's cold-weather operating limits, all in °C
+type Limits struct {
+	FrostTemperature     float64 // At or below this, frost may form on parked aircraft
+	PreheatRecommended   float64 // At or below this, engine preheat is recommended
+	PreheatRequired      float64 // At or below this, the engine must not be started without preheat
+	WinterOilTemperature float64 // Below this, the winter oil grade is recommended
+	WinterOilGrade       string  // Oil grade recommended below WinterOilTemperature
+
+	MaxDemonstratedCrosswind float64 // Maximum demonstrated crosswind component in knots
+}
+
+// Engine describes the engine and fixed-pitch propeller for run-up checks
+type Engine struct {
+	StaticRPMMin float64 // Full-throttle static RPM at sea level standard, lower limit
+	StaticRPMMax float64 // Full-throttle static RPM at sea level standard, upper limit
+
+	LeanAboveDensityAltitude float64 // Density altitude in feet above which the mixture is leaned for takeoff
+	LeaningProcedure         string  // How to lean for takeoff
+}
+
+// StaticRPM returns the expected full-throttle static RPM range at the
+// given density ratio. Full-throttle power of a normally aspirated engine
+// falls with density (Gagg-Ferrar), and a fixed-pitch propeller absorbs
+// power in proportion to RPM cubed.
+func (e Engine) StaticRPM(densityRatio float64) (min, max float64) {
+	power := 1.132*densityRatio - 0.132
+	if power < 0 {
+		power = 0
+	}
+	scale := math.Cbrt(power)
+	return e.StaticRPMMin * scale, e.StaticRPMMax * scale
+}
+
+// Advisories checks the conditions against the profile's operating limits
+// and returns the advisories that apply, most severe first, written in the
+// profile's units
+func (p *Profile) Advisories(c Conditions) []Advisory {
+	var advisories []Advisory
+	u := p.Units
+
+	switch {
+	case c.Temperature <= p.Limits.PreheatRequired:
+		advisories = append(advisories, Advisory{Severity: Warning, Message: fmt.Sprintf(
+			"%s is at or below the %s cold-soak limit; preheat the engine before starting",
+			u.celsius(c.Temperature), u.celsius(p.Limits.PreheatRequired))})
+	case c.Temperature <= p.Limits.PreheatRecommended:
+		advisories = append(advisories, Advisory{Severity: Caution, Message: fmt.Sprintf(
+			"%s is at or below %s; engine preheat is recommended after cold soak",
+			u.celsius(c.Temperature), u.celsius(p.Limits.PreheatRecommended))})
+	}
+
+	if c.Temperature <= p.Limits.FrostTemperature {
+		advisories = append(advisories, Advisory{Severity: Caution,
+			Message: "Frost may be present; remove all frost, ice and snow from wings, tail and control surfaces before flight"})
+	}
+
+	if p.Limits.WinterOilGrade != "" && c.Temperature < p.Limits.WinterOilTemperature {
+		advisories = append(advisories, Advisory{Severity: Info, Message: fmt.Sprintf(
+			"Below %s, %s oil is recommended", u.celsius(p.Limits.WinterOilTemperature), p.Limits.WinterOilGrade)})
+	}
+
+	densityAltitude := atmosphere.DensityAltitude(c.PressureAltitude, c.Temperature)
+	if p.Engine.LeaningProcedure != "" && densityAltitude > p.Engine.LeanAboveDensityAltitude {
+		rpmMin, rpmMax := p.Engine.StaticRPM(atmosphere.DensityRatio(c.PressureAltitude, c.Temperature))
+		advisories = append(advisories, Advisory{Severity: Caution, Message: fmt.Sprintf(
+			"Density altitude %s: %s; expect %.0f-%.0f static RPM at full throttle",
+			u.feet(densityAltitude), p.Engine.LeaningProcedure, math.Floor(rpmMin/10)*10, math.Ceil(rpmMax/10)*10)})
+	}
+
+	sort.SliceStable(advisories, func(i, j int) bool {
+		return advisories[i].Severity > advisories[j].Severity
+	})
+	return advisories
+}
@@ -0,0 +1,126 @@
+// Package aircraft describes the aircraft profiles the calculators support
+package aircraft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// Profile describes an aircraft type and its performance data
+type Profile struct {
+	ID   string // Short identifier, e.g. "pa28-161"
+	Name string // Display name
+
+	// Version is the version of the chart bundle the profile was loaded
+	// from; empty for a profile built into the binary
+	Version string
+
+	// These create calculators for the profile's charts
+	NewTakeoffCalculator func() *performance.TakeoffCalculator
+	NewClimbCalculator   func() *performance.ClimbCalculator
+	NewCruiseCalculator  func() *performance.CruiseCalculator
+	NewLandingCalculator func() *performance.LandingCalculator
+
+	// Calibration holds the POH airspeed calibration tables (IAS to CAS),
+	// one per flap setting, applied by the calculators above
+	Calibration performance.AirspeedCalibrations
+
+	// Limits and Engine hold the operating data checked by Advisories
+	Limits Limits
+	Engine Engine
+
+	// WeightBalance holds the weight data used to build up takeoff weight
+	WeightBalance wb.Aircraft
+
+	// Glide is the engine-out glide used for glide footprints
+	Glide Glide
+
+	// Speeds and Techniques are used to build the takeoff checklist
+	Speeds     Speeds
+	Techniques []Technique
+
+	// Units is the unit system of the advisories and the checklist, set by
+	// WithUnits
+	Units UnitSystem
+
+	// Equipment lists the optional equipment that can be selected per
+	// flight; Installed holds the items applied by WithEquipment
+	Equipment []Equipment
+	Installed []Equipment
+
+	// Variants lists the engine and STC conversions of the airframe that
+	// can be selected; Variant is the one applied by WithVariant, if any
+	Variants []Variant
+	Variant  *Variant
+
+	// Golden holds reference points read from the POH charts, used to
+	// verify that the digitized data reproduces the book
+	Golden []GoldenCase
+}
+
+// registry holds the installed profiles by ID, the built-in ones and those
+// installed from chart bundles, which may be replaced while it is read
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Profile{}
+	builtin    = map[string]bool{}
+)
+
+// register installs a built-in profile
+func register(p *Profile) {
+	registry[p.ID] = p
+	builtin[p.ID] = true
+}
+
+// Install installs a profile loaded from a chart bundle, replacing the
+// one installed with the same ID. A built-in profile cannot be replaced.
+func Install(p *Profile) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if builtin[p.ID] {
+		return fmt.Errorf("%s is a built-in profile", p.ID)
+	}
+	registry[p.ID] = p
+	return nil
+}
+
+// Uninstall removes a profile installed by Install; built-in profiles stay
+func Uninstall(id string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if !builtin[id] {
+		delete(registry, id)
+	}
+}
+
+// Builtin reports whether a profile ID is built into the binary
+func Builtin(id string) bool {
+	return builtin[id]
+}
+
+// Profiles returns all installed profiles sorted by ID
+func Profiles() []*Profile {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	list := make([]*Profile, 0, len(registry))
+	for _, p := range registry {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Lookup finds an installed profile by ID (case-insensitive)
+func Lookup(id string) (*Profile, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if p, ok := registry[strings.ToLower(strings.TrimSpace(id))]; ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown aircraft profile %q", id)
+}
@@ -0,0 +1,636 @@
+package aircraft
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+func TestInstalledProfilesPassSelfTest(t *testing.T) {
+	profiles := Profiles()
+	if len(profiles) == 0 {
+		t.Fatal("No aircraft profiles installed")
+	}
+
+	for _, p := range profiles {
+		if len(p.Golden) == 0 {
+			t.Errorf("%s: no golden cases", p.ID)
+		}
+		for _, r := range p.SelfTest() {
+			if !r.Pass() {
+				t.Errorf("%s: %s: %v", p.ID, r.Case.Name, r.Err)
+			}
+		}
+	}
+}
+
+func TestSelfTestReportsMismatch(t *testing.T) {
+	p := &Profile{
+		ID:                   "test",
+		NewTakeoffCalculator: performance.NewTakeoffCalculator,
+		Golden: []GoldenCase{
+			{
+				Name:            "Wrong Distance",
+				Params:          performance.TakeoffParams{PressureAltitude: 0, Temperature: 15, Weight: 2000},
+				TakeoffDistance: 3000,
+				LiftoffSpeed:    46,
+				BarrierSpeed:    52,
+				Tolerance:       50,
+			},
+			{
+				Name:   "Outside Chart",
+				Params: performance.TakeoffParams{PressureAltitude: 9000, Temperature: 15, Weight: 2000},
+			},
+		},
+	}
+
+	for _, r := range p.SelfTest() {
+		if r.Pass() {
+			t.Errorf("%s: expected failure, but passed", r.Case.Name)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	p, err := Lookup("PA28-161")
+	if err != nil || p.ID != "pa28-161" {
+		t.Errorf("Lookup: got %v, %v", p, err)
+	}
+	if _, err := Lookup("c172s"); err == nil {
+		t.Error("Expected error for unknown profile, but got none")
+	}
+}
+
+func TestColdWeatherAdvisories(t *testing.T) {
+	p, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		temperature float64
+		severities  []Severity
+	}{
+		{25, nil},
+		{3, []Severity{Caution, Info}},
+		{-6, []Severity{Caution, Caution, Info}},
+		{-20, []Severity{Warning, Caution, Info}},
+	}
+
+	for _, tc := range tests {
+		advisories := p.Advisories(Conditions{Temperature: tc.temperature})
+
+		var got []Severity
+		for _, a := range advisories {
+			got = append(got, a.Severity)
+		}
+		if len(got) != len(tc.severities) {
+			t.Errorf("%.0f°C: expected severities %v, got %v", tc.temperature, tc.severities, advisories)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.severities[i] {
+				t.Errorf("%.0f°C: expected severities %v, got %v", tc.temperature, tc.severities, advisories)
+				break
+			}
+		}
+	}
+}
+
+func TestHighDensityAltitudeAdvisory(t *testing.T) {
+	p, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if advisories := p.Advisories(Conditions{PressureAltitude: 0, Temperature: 15}); len(advisories) != 0 {
+		t.Errorf("Sea level standard: expected no advisories, got %v", advisories)
+	}
+
+	advisories := p.Advisories(Conditions{PressureAltitude: 5000, Temperature: 30})
+	if len(advisories) != 1 || !strings.Contains(advisories[0].Message, "lean") {
+		t.Errorf("High density altitude: expected a leaning advisory, got %v", advisories)
+	}
+}
+
+func TestStaticRPM(t *testing.T) {
+	engine := Engine{StaticRPMMin: 2325, StaticRPMMax: 2425}
+
+	if lo, hi := engine.StaticRPM(1); math.Abs(lo-2325) > 1e-9 || math.Abs(hi-2425) > 1e-9 {
+		t.Errorf("Sea level: expected 2325-2425, got %.0f-%.0f", lo, hi)
+	}
+
+	// 8000 ft standard (density ratio 0.786) loses roughly 200 RPM
+	lo, hi := engine.StaticRPM(0.786)
+	if lo < 2080 || lo > 2160 || hi < 2170 || hi > 2250 {
+		t.Errorf("8000 ft: got %.0f-%.0f", lo, hi)
+	}
+}
+
+func TestTakeoffChecklist(t *testing.T) {
+	p, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	technique, err := p.Technique("")
+	if err != nil || technique.ID != "short-field" {
+		t.Fatalf("Default technique: got %v, %v", technique, err)
+	}
+	if _, err := p.Technique("soft-field"); err == nil {
+		t.Error("Expected error for unknown technique, but got none")
+	}
+
+	result := &performance.TakeoffResult{TakeoffDistance: 2100, LiftoffSpeed: 50, BarrierSpeed: 55}
+	items := map[string]string{}
+	for _, item := range p.TakeoffChecklist(technique, result) {
+		items[item.Item] = item.Setting
+	}
+
+	expected := map[string]string{
+		"Flaps":       "25° (second notch)",
+		"Rotate":      "50 KIAS",
+		"Vx / Vy":     "63 / 79 KIAS",
+		"Abort point": "reject if below 35 KIAS at runway midpoint",
+	}
+	for item, setting := range expected {
+		if items[item] != setting {
+			t.Errorf("%s: expected %q, got %q", item, setting, items[item])
+		}
+	}
+
+	result.Roll = &performance.RollTiming{Distance: 1200, Time: 28, Acceleration: 2, LiftoffTrueAirspeed: 56, LiftoffGroundspeed: 56}
+	checklist := p.TakeoffChecklist(technique, result)
+	if got, want := checklist[len(checklist)-1].Setting, "reject if below 35 KIAS at runway midpoint (due about 20 s into the roll)"; got != want {
+		t.Errorf("Abort point with a roll estimate: expected %q, got %q", want, got)
+	}
+}
+
+func TestDualUnits(t *testing.T) {
+	p, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = p.WithUnits(DualUnits)
+	technique, _ := p.Technique("")
+
+	result := &performance.TakeoffResult{TakeoffDistance: 2100, LiftoffSpeed: 50, BarrierSpeed: 55,
+		Roll: &performance.RollTiming{Distance: 1200, Time: 28, Acceleration: 2, LiftoffTrueAirspeed: 56, LiftoffGroundspeed: 56}}
+	items := map[string]string{}
+	for _, item := range p.TakeoffChecklist(technique, result) {
+		items[item.Item] = item.Setting
+	}
+	expected := map[string]string{
+		"Rotate":             "50 KIAS (93 km/h)",
+		"Obstacle clearance": "55 KIAS (102 km/h) until clear",
+		"Vx / Vy":            "63 / 79 KIAS (117 / 146 km/h)",
+		"Abort point":        "reject if below 35 KIAS (65 km/h) at runway midpoint (due about 20 s into the roll)",
+	}
+	for item, setting := range expected {
+		if items[item] != setting {
+			t.Errorf("%s: expected %q, got %q", item, setting, items[item])
+		}
+	}
+	if got := p.WithASI(ASIBoth).TakeoffChecklist(technique, result)[2].Setting; got != "50 KIAS (58 mph) (93 km/h)" {
+		t.Errorf("Rotate on both ASI scales: got %q", got)
+	}
+
+	ref := time.Date(2026, time.January, 15, 18, 0, 0, 0, time.UTC)
+	obs, err := weather.ParseMETAR("KJYO 151753Z 17008G20KT 1SM BR OVC008 M02/M03 A3002", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, a := range WeatherAdvisoriesIn(obs, p.Units) {
+		messages = append(messages, a.Message)
+	}
+	for _, want := range []string{"-2°C (28°F) with visible moisture", "visibility 1 SM (1.6 km)", "ceiling 800 ft (244 m)",
+		"Gusts to 20 kt (37 km/h), 12 kt (22 km/h) over"} {
+		if !strings.Contains(strings.Join(messages, "\n"), want) {
+			t.Errorf("Expected %q in the weather advisories, got %q", want, messages)
+		}
+	}
+	for _, a := range WeatherAdvisories(obs) {
+		if strings.Contains(a.Message, "km") || strings.Contains(a.Message, "°F") {
+			t.Errorf("Expected POH units without WithUnits, got %q", a.Message)
+		}
+	}
+
+	advisories := p.Advisories(Conditions{PressureAltitude: 5000, Temperature: 30})
+	if len(advisories) == 0 || !strings.HasPrefix(advisories[0].Message, "Density altitude 7801 ft (2378 m):") {
+		t.Errorf("Expected the density altitude in feet and meters, got %v", advisories)
+	}
+	plausibility := p.Plausibility(Inputs{PressureAltitude: 1500, Temperature: 15, Weight: 220})
+	if len(plausibility) != 1 || !strings.Contains(plausibility[0].Message, "weight 220 lbs (100 kg) is below") {
+		t.Errorf("Expected the weight in pounds and kilograms, got %v", plausibility)
+	}
+}
+
+func TestReadFleetCSV(t *testing.T) {
+	fleet, err := ReadFleetCSV(strings.NewReader("tail,aircraft,empty_weight,fuel_gal,equipment\nn123ab,pa28-161,1560,36,adsb-out\nN456CD,PA28-161,,,\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fleet) != 2 {
+		t.Fatalf("Expected 2 airframes, got %d", len(fleet))
+	}
+	if fleet[0].Registration != "N123AB" || fleet[0].WeightBalance.EmptyWeight != 1563 || fleet[0].FuelGallons != 36 {
+		t.Errorf("Unexpected first airframe %+v", fleet[0])
+	}
+
+	// Defaults come from the profile
+	profile := fleet[1].Profile
+	if fleet[1].WeightBalance.EmptyWeight != profile.WeightBalance.EmptyWeight || fleet[1].FuelGallons != profile.WeightBalance.FuelCapacity {
+		t.Errorf("Expected profile defaults, got %+v", fleet[1])
+	}
+
+	for _, data := range []string{
+		"",
+		"tail,empty_weight\nN1,1500\n",
+		"tail,aircraft\nN1,c172\n",
+		"tail,aircraft,fuel_gal\nN1,pa28-161,full\n",
+		"tail,aircraft\n,pa28-161\n",
+		"tail,aircraft,equipment\nN1,pa28-161,floats\n",
+		"tail,aircraft,variant\nN1,pa28-161,180hp\n",
+	} {
+		if _, err := ReadFleetCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
+
+func TestWithEquipment(t *testing.T) {
+	base, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configured, err := base.WithEquipment([]string{"no-fairings", "ADSB-OUT"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(configured.Installed) != 2 {
+		t.Errorf("Expected 2 installed items, got %v", configured.Installed)
+	}
+	if configured.WeightBalance.EmptyWeight != base.WeightBalance.EmptyWeight+3 {
+		t.Errorf("Expected empty weight %.0f, got %.0f", base.WeightBalance.EmptyWeight+3, configured.WeightBalance.EmptyWeight)
+	}
+
+	params := performance.CruiseParams{PressureAltitude: 4000, Temperature: 7.1, Power: 65}
+	standard, _ := base.NewCruiseCalculator().CalculateCruise(params)
+	slower, err := configured.NewCruiseCalculator().CalculateCruise(params)
+	if err != nil || math.Abs(slower.TrueAirspeed-(standard.TrueAirspeed-2)) > 1e-9 {
+		t.Errorf("Expected %.1f KTAS, got %+v (%v)", standard.TrueAirspeed-2, slower, err)
+	}
+
+	// The registered profile is unchanged
+	if len(base.Installed) != 0 || base.WeightBalance.EmptyWeight != 1500 {
+		t.Errorf("Base profile modified: %+v", base.Installed)
+	}
+
+	if _, err := base.WithEquipment([]string{"floats"}); err == nil || !strings.Contains(err.Error(), "adsb-out") {
+		t.Errorf("Expected an error listing the available equipment, got %v", err)
+	}
+}
+
+func TestWithEquipmentAdjustments(t *testing.T) {
+	base, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The POH gives no penalties, so use a hypothetical ski installation
+	profile := *base
+	profile.Equipment = []Equipment{{ID: "skis", Name: "Skis installed", TakeoffDistance: 0.2, ClimbRate: -0.1}}
+	configured, err := profile.WithEquipment([]string{"skis"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	params := performance.TakeoffParams{PressureAltitude: 1500, Temperature: 20, Weight: 2325}
+	standard, _ := base.NewTakeoffCalculator().CalculateTakeoff(params)
+	longer, err := configured.NewTakeoffCalculator().CalculateTakeoff(params)
+	if err != nil || math.Abs(longer.TakeoffDistance-standard.TakeoffDistance*1.2) > 1e-9 {
+		t.Errorf("Expected %.0f ft, got %+v (%v)", standard.TakeoffDistance*1.2, longer, err)
+	}
+	if len(longer.Adjustments) != 1 || longer.Adjustments[0].Description != "Skis installed" {
+		t.Errorf("Expected the skis to be disclosed, got %v", longer.Adjustments)
+	}
+
+	climbParams := performance.ClimbParams{PressureAltitude: 1500, Temperature: 20, CruiseAltitude: 5500}
+	climb, err := configured.NewClimbCalculator().CalculateClimb(climbParams)
+	if err != nil || len(climb.Adjustments) != 1 || climb.Adjustments[0].Factor != -0.1 {
+		t.Errorf("Expected the climb penalty to be disclosed, got %+v (%v)", climb, err)
+	}
+
+	if got, want := profile.Equipment[0].String(), "Skis installed (+20% takeoff distance, -10% rate of climb)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWithVariant(t *testing.T) {
+	base, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same, err := base.WithVariant(""); err != nil || same != base {
+		t.Errorf("Expected the base profile without a variant, got %v", err)
+	}
+	if _, err := base.WithVariant("180hp"); err == nil || !strings.Contains(err.Error(), "no engine or STC variants") {
+		t.Errorf("Expected an error for a profile without variants, got %v", err)
+	}
+
+	// The profile ships no conversion charts, so use a hypothetical
+	// conversion whose supplement chart is 10% shorter than the POH's
+	chart := base.NewTakeoffCalculator().Chart()
+	chart.Source.Document = "STC supplement"
+	for _, row := range chart.Distances {
+		for i := range row {
+			row[i] *= 0.9
+		}
+	}
+	engine := base.Engine
+	engine.StaticRPMMin, engine.StaticRPMMax = 2250, 2350
+	profile := *base
+	profile.Variants = []Variant{{
+		ID:   "180hp",
+		Name: "Lycoming O-360, 180 hp",
+		NewTakeoffCalculator: func() *performance.TakeoffCalculator {
+			c, err := performance.NewChartTakeoffCalculator(chart)
+			if err != nil {
+				panic(err)
+			}
+			return c
+		},
+		Engine: &engine,
+	}}
+
+	configured, err := profile.WithVariant("180HP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configured.Name != base.Name+" (Lycoming O-360, 180 hp)" || configured.Variant == nil || configured.Variant.ID != "180hp" {
+		t.Errorf("Unexpected variant profile %q, %+v", configured.Name, configured.Variant)
+	}
+	if configured.Engine.StaticRPMMax != 2350 || len(configured.Golden) != 0 {
+		t.Errorf("Expected the variant's engine and no POH golden cases, got %+v, %d cases", configured.Engine, len(configured.Golden))
+	}
+	if configured.WeightBalance.EmptyWeight != base.WeightBalance.EmptyWeight {
+		t.Errorf("Expected the airframe's weight and balance")
+	}
+
+	// Equipment applies to the variant's chart, and the other charts are
+	// the profile's
+	configured, err = configured.WithEquipment([]string{"no-fairings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := performance.TakeoffParams{PressureAltitude: 1500, Temperature: 20, Weight: 2325}
+	standard, _ := base.NewTakeoffCalculator().CalculateTakeoff(params)
+	shorter, err := configured.NewTakeoffCalculator().CalculateTakeoff(params)
+	if err != nil || math.Abs(shorter.TakeoffDistance-standard.TakeoffDistance*0.9) > 0.5 || configured.NewTakeoffCalculator().Source().Document != "STC supplement" {
+		t.Errorf("Expected %.0f ft from the supplement, got %+v (%v)", standard.TakeoffDistance*0.9, shorter, err)
+	}
+	climbParams := performance.ClimbParams{PressureAltitude: 1500, Temperature: 20, CruiseAltitude: 5500}
+	want, _ := base.NewClimbCalculator().CalculateClimb(climbParams)
+	if got, err := configured.NewClimbCalculator().CalculateClimb(climbParams); err != nil || got.Time != want.Time {
+		t.Errorf("Expected the profile's climb chart, got %+v (%v)", got, err)
+	}
+
+	if _, err := profile.WithVariant("150hp"); err == nil || !strings.Contains(err.Error(), "available: 180hp") {
+		t.Errorf("Expected an error listing the available variants, got %v", err)
+	}
+	if base.Variant != nil || base.Engine.StaticRPMMax != 2425 {
+		t.Errorf("Base profile modified")
+	}
+}
+
+func TestGlideFootprint(t *testing.T) {
+	g := Glide{Speed: 73, Ratio: 9}
+	from := geo.Point{Latitude: 39.0780, Longitude: -77.5575}
+
+	still := g.Footprint(from, 1000, 1389, 13, wind.Wind{})
+	if math.Abs(still.Radius-1.481) > 0.001 || still.Drift != 0 || geo.Distance(from, still.Center) > 1e-9 {
+		t.Errorf("Still air: got %+v, expected 1.48 nm around the start", still)
+	}
+	if still.Time < 1.1 || still.Time > 1.25 {
+		t.Errorf("Still air: expected about 1.2 minutes, got %.2f", still.Time)
+	}
+
+	windy := g.Footprint(from, 1000, 1389, 13, wind.Wind{From: wind.TrueDirection(270), Speed: 20})
+	if math.Abs(windy.Drift-20*windy.Time/60) > 1e-9 || math.Abs(geo.Distance(from, windy.Center)-windy.Drift) > 0.001 {
+		t.Errorf("Wind: drift %.3f nm over %.2f minutes, center %.3f nm away", windy.Drift, windy.Time, geo.Distance(from, windy.Center))
+	}
+	if c := geo.InitialCourse(from, windy.Center); math.Abs(c-90) > 0.5 {
+		t.Errorf("Wind: expected the footprint carried east, got %.1f°", c)
+	}
+	if outline := windy.Outline(72); len(outline) != 72 || math.Abs(geo.Distance(windy.Center, outline[0])-windy.Radius) > 0.001 {
+		t.Errorf("Wind: unexpected outline %v", outline[:1])
+	}
+}
+
+func TestWeatherAdvisories(t *testing.T) {
+	ref := time.Date(2026, time.October, 15, 18, 5, 0, 0, time.UTC)
+	tests := []struct {
+		metar string
+		codes []string
+	}{
+		{"KJYO 151753Z 17008KT 10SM CLR 24/12 A3002", nil},
+		{"KJYO 151753Z 17015G27KT 10SM CLR 24/12 A3002", []string{CodeGustSpread}},
+		{"KJYO 151753Z 17008KT 10SM VCTS SCT050CB 24/18 A3002", []string{CodeConvective}},
+		{"KJYO 151753Z 22012G30KT 3SM +TSRA BKN030CB 24/20 A3002", []string{CodeThunderstorm, CodeConvective, CodeGustSpread}},
+		{"KAPA 151753Z 36008KT 10SM VCSH FEW100 32/05 A3021", []string{CodeDryAir}},
+		{"KJYO 151753Z 36008KT 10SM BKN008 M02/M04 A3021", []string{CodeIcing}},
+		{"KJYO 151753Z 36008KT 3SM BR FEW008 M02/M03 A3021", []string{CodeIcing}},
+		{"KJYO 151753Z 36008KT 10SM FEW100 M02/M10 A3021", nil},
+		{"KJYO 151753Z 36008KT 2SM -FZRA OVC010 01/00 A3021", []string{CodeIcing}},
+		{"KJYO 151753Z 36008KT 1/2SM FZFG M01/M01 A3021", []string{CodeIcing}},
+	}
+	for _, tc := range tests {
+		obs, err := weather.ParseMETAR(tc.metar, ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var codes []string
+		for _, a := range WeatherAdvisories(obs) {
+			codes = append(codes, a.Code)
+		}
+		if strings.Join(codes, ",") != strings.Join(tc.codes, ",") {
+			t.Errorf("%s: expected %v, got %v", tc.metar, tc.codes, codes)
+		}
+	}
+
+	obs, _ := weather.ParseMETAR("KAPA 151753Z 36008KT 10SM VCSH FEW100 32/05 A3021", ref)
+	if a := WeatherAdvisories(obs); a[0].Severity != Caution {
+		t.Errorf("Expected dry air with showers about to be a caution, got %v", a[0])
+	}
+}
+
+func TestASIUnits(t *testing.T) {
+	tests := []struct {
+		asi  ASIUnits
+		want string
+	}{
+		{"", "63 / 79 KIAS"},
+		{ASIKnots, "63 / 79 KIAS"},
+		{ASIMPH, "72 / 91 mph IAS"},
+		{ASIBoth, "63 / 79 KIAS (72 / 91 mph)"},
+	}
+	for _, tt := range tests {
+		if got := tt.asi.Format(63, 79); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.asi, tt.want, got)
+		}
+	}
+	if got := ASIBoth.Callout(50); got != "50/58 mph" {
+		t.Errorf("Expected callout 50/58 mph, got %q", got)
+	}
+
+	if asi, err := ParseASIUnits(" MPH "); err != nil || asi != ASIMPH {
+		t.Errorf("Expected mph, got %q, %v", asi, err)
+	}
+	if _, err := ParseASIUnits("furlongs"); err == nil {
+		t.Error("Expected an error for an unknown scale")
+	}
+
+	p, _ := Lookup("pa28-161")
+	if mph := p.WithASI(ASIMPH); mph.Speeds.ASI != ASIMPH || p.Speeds.ASI != ASIKnots {
+		t.Errorf("Expected a copy on the mph scale leaving the profile on knots, got %q and %q", mph.Speeds.ASI, p.Speeds.ASI)
+	}
+}
+
+func TestReferencePoints(t *testing.T) {
+	p, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases, err := ReadReferenceCSV(strings.NewReader(`# Figure 5-6
+name,pressure_altitude,temperature_f,weight,wind,distance,liftoff_speed,barrier_speed,tolerance
+POH example,1500,80,2325,15,2100,50,55,
+,0,59,2000,,1425,,,100
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 2 || cases[1].Name != "Line 4" || cases[1].Params.Temperature != 15 || cases[1].Tolerance != 100 || cases[0].LiftoffSpeed != 50 {
+		t.Fatalf("Unexpected cases %+v", cases)
+	}
+
+	cases, err = p.DeriveTolerances(cases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tol := cases[0].Tolerance; tol < 50 || tol > 100 || math.Mod(tol, 10) != 0 {
+		t.Errorf("Expected at least 2%% of 2100 ft rounded up to 10 ft, got %v", tol)
+	}
+	if cases[1].Tolerance != 100 {
+		t.Errorf("Expected the given tolerance to be kept, got %v", cases[1].Tolerance)
+	}
+	for _, r := range p.Check(cases) {
+		if !r.Pass() {
+			t.Errorf("%s: %v", r.Case.Name, r.Err)
+		}
+	}
+
+	var src strings.Builder
+	if err := p.WriteChartTest(&src, cases, "profiles", "points.csv"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package profiles", "func TestPA28161ChartReadings(t *testing.T)", `aircraft.Lookup("pa28-161")`, `name: "POH example"`, "tolerance: 100,"} {
+		if !strings.Contains(src.String(), want) {
+			t.Errorf("Expected %q in the generated test:\n%s", want, src.String())
+		}
+	}
+
+	if _, err := p.DeriveTolerances([]GoldenCase{{Name: "Too High", Params: performance.TakeoffParams{PressureAltitude: 9000, Temperature: 15, Weight: 2000}, TakeoffDistance: 3000}}); err == nil || !strings.HasPrefix(err.Error(), "Too High: ") {
+		t.Errorf("Expected a point outside the chart to be reported, got %v", err)
+	}
+	for _, csv := range []string{
+		"pressure_altitude,temperature_c,weight\n0,15,2000\n",
+		"pressure_altitude,weight,distance\n0,2000,1400\n",
+		"pressure_altitude,temperature_c,weight,distance\n0,warm,2000,1400\n",
+	} {
+		if _, err := ReadReferenceCSV(strings.NewReader(csv)); err == nil {
+			t.Errorf("Expected an error for %q", csv)
+		}
+	}
+}
+
+func TestPlausibility(t *testing.T) {
+	p, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	normal := Inputs{Weight: 2300, PressureAltitude: 500, Temperature: 25, Dewpoint: 15, HasDewpoint: true,
+		WindSpeed: 10, WindGust: 18, FieldElevation: 389, HasElevation: true}
+	if advisories := p.Plausibility(normal); len(advisories) != 0 {
+		t.Errorf("Expected no advisories for plausible inputs, got %v", advisories)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Inputs)
+		code   string
+		want   string
+	}{
+		{"dropped digit", func(in *Inputs) { in.Weight = 220 }, CodeImplausibleWeight, "was 2200 lbs meant?"},
+		{"fahrenheit", func(in *Inputs) { in.Temperature, in.Dewpoint = 95, 60 }, CodeImplausibleTemperature, "if it was 95°F, that is 35°C"},
+		{"too cold", func(in *Inputs) { in.Temperature, in.Dewpoint = -60, -65 }, CodeImplausibleTemperature, "colder"},
+		{"swapped dew point", func(in *Inputs) { in.Temperature, in.Dewpoint = 15, 25 }, CodeImplausibleDewpoint, "swapped"},
+		{"dry coastal field", func(in *Inputs) { in.Temperature, in.Dewpoint = 38, -20 }, CodeImplausibleDewpoint, "sign"},
+		{"gusts in calm", func(in *Inputs) { in.WindSpeed = 0 }, CodeImplausibleWind, "calm"},
+		{"gusts below the wind", func(in *Inputs) { in.WindSpeed, in.WindGust = 18, 10 }, CodeImplausibleWind, "swapped"},
+		{"density altitude", func(in *Inputs) { in.PressureAltitude = 3500 }, CodeImplausibleAltitude, "26.81 inHg"},
+	}
+	for _, tc := range tests {
+		in := normal
+		tc.modify(&in)
+		advisories := p.Plausibility(in)
+		if len(advisories) != 1 || advisories[0].Code != tc.code || advisories[0].Severity != Caution ||
+			!strings.Contains(advisories[0].Message, tc.want) {
+			t.Errorf("%s: expected a %s caution containing %q, got %v", tc.name, tc.code, tc.want, advisories)
+		}
+	}
+
+	// A high field may be that dry, and checks without their figures are skipped
+	dry := Inputs{Weight: 2300, PressureAltitude: 5000, Temperature: 38, Dewpoint: -20, HasDewpoint: true, FieldElevation: 5000, HasElevation: true}
+	if advisories := p.Plausibility(dry); len(advisories) != 0 {
+		t.Errorf("Expected no advisories for a dry high field, got %v", advisories)
+	}
+	if advisories := p.Plausibility(Inputs{PressureAltitude: 5000, Temperature: 15}); len(advisories) != 0 {
+		t.Errorf("Expected no advisories without the weight, dew point, or elevation, got %v", advisories)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	base, err := Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := *base
+	p.ID, p.Version = "n8123x", "2026-10-01"
+	if err := Install(&p); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := Lookup("N8123X"); err != nil || found != &p || Builtin(p.ID) {
+		t.Errorf("Expected the installed profile, got %v, %v", found, err)
+	}
+	if err := Install(base); err == nil {
+		t.Error("Expected a built-in profile not to be replaced")
+	}
+
+	Uninstall(p.ID)
+	Uninstall(base.ID)
+	if _, err := Lookup("n8123x"); err == nil {
+		t.Error("Expected the profile uninstalled")
+	}
+	if _, err := Lookup("pa28-161"); err != nil {
+		t.Error("Expected the built-in profile kept")
+	}
+}
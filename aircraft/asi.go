@@ -0,0 +1,67 @@
+package aircraft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// ASIUnits is the airspeed indicator scale speeds are called out in, so
+// the numbers match the needle the pilot is watching
+type ASIUnits string
+
+// Airspeed indicator scales
+const (
+	ASIKnots ASIUnits = "knots" // Knots only
+	ASIMPH   ASIUnits = "mph"   // Statute miles per hour only, for older panels
+	ASIBoth  ASIUnits = "both"  // Knots with miles per hour beside them
+)
+
+// ParseASIUnits parses an airspeed indicator scale: knots, mph or both
+func ParseASIUnits(s string) (ASIUnits, error) {
+	switch u := ASIUnits(strings.ToLower(strings.TrimSpace(s))); u {
+	case ASIKnots, ASIMPH, ASIBoth:
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown airspeed indicator scale %q, expected knots, mph or both", s)
+}
+
+// Format formats indicated airspeeds in knots on the scale, joined with a
+// slash: "50 KIAS", "58 mph IAS" or "50 KIAS (58 mph)", and "63 / 79 KIAS"
+// for two. Knots when the scale is not set.
+func (u ASIUnits) Format(kias ...float64) string {
+	knots, mph := make([]string, len(kias)), make([]string, len(kias))
+	for i, v := range kias {
+		knots[i] = fmt.Sprintf("%.0f", v)
+		mph[i] = fmt.Sprintf("%.0f", units.NauticalToStatute(v))
+	}
+	switch u {
+	case ASIMPH:
+		return strings.Join(mph, " / ") + " mph IAS"
+	case ASIBoth:
+		return strings.Join(knots, " / ") + " KIAS (" + strings.Join(mph, " / ") + " mph)"
+	}
+	return strings.Join(knots, " / ") + " KIAS"
+}
+
+// Callout formats a speed as a bare number on the primary scale, with mph
+// marked, for one-line summaries: "50", "58 mph" or "50/58 mph"
+func (u ASIUnits) Callout(kias float64) string {
+	mph := units.NauticalToStatute(kias)
+	switch u {
+	case ASIMPH:
+		return fmt.Sprintf("%.0f mph", mph)
+	case ASIBoth:
+		return fmt.Sprintf("%.0f/%.0f mph", kias, mph)
+	}
+	return fmt.Sprintf("%.0f", kias)
+}
+
+// WithASI returns a copy of the profile whose speeds are called out on
+// another airspeed indicator scale
+func (p *Profile) WithASI(u ASIUnits) *Profile {
+	configured := *p
+	configured.Speeds.ASI = u
+	return &configured
+}
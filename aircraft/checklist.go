@@ -0,0 +1,77 @@
+package aircraft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// abortSpeedFraction is the fraction of rotation speed that should be
+// reached by the runway midpoint, or the takeoff is rejected
+const abortSpeedFraction = 0.7
+
+// Speeds are the profile's V-speeds in KIAS, and the scale they are
+// called out in
+type Speeds struct {
+	Vx  float64  // Best angle of climb
+	Vy  float64  // Best rate of climb
+	ASI ASIUnits // Primary scale of the airspeed indicator; knots when empty
+}
+
+// Technique is a takeoff technique the profile's chart applies to
+type Technique struct {
+	ID    string // Short identifier, e.g. "short-field"
+	Name  string // Display name
+	Flaps string // Flap setting for takeoff
+	Trim  string // Trim setting for takeoff
+}
+
+// ChecklistItem is one challenge/response line of a checklist
+type ChecklistItem struct {
+	Item    string
+	Setting string
+}
+
+// String formats the item as a dotted checklist line
+func (c ChecklistItem) String() string {
+	dots := 24 - len(c.Item)
+	if dots < 3 {
+		dots = 3
+	}
+	return c.Item + " " + strings.Repeat(".", dots) + " " + c.Setting
+}
+
+// Technique finds a takeoff technique by ID, or returns the profile's
+// default (first) technique when id is empty
+func (p *Profile) Technique(id string) (*Technique, error) {
+	if id == "" && len(p.Techniques) > 0 {
+		return &p.Techniques[0], nil
+	}
+	for i := range p.Techniques {
+		if strings.EqualFold(p.Techniques[i].ID, id) {
+			return &p.Techniques[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no takeoff technique %q", p.ID, id)
+}
+
+// TakeoffChecklist builds the pre-takeoff configuration block for a
+// technique and the speeds computed for the takeoff. With a roll estimate
+// the abort point gives the time the abort speed is due, as a cross-check
+// for pilots timing their roll.
+func (p *Profile) TakeoffChecklist(t *Technique, result *performance.TakeoffResult) []ChecklistItem {
+	asi, u := p.Speeds.ASI, p.Units
+	abort := fmt.Sprintf("reject if below %s at runway midpoint", u.airspeed(asi, result.LiftoffSpeed*abortSpeedFraction))
+	if result.Roll != nil {
+		abort += fmt.Sprintf(" (due about %.0f s into the roll)", result.Roll.TimeToAirspeed(abortSpeedFraction))
+	}
+	return []ChecklistItem{
+		{"Flaps", t.Flaps},
+		{"Trim", t.Trim},
+		{"Rotate", u.airspeed(asi, result.LiftoffSpeed)},
+		{"Obstacle clearance", u.airspeed(asi, result.BarrierSpeed) + " until clear"},
+		{"Vx / Vy", u.airspeed(asi, p.Speeds.Vx, p.Speeds.Vy)},
+		{"Abort point", abort},
+	}
+}
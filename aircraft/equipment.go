@@ -0,0 +1,120 @@
+package aircraft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// Equipment is an optional equipment item or configuration change whose
+// effect on the airframe is not part of the POH charts
+type Equipment struct {
+	ID              string  // Short identifier used to select it, e.g. "adsb-out"
+	Name            string  // Description, e.g. "ADS-B Out transponder installed"
+	Weight          float64 // Change in empty weight in pounds
+	CruiseSpeed     float64 // Change in cruise true airspeed in knots
+	TakeoffDistance float64 // Fractional change in takeoff distance, e.g. 0.2 for skis
+	ClimbRate       float64 // Fractional change in rate of climb, e.g. -0.1 for floats
+}
+
+// String summarizes the equipment and its deltas
+func (e Equipment) String() string {
+	var deltas []string
+	if e.Weight != 0 {
+		deltas = append(deltas, fmt.Sprintf("%+.0f lbs", e.Weight))
+	}
+	if e.CruiseSpeed != 0 {
+		deltas = append(deltas, fmt.Sprintf("%+.0f KTAS cruise", e.CruiseSpeed))
+	}
+	if e.TakeoffDistance != 0 {
+		deltas = append(deltas, fmt.Sprintf("%+.0f%% takeoff distance", e.TakeoffDistance*100))
+	}
+	if e.ClimbRate != 0 {
+		deltas = append(deltas, fmt.Sprintf("%+.0f%% rate of climb", e.ClimbRate*100))
+	}
+	if len(deltas) == 0 {
+		return e.Name
+	}
+	return fmt.Sprintf("%s (%s)", e.Name, strings.Join(deltas, ", "))
+}
+
+// WithEquipment returns a copy of the profile configured with the given
+// equipment IDs: the deltas are applied to the empty weight and to the
+// calculators it creates, and the items are listed in Installed
+func (p *Profile) WithEquipment(ids []string) (*Profile, error) {
+	configured := *p
+	configured.Installed = nil
+
+	var speed float64
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		e, err := p.equipment(id)
+		if err != nil {
+			return nil, err
+		}
+		configured.Installed = append(configured.Installed, e)
+		configured.WeightBalance.EmptyWeight += e.Weight
+		speed += e.CruiseSpeed
+	}
+
+	if takeoff := configured.adjustments(func(e Equipment) float64 { return e.TakeoffDistance }); len(takeoff) > 0 {
+		newTakeoff := p.NewTakeoffCalculator
+		configured.NewTakeoffCalculator = func() *performance.TakeoffCalculator {
+			c := newTakeoff()
+			for _, a := range takeoff {
+				c.AdjustDistance(a)
+			}
+			return c
+		}
+	}
+	if climb := configured.adjustments(func(e Equipment) float64 { return e.ClimbRate }); len(climb) > 0 {
+		newClimb := p.NewClimbCalculator
+		configured.NewClimbCalculator = func() *performance.ClimbCalculator {
+			c := newClimb()
+			for _, a := range climb {
+				c.AdjustRateOfClimb(a)
+			}
+			return c
+		}
+	}
+	if speed != 0 {
+		newCruise := p.NewCruiseCalculator
+		configured.NewCruiseCalculator = func() *performance.CruiseCalculator {
+			c := newCruise()
+			c.AdjustTrueAirspeed(speed)
+			return c
+		}
+	}
+	return &configured, nil
+}
+
+// adjustments returns a performance adjustment, named after the item, for
+// each installed item with a non-zero factor
+func (p *Profile) adjustments(factor func(Equipment) float64) []performance.Adjustment {
+	var adjustments []performance.Adjustment
+	for _, e := range p.Installed {
+		if f := factor(e); f != 0 {
+			adjustments = append(adjustments, performance.Adjustment{Description: e.Name, Factor: f})
+		}
+	}
+	return adjustments
+}
+
+// equipment finds an optional equipment item by ID, ignoring case
+func (p *Profile) equipment(id string) (Equipment, error) {
+	var known []string
+	for _, e := range p.Equipment {
+		if strings.EqualFold(e.ID, id) {
+			return e, nil
+		}
+		known = append(known, e.ID)
+	}
+	if len(known) == 0 {
+		return Equipment{}, fmt.Errorf("%s has no optional equipment defined", p.Name)
+	}
+	return Equipment{}, fmt.Errorf("unknown equipment %q for %s (available: %s)", id, p.Name, strings.Join(known, ", "))
+}
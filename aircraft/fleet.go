@@ -0,0 +1,95 @@
+package aircraft
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// Tail is one airframe of a fleet
+type Tail struct {
+	Registration  string
+	Profile       *Profile
+	WeightBalance wb.Aircraft // The profile's data with this airframe's empty weight
+	FuelGallons   float64     // Fuel dispatched with, in US gallons
+}
+
+// ReadFleetCSV reads a fleet list with the columns tail and aircraft (a
+// profile ID), and optionally empty_weight in pounds from the airframe's
+// weight and balance record, fuel_gal for the standard dispatch fuel,
+// variant for an engine or STC conversion of the profile and equipment,
+// the airframe's optional equipment IDs separated by spaces.
+// Missing values default to the profile's empty weight and full fuel.
+func ReadFleetCSV(r io.Reader) ([]Tail, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty fleet list")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"tail", "aircraft"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("fleet list is missing the %s column", name)
+		}
+	}
+
+	var fleet []Tail
+	for n, record := range records[1:] {
+		line := n + 2
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		profile, err := Lookup(field("aircraft"))
+		if err == nil {
+			profile, err = profile.WithVariant(field("variant"))
+		}
+		if err == nil {
+			profile, err = profile.WithEquipment(strings.Fields(field("equipment")))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tail := Tail{
+			Registration:  strings.ToUpper(field("tail")),
+			Profile:       profile,
+			WeightBalance: profile.WeightBalance,
+			FuelGallons:   profile.WeightBalance.FuelCapacity,
+		}
+		if tail.Registration == "" {
+			return nil, fmt.Errorf("line %d: tail is required", line)
+		}
+
+		if s := field("empty_weight"); s != "" {
+			if tail.WeightBalance.EmptyWeight, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid empty_weight %q", line, s)
+			}
+			// The record's empty weight excludes equipment listed separately
+			for _, e := range profile.Installed {
+				tail.WeightBalance.EmptyWeight += e.Weight
+			}
+		}
+		if s := field("fuel_gal"); s != "" {
+			if tail.FuelGallons, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid fuel_gal %q", line, s)
+			}
+		}
+		fleet = append(fleet, tail)
+	}
+	return fleet, nil
+}
@@ -0,0 +1,53 @@
+package aircraft
+
+import (
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// Glide is the power-off glide performance, flaps up
+type Glide struct {
+	Speed float64 // Best glide speed in KIAS
+	Ratio float64 // Still-air distance flown per unit of height lost
+
+	// Calibration is the flaps-up airspeed calibration; nil takes the
+	// indicator as exact
+	Calibration *performance.AirspeedCalibration
+}
+
+// Footprint is the area reachable in a glide: the still-air range circle
+// carried downwind by the drift over the time of the glide
+type Footprint struct {
+	Center geo.Point
+	Radius float64 // Still-air range in nautical miles
+	Drift  float64 // Distance the wind carries the circle in nautical miles
+	Time   float64 // Minutes to the ground
+}
+
+// Footprint returns where the aircraft can glide to from a position at a
+// height in feet above the terrain, in a wind that is the same at every
+// height. The pressure altitude and temperature are those at the start;
+// the true airspeed, from the calibrated glide speed, is taken at the
+// middle of the glide, with the standard lapse rate below.
+func (g Glide) Footprint(from geo.Point, height, pressureAltitude, temperature float64, w wind.Wind) Footprint {
+	mid := pressureAltitude - height/2
+	trueSpeed := performance.TrueAirspeed(g.Calibration.Calibrated(g.Speed), mid, temperature+atmosphere.LapseRate*height/2000)
+
+	radius := height * g.Ratio * units.MetersPerFoot / units.MetersPerNauticalMile
+	minutes := radius / trueSpeed * 60
+	drift := w.Speed * minutes / 60
+	return Footprint{
+		Center: geo.Destination(from, w.From.Degrees+180, drift),
+		Radius: radius,
+		Drift:  drift,
+		Time:   minutes,
+	}
+}
+
+// Outline returns the edge of the footprint as a polygon of n points
+func (f Footprint) Outline(n int) []geo.Point {
+	return geo.Circle(f.Center, f.Radius, n)
+}
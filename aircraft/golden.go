@@ -0,0 +1,68 @@
+package aircraft
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// speedTolerance is the allowed difference in KIAS for golden case speeds
+const speedTolerance = 1
+
+// GoldenCase is a takeoff reference point read from the POH chart
+type GoldenCase struct {
+	Name            string
+	Params          performance.TakeoffParams
+	TakeoffDistance float64 // Expected distance over 50ft barrier in feet
+	LiftoffSpeed    float64 // Expected liftoff speed in KIAS, 0 when not read
+	BarrierSpeed    float64 // Expected 50ft barrier speed in KIAS, 0 when not read
+	Tolerance       float64 // Allowed distance difference in feet
+}
+
+// CaseResult is the outcome of checking one golden case
+type CaseResult struct {
+	Case   GoldenCase
+	Result *performance.TakeoffResult // nil if the calculation failed
+	Err    error                      // Calculation error or description of the mismatch
+}
+
+// Pass reports whether the case reproduced the chart within tolerance
+func (r CaseResult) Pass() bool {
+	return r.Err == nil
+}
+
+// SelfTest runs every golden case for the profile against its calculator
+func (p *Profile) SelfTest() []CaseResult {
+	return p.Check(p.Golden)
+}
+
+// Check runs reference cases against the profile's calculator
+func (p *Profile) Check(cases []GoldenCase) []CaseResult {
+	calculator := p.NewTakeoffCalculator()
+
+	results := make([]CaseResult, len(cases))
+	for i, gc := range cases {
+		results[i].Case = gc
+
+		result, err := calculator.CalculateTakeoff(gc.Params)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Result = result
+
+		switch {
+		case math.Abs(result.TakeoffDistance-gc.TakeoffDistance) > gc.Tolerance:
+			results[i].Err = fmt.Errorf("takeoff distance %.0f ft, expected %.0f ft (±%.0f)",
+				result.TakeoffDistance, gc.TakeoffDistance, gc.Tolerance)
+		case gc.LiftoffSpeed > 0 && math.Abs(result.LiftoffSpeed-gc.LiftoffSpeed) > speedTolerance:
+			results[i].Err = fmt.Errorf("lift-off speed %.1f KIAS, expected %.0f KIAS",
+				result.LiftoffSpeed, gc.LiftoffSpeed)
+		case gc.BarrierSpeed > 0 && math.Abs(result.BarrierSpeed-gc.BarrierSpeed) > speedTolerance:
+			results[i].Err = fmt.Errorf("50 ft speed %.1f KIAS, expected %.0f KIAS",
+				result.BarrierSpeed, gc.BarrierSpeed)
+		}
+	}
+	return results
+}
@@ -0,0 +1,182 @@
+package aircraft
+
+import (
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// pa28161Calibration is the airspeed calibration (POH Figure 5-1), flaps up
+// and flaps 40°. The figures are typical of the type's airspeed system;
+// check them against the airframe's own POH.
+var pa28161Calibration = performance.AirspeedCalibrations{
+	{
+		Flaps:            0,
+		IndicatedSpeeds:  []float64{50, 60, 70, 80, 90, 100, 110, 120, 130, 140},
+		CalibratedSpeeds: []float64{56, 63, 71, 80, 89, 98, 107, 116, 125, 134},
+	},
+	{
+		Flaps:            40,
+		IndicatedSpeeds:  []float64{40, 50, 60, 70, 80, 90, 100},
+		CalibratedSpeeds: []float64{48, 55, 63, 71, 79, 88, 97},
+	},
+}
+
+func init() {
+	register(&Profile{
+		ID:   "pa28-161",
+		Name: "Piper PA-28-161 Cherokee Warrior II",
+
+		// Short-field takeoffs use 25° flaps, nearest the flaps 40° table;
+		// the climb is flaps up
+		NewTakeoffCalculator: func() *performance.TakeoffCalculator {
+			c := performance.NewTakeoffCalculator()
+			c.CalibrateAirspeed(pa28161Calibration.For(25))
+			return c
+		},
+		NewClimbCalculator: func() *performance.ClimbCalculator {
+			c := performance.NewClimbCalculator()
+			c.CalibrateAirspeed(pa28161Calibration.For(0))
+			return c
+		},
+		NewCruiseCalculator:  performance.NewCruiseCalculator,
+		NewLandingCalculator: performance.NewLandingCalculator,
+		Calibration:          pa28161Calibration,
+
+		// Lycoming O-320-D3G cold-weather recommendations (SI 1505, SI 1014)
+		Limits: Limits{
+			FrostTemperature:     3,
+			PreheatRecommended:   -6,
+			PreheatRequired:      -12,
+			WinterOilTemperature: 4,
+			WinterOilGrade:       "SAE 30 (or 15W-50 multigrade)",
+
+			MaxDemonstratedCrosswind: 17,
+		},
+
+		// Static RPM limits for the Sensenich 74DM6-0-58 propeller (TCDS 2A13)
+		Engine: Engine{
+			StaticRPMMin:             2325,
+			StaticRPMMax:             2425,
+			LeanAboveDensityAltitude: 5000,
+			LeaningProcedure:         "lean for takeoff by leaning for maximum RPM at full throttle during the run-up, then enrich slightly",
+		},
+
+		// The empty weight is typical; each airframe's W&B record differs
+		WeightBalance: wb.Aircraft{
+			EmptyWeight:   1500,
+			FuelDensity:   wb.AvgasDensity,
+			FuelCapacity:  48,
+			Seats:         4,
+			MaxBaggage:    200,
+			MaxRampWeight: 2332,
+			TaxiFuel:      1.2, // Max ramp less max takeoff weight
+
+			// The filler neck tabs mark 17 gal per tank
+			FuelPresets:      []wb.FuelPreset{{Name: "tabs", Gallons: 34}},
+			PlanningFuelFlow: 8.1, // 75% power
+
+			// Arms from the POH loading chart, datum 78.4 in ahead of the
+			// wing leading edge; the empty CG is typical
+			EmptyArm:   86.5,
+			FuelArm:    95.0,
+			SeatArms:   []float64{80.5, 80.5, 118.1, 118.1},
+			BaggageArm: 142.8,
+			Envelopes: []wb.Envelope{
+				{
+					// Forward limit 83.0 in up to 1950 lbs, straight to 87.0 in at 2325 lbs
+					Category: wb.CategoryNormal,
+					Points: []wb.EnvelopePoint{
+						{Weight: 1200, Arm: 83.0},
+						{Weight: 1950, Arm: 83.0},
+						{Weight: 2325, Arm: 87.0},
+						{Weight: 2325, Arm: 93.0},
+						{Weight: 1200, Arm: 93.0},
+					},
+				},
+				{
+					// Spins are approved only in the utility category, with
+					// the rear seats and baggage compartment empty
+					Category: wb.CategoryUtility,
+					Points: []wb.EnvelopePoint{
+						{Weight: 1200, Arm: 83.0},
+						{Weight: 1950, Arm: 83.0},
+						{Weight: 2020, Arm: 83.8},
+						{Weight: 2020, Arm: 86.5},
+						{Weight: 1200, Arm: 86.5},
+					},
+					Seats:     2,
+					NoBaggage: true,
+				},
+			},
+		},
+
+		// Best glide at 2325 lbs; the ratio is a typical figure for the
+		// type, check it against the POH glide range chart
+		Glide: Glide{Speed: 73, Ratio: 9, Calibration: pa28161Calibration.For(0)},
+
+		Speeds: Speeds{Vx: 63, Vy: 79, ASI: ASIKnots},
+		Techniques: []Technique{
+			{ID: "short-field", Name: "Short Field, Obstacle Clearance", Flaps: "25° (second notch)", Trim: "Set for takeoff"},
+		},
+
+		// Typical configuration changes; enter the airframe's own figures
+		// from its weight and balance record where they differ
+		Equipment: []Equipment{
+			{ID: "no-fairings", Name: "Wheel fairings removed", CruiseSpeed: -2},
+			{ID: "adsb-out", Name: "ADS-B Out transponder installed", Weight: 3},
+		},
+
+		Golden: []GoldenCase{
+			{
+				Name: "POH Example (Figure 5-6)",
+				Params: performance.TakeoffParams{
+					PressureAltitude: 1500,
+					Temperature:      performance.ConvertFahrenheitToCelsius(80),
+					Weight:           2325,
+					WindComponent:    15,
+				},
+				TakeoffDistance: 2100,
+				LiftoffSpeed:    50,
+				BarrierSpeed:    55,
+				Tolerance:       50,
+			},
+			{
+				Name: "Sea Level Standard Day",
+				Params: performance.TakeoffParams{
+					PressureAltitude: 0,
+					Temperature:      15,
+					Weight:           2000,
+				},
+				TakeoffDistance: 1425,
+				LiftoffSpeed:    46,
+				BarrierSpeed:    52,
+				Tolerance:       50,
+			},
+			{
+				Name: "High Altitude Cold",
+				Params: performance.TakeoffParams{
+					PressureAltitude: 6000,
+					Temperature:      -20,
+					Weight:           1800,
+				},
+				TakeoffDistance: 1900,
+				LiftoffSpeed:    44,
+				BarrierSpeed:    50,
+				Tolerance:       50,
+			},
+			{
+				Name: "Tailwind",
+				Params: performance.TakeoffParams{
+					PressureAltitude: 1500,
+					Temperature:      performance.ConvertFahrenheitToCelsius(80),
+					Weight:           2200,
+					WindComponent:    -5,
+				},
+				TakeoffDistance: 2475,
+				LiftoffSpeed:    48,
+				BarrierSpeed:    54,
+				Tolerance:       50,
+			},
+		},
+	})
+}
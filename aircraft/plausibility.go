@@ -0,0 +1,101 @@
+package aircraft
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// Codes of the plausibility advisories
+const (
+	CodeImplausibleWeight      = "implausible-weight"      // Weight below the empty weight
+	CodeImplausibleTemperature = "implausible-temperature" // Temperature beyond any on record, as if entered in °F
+	CodeImplausibleDewpoint    = "implausible-dewpoint"    // Dew point above the temperature, or far too dry for a low field
+	CodeImplausibleWind        = "implausible-wind"        // Gusts with a calm or stronger steady wind
+	CodeImplausibleAltitude    = "implausible-altitude"    // Pressure altitude far from the field elevation
+)
+
+// Plausibility thresholds
+const (
+	MaxPlausibleTemperature   = 50.0   // Hottest temperature in °C expected at a field
+	MinPlausibleTemperature   = -55.0  // Coldest temperature in °C expected at a field
+	MaxPlausibleSpread        = 45.0   // Temperature/dew point spread in °C beyond which a low field is implausibly dry
+	LowFieldElevation         = 1000.0 // Field elevation in feet below which MaxPlausibleSpread applies
+	MaxPlausibleAltitudeError = 1500.0 // Difference in feet between pressure altitude and field elevation (about 1.5 inHg)
+)
+
+// Inputs are the figures entered for a flight, for the plausibility checks.
+// A check is skipped when the figures it needs were not entered.
+type Inputs struct {
+	Weight           float64 // in pounds, 0 when not entered
+	PressureAltitude float64 // in feet
+	Temperature      float64 // in °C
+	Dewpoint         float64 // in °C
+	HasDewpoint      bool
+	WindSpeed        float64 // Steady wind in knots
+	WindGust         float64 // Gusts in knots, 0 without gusts
+	FieldElevation   float64 // in feet
+	HasElevation     bool
+}
+
+// Plausibility checks the inputs for figures that are within the charts
+// but unlikely to be what was meant, such as a dropped digit in the weight
+// or a temperature in °F, and returns a caution for each to double-check
+// the transcription. These are soft checks, distinct from the chart
+// envelope: the figures may be right. They are written in the profile's
+// units.
+func (p *Profile) Plausibility(in Inputs) []Advisory {
+	var advisories []Advisory
+	u := p.Units
+	add := func(code, message string, args ...interface{}) {
+		advisories = append(advisories, Advisory{Severity: Caution, Code: code,
+			Message: "Check the input: " + fmt.Sprintf(message, args...)})
+	}
+
+	if empty := p.WeightBalance.EmptyWeight; in.Weight > 0 && in.Weight < empty {
+		message := fmt.Sprintf("weight %s is below the %s empty weight", u.pounds(in.Weight), u.pounds(empty))
+		if tenfold := in.Weight * 10; tenfold >= empty && (p.WeightBalance.MaxTakeoffWeight() == 0 || tenfold <= p.WeightBalance.MaxTakeoffWeight()) {
+			message += fmt.Sprintf("; was %s meant?", u.pounds(tenfold))
+		}
+		add(CodeImplausibleWeight, "%s", message)
+	}
+
+	switch {
+	case in.Temperature > MaxPlausibleTemperature:
+		add(CodeImplausibleTemperature, "%s is hotter than any field on record; if it was %.0f°F, that is %.0f°C",
+			u.celsius(in.Temperature), in.Temperature, performance.ConvertFahrenheitToCelsius(in.Temperature))
+	case in.Temperature < MinPlausibleTemperature:
+		add(CodeImplausibleTemperature, "%s is colder than any field is expected to be", u.celsius(in.Temperature))
+	}
+
+	if in.HasDewpoint {
+		elevation := in.PressureAltitude
+		if in.HasElevation {
+			elevation = in.FieldElevation
+		}
+		switch spread := in.Temperature - in.Dewpoint; {
+		case spread < -1:
+			add(CodeImplausibleDewpoint, "dew point %s is above the temperature %s; are they swapped?", u.celsius(in.Dewpoint), u.celsius(in.Temperature))
+		case spread > MaxPlausibleSpread && elevation < LowFieldElevation:
+			add(CodeImplausibleDewpoint, "temperature %s with a %s dew point is far drier than a low field sees; check the dew point's sign",
+				u.celsius(in.Temperature), u.celsius(in.Dewpoint))
+		}
+	}
+
+	switch {
+	case in.WindGust > 0 && in.WindSpeed == 0:
+		add(CodeImplausibleWind, "gusts to %s with a calm steady wind; was the steady wind left out?", u.knots(in.WindGust))
+	case in.WindGust > 0 && in.WindGust <= in.WindSpeed:
+		add(CodeImplausibleWind, "gusts to %s are not above the %s steady wind; are they swapped?", u.knots(in.WindGust), u.knots(in.WindSpeed))
+	}
+
+	if in.HasElevation {
+		if diff := in.PressureAltitude - in.FieldElevation; math.Abs(diff) > MaxPlausibleAltitudeError {
+			add(CodeImplausibleAltitude, "pressure altitude %s is %s from the %s field elevation, an altimeter setting of about %s; was the elevation or density altitude entered?",
+				u.feet(in.PressureAltitude), u.feet(math.Abs(diff)), u.feet(in.FieldElevation), u.inchesHg(atmosphere.StandardAltimeter-diff/1000))
+		}
+	}
+	return advisories
+}
@@ -0,0 +1,246 @@
+package aircraft
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// A point read off a chart scan by hand is trusted to the larger of
+// readingTolerance and readingFraction of its distance, about the width
+// of a line on a typical POH figure
+const (
+	readingTolerance = 25
+	readingFraction  = 0.02
+)
+
+// ReadReferenceCSV reads takeoff reference points read off a POH chart
+// scan, one a row, with the columns pressure_altitude, temperature_c or
+// temperature_f, weight and distance, and optionally name, wind (knots,
+// positive for headwind), liftoff_speed, barrier_speed and tolerance.
+// Points without a name are named by their line.
+func ReadReferenceCSV(r io.Reader) ([]GoldenCase, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("empty reference point list")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"pressure_altitude", "weight", "distance"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("reference point list is missing the %s column", name)
+		}
+	}
+	_, celsius := columns["temperature_c"]
+	_, fahrenheit := columns["temperature_f"]
+	if !celsius && !fahrenheit {
+		return nil, errors.New("reference point list is missing the temperature_c or temperature_f column")
+	}
+
+	var cases []GoldenCase
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		number := func(name string, required bool) float64 {
+			s := field(name)
+			if err != nil || (s == "" && !required) {
+				return 0
+			}
+			v, parseErr := strconv.ParseFloat(s, 64)
+			if parseErr != nil {
+				err = fmt.Errorf("line %d: invalid %s %q", line, name, s)
+			}
+			return v
+		}
+
+		gc := GoldenCase{
+			Name: field("name"),
+			Params: performance.TakeoffParams{
+				PressureAltitude: number("pressure_altitude", true),
+				Weight:           number("weight", true),
+				WindComponent:    number("wind", false),
+			},
+			TakeoffDistance: number("distance", true),
+			LiftoffSpeed:    number("liftoff_speed", false),
+			BarrierSpeed:    number("barrier_speed", false),
+			Tolerance:       number("tolerance", false),
+		}
+		if field("temperature_f") != "" {
+			gc.Params.Temperature = performance.ConvertFahrenheitToCelsius(number("temperature_f", true))
+		} else {
+			gc.Params.Temperature = number("temperature_c", true)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if gc.Name == "" {
+			gc.Name = fmt.Sprintf("Line %d", line)
+		}
+		cases = append(cases, gc)
+	}
+	return cases, nil
+}
+
+// DeriveTolerances sets the tolerance of reference cases that have none:
+// the reading tolerance of a hand-read point plus the interpolation
+// tolerance of the profile's chart there, rounded up to 10 ft. Points
+// outside the chart are reported together.
+func (p *Profile) DeriveTolerances(cases []GoldenCase) ([]GoldenCase, error) {
+	calculator := p.NewTakeoffCalculator()
+
+	var problems []string
+	derived := make([]GoldenCase, len(cases))
+	for i, gc := range cases {
+		derived[i] = gc
+		if gc.Tolerance > 0 {
+			continue
+		}
+		result, err := calculator.CalculateTakeoff(gc.Params)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", gc.Name, err))
+			continue
+		}
+		reading := math.Max(readingTolerance, readingFraction*gc.TakeoffDistance)
+		derived[i].Tolerance = math.Ceil((reading+result.Tolerance)/10) * 10
+	}
+	if len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "\n"))
+	}
+	return derived, nil
+}
+
+// chartTest is the source of a generated test of reference cases
+var chartTest = template.Must(template.New("test").Parse(`// Code generated by otto aircraft gentest from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"math"
+	"testing"
+{{if .Qualifier}}
+	"github.com/ryanbmilbourne/otto-perf/aircraft"{{end}}
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// {{.Func}} checks the {{.ID}} takeoff chart against
+// points read off the POH figure, each within its reading and
+// interpolation tolerance
+func {{.Func}}(t *testing.T) {
+	profile, err := {{.Qualifier}}Lookup({{printf "%q" .ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calculator := profile.NewTakeoffCalculator()
+
+	testCases := []struct {
+		name      string
+		params    performance.TakeoffParams
+		distance  float64 // in feet
+		tolerance float64 // in feet
+		liftoff   float64 // in KIAS, 0 when not read
+		barrier   float64 // in KIAS, 0 when not read
+	}{
+{{- range .Cases}}
+		{
+			name: {{printf "%q" .Name}},
+			params: performance.TakeoffParams{
+				PressureAltitude: {{.Params.PressureAltitude}},
+				Temperature:      {{.Params.Temperature}},
+				Weight:           {{.Params.Weight}},
+				WindComponent:    {{.Params.WindComponent}},
+			},
+			distance:  {{.TakeoffDistance}},
+			tolerance: {{.Tolerance}},
+			liftoff:   {{.LiftoffSpeed}},
+			barrier:   {{.BarrierSpeed}},
+		},
+{{- end}}
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := calculator.CalculateTakeoff(tc.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(result.TakeoffDistance-tc.distance) > tc.tolerance {
+				t.Errorf("Takeoff distance %.0f ft, read %.0f ft (±%.0f)", result.TakeoffDistance, tc.distance, tc.tolerance)
+			}
+			if tc.liftoff > 0 && math.Abs(result.LiftoffSpeed-tc.liftoff) > {{.SpeedTolerance}} {
+				t.Errorf("Lift-off speed %.1f KIAS, read %.0f KIAS", result.LiftoffSpeed, tc.liftoff)
+			}
+			if tc.barrier > 0 && math.Abs(result.BarrierSpeed-tc.barrier) > {{.SpeedTolerance}} {
+				t.Errorf("50 ft speed %.1f KIAS, read %.0f KIAS", result.BarrierSpeed, tc.barrier)
+			}
+		})
+	}
+}
+`))
+
+// WriteChartTest writes a table-driven Go test of the profile's takeoff
+// chart against reference cases, in package pkg, with source naming the
+// file the cases were read from. Outside package aircraft the profile is
+// looked up through it.
+func (p *Profile) WriteChartTest(w io.Writer, cases []GoldenCase, pkg, source string) error {
+	data := struct {
+		Package, Qualifier, Func, ID, Source string
+		SpeedTolerance                       float64
+		Cases                                []GoldenCase
+	}{Package: pkg, Func: "Test" + identifier(p.ID) + "ChartReadings", ID: p.ID, Source: source, SpeedTolerance: speedTolerance, Cases: cases}
+	if pkg != "aircraft" {
+		data.Qualifier = "aircraft."
+	}
+
+	var buf bytes.Buffer
+	if err := chartTest.Execute(&buf, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// identifier turns a profile ID such as "pa28-161" into "PA28161" for
+// a test name
+func identifier(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package aircraft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// UnitSystem is the unit system the quantities in advisories and the
+// takeoff checklist are written in
+type UnitSystem string
+
+// Unit systems of advisories and checklists
+const (
+	POHUnits  UnitSystem = ""     // As the POH gives them: feet, pounds, knots, statute miles and °C
+	DualUnits UnitSystem = "dual" // The POH's, each with the metric in parentheses
+)
+
+// WithUnits returns a copy of the profile whose advisories and checklist
+// are written in another unit system
+func (p *Profile) WithUnits(u UnitSystem) *Profile {
+	configured := *p
+	configured.Units = u
+	return &configured
+}
+
+// feet formats a height or distance in feet: "1500 ft (457 m)" in dual units
+func (u UnitSystem) feet(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%.0f ft (%.0f m)", v, units.FeetToMeters(v))
+	}
+	return fmt.Sprintf("%.0f ft", v)
+}
+
+// pounds formats a weight in pounds: "2200 lbs (998 kg)" in dual units
+func (u UnitSystem) pounds(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%.0f lbs (%.0f kg)", v, units.PoundsToKilograms(v))
+	}
+	return fmt.Sprintf("%.0f lbs", v)
+}
+
+// knots formats a wind speed in knots: "18 kt (33 km/h)" in dual units
+func (u UnitSystem) knots(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%.0f kt (%.0f km/h)", v, units.NauticalToKilometers(v))
+	}
+	return fmt.Sprintf("%.0f kt", v)
+}
+
+// celsius formats a temperature in °C: "-2°C (28°F)" in dual units
+func (u UnitSystem) celsius(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%.0f°C (%.0f°F)", v, units.CelsiusToFahrenheit(v))
+	}
+	return fmt.Sprintf("%.0f°C", v)
+}
+
+// celsiusSpread formats a temperature difference in °C, such as a dew
+// point spread: "20°C (36°F)" in dual units
+func (u UnitSystem) celsiusSpread(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%.0f°C (%.0f°F)", v, v*9/5)
+	}
+	return fmt.Sprintf("%.0f°C", v)
+}
+
+// statuteMiles formats a visibility in statute miles: "1 SM (1.6 km)" in
+// dual units
+func (u UnitSystem) statuteMiles(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%g SM (%.1f km)", v, units.NauticalToKilometers(units.StatuteToNautical(v)))
+	}
+	return fmt.Sprintf("%g SM", v)
+}
+
+// inchesHg formats an altimeter setting in inHg: "29.92 inHg (1013 hPa)"
+// in dual units
+func (u UnitSystem) inchesHg(v float64) string {
+	if u == DualUnits {
+		return fmt.Sprintf("%.2f inHg (%.0f hPa)", v, units.InchesHgToHectopascals(v))
+	}
+	return fmt.Sprintf("%.2f inHg", v)
+}
+
+// airspeed formats indicated airspeeds on the ASI scale, as ASIUnits.Format
+// does, with km/h beside them in dual units: "50 KIAS (93 km/h)"
+func (u UnitSystem) airspeed(asi ASIUnits, kias ...float64) string {
+	s := asi.Format(kias...)
+	if u != DualUnits {
+		return s
+	}
+	kmh := make([]string, len(kias))
+	for i, v := range kias {
+		kmh[i] = fmt.Sprintf("%.0f", units.NauticalToKilometers(v))
+	}
+	return s + " (" + strings.Join(kmh, " / ") + " km/h)"
+}
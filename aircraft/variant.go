@@ -0,0 +1,84 @@
+package aircraft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// Variant is an alternate engine or STC conversion of an airframe, such as
+// a 150 hp or 180 hp conversion. It shares the profile's weight and
+// balance, speeds, equipment and airspeed calibration, and swaps the
+// performance charts and engine data the conversion changes.
+type Variant struct {
+	ID     string // Short identifier used to select it, e.g. "180hp"
+	Name   string // Description, e.g. "Lycoming O-360-A4M, 180 hp"
+	Source string // Type certificate or STC supplement the charts come from
+
+	// These replace the profile's calculators; nil keeps the profile's
+	// chart, as when the supplement directs the basic POH chart be used
+	NewTakeoffCalculator func() *performance.TakeoffCalculator
+	NewClimbCalculator   func() *performance.ClimbCalculator
+	NewCruiseCalculator  func() *performance.CruiseCalculator
+	NewLandingCalculator func() *performance.LandingCalculator
+
+	// Engine replaces the profile's engine data when set
+	Engine *Engine
+
+	// Golden holds reference points read from the supplement's takeoff
+	// chart; they replace the profile's when the takeoff chart is swapped
+	Golden []GoldenCase
+}
+
+// WithVariant returns a copy of the profile with a variant's charts and
+// engine data in place of its own, named after both and with the variant
+// in Variant. An empty ID returns the profile unchanged. Apply the
+// variant before any equipment, whose adjustments apply to the variant's
+// charts.
+func (p *Profile) WithVariant(id string) (*Profile, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return p, nil
+	}
+	v, err := p.variant(id)
+	if err != nil {
+		return nil, err
+	}
+
+	configured := *p
+	configured.Name = fmt.Sprintf("%s (%s)", p.Name, v.Name)
+	configured.Variant = &v
+	if v.NewTakeoffCalculator != nil {
+		configured.NewTakeoffCalculator = v.NewTakeoffCalculator
+		configured.Golden = v.Golden
+	}
+	if v.NewClimbCalculator != nil {
+		configured.NewClimbCalculator = v.NewClimbCalculator
+	}
+	if v.NewCruiseCalculator != nil {
+		configured.NewCruiseCalculator = v.NewCruiseCalculator
+	}
+	if v.NewLandingCalculator != nil {
+		configured.NewLandingCalculator = v.NewLandingCalculator
+	}
+	if v.Engine != nil {
+		configured.Engine = *v.Engine
+	}
+	return &configured, nil
+}
+
+// variant finds a variant by ID, ignoring case
+func (p *Profile) variant(id string) (Variant, error) {
+	var known []string
+	for _, v := range p.Variants {
+		if strings.EqualFold(v.ID, id) {
+			return v, nil
+		}
+		known = append(known, v.ID)
+	}
+	if len(known) == 0 {
+		return Variant{}, fmt.Errorf("%s has no engine or STC variants defined", p.Name)
+	}
+	return Variant{}, fmt.Errorf("unknown variant %q for %s (available: %s)", id, p.Name, strings.Join(known, ", "))
+}
@@ -0,0 +1,135 @@
+package aircraft
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// Codes of the weather-risk advisories
+const (
+	CodeThunderstorm = "thunderstorm" // Thunderstorm at the field
+	CodeConvective   = "convective"   // Thunderstorm in the vicinity or convective cloud
+	CodeGustSpread   = "gust-spread"  // Gusts well above the steady wind
+	CodeDryAir       = "dry-air"      // Large temperature/dew point spread
+	CodeIcing        = "icing"        // Freezing temperature with visible moisture, or freezing precipitation
+)
+
+// Weather-risk thresholds
+const (
+	GustSpreadCaution = 10.0 // Gust spread in knots from which the wind is called gusty
+	DryAirSpread      = 17.0 // Temperature/dew point spread in °C (about 30°F) from which the air is called dry
+	IcingTemperature  = 0.0  // Temperature in °C at or below which visible moisture can freeze on the airframe
+)
+
+// precipitation lists the present weather codes of precipitation
+var precipitation = []string{"DZ", "RA", "SN", "SG", "IC", "PL", "GR", "GS", "UP"}
+
+// WeatherAdvisories returns the risks in a METAR that the performance
+// charts ignore, most severe first. The charts assume a steady wind,
+// smooth air and a clean wing, so the numbers are framed by the
+// conditions they leave out.
+func WeatherAdvisories(o *weather.Observation) []Advisory {
+	return WeatherAdvisoriesIn(o, POHUnits)
+}
+
+// WeatherAdvisoriesIn returns the weather risks as WeatherAdvisories does,
+// written in a unit system
+func WeatherAdvisoriesIn(o *weather.Observation, u UnitSystem) []Advisory {
+	var advisories []Advisory
+	add := func(severity Severity, code, message string) {
+		advisories = append(advisories, Advisory{Severity: severity, Message: message, Code: code})
+	}
+
+	vicinity := false
+	for _, w := range o.Weather {
+		switch {
+		case strings.HasPrefix(w, "VC") && strings.Contains(w, "TS"):
+			vicinity = true
+		case strings.Contains(w, "TS"):
+			add(Warning, CodeThunderstorm, fmt.Sprintf(
+				"Thunderstorm at the field (%s): expect wind shear, gust fronts and downdrafts the chart does not cover", w))
+		}
+	}
+	switch {
+	case vicinity:
+		add(Caution, CodeConvective,
+			"Thunderstorm in the vicinity: a gust front or downdraft can reverse the wind on the runway without warning")
+	case o.Convective:
+		add(Caution, CodeConvective,
+			"Convective cloud (CB or TCU) reported: expect turbulence and sudden wind changes on the climb-out")
+	}
+
+	if spread := o.Wind.Gust - o.Wind.Speed; o.Wind.Gust > 0 && spread >= GustSpreadCaution {
+		add(Caution, CodeGustSpread, fmt.Sprintf(
+			"Gusts to %s, %s over the steady wind: the chart assumes a steady wind; expect airspeed swings near the ground",
+			u.knots(o.Wind.Gust), u.knots(spread)))
+	}
+
+	if spread := o.Temperature - o.Dewpoint; o.HasTemperature && spread >= DryAirSpread {
+		severity := Info
+		if o.Convective || o.HasWeather("SH") || vicinity {
+			severity = Caution
+		}
+		add(severity, CodeDryAir, fmt.Sprintf(
+			"Temperature/dew point spread %s: dry air; expect thermal turbulence, and dry microbursts from high-based showers or virga",
+			u.celsiusSpread(spread)))
+	}
+
+	if icing := icingAdvisory(o, u); icing != nil {
+		advisories = append(advisories, *icing)
+	}
+
+	sort.SliceStable(advisories, func(i, j int) bool {
+		return advisories[i].Severity > advisories[j].Severity
+	})
+	return advisories
+}
+
+// icingAdvisory returns the icing warning for freezing precipitation, or
+// for a temperature at or below freezing with visible moisture: fog or
+// mist, precipitation, visibility of a mile or less, or a ceiling to climb
+// into. The performance data is only valid for clean wings, so the numbers
+// are not to be used until a contamination check is done.
+func icingAdvisory(o *weather.Observation, u UnitSystem) *Advisory {
+	for _, w := range o.Weather {
+		if (strings.Contains(w, "FZ") && hasAny(w, precipitation)) || strings.Contains(w, "PL") {
+			return &Advisory{Severity: Warning, Code: CodeIcing, Message: fmt.Sprintf(
+				"Freezing precipitation (%s): do not take off with ice on the airframe; performance data is invalid with contaminated wings", w)}
+		}
+	}
+	if !o.HasTemperature || o.Temperature > IcingTemperature {
+		return nil
+	}
+
+	var moisture []string
+	for _, w := range o.Weather {
+		if !strings.HasPrefix(w, "VC") && (strings.Contains(w, "FG") || strings.Contains(w, "BR") || hasAny(w, precipitation)) {
+			moisture = append(moisture, w)
+		}
+	}
+	if o.HasVisibility && o.Visibility <= 1 {
+		moisture = append(moisture, "visibility "+u.statuteMiles(o.Visibility))
+	}
+	if o.HasCeiling {
+		moisture = append(moisture, "ceiling "+u.feet(o.Ceiling))
+	}
+	if len(moisture) == 0 {
+		return nil
+	}
+	return &Advisory{Severity: Warning, Code: CodeIcing, Message: fmt.Sprintf(
+		"%s with visible moisture (%s): frost and contamination check required; performance data is invalid with contaminated wings",
+		u.celsius(o.Temperature), strings.Join(moisture, ", "))}
+}
+
+// hasAny reports whether s contains any of the codes
+func hasAny(s string, codes []string) bool {
+	for _, code := range codes {
+		if strings.Contains(s, code) {
+			return true
+		}
+	}
+	return false
+}
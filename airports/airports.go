@@ -0,0 +1,109 @@
+// Package airports provides airport and runway data behind a pluggable
+// Provider interface, with an embedded sample dataset and a reader for the
+// FAA NASR CSV subscription files
+package airports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/geo"
+)
+
+// ErrNotFound is returned when no airport matches an identifier
+var ErrNotFound = errors.New("airport not found")
+
+// RunwayEnd describes one end of a runway
+type RunwayEnd struct {
+	ID          string  `json:"id"`           // Runway end designator, e.g. "17" or "1L"
+	TrueHeading float64 `json:"true_heading"` // True alignment in degrees
+}
+
+// Runway describes a single runway at an airport
+type Runway struct {
+	ID      string      `json:"id"`      // e.g. "17/35"
+	Length  float64     `json:"length"`  // in feet
+	Width   float64     `json:"width"`   // in feet
+	Surface string      `json:"surface"` // e.g. "ASPH", "CONC", "TURF"
+	Ends    []RunwayEnd `json:"ends"`
+}
+
+// Airport describes an airport and its runways
+type Airport struct {
+	Ident             string   `json:"ident"`          // FAA location identifier or GPS ident, e.g. "JYO"
+	ICAO              string   `json:"icao,omitempty"` // e.g. "KJYO"
+	IATA              string   `json:"iata,omitempty"`
+	Name              string   `json:"name"`
+	City              string   `json:"city,omitempty"`
+	State             string   `json:"state,omitempty"`
+	Country           string   `json:"country,omitempty"`
+	Latitude          float64  `json:"latitude"`            // in decimal degrees, north positive
+	Longitude         float64  `json:"longitude"`           // in decimal degrees, east positive
+	Elevation         float64  `json:"elevation"`           // field elevation in feet
+	MagneticVariation float64  `json:"magnetic_variation"`  // in degrees, east positive
+	TimeZone          string   `json:"time_zone,omitempty"` // IANA time zone, e.g. "America/New_York"
+	Runways           []Runway `json:"runways,omitempty"`
+}
+
+// Position returns the airport's reference point
+func (a *Airport) Position() geo.Point {
+	return geo.Point{Latitude: a.Latitude, Longitude: a.Longitude}
+}
+
+// Runway finds a runway by its full ID ("17/35") or either end ("17")
+func (a *Airport) Runway(id string) (*Runway, *RunwayEnd, error) {
+	id = strings.ToUpper(strings.TrimSpace(id))
+	for i := range a.Runways {
+		rwy := &a.Runways[i]
+		if rwy.ID == id {
+			return rwy, nil, nil
+		}
+		for j := range rwy.Ends {
+			if rwy.Ends[j].ID == id {
+				return rwy, &rwy.Ends[j], nil
+			}
+		}
+	}
+	return nil, nil, fmt.Errorf("runway %s not found at %s", id, a.Ident)
+}
+
+// Provider supplies airport data. Implementations may be backed by embedded
+// files, local data subscriptions or remote services.
+type Provider interface {
+	// Lookup finds an airport by FAA/GPS identifier or ICAO code
+	Lookup(ctx context.Context, ident string) (*Airport, error)
+	// All returns every airport the provider knows about
+	All(ctx context.Context) ([]*Airport, error)
+}
+
+// index is an in-memory set of airports shared by the file-backed providers
+type index struct {
+	airports []*Airport
+	byIdent  map[string]*Airport
+}
+
+// newIndex builds an index over a list of airports, sorted by identifier
+func newIndex(list []*Airport) *index {
+	sort.Slice(list, func(i, j int) bool { return list[i].Ident < list[j].Ident })
+
+	idx := &index{airports: list, byIdent: make(map[string]*Airport)}
+	for _, a := range list {
+		idx.byIdent[a.Ident] = a
+		if a.ICAO != "" {
+			idx.byIdent[a.ICAO] = a
+		}
+	}
+	return idx
+}
+
+// lookup finds an airport by identifier or ICAO code
+func (idx *index) lookup(ident string) (*Airport, error) {
+	key := strings.ToUpper(strings.TrimSpace(ident))
+	if a, ok := idx.byIdent[key]; ok {
+		return a, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+}
@@ -0,0 +1,149 @@
+package airports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedProvider(t *testing.T) {
+	provider, err := Embedded()
+	if err != nil {
+		t.Fatalf("Error loading embedded airports: %v", err)
+	}
+
+	testCases := []struct {
+		ident     string
+		wantIdent string
+		runways   int
+	}{
+		{"JYO", "JYO", 1},
+		{"kjyo", "JYO", 1},
+		{"KIAD", "IAD", 4},
+		{"W00", "W00", 1},
+	}
+
+	for _, tc := range testCases {
+		a, err := provider.Lookup(context.Background(), tc.ident)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.ident, err)
+			continue
+		}
+		if a.Ident != tc.wantIdent || len(a.Runways) != tc.runways {
+			t.Errorf("%s: got %s with %d runways, expected %s with %d", tc.ident, a.Ident, len(a.Runways), tc.wantIdent, tc.runways)
+		}
+	}
+
+	if _, err := provider.Lookup(context.Background(), "XXXX"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	all, err := provider.All(context.Background())
+	if err != nil || len(all) == 0 {
+		t.Fatalf("Expected embedded airports, got %d (%v)", len(all), err)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Ident >= all[i].Ident {
+			t.Errorf("Airports not sorted by identifier: %s before %s", all[i-1].Ident, all[i].Ident)
+		}
+	}
+}
+
+func TestAirportRunway(t *testing.T) {
+	provider, err := NewCSVProvider(
+		strings.NewReader("ident,icao,iata,name,city,state,country,latitude,longitude,elevation,magnetic_variation\nTST,KTST,,Test Field,,,US,40,-100,1000,-5\n"),
+		strings.NewReader("airport,runway,length,width,surface,end1_id,end1_true_heading,end2_id,end2_true_heading\nTST,9/27,3000,60,TURF,9,85,27,265\n"),
+	)
+	if err != nil {
+		t.Fatalf("Error reading CSV: %v", err)
+	}
+
+	a, err := provider.Lookup(context.Background(), "KTST")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+
+	rwy, end, err := a.Runway("27")
+	if err != nil {
+		t.Fatalf("Runway lookup failed: %v", err)
+	}
+	if rwy.ID != "9/27" || end.TrueHeading != 265 || rwy.Surface != "TURF" {
+		t.Errorf("Unexpected runway %+v end %+v", *rwy, *end)
+	}
+
+	rwy, end, err = a.Runway("9/27")
+	if err != nil || end != nil || rwy.Length != 3000 {
+		t.Errorf("Full runway ID lookup: got %+v, %+v, %v", rwy, end, err)
+	}
+
+	if _, _, err := a.Runway("18"); err == nil {
+		t.Error("Expected error for unknown runway, but got none")
+	}
+}
+
+func TestCSVProviderErrors(t *testing.T) {
+	header := "ident,icao,iata,name,city,state,country,latitude,longitude,elevation,magnetic_variation\n"
+	rwyHeader := "airport,runway,length,width,surface,end1_id,end1_true_heading,end2_id,end2_true_heading\n"
+
+	if _, err := NewCSVProvider(strings.NewReader(header+"TST,,,Test,,,,north,0,0,0\n"), strings.NewReader(rwyHeader)); err == nil {
+		t.Error("Expected error for invalid latitude, but got none")
+	}
+	if _, err := NewCSVProvider(strings.NewReader(header), strings.NewReader(rwyHeader+"TST,9/27,3000,60,ASPH,9,90,27,270\n")); err == nil {
+		t.Error("Expected error for runway at unknown airport, but got none")
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadDir(dir); err == nil {
+		t.Error("Expected error for a directory without the data files, but got none")
+	}
+	if err := SetDataDir(dir); err == nil || dataDir != "" {
+		t.Errorf("Expected SetDataDir to refuse a directory without the data files, got %v", err)
+	}
+
+	files := map[string]string{
+		"airports.csv": "ident,icao,iata,name,city,state,country,latitude,longitude,elevation,magnetic_variation\nTST,KTST,,Test Field,,,US,40,-100,1000,-5\n",
+		"runways.csv":  "airport,runway,length,width,surface,end1_id,end1_true_heading,end2_id,end2_true_heading\nTST,9/27,3000,60,TURF,9,85,27,265\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	provider, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("Error loading %s: %v", dir, err)
+	}
+	a, err := provider.Lookup(context.Background(), "KTST")
+	if err != nil || len(a.Runways) != 1 || a.Runways[0].Length != 3000 {
+		t.Errorf("Unexpected airport %+v (%v)", a, err)
+	}
+}
+
+func TestAirportLocation(t *testing.T) {
+	provider, err := Embedded()
+	if err != nil {
+		t.Fatalf("Error loading embedded airports: %v", err)
+	}
+	a, err := provider.Lookup(context.Background(), "KAPA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, err := a.Location()
+	if err != nil || loc.String() != "America/Denver" {
+		t.Errorf("KAPA: got %v (%v), expected America/Denver", loc, err)
+	}
+
+	// NASR records carry no zone, so the state's zone is used
+	nasr := &Airport{Ident: "TST", State: "tx"}
+	if loc, err := nasr.Location(); err != nil || loc.String() != "America/Chicago" {
+		t.Errorf("Texas fallback: got %v (%v), expected America/Chicago", loc, err)
+	}
+	if _, err := (&Airport{Ident: "XYZ", Country: "CA"}).Location(); err == nil {
+		t.Error("Expected an error with no time zone or state")
+	}
+}
@@ -0,0 +1,171 @@
+package airports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/trace"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// DefaultBaseURL is the aviationweather.gov data API, whose airport
+// endpoint covers airports worldwide
+const DefaultBaseURL = "https://aviationweather.gov/api/data"
+
+// Client looks up single airports from the aviationweather.gov data API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// apiAirport is an airport as the data API returns it
+type apiAirport struct {
+	ICAO      string  `json:"icaoId"`
+	IATA      string  `json:"iataId"`
+	FAA       string  `json:"faaId"`
+	Name      string  `json:"name"`
+	State     string  `json:"state"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Elevation float64 `json:"elev"`   // in meters
+	MagDec    string  `json:"magdec"` // e.g. "10W"
+	Runways   []struct {
+		ID        string `json:"id"`        // e.g. "17/35"
+		Dimension string `json:"dimension"` // length x width in feet, e.g. "5500x100"
+		Surface   string `json:"surface"`   // A, C, G, ...
+		Alignment string `json:"alignment"` // true heading of the first end, e.g. "167"
+	} `json:"runways"`
+}
+
+// apiSurfaces maps the data API's surface codes to the NASR ones
+var apiSurfaces = map[string]string{"A": "ASPH", "C": "CONC", "G": "TURF", "W": "WATER"}
+
+// Lookup fetches one airport by identifier
+func (c *Client) Lookup(ctx context.Context, ident string) (a *Airport, err error) {
+	ident = NormalizeIdent(ident)
+	ctx, span := trace.Start(ctx, "airports.fetch")
+	span.SetAttribute("airports.ident", ident)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	query := url.Values{}
+	query.Set("ids", ident)
+	query.Set("format", "json")
+	u := strings.TrimRight(c.BaseURL, "/") + "/airport?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("airport request: %s", resp.Status)
+	}
+
+	var list []apiAirport
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("airport %s: %v", ident, err)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ident)
+	}
+	return list[0].airport()
+}
+
+// airport converts the API's record
+func (r apiAirport) airport() (*Airport, error) {
+	a := &Airport{
+		Ident:     r.FAA,
+		ICAO:      r.ICAO,
+		IATA:      r.IATA,
+		Name:      r.Name,
+		State:     r.State,
+		Country:   r.Country,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Elevation: units.MetersToFeet(r.Elevation),
+	}
+	if a.Ident == "" {
+		a.Ident = a.ICAO
+	}
+	if n := len(r.MagDec); n > 1 {
+		variation, err := strconv.ParseFloat(r.MagDec[:n-1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("airport %s: magnetic variation %q", a.Ident, r.MagDec)
+		}
+		if r.MagDec[n-1] == 'W' {
+			variation = -variation
+		}
+		a.MagneticVariation = variation
+	}
+
+	for _, rw := range r.Runways {
+		rwy := Runway{ID: rw.ID, Surface: rw.Surface}
+		if s, ok := apiSurfaces[rw.Surface]; ok {
+			rwy.Surface = s
+		}
+		if length, width, ok := strings.Cut(rw.Dimension, "x"); ok {
+			rwy.Length, _ = strconv.ParseFloat(length, 64)
+			rwy.Width, _ = strconv.ParseFloat(width, 64)
+		}
+		heading, err := strconv.ParseFloat(rw.Alignment, 64)
+		if err != nil {
+			// Helipads and runways without an alignment have no usable ends
+			continue
+		}
+		for i, id := range strings.Split(rw.ID, "/") {
+			end := RunwayEnd{ID: id, TrueHeading: heading}
+			if i == 1 {
+				end.TrueHeading = math.Mod(heading+180, 360)
+			}
+			rwy.Ends = append(rwy.Ends, end)
+		}
+		a.Runways = append(a.Runways, rwy)
+	}
+	return a, nil
+}
+
+// Online serves the airports of a local provider and looks up the ones it
+// lacks with a Client, so an airport outside the NASR files or the
+// embedded sample can still be resolved
+type Online struct {
+	Local  Provider
+	Client *Client
+}
+
+// Lookup implements Provider, asking the Client only for airports Local
+// does not have
+func (o *Online) Lookup(ctx context.Context, ident string) (*Airport, error) {
+	a, err := o.Local.Lookup(ctx, ident)
+	if !errors.Is(err, ErrNotFound) {
+		return a, err
+	}
+	return o.Client.Lookup(ctx, ident)
+}
+
+// All implements Provider with the local airports only
+func (o *Online) All(ctx context.Context) ([]*Airport, error) {
+	return o.Local.All(ctx)
+}
@@ -0,0 +1,51 @@
+package airports
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOnline(t *testing.T) {
+	var requested []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("ids"))
+		if r.URL.Path != "/airport" || r.URL.Query().Get("ids") != "EDDF" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"icaoId": "EDDF", "iataId": "FRA", "faaId": "", "name": "Frankfurt/Main", "country": "DE",
+			"lat": 50.033, "lon": 8.571, "elev": 111, "magdec": "03E",
+			"runways": [{"id": "07C/25C", "dimension": "13123x197", "surface": "C", "alignment": "069"}, {"id": "H1", "dimension": "60x60", "surface": "C", "alignment": "-"}]}]`))
+	}))
+	defer api.Close()
+
+	local, err := Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &Online{Local: local, Client: &Client{BaseURL: api.URL}}
+
+	if _, err := Resolve(context.Background(), provider, "KJYO"); err != nil || len(requested) != 0 {
+		t.Errorf("Expected a local airport without a request, got %v after %v", err, requested)
+	}
+
+	a, err := Resolve(context.Background(), provider, "eddf")
+	if err != nil {
+		t.Fatalf("Error resolving a remote airport: %v", err)
+	}
+	if a.Ident != "EDDF" || a.IATA != "FRA" || math.Abs(a.Elevation-364) > 1 || a.MagneticVariation != 3 || len(a.Runways) != 1 {
+		t.Fatalf("Unexpected airport %+v", a)
+	}
+	rwy, end, err := a.Runway("25C")
+	if err != nil || rwy.Length != 13123 || rwy.Surface != "CONC" || end.TrueHeading != 249 {
+		t.Errorf("Unexpected runway %+v, end %+v (%v)", rwy, end, err)
+	}
+
+	if _, err := Resolve(context.Background(), provider, "ZZZZ"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown airport, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package airports
+
+import (
+	"context"
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/airports.csv data/runways.csv
+var embeddedData embed.FS
+
+// CSVProvider serves airports from a pair of CSV files in the format of
+// the embedded dataset (see data/airports.csv and data/runways.csv)
+type CSVProvider struct {
+	idx *index
+}
+
+var (
+	embeddedOnce     sync.Once
+	embeddedProvider *CSVProvider
+	embeddedErr      error
+	dataDir          string
+)
+
+// Embedded returns a provider over the small sample dataset compiled into
+// the binary, so the tools run offline. It covers a handful of fields used
+// in examples and tests. After SetDataDir it reads that directory instead.
+func Embedded() (*CSVProvider, error) {
+	embeddedOnce.Do(func() {
+		if dataDir != "" {
+			embeddedProvider, embeddedErr = LoadDir(dataDir)
+			return
+		}
+		embeddedProvider, embeddedErr = loadFS(embeddedData, "data")
+	})
+	return embeddedProvider, embeddedErr
+}
+
+// SetDataDir has Embedded read airports.csv and runways.csv from a
+// directory, in the format of the compiled-in files, instead of the
+// compiled-in copies: a larger dataset for the local area, say. Call it
+// before the first use of Embedded.
+func SetDataDir(dir string) error {
+	for _, name := range []string{"airports.csv", "runways.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("data directory: %w", err)
+		}
+	}
+	dataDir = dir
+	return nil
+}
+
+// LoadDir reads a provider from airports.csv and runways.csv in a directory
+func LoadDir(dir string) (*CSVProvider, error) {
+	provider, err := loadFS(os.DirFS(dir), ".")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	return provider, nil
+}
+
+// loadFS reads a provider from airports.csv and runways.csv in a directory
+// of a file system
+func loadFS(fsys fs.FS, dir string) (*CSVProvider, error) {
+	airportsFile, err := fsys.Open(path.Join(dir, "airports.csv"))
+	if err != nil {
+		return nil, err
+	}
+	defer airportsFile.Close()
+
+	runwaysFile, err := fsys.Open(path.Join(dir, "runways.csv"))
+	if err != nil {
+		return nil, err
+	}
+	defer runwaysFile.Close()
+
+	return NewCSVProvider(airportsFile, runwaysFile)
+}
+
+// NewCSVProvider reads airports and runways from CSV. The airports file has
+// the columns ident, icao, iata, name, city, state, country, latitude,
+// longitude, elevation, magnetic_variation and an optional time_zone; the
+// runways file has airport, runway, length, width, surface, and one or two
+// end id/true heading pairs.
+func NewCSVProvider(airportsCSV, runwaysCSV io.Reader) (*CSVProvider, error) {
+	rows, err := readCSV(airportsCSV)
+	if err != nil {
+		return nil, fmt.Errorf("reading airports: %w", err)
+	}
+
+	byIdent := make(map[string]*Airport)
+	var list []*Airport
+	for _, row := range rows {
+		a := &Airport{
+			Ident:    strings.ToUpper(row.get("ident")),
+			ICAO:     strings.ToUpper(row.get("icao")),
+			IATA:     strings.ToUpper(row.get("iata")),
+			Name:     row.get("name"),
+			City:     row.get("city"),
+			State:    row.get("state"),
+			Country:  row.get("country"),
+			TimeZone: row.get("time_zone"),
+		}
+		if a.Latitude, err = row.float("latitude"); err != nil {
+			return nil, err
+		}
+		if a.Longitude, err = row.float("longitude"); err != nil {
+			return nil, err
+		}
+		if a.Elevation, err = row.float("elevation"); err != nil {
+			return nil, err
+		}
+		if a.MagneticVariation, err = row.float("magnetic_variation"); err != nil {
+			return nil, err
+		}
+		byIdent[a.Ident] = a
+		list = append(list, a)
+	}
+
+	rows, err = readCSV(runwaysCSV)
+	if err != nil {
+		return nil, fmt.Errorf("reading runways: %w", err)
+	}
+	for _, row := range rows {
+		a, ok := byIdent[strings.ToUpper(row.get("airport"))]
+		if !ok {
+			return nil, fmt.Errorf("line %d: runway for unknown airport %q", row.line, row.get("airport"))
+		}
+
+		rwy := Runway{
+			ID:      strings.ToUpper(row.get("runway")),
+			Surface: row.get("surface"),
+		}
+		if rwy.Length, err = row.float("length"); err != nil {
+			return nil, err
+		}
+		if rwy.Width, err = row.float("width"); err != nil {
+			return nil, err
+		}
+		for _, end := range []string{"end1", "end2"} {
+			id := strings.ToUpper(row.get(end + "_id"))
+			if id == "" {
+				continue
+			}
+			heading, err := row.float(end + "_true_heading")
+			if err != nil {
+				return nil, err
+			}
+			rwy.Ends = append(rwy.Ends, RunwayEnd{ID: id, TrueHeading: heading})
+		}
+		a.Runways = append(a.Runways, rwy)
+	}
+
+	return &CSVProvider{idx: newIndex(list)}, nil
+}
+
+// Lookup implements Provider
+func (p *CSVProvider) Lookup(ctx context.Context, ident string) (*Airport, error) {
+	return p.idx.lookup(ident)
+}
+
+// All implements Provider
+func (p *CSVProvider) All(ctx context.Context) ([]*Airport, error) {
+	return p.idx.airports, nil
+}
+
+// csvRow is a CSV record with access to fields by header name
+type csvRow struct {
+	line   int
+	header map[string]int
+	fields []string
+}
+
+// get returns a field by column name, or "" if the column is absent
+func (r csvRow) get(column string) string {
+	if i, ok := r.header[column]; ok && i < len(r.fields) {
+		return strings.TrimSpace(r.fields[i])
+	}
+	return ""
+}
+
+// float parses a numeric field, treating an empty field as zero
+func (r csvRow) float(column string) (float64, error) {
+	s := r.get(column)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: invalid %s %q", r.line, column, s)
+	}
+	return v, nil
+}
+
+// readCSV reads a CSV file with a header row into rows addressable by column name
+func readCSV(r io.Reader) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	var rows []csvRow
+	for line := 2; ; line++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, csvRow{line: line, header: columns, fields: fields})
+	}
+	return rows, nil
+}
@@ -0,0 +1,188 @@
+package airports
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NASR subscription file names, from the CSV_Data folder of the FAA's
+// 28-day National Airspace System Resources subscription
+const (
+	nasrAirportsFile   = "APT_BASE.csv"
+	nasrRunwaysFile    = "APT_RWY.csv"
+	nasrRunwayEndsFile = "APT_RWY_END.csv"
+)
+
+// NASRProvider serves US airports from an extracted FAA NASR CSV
+// subscription. The files are read on first use.
+type NASRProvider struct {
+	dir string
+
+	once      sync.Once
+	idx       *index
+	effective time.Time
+	err       error
+}
+
+// NewNASRProvider creates a provider reading the NASR CSV files in dir
+func NewNASRProvider(dir string) *NASRProvider {
+	return &NASRProvider{dir: dir}
+}
+
+// Lookup implements Provider
+func (p *NASRProvider) Lookup(ctx context.Context, ident string) (*Airport, error) {
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p.idx.lookup(ident)
+}
+
+// All implements Provider
+func (p *NASRProvider) All(ctx context.Context) ([]*Airport, error) {
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return p.idx.airports, nil
+}
+
+// Effective returns the start of the subscription's 28-day cycle, from
+// the EFF_DATE column, or the modification time of the airport file when
+// the column is missing
+func (p *NASRProvider) Effective() (time.Time, error) {
+	if err := p.load(); err != nil {
+		return time.Time{}, err
+	}
+	return p.effective, nil
+}
+
+// load reads the subscription files once
+func (p *NASRProvider) load() error {
+	p.once.Do(func() {
+		p.idx, p.err = p.read()
+	})
+	return p.err
+}
+
+// read parses the airport, runway and runway end files into an index
+func (p *NASRProvider) read() (*index, error) {
+	rows, err := p.readFile(nasrAirportsFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		p.effective, _ = time.Parse("2006/01/02", rows[0].get("eff_date"))
+	}
+	if p.effective.IsZero() {
+		info, err := os.Stat(filepath.Join(p.dir, nasrAirportsFile))
+		if err != nil {
+			return nil, err
+		}
+		p.effective = info.ModTime()
+	}
+
+	byIdent := make(map[string]*Airport)
+	var list []*Airport
+	for _, row := range rows {
+		// Skip heliports, seaplane bases and other non-airport facilities
+		if t := row.get("site_type_code"); t != "" && t != "A" {
+			continue
+		}
+
+		a := &Airport{
+			Ident:   strings.ToUpper(row.get("arpt_id")),
+			ICAO:    strings.ToUpper(row.get("icao_id")),
+			Name:    row.get("arpt_name"),
+			City:    row.get("city"),
+			State:   row.get("state_code"),
+			Country: row.get("country_code"),
+		}
+		if a.Latitude, err = row.float("lat_decimal"); err != nil {
+			return nil, err
+		}
+		if a.Longitude, err = row.float("long_decimal"); err != nil {
+			return nil, err
+		}
+		if a.Elevation, err = row.float("elev"); err != nil {
+			return nil, err
+		}
+		if a.MagneticVariation, err = row.float("mag_varn"); err != nil {
+			return nil, err
+		}
+		// NASR gives the variation as a magnitude with an E/W hemisphere
+		if strings.EqualFold(row.get("mag_hemis"), "W") {
+			a.MagneticVariation = -a.MagneticVariation
+		}
+
+		byIdent[a.Ident] = a
+		list = append(list, a)
+	}
+
+	rows, err = p.readFile(nasrRunwaysFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		a, ok := byIdent[strings.ToUpper(row.get("arpt_id"))]
+		if !ok {
+			continue
+		}
+		rwy := Runway{
+			ID:      strings.ToUpper(row.get("rwy_id")),
+			Surface: row.get("surface_type_code"),
+		}
+		if rwy.Length, err = row.float("rwy_len"); err != nil {
+			return nil, err
+		}
+		if rwy.Width, err = row.float("rwy_width"); err != nil {
+			return nil, err
+		}
+		a.Runways = append(a.Runways, rwy)
+	}
+
+	rows, err = p.readFile(nasrRunwayEndsFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		a, ok := byIdent[strings.ToUpper(row.get("arpt_id"))]
+		if !ok {
+			continue
+		}
+		rwyID := strings.ToUpper(row.get("rwy_id"))
+		for i := range a.Runways {
+			if a.Runways[i].ID != rwyID {
+				continue
+			}
+			heading, err := row.float("true_alignment")
+			if err != nil {
+				return nil, err
+			}
+			a.Runways[i].Ends = append(a.Runways[i].Ends, RunwayEnd{
+				ID:          strings.ToUpper(row.get("rwy_end_id")),
+				TrueHeading: heading,
+			})
+		}
+	}
+
+	return newIndex(list), nil
+}
+
+// readFile opens and parses one of the subscription files
+func (p *NASRProvider) readFile(name string) ([]csvRow, error) {
+	f, err := os.Open(filepath.Join(p.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("NASR subscription: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := readCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	return rows, nil
+}
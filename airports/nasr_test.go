@@ -0,0 +1,55 @@
+package airports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNASRProvider(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		nasrAirportsFile: "EFF_DATE,SITE_NO,SITE_TYPE_CODE,STATE_CODE,ARPT_ID,CITY,COUNTRY_CODE,ARPT_NAME,LAT_DECIMAL,LONG_DECIMAL,ELEV,MAG_VARN,MAG_HEMIS,ICAO_ID\n" +
+			"2026/10/02,12345.*A,A,VA,JYO,LEESBURG,US,LEESBURG EXECUTIVE,39.0780,-77.5575,389.4,10,W,KJYO\n" +
+			"2026/10/02,12346.*H,H,VA,9VA1,LEESBURG,US,HOSPITAL HELIPORT,39.1,-77.5,300,10,W,\n",
+		nasrRunwaysFile: "EFF_DATE,SITE_NO,SITE_TYPE_CODE,STATE_CODE,ARPT_ID,CITY,COUNTRY_CODE,RWY_ID,RWY_LEN,RWY_WIDTH,SURFACE_TYPE_CODE\n" +
+			"2026/10/02,12345.*A,A,VA,JYO,LEESBURG,US,17/35,5500,100,ASPH\n",
+		nasrRunwayEndsFile: "EFF_DATE,SITE_NO,SITE_TYPE_CODE,STATE_CODE,ARPT_ID,CITY,COUNTRY_CODE,RWY_ID,RWY_END_ID,TRUE_ALIGNMENT\n" +
+			"2026/10/02,12345.*A,A,VA,JYO,LEESBURG,US,17/35,17,160\n" +
+			"2026/10/02,12345.*A,A,VA,JYO,LEESBURG,US,17/35,35,340\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider := NewNASRProvider(dir)
+
+	a, err := provider.Lookup(context.Background(), "KJYO")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if a.Name != "LEESBURG EXECUTIVE" || a.MagneticVariation != -10 || a.Elevation != 389.4 {
+		t.Errorf("Unexpected airport %+v", *a)
+	}
+	if len(a.Runways) != 1 || len(a.Runways[0].Ends) != 2 || a.Runways[0].Ends[1].TrueHeading != 340 {
+		t.Errorf("Unexpected runways %+v", a.Runways)
+	}
+
+	all, err := provider.All(context.Background())
+	if err != nil || len(all) != 1 {
+		t.Errorf("Expected heliport to be skipped, got %d airports (%v)", len(all), err)
+	}
+
+	effective, err := provider.Effective()
+	if want := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC); err != nil || !effective.Equal(want) {
+		t.Errorf("Expected effective date %v, got %v (%v)", want, effective, err)
+	}
+
+	if _, err := NewNASRProvider(t.TempDir()).All(context.Background()); err == nil {
+		t.Error("Expected error for missing subscription files, but got none")
+	}
+}
@@ -0,0 +1,81 @@
+package airports
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/geo"
+)
+
+// pavedSurfaces are the surface codes of hard-surfaced runways, matched
+// as prefixes so NASR codes such as "ASPH-G" and "CONC-TRTD" count
+var pavedSurfaces = []string{"ASPH", "CONC", "BIT", "PEM"}
+
+// Paved reports whether the runway has a hard surface
+func (r *Runway) Paved() bool {
+	surface := strings.ToUpper(r.Surface)
+	for _, code := range pavedSurfaces {
+		if strings.HasPrefix(surface, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Requirement describes the runway a diversion needs
+type Requirement struct {
+	MinLength float64 // in feet
+	MinWidth  float64 // in feet
+	Paved     bool    // Only hard-surfaced runways
+}
+
+// Meets reports whether a runway satisfies the requirement
+func (req Requirement) Meets(r *Runway) bool {
+	return r.Length >= req.MinLength && r.Width >= req.MinWidth && (!req.Paved || r.Paved())
+}
+
+// Candidate is an airport with at least one suitable runway
+type Candidate struct {
+	Airport  *Airport
+	Runways  []*Runway // Runways that meet the requirement, longest first
+	Distance float64   // Great-circle distance in nautical miles
+	Course   float64   // Initial true course in degrees
+}
+
+// Nearest searches a provider for the airports with a runway meeting the
+// requirement, nearest first. At most limit candidates are returned, or
+// all of them when limit is 0.
+func Nearest(ctx context.Context, p Provider, from geo.Point, req Requirement, limit int) ([]Candidate, error) {
+	all, err := p.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []Candidate
+	for _, a := range all {
+		var runways []*Runway
+		for i := range a.Runways {
+			if req.Meets(&a.Runways[i]) {
+				runways = append(runways, &a.Runways[i])
+			}
+		}
+		if len(runways) == 0 {
+			continue
+		}
+		sort.SliceStable(runways, func(i, j int) bool { return runways[i].Length > runways[j].Length })
+		to := a.Position()
+		candidates = append(candidates, Candidate{
+			Airport:  a,
+			Runways:  runways,
+			Distance: geo.Distance(from, to),
+			Course:   geo.InitialCourse(from, to),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Distance < candidates[j].Distance })
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
@@ -0,0 +1,61 @@
+package airports
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNearest(t *testing.T) {
+	provider, err := Embedded()
+	if err != nil {
+		t.Fatalf("Error loading embedded airports: %v", err)
+	}
+	jyo, err := provider.Lookup(context.Background(), "JYO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name  string
+		req   Requirement
+		limit int
+		want  []string
+	}{
+		{"any runway", Requirement{}, 4, []string{"JYO", "IAD", "HEF", "FDK"}},
+		{"long runway", Requirement{MinLength: 6000}, 3, []string{"IAD", "HEF", "DCA"}},
+		{"wide runway", Requirement{MinLength: 5000, MinWidth: 150}, 0, []string{"IAD", "DCA"}},
+	}
+	for _, tc := range testCases {
+		candidates, err := Nearest(context.Background(), provider, jyo.Position(), tc.req, tc.limit)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var got []string
+		for _, c := range candidates {
+			got = append(got, c.Airport.Ident)
+			for _, rwy := range c.Runways {
+				if !tc.req.Meets(rwy) {
+					t.Errorf("%s: %s runway %s does not meet %+v", tc.name, c.Airport.Ident, rwy.ID, tc.req)
+				}
+			}
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %v, expected %v", tc.name, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: got %v, expected %v", tc.name, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestRunwayPaved(t *testing.T) {
+	for surface, want := range map[string]bool{"ASPH": true, "CONC-TRTD": true, "asph-g": true, "TURF": false, "GRVL": false, "": false} {
+		if got := (&Runway{Surface: surface}).Paved(); got != want {
+			t.Errorf("%q: got %v, expected %v", surface, got, want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package airports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Recorder wraps a Provider and saves the airports it serves to Path as
+// JSON, so that a briefing can later be computed again with the same
+// airport data by a replay provider. The whole data set is recorded, since
+// Resolve searches all of it; for a NASR subscription that is several MB.
+type Recorder struct {
+	Path     string
+	Provider Provider
+
+	once sync.Once
+	err  error
+}
+
+// Lookup implements Provider
+func (r *Recorder) Lookup(ctx context.Context, ident string) (*Airport, error) {
+	if _, err := r.All(ctx); err != nil {
+		return nil, err
+	}
+	return r.Provider.Lookup(ctx, ident)
+}
+
+// All implements Provider, recording the airports on first use
+func (r *Recorder) All(ctx context.Context) ([]*Airport, error) {
+	list, err := r.Provider.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.once.Do(func() {
+		var data []byte
+		if data, r.err = json.Marshal(list); r.err != nil {
+			return
+		}
+		if r.err = os.MkdirAll(filepath.Dir(r.Path), 0o755); r.err == nil {
+			r.err = os.WriteFile(r.Path, data, 0o644)
+		}
+	})
+	if r.err != nil {
+		return nil, fmt.Errorf("recording airports: %v", r.err)
+	}
+	return list, nil
+}
+
+// NewReplayProvider serves the airports recorded at path by a Recorder
+func NewReplayProvider(path string) (Provider, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no airports recorded at %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Airport
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("recorded airports %s: %v", path, err)
+	}
+	return &CSVProvider{idx: newIndex(list)}, nil
+}
@@ -0,0 +1,44 @@
+package airports
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	embedded, err := Embedded()
+	if err != nil {
+		t.Fatalf("Error loading embedded airports: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "airports.json")
+
+	recorder := &Recorder{Path: path, Provider: embedded}
+	recorded, err := Resolve(context.Background(), recorder, "KJYO")
+	if err != nil {
+		t.Fatalf("Resolving through the recorder: %v", err)
+	}
+
+	replay, err := NewReplayProvider(path)
+	if err != nil {
+		t.Fatalf("Opening the recording: %v", err)
+	}
+	replayed, err := Resolve(context.Background(), replay, "KJYO")
+	if err != nil {
+		t.Fatalf("Resolving from the recording: %v", err)
+	}
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("Replayed %+v, expected %+v", replayed, recorded)
+	}
+
+	all, _ := replay.All(context.Background())
+	want, _ := embedded.All(context.Background())
+	if len(all) != len(want) {
+		t.Errorf("Replayed %d airports, recorded %d", len(all), len(want))
+	}
+
+	if _, err := NewReplayProvider(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing recording")
+	}
+}
@@ -0,0 +1,114 @@
+package airports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AmbiguousError is returned when an identifier matches more than one airport
+type AmbiguousError struct {
+	Input   string
+	Matches []*Airport
+}
+
+// Error implements the error interface
+func (e *AmbiguousError) Error() string {
+	names := make([]string, len(e.Matches))
+	for i, a := range e.Matches {
+		names[i] = describe(a)
+	}
+	return fmt.Sprintf("%s matches %d airports: %s; use a more specific identifier",
+		e.Input, len(e.Matches), strings.Join(names, ", "))
+}
+
+// NormalizeIdent upper-cases an identifier and strips surrounding whitespace
+func NormalizeIdent(ident string) string {
+	return strings.ToUpper(strings.TrimSpace(ident))
+}
+
+// Resolve finds the airport meant by an identifier as a pilot would type
+// it: an FAA or GPS identifier (JYO, W00), an ICAO code (KJYO), the ICAO
+// code without its K prefix, or an IATA code. If the identifier matches
+// different airports in different schemes an *AmbiguousError lists them.
+func Resolve(ctx context.Context, p Provider, ident string) (*Airport, error) {
+	input := NormalizeIdent(ident)
+	if input == "" {
+		return nil, fmt.Errorf("empty airport identifier")
+	}
+
+	all, err := p.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Airport
+	seen := make(map[*Airport]bool)
+	for _, a := range all {
+		if !seen[a] && identMatches(a, input) {
+			seen[a] = true
+			matches = append(matches, a)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		// A provider backed by a network service, such as Online, can
+		// know an airport that is not among All
+		if a, err := p.Lookup(ctx, input); err == nil {
+			return a, nil
+		} else if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, input)
+	case 1:
+		return matches[0], nil
+	}
+
+	// An exact ICAO match is unambiguous: ICAO codes are globally unique
+	for _, a := range matches {
+		if a.ICAO == input {
+			return a, nil
+		}
+	}
+	return nil, &AmbiguousError{Input: input, Matches: matches}
+}
+
+// identMatches reports whether a normalized input refers to an airport
+func identMatches(a *Airport, input string) bool {
+	switch {
+	case input == a.Ident, input == a.ICAO, a.IATA != "" && input == a.IATA:
+		return true
+	case len(input) == 3 && a.ICAO == "K"+input:
+		// K-less contiguous US ICAO code
+		return true
+	case len(input) == 4 && input[0] == 'K' && a.ICAO == "" && a.Ident == input[1:]:
+		// A K prefix added to a GPS or FAA ident that has no ICAO code
+		return true
+	}
+	return false
+}
+
+// describe identifies an airport for disambiguation messages
+func describe(a *Airport) string {
+	var codes []string
+	if a.ICAO != "" {
+		codes = append(codes, "ICAO "+a.ICAO)
+	}
+	codes = append(codes, "FAA "+a.Ident)
+	if a.IATA != "" {
+		codes = append(codes, "IATA "+a.IATA)
+	}
+
+	place := a.Name
+	if a.City != "" {
+		place += ", " + a.City
+	}
+	if a.State != "" {
+		place += " " + a.State
+	} else if a.Country != "" {
+		place += " " + a.Country
+	}
+	return fmt.Sprintf("%s (%s)", place, strings.Join(codes, "/"))
+}
@@ -0,0 +1,63 @@
+package airports
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	// HEF is Manassas' FAA ident; here it is also given as the IATA code of a
+	// second, fictional field to exercise disambiguation
+	provider, err := NewCSVProvider(
+		strings.NewReader(`ident,icao,iata,name,city,state,country,latitude,longitude,elevation,magnetic_variation
+HEF,KHEF,MNZ,Manassas Regional,Manassas,VA,US,38.72,-77.52,192,-10
+JYO,KJYO,,Leesburg Executive,Leesburg,VA,US,39.08,-77.56,389,-10
+W00,,,Freeway,Mitchellville,MD,US,38.94,-76.77,168,-10
+XHE,EXHE,HEF,Example Field,Example,,DE,50.0,8.0,400,3
+`),
+		strings.NewReader("airport,runway,length,width,surface,end1_id,end1_true_heading,end2_id,end2_true_heading\n"),
+	)
+	if err != nil {
+		t.Fatalf("Error reading CSV: %v", err)
+	}
+
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"JYO", "JYO"},
+		{"kjyo", "JYO"},
+		{" KJYO ", "JYO"},
+		{"MNZ", "HEF"},  // IATA code
+		{"KHEF", "HEF"}, // Exact ICAO match is never ambiguous
+		{"W00", "W00"},  // GPS-style ident without ICAO code
+		{"KW00", "W00"}, // K prefix added out of habit
+		{"EXHE", "XHE"},
+	}
+
+	for _, tc := range testCases {
+		a, err := Resolve(context.Background(), provider, tc.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if a.Ident != tc.want {
+			t.Errorf("%q: resolved to %s, expected %s", tc.input, a.Ident, tc.want)
+		}
+	}
+
+	_, err = Resolve(context.Background(), provider, "HEF")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousError for HEF, got %v", err)
+	}
+	if len(ambiguous.Matches) != 2 || !strings.Contains(err.Error(), "Manassas") || !strings.Contains(err.Error(), "Example Field") {
+		t.Errorf("Unexpected disambiguation error: %v", err)
+	}
+
+	if _, err := Resolve(context.Background(), provider, "ZZZ"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
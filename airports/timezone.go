@@ -0,0 +1,55 @@
+package airports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the time zone database so lookups work on hosts without one
+	_ "time/tzdata"
+)
+
+// stateTimeZones maps US state and territory codes to the time zone that
+// covers most of the state. It is the fallback for data sources such as
+// NASR that do not record a zone per airport; airports in the minority zone
+// of a split state (western Florida, eastern Tennessee, and so on) need an
+// explicit time_zone.
+var stateTimeZones = map[string]string{
+	"AK": "America/Anchorage", "AL": "America/Chicago", "AR": "America/Chicago",
+	"AS": "Pacific/Pago_Pago", "AZ": "America/Phoenix", "CA": "America/Los_Angeles",
+	"CO": "America/Denver", "CT": "America/New_York", "DC": "America/New_York",
+	"DE": "America/New_York", "FL": "America/New_York", "GA": "America/New_York",
+	"GU": "Pacific/Guam", "HI": "Pacific/Honolulu", "IA": "America/Chicago",
+	"ID": "America/Boise", "IL": "America/Chicago", "IN": "America/Indiana/Indianapolis",
+	"KS": "America/Chicago", "KY": "America/New_York", "LA": "America/Chicago",
+	"MA": "America/New_York", "MD": "America/New_York", "ME": "America/New_York",
+	"MI": "America/Detroit", "MN": "America/Chicago", "MO": "America/Chicago",
+	"MP": "Pacific/Saipan", "MS": "America/Chicago", "MT": "America/Denver",
+	"NC": "America/New_York", "ND": "America/Chicago", "NE": "America/Chicago",
+	"NH": "America/New_York", "NJ": "America/New_York", "NM": "America/Denver",
+	"NV": "America/Los_Angeles", "NY": "America/New_York", "OH": "America/New_York",
+	"OK": "America/Chicago", "OR": "America/Los_Angeles", "PA": "America/New_York",
+	"PR": "America/Puerto_Rico", "RI": "America/New_York", "SC": "America/New_York",
+	"SD": "America/Chicago", "TN": "America/Chicago", "TX": "America/Chicago",
+	"UT": "America/Denver", "VA": "America/New_York", "VI": "America/St_Thomas",
+	"VT": "America/New_York", "WA": "America/Los_Angeles", "WI": "America/Chicago",
+	"WV": "America/New_York", "WY": "America/Denver",
+}
+
+// Location returns the airport's local time zone, from the airport record
+// or else from the zone covering most of its state
+func (a *Airport) Location() (*time.Location, error) {
+	name := a.TimeZone
+	if name == "" {
+		name = stateTimeZones[strings.ToUpper(a.State)]
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no time zone known for %s", a.Ident)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("time zone for %s: %w", a.Ident, err)
+	}
+	return loc, nil
+}
@@ -0,0 +1,94 @@
+// Package atmosphere implements the ICAO standard atmosphere in the
+// troposphere, in the units used on POH charts (feet and °C)
+package atmosphere
+
+import (
+	"math"
+)
+
+// Standard atmosphere constants
+const (
+	SeaLevelTemperature = 15.0    // °C
+	LapseRate           = 1.9812  // °C per 1000 ft
+	StandardAltimeter   = 29.92   // inHg
+	StandardPressure    = 1013.25 // hPa
+	kelvin              = 273.15
+
+	// The troposphere pressure and density ratios are (1 - k·h)^n with h in feet
+	k             = 6.8755856e-6
+	pressureExp   = 5.2558797
+	densityExp    = pressureExp - 1
+	densityExpInv = 1 / densityExp
+
+	// Speed of sound at sea level standard temperature, in knots
+	seaLevelSpeedOfSound = 661.4786
+)
+
+// ISATemperature returns the standard temperature in °C at a pressure altitude in feet
+func ISATemperature(pressureAltitude float64) float64 {
+	return SeaLevelTemperature - LapseRate*pressureAltitude/1000
+}
+
+// PressureRatio returns the ratio of static pressure at a pressure altitude to sea level pressure
+func PressureRatio(pressureAltitude float64) float64 {
+	// The product is rounded before the subtraction so no platform fuses
+	// it, keeping deterministic takeoff results the same everywhere
+	return math.Pow(1-float64(k*pressureAltitude), pressureExp)
+}
+
+// DensityRatio returns the ratio of air density to sea level standard density
+// at a pressure altitude in feet and temperature in °C
+func DensityRatio(pressureAltitude, temperature float64) float64 {
+	theta := (temperature + kelvin) / (SeaLevelTemperature + kelvin)
+	return PressureRatio(pressureAltitude) / theta
+}
+
+// DensityAltitude returns the altitude in the standard atmosphere with the
+// same air density as the given pressure altitude (ft) and temperature (°C)
+func DensityAltitude(pressureAltitude, temperature float64) float64 {
+	sigma := DensityRatio(pressureAltitude, temperature)
+	return (1 - math.Pow(sigma, densityExpInv)) / k
+}
+
+// PressureAltitude returns the pressure altitude in feet at a field
+// elevation in feet with an altimeter setting in inHg
+func PressureAltitude(elevation, altimeter float64) float64 {
+	return elevation + (1-math.Pow(altimeter/StandardAltimeter, 1/pressureExp))/k
+}
+
+// PressureLevelAltitude returns the pressure altitude in feet of a pressure
+// in hPa, such as an isobaric level of a weather model
+func PressureLevelAltitude(pressure float64) float64 {
+	return (1 - math.Pow(pressure/StandardPressure, 1/pressureExp)) / k
+}
+
+// SpeedOfSound returns the speed of sound in knots at a temperature in °C
+func SpeedOfSound(temperature float64) float64 {
+	return seaLevelSpeedOfSound * math.Sqrt((temperature+kelvin)/(SeaLevelTemperature+kelvin))
+}
+
+// Field is the atmospheric picture at an airfield: how the pressure
+// altitude follows from the field elevation and altimeter setting, and the
+// density altitude from the pressure altitude and temperature
+type Field struct {
+	Elevation        float64 `json:"field_elevation"`   // in feet
+	Altimeter        float64 `json:"altimeter"`         // in inHg
+	Temperature      float64 `json:"temperature_c"`     // in °C
+	PressureAltitude float64 `json:"pressure_altitude"` // in feet
+	DensityAltitude  float64 `json:"density_altitude"`  // in feet
+	ISADeviation     float64 `json:"isa_deviation"`     // °C above standard at the pressure altitude
+}
+
+// NewField derives the pressure and density altitudes at a field elevation
+// in feet with an altimeter setting in inHg and a temperature in °C
+func NewField(elevation, altimeter, temperature float64) Field {
+	pressureAltitude := PressureAltitude(elevation, altimeter)
+	return Field{
+		Elevation:        elevation,
+		Altimeter:        altimeter,
+		Temperature:      temperature,
+		PressureAltitude: pressureAltitude,
+		DensityAltitude:  DensityAltitude(pressureAltitude, temperature),
+		ISADeviation:     temperature - ISATemperature(pressureAltitude),
+	}
+}
@@ -0,0 +1,92 @@
+package atmosphere
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStandardDayDensityAltitude(t *testing.T) {
+	for _, altitude := range []float64{0, 1000, 5000, 10000} {
+		da := DensityAltitude(altitude, ISATemperature(altitude))
+		if math.Abs(da-altitude) > 1 {
+			t.Errorf("Standard day at %.0f ft: expected density altitude %.0f ft, got %.1f ft", altitude, altitude, da)
+		}
+	}
+}
+
+func TestDensityAltitude(t *testing.T) {
+	tests := []struct {
+		pressureAltitude float64
+		temperature      float64
+		expected         float64 // in feet, rounded
+		tolerance        float64
+	}{
+		{0, 35, 2300, 50},
+		{5000, 30, 7800, 50},
+		{1500, 26.7, 3190, 50},
+		{3000, -20, -690, 50},
+	}
+
+	for _, tc := range tests {
+		da := DensityAltitude(tc.pressureAltitude, tc.temperature)
+		if math.Abs(da-tc.expected) > tc.tolerance {
+			t.Errorf("PA %.0f ft, %.1f°C: expected ~%.0f ft, got %.0f ft", tc.pressureAltitude, tc.temperature, tc.expected, da)
+		}
+	}
+}
+
+func TestPressureRatio(t *testing.T) {
+	if r := PressureRatio(0); r != 1 {
+		t.Errorf("Sea level: expected 1, got %f", r)
+	}
+	// 29.92 inHg at sea level, ~24.89 inHg at 5000 ft
+	if r := PressureRatio(5000) * 29.92; math.Abs(r-24.89) > 0.02 {
+		t.Errorf("5000 ft: expected 24.89 inHg, got %.2f", r)
+	}
+}
+
+func TestPressureAltitude(t *testing.T) {
+	if pa := PressureAltitude(1500, StandardAltimeter); math.Abs(pa-1500) > 1e-6 {
+		t.Errorf("Standard setting: expected 1500 ft, got %.1f ft", pa)
+	}
+	// Roughly 1000 ft per inHg near sea level
+	if pa := PressureAltitude(0, 29.42); math.Abs(pa-470) > 10 {
+		t.Errorf("29.42 inHg at sea level: expected ~470 ft, got %.0f ft", pa)
+	}
+	if pa := PressureAltitude(5000, 30.42); math.Abs(pa-4540) > 10 {
+		t.Errorf("30.42 inHg at 5000 ft: expected ~4540 ft, got %.0f ft", pa)
+	}
+}
+
+func TestPressureLevelAltitude(t *testing.T) {
+	// Standard heights of the mandatory pressure levels
+	for _, tc := range []struct{ pressure, altitude float64 }{
+		{1013.25, 0}, {850, 4781}, {700, 9882}, {500, 18289},
+	} {
+		if got := PressureLevelAltitude(tc.pressure); math.Abs(got-tc.altitude) > 5 {
+			t.Errorf("%.0f hPa: expected %.0f ft, got %.0f ft", tc.pressure, tc.altitude, got)
+		}
+	}
+}
+
+func TestSpeedOfSound(t *testing.T) {
+	if a := SpeedOfSound(SeaLevelTemperature); math.Abs(a-661.5) > 0.1 {
+		t.Errorf("Sea level standard: expected 661.5 kt, got %.1f kt", a)
+	}
+	if a := SpeedOfSound(-56.5); math.Abs(a-573.6) > 0.5 {
+		t.Errorf("Tropopause: expected 573.6 kt, got %.1f kt", a)
+	}
+}
+
+func TestNewField(t *testing.T) {
+	f := NewField(1500, 29.42, 30)
+	if math.Abs(f.PressureAltitude-1970) > 10 {
+		t.Errorf("Expected a pressure altitude of ~1970 ft, got %.0f ft", f.PressureAltitude)
+	}
+	if f.DensityAltitude != DensityAltitude(f.PressureAltitude, 30) || f.DensityAltitude < f.PressureAltitude+1500 {
+		t.Errorf("Unexpected density altitude %.0f ft", f.DensityAltitude)
+	}
+	if math.Abs(f.ISADeviation-(30-ISATemperature(f.PressureAltitude))) > 1e-9 || f.Elevation != 1500 || f.Altimeter != 29.42 {
+		t.Errorf("Unexpected field %+v", f)
+	}
+}
@@ -0,0 +1,197 @@
+// Package batch runs a calculation over many inputs on a pool of workers,
+// for parameter sweeps and Monte Carlo runs, reporting progress as it goes.
+//
+// The calculators are safe for concurrent use once configured, so one
+// calculator can be shared by every worker.
+package batch
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultProgressInterval is how often progress is reported by default
+const DefaultProgressInterval = 100 * time.Millisecond
+
+// chunkSize is how many inputs a worker takes at a time; chart lookups
+// take well under a microsecond, so handing out single inputs would spend
+// more time coordinating than computing
+const chunkSize = 256
+
+// Options configure a run
+type Options struct {
+	// Workers is the number of goroutines computing results (default:
+	// runtime.GOMAXPROCS(0))
+	Workers int
+
+	// Progress, if set, is called with the number of inputs done so far,
+	// from one goroutine at a time: every ProgressInterval while the run
+	// is going and once at the end
+	Progress         func(done, total int)
+	ProgressInterval time.Duration
+}
+
+// Result is the outcome of one input
+type Result[T any] struct {
+	Index int // Position of the input
+	Value T
+	Err   error
+}
+
+// Run computes every input on a pool of workers and returns the results
+// in input order. Errors from compute are kept per result; Run itself
+// only fails if ctx is cancelled, returning the results computed so far.
+func Run[In, Out any](ctx context.Context, inputs []In, compute func(In) (Out, error), opts Options) ([]Result[Out], error) {
+	results := make([]Result[Out], len(inputs))
+	err := Each(ctx, len(inputs), func(i int) {
+		value, err := compute(inputs[i])
+		results[i] = Result[Out]{Index: i, Value: value, Err: err}
+	}, opts)
+	return results, err
+}
+
+// Each calls work for every index from 0 to n-1 on a pool of workers.
+// Calls for different indices run concurrently and in no particular order.
+func Each(ctx context.Context, n int, work func(i int), opts Options) error {
+	var done atomic.Int64
+	stop := opts.watch(n, &done)
+	each(ctx, 0, n, work, opts.workers(), &done)
+	stop()
+	return ctx.Err()
+}
+
+// Stream computes results for the indices 0 to n-1 on a pool of workers
+// and passes them to emit in index order as they become available, so a
+// large run can be written out without holding every result in memory.
+// Only a few thousand results are buffered at a time. Stream stops at the
+// first error from emit and returns it.
+func Stream[Out any](ctx context.Context, n int, compute func(i int) (Out, error), emit func(Result[Out]) error, opts Options) error {
+	workers := opts.workers()
+	block := workers * chunkSize * 4
+
+	var done atomic.Int64
+	stop := opts.watch(n, &done)
+	defer stop()
+
+	// One block is computed while the previous one is emitted
+	running, cancel := context.WithCancel(ctx)
+	defer cancel()
+	free := make(chan []Result[Out], 2)
+	free <- make([]Result[Out], block)
+	free <- make([]Result[Out], block)
+	blocks := make(chan []Result[Out], 1)
+	go func() {
+		defer close(blocks)
+		for start := 0; start < n; start += block {
+			end := start + block
+			if end > n {
+				end = n
+			}
+			var buf []Result[Out]
+			select {
+			case buf = <-free:
+				buf = buf[:end-start]
+			case <-running.Done():
+				return
+			}
+			each(running, start, end, func(i int) {
+				value, err := compute(i)
+				buf[i-start] = Result[Out]{Index: i, Value: value, Err: err}
+			}, workers, &done)
+			if running.Err() != nil {
+				return
+			}
+			select {
+			case blocks <- buf:
+			case <-running.Done():
+				return
+			}
+		}
+	}()
+
+	for buf := range blocks {
+		for _, r := range buf {
+			if err := emit(r); err != nil {
+				cancel()
+				for range blocks {
+				}
+				return err
+			}
+		}
+		free <- buf[:cap(buf)]
+	}
+	return ctx.Err()
+}
+
+// workers returns the size of the worker pool
+func (o Options) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// watch reports progress from a goroutine until the returned function is
+// called, which makes the final report
+func (o Options) watch(n int, done *atomic.Int64) func() {
+	if o.Progress == nil {
+		return func() {}
+	}
+	interval := o.ProgressInterval
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+
+	quit := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				o.Progress(int(done.Load()), n)
+			case <-quit:
+				o.Progress(int(done.Load()), n)
+				return
+			}
+		}
+	}()
+	return func() {
+		close(quit)
+		<-stopped
+	}
+}
+
+// each calls work for the indices from start to end-1 on workers
+// goroutines, handing out chunks of indices and counting them in done
+func each(ctx context.Context, start, end int, work func(i int), workers int, done *atomic.Int64) {
+	var next atomic.Int64
+	next.Store(int64(start))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				from := int(next.Add(chunkSize)) - chunkSize
+				if from >= end {
+					return
+				}
+				to := from + chunkSize
+				if to > end {
+					to = end
+				}
+				for i := from; i < to; i++ {
+					work(i)
+				}
+				done.Add(int64(to - from))
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,130 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func TestRun(t *testing.T) {
+	calculator := performance.NewTakeoffCalculator()
+	var inputs []performance.TakeoffParams
+	for altitude := 0.0; altitude <= 7000; altitude += 100 {
+		for temperature := -40.0; temperature <= 40; temperature += 2 {
+			inputs = append(inputs, performance.TakeoffParams{PressureAltitude: altitude, Temperature: temperature, Weight: 2200})
+		}
+	}
+	inputs = append(inputs, performance.TakeoffParams{PressureAltitude: 9000, Temperature: 15, Weight: 2200})
+
+	var mu sync.Mutex
+	var reports []int
+	results, err := Run(context.Background(), inputs, calculator.CalculateTakeoff, Options{
+		Workers:          4,
+		ProgressInterval: time.Millisecond,
+		Progress: func(done, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			if total != len(inputs) {
+				t.Errorf("Progress total %d, expected %d", total, len(inputs))
+			}
+			reports = append(reports, done)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(results) != len(inputs) {
+		t.Fatalf("Got %d results for %d inputs", len(results), len(inputs))
+	}
+	for i, r := range results {
+		want, wantErr := calculator.CalculateTakeoff(inputs[i])
+		if r.Index != i || (r.Err == nil) != (wantErr == nil) {
+			t.Fatalf("Result %d: index %d, error %v, expected error %v", i, r.Index, r.Err, wantErr)
+		}
+		if wantErr == nil && r.Value.TakeoffDistance != want.TakeoffDistance {
+			t.Errorf("Result %d: %.1f ft, expected %.1f ft", i, r.Value.TakeoffDistance, want.TakeoffDistance)
+		}
+	}
+	if results[len(results)-1].Err == nil {
+		t.Error("Expected an error for the input above the chart")
+	}
+
+	if len(reports) == 0 || reports[len(reports)-1] != len(inputs) {
+		t.Errorf("Progress reports %v should end with %d", reports, len(inputs))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i] < reports[i-1] {
+			t.Errorf("Progress went backwards: %v", reports)
+			break
+		}
+	}
+}
+
+func TestEachCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	calls := 0
+	err := Each(ctx, 1000000, func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if calls++; calls == 1000 {
+			cancel()
+		}
+	}, Options{Workers: 2})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if calls >= 1000000 {
+		t.Errorf("Every input was computed despite the cancellation")
+	}
+}
+
+func TestStream(t *testing.T) {
+	const n = 10000
+	square := func(i int) (int, error) {
+		if i%1000 == 999 {
+			return 0, errors.New("skipped")
+		}
+		return i * i, nil
+	}
+
+	next := 0
+	err := Stream(context.Background(), n, square, func(r Result[int]) error {
+		if r.Index != next {
+			t.Fatalf("Got index %d, expected %d", r.Index, next)
+		}
+		if (r.Err != nil) != (next%1000 == 999) || (r.Err == nil && r.Value != next*next) {
+			t.Fatalf("Result %d: %d, error %v", next, r.Value, r.Err)
+		}
+		next++
+		return nil
+	}, Options{Workers: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if next != n {
+		t.Errorf("Got %d results, expected %d", next, n)
+	}
+
+	// An error from emit stops the run
+	stop := errors.New("disk full")
+	emitted := 0
+	err = Stream(context.Background(), 1000000, square, func(r Result[int]) error {
+		if emitted++; emitted == 5000 {
+			return stop
+		}
+		return nil
+	}, Options{Workers: 2})
+	if err != stop {
+		t.Errorf("Expected the emit error, got %v", err)
+	}
+	if emitted != 5000 {
+		t.Errorf("Emitted %d results after the error", emitted-5000)
+	}
+}
@@ -0,0 +1,133 @@
+// Package bundle loads takeoff charts shipped as files rather than built
+// into the binary, checking them before they are served
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/digitize"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// Bundle is a takeoff chart shipped as a file rather than built into the
+// binary, such as a chart digitized with otto digitize for a club's
+// airframe. It takes everything but the takeoff chart from a built-in
+// base profile, and carries reference points read off the POH figure to
+// check the chart against.
+type Bundle struct {
+	ID           string                    `json:"id"`
+	Name         string                    `json:"name"`
+	Base         string                    `json:"base"`    // Built-in profile for the weights, speeds and other charts
+	Version      string                    `json:"version"` // e.g. the date the chart was digitized
+	TakeoffChart *performance.TakeoffChart `json:"takeoff_chart"`
+	Golden       []Case                    `json:"golden"`
+}
+
+// Case is a takeoff reference point of a bundle, as an aircraft.GoldenCase
+type Case struct {
+	Name            string                    `json:"name"`
+	Params          performance.TakeoffParams `json:"params"`
+	TakeoffDistance float64                   `json:"takeoff_distance"`
+	Tolerance       float64                   `json:"tolerance"`
+}
+
+// Read reads a chart bundle file
+func Read(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// Parse parses the JSON of a chart bundle
+func Parse(data []byte) (*Bundle, error) {
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Lint checks a bundle before it is installed: the chart's axes and
+// tables must be consistent, its distances must never fall as the
+// altitude, temperature or weight rises, and it must reproduce every
+// reference point. It returns the problems found, or none.
+func (b *Bundle) Lint() []string {
+	var problems []string
+	switch {
+	case b.ID == "" || b.Base == "" || b.Version == "":
+		problems = append(problems, "bundle needs an id, a base and a version")
+	case aircraft.Builtin(strings.ToLower(b.ID)):
+		problems = append(problems, fmt.Sprintf("bundle id %s is a built-in profile", b.ID))
+	}
+	if _, err := aircraft.Lookup(b.Base); err != nil || !aircraft.Builtin(strings.ToLower(b.Base)) {
+		problems = append(problems, fmt.Sprintf("base %q is not a built-in profile", b.Base))
+	}
+	if b.TakeoffChart == nil {
+		return append(problems, "bundle has no takeoff_chart")
+	}
+	if _, err := performance.NewChartTakeoffCalculator(b.TakeoffChart); err != nil {
+		return append(problems, err.Error())
+	}
+	problems = append(problems, digitize.Monotonicity(b.TakeoffChart)...)
+	if len(b.Golden) == 0 {
+		problems = append(problems, "bundle has no golden reference points")
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+
+	p, _ := b.profile()
+	for _, r := range p.SelfTest() {
+		if !r.Pass() {
+			problems = append(problems, fmt.Sprintf("%s: %v", r.Case.Name, r.Err))
+		}
+	}
+	return problems
+}
+
+// Profile checks the bundle with Lint and returns its profile, without
+// installing it
+func (b *Bundle) Profile() (*aircraft.Profile, error) {
+	if problems := b.Lint(); len(problems) > 0 {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return b.profile()
+}
+
+// profile builds the bundle's profile on its base. The base's variants
+// are dropped, as they would swap the bundle's chart for the base's.
+func (b *Bundle) profile() (*aircraft.Profile, error) {
+	base, err := aircraft.Lookup(b.Base)
+	if err != nil {
+		return nil, err
+	}
+	chart := b.TakeoffChart
+	p := *base
+	p.ID = strings.ToLower(b.ID)
+	p.Name = b.Name
+	if p.Name == "" {
+		p.Name = base.Name
+	}
+	p.Version = b.Version
+	p.NewTakeoffCalculator = func() *performance.TakeoffCalculator {
+		c, _ := performance.NewChartTakeoffCalculator(chart)
+		return c
+	}
+	p.Variants, p.Variant = nil, nil
+	p.Golden = make([]aircraft.GoldenCase, len(b.Golden))
+	for i, c := range b.Golden {
+		p.Golden[i] = aircraft.GoldenCase{Name: c.Name, Params: c.Params, TakeoffDistance: c.TakeoffDistance, Tolerance: c.Tolerance}
+	}
+	return &p, nil
+}
@@ -0,0 +1,79 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+)
+
+// testBundle is a bundle of the PA-28-161 chart under another ID
+func testBundle(t *testing.T) *Bundle {
+	base, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &Bundle{ID: "N8123X", Base: base.ID, Version: "2026-10-01", TakeoffChart: base.NewTakeoffCalculator().Chart()}
+	for _, c := range base.Golden {
+		b.Golden = append(b.Golden, Case{Name: c.Name, Params: c.Params, TakeoffDistance: c.TakeoffDistance, Tolerance: c.Tolerance})
+	}
+	return b
+}
+
+func TestRead(t *testing.T) {
+	data, err := json.Marshal(testBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "n8123x.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := b.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != "n8123x" || p.Version != "2026-10-01" || p.NewLandingCalculator == nil {
+		t.Errorf("Unexpected profile %+v", p)
+	}
+
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(b *Bundle)
+		want   string
+	}{
+		{"Built-in ID", func(b *Bundle) { b.ID = "PA28-161" }, "built-in"},
+		{"No Version", func(b *Bundle) { b.Version = "" }, "version"},
+		{"Unknown Base", func(b *Bundle) { b.Base = "c999" }, "base"},
+		{"No Chart", func(b *Bundle) { b.TakeoffChart = nil }, "takeoff_chart"},
+		{"No Golden", func(b *Bundle) { b.Golden = nil }, "golden"},
+		{"One Headwind", func(b *Bundle) {
+			b.TakeoffChart.Headwinds, b.TakeoffChart.HeadwindFactors = []float64{0}, []float64{1}
+		}, "at least two headwinds"},
+		{"Falling Distance", func(b *Bundle) { b.TakeoffChart.Distances[1][0] = 100 }, "less than"},
+		{"Mismatch", func(b *Bundle) { b.Golden[0].TakeoffDistance += 500 }, "takeoff distance"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := testBundle(t)
+			tc.change(b)
+			problems := b.Lint()
+			if !strings.Contains(strings.Join(problems, "; "), tc.want) {
+				t.Errorf("Expected a problem about %q, got %v", tc.want, problems)
+			}
+			if _, err := b.Profile(); err == nil {
+				t.Error("Expected the bundle to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/termstyle"
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+func main() {
+	// Define CLI flags, named as the takeoff tool's
+	pressureAlt := flag.Float64("altitude", 0, "Pressure altitude in feet")
+	tempC := flag.Float64("temp-c", 15, "Temperature in °C")
+	tempF := flag.Float64("temp-f", 0, "Temperature in °F (overrides temp-c if provided)")
+	weight := flag.Float64("weight", 2325, "Aircraft weight at landing in pounds")
+	takeoffWeight := flag.Float64("takeoff-weight", 0, "Weight at takeoff in pounds, for the landing weight after -flight-time (overrides -weight)")
+	flightTime := flag.Duration("flight-time", 0, "Flight time from takeoff to landing, e.g. 1h30m, burning fuel off -takeoff-weight")
+	fuelFlow := flag.Float64("fuel-flow", 0, "Average fuel flow for -flight-time in US gallons per hour (default: the aircraft profile's planning fuel flow)")
+	fuelGallons := flag.Float64("fuel-gal", 0, "Usable fuel at takeoff in US gallons, to check the trip's burn against and show the fuel at landing")
+	windComponent := flag.Float64("wind", 0, "Wind component in knots (positive for headwind, negative for tailwind)")
+	airportID := flag.String("airport", "", "Destination airport identifier (with -runway, for the available distance)")
+	runwayID := flag.String("runway", "", "Landing runway, e.g. 17")
+	available := flag.Float64("available", 0, "Available landing distance in feet for the margin (default: the runway length with -airport and -runway)")
+	flaps := flag.String("flaps", "", "Flap setting in degrees, 0, 25 or 40, corrected from the chart per the POH notes (default: the chart's, 40° for the pa28-161)")
+	techniqueID := flag.String("technique", "", "Landing technique: 'short-field', as charted with maximum braking, or 'normal', corrected for a faster approach and moderate braking (default: the chart's)")
+	aircraftID := flag.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := flag.String("variant", "", "Engine or STC variant of the aircraft, swapping in its performance charts (see the aircraft profile)")
+	asiUnits := flag.String("asi", "", "Airspeed indicator scale for speeds: 'knots', 'mph' for older panels, or 'both' (default from the aircraft profile)")
+	unitSystem := flag.String("units", "imperial", "Unit system for display: 'imperial', 'metric' or 'mixed'")
+	noColor := flag.Bool("no-color", false, "Do not highlight warnings and margins in color (also set by NO_COLOR)")
+	factored := flag.Bool("factored", false, "Also show the landing distance with the UK CAA/AOPA safety factor (×1.43), labeled with its source")
+	goAround := flag.Bool("go-around", false, "Also check the balked landing climb at the landing weight and density altitude")
+	dataDir := flag.String("data-dir", "", "Directory of airports.csv and runways.csv to use instead of the airport data built into the binary")
+	showHelp := flag.Bool("help", false, "Show help")
+	
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "PA-28-161 Cherokee Warrior II Landing Performance Calculator\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExample:\n  %s -altitude 1500 -temp-c 25 -weight 2200 -wind 10\n", os.Args[0])
+	}
+	flag.Parse()
+	
+	tempFProvided := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "temp-f" {
+			tempFProvided = true
+		}
+	})
+	if *showHelp || flag.NFlag() == 0 {
+		flag.Usage()
+		os.Exit(0)
+	}
+	
+	if *dataDir != "" {
+		if err := airports.SetDataDir(*dataDir); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	
+	// Look up the selected aircraft
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if profile.NewLandingCalculator == nil {
+		log.Fatalf("Error: %s has no landing chart", profile.Name)
+	}
+	if *asiUnits != "" {
+		asi, err := aircraft.ParseASIUnits(*asiUnits)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		profile = profile.WithASI(asi)
+	}
+	
+	temperature := *tempC
+	if tempFProvided {
+		temperature = performance.ConvertFahrenheitToCelsius(*tempF)
+	}
+	flapSetting, err := performance.ParseFlapSetting(*flaps)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	technique, err := performance.ParseLandingTechnique(*techniqueID)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	params := performance.LandingParams{
+		PressureAltitude: *pressureAlt,
+		Temperature:      temperature,
+		Weight:           *weight,
+		WindComponent:    *windComponent,
+		FlapSetting:      flapSetting,
+		Technique:        technique,
+	}
+	
+	// Burn the trip's fuel off the takeoff weight, so the landing is
+	// computed from the same loading as the takeoff
+	var trip *landingTrip
+	if *takeoffWeight > 0 || *flightTime > 0 {
+		if *takeoffWeight <= 0 {
+			log.Fatalf("Error: -flight-time needs -takeoff-weight")
+		}
+		trip = &landingTrip{Trip: wb.Trip{
+			TakeoffWeight: *takeoffWeight,
+			FlightTime:    *flightTime,
+			FuelFlow:      *fuelFlow,
+			FuelGallons:   *fuelGallons,
+		}}
+		if trip.Arrival, err = profile.WeightBalance.Arrival(trip.Trip); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		params.Weight = trip.Arrival.LandingWeight
+	}
+	
+	calc := profile.NewLandingCalculator()
+	if *goAround {
+		if profile.NewClimbCalculator == nil {
+			log.Fatalf("Error: %s has no climb chart for the go-around check", profile.Name)
+		}
+		calc.CheckGoAround(profile.NewClimbCalculator())
+	}
+	// Flag figures that are within the chart but unlikely to be what was
+	// meant; a figure outside it may be a mistyped one they point to
+	inputs := aircraft.Inputs{PressureAltitude: params.PressureAltitude, Temperature: params.Temperature}
+	if trip == nil {
+		inputs.Weight = params.Weight
+	}
+	if *airportID != "" {
+		if airport, err := lookupAirport(*airportID); err == nil {
+			inputs.FieldElevation, inputs.HasElevation = airport.Elevation, true
+		}
+	}
+	plausibility := profile.Plausibility(inputs)
+	result, err := calc.CalculateLanding(params)
+	if err != nil {
+		for _, a := range plausibility {
+			log.Printf("%s", a)
+		}
+		log.Fatalf("Error calculating landing performance: %v", err)
+	}
+	
+	distance := *available
+	if distance <= 0 && *airportID != "" && *runwayID != "" {
+		if distance, err = runwayLength(*airportID, *runwayID); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	
+	var factor *performance.SafetyFactor
+	if *factored {
+		factor = &performance.CAASafetySense
+	}
+	displayResults(profile, params, trip, result, plausibility, factor, distance, strings.ToLower(*unitSystem), termstyle.Detect(os.Stdout, *noColor, false))
+}
+
+// landingTrip is the trip a landing weight was derived from
+type landingTrip struct {
+	Trip    wb.Trip
+	Arrival *wb.Arrival
+}
+
+// displayResults prints the inputs and the landing performance, with the
+// trip the landing weight came from, if any
+func displayResults(profile *aircraft.Profile, params performance.LandingParams, trip *landingTrip, result *performance.LandingResult, advisories []aircraft.Advisory,
+	factor *performance.SafetyFactor, available float64, unitSystem string, style termstyle.Styler) {
+	title := profile.Name + " Landing Performance"
+	fmt.Printf("\n%s\n%s\n\n", style.Emphasis(title), strings.Repeat("=", len(title)))
+	
+	fmt.Printf("Input Parameters:\n")
+	fmt.Printf("----------------\n")
+	fmt.Printf("Pressure Altitude: %.0f ft\n", params.PressureAltitude)
+	if unitSystem == "imperial" {
+		fmt.Printf("Temperature: %.1f°F (%.1f°C)\n", performance.ConvertCelsiusToFahrenheit(params.Temperature), params.Temperature)
+	} else {
+		fmt.Printf("Temperature: %.1f°C (%.1f°F)\n", params.Temperature, performance.ConvertCelsiusToFahrenheit(params.Temperature))
+	}
+	fmt.Printf("Weight: %.0f lbs\n", params.Weight)
+	if trip != nil {
+		fmt.Printf("  (%.0f lbs at takeoff less %.1f gal, %.0f lbs, burned in %s at %.1f gph)\n", trip.Trip.TakeoffWeight,
+			trip.Arrival.BurnGallons, trip.Arrival.Burn, strings.TrimSuffix(trip.Trip.FlightTime.String(), "0s"), trip.Arrival.FuelFlow)
+		if trip.Trip.FuelGallons > 0 {
+			fmt.Printf("Fuel at Landing: %.1f gal\n", trip.Arrival.FuelRemaining)
+		}
+	}
+	if params.FlapSetting != performance.FlapsCharted {
+		fmt.Printf("Flaps: %s° (chart corrected per the POH notes)\n", string(params.FlapSetting))
+	}
+	if params.Technique == performance.TechniqueNormal {
+		fmt.Printf("Technique: Normal (chart corrected for a faster approach and moderate braking)\n")
+	}
+	switch {
+	case params.WindComponent > 0:
+		fmt.Printf("Wind: %.0f knots headwind\n", params.WindComponent)
+	case params.WindComponent < 0:
+		fmt.Printf("Wind: %.0f knots tailwind\n", -params.WindComponent)
+	default:
+		fmt.Printf("Wind: No wind\n")
+	}
+	
+	fmt.Printf("\nLanding Performance:\n")
+	fmt.Printf("-------------------\n")
+	
+	// The factored distance goes beside the POH figure, never in its place
+	label := "Landing Distance (over 50 ft obstacle)"
+	if factor != nil {
+		label = "Landing Distance (over 50 ft obstacle, POH)"
+	}
+	fmt.Printf("%s: %s\n", label, style.Emphasis(formatDistance(result.LandingDistance, unitSystem)))
+	if factor != nil {
+		fmt.Printf("Factored Landing Distance (×%.2f, %s): %s\n", factor.Landing, factor.Source,
+			style.Emphasis(formatDistance(factor.LandingDistance(result.LandingDistance), unitSystem)))
+	}
+	fmt.Printf("Ground Roll: %s\n", formatDistance(result.GroundRoll, unitSystem))
+	fmt.Printf("Approach Speed: %s\n", profile.Speeds.ASI.Format(result.ApproachSpeed))
+	
+	if available > 0 {
+		required := result.LandingDistance
+		if factor != nil {
+			required = factor.LandingDistance(required)
+		}
+		margin := available - required
+		line := fmt.Sprintf("Margin: %s of %s available", formatDistance(margin, unitSystem), formatDistance(available, unitSystem))
+		if margin < 0 {
+			fmt.Printf("%s\n", style.Warning(fmt.Sprintf("WARNING: %s short of the %s available", formatDistance(-margin, unitSystem), formatDistance(available, unitSystem))))
+		} else {
+			fmt.Printf("%s\n", style.Good(line))
+		}
+	}
+	
+	flaps := "full flaps"
+	if params.FlapSetting != performance.FlapsCharted {
+		flaps = params.FlapSetting.String()
+	}
+	if g := result.GoAround; g != nil {
+		line := fmt.Sprintf("Go-around: %+.0f fpm at %s with %s (density altitude %.0f ft)", g.RateOfClimb, profile.Speeds.ASI.Format(g.Speed), flaps, g.DensityAltitude)
+		if g.Positive {
+			fmt.Printf("%s\n", line)
+		} else {
+			fmt.Printf("%s\n", style.Warning(fmt.Sprintf("WARNING: Go-around: no climb (%.0f fpm) with %s at %.0f ft density altitude; brief retracting flaps early or a decision point", g.RateOfClimb, flaps, g.DensityAltitude)))
+		}
+	}
+	
+	if len(advisories) > 0 {
+		fmt.Printf("\n")
+	}
+	for _, a := range advisories {
+		fmt.Printf("%s\n", style.Caution(a.String()))
+	}
+	
+	// Safety note
+	var corrected []string
+	if params.FlapSetting != performance.FlapsCharted {
+		corrected = append(corrected, flaps)
+	}
+	if params.Technique == performance.TechniqueNormal {
+		corrected = append(corrected, "a normal landing")
+	}
+	if len(corrected) > 0 {
+		fmt.Printf("\nNOTE: The chart assumes full flaps, power off, maximum braking and a paved,\n")
+		fmt.Printf("      level, dry runway, corrected here for %s.\n", strings.Join(corrected, " and "))
+		if params.Technique == performance.TechniqueNormal {
+			fmt.Printf("      The normal landing correction is a rule of thumb, not a POH figure.\n")
+		}
+		fmt.Printf("      Always verify these calculations against the POH.\n")
+		return
+	}
+	fmt.Printf("\nNOTE: The chart assumes full flaps, power off, maximum braking and a paved,\n")
+	fmt.Printf("      level, dry runway. Always verify these calculations against the POH.\n")
+}
+
+// formatDistance formats a distance in feet in the unit system
+func formatDistance(feet float64, unitSystem string) string {
+	switch unitSystem {
+	case "metric":
+		return fmt.Sprintf("%.0f m (%.0f ft)", units.FeetToMeters(feet), feet)
+	case "mixed":
+		return fmt.Sprintf("%.0f ft (%.0f m)", feet, units.FeetToMeters(feet))
+	default:
+		return fmt.Sprintf("%.0f ft", feet)
+	}
+}
+
+// runwayLength looks up the length of a runway in feet in the embedded
+// airport data
+func runwayLength(airportID, runwayID string) (float64, error) {
+	airport, err := lookupAirport(airportID)
+	if err != nil {
+		return 0, err
+	}
+	rwy, _, err := airport.Runway(runwayID)
+	if err != nil {
+		return 0, err
+	}
+	return rwy.Length, nil
+}
+
+// lookupAirport finds an airport in the embedded airport data
+func lookupAirport(airportID string) (*airports.Airport, error) {
+	provider, err := airports.Embedded()
+	if err != nil {
+		return nil, err
+	}
+	return airports.Resolve(context.Background(), provider, airportID)
+}
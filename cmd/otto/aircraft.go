@@ -0,0 +1,283 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/termplot"
+)
+
+// aircraftActions lists every otto aircraft action by name
+var aircraftActions = map[string]command{
+	"gentest": {
+		summary: "Generate a Go test of the takeoff chart from points read off the POH figure",
+		run:     runAircraftGentest,
+	},
+	"inspect": {
+		summary: "Show each chart's axes, ranges, grid resolution and gaps",
+		run:     runAircraftInspect,
+	},
+}
+
+// runAircraft dispatches to one of the aircraft profile actions
+func runAircraft(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-help" || args[0] == "-h" {
+		aircraftUsage()
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	action, ok := aircraftActions[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "otto aircraft: unknown action %q\n\n", args[0])
+		aircraftUsage()
+		return 2
+	}
+	return action.run(args[1:])
+}
+
+// aircraftUsage prints the list of available actions
+func aircraftUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: otto aircraft <action> [options] <aircraft>\n\n")
+	fmt.Fprintf(os.Stderr, "Actions:\n")
+
+	names := make([]string, 0, len(aircraftActions))
+	for name := range aircraftActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, aircraftActions[name].summary)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nRun 'otto aircraft <action> -help' for options.\n")
+}
+
+// runAircraftInspect prints how densely each of a profile's charts is
+// digitized, so users can see where interpolation confidence is low
+func runAircraftInspect(args []string) int {
+	fs := flag.NewFlagSet("aircraft inspect", flag.ContinueOnError)
+	plotStyle := fs.String("plot", "", "Plot each chart's curves with the digitized points marked: term (braille) or ascii")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto aircraft inspect [options] <aircraft>\n\n")
+		fmt.Fprintf(os.Stderr, "Gaps are spans between chart lines more than 1.5 times the axis's\n")
+		fmt.Fprintf(os.Stderr, "typical spacing, and chart values that were never digitized.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	// Allow the options after the aircraft as well as before it
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return 0
+			}
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *plotStyle != "" && *plotStyle != "term" && *plotStyle != "ascii" {
+		fmt.Fprintf(os.Stderr, "otto aircraft inspect: unknown -plot %q, expected term or ascii\n", *plotStyle)
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto aircraft inspect: %v\n", err)
+		return 2
+	}
+
+	fmt.Printf("\n%s charts\n", profile.Name)
+	charts := []struct {
+		name     string
+		coverage performance.Coverage
+	}{
+		{"Takeoff", profile.NewTakeoffCalculator().Coverage()},
+		{"Climb", profile.NewClimbCalculator().Coverage()},
+		{"Cruise", profile.NewCruiseCalculator().Coverage()},
+		{"Landing", profile.NewLandingCalculator().Coverage()},
+	}
+	for _, chart := range charts {
+		printCoverage(chart.name, chart.coverage)
+		if *plotStyle != "" {
+			fmt.Println()
+			fmt.Print(coveragePlot(chart.coverage, *plotStyle == "ascii"))
+			fmt.Printf("%s\n", lineLegend(chart.coverage.Lines))
+		}
+	}
+	return 0
+}
+
+// printCoverage prints a chart's axes and gaps
+func printCoverage(name string, c performance.Coverage) {
+	fmt.Printf("\n%s: %s Figure %s, %s\n\n", name, c.Source.Document, c.Source.Figure, c.Source.Title)
+
+	rows := [][]string{{"Axis", "Range", "Lines", "Spacing"}}
+	for _, axis := range c.Axes {
+		spacing := "range only"
+		switch narrowest, widest := axis.Spacing(); {
+		case widest == 0:
+		case narrowest == widest:
+			spacing = fmt.Sprintf("%g %s", widest, axis.Unit)
+		default:
+			spacing = fmt.Sprintf("%g to %g %s", narrowest, widest, axis.Unit)
+		}
+		rows = append(rows, []string{
+			axis.Name,
+			fmt.Sprintf("%g to %g %s", axis.Min(), axis.Max(), axis.Unit),
+			fmt.Sprintf("%d", len(axis.Values)),
+			spacing,
+		})
+	}
+	printColumns(rows)
+
+	fmt.Printf("\n%d digitized points", c.Points)
+	if len(c.Gaps) == 0 {
+		fmt.Printf(", no gaps\n")
+		return
+	}
+	fmt.Printf(", %d gaps:\n", len(c.Gaps))
+	for _, g := range c.Gaps {
+		fmt.Printf("  %s %g to %g: %s\n", g.Axis, g.From, g.To, g.Reason)
+	}
+}
+
+// coveragePlot draws a chart's curves along its first axis with every
+// digitized point marked, so sparse stretches stand out
+func coveragePlot(c performance.Coverage, ascii bool) string {
+	plot := termplot.Plot{ASCII: ascii, XUnit: c.Axes[0].Unit, YUnit: " " + c.Unit}
+	if c.Axes[0].Unit != "°C" {
+		plot.XUnit = " " + c.Axes[0].Unit
+	}
+	for _, line := range c.Lines {
+		plot.Series = append(plot.Series, termplot.Series{X: line.X, Y: line.Y})
+		for i := range line.X {
+			plot.Marks = append(plot.Marks, termplot.Mark{X: line.X[i], Y: line.Y[i]})
+		}
+	}
+	return plot.Render()
+}
+
+// lineLegend names the plotted curves in order
+func lineLegend(lines []performance.Line) string {
+	labels := make([]string, 0, len(lines))
+	for _, line := range lines {
+		labels = append(labels, line.Label)
+	}
+	return "Lines: " + strings.Join(labels, ", ")
+}
+
+// runAircraftGentest turns reference points read off a chart scan into a
+// table-driven test of the profile, and reports how far the profile is
+// from each point now
+func runAircraftGentest(args []string) int {
+	fs := flag.NewFlagSet("aircraft gentest", flag.ContinueOnError)
+	pointsFile := fs.String("points", "", "CSV of points read off the chart: pressure_altitude, temperature_c or temperature_f, weight, distance[, name, wind, liftoff_speed, barrier_speed, tolerance]")
+	outFile := fs.String("o", "", "Write the test to this file (default: stdout)")
+	pkg := fs.String("package", "aircraft", "Package of the generated test")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto aircraft gentest -points FILE [options] <aircraft>\n\n")
+		fmt.Fprintf(os.Stderr, "A point without a tolerance column is allowed the larger of 25 ft and 2%% of its\n")
+		fmt.Fprintf(os.Stderr, "distance for reading the scan, plus the chart's interpolation tolerance there.\n")
+		fmt.Fprintf(os.Stderr, "The residual of each point is reported on stderr; the test is written even\n")
+		fmt.Fprintf(os.Stderr, "when some fail, so they can be fixed in the profile or re-read from the scan.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	// Allow the options after the aircraft as well as before it
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return 0
+			}
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != 1 || *pointsFile == "" {
+		fs.Usage()
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto aircraft gentest: %v\n", err)
+		return 2
+	}
+	f, err := os.Open(*pointsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto aircraft gentest: %v\n", err)
+		return 2
+	}
+	cases, err := aircraft.ReadReferenceCSV(f)
+	f.Close()
+	if err == nil {
+		cases, err = profile.DeriveTolerances(cases)
+	}
+	if err != nil {
+		printErrorLines("aircraft gentest", fmt.Errorf("%s: %w", *pointsFile, err))
+		return 2
+	}
+
+	rows := [][]string{{"", "Point", "Read", "Computed", "Residual", "Tolerance"}}
+	failed := 0
+	for _, r := range profile.Check(cases) {
+		status := "ok"
+		if !r.Pass() {
+			status = "FAIL"
+			failed++
+		}
+		rows = append(rows, []string{
+			status,
+			r.Case.Name,
+			fmt.Sprintf("%.0f ft", r.Case.TakeoffDistance),
+			fmt.Sprintf("%.0f ft", r.Result.TakeoffDistance),
+			fmt.Sprintf("%+.0f ft", r.Result.TakeoffDistance-r.Case.TakeoffDistance),
+			fmt.Sprintf("±%.0f ft", r.Case.Tolerance),
+		})
+	}
+	fprintColumns(os.Stderr, rows)
+	fmt.Fprintf(os.Stderr, "%d of %d points within tolerance\n", len(cases)-failed, len(cases))
+
+	out := os.Stdout
+	if *outFile != "" {
+		if out, err = os.Create(*outFile); err != nil {
+			fmt.Fprintf(os.Stderr, "otto aircraft gentest: %v\n", err)
+			return 1
+		}
+		defer out.Close()
+	}
+	if err := profile.WriteChartTest(out, cases, *pkg, filepath.Base(*pointsFile)); err != nil {
+		fmt.Fprintf(os.Stderr, "otto aircraft gentest: %v\n", err)
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
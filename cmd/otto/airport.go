@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/netconfig"
+)
+
+// runAirport prints airport and runway information
+func runAirport(args []string) int {
+	fs := flag.NewFlagSet("airport", flag.ContinueOnError)
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto airport [options] <ident>\n\n")
+		fmt.Fprintf(os.Stderr, "An airport missing from the NASR files or the sample data is looked up on the network.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	provider, err := onlineAirportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto airport: %v\n", err)
+		return 1
+	}
+
+	a, err := airports.Resolve(context.Background(), provider, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto airport: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("\n%s (%s) %s\n", a.Ident, a.ICAO, a.Name)
+	fmt.Printf("%s, %s\n\n", a.City, a.State)
+	fmt.Printf("Position: %.4f, %.4f\n", a.Latitude, a.Longitude)
+	fmt.Printf("Elevation: %.0f ft\n", a.Elevation)
+	fmt.Printf("Magnetic Variation: %.0f°%s\n\n", abs(a.MagneticVariation), hemisphere(a.MagneticVariation))
+
+	fmt.Printf("Runways:\n")
+	for _, rwy := range a.Runways {
+		fmt.Printf("  %-8s %5.0f x %-3.0f ft  %s\n", rwy.ID, rwy.Length, rwy.Width, rwy.Surface)
+	}
+	return 0
+}
+
+// airportProvider selects the NASR subscription when a directory is given,
+// and the embedded sample data otherwise
+func airportProvider(nasrDir string) (airports.Provider, error) {
+	if nasrDir != "" {
+		return airports.NewNASRProvider(nasrDir), nil
+	}
+	return airports.Embedded()
+}
+
+// onlineAirportProvider builds the provider for nasrDir behind a client of
+// the airports integration from the network configuration
+func onlineAirportProvider(nasrDir, configPath string) (airports.Provider, error) {
+	local, err := airportProvider(nasrDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := netconfig.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := cfg.HTTPClient("airports")
+	if err != nil {
+		return nil, err
+	}
+
+	return &airports.Online{
+		Local: local,
+		Client: &airports.Client{
+			BaseURL:    cfg.BaseURL("airports", airports.DefaultBaseURL),
+			HTTPClient: httpClient,
+		},
+	}, nil
+}
+
+// hemisphere returns E or W for a magnetic variation
+func hemisphere(variation float64) string {
+	if variation < 0 {
+		return "W"
+	}
+	return "E"
+}
+
+// abs returns the absolute value of x
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/flightplan"
+)
+
+// runAltitude finds the best cruise altitudes for a route from the winds
+// aloft, including the cost of climbing to each
+func runAltitude(args []string) int {
+	fs := flag.NewFlagSet("altitude", flag.ContinueOnError)
+	departureAlt := fs.Float64("altitude", 0, "Departure pressure altitude in feet")
+	tempC := fs.Float64("temp-c", 15, "Departure temperature in °C")
+	distance := fs.Float64("distance", 0, "Route distance in nautical miles")
+	course := fs.Float64("course", 0, "True course in degrees")
+	magVar := fs.Float64("magvar", 0, "Magnetic variation in degrees, east positive (for VFR cruising altitudes)")
+	power := fs.Float64("power", 65, "Cruise power in percent")
+	winds := fs.String("winds", "", "Winds aloft as ALTITUDE:DDSS[±TT] levels, e.g. 3000:2710,6000:2815+05,9000:2920-02")
+	fuel := fs.Bool("fuel", false, "Rank by fuel burned instead of time en route")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto altitude -distance 120 -course 085 -winds 3000:2710,6000:2815+05 [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Ranks the VFR cruising altitudes for the course by time (or fuel) en route.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *distance <= 0 || *winds == "" {
+		fmt.Fprintf(os.Stderr, "otto altitude: -distance and -winds are required\n")
+		return 2
+	}
+
+	forecast, err := flightplan.ParseWindsAloft(*winds)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto altitude: %v\n", err)
+		return 2
+	}
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto altitude: %v\n", err)
+		return 2
+	}
+
+	query := flightplan.AltitudeQuery{
+		DepartureAltitude:    *departureAlt,
+		DepartureTemperature: *tempC,
+		Distance:             *distance,
+		Course:               *course,
+		Variation:            *magVar,
+		Power:                *power,
+		Winds:                forecast,
+	}
+	if *fuel {
+		query.Objective = flightplan.MinimizeFuel
+	}
+
+	options, err := flightplan.OptimumAltitude(profile.NewClimbCalculator(), profile.NewCruiseCalculator(), query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto altitude: %v\n", err)
+		return 1
+	}
+
+	rows := [][]string{{"Altitude", "TAS", "Wind", "GS", "Climb", "Time", "Fuel"}}
+	for _, o := range options {
+		rows = append(rows, []string{
+			fmt.Sprintf("%.0f ft", o.Altitude),
+			fmt.Sprintf("%.0f kt", o.TrueAirspeed),
+			formatWind(o.Headwind),
+			fmt.Sprintf("%.0f kt", o.Groundspeed),
+			fmt.Sprintf("%.0f min", o.ClimbTime),
+			fmt.Sprintf("%.0f min", o.Time),
+			fmt.Sprintf("%.1f gal", o.Fuel),
+		})
+	}
+
+	fmt.Printf("\nBest cruise altitudes for %.0f nm on %03.0f°T at %.0f%% power\n\n", *distance, *course, *power)
+	printColumns(rows)
+	return 0
+}
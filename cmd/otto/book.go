@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// bookRange is a named range of temperatures in °C, tabulated together
+type bookRange struct {
+	name  string
+	temps []float64
+}
+
+// bookSeasons are the temperature ranges tabulated for each season
+var bookSeasons = []bookRange{
+	{"Winter", []float64{-10, -5, 0, 5, 10}},
+	{"Spring and Fall", []float64{5, 10, 15, 20, 25}},
+	{"Summer", []float64{20, 25, 30, 35, 40}},
+}
+
+// bookWinds are the wind components of the wind correction table, in knots
+var bookWinds = []float64{-5, 0, 5, 10, 15}
+
+// booklet is the data of a home-field performance booklet
+type booklet struct {
+	Aircraft  string
+	Airport   *airports.Airport
+	Longest   float64 // Longest runway in feet
+	Source    performance.Source
+	Generated string
+	Weights   []float64
+	Seasons   []bookSeason
+	Wind      bookWind
+}
+
+// bookSeason holds the tables for one season
+type bookSeason struct {
+	Name   string
+	Tables []bookTable
+}
+
+// bookTable is takeoff distance by temperature and weight at one
+// altimeter setting, with the rate of climb
+type bookTable struct {
+	Altimeter        float64
+	PressureAltitude float64
+	Rows             []bookRow
+	Chart            template.HTML
+}
+
+// bookRow is one temperature of a table
+type bookRow struct {
+	Temperature float64
+	Distances   []bookCell
+	Climb       string
+}
+
+// bookCell is one takeoff distance; Long marks a distance beyond the
+// longest runway
+type bookCell struct {
+	Text string
+	Long bool
+}
+
+// bookWind is the wind correction table: the takeoff distance with each
+// wind component as a percentage of the no-wind distance
+type bookWind struct {
+	Temperature float64
+	Weight      float64
+	Winds       []float64
+	Percent     []string
+}
+
+// runBook renders a printable HTML booklet of takeoff tables for an airport
+func runBook(args []string) int {
+	fs := flag.NewFlagSet("book", flag.ContinueOnError)
+	airportID := fs.String("airport", "", "Home airport identifier (e.g. KJYO)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft (see the aircraft profile)")
+	var equipment stringList
+	fs.Var(&equipment, "equipment", "Optional equipment installed, comma separated")
+	var weights rangeList
+	fs.Var(&weights, "weights", "Weights in pounds to tabulate, as START:END:STEP or a list (default: four across the chart)")
+	var temps rangeList
+	fs.Var(&temps, "temps", "Temperatures in °C to tabulate in one table instead of by season, as START:END:STEP or a list")
+	var altimeters floatList
+	altimeters.Set("29.92")
+	fs.Var(&altimeters, "altimeters", "Altimeter settings in inHg to tabulate, comma separated")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto book -airport KJYO [options] > booklet.html\n\n")
+		fmt.Fprintf(os.Stderr, "Writes a self-contained HTML booklet of takeoff distance and climb tables, with\n")
+		fmt.Fprintf(os.Stderr, "charts, for each season at the airport. Print it, or save it as PDF from a browser.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *airportID == "" {
+		fmt.Fprintf(os.Stderr, "otto book: -airport is required\n")
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err == nil {
+		profile, err = profile.WithEquipment(equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto book: %v\n", err)
+		return 2
+	}
+	provider, err := airportProvider(*nasrDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto book: %v\n", err)
+		return 1
+	}
+	airport, err := airports.Resolve(context.Background(), provider, *airportID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto book: %v\n", err)
+		return 1
+	}
+
+	takeoff := profile.NewTakeoffCalculator()
+	climb := profile.NewClimbCalculator()
+	if len(weights) == 0 {
+		for _, limit := range takeoff.Envelope() {
+			if limit.Field != performance.FieldWeight {
+				continue
+			}
+			for i := 0; i < 4; i++ {
+				weights = append(weights, limit.Max-float64(i)*(limit.Max-limit.Min)/3)
+			}
+		}
+	}
+
+	book := booklet{
+		Aircraft:  profile.Name,
+		Airport:   airport,
+		Source:    takeoff.Source(),
+		Generated: time.Now().Format("2 January 2006"),
+		Weights:   weights,
+	}
+	for _, rwy := range airport.Runways {
+		book.Longest = math.Max(book.Longest, rwy.Length)
+	}
+
+	seasons := bookSeasons
+	if len(temps) > 0 {
+		seasons = []bookRange{{"All seasons", temps}}
+	}
+	for _, season := range seasons {
+		s := bookSeason{Name: season.name}
+		for _, altimeter := range altimeters {
+			s.Tables = append(s.Tables, book.table(takeoff, climb, altimeter, season.temps))
+		}
+		book.Seasons = append(book.Seasons, s)
+	}
+
+	// The wind table is at standard temperature for the field, where the
+	// chart is sure to have data
+	book.Wind = bookWind{
+		Temperature: math.Round(atmosphere.ISATemperature(airport.Elevation)),
+		Weight:      weights[0],
+		Winds:       bookWinds,
+	}
+	still, err := takeoff.CalculateTakeoff(performance.TakeoffParams{
+		PressureAltitude: airport.Elevation, Temperature: book.Wind.Temperature, Weight: book.Wind.Weight,
+	})
+	for _, w := range bookWinds {
+		text := "—"
+		if err == nil {
+			if r, err := takeoff.CalculateTakeoff(performance.TakeoffParams{
+				PressureAltitude: airport.Elevation, Temperature: book.Wind.Temperature, Weight: book.Wind.Weight, WindComponent: w,
+			}); err == nil {
+				text = fmt.Sprintf("%.0f%%", r.TakeoffDistance/still.TakeoffDistance*100)
+			}
+		}
+		book.Wind.Percent = append(book.Wind.Percent, text)
+	}
+
+	if err := bookTemplate.Execute(os.Stdout, book); err != nil {
+		fmt.Fprintf(os.Stderr, "otto book: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// table computes the takeoff distances and climb rates at an altimeter
+// setting over the given temperatures, with a chart of the distances
+func (b *booklet) table(takeoff *performance.TakeoffCalculator, climb *performance.ClimbCalculator, altimeter float64, temps []float64) bookTable {
+	t := bookTable{
+		Altimeter:        altimeter,
+		PressureAltitude: math.Round(atmosphere.PressureAltitude(b.Airport.Elevation, altimeter)),
+	}
+	lines := make([][]float64, len(b.Weights))
+	for _, temp := range temps {
+		row := bookRow{Temperature: temp, Climb: fmt.Sprintf("%.0f", climb.RateOfClimb(t.PressureAltitude, temp))}
+		for i, weight := range b.Weights {
+			cell := bookCell{Text: "—"}
+			distance := math.NaN()
+			params := performance.TakeoffParams{PressureAltitude: t.PressureAltitude, Temperature: temp, Weight: weight}
+			if errs := takeoff.Validate(params); len(errs) == 0 {
+				if r, err := takeoff.CalculateTakeoff(params); err == nil {
+					distance = r.TakeoffDistance
+					cell = bookCell{Text: fmt.Sprintf("%.0f", distance), Long: b.Longest > 0 && distance > b.Longest}
+				}
+			}
+			row.Distances = append(row.Distances, cell)
+			lines[i] = append(lines[i], distance)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	t.Chart = b.chart(temps, lines)
+	return t
+}
+
+// Chart layout in SVG user units
+const (
+	chartWidth, chartHeight = 480.0, 240.0
+	chartLeft, chartRight   = 50.0, 20.0
+	chartTop, chartBottom   = 24.0, 30.0 // Room for the legend and temperatures
+)
+
+// chart draws takeoff distance against temperature as an SVG line per
+// weight, with the longest runway as a dashed line when it is in range
+// and a legend of the weights. Missing distances
+// (NaN) break the lines.
+func (b *booklet) chart(temps []float64, lines [][]float64) template.HTML {
+	top := 0.0
+	for _, line := range lines {
+		for _, d := range line {
+			if !math.IsNaN(d) {
+				top = math.Max(top, d)
+			}
+		}
+	}
+	if top == 0 || len(temps) < 2 {
+		return ""
+	}
+	top = math.Ceil(top*1.1/500) * 500
+	x := func(i int) float64 {
+		return chartLeft + float64(i)*(chartWidth-chartLeft-chartRight)/float64(len(temps)-1)
+	}
+	y := func(d float64) float64 { return chartHeight - chartBottom - d/top*(chartHeight-chartBottom-chartTop) }
+
+	var s strings.Builder
+	fmt.Fprintf(&s, `<svg viewBox="0 0 %.0f %.0f" role="img" aria-label="Takeoff distance against temperature">`, chartWidth, chartHeight)
+	for d := 0.0; d <= top; d += 500 {
+		fmt.Fprintf(&s, `<line class="grid" x1="%.0f" x2="%.0f" y1="%.1f" y2="%.1f"/>`, chartLeft, chartWidth-chartRight, y(d), y(d))
+		if int(d)%1000 == 0 {
+			fmt.Fprintf(&s, `<text x="%.0f" y="%.1f" text-anchor="end">%.0f</text>`, chartLeft-4, y(d)+4, d)
+		}
+	}
+	for i, temp := range temps {
+		fmt.Fprintf(&s, `<text x="%.1f" y="%.0f" text-anchor="middle">%.0f°C</text>`, x(i), chartHeight-chartBottom+16, temp)
+	}
+	if b.Longest > 0 && b.Longest <= top {
+		fmt.Fprintf(&s, `<line class="runway" x1="%.0f" x2="%.0f" y1="%.1f" y2="%.1f"/>`, chartLeft, chartWidth-chartRight, y(b.Longest), y(b.Longest))
+	}
+	for w, line := range lines {
+		var path strings.Builder
+		move := true
+		for i, d := range line {
+			if math.IsNaN(d) {
+				move = true
+				continue
+			}
+			op := "L"
+			if move {
+				op, move = "M", false
+			}
+			fmt.Fprintf(&path, "%s%.1f %.1f ", op, x(i), y(d))
+		}
+		class := fmt.Sprintf("w%d", w%4)
+		fmt.Fprintf(&s, `<path class="%s" d="%s"><title>%.0f lbs</title></path>`, class, strings.TrimSpace(path.String()), b.Weights[w])
+
+		// Legend across the top
+		lx := chartLeft + 10 + float64(w)*90
+		fmt.Fprintf(&s, `<path class="%s" d="M%.0f 12 L%.0f 12"/><text x="%.0f" y="16">%.0f lbs</text>`, class, lx, lx+20, lx+24, b.Weights[w])
+	}
+	s.WriteString(`</svg>`)
+	return template.HTML(s.String())
+}
+
+// bookTemplate lays out the booklet for printing, a season per page
+var bookTemplate = template.Must(template.New("book").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Airport.Ident}} {{.Aircraft}} performance</title>
+<style>
+@page { size: letter; margin: 12mm; }
+body { font-family: sans-serif; font-size: 11pt; max-width: 190mm; margin: auto; }
+h1 { font-size: 16pt; margin-bottom: 0; }
+h2 { font-size: 13pt; border-bottom: 2px solid #000; }
+section { break-after: page; }
+section:last-of-type { break-after: auto; }
+table { border-collapse: collapse; margin: 6px 0 12px; }
+th, td { border: 1px solid #000; padding: 2px 8px; text-align: right; }
+td.long { font-weight: bold; background: #ddd; }
+svg { width: 100%; max-width: 130mm; font-size: 10px; }
+svg .grid { stroke: #ccc; }
+svg .runway { stroke: #000; stroke-dasharray: 6 4; }
+svg path { fill: none; stroke-width: 2; }
+svg .w0 { stroke: #000; } svg .w1 { stroke: #555; stroke-dasharray: 8 3; }
+svg .w2 { stroke: #888; stroke-dasharray: 3 3; } svg .w3 { stroke: #aaa; }
+.note { font-size: 9pt; }
+</style>
+</head>
+<body>
+<section>
+<h1>{{.Airport.Ident}} {{.Airport.Name}}: {{.Aircraft}}</h1>
+<p>Field elevation {{printf "%.0f" .Airport.Elevation}} ft.
+{{range .Airport.Runways}}Runway {{.ID}}: {{printf "%.0f" .Length}} ft {{.Surface}}. {{end}}</p>
+<p class="note">Takeoff distances are over a 50 ft obstacle on a paved, level, dry runway with no wind, from
+{{.Source.Document}} Figure {{.Source.Figure}} ({{.Source.Title}}). Distances in bold are longer than the
+longest runway; — is outside the chart. Climb is the rate of climb in ft/min at maximum weight.
+Each 0.1 inHg of altimeter below 29.92 raises the pressure altitude about 100 ft.
+Generated {{.Generated}}; check the current POH and conditions before flight.</p>
+
+<h2>Wind correction</h2>
+<p>Takeoff distance as a share of the no-wind distance at {{printf "%.0f" .Wind.Weight}} lbs and {{printf "%.0f" .Wind.Temperature}}°C.</p>
+<table>
+<tr><th>Wind (kts, + headwind)</th>{{range .Wind.Winds}}<th>{{printf "%+.0f" .}}</th>{{end}}</tr>
+<tr><th>Distance</th>{{range .Wind.Percent}}<td>{{.}}</td>{{end}}</tr>
+</table>
+</section>
+{{$weights := .Weights}}
+{{range .Seasons}}<section>
+<h2>{{.Name}}</h2>
+{{range .Tables}}<h3>Altimeter {{printf "%.2f" .Altimeter}} inHg, pressure altitude {{printf "%.0f" .PressureAltitude}} ft</h3>
+<table>
+<tr><th>Temp</th>{{range $weights}}<th>{{printf "%.0f" .}} lbs</th>{{end}}<th>Climb</th></tr>
+{{range .Rows}}<tr><th>{{printf "%.0f" .Temperature}}°C</th>{{range .Distances}}<td{{if .Long}} class="long"{{end}}>{{.Text}}</td>{{end}}<td>{{.Climb}}</td></tr>
+{{end}}</table>
+{{.Chart}}
+{{end}}</section>
+{{end}}
+</body>
+</html>
+`))
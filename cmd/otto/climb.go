@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// runClimb prints the climb table from the departure altitude to cruise
+// altitude, for top-of-climb and en route planning
+func runClimb(args []string) int {
+	fs := flag.NewFlagSet("climb", flag.ContinueOnError)
+	pressureAlt := fs.Float64("altitude", 0, "Departure pressure altitude in feet")
+	tempC := fs.Float64("temp-c", 15, "Departure temperature in °C")
+	tempF := fs.Float64("temp-f", 0, "Departure temperature in °F (overrides temp-c if provided)")
+	cruise := fs.Float64("cruise", 0, "Cruise pressure altitude in feet")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft (see the aircraft profile)")
+	var equipment stringList
+	fs.Var(&equipment, "equipment", "Comma-separated optional equipment installed (see the aircraft profile)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto climb -altitude 500 -temp-c 20 -cruise 6500 [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Time, fuel and distance are cumulative from departure, with no wind.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	params := performance.ClimbParams{
+		PressureAltitude: *pressureAlt,
+		Temperature:      *tempC,
+		CruiseAltitude:   *cruise,
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "temp-f" {
+			params.Temperature = performance.ConvertFahrenheitToCelsius(*tempF)
+		}
+	})
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err == nil {
+		profile, err = profile.WithEquipment(equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto climb: %v\n", err)
+		return 2
+	}
+
+	result, err := profile.NewClimbCalculator().CalculateClimb(params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto climb: %v\n", err)
+		return 1
+	}
+
+	rows := [][]string{{"Altitude", "OAT", "Rate", "IAS", "TAS", "Time", "Fuel", "Distance"}}
+	for _, row := range result.Rows {
+		rows = append(rows, []string{
+			fmt.Sprintf("%.0f ft", row.PressureAltitude),
+			fmt.Sprintf("%.0f°C", row.Temperature),
+			fmt.Sprintf("%.0f fpm", row.RateOfClimb),
+			fmt.Sprintf("%.0f kt", row.IndicatedSpeed),
+			fmt.Sprintf("%.0f kt", row.TrueSpeed),
+			fmt.Sprintf("%.1f min", row.Time),
+			fmt.Sprintf("%.1f gal", row.Fuel),
+			fmt.Sprintf("%.1f nm", row.Distance),
+		})
+	}
+
+	fmt.Printf("\n%s Climb to %.0f ft\n\n", profile.Name, params.CruiseAltitude)
+	printColumns(rows)
+	fmt.Printf("\nTop of climb: %.0f min, %.1f gal, %.0f nm from departure\n", result.Time, result.Fuel, result.Distance)
+	for _, a := range result.Adjustments {
+		fmt.Printf("Adjusted: %s rate of climb, not from the POH chart\n", a)
+	}
+	return 0
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// Departure corridor: 500 ft either side of the centreline at the
+// departure end of the runway, splaying 15° each side, as the initial
+// climb area of a diverse departure assessment (FAA Order 8260.3)
+const (
+	corridorHalfWidth = 500.0 // in feet
+	corridorSplay     = 15.0  // in degrees
+	corridorMinLength = 2.0   // in nautical miles past the departure end
+)
+
+// standardGradient is the climb gradient in ft/nm assumed by instrument
+// departure procedures unless a higher one is published
+const standardGradient = 200.0
+
+// feetPerNauticalMile converts runway and takeoff distances for the geo package
+var feetPerNauticalMile = units.MetersPerNauticalMile / units.MetersPerFoot
+
+// climbOut is the path of a departure from brake release to a height
+// above the field, along the extended centreline
+type climbOut struct {
+	Track     []geo.Point
+	Altitudes []float64 // Feet MSL at each point of Track
+	Distance  float64   // Ground distance from the departure end to the height in nautical miles
+	Gradient  float64   // Climb gradient from 50 ft to the height, over the ground, in ft/nm
+}
+
+// runCorridor exports the climb-out path and departure corridor of a
+// runway as KML or GeoJSON
+func runCorridor(args []string) int {
+	fs := flag.NewFlagSet("corridor", flag.ContinueOnError)
+	airportID := fs.String("airport", "", "Departure airport identifier")
+	runwayID := fs.String("runway", "", "Departure runway end (e.g. 17)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft (see the aircraft profile)")
+	var equipment stringList
+	fs.Var(&equipment, "equipment", "Optional equipment installed, comma separated")
+	weight := fs.Float64("weight", 0, "Takeoff weight in pounds (default: the chart's maximum)")
+	temp := fs.Float64("temp", 15, "Temperature in °C")
+	altimeter := fs.Float64("altimeter", 29.92, "Altimeter setting in inHg")
+	windDir := fs.Float64("wind-dir", 0, "Wind direction in degrees true")
+	windSpeed := fs.Float64("wind-speed", 0, "Wind speed in knots")
+	height := fs.Float64("height", 1000, "Height above the field to climb to, in feet")
+	format := fs.String("format", "kml", "Output format: kml or geojson")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto corridor -airport KJYO -runway 17 [options] > departure.kml\n\n")
+		fmt.Fprintf(os.Stderr, "Writes the runway, the climb-out path at the charted takeoff distance and rate of\n")
+		fmt.Fprintf(os.Stderr, "climb, and the departure corridor (500 ft either side of the centreline at the\n")
+		fmt.Fprintf(os.Stderr, "departure end, splaying 15°) for Google Earth or an EFB map layer. The runway is\n")
+		fmt.Fprintf(os.Stderr, "placed on the airport reference point, so check it against the imagery.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *airportID == "" || *runwayID == "" {
+		fmt.Fprintf(os.Stderr, "otto corridor: -airport and -runway are required\n")
+		return 2
+	}
+	if *format != "kml" && *format != "geojson" {
+		fmt.Fprintf(os.Stderr, "otto corridor: -format must be kml or geojson\n")
+		return 2
+	}
+	if *height <= 50 {
+		fmt.Fprintf(os.Stderr, "otto corridor: -height must be above 50 ft\n")
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err == nil {
+		profile, err = profile.WithEquipment(equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 2
+	}
+	provider, err := airportProvider(*nasrDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 1
+	}
+	airport, err := airports.Resolve(context.Background(), provider, *airportID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 1
+	}
+	rwy, end, err := airport.Runway(*runwayID)
+	if err == nil && end == nil {
+		err = fmt.Errorf("specify a single runway end (e.g. 17), not %s", *runwayID)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 2
+	}
+
+	takeoff := profile.NewTakeoffCalculator()
+	if *weight == 0 {
+		for _, limit := range takeoff.Envelope() {
+			if limit.Field == performance.FieldWeight {
+				*weight = limit.Max
+			}
+		}
+	}
+	headwind := wind.Decompose(wind.Wind{From: wind.TrueDirection(*windDir), Speed: *windSpeed}, wind.TrueDirection(end.TrueHeading), 0).Headwind
+	params := performance.TakeoffParams{
+		PressureAltitude: atmosphere.PressureAltitude(airport.Elevation, *altimeter),
+		Temperature:      *temp,
+		Weight:           *weight,
+		WindComponent:    headwind,
+	}
+	result, err := takeoff.CalculateTakeoff(params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 1
+	}
+	path, err := climbPath(airport, rwy, end, profile.NewClimbCalculator(), params, result.TakeoffDistance, *height)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 1
+	}
+
+	features := corridorFeatures(airport, rwy, end, path)
+	features[1].Properties = map[string]interface{}{
+		"takeoff_distance_ft": math.Round(result.TakeoffDistance),
+		"headwind_kt":         math.Round(headwind),
+		"height_ft":           *height,
+		"distance_nm":         math.Round(path.Distance*100) / 100,
+		"gradient_ft_per_nm":  math.Round(path.Gradient),
+	}
+
+	var out io.Writer = os.Stdout
+	if *format == "geojson" {
+		err = geo.WriteGeoJSON(out, features)
+	} else {
+		err = geo.WriteKML(out, fmt.Sprintf("%s runway %s departure", airport.Ident, end.ID), features)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto corridor: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Takeoff distance %.0f ft of %.0f ft; %.0f ft above the field %.1f nm past the departure end\n",
+		result.TakeoffDistance, rwy.Length, *height, path.Distance)
+	fmt.Fprintf(os.Stderr, "Climb gradient %.0f ft/nm (%.0f ft/nm standard)\n", path.Gradient, standardGradient)
+	if result.TakeoffDistance > rwy.Length {
+		fmt.Fprintf(os.Stderr, "WARNING: 50 ft is not reached before the departure end of the runway\n")
+	}
+	if path.Gradient < standardGradient {
+		fmt.Fprintf(os.Stderr, "WARNING: the climb gradient is below the %.0f ft/nm standard\n", standardGradient)
+	}
+	return 0
+}
+
+// climbPath follows the departure from brake release at the threshold: 50
+// ft at the takeoff distance, then the climb table to the height, with the
+// headwind taken off the still-air distance
+func climbPath(airport *airports.Airport, rwy *airports.Runway, end *airports.RunwayEnd, climb *performance.ClimbCalculator,
+	params performance.TakeoffParams, takeoffDistance, height float64) (*climbOut, error) {
+	table, err := climb.CalculateClimb(performance.ClimbParams{
+		PressureAltitude: params.PressureAltitude + 50,
+		Temperature:      params.Temperature,
+		CruiseAltitude:   params.PressureAltitude + height,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := runwayThreshold(airport, rwy, end)
+	barrier := takeoffDistance / feetPerNauticalMile
+	path := &climbOut{
+		Track:     []geo.Point{threshold, geo.Destination(threshold, end.TrueHeading, barrier)},
+		Altitudes: []float64{airport.Elevation, airport.Elevation + 50},
+	}
+	ground := barrier
+	for _, row := range table.Rows[1:] {
+		ground = barrier + row.Distance - params.WindComponent*row.Time/60
+		path.Track = append(path.Track, geo.Destination(threshold, end.TrueHeading, ground))
+		path.Altitudes = append(path.Altitudes, airport.Elevation+row.PressureAltitude-params.PressureAltitude)
+	}
+	if ground <= barrier {
+		return nil, fmt.Errorf("no climb over the ground into a %.0f kt headwind", params.WindComponent)
+	}
+	path.Distance = ground - rwy.Length/feetPerNauticalMile
+	path.Gradient = (height - 50) / (ground - barrier)
+	return path, nil
+}
+
+// runwayThreshold places the start of a runway end on the extended
+// centreline through the airport reference point; the data has no
+// threshold positions
+func runwayThreshold(airport *airports.Airport, rwy *airports.Runway, end *airports.RunwayEnd) geo.Point {
+	return geo.Destination(airport.Position(), end.TrueHeading+180, rwy.Length/2/feetPerNauticalMile)
+}
+
+// corridorFeatures returns the runway, the climb path and the departure
+// corridor as map features
+func corridorFeatures(airport *airports.Airport, rwy *airports.Runway, end *airports.RunwayEnd, path *climbOut) []geo.Feature {
+	threshold := path.Track[0]
+	der := geo.Destination(threshold, end.TrueHeading, rwy.Length/feetPerNauticalMile)
+
+	length := math.Max(path.Distance, corridorMinLength)
+	near := corridorHalfWidth / feetPerNauticalMile
+	far := near + length*math.Tan(corridorSplay*math.Pi/180)
+	outer := geo.Destination(der, end.TrueHeading, length)
+	corridor := []geo.Point{
+		geo.Destination(der, end.TrueHeading-90, near),
+		geo.Destination(outer, end.TrueHeading-90, far),
+		geo.Destination(outer, end.TrueHeading+90, far),
+		geo.Destination(der, end.TrueHeading+90, near),
+	}
+
+	return []geo.Feature{
+		{Name: "Runway " + end.ID, Line: []geo.Point{threshold, der}},
+		{Name: "Climb-out", Line: path.Track, Altitudes: path.Altitudes},
+		{Name: "Departure corridor", Area: corridor},
+	}
+}
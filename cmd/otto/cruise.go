@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// runCruise finds the power setting for a cruise altitude, from a percent
+// power, a target true airspeed or a target fuel flow
+func runCruise(args []string) int {
+	fs := flag.NewFlagSet("cruise", flag.ContinueOnError)
+	pressureAlt := fs.Float64("altitude", 0, "Cruise pressure altitude in feet")
+	tempC := fs.Float64("temp-c", 0, "Temperature at cruise altitude in °C (default standard temperature)")
+	tempF := fs.Float64("temp-f", 0, "Temperature at cruise altitude in °F (overrides temp-c if provided)")
+	power := fs.Float64("power", 0, "Percent power")
+	tas := fs.Float64("tas", 0, "Target true airspeed in knots (instead of -power)")
+	fuelFlow := fs.Float64("fuel-flow", 0, "Target fuel flow in gph (instead of -power)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft (see the aircraft profile)")
+	var equipment stringList
+	fs.Var(&equipment, "equipment", "Comma-separated optional equipment installed, e.g. no-fairings")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto cruise -altitude 6500 (-power 65 | -tas 110 | -fuel-flow 8) [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Shows the RPM, true airspeed and leaned fuel flow for the power setting.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	params := performance.CruiseParams{
+		PressureAltitude: *pressureAlt,
+		Temperature:      atmosphere.ISATemperature(*pressureAlt),
+		Power:            *power,
+	}
+	targets := 0
+	tempCProvided, tempFProvided := false, false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "temp-c":
+			tempCProvided = true
+		case "temp-f":
+			tempFProvided = true
+		case "power", "tas", "fuel-flow":
+			targets++
+		}
+	})
+	if tempFProvided {
+		params.Temperature = performance.ConvertFahrenheitToCelsius(*tempF)
+	} else if tempCProvided {
+		params.Temperature = *tempC
+	}
+	if targets != 1 {
+		fmt.Fprintf(os.Stderr, "otto cruise: give exactly one of -power, -tas or -fuel-flow\n")
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err == nil {
+		profile, err = profile.WithEquipment(equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto cruise: %v\n", err)
+		return 2
+	}
+	calculator := profile.NewCruiseCalculator()
+
+	var result *performance.CruiseResult
+	switch {
+	case *tas > 0:
+		result, err = calculator.PowerForTrueAirspeed(params, *tas)
+	case *fuelFlow > 0:
+		result, err = calculator.PowerForFuelFlow(params, *fuelFlow)
+	default:
+		result, err = calculator.CalculateCruise(params)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto cruise: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("\n%s Cruise at %.0f ft, %.0f°C (density altitude %.0f ft)\n\n",
+		profile.Name, params.PressureAltitude, params.Temperature, result.DensityAltitude)
+	printColumns([][]string{
+		{"Power", fmt.Sprintf("%.0f%%", result.Power)},
+		{"RPM", fmt.Sprintf("%.0f", result.RPM)},
+		{"True Airspeed", fmt.Sprintf("%.0f kt", result.TrueAirspeed)},
+		{"Fuel Flow", fmt.Sprintf("%.1f gph", result.FuelFlow)},
+	})
+	for _, e := range profile.Installed {
+		fmt.Printf("Equipment: %s\n", e)
+	}
+	return 0
+}
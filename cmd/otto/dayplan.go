@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// runDayplan computes takeoff and landing performance for several candidate
+// departure times and prints them side by side, answering "when today can
+// we do this". The temperatures and winds come from the airport's TAF at
+// each time, or are entered per time.
+func runDayplan(args []string) int {
+	fs := flag.NewFlagSet("dayplan", flag.ContinueOnError)
+	var times stringList
+	var tempsC, tempsF, winds floatList
+	fs.Var(&times, "times", "Candidate departure times, local hours at the airport, comma separated (e.g. 08,10,12,14:30)")
+	airportID := fs.String("airport", "", "Departure airport, for its TAF, local time, elevation and runway")
+	runwayEnd := fs.String("runway-end", "", "Runway end at -airport for the TAF wind components and the runway length, e.g. 17")
+	date := fs.String("date", "", "Day of the times, YYYY-MM-DD (default: today at the airport)")
+	taf := fs.String("taf", "", "Raw TAF to use instead of fetching the airport's latest")
+	fs.Var(&tempsC, "temps-c", "Temperature in °C for each time, comma separated, in place of the TAF's")
+	fs.Var(&tempsF, "temps-f", "Temperature in °F for each time (overrides temps-c)")
+	fs.Var(&winds, "winds", "Wind component in knots for each time (positive for headwind), in place of the TAF's; without an airport, default calm")
+	pressureAlt := fs.Float64("altitude", 0, "Pressure altitude in feet (default: the airport's elevation, corrected by a TAF QNH)")
+	weight := fs.Float64("weight", 2325, "Takeoff weight in pounds")
+	landingWeight := fs.Float64("landing-weight", 0, "Landing weight in pounds (default: the takeoff weight)")
+	runway := fs.Float64("runway", 0, "Available takeoff and landing distance in feet (default: the -runway-end length), adds margin rows")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch the TAF from the provider")
+	sources := addSourceFlags(fs)
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto dayplan -times 08,10,12,14 -airport KJYO -runway-end 17 [options]\n")
+		fmt.Fprintf(os.Stderr, "       otto dayplan -times 08,10,12,14 -temps-c 12,18,24,27 [-winds 5,8,10,10] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "With -airport the winds (and a QNH) are read from its TAF at each time, with the\n")
+		fmt.Fprintf(os.Stderr, "prevailing conditions, and the temperatures from its TX and TN groups. Most US TAFs\n")
+		fmt.Fprintf(os.Stderr, "have no TX/TN groups; give -temps-c or -temps-f for them. The landing rows are for\n")
+		fmt.Fprintf(os.Stderr, "a return to the same runway in the same conditions. Times the TAF doesn't reach are\n")
+		fmt.Fprintf(os.Stderr, "left blank unless -temps-c/-temps-f and -winds give their conditions.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+		return 2
+	}
+	altitudeSet := false
+	fs.Visit(func(f *flag.Flag) {
+		altitudeSet = altitudeSet || f.Name == "altitude"
+	})
+
+	temps := []float64(tempsC)
+	if len(tempsF) > 0 {
+		temps = make([]float64, len(tempsF))
+		for i, f := range tempsF {
+			temps[i] = performance.ConvertFahrenheitToCelsius(f)
+		}
+	}
+	if len(times) == 0 {
+		fmt.Fprintf(os.Stderr, "otto dayplan: -times is required\n")
+		return 2
+	}
+	if len(temps) != 0 && len(temps) != len(times) {
+		fmt.Fprintf(os.Stderr, "otto dayplan: -temps-c/-temps-f must list one value per time\n")
+		return 2
+	}
+	if len(winds) != 0 && len(winds) != len(times) {
+		fmt.Fprintf(os.Stderr, "otto dayplan: -winds must list one value per time\n")
+		return 2
+	}
+	if *airportID == "" && len(temps) == 0 {
+		fmt.Fprintf(os.Stderr, "otto dayplan: give -airport for the TAF, or -temps-c/-temps-f\n")
+		return 2
+	}
+	if *landingWeight <= 0 {
+		*landingWeight = *weight
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+		return 2
+	}
+
+	// The airport gives the local zone, elevation, runway and TAF
+	loc := time.UTC
+	var airport *airports.Airport
+	var end *airports.RunwayEnd
+	var forecast weather.Forecast
+	if *airportID != "" {
+		provider, err := sources.airportProvider(*nasrDir, *netConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+			return 1
+		}
+		if airport, err = airports.Resolve(context.Background(), provider, *airportID); err != nil {
+			fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+			return 1
+		}
+		if loc, err = airport.Location(); err != nil {
+			fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+			return 1
+		}
+		if *runwayEnd != "" {
+			var rwy *airports.Runway
+			if rwy, end, err = airport.Runway(*runwayEnd); err != nil {
+				fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+				return 1
+			}
+			if end == nil {
+				fmt.Fprintf(os.Stderr, "otto dayplan: specify a single runway end (e.g. 17), not %s\n", *runwayEnd)
+				return 2
+			}
+			if *runway <= 0 {
+				*runway = rwy.Length
+			}
+		}
+		if end == nil && len(winds) == 0 {
+			fmt.Fprintf(os.Stderr, "otto dayplan: give -runway-end for the TAF wind components, or -winds\n")
+			return 2
+		}
+		if !altitudeSet {
+			*pressureAlt = airport.Elevation
+		}
+
+		raw := *taf
+		if raw == "" {
+			station := airport.ICAO
+			if station == "" {
+				station = airport.Ident
+			}
+			fetcher, err := sources.weatherFetcher("dayplan", *netConfig, "", weather.DefaultTTL, *noCache)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+				return 1
+			}
+			report, err := fetcher.Fetch(context.Background(), weather.TAF, station)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+				return 1
+			}
+			raw = report.Raw
+		}
+		decoded, err := weather.ParseTAF(raw, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+			return 1
+		}
+		if len(temps) == 0 && len(decoded.Temperatures) == 0 {
+			fmt.Fprintf(os.Stderr, "otto dayplan: the TAF for %s has no TX/TN temperatures; give -temps-c or -temps-f\n", decoded.Station)
+			return 2
+		}
+		forecast = decoded.Forecast(tafStep, 0)
+	} else if *runwayEnd != "" {
+		fmt.Fprintf(os.Stderr, "otto dayplan: -runway-end needs -airport\n")
+		return 2
+	}
+
+	day := time.Now().In(loc)
+	if *date != "" {
+		if day, err = time.ParseInLocation("2006-01-02", *date, loc); err != nil {
+			fmt.Fprintf(os.Stderr, "otto dayplan: invalid -date %q\n", *date)
+			return 2
+		}
+	}
+	departures := make([]time.Time, len(times))
+	for i, t := range times {
+		if departures[i], err = dayTime(day, t); err != nil {
+			fmt.Fprintf(os.Stderr, "otto dayplan: %v\n", err)
+			return 2
+		}
+	}
+
+	takeoffCalc := profile.NewTakeoffCalculator()
+	var landingCalc *performance.LandingCalculator
+	if profile.NewLandingCalculator != nil {
+		landingCalc = profile.NewLandingCalculator()
+	}
+	rows := [][]string{
+		{"Temperature"},
+		{"Wind"},
+		{"Pressure Altitude"},
+		{"Takeoff Distance"},
+		{"Lift-off Speed"},
+		{"50 ft Speed"},
+	}
+	marginRow, landingRow := -1, -1
+	if *runway > 0 {
+		marginRow = len(rows)
+		rows = append(rows, []string{"Runway Margin"})
+	}
+	if landingCalc != nil {
+		landingRow = len(rows)
+		rows = append(rows, []string{"Landing Distance"}, []string{"Landing Ground Roll"})
+		if *runway > 0 {
+			rows = append(rows, []string{"Landing Margin"})
+		}
+	}
+
+	var heading float64
+	if end != nil {
+		heading = end.TrueHeading
+	}
+	for i, departure := range departures {
+		params := performance.TakeoffParams{
+			PressureAltitude: *pressureAlt,
+			Weight:           *weight,
+		}
+		point, ok := weather.ForecastPoint{}, false
+		if forecast != nil {
+			point, ok = nearestForecast(forecast, departure)
+		}
+		var temp, headwind *float64
+		if len(temps) > 0 {
+			temp = &temps[i]
+		}
+		if len(winds) > 0 {
+			headwind = &winds[i]
+		}
+		var missing string
+		params.Temperature, params.WindComponent, missing = slotConditions(point, ok || forecast == nil, temp, headwind, heading)
+		if missing != "" {
+			for r := range rows {
+				rows[r] = append(rows[r], "--")
+			}
+			fmt.Fprintf(os.Stderr, "%s: no TAF for the time; give %s\n", times[i], missing)
+			continue
+		}
+		if ok && point.Altimeter > 0 && !altitudeSet {
+			params.PressureAltitude = atmosphere.PressureAltitude(airport.Elevation, point.Altimeter)
+		}
+
+		rows[0] = append(rows[0], fmt.Sprintf("%.0f°C", params.Temperature))
+		rows[1] = append(rows[1], formatWind(params.WindComponent))
+		rows[2] = append(rows[2], fmt.Sprintf("%.0f ft", params.PressureAltitude))
+
+		if result, err := takeoffCalc.CalculateTakeoff(params); err != nil {
+			rows[3] = append(rows[3], "n/a")
+			rows[4] = append(rows[4], "n/a")
+			rows[5] = append(rows[5], "n/a")
+			if marginRow >= 0 {
+				rows[marginRow] = append(rows[marginRow], "n/a")
+			}
+			fmt.Fprintf(os.Stderr, "%s: takeoff: %v\n", times[i], err)
+		} else {
+			rows[3] = append(rows[3], fmt.Sprintf("%.0f ft", result.TakeoffDistance))
+			rows[4] = append(rows[4], fmt.Sprintf("%.0f KIAS", result.LiftoffSpeed))
+			rows[5] = append(rows[5], fmt.Sprintf("%.0f KIAS", result.BarrierSpeed))
+			if marginRow >= 0 {
+				rows[marginRow] = append(rows[marginRow], fmt.Sprintf("%+.0f ft", *runway-result.TakeoffDistance))
+			}
+		}
+
+		if landingRow < 0 {
+			continue
+		}
+		landing, err := landingCalc.CalculateLanding(performance.LandingParams{
+			PressureAltitude: params.PressureAltitude,
+			Temperature:      params.Temperature,
+			Weight:           *landingWeight,
+			WindComponent:    params.WindComponent,
+		})
+		if err != nil {
+			for r := landingRow; r < len(rows); r++ {
+				rows[r] = append(rows[r], "n/a")
+			}
+			fmt.Fprintf(os.Stderr, "%s: landing: %v\n", times[i], err)
+			continue
+		}
+		rows[landingRow] = append(rows[landingRow], fmt.Sprintf("%.0f ft", landing.LandingDistance))
+		rows[landingRow+1] = append(rows[landingRow+1], fmt.Sprintf("%.0f ft", landing.GroundRoll))
+		if *runway > 0 {
+			rows[landingRow+2] = append(rows[landingRow+2], fmt.Sprintf("%+.0f ft", *runway-landing.LandingDistance))
+		}
+	}
+
+	where := ""
+	if airport != nil {
+		where = airport.Ident
+		if end != nil {
+			where += " runway " + end.ID
+		}
+		where += ", " + day.Format("Mon 02 Jan") + " " + day.Format("MST") + ", "
+	}
+	fmt.Printf("\n%s Day Plan (%s%.0f lbs takeoff, %.0f lbs landing)\n\n", profile.Name, where, *weight, *landingWeight)
+	printColumns(append([][]string{append([]string{"Departure"}, times...)}, rows...))
+	return 0
+}
+
+// slotConditions returns the temperature and headwind component for a
+// departure: the values given with -temps-c/-temps-f and -winds where there
+// are any, otherwise the TAF point's. covered is false when the TAF doesn't
+// reach the departure; a value it would have given then has to be entered,
+// and missing names the flag for it. Without a TAF the wind defaults to calm.
+func slotConditions(point weather.ForecastPoint, covered bool, temp, headwind *float64, runwayHeading float64) (temperature, component float64, missing string) {
+	switch {
+	case temp != nil:
+		temperature = *temp
+	case covered:
+		temperature = point.Temperature
+	default:
+		return 0, 0, "-temps-c or -temps-f"
+	}
+	switch {
+	case headwind != nil:
+		component = *headwind
+	case !covered:
+		return 0, 0, "-winds"
+	case point.Variable:
+		// No headwind to count on from a variable wind
+	default:
+		component = wind.Decompose(point.Wind, wind.TrueDirection(runwayHeading), 0).Headwind
+	}
+	return temperature, component, ""
+}
+
+// dayTime reads a local hour such as "08" or "14:30" on a day
+func dayTime(day time.Time, value string) (time.Time, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		if t, err = time.Parse("15", value); err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q (expected an hour such as 08 or 14:30)", value)
+		}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+// formatWind describes a wind component compactly
+func formatWind(wind float64) string {
+	switch {
+	case wind > 0:
+		return fmt.Sprintf("%.0f kt HW", wind)
+	case wind < 0:
+		return fmt.Sprintf("%.0f kt TW", -wind)
+	default:
+		return "calm"
+	}
+}
+
+// printColumns prints rows of cells with every column padded to its widest cell
+func printColumns(rows [][]string) {
+	fprintColumns(os.Stdout, rows)
+}
+
+// fprintColumns prints columns as printColumns does, to w
+func fprintColumns(w io.Writer, rows [][]string) {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			pad := widths[i] - len([]rune(cell))
+			if i == 0 {
+				b.WriteString(cell + strings.Repeat(" ", pad))
+			} else {
+				b.WriteString("  " + strings.Repeat(" ", pad) + cell)
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+func TestSlotConditions(t *testing.T) {
+	point := weather.ForecastPoint{Temperature: 21, Wind: wind.Wind{From: wind.TrueDirection(170), Speed: 10}}
+	temp, headwind := 27.0, -4.0
+
+	tests := []struct {
+		name          string
+		point         weather.ForecastPoint
+		covered       bool
+		temp, wind    *float64
+		wantTemp      float64
+		wantComponent float64
+		wantMissing   string
+	}{
+		{"TAF", point, true, nil, nil, 21, 10, ""},
+		{"Overrides in the TAF", point, true, &temp, &headwind, 27, -4, ""},
+		{"Variable", weather.ForecastPoint{Temperature: 21, Wind: wind.Wind{Speed: 5}, Variable: true}, true, nil, nil, 21, 0, ""},
+		{"Overrides outside the TAF", weather.ForecastPoint{}, false, &temp, &headwind, 27, -4, ""},
+		{"Temperature outside the TAF", weather.ForecastPoint{}, false, &temp, nil, 0, 0, "-winds"},
+		{"Wind outside the TAF", weather.ForecastPoint{}, false, nil, &headwind, 0, 0, "-temps-c or -temps-f"},
+		{"Nothing outside the TAF", weather.ForecastPoint{}, false, nil, nil, 0, 0, "-temps-c or -temps-f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temperature, component, missing := slotConditions(tt.point, tt.covered, tt.temp, tt.wind, 170)
+			if temperature != tt.wantTemp || component != tt.wantComponent || missing != tt.wantMissing {
+				t.Errorf("slotConditions = %v, %v, %q, want %v, %v, %q",
+					temperature, component, missing, tt.wantTemp, tt.wantComponent, tt.wantMissing)
+			}
+		})
+	}
+}
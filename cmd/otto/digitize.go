@@ -0,0 +1,167 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/digitize"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// runDigitize assembles a takeoff chart bundle on a regular grid from
+// points read off a POH figure, and reports how far the chart is from
+// each reading
+func runDigitize(args []string) int {
+	fs := flag.NewFlagSet("digitize", flag.ContinueOnError)
+	pointsFile := fs.String("points", "", "CSV of zero-wind points read off the chart: pressure_altitude, temperature_c or temperature_f, weight, distance")
+	outFile := fs.String("o", "", "Write the chart to this file (default: stdout)")
+	var altitudes, temperatures, weights, liftoff, barrier floatList
+	fs.Var(&altitudes, "altitudes", "Pressure altitude lines of the chart, comma separated (default: the altitudes read)")
+	fs.Var(&temperatures, "temperatures", "Temperature lines in °C, comma separated (default: the temperatures read)")
+	fs.Var(&weights, "weights", "Weight lines, comma separated (default: the weights read)")
+	fs.Var(&liftoff, "liftoff-speeds", "Lift-off speed in knots at each weight line, comma separated")
+	fs.Var(&barrier, "barrier-speeds", "50 ft speed in knots at each weight line, comma separated")
+	from := fs.String("from", "", "Copy the wind lines and speeds from this aircraft's chart, e.g. pa28-161")
+	altitudeUnit := fs.String("altitude-unit", "ft", "Unit of the altitudes: ft or m")
+	weightUnit := fs.String("weight-unit", "lbs", "Unit of the weights: lbs or kg")
+	distanceUnit := fs.String("distance-unit", "ft", "Unit of the distances: ft or m")
+	var source performance.Source
+	fs.StringVar(&source.Aircraft, "aircraft", "", "Aircraft of the chart, for its source")
+	fs.StringVar(&source.Document, "document", "", "Document the chart was read from, e.g. \"PA-28-161 POH\"")
+	fs.StringVar(&source.Figure, "figure", "", "Figure number, e.g. 5-7")
+	fs.StringVar(&source.Title, "title", "", "Figure title")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto digitize -points FILE [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Points may be read anywhere on the chart, not only where its lines cross.\n")
+		fmt.Fprintf(os.Stderr, "The logarithm of the distance is fitted as a quadratic in altitude,\n")
+		fmt.Fprintf(os.Stderr, "temperature and weight (linear below 15 points), corrected onto the\n")
+		fmt.Fprintf(os.Stderr, "readings near each grid node. The residual of each point through the\n")
+		fmt.Fprintf(os.Stderr, "assembled chart is reported on stderr.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *pointsFile == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	if *from != "" && (len(liftoff) > 0 || len(barrier) > 0) {
+		fmt.Fprintf(os.Stderr, "otto digitize: -from and -liftoff-speeds or -barrier-speeds are exclusive\n")
+		return 2
+	}
+
+	f, err := os.Open(*pointsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto digitize: %v\n", err)
+		return 2
+	}
+	points, err := digitize.ReadCSV(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto digitize: %s: %v\n", *pointsFile, err)
+		return 2
+	}
+
+	grid := digitize.GridOf(points)
+	for _, axis := range []struct {
+		flag   []float64
+		values *[]float64
+	}{{altitudes, &grid.Altitudes}, {temperatures, &grid.Temperatures}, {weights, &grid.Weights}} {
+		if len(axis.flag) > 0 {
+			*axis.values = axis.flag
+		}
+	}
+
+	template := &performance.TakeoffChart{
+		Source:          source,
+		Units:           performance.ChartUnits{Altitude: *altitudeUnit, Weight: *weightUnit, Distance: *distanceUnit, Speed: "kts", Wind: "kts"},
+		Headwinds:       []float64{0},
+		HeadwindFactors: []float64{1},
+		Tailwinds:       []float64{0},
+		TailwindFactors: []float64{1},
+		Weights:         grid.Weights,
+		LiftoffSpeeds:   liftoff,
+		BarrierSpeeds:   barrier,
+	}
+	for _, speeds := range []struct {
+		name   string
+		values floatList
+	}{{"-liftoff-speeds", liftoff}, {"-barrier-speeds", barrier}} {
+		if len(speeds.values) > 0 && len(speeds.values) != len(grid.Weights) {
+			fmt.Fprintf(os.Stderr, "otto digitize: %s has %d speeds for %d weight lines\n", speeds.name, len(speeds.values), len(grid.Weights))
+			return 2
+		}
+	}
+	if *from != "" {
+		profile, err := aircraft.Lookup(*from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto digitize: %v\n", err)
+			return 2
+		}
+		c := profile.NewTakeoffCalculator().Chart()
+		template.Headwinds, template.HeadwindFactors = c.Headwinds, c.HeadwindFactors
+		template.Tailwinds, template.TailwindFactors = c.Tailwinds, c.TailwindFactors
+		template.Weights, template.LiftoffSpeeds, template.BarrierSpeeds = c.Weights, c.LiftoffSpeeds, c.BarrierSpeeds
+		if *weightUnit == "kg" {
+			template.Weights = nil
+			for _, w := range c.Weights {
+				template.Weights = append(template.Weights, units.PoundsToKilograms(w))
+			}
+		}
+	}
+
+	fit, err := digitize.NewFit(points, grid)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto digitize: %v\n", err)
+		return 2
+	}
+	chart := fit.Chart(template)
+	residuals, err := digitize.Residuals(chart, points)
+	if err != nil {
+		printErrorLines("digitize", err)
+		return 1
+	}
+
+	unit := *distanceUnit
+	rows := [][]string{{"Line", "Altitude", "Temp", "Weight", "Read", "Chart", "Residual"}}
+	var sumSquares, worst float64
+	for _, r := range residuals {
+		rows = append(rows, []string{
+			fmt.Sprint(r.Point.Line),
+			fmt.Sprintf("%g %s", r.Point.PressureAltitude, *altitudeUnit),
+			fmt.Sprintf("%g°C", r.Point.Temperature),
+			fmt.Sprintf("%g %s", r.Point.Weight, *weightUnit),
+			fmt.Sprintf("%.0f %s", r.Point.Distance, unit),
+			fmt.Sprintf("%.0f %s", r.Chart, unit),
+			fmt.Sprintf("%+.0f %s", r.Residual, unit),
+		})
+		sumSquares += r.Residual * r.Residual
+		worst = math.Max(worst, math.Abs(r.Residual))
+	}
+	fprintColumns(os.Stderr, rows)
+	fmt.Fprintf(os.Stderr, "%d points, %d-term fit, RMS residual %.0f %s, worst %.0f %s\n",
+		len(points), fit.Terms, math.Sqrt(sumSquares/float64(len(residuals))), unit, worst, unit)
+	for _, problem := range digitize.Monotonicity(chart) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", problem)
+	}
+	if *from == "" && len(liftoff) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: no wind lines or speeds; fill them in or use -from\n")
+	}
+
+	if err := writeChart(*outFile, chart); err != nil {
+		fmt.Fprintf(os.Stderr, "otto digitize: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// floatList is a flag.Value holding a comma-separated list of numbers
+type floatList []float64
+
+// String implements flag.Value
+func (l *floatList) String() string {
+	parts := make([]string, len(*l))
+	for i, v := range *l {
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value
+func (l *floatList) Set(value string) error {
+	*l = nil
+	for _, part := range strings.Split(value, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", part)
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// weightList is a flag.Value holding a comma-separated list of weights in
+// pounds, each optionally suffixed with its unit, e.g. 77kg,160
+type weightList []float64
+
+// String implements flag.Value
+func (l *weightList) String() string {
+	return (*floatList)(l).String()
+}
+
+// Set implements flag.Value
+func (l *weightList) Set(value string) error {
+	*l = nil
+	for _, part := range strings.Split(value, ",") {
+		v, err := units.ParseWeight(part)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// weightValue is a flag.Value holding a weight in pounds, optionally
+// suffixed with its unit, e.g. 20kg
+type weightValue float64
+
+// String implements flag.Value
+func (w *weightValue) String() string {
+	return strconv.FormatFloat(float64(*w), 'f', -1, 64)
+}
+
+// Set implements flag.Value
+func (w *weightValue) Set(value string) error {
+	v, err := units.ParseWeight(value)
+	if err != nil {
+		return err
+	}
+	*w = weightValue(v)
+	return nil
+}
+
+// stringList is a flag.Value holding a comma-separated list of strings
+type stringList []string
+
+// String implements flag.Value
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements flag.Value
+func (l *stringList) Set(value string) error {
+	*l = nil
+	for _, part := range strings.Split(value, ",") {
+		*l = append(*l, strings.TrimSpace(part))
+	}
+	return nil
+}
+
+// rangeList is a flag.Value holding the values of a sweep axis, given as
+// START:END:STEP or as a comma-separated list
+type rangeList []float64
+
+// String implements flag.Value
+func (l *rangeList) String() string {
+	return (*floatList)(l).String()
+}
+
+// Set implements flag.Value
+func (l *rangeList) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) == 1 {
+		return (*floatList)(l).Set(value)
+	}
+	if len(parts) != 3 {
+		return fmt.Errorf("expected START:END:STEP, got %q", value)
+	}
+
+	var bounds [3]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", part)
+		}
+		bounds[i] = v
+	}
+	start, end, step := bounds[0], bounds[1], bounds[2]
+	if step <= 0 || end < start {
+		return fmt.Errorf("range %q needs END at least START and a positive STEP", value)
+	}
+
+	// Multiply rather than accumulate so the steps do not drift
+	*l = nil
+	for i := 0; ; i++ {
+		v := start + float64(i)*step
+		if v > end+step*1e-9 {
+			break
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// fleetSummary is today's dispatch summary for one airframe
+type fleetSummary struct {
+	Tail        aircraft.Tail
+	Available   float64 // Factored takeoff distance available in feet
+	MaxWeight   *performance.ReverseResult
+	Dispatch    *wb.Summary // Loading with dispatch fuel and no payload
+	MaxPayload  float64     // People and baggage in pounds
+	Crosswind   float64     // Crosswind at the gust speed, in knots
+	CrosswindOK bool
+	Landing     *performance.LandingResult // At the landing chart's maximum weight, nil without a chart for the conditions
+	Err         error                      // Set when no takeoff weight can be found for the runway
+}
+
+// fleetBoard is the dispatch summary of a whole fleet for one METAR at the
+// home field
+type fleetBoard struct {
+	Airport     *airports.Airport
+	Runway      *airports.Runway
+	End         *airports.RunwayEnd
+	Observation *weather.Observation
+	Components  wind.Components
+	Params      performance.TakeoffParams
+	Factor      float64
+	Summaries   []*fleetSummary
+}
+
+// runFleet prints a dispatch summary per aircraft of a fleet for today's
+// METAR at the home field: the maximum payload for the runway and the
+// crosswind status
+func runFleet(args []string) int {
+	fs := flag.NewFlagSet("fleet", flag.ContinueOnError)
+	fleetFile := fs.String("fleet", "", "Fleet CSV: tail, aircraft[, empty_weight, fuel_gal]")
+	airportID := fs.String("airport", "", "Home airport identifier")
+	runwayID := fs.String("runway", "", "Departure runway end (default: the end with the most headwind)")
+	metar := fs.String("metar", "", "Raw METAR to use instead of fetching the latest")
+	factor := fs.Float64("factor", 1.0, "Safety factor applied to the takeoff distance, e.g. 1.5")
+	csvOutput := fs.Bool("csv", false, "Print one CSV row per aircraft instead of pages")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch the METAR from the provider")
+	sources := addSourceFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto fleet -fleet fleet.csv -airport KJYO [options]\n\n")
+		fmt.Fprintf(os.Stderr, "The maximum payload is the people and baggage that fit under the runway-limited\n")
+		fmt.Fprintf(os.Stderr, "takeoff weight with each airframe's dispatch fuel.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 2
+	}
+	if *fleetFile == "" || *airportID == "" {
+		fmt.Fprintf(os.Stderr, "otto fleet: -fleet and -airport are required\n")
+		return 2
+	}
+	if *factor < 1 {
+		fmt.Fprintf(os.Stderr, "otto fleet: -factor must be at least 1\n")
+		return 2
+	}
+
+	f, err := os.Open(*fleetFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 2
+	}
+	fleet, err := aircraft.ReadFleetCSV(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %s: %v\n", *fleetFile, err)
+		return 2
+	}
+
+	provider, err := sources.airportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 1
+	}
+	airport, err := airports.Resolve(context.Background(), provider, *airportID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 1
+	}
+
+	raw := *metar
+	if raw == "" {
+		fetcher, err := sources.weatherFetcher("fleet", *netConfig, "", weather.DefaultTTL, *noCache)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+			return 1
+		}
+		report, err := fetcher.Fetch(context.Background(), weather.METAR, metarStation(airport))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+			return 1
+		}
+		raw = report.Raw
+	}
+	obs, err := weather.ParseMETAR(raw, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 1
+	}
+	if !obs.HasTemperature {
+		fmt.Fprintf(os.Stderr, "otto fleet: METAR %s has no temperature\n", obs.Station)
+		return 1
+	}
+
+	board, err := summarizeFleet(fleet, airport, *runwayID, obs, *factor)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 2
+	}
+	summaries, end, params, components := board.Summaries, board.End, board.Params, board.Components
+
+	if *csvOutput {
+		return printFleetCSV(summaries, airport, end, params)
+	}
+
+	for i, s := range summaries {
+		if i > 0 {
+			fmt.Print("\f")
+		}
+		title := fmt.Sprintf("%s  %s", s.Tail.Registration, s.Tail.Profile.Name)
+		fmt.Printf("%s\n%s\n\n", title, strings.Repeat("=", len(title)))
+		fmt.Printf("METAR:        %s\n", strings.TrimSpace(raw))
+		fmt.Printf("Runway:       %s %s, %.0f ft (%.0f ft with factor %s)\n",
+			airport.Ident, end.ID, board.Runway.Length, s.Available, strconv.FormatFloat(*factor, 'f', -1, 64))
+		fmt.Printf("Conditions:   %.0f ft PA, %.0f°C, DA %.0f ft\n",
+			params.PressureAltitude, params.Temperature, atmosphere.DensityAltitude(params.PressureAltitude, params.Temperature))
+		fmt.Printf("Wind:         %s\n", formatWind(params.WindComponent))
+
+		status := "OK"
+		if !s.CrosswindOK {
+			status = "EXCEEDS"
+		}
+		fmt.Printf("Crosswind:    %.0f kt from the %s, %s (max demonstrated %.0f kt)\n",
+			s.Crosswind, components.CrosswindSide(), status, s.Tail.Profile.Limits.MaxDemonstratedCrosswind)
+
+		fmt.Printf("Fuel:         %.1f gal, %.0f lbs takeoff weight without payload\n", s.Tail.FuelGallons, dispatchWeight(s))
+		if s.Err != nil {
+			fmt.Printf("Max payload:  NOT AVAILABLE: %v\n", s.Err)
+			continue
+		}
+		limit := "runway"
+		if s.MaxWeight.Limit == performance.LimitChart {
+			limit = "maximum weight"
+		}
+		fmt.Printf("Max weight:   %.0f lbs (%s limited), %.0f ft over 50 ft\n", s.MaxWeight.Value, limit, s.MaxWeight.TakeoffDistance)
+		if s.MaxPayload < 0 {
+			fmt.Printf("Max payload:  NONE: reduce fuel by %.1f gal\n", -s.MaxPayload/s.Tail.WeightBalance.FuelDensity)
+		} else {
+			fmt.Printf("Max payload:  %.0f lbs people and baggage\n", s.MaxPayload)
+		}
+	}
+	return 0
+}
+
+// summarizeFleet works out the conditions along the home runway for an
+// observation and each airframe's summary under them
+func summarizeFleet(fleet []aircraft.Tail, airport *airports.Airport, runwayID string, obs *weather.Observation, factor float64) (*fleetBoard, error) {
+	rwy, end, err := departureRunway(airport, runwayID, obs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Conditions shared by the whole fleet
+	components := wind.Decompose(obs.Wind, wind.TrueDirection(end.TrueHeading), 0)
+	if obs.Variable {
+		// No headwind credit and the full speed across the runway
+		components = wind.Components{Crosswind: obs.Wind.Speed}
+	}
+	gustCrosswind := abs(components.Crosswind)
+	if obs.Wind.Gust > obs.Wind.Speed && obs.Wind.Speed > 0 {
+		gustCrosswind *= obs.Wind.Gust / obs.Wind.Speed
+	}
+	pressureAlt := airport.Elevation
+	if obs.Altimeter > 0 {
+		pressureAlt = atmosphere.PressureAltitude(airport.Elevation, obs.Altimeter)
+	}
+	params := performance.TakeoffParams{
+		PressureAltitude: pressureAlt,
+		Temperature:      obs.Temperature,
+		WindComponent:    components.Headwind,
+	}
+
+	summaries := make([]*fleetSummary, len(fleet))
+	for i, tail := range fleet {
+		maxCrosswind := tail.Profile.Limits.MaxDemonstratedCrosswind
+		s := &fleetSummary{
+			Tail:        tail,
+			Available:   rwy.Length / factor,
+			Crosswind:   gustCrosswind,
+			CrosswindOK: maxCrosswind == 0 || gustCrosswind <= maxCrosswind,
+		}
+
+		if s.Dispatch, s.Err = tail.WeightBalance.Compute(wb.Loading{FuelGallons: tail.FuelGallons}); s.Err == nil {
+			s.MaxWeight, s.Err = tail.Profile.NewTakeoffCalculator().MaxWeight(params, s.Available)
+		}
+		if s.Err == nil {
+			s.MaxPayload = s.MaxWeight.Value - s.Dispatch.TakeoffWeight
+		}
+		if tail.Profile.NewLandingCalculator != nil {
+			calc := tail.Profile.NewLandingCalculator()
+			landing := performance.LandingParams{
+				PressureAltitude: params.PressureAltitude,
+				Temperature:      params.Temperature,
+				WindComponent:    params.WindComponent,
+			}
+			for _, limit := range calc.Envelope() {
+				if limit.Field == performance.FieldWeight {
+					landing.Weight = limit.Max
+				}
+			}
+			s.Landing, _ = calc.CalculateLanding(landing)
+		}
+		summaries[i] = s
+	}
+
+	return &fleetBoard{
+		Airport:     airport,
+		Runway:      rwy,
+		End:         end,
+		Observation: obs,
+		Components:  components,
+		Params:      params,
+		Factor:      factor,
+		Summaries:   summaries,
+	}, nil
+}
+
+// metarStation is the identifier an airport's METAR is reported under
+func metarStation(airport *airports.Airport) string {
+	if airport.ICAO != "" {
+		return airport.ICAO
+	}
+	return airport.Ident
+}
+
+// dispatchWeight is the takeoff weight with dispatch fuel and no payload, or 0 if it could not be computed
+func dispatchWeight(s *fleetSummary) float64 {
+	if s.Dispatch == nil {
+		return 0
+	}
+	return s.Dispatch.TakeoffWeight
+}
+
+// printFleetCSV prints the fleet summaries as CSV
+func printFleetCSV(summaries []*fleetSummary, airport *airports.Airport, end *airports.RunwayEnd, params performance.TakeoffParams) int {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"tail", "aircraft", "airport", "runway", "pressure_altitude", "temperature_c", "headwind",
+		"crosswind", "crosswind_ok", "fuel_gal", "max_weight", "limit", "max_payload", "error"})
+
+	number := func(v float64) string { return strconv.FormatFloat(v, 'f', 0, 64) }
+	for _, s := range summaries {
+		row := []string{s.Tail.Registration, s.Tail.Profile.ID, airport.Ident, end.ID,
+			number(params.PressureAltitude), number(params.Temperature), number(params.WindComponent),
+			number(s.Crosswind), strconv.FormatBool(s.CrosswindOK), strconv.FormatFloat(s.Tail.FuelGallons, 'f', -1, 64)}
+		if s.Err != nil {
+			row = append(row, "", "", "", s.Err.Error())
+		} else {
+			limit, _ := s.MaxWeight.Limit.MarshalText()
+			row = append(row, number(s.MaxWeight.Value), string(limit), number(s.MaxPayload), "")
+		}
+		w.Write(row)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto fleet: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// departureRunway finds the given runway end, or the end with the most headwind
+func departureRunway(airport *airports.Airport, runwayID string, obs *weather.Observation) (*airports.Runway, *airports.RunwayEnd, error) {
+	if runwayID != "" {
+		rwy, end, err := airport.Runway(runwayID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if end == nil {
+			return nil, nil, fmt.Errorf("specify a single runway end (e.g. 17), not %s", runwayID)
+		}
+		return rwy, end, nil
+	}
+
+	var bestRunway *airports.Runway
+	var bestEnd *airports.RunwayEnd
+	best := 0.0
+	for i := range airport.Runways {
+		rwy := &airport.Runways[i]
+		for j := range rwy.Ends {
+			headwind := wind.Decompose(obs.Wind, wind.TrueDirection(rwy.Ends[j].TrueHeading), 0).Headwind
+			// Prefer the longer runway when the wind favours neither
+			if bestEnd == nil || headwind > best+0.5 || (headwind > best-0.5 && rwy.Length > bestRunway.Length) {
+				bestRunway, bestEnd, best = rwy, &rwy.Ends[j], headwind
+			}
+		}
+	}
+	if bestEnd == nil {
+		return nil, nil, fmt.Errorf("no runways known at %s", airport.Ident)
+	}
+	return bestRunway, bestEnd, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// runGlide exports the engine-out glide footprint from a position as
+// rings for a moving map, one per height above the terrain
+func runGlide(args []string) int {
+	fs := flag.NewFlagSet("glide", flag.ContinueOnError)
+	from := fs.String("from", "", "Position as LAT,LON in decimal degrees, or an airport identifier")
+	var heights floatList
+	heights.Set("1000")
+	fs.Var(&heights, "heights", "Heights above the terrain in feet, comma separated, one ring each")
+	elevation := fs.Float64("elevation", math.NaN(), "Terrain elevation in feet (default: the airport's, or 0 for a position)")
+	altimeter := fs.Float64("altimeter", 29.92, "Altimeter setting in inHg")
+	temp := fs.Float64("temp", math.NaN(), "Surface temperature in °C (default: standard)")
+	windDir := fs.Float64("wind-dir", 0, "Wind direction in degrees true")
+	windSpeed := fs.Float64("wind-speed", 0, "Wind speed in knots, taken as the same at every height")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	format := fs.String("format", "geojson", "Output format: geojson or kml")
+	points := fs.Int("points", 72, "Points around each ring")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto glide -from LAT,LON|IDENT [options] > glide.geojson\n\n")
+		fmt.Fprintf(os.Stderr, "Each ring is the still-air glide range at best glide speed, carried downwind by\n")
+		fmt.Fprintf(os.Stderr, "the drift over the time of the glide. Terrain and obstacles are not considered.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *from == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *format != "geojson" && *format != "kml" {
+		fmt.Fprintf(os.Stderr, "otto glide: -format must be geojson or kml\n")
+		return 2
+	}
+	if *points < 8 {
+		fmt.Fprintf(os.Stderr, "otto glide: -points must be at least 8\n")
+		return 2
+	}
+	for _, h := range heights {
+		if h <= 0 {
+			fmt.Fprintf(os.Stderr, "otto glide: heights must be above the terrain\n")
+			return 2
+		}
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto glide: %v\n", err)
+		return 2
+	}
+	if profile.Glide.Ratio == 0 {
+		fmt.Fprintf(os.Stderr, "otto glide: %s has no glide data\n", profile.Name)
+		return 1
+	}
+	provider, err := airportProvider(*nasrDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto glide: %v\n", err)
+		return 1
+	}
+	position, origin, err := parsePosition(context.Background(), provider, *from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto glide: %v\n", err)
+		return 2
+	}
+	name := fmt.Sprintf("%.4f, %.4f", position.Latitude, position.Longitude)
+	if origin != nil {
+		name = origin.Ident
+	}
+	if math.IsNaN(*elevation) {
+		*elevation = 0
+		if origin != nil {
+			*elevation = origin.Elevation
+		}
+	}
+
+	surfacePA := atmosphere.PressureAltitude(*elevation, *altimeter)
+	if math.IsNaN(*temp) {
+		*temp = atmosphere.ISATemperature(surfacePA)
+	}
+	w := wind.Wind{From: wind.TrueDirection(*windDir), Speed: *windSpeed}
+
+	var features []geo.Feature
+	for _, h := range heights {
+		footprint := profile.Glide.Footprint(position, h, surfacePA+h, *temp-atmosphere.LapseRate*h/1000, w)
+		features = append(features, geo.Feature{
+			Name: fmt.Sprintf("Glide from %.0f ft AGL", h),
+			Area: footprint.Outline(*points),
+			Properties: map[string]interface{}{
+				"height_ft":    h,
+				"radius_nm":    math.Round(footprint.Radius*100) / 100,
+				"drift_nm":     math.Round(footprint.Drift*100) / 100,
+				"time_min":     math.Round(footprint.Time*10) / 10,
+				"glide_kias":   profile.Glide.Speed,
+				"glide_ratio":  profile.Glide.Ratio,
+				"wind":         fmt.Sprintf("%s at %.0f kt", w.From, w.Speed),
+				"elevation_ft": *elevation,
+			},
+		})
+		fmt.Fprintf(os.Stderr, "%5.0f ft AGL: %.1f nm in still air, drifting %.1f nm over %.1f min\n",
+			h, footprint.Radius, footprint.Drift, footprint.Time)
+	}
+
+	if *format == "kml" {
+		err = geo.WriteKML(os.Stdout, fmt.Sprintf("%s glide footprint", name), features)
+	} else {
+		err = geo.WriteGeoJSON(os.Stdout, features)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto glide: %v\n", err)
+		return 1
+	}
+	return 0
+}
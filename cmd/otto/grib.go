@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/flightplan"
+	"github.com/ryanbmilbourne/otto-perf/grib"
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// runGrib samples GFS or HRRR output at a point and prints the surface
+// forecast and the winds aloft for each valid time, in the forms the
+// outlook, navlog and altitude commands read
+func runGrib(args []string) int {
+	fs := flag.NewFlagSet("grib", flag.ContinueOnError)
+	at := fs.String("at", "", "Airport identifier or LAT/LON to sample the model at")
+	csvOut := fs.Bool("csv", false, "Print the surface forecast as CSV for otto outlook -forecast")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto grib -at KJYO [options] FILE.grib2\n\n")
+		fmt.Fprintf(os.Stderr, "Reads 2 m TMP, 10 m UGRD/VGRD, MSLMA or PRMSL, and isobaric TMP/UGRD/VGRD fields,\n")
+		fmt.Fprintf(os.Stderr, "e.g. a GFS or HRRR subset from the NOMADS grib filter.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 || *at == "" {
+		fmt.Fprintf(os.Stderr, "otto grib: -at and one GRIB2 file are required\n")
+		return 2
+	}
+
+	provider, err := airportProvider(*nasrDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto grib: %v\n", err)
+		return 1
+	}
+	point, err := resolveWaypoint(provider, *at, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto grib: %v\n", err)
+		return 2
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto grib: %v\n", err)
+		return 2
+	}
+	messages, err := grib.Read(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto grib: %s: %v\n", fs.Arg(0), err)
+		return 2
+	}
+
+	// Either part may be missing from a subset, but not both
+	surface, surfaceErr := weather.ForecastFromGRIB(messages, point.Latitude, point.Longitude)
+	aloft := make(map[time.Time]flightplan.WindsAloft)
+	for _, valid := range validTimes(messages) {
+		if winds, err := flightplan.WindsAloftFromGRIB(messages, point.Latitude, point.Longitude, valid); err == nil {
+			aloft[valid] = winds
+		}
+	}
+	if surfaceErr != nil && len(aloft) == 0 {
+		fmt.Fprintf(os.Stderr, "otto grib: %s: %v\n", fs.Arg(0), surfaceErr)
+		return 1
+	}
+
+	if *csvOut {
+		if surfaceErr != nil {
+			fmt.Fprintf(os.Stderr, "otto grib: %s: %v\n", fs.Arg(0), surfaceErr)
+			return 1
+		}
+		if err := surface.WriteCSV(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "otto grib: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Printf("\n%s, model run %s\n", point.Name, localtime.Format(messages[0].Reference, nil))
+	if len(surface) > 0 {
+		rows := [][]string{{"Valid", "Temp", "Wind", "Altimeter"}}
+		for _, p := range surface {
+			altimeter := "-"
+			if p.Altimeter > 0 {
+				altimeter = fmt.Sprintf("%.2f", p.Altimeter)
+			}
+			rows = append(rows, []string{
+				localtime.Format(p.Time, nil),
+				fmt.Sprintf("%.0f°C", p.Temperature),
+				fmt.Sprintf("%03.0f° %.0f kt", p.Wind.From.Degrees, p.Wind.Speed),
+				altimeter,
+			})
+		}
+		fmt.Println()
+		printColumns(rows)
+	}
+
+	if len(aloft) > 0 {
+		fmt.Printf("\nWinds aloft at pressure altitude (for -winds):\n")
+		var rows [][]string
+		for _, valid := range validTimes(messages) {
+			if winds, ok := aloft[valid]; ok {
+				rows = append(rows, []string{localtime.Format(valid, nil), winds.String()})
+			}
+		}
+		printColumns(rows)
+	}
+	return 0
+}
+
+// validTimes lists the distinct valid times of the fields, in order
+func validTimes(messages []*grib.Message) []time.Time {
+	seen := make(map[time.Time]bool)
+	var times []time.Time
+	for _, m := range messages {
+		if valid := m.Valid(); !seen[valid] {
+			seen[valid] = true
+			times = append(times, valid)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times
+}
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/termstyle"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// kioskStaleAge is how old a METAR gets before the kiosk flags it; reports
+// are issued hourly, so one this old means the station has stopped
+const kioskStaleAge = 90 * time.Minute
+
+// kioskState is what the kiosk shows: the board for the latest METAR and
+// the outcome of the last update
+type kioskState struct {
+	Board   *fleetBoard
+	METAR   string
+	Report  *weather.Report
+	Checked time.Time // When the last update ran
+	Next    time.Time // When the next update runs
+	Err     error     // Why the last update failed, leaving the previous board up
+}
+
+// kiosk refreshes the fleet board from the METAR at the home field
+type kiosk struct {
+	fleet    []aircraft.Tail
+	airport  *airports.Airport
+	runwayID string
+	factor   float64
+	refresh  time.Duration
+	fetcher  weather.Fetcher
+
+	mu    sync.Mutex
+	state kioskState
+}
+
+// runKiosk keeps the fleet's dispatch numbers for the home runway on a
+// screen at the front desk, updated from each new METAR: full screen in
+// the terminal, or as a self-refreshing web page with -addr
+func runKiosk(args []string) int {
+	fs := flag.NewFlagSet("kiosk", flag.ContinueOnError)
+	fleetFile := fs.String("fleet", "", "Fleet CSV: tail, aircraft[, empty_weight, fuel_gal]")
+	airportID := fs.String("airport", "", "Home airport identifier")
+	runwayID := fs.String("runway", "", "Departure runway end (default: the end with the most headwind at each update)")
+	factor := fs.Float64("factor", 1.0, "Safety factor applied to the takeoff distance, e.g. 1.5")
+	refresh := fs.Duration("refresh", 5*time.Minute, "How often to check for a new METAR")
+	addr := fs.String("addr", "", "Serve the board as a web page on this address (e.g. :8080) instead of drawing it in the terminal")
+	highContrast := fs.Bool("high-contrast", false, "Highlight with bold and reverse video instead of color, for a screen in sunlight")
+	noColor := fs.Bool("no-color", false, "Do not highlight in color (also set by NO_COLOR)")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	sources := addSourceFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto kiosk -fleet fleet.csv -airport KJYO [-addr :8080] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Shows the conditions at the home runway and each aircraft's maximum weight,\n")
+		fmt.Fprintf(os.Stderr, "payload, landing distance and crosswind status, as in otto fleet, and updates\n")
+		fmt.Fprintf(os.Stderr, "them from each new METAR until interrupted. If an update fails the last board\n")
+		fmt.Fprintf(os.Stderr, "stays up with the failure shown beneath it.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+		return 2
+	}
+	if *fleetFile == "" || *airportID == "" {
+		fmt.Fprintf(os.Stderr, "otto kiosk: -fleet and -airport are required\n")
+		return 2
+	}
+	if *factor < 1 {
+		fmt.Fprintf(os.Stderr, "otto kiosk: -factor must be at least 1\n")
+		return 2
+	}
+	if *refresh < time.Minute {
+		fmt.Fprintf(os.Stderr, "otto kiosk: -refresh must be at least 1m\n")
+		return 2
+	}
+
+	f, err := os.Open(*fleetFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+		return 2
+	}
+	fleet, err := aircraft.ReadFleetCSV(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto kiosk: %s: %v\n", *fleetFile, err)
+		return 2
+	}
+
+	provider, err := sources.airportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+		return 1
+	}
+	airport, err := airports.Resolve(context.Background(), provider, *airportID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+		return 1
+	}
+	if *runwayID != "" {
+		if _, _, err := departureRunway(airport, *runwayID, &weather.Observation{}); err != nil {
+			fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+			return 2
+		}
+	}
+
+	// Cache for the refresh interval, so a provider outage still shows the
+	// last report, flagged as stale
+	fetcher, err := sources.weatherFetcher("kiosk", *netConfig, "", *refresh, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+		return 1
+	}
+
+	k := &kiosk{
+		fleet:    fleet,
+		airport:  airport,
+		runwayID: *runwayID,
+		factor:   *factor,
+		refresh:  *refresh,
+		fetcher:  fetcher,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if *addr != "" {
+		return k.serve(ctx, *addr)
+	}
+	return k.draw(ctx, termstyle.Detect(os.Stdout, *noColor, *highContrast))
+}
+
+// update fetches the METAR and works out a new board, keeping the previous
+// one when that fails
+func (k *kiosk) update(ctx context.Context) {
+	now := time.Now()
+	report, err := k.fetcher.Fetch(ctx, weather.METAR, metarStation(k.airport))
+	var obs *weather.Observation
+	if err == nil {
+		obs, err = weather.ParseMETAR(report.Raw, now)
+	}
+	if err == nil && !obs.HasTemperature {
+		err = fmt.Errorf("METAR %s has no temperature", obs.Station)
+	}
+	var board *fleetBoard
+	if err == nil {
+		board, err = summarizeFleet(k.fleet, k.airport, k.runwayID, obs, k.factor)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.state.Checked = now
+	k.state.Next = now.Add(k.refresh)
+	k.state.Err = err
+	if err == nil {
+		k.state.Board, k.state.METAR, k.state.Report = board, strings.TrimSpace(report.Raw), report
+	}
+}
+
+// snapshot returns the current state
+func (k *kiosk) snapshot() kioskState {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.state
+}
+
+// run updates the board now and then every refresh interval until ctx is
+// done, calling changed after each update
+func (k *kiosk) run(ctx context.Context, changed func()) {
+	ticker := time.NewTicker(k.refresh)
+	defer ticker.Stop()
+	for {
+		k.update(ctx)
+		changed()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw shows the board full screen in the terminal until ctx is done
+func (k *kiosk) draw(ctx context.Context, style termstyle.Styler) int {
+	if style.Mode != termstyle.Plain {
+		// Hide the cursor while the board is up
+		fmt.Print("\x1b[?25l")
+		defer fmt.Print("\x1b[?25h\n")
+	}
+	k.run(ctx, func() {
+		var b bytes.Buffer
+		if style.Mode != termstyle.Plain {
+			b.WriteString("\x1b[H\x1b[2J")
+		} else {
+			b.WriteString("\f")
+		}
+		writeKioskBoard(&b, k.snapshot(), style, time.Now())
+		os.Stdout.Write(b.Bytes())
+	})
+	return 0
+}
+
+// serve serves the board as a web page until ctx is done
+func (k *kiosk) serve(ctx context.Context, addr string) int {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var b bytes.Buffer
+		if err := kioskTemplate.Execute(&b, newKioskPage(k.snapshot(), k.refresh, time.Now())); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write(b.Bytes())
+	})
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "otto kiosk: serving the board on %s\n", addr)
+	go k.run(ctx, func() {
+		if err := k.snapshot().Err; err != nil {
+			fmt.Fprintf(os.Stderr, "otto kiosk: update failed: %v\n", err)
+		}
+	})
+
+	select {
+	case err := <-served:
+		fmt.Fprintf(os.Stderr, "otto kiosk: %v\n", err)
+		return 1
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdown)
+	return 0
+}
+
+// kioskRow is one aircraft's line on the board
+type kioskRow struct {
+	Tail      string
+	Aircraft  string
+	MaxWeight string
+	Payload   string
+	Landing   string
+	Crosswind string
+	Warning   bool // Payload or crosswind rules the aircraft out
+}
+
+// kioskRows formats each aircraft's numbers for the board
+func kioskRows(board *fleetBoard) []kioskRow {
+	rows := make([]kioskRow, len(board.Summaries))
+	for i, s := range board.Summaries {
+		row := kioskRow{
+			Tail:      s.Tail.Registration,
+			Aircraft:  s.Tail.Profile.Name,
+			MaxWeight: "n/a",
+			Payload:   "NOT AVAILABLE",
+			Landing:   "n/a",
+			Crosswind: fmt.Sprintf("%.0f kt OK", s.Crosswind),
+			Warning:   s.Err != nil || !s.CrosswindOK,
+		}
+		if !s.CrosswindOK {
+			row.Crosswind = fmt.Sprintf("%.0f kt EXCEEDS %.0f", s.Crosswind, s.Tail.Profile.Limits.MaxDemonstratedCrosswind)
+		}
+		if s.Err == nil {
+			limit := "runway"
+			if s.MaxWeight.Limit == performance.LimitChart {
+				limit = "max"
+			}
+			row.MaxWeight = fmt.Sprintf("%.0f lbs (%s)", s.MaxWeight.Value, limit)
+			if s.MaxPayload < 0 {
+				row.Payload = fmt.Sprintf("NONE: -%.1f gal", -s.MaxPayload/s.Tail.WeightBalance.FuelDensity)
+				row.Warning = true
+			} else {
+				row.Payload = fmt.Sprintf("%.0f lbs", s.MaxPayload)
+			}
+		}
+		if s.Landing != nil {
+			row.Landing = fmt.Sprintf("%.0f ft", s.Landing.LandingDistance)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// kioskConditions describes the conditions at the home runway in one line
+func kioskConditions(board *fleetBoard) string {
+	p := board.Params
+	return fmt.Sprintf("%s  PA %.0f ft  %.0f°C  DA %.0f ft  Wind %s, %.0f kt crosswind from the %s",
+		board.Observation.Category(), p.PressureAltitude, p.Temperature, atmosphere.DensityAltitude(p.PressureAltitude, p.Temperature),
+		formatWind(p.WindComponent), abs(board.Components.Crosswind), board.Components.CrosswindSide())
+}
+
+// kioskRunway describes the home runway end and the factored distance
+func kioskRunway(board *fleetBoard) string {
+	line := fmt.Sprintf("%s Runway %s  %.0f ft", board.Airport.Ident, board.End.ID, board.Runway.Length)
+	if board.Factor > 1 {
+		line += fmt.Sprintf(" (%.0f ft with factor %s)", board.Runway.Length/board.Factor, strconv.FormatFloat(board.Factor, 'f', -1, 64))
+	}
+	return line
+}
+
+// kioskNotices lists what the desk must know about the data on the board:
+// a stale or cached METAR and a failed update
+func kioskNotices(state kioskState, now time.Time) []string {
+	var notices []string
+	if state.Board != nil {
+		if age := now.Sub(state.Board.Observation.Observed); age > kioskStaleAge {
+			notices = append(notices, fmt.Sprintf("METAR is %.0f minutes old", age.Minutes()))
+		}
+		if state.Report.Stale {
+			notices = append(notices, "weather provider unreachable, showing the cached report")
+		}
+	}
+	if state.Err != nil {
+		notices = append(notices, fmt.Sprintf("update at %s failed: %v", state.Checked.UTC().Format("1504Z"), state.Err))
+	}
+	return notices
+}
+
+// writeKioskBoard draws the board as text for a full-screen terminal
+func writeKioskBoard(w io.Writer, state kioskState, style termstyle.Styler, now time.Time) {
+	fmt.Fprintf(w, "%s  %s\n\n", style.Emphasis("otto-perf fleet board"), now.UTC().Format("2006-01-02 1504Z"))
+	if state.Board == nil {
+		fmt.Fprintf(w, "Waiting for the first METAR...\n")
+	} else {
+		board := state.Board
+		fmt.Fprintf(w, "%s\n", style.Emphasis(kioskRunway(board)))
+		fmt.Fprintf(w, "%s\n", state.METAR)
+		fmt.Fprintf(w, "%s\n\n", kioskConditions(board))
+
+		// Align the columns before highlighting, which tabwriter would count
+		rows := kioskRows(board)
+		var table bytes.Buffer
+		tw := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "TAIL\tAIRCRAFT\tMAX WEIGHT\tMAX PAYLOAD\tLANDING\tCROSSWIND\n")
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", row.Tail, row.Aircraft, row.MaxWeight, row.Payload, row.Landing, row.Crosswind)
+		}
+		tw.Flush()
+		for i, line := range strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n") {
+			if i > 0 && rows[i-1].Warning {
+				line = style.Warning(line)
+			}
+			fmt.Fprintf(w, "%s\n", line)
+		}
+		fmt.Fprintf(w, "\nLanding is over 50 ft at maximum weight. Verify against the POH before flight.\n")
+	}
+
+	fmt.Fprintf(w, "\n")
+	for _, notice := range kioskNotices(state, now) {
+		fmt.Fprintf(w, "%s\n", style.Caution("WARNING: "+notice))
+	}
+	if !state.Next.IsZero() {
+		fmt.Fprintf(w, "Next update %s\n", state.Next.UTC().Format("1504Z"))
+	}
+}
+
+// kioskPage is the data behind the kiosk web page
+type kioskPage struct {
+	Refresh    int // Seconds between page reloads
+	Now        string
+	Ready      bool
+	Runway     string
+	METAR      string
+	Conditions string
+	Rows       []kioskRow
+	Notices    []string
+	Next       string
+}
+
+// newKioskPage fills in the web page for a state
+func newKioskPage(state kioskState, refresh time.Duration, now time.Time) kioskPage {
+	// Reload often enough to show a new board soon after it is worked out
+	page := kioskPage{
+		Refresh: int(refresh.Seconds() / 5),
+		Now:     now.UTC().Format("2006-01-02 1504Z"),
+		Notices: kioskNotices(state, now),
+	}
+	if page.Refresh < 15 {
+		page.Refresh = 15
+	}
+	if !state.Next.IsZero() {
+		page.Next = state.Next.UTC().Format("1504Z")
+	}
+	if state.Board != nil {
+		page.Ready = true
+		page.Runway = kioskRunway(state.Board)
+		page.METAR = state.METAR
+		page.Conditions = kioskConditions(state.Board)
+		page.Rows = kioskRows(state.Board)
+	}
+	return page
+}
+
+// kioskTemplate lays out the board for a screen across the room
+var kioskTemplate = template.Must(template.New("kiosk").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Fleet board</title>
+<style>
+body { background: #000; color: #fff; font-family: sans-serif; font-size: 2.2vw; margin: 2vw; }
+h1 { font-size: 1.6em; margin: 0 0 0.4em; }
+.metar { font-family: monospace; color: #ccc; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { text-align: left; padding: 0.3em 0.6em; border-bottom: 1px solid #444; }
+tr.warning td { background: #a00; font-weight: bold; }
+.notice { color: #fc0; font-weight: bold; }
+.footer { color: #999; font-size: 0.8em; }
+</style>
+</head>
+<body>
+{{if .Ready}}<h1>{{.Runway}}</h1>
+<p class="metar">{{.METAR}}</p>
+<p>{{.Conditions}}</p>
+<table>
+<tr><th>Tail</th><th>Aircraft</th><th>Max weight</th><th>Max payload</th><th>Landing</th><th>Crosswind</th></tr>
+{{range .Rows}}<tr{{if .Warning}} class="warning"{{end}}><td>{{.Tail}}</td><td>{{.Aircraft}}</td><td>{{.MaxWeight}}</td><td>{{.Payload}}</td><td>{{.Landing}}</td><td>{{.Crosswind}}</td></tr>
+{{end}}</table>
+<p class="footer">Landing is over 50 ft at maximum weight. Verify against the POH before flight.</p>
+{{else}}<h1>Waiting for the first METAR...</h1>
+{{end}}{{range .Notices}}<p class="notice">WARNING: {{.}}</p>
+{{end}}<p class="footer">{{.Now}}{{with .Next}}, next update {{.}}{{end}}</p>
+</body>
+</html>
+`))
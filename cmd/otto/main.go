@@ -0,0 +1,209 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/airports"
+)
+
+// command is a single otto subcommand
+type command struct {
+	summary string
+	run     func(args []string) int
+}
+
+// commands lists every subcommand by name
+var commands = map[string]command{
+	"airport": {
+		summary: "Show airport and runway information",
+		run:     runAirport,
+	},
+	"aircraft": {
+		summary: "Inspect an aircraft profile's charts: axes, grid resolution and gaps",
+		run:     runAircraft,
+	},
+	"altitude": {
+		summary: "Rank cruise altitudes for a route by time or fuel from the winds aloft",
+		run:     runAltitude,
+	},
+	"book": {
+		summary: "Render a printable HTML booklet of takeoff tables for a home airport",
+		run:     runBook,
+	},
+	"climb": {
+		summary: "Print time, fuel and distance to climb every 1000 ft up to cruise altitude",
+		run:     runClimb,
+	},
+	"corridor": {
+		summary: "Export the climb-out path and departure corridor of a runway as KML or GeoJSON",
+		run:     runCorridor,
+	},
+	"cruise": {
+		summary: "Find the RPM for a cruise power, target true airspeed or target fuel flow",
+		run:     runCruise,
+	},
+	"dayplan": {
+		summary: "Compare takeoff and landing performance across departure times from the TAF",
+		run:     runDayplan,
+	},
+	"digitize": {
+		summary: "Assemble a takeoff chart grid from points read off a POH figure, with residuals",
+		run:     runDigitize,
+	},
+	"e6b": {
+		summary: "Flight computer calculations: wind triangle, crosswind, density altitude, conversions",
+		run:     runE6B,
+	},
+	"fleet": {
+		summary: "Print today's maximum payload and crosswind status for each aircraft of a fleet",
+		run:     runFleet,
+	},
+	"glide": {
+		summary: "Export engine-out glide footprint rings from a position as GeoJSON or KML",
+		run:     runGlide,
+	},
+	"grib": {
+		summary: "Sample GFS or HRRR GRIB2 output for a field forecast and winds aloft",
+		run:     runGrib,
+	},
+	"kiosk": {
+		summary: "Show the fleet's numbers for the home runway on a front-desk screen, updated from each METAR",
+		run:     runKiosk,
+	},
+	"margins": {
+		summary: "Print the takeoff margins recorded by otto serve, or export them as OpenMetrics",
+		run:     runMargins,
+	},
+	"navlog": {
+		summary: "Print a navigation log with heading, groundspeed, time and fuel per leg",
+		run:     runNavlog,
+	},
+	"nearest": {
+		summary: "List the nearest airports with a runway long enough to land on in their METARs",
+		run:     runNearest,
+	},
+	"outlook": {
+		summary: "Show a multi-day GO/NO calendar for a scenario from a forecast",
+		run:     runOutlook,
+	},
+	"payload": {
+		summary: "Find today's maximum payload from a runway, and the maximum fuel with given people",
+		run:     runPayload,
+	},
+	"query": {
+		summary: "Count and average the results recorded in a warehouse, e.g. NO-GO verdicts by month",
+		run:     runQuery,
+	},
+	"score": {
+		summary: "Score the runway, climb, fuel and weather margins of a flight from 0 to 100",
+		run:     runScore,
+	},
+	"serve": {
+		summary: "Serve the calculators over HTTP with health and readiness endpoints",
+		run:     runServe,
+	},
+	"selftest": {
+		summary: "Verify installed aircraft profiles against their POH reference cases",
+		run:     runSelftest,
+	},
+	"sim": {
+		summary: "Publish takeoff performance for a flight simulator's conditions over UDP and WebSocket",
+		run:     runSim,
+	},
+	"stitch": {
+		summary: "Compose a takeoff chart printed across several POH figures, checking the seams",
+		run:     runStitch,
+	},
+	"sweep": {
+		summary: "Compute takeoff distances over a grid of altitudes, temperatures, weights and winds",
+		run:     runSweep,
+	},
+	"takeoff": {
+		summary: "Compute takeoff performance for given conditions or a built-in training scenario",
+		run:     runTakeoff,
+	},
+	"validate": {
+		summary: "Check inputs for completeness and chart envelope compliance without computing",
+		run:     runValidate,
+	},
+	"wb": {
+		summary: "Check the weight and CG of a loading and the categories it qualifies for",
+		run:     runWB,
+	},
+	"weather": {
+		summary: "Fetch a METAR, TAF or winds aloft forecast (cached on disk)",
+		run:     runWeather,
+	},
+}
+
+func main() {
+	// Options before the command apply to every command
+	global := flag.NewFlagSet("otto", flag.ContinueOnError)
+	dataDir := global.String("data-dir", "", "")
+	global.Usage = usage
+	if err := global.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+	args := global.Args()
+	if len(args) < 1 {
+		usage()
+		os.Exit(2)
+	}
+	if *dataDir != "" {
+		if err := airports.SetDataDir(*dataDir); err != nil {
+			fmt.Fprintf(os.Stderr, "otto: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	name := args[0]
+	if name == "help" {
+		usage()
+		os.Exit(0)
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "otto: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(cmd.run(args[1:]))
+}
+
+// usage prints the list of available subcommands
+func usage() {
+	fmt.Fprintf(os.Stderr, "PA-28-161 Cherokee Warrior II Performance Tools\n\n")
+	fmt.Fprintf(os.Stderr, "Usage: otto [-data-dir DIR] <command> [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintf(os.Stderr, "\nOptions:\n")
+	fmt.Fprintf(os.Stderr, "  -data-dir DIR  Read airports.csv and runways.csv from DIR instead of the\n")
+	fmt.Fprintf(os.Stderr, "                 airport data built into the binary\n")
+	fmt.Fprintf(os.Stderr, "\nRun 'otto <command> -help' for command options.\n")
+}
+
+// printErrorLines prints an error of several lines, such as the problems
+// found in a scenario file, with the command prefix on each line
+func printErrorLines(cmd string, err error) {
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Fprintf(os.Stderr, "otto %s: %s\n", cmd, line)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/margins"
+)
+
+// runMargins prints the takeoff margins recorded by otto serve, as a table
+// or as OpenMetrics to backfill a time series database
+func runMargins(args []string) int {
+	fs := flag.NewFlagSet("margins", flag.ContinueOnError)
+	logFile := fs.String("log", "", "Margin log written by otto serve -margin-log")
+	since := fs.String("since", "", "Only margins observed from this date or time on, e.g. 2026-06-01 (UTC)")
+	scenarioName := fs.String("scenario", "", "Only this scenario's margins")
+	openMetrics := fs.Bool("openmetrics", false, "Print OpenMetrics instead of a table")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto margins -log FILE [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Prometheus keeps only the latest margins scraped from /metrics; load the whole\n")
+		fmt.Fprintf(os.Stderr, "history with, e.g.:\n\n")
+		fmt.Fprintf(os.Stderr, "  otto margins -log margins.jsonl -openmetrics > margins.om\n")
+		fmt.Fprintf(os.Stderr, "  promtool tsdb create-blocks-from openmetrics margins.om data/\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *logFile == "" {
+		fmt.Fprintf(os.Stderr, "otto margins: -log is required\n")
+		return 2
+	}
+	var from time.Time
+	if *since != "" {
+		var err error
+		if from, err = localtime.Parse(*since, time.UTC); err != nil {
+			fmt.Fprintf(os.Stderr, "otto margins: -since: %v\n", err)
+			return 2
+		}
+	}
+
+	samples, err := margins.NewLog(*logFile).Read(from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto margins: %v\n", err)
+		return 1
+	}
+	if *scenarioName != "" {
+		kept := samples[:0]
+		for _, s := range samples {
+			if s.Scenario == *scenarioName {
+				kept = append(kept, s)
+			}
+		}
+		samples = kept
+	}
+
+	if *openMetrics {
+		if err := margins.WriteOpenMetrics(os.Stdout, samples); err != nil {
+			fmt.Fprintf(os.Stderr, "otto margins: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	if len(samples) == 0 {
+		fmt.Println("No margins recorded.")
+		return 0
+	}
+
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Time.Before(samples[j].Time) })
+	rows := [][]string{{"Observed", "Scenario", "Runway", "DA", "Takeoff", "Margin"}}
+	for _, s := range samples {
+		rows = append(rows, []string{
+			s.Time.UTC().Format("2006-01-02 1504Z"),
+			s.Scenario,
+			s.Airport + " " + s.Runway,
+			fmt.Sprintf("%.0f ft", s.DensityAltitude),
+			fmt.Sprintf("%.0f ft", s.TakeoffDistance),
+			fmt.Sprintf("%.0f ft (%.0f%%)", s.Margin, 100*s.Margin/s.RunwayLength),
+		})
+	}
+	printColumns(rows)
+	return 0
+}
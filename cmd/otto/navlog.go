@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/flightplan"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// runNavlog prints a navigation log for a route: course, distance, wind
+// correction, heading, groundspeed, time and fuel for every leg
+func runNavlog(args []string) int {
+	fs := flag.NewFlagSet("navlog", flag.ContinueOnError)
+	var route stringList
+	fs.Var(&route, "route", "Waypoints, comma separated: airport identifiers or LAT/LON (e.g. KJYO,39.25/-77.45,KFDK)")
+	altitude := fs.Float64("altitude", 0, "Cruise pressure altitude in feet")
+	power := fs.Float64("power", 65, "Cruise power in percent")
+	winds := fs.String("winds", "", "Winds aloft as ALTITUDE:DDSS[±TT] levels, e.g. 3000:2710,6000:2815+05")
+	var terrain floatList
+	fs.Var(&terrain, "terrain", "Highest terrain per leg in ft MSL, comma separated, to check against the service ceiling")
+	clearance := fs.Float64("clearance", 1000, "Clearance above terrain in feet for -terrain")
+	altimeter := fs.Float64("altimeter", atmosphere.StandardAltimeter, "Altimeter setting in inHg for -terrain")
+	magVar := fs.Float64("magvar", 0, "Magnetic variation for LAT/LON waypoints in degrees, east positive")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto navlog -route KJYO,KFDK -altitude 4500 -winds 3000:2710,6000:2815+05 [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Every leg is flown at the cruise true airspeed and fuel flow; climb and descent are not included.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if len(route) < 2 || *winds == "" {
+		fmt.Fprintf(os.Stderr, "otto navlog: -route with at least two waypoints and -winds are required\n")
+		return 2
+	}
+
+	forecast, err := flightplan.ParseWindsAloft(*winds)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto navlog: %v\n", err)
+		return 2
+	}
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto navlog: %v\n", err)
+		return 2
+	}
+	provider, err := airportProvider(*nasrDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto navlog: %v\n", err)
+		return 1
+	}
+
+	waypoints := make([]flightplan.Waypoint, len(route))
+	for i, name := range route {
+		waypoints[i], err = resolveWaypoint(provider, name, *magVar)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto navlog: %v\n", err)
+			return 1
+		}
+	}
+
+	navlog, err := flightplan.NewNavlog(profile.NewCruiseCalculator(), flightplan.NavlogQuery{
+		Waypoints: waypoints,
+		Altitude:  *altitude,
+		Power:     *power,
+		Winds:     forecast,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto navlog: %v\n", err)
+		return 1
+	}
+
+	rows := [][]string{{"Leg", "TC", "Dist", "Wind", "WCA", "TH", "MH", "GS", "ETE", "Fuel", "Total"}}
+	var distance float64
+	for _, leg := range navlog.Legs {
+		distance += leg.Distance
+		rows = append(rows, []string{
+			leg.From + "-" + leg.To,
+			fmt.Sprintf("%03.0f", leg.TrueCourse),
+			fmt.Sprintf("%.1f", leg.Distance),
+			fmt.Sprintf("%03.0f/%.0f", leg.Wind.From.Degrees, leg.Wind.Speed),
+			fmt.Sprintf("%+.0f", leg.WindCorrection),
+			fmt.Sprintf("%03.0f", leg.TrueHeading),
+			fmt.Sprintf("%03.0f", leg.MagneticHeading),
+			fmt.Sprintf("%.0f", leg.Groundspeed),
+			fmt.Sprintf("%.0f min", leg.Time),
+			fmt.Sprintf("%.1f gal", leg.Fuel),
+			fmt.Sprintf("%.0f min", leg.TotalTime),
+		})
+	}
+
+	last := navlog.Legs[len(navlog.Legs)-1]
+	fmt.Printf("\nNavlog at %.0f ft, %.0f%% power: %.0f KTAS, %.0f RPM, %.1f gph\n\n",
+		*altitude, navlog.Cruise.Power, navlog.Cruise.TrueAirspeed, navlog.Cruise.RPM, navlog.Cruise.FuelFlow)
+	printColumns(rows)
+	fmt.Printf("\nTotal: %.1f nm, %.0f min, %.1f gal\n", distance, last.TotalTime, last.TotalFuel)
+
+	if len(terrain) > 0 {
+		legs, err := flightplan.CheckCeiling(profile.NewClimbCalculator(), flightplan.CeilingQuery{
+			Waypoints: waypoints,
+			Terrain:   terrain,
+			Clearance: *clearance,
+			Altitude:  *altitude - atmosphere.PressureAltitude(0, *altimeter),
+			Altimeter: *altimeter,
+			Winds:     forecast,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto navlog: %v\n", err)
+			return 2
+		}
+		printCeiling(legs)
+	}
+	return 0
+}
+
+// printCeiling prints the terrain check of each leg against the service ceiling
+func printCeiling(legs []flightplan.LegCeiling) {
+	rows := [][]string{{"Leg", "Terrain", "Required", "Ceiling", "Climb", "Status"}}
+	for _, leg := range legs {
+		climb := "-"
+		if leg.RateOfClimb > 0 {
+			climb = fmt.Sprintf("%.0f fpm", leg.RateOfClimb)
+		}
+		rows = append(rows, []string{
+			leg.From + "-" + leg.To,
+			fmt.Sprintf("%.0f ft", leg.Terrain),
+			fmt.Sprintf("%.0f ft", leg.Required),
+			fmt.Sprintf("%.0f ft", leg.Ceiling),
+			climb,
+			leg.Status.String(),
+		})
+	}
+
+	fmt.Printf("\nTerrain check (service ceiling at %d fpm, forecast temperatures, maximum weight chart):\n\n",
+		performance.ServiceCeilingRate)
+	printColumns(rows)
+}
+
+// resolveWaypoint turns a route entry into a waypoint, either a LAT/LON
+// pair or an airport identifier
+func resolveWaypoint(provider airports.Provider, name string, magVar float64) (flightplan.Waypoint, error) {
+	if lat, lon, ok := strings.Cut(name, "/"); ok {
+		latitude, err1 := strconv.ParseFloat(lat, 64)
+		longitude, err2 := strconv.ParseFloat(lon, 64)
+		if err1 != nil || err2 != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			return flightplan.Waypoint{}, fmt.Errorf("invalid waypoint position %q", name)
+		}
+		return flightplan.Waypoint{Name: name, Latitude: latitude, Longitude: longitude, Variation: magVar}, nil
+	}
+
+	a, err := airports.Resolve(context.Background(), provider, name)
+	if err != nil {
+		return flightplan.Waypoint{}, err
+	}
+	return flightplan.Waypoint{
+		Name:      a.ICAO,
+		Latitude:  a.Latitude,
+		Longitude: a.Longitude,
+		Variation: a.MagneticVariation,
+	}, nil
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// runNearest lists the nearest airports with a runway long enough to land
+// on in the current conditions, for diversion planning
+func runNearest(args []string) int {
+	fs := flag.NewFlagSet("nearest", flag.ContinueOnError)
+	from := fs.String("from", "", "Position as LAT,LON in decimal degrees, or an airport identifier")
+	weight := weightValue(2325)
+	fs.Var(&weight, "weight", "Landing weight in lbs (or kg with a suffix)")
+	landing := fs.Float64("landing-distance", 0, "Landing distance over a 50 ft obstacle in feet for every runway, in place of the one computed from each airport's METAR")
+	factor := fs.Float64("factor", 1.0, "Safety factor applied to the landing distance, e.g. 1.67")
+	minWidth := fs.Float64("min-width", 0, "Minimum runway width in feet")
+	paved := fs.Bool("paved", false, "Only hard-surfaced runways")
+	windDir := fs.Float64("wind-dir", 0, "Wind direction in degrees true, in place of each airport's METAR wind")
+	windSpeed := fs.Float64("wind-speed", 0, "Wind speed in knots, in place of each airport's METAR wind")
+	windGust := fs.Float64("wind-gust", 0, "Gust speed in knots")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile for the landing chart and the crosswind limit")
+	limit := fs.Int("limit", 5, "Number of airports to list")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch the METARs from the provider")
+	sources := addSourceFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto nearest -from LAT,LON|IDENT [options]\n\n")
+		fmt.Fprintf(os.Stderr, "The landing distance on each runway is computed from the aircraft's landing chart\n")
+		fmt.Fprintf(os.Stderr, "at the field elevation, with the temperature, altimeter and wind of the airport's\n")
+		fmt.Fprintf(os.Stderr, "METAR. Runways shorter than the factored landing distance, outside the chart, or\n")
+		fmt.Fprintf(os.Stderr, "with a crosswind at the gust speed above the aircraft's maximum demonstrated\n")
+		fmt.Fprintf(os.Stderr, "crosswind, are left out. Airports without a METAR are listed without a landing\n")
+		fmt.Fprintf(os.Stderr, "distance or wind. -landing-distance and the -wind flags override the METARs.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *from == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *factor < 1 {
+		fmt.Fprintf(os.Stderr, "otto nearest: -factor must be at least 1\n")
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto nearest: %v\n", err)
+		return 2
+	}
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto nearest: %v\n", err)
+		return 2
+	}
+	compute := *landing == 0
+	if compute && profile.NewLandingCalculator == nil {
+		fmt.Fprintf(os.Stderr, "otto nearest: %s has no landing chart; give -landing-distance\n", profile.Name)
+		return 2
+	}
+
+	provider, err := sources.airportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto nearest: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	position, origin, err := parsePosition(ctx, provider, *from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto nearest: %v\n", err)
+		return 2
+	}
+	name := fmt.Sprintf("%.4f, %.4f", position.Latitude, position.Longitude)
+	if origin != nil {
+		name = origin.Ident
+	}
+
+	// The runway length needed is only known per runway when computed
+	req := airports.Requirement{MinLength: *landing * *factor, MinWidth: *minWidth, Paved: *paved}
+	candidates, err := airports.Nearest(ctx, provider, position, req, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto nearest: %v\n", err)
+		return 1
+	}
+
+	// The METARs give whatever the flags do not
+	metars := compute || *windSpeed == 0
+	var fetcher weather.Fetcher
+	if metars {
+		if fetcher, err = sources.weatherFetcher("nearest", *netConfig, "", weather.DefaultTTL, *noCache); err != nil {
+			fmt.Fprintf(os.Stderr, "otto nearest: %v\n", err)
+			return 1
+		}
+	}
+	var calc *performance.LandingCalculator
+	if compute {
+		calc = profile.NewLandingCalculator()
+	}
+
+	given := wind.Wind{From: wind.TrueDirection(*windDir), Speed: *windSpeed, Gust: *windGust}
+	maxCrosswind := profile.Limits.MaxDemonstratedCrosswind
+	rows := [][]string{{"Airport", "Distance", "Course", "Runway", "Size", "Surface"}}
+	rows[0] = append(rows[0], "Headwind", "Crosswind")
+	if compute {
+		rows[0] = append(rows[0], "Temp", "Landing")
+	}
+	listed := 0
+	for _, c := range candidates {
+		if listed == *limit {
+			break
+		}
+
+		// The airport's own conditions, or those given for every airport
+		w, variable := given, false
+		var obs *weather.Observation
+		if metars {
+			obs, err = fetchObservation(ctx, fetcher, c.Airport)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "otto nearest: %s: %v\n", c.Airport.Ident, err)
+			} else if given.Speed == 0 {
+				w, variable = obs.Wind, obs.Variable
+			}
+		}
+
+		first := true
+		for _, rwy := range c.Runways {
+			end, components := landingEnd(rwy, w)
+			if variable {
+				// No headwind to count on, and the full wind across
+				components = wind.Components{Crosswind: w.Speed}
+			}
+			if end == nil || (maxCrosswind > 0 && gustCrosswind(components, w) > maxCrosswind) {
+				continue
+			}
+			row := []string{"", "", "", rwy.ID, fmt.Sprintf("%.0f x %.0f", rwy.Length, rwy.Width), rwy.Surface}
+			if obs == nil && given.Speed == 0 {
+				row = append(row, "-", "-")
+			} else {
+				row[3] = end.ID
+				row = append(row, fmt.Sprintf("%.0f kt", components.Headwind), fmt.Sprintf("%.0f kt", abs(components.Crosswind)))
+			}
+			if compute && obs == nil {
+				row = append(row, "no METAR", "-")
+			} else if compute {
+				params := performance.LandingParams{
+					PressureAltitude: c.Airport.Elevation,
+					Temperature:      obs.Temperature,
+					Weight:           float64(weight),
+					WindComponent:    components.Headwind,
+				}
+				if obs.Altimeter > 0 {
+					params.PressureAltitude = atmosphere.PressureAltitude(c.Airport.Elevation, obs.Altimeter)
+				}
+				result, err := calc.CalculateLanding(params)
+				if err != nil || result.LandingDistance**factor > rwy.Length {
+					continue
+				}
+				row = append(row, fmt.Sprintf("%.0f°C", obs.Temperature), fmt.Sprintf("%.0f ft", result.LandingDistance**factor))
+			}
+			if first {
+				row[0] = c.Airport.Ident + " " + c.Airport.Name
+				row[1] = fmt.Sprintf("%.1f nm", c.Distance)
+				row[2] = fmt.Sprintf("%03.0f°T", c.Course)
+				if c.Distance < 0.05 {
+					row[2] = "-"
+				}
+				first = false
+			}
+			rows = append(rows, row)
+		}
+		if !first {
+			listed++
+		}
+	}
+
+	requirement := "any runway"
+	switch {
+	case compute:
+		requirement = fmt.Sprintf("the landing distance at %.0f lbs in each METAR", float64(weight))
+		if *factor != 1 {
+			requirement += " x " + strconv.FormatFloat(*factor, 'f', -1, 64)
+		}
+	case req.MinLength > 0:
+		requirement = fmt.Sprintf("%.0f ft", req.MinLength)
+		if *factor != 1 {
+			requirement += fmt.Sprintf(" (%.0f ft x %s)", *landing, strconv.FormatFloat(*factor, 'f', -1, 64))
+		}
+	}
+	if *paved {
+		requirement += ", paved"
+	}
+	fmt.Printf("\nNearest airports to %s with %s", name, requirement)
+	if given.Speed > 0 {
+		fmt.Printf(", wind %s at %.0f kt", given.From, given.Speed)
+		if given.Gust > given.Speed {
+			fmt.Printf(" gusting %.0f", given.Gust)
+		}
+	} else if !compute {
+		fmt.Printf(", wind in each METAR")
+	}
+	fmt.Printf("\n\n")
+	if listed == 0 {
+		fmt.Println("No suitable airports found.")
+		return 1
+	}
+	printColumns(rows)
+	return 0
+}
+
+// fetchObservation fetches and decodes the latest METAR of an airport,
+// which must report a temperature
+func fetchObservation(ctx context.Context, fetcher weather.Fetcher, airport *airports.Airport) (*weather.Observation, error) {
+	report, err := fetcher.Fetch(ctx, weather.METAR, metarStation(airport))
+	if err != nil {
+		return nil, err
+	}
+	obs, err := weather.ParseMETAR(report.Raw, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !obs.HasTemperature {
+		return nil, fmt.Errorf("METAR %s has no temperature", obs.Station)
+	}
+	return obs, nil
+}
+
+// parsePosition reads a LAT,LON pair, or the position of an airport and
+// the airport itself
+func parsePosition(ctx context.Context, provider airports.Provider, s string) (geo.Point, *airports.Airport, error) {
+	if lat, lon, ok := strings.Cut(s, ","); ok {
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+		if err != nil || latitude < -90 || latitude > 90 {
+			return geo.Point{}, nil, fmt.Errorf("invalid latitude %q", lat)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+		if err != nil || longitude < -180 || longitude > 180 {
+			return geo.Point{}, nil, fmt.Errorf("invalid longitude %q", lon)
+		}
+		return geo.Point{Latitude: latitude, Longitude: longitude}, nil, nil
+	}
+	a, err := airports.Resolve(ctx, provider, s)
+	if err != nil {
+		return geo.Point{}, nil, err
+	}
+	return a.Position(), a, nil
+}
+
+// landingEnd returns the runway end with the most headwind and the wind
+// components on it
+func landingEnd(rwy *airports.Runway, w wind.Wind) (*airports.RunwayEnd, wind.Components) {
+	var best *airports.RunwayEnd
+	var components wind.Components
+	for i := range rwy.Ends {
+		c := wind.Decompose(w, wind.TrueDirection(rwy.Ends[i].TrueHeading), 0)
+		if best == nil || c.Headwind > components.Headwind {
+			best, components = &rwy.Ends[i], c
+		}
+	}
+	return best, components
+}
+
+// gustCrosswind scales a crosswind component up to the gust speed
+func gustCrosswind(c wind.Components, w wind.Wind) float64 {
+	crosswind := abs(c.Crosswind)
+	if w.Gust > w.Speed && w.Speed > 0 {
+		crosswind *= w.Gust / w.Speed
+	}
+	return crosswind
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// forecastWindow is how far from a calendar slot a forecast point may be
+// and still be used for it; extended MOS guidance is three-hourly
+const forecastWindow = 90 * time.Minute
+
+// tafStep is how often the TAF is sampled for the calendar slots
+const tafStep = time.Hour
+
+// runOutlook evaluates a saved scenario against the departure airport's TAF,
+// or a multi-day forecast file, and prints a calendar of departure times
+// that are GO for the runway
+func runOutlook(args []string) int {
+	fs := flag.NewFlagSet("outlook", flag.ContinueOnError)
+	scenarioFile := fs.String("scenario", "", "Scenario file (JSON or YAML) with the weight and departure airport")
+	taf := fs.String("taf", "", "Raw TAF to use instead of fetching the airport's latest")
+	forecastFile := fs.String("forecast", "", "Forecast CSV to use offline instead of the TAF: time (Zulu), temp_c, wind_dir, wind_speed[, wind_gust, altimeter]")
+	runwayID := fs.String("runway", "", "Departure runway end, e.g. 17 (for the wind components and length)")
+	available := fs.Float64("available", 0, "Available takeoff distance in feet (default: the runway length)")
+	factor := fs.Float64("factor", 1.0, "Safety factor applied to the takeoff distance, e.g. 1.5")
+	days := fs.Int("days", 5, "Number of days to show")
+	start := fs.String("start", "", "First day, YYYY-MM-DD (default: today at the airport)")
+	var hours floatList
+	hours.Set("8,10,12,14,16,18")
+	fs.Var(&hours, "hours", "Local departure hours to evaluate, comma separated")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch the TAF from the provider")
+	sources := addSourceFlags(fs)
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto outlook -scenario trip.json -runway 17 [-forecast forecast.csv] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "The winds and altimeter come from the TAF of the scenario's airport, sampled hourly\n")
+		fmt.Fprintf(os.Stderr, "with the prevailing conditions (TEMPO and PROB groups are left out), so the calendar\n")
+		fmt.Fprintf(os.Stderr, "ends with the TAF. The temperatures come from its TX and TN groups; most US TAFs\n")
+		fmt.Fprintf(os.Stderr, "have none, and then the scenario's temperature is used for every slot. A forecast CSV,\n")
+		fmt.Fprintf(os.Stderr, "e.g. from otto grib -csv, covers more days and forecasts the temperatures, offline.\n\n")
+		fmt.Fprintf(os.Stderr, "Each slot uses the forecast point within 90 minutes of it; the scenario supplies\n")
+		fmt.Fprintf(os.Stderr, "the weight, the airport and, without a forecast altimeter, the pressure altitude.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+		return 2
+	}
+	if *scenarioFile == "" {
+		fmt.Fprintf(os.Stderr, "otto outlook: -scenario is required\n")
+		return 2
+	}
+
+	s, err := scenario.Load(*scenarioFile)
+	if err != nil {
+		printErrorLines("outlook", err)
+		return 2
+	}
+	if s.Weight == nil {
+		fmt.Fprintf(os.Stderr, "otto outlook: the scenario has no weight\n")
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(s.Variant)
+	}
+	if err == nil {
+		profile, err = profile.WithEquipment(s.Equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+		return 2
+	}
+
+	// The departure airport gives the local zone, elevation and runway
+	loc := time.UTC
+	var airport *airports.Airport
+	var end *airports.RunwayEnd
+	runwayLength := 0.0
+	if s.Airport != "" {
+		provider, err := sources.airportProvider(*nasrDir, *netConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+			return 1
+		}
+		if airport, err = airports.Resolve(context.Background(), provider, s.Airport); err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+			return 1
+		}
+		if loc, err = airport.Location(); err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+			return 1
+		}
+		if *runwayID != "" {
+			var rwy *airports.Runway
+			if rwy, end, err = airport.Runway(*runwayID); err != nil {
+				fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+				return 1
+			}
+			if end == nil {
+				fmt.Fprintf(os.Stderr, "otto outlook: specify a single runway end (e.g. 17), not %s\n", *runwayID)
+				return 2
+			}
+			runwayLength = rwy.Length
+		}
+	} else if *runwayID != "" {
+		fmt.Fprintf(os.Stderr, "otto outlook: -runway needs an airport in the scenario\n")
+		return 2
+	}
+	if *available > 0 {
+		runwayLength = *available
+	}
+	if runwayLength <= 0 {
+		fmt.Fprintf(os.Stderr, "otto outlook: give -runway with an airport in the scenario, or -available\n")
+		return 2
+	}
+
+	var forecast weather.Forecast
+	temperatures := "forecast"
+	if *forecastFile != "" {
+		f, err := os.Open(*forecastFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+			return 2
+		}
+		forecast, err = weather.ParseForecastCSV(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: %s: %v\n", *forecastFile, err)
+			return 2
+		}
+	} else {
+		if airport == nil && *taf == "" {
+			fmt.Fprintf(os.Stderr, "otto outlook: give an airport in the scenario for its TAF, -taf or -forecast\n")
+			return 2
+		}
+		raw := *taf
+		if raw == "" {
+			station := airport.ICAO
+			if station == "" {
+				station = airport.Ident
+			}
+			fetcher, err := sources.weatherFetcher("outlook", *netConfig, "", weather.DefaultTTL, *noCache)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+				return 1
+			}
+			report, err := fetcher.Fetch(context.Background(), weather.TAF, station)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+				return 1
+			}
+			raw = report.Raw
+		}
+		decoded, err := weather.ParseTAF(raw, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: %v\n", err)
+			return 1
+		}
+		// The TAF's TX and TN groups, or the scenario's temperature
+		temperature, ok := s.Temperature()
+		if len(decoded.Temperatures) > 0 {
+			temperatures = "the TAF's TX/TN"
+		} else if ok {
+			temperatures = fmt.Sprintf("the scenario's %.0f°C (the TAF forecasts none)", temperature)
+		} else {
+			fmt.Fprintf(os.Stderr, "otto outlook: the TAF for %s has no TX/TN temperatures; give a temperature in the scenario, or -forecast\n", decoded.Station)
+			return 2
+		}
+		forecast = decoded.Forecast(tafStep, temperature)
+	}
+
+	first := time.Now().In(loc)
+	if *start != "" {
+		if first, err = time.ParseInLocation("2006-01-02", *start, loc); err != nil {
+			fmt.Fprintf(os.Stderr, "otto outlook: invalid -start %q\n", *start)
+			return 2
+		}
+	}
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+
+	calculator := profile.NewTakeoffCalculator()
+	header := []string{"Day"}
+	for _, h := range hours {
+		header = append(header, fmt.Sprintf("%02.0f:00", h))
+	}
+	rows := [][]string{header}
+
+	for d := 0; d < *days; d++ {
+		day := first.AddDate(0, 0, d)
+		row := []string{day.Format("Mon 02 Jan")}
+		for _, h := range hours {
+			slot := day.Add(time.Duration(h * float64(time.Hour)))
+			point, ok := nearestForecast(forecast, slot)
+			if !ok {
+				row = append(row, "--")
+				continue
+			}
+
+			// Fill in the scenario's weather from the forecast
+			params := performance.TakeoffParams{
+				Temperature: point.Temperature,
+				Weight:      *s.Weight,
+			}
+			switch {
+			case airport != nil && point.Altimeter > 0:
+				params.PressureAltitude = atmosphere.PressureAltitude(airport.Elevation, point.Altimeter)
+			case s.PressureAltitude != nil:
+				params.PressureAltitude = *s.PressureAltitude
+			default:
+				row = append(row, "?")
+				continue
+			}
+			switch {
+			case end != nil && point.Variable:
+				// No headwind to count on from a variable wind
+			case end != nil:
+				params.WindComponent = wind.Decompose(point.Wind, wind.TrueDirection(end.TrueHeading), 0).Headwind
+			case s.WindComponent != nil:
+				params.WindComponent = *s.WindComponent
+			}
+
+			result, err := calculator.CalculateTakeoff(params)
+			if err != nil {
+				row = append(row, "chart")
+				continue
+			}
+			required := result.TakeoffDistance * *factor
+			verdict := "GO"
+			if required > runwayLength {
+				verdict = "NO"
+			}
+			row = append(row, fmt.Sprintf("%s %.0f", verdict, required))
+		}
+		rows = append(rows, row)
+	}
+
+	where := "takeoff distance available"
+	if airport != nil {
+		where = airport.Ident
+		if end != nil {
+			where += " runway " + end.ID
+		}
+	}
+	fmt.Printf("\nTakeoff Outlook: %s, %.0f ft available, %.0f lbs, factor %s\n",
+		where, runwayLength, *s.Weight, strconv.FormatFloat(*factor, 'f', -1, 64))
+	fmt.Printf("Times are %s; temperatures are %s; cells show GO/NO and the factored takeoff distance in feet\n\n", loc, temperatures)
+	printColumns(rows)
+	fmt.Printf("\n-- no forecast for the slot, chart: outside the takeoff chart, ?: no pressure altitude\n")
+	return 0
+}
+
+// nearestForecast finds the forecast point closest to a time, within forecastWindow
+func nearestForecast(forecast weather.Forecast, t time.Time) (weather.ForecastPoint, bool) {
+	points := forecast.Between(t.Add(-forecastWindow), t.Add(forecastWindow+time.Nanosecond))
+	if len(points) == 0 {
+		return weather.ForecastPoint{}, false
+	}
+
+	best := points[0]
+	for _, p := range points[1:] {
+		if abs(p.Time.Sub(t).Minutes()) < abs(best.Time.Sub(t).Minutes()) {
+			best = p
+		}
+	}
+	return best, true
+}
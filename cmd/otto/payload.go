@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// runPayload reports the two numbers a renter negotiates for today's METAR
+// at a runway: the maximum payload with the fuel, and the maximum fuel with
+// the people and baggage, under the lower of the structural and runway
+// limited takeoff weight and within the W&B limits
+func runPayload(args []string) int {
+	fs := flag.NewFlagSet("payload", flag.ContinueOnError)
+	airportID := fs.String("airport", "", "Departure airport identifier")
+	runwayID := fs.String("runway", "", "Departure runway end (default: the end with the most headwind)")
+	available := fs.Float64("available", 0, "Available takeoff distance in feet (default: the runway length)")
+	factor := fs.Float64("factor", 1.0, "Safety factor applied to the takeoff distance, e.g. 1.5")
+	metar := fs.String("metar", "", "Raw METAR to use instead of fetching the latest")
+	fuelGallons := fs.Float64("fuel-gal", 0, "Usable fuel in US gallons for the maximum payload (default: full)")
+	fuelState := fs.String("fuel", "", "Fuel state instead of -fuel-gal: full, a profile preset such as tabs, or gallons, with optional +Nhr/+Ngal")
+	var people weightList
+	fs.Var(&people, "people", "Comma-separated occupant weights in seat order for the maximum fuel, in pounds or with a kg suffix")
+	var baggage weightValue
+	fs.Var(&baggage, "bags", "Baggage weight for the maximum fuel, in pounds or with a kg suffix")
+	emptyWeight := fs.Float64("empty-weight", 0, "Basic empty weight in pounds from the airframe's W&B record (default from the aircraft profile)")
+	emptyArm := fs.Float64("empty-arm", 0, "Empty CG in inches aft of the datum from the airframe's W&B record (default from the aircraft profile)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft (see the aircraft profile)")
+	var equipment stringList
+	fs.Var(&equipment, "equipment", "Comma-separated optional equipment installed, e.g. adsb-out")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch the METAR from the provider")
+	sources := addSourceFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto payload -airport KJYO [-fuel tabs] [-people 170,160 -bags 20] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "The maximum weight is the lower of the structural maximum and the heaviest takeoff\n")
+		fmt.Fprintf(os.Stderr, "that fits the runway in today's METAR. The maximum payload is the people and baggage\n")
+		fmt.Fprintf(os.Stderr, "that fit under it with the fuel; with -people or -bags, the maximum fuel is the most\n")
+		fmt.Fprintf(os.Stderr, "that fits with them, also keeping the CG inside an envelope.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+		return 2
+	}
+	if *airportID == "" {
+		fmt.Fprintf(os.Stderr, "otto payload: -airport is required\n")
+		return 2
+	}
+	if *factor < 1 {
+		fmt.Fprintf(os.Stderr, "otto payload: -factor must be at least 1\n")
+		return 2
+	}
+	fuelGiven, loadGiven := false, false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "fuel-gal", "fuel":
+			fuelGiven = true
+		case "people", "bags":
+			loadGiven = true
+		}
+	})
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err == nil {
+		profile, err = profile.WithEquipment(equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+		return 2
+	}
+	weights := profile.WeightBalance
+	if *emptyWeight > 0 {
+		// The record's empty weight excludes equipment selected with -equipment
+		weights.EmptyWeight = *emptyWeight
+		for _, e := range profile.Installed {
+			weights.EmptyWeight += e.Weight
+		}
+	}
+	if *emptyArm > 0 {
+		weights.EmptyArm = *emptyArm
+	}
+	switch {
+	case *fuelState != "":
+		if *fuelGallons, err = weights.FuelState(*fuelState); err != nil {
+			fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+			return 2
+		}
+	case !fuelGiven:
+		*fuelGallons = weights.FuelCapacity
+	}
+
+	provider, err := sources.airportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+		return 1
+	}
+	airport, err := airports.Resolve(context.Background(), provider, *airportID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+		return 1
+	}
+
+	raw := *metar
+	if raw == "" {
+		station := airport.ICAO
+		if station == "" {
+			station = airport.Ident
+		}
+		fetcher, err := sources.weatherFetcher("payload", *netConfig, "", weather.DefaultTTL, *noCache)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+			return 1
+		}
+		report, err := fetcher.Fetch(context.Background(), weather.METAR, station)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+			return 1
+		}
+		raw = report.Raw
+	}
+	obs, err := weather.ParseMETAR(raw, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+		return 1
+	}
+	if !obs.HasTemperature {
+		fmt.Fprintf(os.Stderr, "otto payload: METAR %s has no temperature\n", obs.Station)
+		return 1
+	}
+
+	rwy, end, err := departureRunway(airport, *runwayID, obs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto payload: %v\n", err)
+		return 2
+	}
+	distance := rwy.Length
+	if *available > 0 {
+		distance = *available
+	}
+	distance /= *factor
+
+	headwind := wind.Decompose(obs.Wind, wind.TrueDirection(end.TrueHeading), 0).Headwind
+	if obs.Variable {
+		headwind = 0
+	}
+	pressureAlt := airport.Elevation
+	if obs.Altimeter > 0 {
+		pressureAlt = atmosphere.PressureAltitude(airport.Elevation, obs.Altimeter)
+	}
+	params := performance.TakeoffParams{
+		PressureAltitude: pressureAlt,
+		Temperature:      obs.Temperature,
+		WindComponent:    headwind,
+	}
+
+	fmt.Printf("METAR:        %s\n", strings.TrimSpace(raw))
+	fmt.Printf("Runway:       %s %s, %.0f ft available (factor %s)\n",
+		airport.Ident, end.ID, distance, strconv.FormatFloat(*factor, 'f', -1, 64))
+	fmt.Printf("Conditions:   %.0f ft PA, %.0f°C, DA %.0f ft, %s\n", params.PressureAltitude, params.Temperature,
+		atmosphere.DensityAltitude(params.PressureAltitude, params.Temperature), formatWind(params.WindComponent))
+
+	maxWeight, err := profile.NewTakeoffCalculator().MaxWeight(params, distance)
+	if err != nil {
+		fmt.Printf("Max weight:   NOT AVAILABLE: %v\n", err)
+		return 1
+	}
+	limit, weightLimit := "runway", maxWeight.Value
+	if maxWeight.Limit == performance.LimitChart {
+		limit = "maximum weight"
+	}
+	if structural := weights.MaxTakeoffWeight(); structural > 0 && structural < weightLimit {
+		limit, weightLimit = "maximum weight", structural
+	}
+	fmt.Printf("Max weight:   %.0f lbs (%s limited)\n", weightLimit, limit)
+
+	if payload := weights.MaxPayload(*fuelGallons, weightLimit); payload < 0 {
+		fmt.Printf("Max payload:  NONE with %.1f gal: reduce fuel by %.1f gal\n", *fuelGallons, -payload/weights.FuelDensity)
+	} else {
+		fmt.Printf("Max payload:  %.0f lbs people and baggage with %.1f gal\n", payload, *fuelGallons)
+	}
+
+	if loadGiven {
+		load := float64(baggage)
+		for _, p := range people {
+			load += p
+		}
+		fuel, err := weights.MaxFuel(people, float64(baggage), weightLimit)
+		if err != nil {
+			fmt.Printf("Max fuel:     NONE with %.0f lbs people and baggage: %v\n", load, err)
+			return 1
+		}
+		fmt.Printf("Max fuel:     %.1f gal with %.0f lbs people and baggage\n", fuel, load)
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/warehouse"
+)
+
+// runQuery answers a filter and aggregation query over the results
+// recorded in a warehouse, for safety program reports
+func runQuery(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	warehouseFile := fs.String("warehouse", "", "Warehouse file written by takeoff -warehouse")
+	var where, groupBy stringList
+	fs.Var(&where, "where", "Comma-separated conditions, e.g. verdict=NO-GO,distance>2000")
+	fs.Var(&groupBy, "group", "Comma-separated keys to group by, e.g. month,aircraft")
+	aggregates := fs.String("select", "count", "Comma-separated aggregates: count, or avg, min, max or sum of a numeric field, e.g. avg(distance)")
+	since := fs.String("since", "", "Only results from this date or time on, e.g. 2026-06-01 (UTC)")
+	until := fs.String("until", "", "Only results before this date or time (UTC)")
+	format := fs.String("format", "table", "Output format: table, csv or json")
+
+	fs.Usage = func() {
+		numeric, keys := warehouse.Fields()
+		fmt.Fprintf(os.Stderr, "Usage: otto query -warehouse FILE [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Examples:\n")
+		fmt.Fprintf(os.Stderr, "  otto query -warehouse results.jsonl -group month -select 'count,avg(distance)'\n")
+		fmt.Fprintf(os.Stderr, "  otto query -warehouse results.jsonl -where verdict=NO-GO -group aircraft\n\n")
+		fmt.Fprintf(os.Stderr, "Numeric fields: %s\n", strings.Join(numeric, ", "))
+		fmt.Fprintf(os.Stderr, "Keys: %s\n\n", strings.Join(keys, ", "))
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *warehouseFile == "" {
+		fmt.Fprintf(os.Stderr, "otto query: -warehouse is required\n")
+		return 2
+	}
+	if *format != "table" && *format != "csv" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "otto query: unknown -format %q, expected table, csv or json\n", *format)
+		return 2
+	}
+
+	q := &warehouse.Query{GroupBy: groupBy}
+	for _, s := range where {
+		c, err := warehouse.ParseCondition(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto query: %v\n", err)
+			return 2
+		}
+		q.Where = append(q.Where, c)
+	}
+	var err error
+	if q.Aggregates, err = warehouse.ParseAggregates(*aggregates); err != nil {
+		fmt.Fprintf(os.Stderr, "otto query: %v\n", err)
+		return 2
+	}
+	for _, t := range []struct {
+		flag, value string
+		into        *time.Time
+	}{{"since", *since, &q.Since}, {"until", *until, &q.Until}} {
+		if t.value == "" {
+			continue
+		}
+		if *t.into, err = localtime.Parse(t.value, time.UTC); err != nil {
+			fmt.Fprintf(os.Stderr, "otto query: -%s: %v\n", t.flag, err)
+			return 2
+		}
+	}
+
+	results, err := warehouse.New(*warehouseFile).Results()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto query: %v\n", err)
+		return 1
+	}
+	rows, err := q.Run(results)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto query: %v\n", err)
+		return 2
+	}
+
+	header := append([]string(nil), groupBy...)
+	for _, a := range q.Aggregates {
+		header = append(header, a.String())
+	}
+	switch *format {
+	case "json":
+		out := make([]map[string]interface{}, 0, len(rows))
+		for _, row := range rows {
+			obj := make(map[string]interface{})
+			for i, key := range row.Keys {
+				obj[header[i]] = key
+			}
+			for i, v := range row.Values {
+				if !math.IsNaN(v) {
+					obj[header[len(row.Keys)+i]] = v
+				}
+			}
+			out = append(out, obj)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(out)
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(header)
+		for _, row := range rows {
+			w.Write(queryCells(row, func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }))
+		}
+		w.Flush()
+	default:
+		if len(rows) == 0 {
+			fmt.Println("No results match.")
+			return 0
+		}
+		table := [][]string{header}
+		for _, row := range rows {
+			table = append(table, queryCells(row, func(v float64) string {
+				if v == math.Trunc(v) {
+					return fmt.Sprintf("%.0f", v)
+				}
+				return fmt.Sprintf("%.1f", v)
+			}))
+		}
+		printColumns(table)
+	}
+	return 0
+}
+
+// queryCells formats a row's keys and values, leaving aggregates over no
+// values empty
+func queryCells(row warehouse.Row, format func(float64) string) []string {
+	cells := append([]string(nil), row.Keys...)
+	for _, v := range row.Values {
+		if math.IsNaN(v) {
+			cells = append(cells, "")
+		} else {
+			cells = append(cells, format(v))
+		}
+	}
+	return cells
+}
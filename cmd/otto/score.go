@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/feasibility"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// runScore rolls the runway, climb, fuel and weather margins of a flight
+// from the departure METAR into a 0-100 feasibility score with a breakdown
+func runScore(args []string) int {
+	fs := flag.NewFlagSet("score", flag.ContinueOnError)
+	airportID := fs.String("airport", "", "Departure airport identifier")
+	runwayID := fs.String("runway", "", "Departure runway end (default: the end with the most headwind)")
+	available := fs.Float64("available", 0, "Available takeoff distance in feet (default: the runway length)")
+	metar := fs.String("metar", "", "Raw METAR to use instead of fetching the latest")
+	weight := fs.Float64("weight", 0, "Takeoff weight in pounds")
+	altitude := fs.Float64("altitude", 0, "Cruise pressure altitude in feet (default: the field, for the climb at departure)")
+	fuelGal := fs.Float64("fuel-gal", 0, "Usable fuel on board in gallons, for the fuel margin")
+	tripTime := fs.Float64("trip-time", 0, "Planned flight time in minutes, for the fuel margin")
+	fuelFlow := fs.Float64("fuel-flow", 0, "Planning fuel flow in gph (default: the profile's)")
+	reserve := fs.Float64("reserve", 30, "Required fuel reserve in minutes, e.g. 45 at night")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch the METAR from the provider")
+	sources := addSourceFlags(fs)
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto score -airport KJYO -weight 2300 [-fuel-gal 48 -trip-time 90] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Runway:  0 when the takeoff distance does not fit, 100 when it needs half the runway.\n")
+		fmt.Fprintf(os.Stderr, "Climb:   0 at 100 fpm at the cruise altitude, 100 from 500 fpm.\n")
+		fmt.Fprintf(os.Stderr, "Fuel:    0 below the reserve at the destination, 100 from twice the reserve.\n")
+		fmt.Fprintf(os.Stderr, "Weather: the worst of the flight category (MVFR 50, IFR 0), the crosswind\n")
+		fmt.Fprintf(os.Stderr, "         (100 to half the demonstrated, 0 at it) and the gust spread (100 to 5 kt, 0 at 20 kt).\n")
+		fmt.Fprintf(os.Stderr, "Any factor at 0 makes the flight not feasible. The fuel factor needs -fuel-gal and -trip-time.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 2
+	}
+	if *airportID == "" || *weight <= 0 {
+		fmt.Fprintf(os.Stderr, "otto score: -airport and -weight are required\n")
+		return 2
+	}
+	if (*fuelGal > 0) != (*tripTime > 0) {
+		fmt.Fprintf(os.Stderr, "otto score: give both -fuel-gal and -trip-time for the fuel margin\n")
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 2
+	}
+	for _, v := range profile.WeightBalance.Check(wb.Loading{FuelGallons: *fuelGal}) {
+		if v.Station == wb.StationFuel {
+			fmt.Fprintf(os.Stderr, "otto score: %v\n", v)
+			return 2
+		}
+	}
+	provider, err := sources.airportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 1
+	}
+	airport, err := airports.Resolve(context.Background(), provider, *airportID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 1
+	}
+
+	raw := *metar
+	if raw == "" {
+		station := airport.ICAO
+		if station == "" {
+			station = airport.Ident
+		}
+		fetcher, err := sources.weatherFetcher("score", *netConfig, "", weather.DefaultTTL, *noCache)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+			return 1
+		}
+		report, err := fetcher.Fetch(context.Background(), weather.METAR, station)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+			return 1
+		}
+		raw = report.Raw
+	}
+	obs, err := weather.ParseMETAR(raw, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 1
+	}
+	if !obs.HasTemperature {
+		fmt.Fprintf(os.Stderr, "otto score: METAR %s has no temperature\n", obs.Station)
+		return 1
+	}
+
+	rwy, end, err := departureRunway(airport, *runwayID, obs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 2
+	}
+	distance := rwy.Length
+	if *available > 0 {
+		distance = *available
+	}
+
+	components := wind.Decompose(obs.Wind, wind.TrueDirection(end.TrueHeading), 0)
+	if obs.Variable {
+		components = wind.Components{Crosswind: obs.Wind.Speed}
+	}
+	gustCrosswind := abs(components.Crosswind)
+	if obs.Wind.Gust > obs.Wind.Speed && obs.Wind.Speed > 0 {
+		gustCrosswind *= obs.Wind.Gust / obs.Wind.Speed
+	}
+	pressureAlt := airport.Elevation
+	if obs.Altimeter > 0 {
+		pressureAlt = atmosphere.PressureAltitude(airport.Elevation, obs.Altimeter)
+	}
+
+	takeoff, err := profile.NewTakeoffCalculator().CalculateTakeoff(performance.TakeoffParams{
+		PressureAltitude: pressureAlt,
+		Temperature:      obs.Temperature,
+		Weight:           *weight,
+		WindComponent:    components.Headwind,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto score: %v\n", err)
+		return 1
+	}
+
+	// The cruise temperature keeps the field's deviation from standard
+	cruise := *altitude
+	if cruise < pressureAlt {
+		cruise = pressureAlt
+	}
+	cruiseTemp := obs.Temperature + atmosphere.ISATemperature(cruise) - atmosphere.ISATemperature(pressureAlt)
+	rate := profile.NewClimbCalculator().RateOfClimb(cruise, cruiseTemp)
+
+	factors := []feasibility.Factor{
+		feasibility.Runway(takeoff.TakeoffDistance, distance),
+		feasibility.Climb(rate),
+	}
+	if *fuelGal > 0 {
+		flow := *fuelFlow
+		if flow <= 0 {
+			flow = profile.WeightBalance.PlanningFuelFlow
+		}
+		if flow <= 0 {
+			fmt.Fprintf(os.Stderr, "otto score: the %s profile has no planning fuel flow; give -fuel-flow\n", profile.ID)
+			return 2
+		}
+		factors = append(factors, feasibility.Fuel(*fuelGal/flow*60-*tripTime, *reserve))
+	}
+	spread := 0.0
+	if obs.Wind.Gust > obs.Wind.Speed {
+		spread = obs.Wind.Gust - obs.Wind.Speed
+	}
+	factors = append(factors, feasibility.Weather(feasibility.Conditions{
+		Category:     obs.Category(),
+		Crosswind:    gustCrosswind,
+		MaxCrosswind: profile.Limits.MaxDemonstratedCrosswind,
+		GustSpread:   spread,
+	}))
+	score := feasibility.Combine(factors...)
+
+	fmt.Printf("\nMETAR:  %s\n", strings.TrimSpace(raw))
+	fmt.Printf("Flight: %s runway %s, %.0f lbs, cruise %.0f ft\n\n", airport.Ident, end.ID, *weight, cruise)
+	fmt.Printf("Feasibility: %.0f/100 %s\n\n", score.Total, score.Rating())
+	for _, f := range score.Factors {
+		fmt.Printf("  %-8s %3.0f  (weight %.0f)  %s\n", f.Name, f.Score, f.Weight, f.Detail)
+	}
+	if *fuelGal == 0 {
+		fmt.Printf("\nFuel not scored: give -fuel-gal and -trip-time.\n")
+	}
+	if advisories := aircraft.WeatherAdvisories(obs); len(advisories) > 0 {
+		fmt.Printf("\nWeather risks the chart ignores:\n")
+		for _, a := range advisories {
+			fmt.Printf("  %s\n", a)
+		}
+	}
+	fmt.Printf("\nThe score is a summary of margins, not a go/no-go decision.\n")
+	return 0
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+)
+
+// runSelftest checks every installed aircraft profile against its golden POH cases
+func runSelftest(args []string) int {
+	fs := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "List every case, not just failures")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto selftest [-v]\n\n")
+		fmt.Fprintf(os.Stderr, "Verifies that each installed aircraft profile reproduces its POH reference cases.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	failed := 0
+	for _, p := range aircraft.Profiles() {
+		results := p.SelfTest()
+
+		passed := 0
+		for _, r := range results {
+			if r.Pass() {
+				passed++
+			}
+		}
+
+		status := "PASS"
+		if passed != len(results) {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Printf("%s  %-10s %s (%d/%d cases)\n", status, p.ID, p.Name, passed, len(results))
+
+		for _, r := range results {
+			switch {
+			case !r.Pass():
+				fmt.Printf("      FAIL %s: %v\n", r.Case.Name, r.Err)
+			case *verbose:
+				fmt.Printf("      ok   %s: %.0f ft\n", r.Case.Name, r.Result.TakeoffDistance)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
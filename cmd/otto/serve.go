@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/margins"
+	"github.com/ryanbmilbourne/otto-perf/netconfig"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+	"github.com/ryanbmilbourne/otto-perf/server"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+	"github.com/ryanbmilbourne/otto-perf/webhook"
+)
+
+// nasrCycle is the length of an FAA NASR subscription cycle
+const nasrCycle = 28 * 24 * time.Hour
+
+// runServe serves the calculators over HTTP
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	probeStation := fs.String("probe-station", "KJYO", "Station whose METAR /readyz fetches to check the weather provider")
+	probeInterval := fs.Duration("probe-interval", server.DefaultProbeInterval, "Reuse a weather provider probe for this long")
+	maxAirportAge := fs.Duration("max-airport-age", 2*nasrCycle, "Report the airport data stale when it is older than this")
+	scenarioDir := fs.String("scenario-dir", "", "Directory of saved scenarios (JSON or YAML) to stream live results for")
+	reviewDir := fs.String("review-dir", "", "Directory to keep the scenario reviews in (default: reviews under -scenario-dir)")
+	pollInterval := fs.Duration("poll-interval", server.DefaultPollInterval, "How often live scenarios check for a new METAR")
+	fleetFile := fs.String("fleet", "", "Fleet CSV (tail, aircraft[, empty_weight, fuel_gal]) to match missions against")
+	webhookMapping := fs.String("webhook-mapping", "", "Mapping file (JSON) of scenario submissions pushed by other systems, with their callback and secret")
+	marginLog := fs.String("margin-log", "", "File (JSON lines) to record the saved scenarios' takeoff margins in, served at /metrics")
+	marginInterval := fs.Duration("margin-interval", server.DefaultMarginInterval, "How often to record the saved scenarios' takeoff margins")
+	profilesDir := fs.String("profiles-dir", "", "Directory of chart bundles (JSON) to load as aircraft profiles, reloaded as they change")
+	profilesInterval := fs.Duration("profiles-interval", server.DefaultProfileInterval, "How often to check -profiles-dir for new, changed or removed bundles")
+	nasrDir := fs.String("nasr-dir", "", "Directory of FAA NASR CSV files (default: embedded sample data)")
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+	shutdownTimeout := fs.Duration("shutdown-timeout", 30*time.Second, "On SIGINT or SIGTERM, wait this long for requests in flight to finish")
+	drainDelay := fs.Duration("drain-delay", 0, "On SIGINT or SIGTERM, fail /readyz and keep serving this long before closing the listener")
+	var corsOrigins stringList
+	fs.Var(&corsOrigins, "cors-origins", "Comma-separated browser origins allowed to call the API, or '*' for any")
+	traceFile := fs.String("trace", "", "Write a JSON line per request, weather fetch and calculation span to this file ('-' for stderr)")
+	sources := addSourceFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto serve [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Endpoints:\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/takeoff[?aircraft=ID]        Takeoff performance for a JSON parameter set\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/takeoff:batch[?aircraft=ID]  Takeoff performance for an array of parameter sets\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/wb[?aircraft=ID]             Weight and CG of a JSON loading\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/missions:match               The -fleet airframes that can fly a JSON mission, with margins\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/webhooks/scenarios           A submission mapped by -webhook-mapping; the briefing is posted to its callback\n")
+		fmt.Fprintf(os.Stderr, "  GET  /v1/scenarios/                   Names of the saved scenarios in -scenario-dir\n")
+		fmt.Fprintf(os.Stderr, "  GET  /v1/scenarios/NAME/events[?runway=ID&aircraft=ID]\n")
+		fmt.Fprintf(os.Stderr, "                                        Server-sent events with the scenario's takeoff performance per new METAR\n")
+		fmt.Fprintf(os.Stderr, "  GET  /v1/scenarios/NAME/review        The scenario's review status and history\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/scenarios/NAME/review        Record a review: {\"status\": \"approved\", \"reviewer\": ..., \"comment\": ...}\n")
+		fmt.Fprintf(os.Stderr, "  GET  /metrics[?since=DATE]             OpenMetrics of the latest -margin-log margins, or every one since DATE\n")
+		fmt.Fprintf(os.Stderr, "  GET  /v1/admin/profiles               The profiles served with their bundle versions, and the -profiles-dir bundles rejected\n")
+		fmt.Fprintf(os.Stderr, "  GET  /healthz                         200 while the process is up\n")
+		fmt.Fprintf(os.Stderr, "  GET  /readyz                          Weather provider and data age checks; 503 on failure\n\n")
+		fmt.Fprintf(os.Stderr, "Results are JSON, CSV or plain text by the Accept header (application/json, text/csv, text/plain).\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+		return 2
+	}
+
+	switch *traceFile {
+	case "":
+	case "-":
+		trace.SetExporter(trace.NewJSONExporter(os.Stderr))
+	default:
+		f, err := os.OpenFile(*traceFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		trace.SetExporter(trace.NewJSONExporter(f))
+	}
+
+	// The probe goes straight to the provider; through the cache it would
+	// only show what the cache holds
+	probe, err := sources.weatherFetcher("serve", *netConfig, "", 0, true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+		return 1
+	}
+	provider, err := sources.airportProvider(*nasrDir, *netConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+		return 1
+	}
+
+	// Live scenarios share a cache so dashboards watching the same airport
+	// fetch its METAR once per poll
+	scenarios, err := loadScenarios(*scenarioDir)
+	if err != nil {
+		printErrorLines("serve", err)
+		return 2
+	}
+	var fleet []aircraft.Tail
+	if *fleetFile != "" {
+		f, err := os.Open(*fleetFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+			return 2
+		}
+		fleet, err = aircraft.ReadFleetCSV(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto serve: %s: %v\n", *fleetFile, err)
+			return 2
+		}
+	}
+	var mapping *webhook.Mapping
+	var callbacks *http.Client
+	if *webhookMapping != "" {
+		if mapping, err = webhook.Load(*webhookMapping); err != nil {
+			fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+			return 2
+		}
+		// The briefings are posted with the timeout, proxy and API key of
+		// the webhooks integration
+		netCfg, err := netconfig.Load(*netConfig)
+		if err == nil {
+			callbacks, err = netCfg.HTTPClient("webhooks")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+			return 1
+		}
+	}
+	var reviews *scenario.ReviewLog
+	if *scenarioDir != "" {
+		if *reviewDir == "" {
+			*reviewDir = filepath.Join(*scenarioDir, "reviews")
+		}
+		reviews = scenario.NewReviewLog(*reviewDir)
+	}
+	var marginsLog *margins.Log
+	if *marginLog != "" {
+		marginsLog = margins.NewLog(*marginLog)
+	}
+	reports, err := sources.weatherFetcher("serve", *netConfig, "", *pollInterval, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+		return 1
+	}
+
+	// Only the NASR subscription carries a publication date; the embedded
+	// sample and recordings are reported as built in
+	airportData := server.Dataset{
+		Name:    "airports",
+		Updated: func() (time.Time, error) { return time.Time{}, nil },
+		MaxAge:  *maxAirportAge,
+	}
+	if dated, ok := provider.(interface{ Effective() (time.Time, error) }); ok {
+		airportData.Updated = dated.Effective
+	}
+
+	handler := server.New(server.Config{
+		Weather:         probe,
+		ProbeStation:    *probeStation,
+		ProbeInterval:   *probeInterval,
+		Datasets:        []server.Dataset{airportData},
+		AllowedOrigins:  corsOrigins,
+		Scenarios:       scenarios,
+		Reviews:         reviews,
+		Reports:         reports,
+		Airports:        provider,
+		Fleet:           fleet,
+		Webhook:         mapping,
+		Callbacks:       callbacks,
+		PollInterval:    *pollInterval,
+		Margins:         marginsLog,
+		MarginInterval:  *marginInterval,
+		ProfileDir:      *profilesDir,
+		ProfileInterval: *profilesInterval,
+	})
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Serve until a signal, then drain: fail readiness so the load
+	// balancer stops sending requests, stop accepting connections and let
+	// requests in flight finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "otto serve: listening on %s\n", *addr)
+	if marginsLog != nil {
+		go handler.RecordMargins(ctx)
+	}
+	if *profilesDir != "" {
+		go handler.WatchProfiles(ctx)
+	}
+
+	select {
+	case err := <-served:
+		fmt.Fprintf(os.Stderr, "otto serve: %v\n", err)
+		return 1
+	case <-ctx.Done():
+	}
+	stop()
+
+	fmt.Fprintf(os.Stderr, "otto serve: shutting down\n")
+	handler.Drain()
+	time.Sleep(*drainDelay)
+	shutdown, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		fmt.Fprintf(os.Stderr, "otto serve: requests still running after %s: %v\n", *shutdownTimeout, err)
+		return 1
+	}
+	return 0
+}
+
+// loadScenarios reads the JSON and YAML scenario files in a directory,
+// named by their file names without the extension
+func loadScenarios(dir string) (map[string]*scenario.Scenario, error) {
+	scenarios := make(map[string]*scenario.Scenario)
+	if dir == "" {
+		return scenarios, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		switch strings.ToLower(ext) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		s, err := scenario.Load(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		scenarios[strings.TrimSuffix(e.Name(), ext)] = s
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/sim"
+)
+
+// simPath is where the WebSocket of updates is served
+const simPath = "/v1/sim"
+
+// runSim bridges a flight simulator to the takeoff calculator, publishing
+// the performance for the simulated conditions as they change
+func runSim(args []string) int {
+	fs := flag.NewFlagSet("sim", flag.ContinueOnError)
+	source := fs.String("source", "xplane", "Simulator protocol: 'xplane' subscribes to X-Plane's datarefs, 'json' reads the state a helper sends (e.g. a SimConnect client for MSFS)")
+	listen := fs.String("listen", "127.0.0.1:49005", "UDP address to receive the simulator's datagrams on")
+	xplaneAddr := fs.String("xplane", "127.0.0.1:"+strconv.Itoa(sim.XPlanePort), "UDP address of X-Plane, to subscribe to its datarefs")
+	rate := fs.Int("rate", 5, "Times a second X-Plane sends the datarefs")
+	interval := fs.Duration("interval", sim.DefaultInterval, "How often to publish the performance at most")
+	var publish stringList
+	fs.Var(&publish, "publish", "Comma-separated UDP addresses (host:port) to send each update to as JSON")
+	addr := fs.String("addr", "", "Serve the updates to WebSocket clients at "+simPath+" on this address (e.g. :8090)")
+	var weight weightValue
+	fs.Var(&weight, "weight", "Weight in lbs (or kg with a suffix) in place of the simulator's, whose loading may not match the aircraft's")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto sim [-source xplane|json] [-publish host:port] [-addr :8090] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Reads the weather, weight and heading of a simulated aircraft and publishes the\n")
+		fmt.Fprintf(os.Stderr, "takeoff performance for them until interrupted, for training devices. The wind\n")
+		fmt.Fprintf(os.Stderr, "component is taken along the aircraft's heading, so line up on the runway. Each\n")
+		fmt.Fprintf(os.Stderr, "update is printed as a line, sent as JSON to the -publish addresses and to the\n")
+		fmt.Fprintf(os.Stderr, "WebSocket clients of -addr.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintf(os.Stderr, "otto sim: -interval must be positive\n")
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto sim: %v\n", err)
+		return 2
+	}
+
+	bridge := &sim.Bridge{
+		Calculator: profile.NewTakeoffCalculator(),
+		Interval:   *interval,
+		Weight:     float64(weight),
+		OnUpdate: func(u sim.Update) {
+			fmt.Println(formatSimUpdate(u))
+		},
+	}
+	for _, target := range publish {
+		udpAddr, err := net.ResolveUDPAddr("udp", target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto sim: -publish: %v\n", err)
+			return 2
+		}
+		bridge.Targets = append(bridge.Targets, udpAddr)
+	}
+	var xplane net.Addr
+	switch *source {
+	case "xplane":
+		if xplane, err = net.ResolveUDPAddr("udp", *xplaneAddr); err != nil {
+			fmt.Fprintf(os.Stderr, "otto sim: -xplane: %v\n", err)
+			return 2
+		}
+		bridge.Decoder = &sim.XPlaneDecoder{}
+	case "json":
+		bridge.Decoder = sim.JSONDecoder{}
+	default:
+		fmt.Fprintf(os.Stderr, "otto sim: -source must be xplane or json\n")
+		return 2
+	}
+
+	conn, err := net.ListenPacket("udp", *listen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto sim: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *addr != "" {
+		bridge.Hub = sim.NewHub()
+		mux := http.NewServeMux()
+		mux.Handle(simPath, bridge.Hub)
+		srv := &http.Server{
+			Addr:              *addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintf(os.Stderr, "otto sim: %v\n", err)
+				stop()
+			}
+		}()
+		defer srv.Close()
+		fmt.Fprintf(os.Stderr, "otto sim: serving updates on ws://%s%s\n", *addr, simPath)
+	}
+
+	if xplane != nil {
+		if err := sim.SubscribeXPlane(conn, xplane, *rate); err != nil {
+			fmt.Fprintf(os.Stderr, "otto sim: %v\n", err)
+			return 1
+		}
+		// Stop X-Plane sending once the bridge is gone
+		defer sim.SubscribeXPlane(conn, xplane, 0)
+		fmt.Fprintf(os.Stderr, "otto sim: subscribed to X-Plane at %s, listening on %s\n", xplane, conn.LocalAddr())
+	} else {
+		fmt.Fprintf(os.Stderr, "otto sim: listening for the simulator state on %s\n", conn.LocalAddr())
+	}
+
+	if err := bridge.Run(ctx, conn); err != nil {
+		fmt.Fprintf(os.Stderr, "otto sim: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// formatSimUpdate formats an update as one line: the inputs, then the
+// takeoff distance and speeds or why there are none
+func formatSimUpdate(u sim.Update) string {
+	p := u.Params
+	line := fmt.Sprintf("%s  PA %.0f ft, %.0f °C (DA %.0f ft), %.0f lbs, wind %+.0f kt", u.Time.Format("15:04:05"),
+		p.PressureAltitude, p.Temperature, u.DensityAltitude, p.Weight, p.WindComponent)
+	switch {
+	case u.Result != nil:
+		return line + fmt.Sprintf(": TO 50 ft %.0f ft, Vr %.0f, V50 %.0f", u.Result.TakeoffDistance, u.Result.LiftoffSpeed, u.Result.BarrierSpeed)
+	case len(u.Errors) > 0:
+		return line + ": " + u.Errors.Error()
+	default:
+		return line + ": " + u.Error
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// sourceFlags select where a command that fetches weather gets its data:
+// the network, made-up demo reports from a seed, or a recording
+type sourceFlags struct {
+	demo   *bool
+	seed   *int64
+	record *string
+	replay *string
+}
+
+// addSourceFlags registers -demo, -seed, -record and -replay on a command's flag set
+func addSourceFlags(fs *flag.FlagSet) sourceFlags {
+	return sourceFlags{
+		demo:   fs.Bool("demo", false, "Use made-up weather and the embedded airports instead of the network"),
+		seed:   fs.Int64("seed", 1, "Seed of the made-up weather in -demo mode"),
+		record: fs.String("record", "", "Directory to record the weather and airport data used, for -replay"),
+		replay: fs.String("replay", "", "Directory of a -record run to take the weather and airport data from"),
+	}
+}
+
+// check reports conflicting source flags
+func (s sourceFlags) check() error {
+	set := 0
+	for _, on := range []bool{*s.demo, *s.record != "", *s.replay != ""} {
+		if on {
+			set++
+		}
+	}
+	if set > 1 {
+		return fmt.Errorf("-demo, -record and -replay cannot be combined")
+	}
+	return nil
+}
+
+// weatherFetcher returns the fetcher for the selected source, wrapping
+// the network fetcher built by newWeatherFetcher when recording
+func (s sourceFlags) weatherFetcher(cmd, netConfig, cacheDir string, ttl time.Duration, noCache bool) (weather.Fetcher, error) {
+	switch {
+	case *s.demo:
+		fmt.Fprintf(os.Stderr, "otto %s: demo mode, the weather is made up (seed %d)\n", cmd, *s.seed)
+		return weather.NewDemoFetcher(*s.seed), nil
+	case *s.replay != "":
+		return &weather.Replay{Dir: *s.replay}, nil
+	}
+
+	fetcher, err := newWeatherFetcher(netConfig, cacheDir, ttl, noCache)
+	if err != nil || *s.record == "" {
+		return fetcher, err
+	}
+	return &weather.Recorder{Dir: *s.record, Fetcher: fetcher}, nil
+}
+
+// airportProvider returns the airports of the selected source: the
+// embedded data in demo mode, a recording, or the provider for nasrDir
+// with the airports it lacks looked up on the network
+func (s sourceFlags) airportProvider(nasrDir, netConfig string) (airports.Provider, error) {
+	switch {
+	case *s.demo:
+		return airports.Embedded()
+	case *s.replay != "":
+		return airports.NewReplayProvider(filepath.Join(*s.replay, "airports.json"))
+	}
+
+	provider, err := onlineAirportProvider(nasrDir, netConfig)
+	if err != nil || *s.record == "" {
+		return provider, err
+	}
+	return &airports.Recorder{Path: filepath.Join(*s.record, "airports.json"), Provider: provider}, nil
+}
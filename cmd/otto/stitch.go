@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// runStitch composes a takeoff chart printed across several POH figures
+// into one chart, checking the distances are continuous at the seams
+func runStitch(args []string) int {
+	fs := flag.NewFlagSet("stitch", flag.ContinueOnError)
+	outFile := fs.String("o", "", "Write the stitched barrier chart to this file (default: stdout)")
+	rollFile := fs.String("ground-roll", "", "Write the stitched ground roll chart to this file")
+	printSchema := fs.Bool("print-schema", false, "Print the JSON Schema of stitched chart files and exit")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto stitch [options] <stitched-chart.json>\n\n")
+		fmt.Fprintf(os.Stderr, "The file lists the panels of the chart, each a complete takeoff_chart of its\n")
+		fmt.Fprintf(os.Stderr, "figure, under \"barrier\" and optionally \"ground_roll\". Panels must split one\n")
+		fmt.Fprintf(os.Stderr, "axis, and their distances must agree within 3%% where they meet.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *printSchema {
+		schema, _ := performance.Schema("stitched_takeoff_chart")
+		os.Stdout.Write(schema)
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto stitch: %v\n", err)
+		return 2
+	}
+	var chart performance.StitchedTakeoffChart
+	if err := json.Unmarshal(data, &chart); err != nil {
+		fmt.Fprintf(os.Stderr, "otto stitch: %s: %v\n", fs.Arg(0), err)
+		return 2
+	}
+	barrier, roll, err := chart.Stitch()
+	if err != nil {
+		printErrorLines("stitch", err)
+		return 1
+	}
+	if roll != nil && *rollFile == "" {
+		fmt.Fprintf(os.Stderr, "otto stitch: the ground roll panels were checked; use -ground-roll to write their chart\n")
+	}
+	if roll == nil && *rollFile != "" {
+		fmt.Fprintf(os.Stderr, "otto stitch: %s has no ground roll panels\n", fs.Arg(0))
+		return 2
+	}
+
+	rows := [][]string{{"Part", "Panels", "Altitudes", "Temperatures", "Weights"}}
+	for _, part := range []struct {
+		name   string
+		panels int
+		chart  *performance.TakeoffChart
+	}{{"barrier", len(chart.Barrier), barrier}, {"ground roll", len(chart.GroundRoll), roll}} {
+		if part.chart == nil {
+			continue
+		}
+		c := part.chart
+		rows = append(rows, []string{
+			part.name,
+			fmt.Sprint(part.panels),
+			fmt.Sprintf("%d, %g–%g", len(c.Altitudes), c.Altitudes[0], c.Altitudes[len(c.Altitudes)-1]),
+			fmt.Sprintf("%d, %g–%g", len(c.Temperatures), c.Temperatures[0], c.Temperatures[len(c.Temperatures)-1]),
+			fmt.Sprintf("%d, %g–%g", len(c.Weights), c.Weights[0], c.Weights[len(c.Weights)-1]),
+		})
+	}
+	fprintColumns(os.Stderr, rows)
+
+	if err := writeChart(*outFile, barrier); err != nil {
+		fmt.Fprintf(os.Stderr, "otto stitch: %v\n", err)
+		return 1
+	}
+	if *rollFile != "" {
+		if err := writeChart(*rollFile, roll); err != nil {
+			fmt.Fprintf(os.Stderr, "otto stitch: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// writeChart writes a chart as indented JSON to a file, or to stdout when
+// the name is empty
+func writeChart(name string, chart *performance.TakeoffChart) error {
+	out := os.Stdout
+	if name != "" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(chart)
+}
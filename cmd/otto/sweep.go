@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/batch"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// runSweep computes the takeoff distance over every combination of the
+// given altitudes, temperatures, weights and winds on a pool of workers
+func runSweep(args []string) int {
+	fs := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	altitudes := rangeList{0}
+	temperatures := rangeList{15}
+	weights := rangeList{2325}
+	winds := rangeList{0}
+	fs.Var(&altitudes, "altitude", "Pressure altitudes in feet, START:END:STEP or a list")
+	fs.Var(&temperatures, "temp-c", "Temperatures in °C, START:END:STEP or a list")
+	fs.Var(&weights, "weight", "Weights in pounds, START:END:STEP or a list")
+	fs.Var(&winds, "wind", "Wind components in knots (negative for tailwind), START:END:STEP or a list")
+	workers := fs.Int("workers", 0, "Number of parallel workers (default: one per CPU)")
+	progress := fs.Bool("progress", false, "Show a progress bar on stderr")
+	format := fs.String("format", "csv", "Output format: csv or ndjson (one JSON object per line)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto sweep -altitude 0:7000:100 -temp-c -20:40:1 -weight 1800:2325:25 [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Prints one row per combination as it is computed. Points outside the chart have an error instead of results.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto sweep: %v\n", err)
+		return 2
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	var write func(performance.TakeoffParams, batch.Result[*performance.TakeoffResult]) error
+	switch *format {
+	case "csv":
+		write = csvSweepWriter(out)
+	case "ndjson":
+		write = ndjsonSweepWriter(out)
+	default:
+		fmt.Fprintf(os.Stderr, "otto sweep: unknown format %q (want csv or ndjson)\n", *format)
+		return 2
+	}
+
+	// The grid is indexed rather than built up front, with the last axis
+	// varying fastest, so a run of any size uses a fixed amount of memory
+	total := len(altitudes) * len(temperatures) * len(weights) * len(winds)
+	point := func(i int) performance.TakeoffParams {
+		wind := winds[i%len(winds)]
+		i /= len(winds)
+		weight := weights[i%len(weights)]
+		i /= len(weights)
+		temperature := temperatures[i%len(temperatures)]
+		i /= len(temperatures)
+		return performance.TakeoffParams{
+			PressureAltitude: altitudes[i],
+			Temperature:      temperature,
+			Weight:           weight,
+			WindComponent:    wind,
+		}
+	}
+
+	opts := batch.Options{Workers: *workers}
+	if *progress {
+		started := time.Now()
+		opts.Progress = func(done, total int) {
+			printProgress(done, total, time.Since(started))
+		}
+	}
+	calculator := profile.NewTakeoffCalculator()
+	err = batch.Stream(context.Background(), total, func(i int) (*performance.TakeoffResult, error) {
+		return calculator.CalculateTakeoff(point(i))
+	}, func(r batch.Result[*performance.TakeoffResult]) error {
+		return write(point(r.Index), r)
+	}, opts)
+	if err == nil {
+		err = out.Flush()
+	}
+	if *progress {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto sweep: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// csvSweepWriter writes a header and returns a function writing one CSV
+// row per sweep point
+func csvSweepWriter(out io.Writer) func(performance.TakeoffParams, batch.Result[*performance.TakeoffResult]) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"pressure_altitude", "temperature_c", "weight", "wind_component",
+		"takeoff_distance", "liftoff_speed", "barrier_speed", "error"})
+	number := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return func(p performance.TakeoffParams, r batch.Result[*performance.TakeoffResult]) error {
+		row := []string{number(p.PressureAltitude), number(p.Temperature), number(p.Weight), number(p.WindComponent)}
+		if r.Err != nil {
+			row = append(row, "", "", "", r.Err.Error())
+		} else {
+			row = append(row, strconv.FormatFloat(r.Value.TakeoffDistance, 'f', 0, 64),
+				strconv.FormatFloat(r.Value.LiftoffSpeed, 'f', 1, 64),
+				strconv.FormatFloat(r.Value.BarrierSpeed, 'f', 1, 64), "")
+		}
+		// out is already buffered, so the csv writer shares its buffer
+		// and rows reach it without a flush
+		return w.Write(row)
+	}
+}
+
+// sweepRow is one line of ndjson sweep output
+type sweepRow struct {
+	Params performance.TakeoffParams  `json:"params"`
+	Result *performance.TakeoffResult `json:"result,omitempty"`
+	Error  string                     `json:"error,omitempty"`
+}
+
+// ndjsonSweepWriter returns a function writing one JSON object per line
+// per sweep point
+func ndjsonSweepWriter(out io.Writer) func(performance.TakeoffParams, batch.Result[*performance.TakeoffResult]) error {
+	enc := json.NewEncoder(out)
+	return func(p performance.TakeoffParams, r batch.Result[*performance.TakeoffResult]) error {
+		row := sweepRow{Params: p, Result: r.Value}
+		if r.Err != nil {
+			row.Result, row.Error = nil, r.Err.Error()
+		}
+		return enc.Encode(row)
+	}
+}
+
+// printProgress redraws a progress bar on stderr
+func printProgress(done, total int, elapsed time.Duration) {
+	const width = 40
+	fraction := 1.0
+	if total > 0 {
+		fraction = float64(done) / float64(total)
+	}
+	filled := int(fraction * width)
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %3.0f%% %d/%d %s", strings.Repeat("#", filled), strings.Repeat(" ", width-filled),
+		fraction*100, done, total, elapsed.Round(100*time.Millisecond))
+}
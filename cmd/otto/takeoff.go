@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/corrections"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+)
+
+// runTakeoff computes the takeoff distance and speeds for one set of
+// conditions, or for a built-in training scenario, for ground school
+// demonstrations. The takeoff command has the full briefing.
+func runTakeoff(args []string) int {
+	fs := flag.NewFlagSet("takeoff", flag.ContinueOnError)
+	presetName := fs.String("preset", "", "Built-in training scenario to run, e.g. hot-high, with any other inputs given overriding it ('list' to list them)")
+	pressureAlt := fs.Float64("altitude", 0, "Pressure altitude in feet")
+	tempC := fs.Float64("temp-c", 15, "Temperature in °C")
+	tempF := fs.Float64("temp-f", 0, "Temperature in °F (overrides temp-c if provided)")
+	weight := weightValue(2325)
+	fs.Var(&weight, "weight", "Takeoff weight in lbs (or kg with a suffix)")
+	windComponent := fs.Float64("wind", 0, "Wind component in knots (positive for headwind, negative for tailwind)")
+	available := fs.Float64("available", 0, "Runway available in feet, for the margin")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	variantID := fs.String("variant", "", "Engine or STC variant of the aircraft (see the aircraft profile)")
+	correctionsFile := fs.String("corrections", "", "Operator correction rules file (default: corrections.json in the user config directory, if present)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto takeoff -preset hot-high [options]\n")
+		fmt.Fprintf(os.Stderr, "       otto takeoff -altitude 1500 -temp-c 25 -weight 2200 [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Inputs given with flags override the preset's, so one change can be compared with\n")
+		fmt.Fprintf(os.Stderr, "the scenario. A preset's runway, and its surface for the operator corrections,\n")
+		fmt.Fprintf(os.Stderr, "stand in for -available unless it is given.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *presetName == "list" {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, p := range scenario.Presets() {
+			fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Title)
+		}
+		w.Flush()
+		return 0
+	}
+
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	if given["temp-f"] {
+		*tempC = performance.ConvertFahrenheitToCelsius(*tempF)
+	}
+
+	// A preset fills in the inputs not given on the command line
+	var preset *scenario.Preset
+	if *presetName != "" {
+		var err error
+		if preset, err = scenario.LookupPreset(*presetName); err != nil {
+			fmt.Fprintf(os.Stderr, "otto takeoff: %v\n", err)
+			return 2
+		}
+		s := preset.Scenario
+		if s.PressureAltitude != nil && !given["altitude"] {
+			*pressureAlt = *s.PressureAltitude
+		}
+		if s.TemperatureC != nil && !given["temp-c"] && !given["temp-f"] {
+			*tempC = *s.TemperatureC
+		}
+		if s.Weight != nil && !given["weight"] {
+			weight = weightValue(*s.Weight)
+		}
+		if s.WindComponent != nil && !given["wind"] {
+			*windComponent = *s.WindComponent
+		}
+		if !given["available"] {
+			*available = preset.Available
+		}
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto takeoff: %v\n", err)
+		return 2
+	}
+	rules, err := corrections.Load(*correctionsFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto takeoff: %v\n", err)
+		return 2
+	}
+	conditions := corrections.Conditions{Aircraft: profile.ID}
+	if preset != nil {
+		conditions.Surface = preset.Surface
+	}
+	profile = rules.Apply(profile, conditions)
+
+	params := performance.TakeoffParams{
+		PressureAltitude: *pressureAlt,
+		Temperature:      *tempC,
+		Weight:           float64(weight),
+		WindComponent:    *windComponent,
+	}
+	result, err := profile.NewTakeoffCalculator().CalculateTakeoff(params)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto takeoff: %v\n", err)
+		return 1
+	}
+
+	title := profile.Name + " Takeoff"
+	if preset != nil {
+		title += ": " + preset.Title
+	}
+	fmt.Printf("\n%s\n\n", title)
+	fmt.Printf("Pressure altitude: %.0f ft, %.0f°C (density altitude %.0f ft)\n",
+		params.PressureAltitude, params.Temperature, atmosphere.DensityAltitude(params.PressureAltitude, params.Temperature))
+	fmt.Printf("Weight: %.0f lbs, wind %s\n\n", params.Weight, formatWind(params.WindComponent))
+	fmt.Printf("Takeoff distance over 50 ft: %.0f ft\n", result.TakeoffDistance)
+	if result.GroundRoll > 0 {
+		fmt.Printf("Ground roll: %.0f ft\n", result.GroundRoll)
+	}
+	fmt.Printf("Lift-off speed: %.0f KIAS, 50 ft speed: %.0f KIAS\n", result.LiftoffSpeed, result.BarrierSpeed)
+	for _, a := range result.Adjustments {
+		fmt.Printf("Adjusted: %s takeoff distance, not from the POH chart\n", a)
+	}
+
+	if *available > 0 {
+		runway := fmt.Sprintf("Runway: %.0f ft", *available)
+		if preset != nil && preset.Surface != "" {
+			runway += " " + preset.Surface
+		}
+		if margin := *available - result.TakeoffDistance; margin < 0 {
+			runway += fmt.Sprintf(", %.0f ft short", -margin)
+		} else {
+			runway += fmt.Sprintf(", %.0f ft left over", margin)
+		}
+		fmt.Printf("\n%s\n", runway)
+	}
+	if preset != nil {
+		fmt.Printf("\n%s\n", wrapText(preset.Lesson, 72))
+	}
+	return 0
+}
+
+// wrapText wraps text at columns, breaking between words
+func wrapText(text string, columns int) string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > columns {
+			lines = append(lines, line)
+			line = word
+			continue
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return strings.Join(append(lines, line), "\n")
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+)
+
+// validateReport is the structured output of the validate command
+type validateReport struct {
+	Valid  bool                         `json:"valid"`
+	Errors performance.ValidationErrors `json:"errors"`
+}
+
+// runValidate checks a scenario file and/or flag set without computing performance.
+// It exits 0 when the inputs are valid, 1 when they are not and 2 on usage errors.
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	scenarioFile := fs.String("scenario", "", "Scenario file (JSON or YAML) to validate")
+	printSchema := fs.Bool("print-schema", false, "Print the JSON Schema of scenario files and exit")
+	pressureAlt := fs.Float64("altitude", 0, "Pressure altitude in feet")
+	tempC := fs.Float64("temp-c", 0, "Temperature in °C")
+	tempF := fs.Float64("temp-f", 0, "Temperature in °F (overrides temp-c if provided)")
+	weight := fs.Float64("weight", 0, "Aircraft weight in pounds")
+	windComponent := fs.Float64("wind", 0, "Wind component in knots (positive for headwind, negative for tailwind)")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto validate [-scenario file.json] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Flags override values from the scenario file.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *printSchema {
+		os.Stdout.Write(scenario.Schema())
+		return 0
+	}
+
+	// Start from the scenario file, if any, then apply explicitly set flags
+	s := &scenario.Scenario{}
+	if *scenarioFile != "" {
+		loaded, err := scenario.Load(*scenarioFile)
+		if err != nil {
+			printErrorLines("validate", err)
+			return 2
+		}
+		s = loaded
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "altitude":
+			s.PressureAltitude = pressureAlt
+		case "temp-c":
+			// The file's Fahrenheit value would otherwise win
+			s.TemperatureC, s.TemperatureF = tempC, nil
+		case "temp-f":
+			s.TemperatureC, s.TemperatureF = nil, tempF
+		case "weight":
+			s.Weight = weight
+		case "wind":
+			s.WindComponent = windComponent
+		}
+	})
+
+	errs := s.Validate(performance.NewTakeoffCalculator())
+	report := validateReport{Valid: len(errs) == 0, Errors: errs}
+	if report.Errors == nil {
+		report.Errors = performance.ValidationErrors{}
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else if report.Valid {
+		fmt.Println("Inputs are complete and within the chart envelope.")
+	} else {
+		for _, err := range report.Errors {
+			fmt.Printf("%s: %s\n", err.Field, err.Message)
+		}
+	}
+
+	if !report.Valid {
+		return 1
+	}
+	return 0
+}
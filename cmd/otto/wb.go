@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// runWB builds up the weight and CG of a loading and reports the
+// categories it qualifies for, without any weather or runway
+func runWB(args []string) int {
+	fs := flag.NewFlagSet("wb", flag.ContinueOnError)
+	fuelGallons := fs.Float64("fuel-gal", 0, "Usable fuel in US gallons")
+	fuelState := fs.String("fuel", "", "Fuel state instead of -fuel-gal: full, a profile preset such as tabs, or gallons, with optional +Nhr/+Ngal")
+	var people weightList
+	fs.Var(&people, "people", "Comma-separated occupant weights in pounds (or with a kg suffix) in seat order, e.g. 170,72kg; 0 for an empty seat")
+	var baggage weightValue
+	fs.Var(&baggage, "bags", "Baggage weight in pounds, or with a kg suffix, e.g. 20kg")
+	emptyWeight := fs.Float64("empty-weight", 0, "Basic empty weight in pounds from the airframe's W&B record (default from the aircraft profile)")
+	emptyArm := fs.Float64("empty-arm", 0, "Empty CG in inches aft of the datum from the airframe's W&B record (default from the aircraft profile)")
+	taxiFuel := fs.Float64("taxi-fuel", -1, "Taxi and run-up fuel allowance in US gallons (default from the aircraft profile)")
+	aircraftID := fs.String("aircraft", "pa28-161", "Aircraft profile")
+	var equipment stringList
+	fs.Var(&equipment, "equipment", "Comma-separated optional equipment installed, e.g. adsb-out")
+	jsonOutput := fs.Bool("json", false, "Print the result as JSON")
+	worksheet := fs.String("worksheet", "", "Print a filled-in W&B worksheet: 'text', or 'html' to print or save as PDF from a browser")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto wb (-fuel-gal 48 | -fuel tabs) [-people 170,160] [-bags 40] [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Shows the ramp and takeoff weight, the takeoff CG and the categories the loading\n")
+		fmt.Fprintf(os.Stderr, "qualifies for. Exits 1 when a station limit is exceeded or the CG fits no envelope.\n")
+		fmt.Fprintf(os.Stderr, "-worksheet prints the POH-style table of stations, weights, arms and moments.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if *worksheet != "" && *worksheet != "text" && *worksheet != "html" {
+		fmt.Fprintf(os.Stderr, "otto wb: unknown -worksheet %q, expected text or html\n", *worksheet)
+		return 2
+	}
+
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithEquipment(equipment)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto wb: %v\n", err)
+		return 2
+	}
+
+	weights := profile.WeightBalance
+	if *emptyWeight > 0 {
+		// The record's empty weight excludes equipment selected with -equipment
+		weights.EmptyWeight = *emptyWeight
+		for _, e := range profile.Installed {
+			weights.EmptyWeight += e.Weight
+		}
+	}
+	if *emptyArm > 0 {
+		weights.EmptyArm = *emptyArm
+	}
+	if *taxiFuel >= 0 {
+		weights.TaxiFuel = *taxiFuel
+	}
+	if *fuelState != "" {
+		if *fuelGallons, err = weights.FuelState(*fuelState); err != nil {
+			fmt.Fprintf(os.Stderr, "otto wb: %v\n", err)
+			return 2
+		}
+	}
+
+	loading := wb.Loading{FuelGallons: *fuelGallons, People: people, Baggage: float64(baggage)}
+	summary, err := weights.Compute(loading)
+	var violations wb.Violations
+	if errors.As(err, &violations) {
+		fmt.Fprintf(os.Stderr, "otto wb: loading outside the %s limits:\n", profile.Name)
+		for _, v := range violations {
+			fmt.Fprintf(os.Stderr, "  - %s\n", v.Message)
+		}
+		return 1
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "otto wb: %v\n", err)
+		return 1
+	}
+
+	if *worksheet != "" {
+		sheet, err := weights.Worksheet(loading)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto wb: %v\n", err)
+			return 1
+		}
+		if *worksheet == "html" {
+			err = worksheetTemplate.Execute(os.Stdout, worksheetPage{
+				Aircraft:  profile.Name,
+				Generated: time.Now().Format("2 January 2006"),
+				Worksheet: sheet,
+			})
+		} else {
+			printWorksheet(profile.Name, sheet)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "otto wb: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(summary)
+		return 0
+	}
+
+	rows := [][]string{
+		{"Empty weight", fmt.Sprintf("%.0f lbs", summary.EmptyWeight)},
+		{"Fuel", fmt.Sprintf("%.0f lbs", summary.Fuel), fmt.Sprintf("(%.1f gal)", summary.FuelGallons)},
+		{"People", fmt.Sprintf("%.0f lbs", summary.People)},
+		{"Baggage", fmt.Sprintf("%.0f lbs", summary.Baggage)},
+		{"Ramp weight", fmt.Sprintf("%.0f lbs", summary.RampWeight)},
+		{"Taxi fuel", fmt.Sprintf("%.0f lbs", -summary.TaxiFuel)},
+		{"Takeoff weight", fmt.Sprintf("%.0f lbs", summary.TakeoffWeight)},
+	}
+	if summary.TakeoffCG > 0 {
+		rows = append(rows, []string{"Takeoff CG", fmt.Sprintf("%.1f in", summary.TakeoffCG)})
+	}
+	printColumns(rows)
+
+	if len(summary.Categories) > 0 {
+		fmt.Println()
+	}
+	for _, q := range summary.Categories {
+		if q.Qualified {
+			fmt.Printf("%s category: qualifies\n", q.Category)
+		} else {
+			fmt.Printf("%s category: no, %s\n", q.Category, q.Reason)
+		}
+	}
+	return 0
+}
+
+// printWorksheet prints a worksheet as a table in the layout of the POH
+// sample loading problem
+func printWorksheet(name string, sheet *wb.Worksheet) {
+	fmt.Printf("Weight and Balance: %s\n\n", name)
+	row := func(l wb.WorksheetLine) []string {
+		return []string{l.Station, fmt.Sprintf("%.0f", l.Weight), fmt.Sprintf("%.1f", l.Arm), fmt.Sprintf("%.0f", l.Moment)}
+	}
+	rows := [][]string{{"Station", "Weight (lbs)", "Arm (in)", "Moment (in-lbs)"}}
+	for _, l := range sheet.Stations {
+		rows = append(rows, row(l))
+	}
+	rows = append(rows, row(sheet.Ramp), row(sheet.TaxiFuel), row(sheet.Takeoff))
+	printColumns(rows)
+
+	fmt.Printf("\nThe takeoff CG is %.1f in aft of the datum.\n", sheet.Takeoff.Arm)
+	for _, q := range sheet.Categories {
+		if q.Qualified {
+			fmt.Printf("%s category: qualifies\n", q.Category)
+		} else {
+			fmt.Printf("%s category: no, %s\n", q.Category, q.Reason)
+		}
+	}
+}
+
+// worksheetPage is the data of the HTML worksheet
+type worksheetPage struct {
+	Aircraft  string
+	Generated string
+	*wb.Worksheet
+}
+
+// worksheetTemplate lays out the worksheet for printing on one page
+var worksheetTemplate = template.Must(template.New("worksheet").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Aircraft}} weight and balance</title>
+<style>
+@page { size: letter; margin: 12mm; }
+body { font-family: sans-serif; font-size: 11pt; max-width: 190mm; margin: auto; }
+h1 { font-size: 16pt; }
+table { border-collapse: collapse; margin: 6px 0 12px; }
+th, td { border: 1px solid #000; padding: 2px 8px; text-align: right; }
+th.station { text-align: left; }
+tr.total th, tr.total td { font-weight: bold; border-top: 2px solid #000; }
+.note { font-size: 9pt; }
+</style>
+</head>
+<body>
+<h1>Weight and Balance: {{.Aircraft}}</h1>
+<table>
+<tr><th class="station">Station</th><th>Weight (lbs)</th><th>Arm (in)</th><th>Moment (in-lbs)</th></tr>
+{{range .Stations}}<tr><th class="station">{{.Station}}</th><td>{{printf "%.0f" .Weight}}</td><td>{{printf "%.1f" .Arm}}</td><td>{{printf "%.0f" .Moment}}</td></tr>
+{{end}}{{with .Ramp}}<tr class="total"><th class="station">{{.Station}}</th><td>{{printf "%.0f" .Weight}}</td><td>{{printf "%.1f" .Arm}}</td><td>{{printf "%.0f" .Moment}}</td></tr>{{end}}
+{{with .TaxiFuel}}<tr><th class="station">{{.Station}}</th><td>{{printf "%.0f" .Weight}}</td><td>{{printf "%.1f" .Arm}}</td><td>{{printf "%.0f" .Moment}}</td></tr>{{end}}
+{{with .Takeoff}}<tr class="total"><th class="station">{{.Station}}</th><td>{{printf "%.0f" .Weight}}</td><td>{{printf "%.1f" .Arm}}</td><td>{{printf "%.0f" .Moment}}</td></tr>{{end}}
+</table>
+<p>The takeoff CG is {{printf "%.1f" .Takeoff.Arm}} in aft of the datum.</p>
+<ul>
+{{range .Categories}}<li>{{.Category}} category: {{if .Qualified}}qualifies{{else}}no, {{.Reason}}{{end}}</li>
+{{end}}</ul>
+<p class="note">Generated {{.Generated}} from the aircraft profile; check the arms and limits against the
+airframe's current weight and balance record and POH before flight.</p>
+</body>
+</html>
+`))
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/netconfig"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// runWeather fetches and prints a raw weather product, using the on-disk cache
+func runWeather(args []string) int {
+	fs := flag.NewFlagSet("weather", flag.ContinueOnError)
+	station := fs.String("station", "", "Station identifier (ICAO for METAR/TAF, three-letter site for winds aloft)")
+	product := fs.String("product", "metar", "Product to fetch: 'metar', 'taf' or 'windtemp'")
+	ttl := fs.Duration("ttl", weather.DefaultTTL, "Reuse cached reports fetched within this duration")
+	cacheDir := fs.String("cache-dir", "", "Cache directory (default: user cache directory)")
+	noCache := fs.Bool("no-cache", false, "Always fetch from the provider")
+	sources := addSourceFlags(fs)
+	netConfig := fs.String("net-config", "", "Network config file (default: user config directory)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: otto weather -station KJYO [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if err := sources.check(); err != nil {
+		fmt.Fprintf(os.Stderr, "otto weather: %v\n", err)
+		return 2
+	}
+	if *station == "" {
+		fs.Usage()
+		return 2
+	}
+
+	fetcher, err := sources.weatherFetcher("weather", *netConfig, *cacheDir, *ttl, *noCache)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto weather: %v\n", err)
+		return 1
+	}
+
+	report, err := fetcher.Fetch(context.Background(), weather.Product(*product), *station)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "otto weather: %v\n", err)
+		return 1
+	}
+
+	if report.Stale {
+		fmt.Fprintf(os.Stderr, "WARNING: provider unreachable, showing cached report fetched %s\n",
+			report.Fetched.Format("2006-01-02 15:04Z"))
+	}
+	fmt.Println(report.Raw)
+	return 0
+}
+
+// newWeatherFetcher builds a weather client, behind the on-disk cache
+// unless noCache is set
+func newWeatherFetcher(netConfig, cacheDir string, ttl time.Duration, noCache bool) (weather.Fetcher, error) {
+	client, err := newWeatherClient(netConfig)
+	if err != nil {
+		return nil, err
+	}
+	if noCache {
+		return client, nil
+	}
+
+	if cacheDir == "" {
+		if cacheDir, err = weather.DefaultCacheDir(); err != nil {
+			return nil, err
+		}
+	}
+	return weather.NewCache(cacheDir, ttl, client), nil
+}
+
+// newWeatherClient builds a weather client from the network configuration
+func newWeatherClient(configPath string) (*weather.Client, error) {
+	cfg, err := netconfig.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := cfg.HTTPClient("weather")
+	if err != nil {
+		return nil, err
+	}
+
+	return &weather.Client{
+		BaseURL:    cfg.BaseURL("weather", weather.DefaultBaseURL),
+		HTTPClient: httpClient,
+	}, nil
+}
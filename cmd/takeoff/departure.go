@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"time"
+	
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+)
+
+// departure is the planned departure time and the airport's local zone
+type departure struct {
+	Time     time.Time
+	Location *time.Location // nil when no airport was given
+}
+
+// String formats the departure in local time and Zulu
+func (d *departure) String() string {
+	return localtime.Format(d.Time, d.Location)
+}
+
+// resolveDeparture parses a departure time. Times without a zone are local
+// to the departure airport, so they need -airport.
+func resolveDeparture(value, airportID string) (*departure, error) {
+	d := &departure{}
+	if airportID != "" {
+		provider, err := airports.Embedded()
+		if err != nil {
+			return nil, err
+		}
+		airport, err := airports.Resolve(context.Background(), provider, airportID)
+		if err != nil {
+			return nil, err
+		}
+		if d.Location, err = airport.Location(); err != nil {
+			return nil, err
+		}
+	}
+	
+	t, err := localtime.Parse(value, d.Location)
+	if err != nil {
+		return nil, err
+	}
+	d.Time = t
+	return d, nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// dual is the unit system that prints every quantity in both imperial and
+// metric, for students who learned in metric and train in the US. The
+// unit of the POH and ATC comes first, with the metric in parentheses.
+const dual = "dual"
+
+// inMeters returns " (N m)" for a height or distance in feet with dual
+// units, otherwise ""
+func inMeters(feet float64, unitSystem string) string {
+	if unitSystem != dual {
+		return ""
+	}
+	return fmt.Sprintf(" (%.0f m)", units.FeetToMeters(feet))
+}
+
+// inKilograms returns " (N kg)" for a weight in pounds with dual units,
+// otherwise ""
+func inKilograms(pounds float64, unitSystem string) string {
+	if unitSystem != dual {
+		return ""
+	}
+	return fmt.Sprintf(" (%.0f kg)", units.PoundsToKilograms(pounds))
+}
+
+// inKilometersPerHour returns " (N km/h)" for a speed in knots with dual
+// units, otherwise ""
+func inKilometersPerHour(knots float64, unitSystem string) string {
+	if unitSystem != dual {
+		return ""
+	}
+	return fmt.Sprintf(" (%.0f km/h)", units.NauticalToKilometers(knots))
+}
+
+// inLiters returns " (N L)" for a fuel volume in US gallons with dual
+// units, otherwise ""
+func inLiters(gallons float64, unitSystem string) string {
+	if unitSystem != dual {
+		return ""
+	}
+	return fmt.Sprintf(" (%.1f L)", units.GallonsToLiters(gallons))
+}
+
+// inMetersPerSecondSquared returns " (N m/s²)" for an acceleration in knots
+// per second with dual units, otherwise ""
+func inMetersPerSecondSquared(knotsPerSecond float64, unitSystem string) string {
+	if unitSystem != dual {
+		return ""
+	}
+	return fmt.Sprintf(" (%.1f m/s²)", units.NauticalToKilometers(knotsPerSecond) / 3.6)
+}
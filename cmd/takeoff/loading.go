@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// floatList is a flag.Value holding a comma-separated list of numbers
+type floatList []float64
+
+// String implements flag.Value
+func (l *floatList) String() string {
+	parts := make([]string, len(*l))
+	for i, v := range *l {
+		parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value
+func (l *floatList) Set(value string) error {
+	*l = nil
+	for _, part := range strings.Split(value, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", part)
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// weightList is a flag.Value holding a comma-separated list of weights in
+// pounds, each optionally suffixed with its unit, e.g. 77kg,160
+type weightList []float64
+
+// String implements flag.Value
+func (l *weightList) String() string {
+	return (*floatList)(l).String()
+}
+
+// Set implements flag.Value
+func (l *weightList) Set(value string) error {
+	*l = nil
+	for _, part := range strings.Split(value, ",") {
+		v, err := units.ParseWeight(part)
+		if err != nil {
+			return err
+		}
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// weightValue is a flag.Value holding a weight in pounds, optionally
+// suffixed with its unit, e.g. 20kg
+type weightValue float64
+
+// String implements flag.Value
+func (w *weightValue) String() string {
+	return strconv.FormatFloat(float64(*w), 'f', -1, 64)
+}
+
+// Set implements flag.Value
+func (w *weightValue) Set(value string) error {
+	v, err := units.ParseWeight(value)
+	if err != nil {
+		return err
+	}
+	*w = weightValue(v)
+	return nil
+}
+
+// displayLoading prints the weight build-up from the loading flags
+func displayLoading(s *wb.Summary, unitSystem string) {
+	fmt.Printf("Loading:\n")
+	fmt.Printf("  Empty Weight:   %6.0f lbs%s\n", s.EmptyWeight, inKilograms(s.EmptyWeight, unitSystem))
+	if unitSystem == dual {
+		fmt.Printf("  Fuel:           %6.0f lbs%s, %.1f gal%s\n", s.Fuel, inKilograms(s.Fuel, unitSystem),
+			s.FuelGallons, inLiters(s.FuelGallons, unitSystem))
+	} else {
+		fmt.Printf("  Fuel:           %6.0f lbs (%.1f gal)\n", s.Fuel, s.FuelGallons)
+	}
+	fmt.Printf("  People:         %6.0f lbs%s\n", s.People, inKilograms(s.People, unitSystem))
+	fmt.Printf("  Baggage:        %6.0f lbs%s\n", s.Baggage, inKilograms(s.Baggage, unitSystem))
+	fmt.Printf("  Ramp Weight:    %6.0f lbs%s\n", s.RampWeight, inKilograms(s.RampWeight, unitSystem))
+	fmt.Printf("  Taxi Fuel:      %6.0f lbs%s\n", -s.TaxiFuel, inKilograms(-s.TaxiFuel, unitSystem))
+	fmt.Printf("  Takeoff Weight: %6.0f lbs%s\n", s.TakeoffWeight, inKilograms(s.TakeoffWeight, unitSystem))
+	if s.TakeoffCG > 0 {
+		fmt.Printf("  Takeoff CG:     %6.1f in\n", s.TakeoffCG)
+	}
+	for _, q := range s.Categories {
+		status := "qualifies"
+		if !q.Qualified {
+			status = "no, " + q.Reason
+		}
+		fmt.Printf("  %-15s %s\n", categoryName(q.Category)+":", status)
+	}
+}
+
+// formatViolations lists the stations loaded outside the aircraft's limits, one per line
+func formatViolations(violations wb.Violations) string {
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = "  - " + v.Message
+	}
+	return strings.Join(lines, "\n")
+}
+
+// categoryName capitalizes a certification category for display
+func categoryName(category string) string {
+	if category == "" {
+		return category
+	}
+	return strings.ToUpper(category[:1]) + category[1:]
+}
@@ -4,10 +4,23 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"os"
+	"sort"
 	"strings"
+	"time"
 	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/corrections"
 	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/policy"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+	"github.com/ryanbmilbourne/otto-perf/termstyle"
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/warehouse"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+	"github.com/ryanbmilbourne/otto-perf/weather"
 )
 
 func main() {
@@ -18,10 +31,58 @@ func main() {
 	tempC := flag.Float64("temp-c", 15, "Temperature in °C")
 	tempF := flag.Float64("temp-f", 0, "Temperature in °F (overrides temp-c if provided)")
 	tempFProvided := false
+	windProvided := false
+	magVarProvided := false
 	
 	weight := flag.Float64("weight", 2325, "Aircraft weight in pounds")
+	
+	// Alternatively, build up the weight from the loading
+	loadingProvided := false
+	var people weightList
+	fuelGallons := flag.Float64("fuel-gal", 0, "Usable fuel in US gallons (with -people/-bags, overrides -weight)")
+	fuelState := flag.String("fuel", "", "Fuel state instead of -fuel-gal: full, a profile preset such as tabs, or gallons, with optional +Nhr/+Ngal (e.g. tabs+1hr)")
+	flag.Var(&people, "people", "Comma-separated occupant weights in pounds, or with a kg suffix, e.g. 170,72kg")
+	var baggage weightValue
+	flag.Var(&baggage, "bags", "Baggage weight in pounds, or with a kg suffix, e.g. 20kg")
+	taxiFuel := flag.Float64("taxi-fuel", 0, "Taxi and run-up fuel allowance in US gallons (default from the aircraft profile)")
+	taxiFuelProvided := false
+	emptyWeight := flag.Float64("empty-weight", 0, "Basic empty weight in pounds from the airframe's W&B record (default from the aircraft profile)")
+	emptyArm := flag.Float64("empty-arm", 0, "Empty CG in inches aft of the datum from the airframe's W&B record (default from the aircraft profile)")
 	windComponent := flag.Float64("wind", 0, "Wind component in knots (positive for headwind, negative for tailwind)")
-	unitSystem := flag.String("units", "imperial", "Unit system for display: 'imperial', 'metric', or 'mixed'")
+	
+	// Alternatively, derive the wind component from the reported wind and runway
+	windDir := flag.Float64("wind-dir", 0, "Wind direction in degrees (with -wind-speed, overrides -wind)")
+	windSpeed := flag.Float64("wind-speed", 0, "Wind speed in knots")
+	windRef := flag.String("wind-ref", "true", "Wind direction reference: 'true' (METAR/TAF) or 'magnetic' (ATIS/tower)")
+	metar := flag.String("metar", "", "Raw departure METAR, checked for the weather risks the chart ignores: gusts, convection, dry air and icing")
+	airportID := flag.String("airport", "", "Departure airport identifier (for runway heading and magnetic variation)")
+	runwayID := flag.String("runway", "", "Departure runway, e.g. 17")
+	magVar := flag.Float64("magvar", 0, "Magnetic variation in degrees, east positive (overrides the airport record)")
+	departureTime := flag.String("departure", "", "Departure time, 'YYYY-MM-DD HH:MM' local to -airport or with a Z suffix for Zulu")
+	
+	aircraftID := flag.String("aircraft", "pa28-161", "Aircraft profile")
+	correctionsFile := flag.String("corrections", "", "Operator correction rules file (default: corrections.json in the user config directory, if present)")
+	policyFile := flag.String("policy", "", "Go/no-go policy rules file, one 'CONDITION -> NOGO|CAUTION|INFO \"message\"' a line")
+	variantID := flag.String("variant", "", "Engine or STC variant of the aircraft, swapping in its performance charts (see the aircraft profile)")
+	equipment := flag.String("equipment", "", "Comma-separated optional equipment installed, e.g. adsb-out (see the aircraft profile)")
+	flaps := flag.String("flaps", "", "Flap setting in degrees, 0 or 25, corrected from the chart per the POH notes (default: the chart's, 25° for the pa28-161)")
+	techniqueID := flag.String("technique", "", "Takeoff technique for the configuration checklist (default from the aircraft profile)")
+	asiUnits := flag.String("asi", "", "Airspeed indicator scale for speeds: 'knots', 'mph' for older panels, or 'both' (default from the aircraft profile)")
+	unitSystem := flag.String("units", "imperial", "Unit system for display: 'imperial', 'metric', 'mixed', or 'dual' (every quantity in both)")
+	noColor := flag.Bool("no-color", false, "Do not highlight warnings and margins in color (also set by NO_COLOR)")
+	highContrast := flag.Bool("high-contrast", false, "Highlight with bold and reverse video instead of color, for bright light")
+	plain := flag.Bool("plain", false, "Screen reader friendly output: warnings and results first, no rules, plots or highlighting")
+	factored := flag.Bool("factored", false, "Also show the takeoff distance with the UK CAA/AOPA safety factor (×1.33), labeled with its source")
+	showSummary := flag.Bool("summary", false, "Print only a one-line summary to share, e.g. by text to a safety pilot")
+	speechFormat := flag.String("speech", "", "Print only a script of the key numbers in words to pipe to a text-to-speech engine: 'text' or 'ssml'")
+	available := flag.Float64("available", 0, "Available takeoff distance in feet for the margin and the runway remaining at lift-off (default: the runway length with -airport and -runway)")
+	narrow := flag.Bool("narrow", false, "Compact layout of at most 40 columns for phone terminals (default when the terminal is narrower than 60 columns)")
+	plotStyle := flag.String("plot", "", "Plot takeoff distance against temperature: 'term' (braille) or 'ascii'")
+	warehouseFile := flag.String("warehouse", "", "Record the result and its GO/CAUTION/NO-GO verdict in this warehouse file for safety program reports (see otto query)")
+	deterministic := flag.Bool("deterministic", false, "Round results to a fixed resolution and show their hash, so an archived briefing can be reproduced exactly on any platform")
+	confirmInputs := flag.Bool("confirm", false, "Read the parsed inputs back in words and ask to confirm them before computing, to catch a mistyped figure")
+	presetName := flag.String("preset", "", "Built-in training scenario to run, e.g. hot-high, with any other inputs given overriding it ('list' to list them)")
+	dataDir := flag.String("data-dir", "", "Directory of airports.csv and runways.csv to use instead of the airport data built into the binary")
 	showHelp := flag.Bool("help", false, "Show help")
 	
 	// Custom usage function for better help display
@@ -37,9 +98,21 @@ func main() {
 	flag.Parse()
 	
 	// Check if -temp-f was explicitly provided
+	given := make(map[string]bool)
 	flag.Visit(func(f *flag.Flag) {
-		if f.Name == "temp-f" {
+		given[f.Name] = true
+		switch f.Name {
+		case "temp-f":
 			tempFProvided = true
+		case "wind-dir", "wind-speed":
+			windProvided = true
+		case "magvar":
+			magVarProvided = true
+		case "fuel-gal", "fuel", "people", "bags", "empty-weight", "empty-arm":
+			loadingProvided = true
+		case "taxi-fuel":
+			loadingProvided = true
+			taxiFuelProvided = true
 		}
 	})
 	
@@ -49,6 +122,93 @@ func main() {
 		os.Exit(0)
 	}
 	
+	if *dataDir != "" {
+		if err := airports.SetDataDir(*dataDir); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	
+	// A training preset fills in the inputs not given on the command line
+	var preset *scenario.Preset
+	if *presetName == "list" {
+		listPresets()
+		return
+	}
+	if *presetName != "" {
+		var err error
+		if preset, err = scenario.LookupPreset(*presetName); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		applyPreset(preset, given, pressureAlt, tempC, weight, windComponent, available)
+	}
+	
+	if *plotStyle != "" && *plotStyle != "term" && *plotStyle != "ascii" {
+		log.Fatalf("Error: unknown -plot %q, expected term or ascii", *plotStyle)
+	}
+	
+	// Look up the selected aircraft
+	profile, err := aircraft.Lookup(*aircraftID)
+	if err == nil {
+		profile, err = profile.WithVariant(*variantID)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if *equipment != "" {
+		if profile, err = profile.WithEquipment(strings.Split(*equipment, ",")); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if *asiUnits != "" {
+		asi, err := aircraft.ParseASIUnits(*asiUnits)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		profile = profile.WithASI(asi)
+	}
+	if strings.ToLower(*unitSystem) == dual {
+		profile = profile.WithUnits(aircraft.DualUnits)
+	}
+	
+	// Apply the operator's correction factors; the surface filters need
+	// the departure runway
+	rules, err := corrections.Load(*correctionsFile)
+	if err != nil {
+		log.Fatalf("Error loading corrections: %v", err)
+	}
+	conditions := corrections.Conditions{Aircraft: profile.ID}
+	if preset != nil {
+		conditions.Surface = preset.Surface
+	}
+	if *airportID != "" && *runwayID != "" {
+		if rwy, err := lookupRunway(*airportID, *runwayID); err == nil {
+			conditions.Surface = rwy.Surface
+		}
+	}
+	profile = rules.Apply(profile, conditions)
+	
+	var goNoGo *policy.Policy
+	if *policyFile != "" {
+		if goNoGo, err = policy.Load(*policyFile, policyVariables); err != nil {
+			log.Fatalf("Error loading policy:\n%v", err)
+		}
+	}
+	
+	technique, err := profile.Technique(*techniqueID)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	flapSetting, err := performance.ParseFlapSetting(*flaps)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if flapSetting != performance.FlapsCharted {
+		// The checklist sets the flaps the distance was corrected for
+		configured := *technique
+		configured.Flaps = string(flapSetting) + "°"
+		technique = &configured
+	}
+	
 	// Determine temperature in Celsius
 	var temperature float64
 	if tempFProvided {
@@ -63,30 +223,301 @@ func main() {
 		Temperature:      temperature,
 		Weight:           *weight,
 		WindComponent:    *windComponent,
+		FlapSetting:      flapSetting,
+	}
+	
+	// Build up the weight from the loading if one was given
+	var loading *wb.Summary
+	if loadingProvided {
+		weights := profile.WeightBalance
+		if *emptyWeight > 0 {
+			// The record's empty weight excludes equipment selected with -equipment
+			weights.EmptyWeight = *emptyWeight
+			for _, e := range profile.Installed {
+				weights.EmptyWeight += e.Weight
+			}
+		}
+		if *emptyArm > 0 {
+			weights.EmptyArm = *emptyArm
+		}
+		if taxiFuelProvided {
+			weights.TaxiFuel = *taxiFuel
+		}
+		if *fuelState != "" {
+			if *fuelGallons, err = weights.FuelState(*fuelState); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+		}
+		
+		loading, err = weights.Compute(wb.Loading{
+			FuelGallons: *fuelGallons,
+			People:      people,
+			Baggage:     float64(baggage),
+		})
+		if violations, ok := err.(wb.Violations); ok {
+			log.Fatalf("Error: loading outside the %s limits:\n%s", profile.Name, formatViolations(violations))
+		} else if err != nil {
+			log.Fatalf("Error computing weight: %v", err)
+		}
+		params.Weight = loading.TakeoffWeight
+	}
+	
+	// Resolve the reported wind along the runway if one was given
+	var rwyWind *runwayWind
+	if windProvided {
+		var magVarOverride *float64
+		if magVarProvided {
+			magVarOverride = magVar
+		}
+		
+		rwyWind, err = resolveRunwayWind(*windDir, *windSpeed, *windRef, *airportID, *runwayID, magVarOverride)
+		if err != nil {
+			log.Fatalf("Error resolving wind: %v", err)
+		}
+		params.WindComponent = rwyWind.Components.Headwind
+	}
+	
+	// Resolve the departure time in the airport's local zone if one was given
+	var dep *departure
+	if *departureTime != "" {
+		dep, err = resolveDeparture(*departureTime, *airportID)
+		if err != nil {
+			log.Fatalf("Error resolving departure time: %v", err)
+		}
+	}
+	
+	var obs *weather.Observation
+	if *metar != "" {
+		if obs, err = weather.ParseMETAR(*metar, time.Now()); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	
+	// Flag figures that are within the chart but unlikely to be what was
+	// meant, before anything is computed from them
+	plausibility := profile.Plausibility(plausibilityInputs(params, loading != nil, rwyWind, obs, *airportID))
+	
+	// Read the inputs back before anything is computed from them; the
+	// prompt goes to stderr to keep the results alone on stdout
+	if *confirmInputs {
+		lines := readback(params, loading != nil, *airportID, *runwayID)
+		for _, a := range plausibility {
+			lines = append(lines, a.String())
+		}
+		if !confirm(os.Stdin, os.Stderr, lines) {
+			fmt.Fprintf(os.Stderr, "Not confirmed: nothing computed\n")
+			os.Exit(1)
+		}
 	}
 	
-	// Initialize takeoff calculator
-	calculator := performance.NewTakeoffCalculator()
+	// Initialize takeoff calculator for the selected aircraft
+	calculator := profile.NewTakeoffCalculator()
+	calculator.SetDeterministic(*deterministic)
 	
-	// Calculate takeoff performance
+	// Calculate takeoff performance; a figure outside the chart may be
+	// a mistyped one the plausibility checks point to
 	result, err := calculator.CalculateTakeoff(params)
 	if err != nil {
+		for _, a := range plausibility {
+			log.Printf("%s", a)
+		}
 		log.Fatalf("Error calculating takeoff performance: %v", err)
 	}
 	
-	// Display results based on selected unit system
-	displayResults(params, result, strings.ToLower(*unitSystem))
+	// Check the conditions against the aircraft's operating limits
+	advisories := profile.Advisories(aircraft.Conditions{
+		PressureAltitude: params.PressureAltitude,
+		Temperature:      params.Temperature,
+	})
+	
+	// Frame the numbers with the weather risks the chart ignores, and
+	// the inputs to double-check
+	advisories = append(advisories, plausibility...)
+	if obs != nil {
+		advisories = append(advisories, aircraft.WeatherAdvisoriesIn(obs, profile.Units)...)
+	}
+	if len(plausibility) > 0 || obs != nil {
+		sort.SliceStable(advisories, func(i, j int) bool {
+			return advisories[i].Severity > advisories[j].Severity
+		})
+	}
+	
+	// Plot the distance over the chart's temperatures if asked; a plot
+	// means nothing to a screen reader
+	var plot string
+	if *plotStyle != "" && !*plain {
+		plot = plotTemperature(calculator, params, strings.ToLower(*unitSystem), *plotStyle == "ascii")
+	}
+	
+	b := &briefing{
+		Profile:    profile,
+		Params:     params,
+		Result:     result,
+		Loading:    loading,
+		Wind:       rwyWind,
+		Departure:  dep,
+		Advisories: advisories,
+		Technique:  technique,
+		Checklist:  profile.TakeoffChecklist(technique, result),
+		Plot:       plot,
+		Preset:     preset,
+	}
+	if *factored {
+		b.Factor = &performance.CAASafetySense
+	}
+	if *deterministic {
+		b.Hash = performance.ResultHash(calculator.Source(), params, result)
+	}
+	
+	if *speechFormat != "" && *speechFormat != "text" && *speechFormat != "ssml" {
+		log.Fatalf("Error: unknown speech format %q (text or ssml)", *speechFormat)
+	}
+	
+	// The available distance gives the summary its margin, the policy its
+	// tora, the warehouse its verdict and the ground roll the runway left
+	// at lift-off, which alone can go without it
+	distance := *available
+	if distance <= 0 && *airportID != "" && *runwayID != "" {
+		if distance, err = runwayLength(*airportID, *runwayID); err != nil {
+			if *showSummary || *speechFormat != "" || goNoGo != nil || *warehouseFile != "" {
+				log.Fatalf("Error: %v", err)
+			}
+			distance = 0
+		}
+	}
+	b.Available = distance
+	var findings []policy.Finding
+	if goNoGo != nil {
+		findings = goNoGo.Evaluate(policyValues(b, distance))
+		b.Advisories = append(b.Advisories, policyAdvisories(findings)...)
+	}
+	
+	// A result that cannot be recorded is still shown
+	if *warehouseFile != "" {
+		record := warehouseResult(b, findings, *airportID, *runwayID, distance)
+		if err := warehouse.New(*warehouseFile).Add(record); err != nil {
+			log.Printf("Warning: result not recorded: %v", err)
+		}
+	}
+	
+	// Print the shareable summary alone if asked
+	if *showSummary {
+		fmt.Println(summary(b, *airportID, *runwayID, distance))
+		return
+	}
+	
+	// Or the speech script for an audio brief
+	if *speechFormat != "" {
+		fmt.Print(speechScript(b, *airportID, *runwayID, distance, *speechFormat == "ssml"))
+		return
+	}
+	
+	// Display results based on selected unit system, narrow on a phone
+	width := termstyle.Width(os.Stdout)
+	displayResults(b, strings.ToLower(*unitSystem), output{
+		Styler: termstyle.Detect(os.Stdout, *noColor || *plain, *highContrast),
+		plain:  *plain,
+		narrow: *narrow || (width > 0 && width < narrowThreshold),
+	})
+}
+
+// briefing collects everything shown in the results
+type briefing struct {
+	Profile    *aircraft.Profile
+	Params     performance.TakeoffParams
+	Result     *performance.TakeoffResult
+	Loading    *wb.Summary // nil when the weight was given directly
+	Wind       *runwayWind // nil when the wind component was given directly
+	Departure  *departure  // nil when no departure time was given
+	Advisories []aircraft.Advisory
+	Technique  *aircraft.Technique
+	Checklist  []aircraft.ChecklistItem
+	Plot       string                    // Rendered plot, empty without -plot
+	Factor     *performance.SafetyFactor // nil unless -factored
+	Preset     *scenario.Preset          // nil unless -preset
+	Available  float64                   // Available takeoff distance in feet, 0 when unknown
+	Hash       string                    // Result hash, empty unless -deterministic
+}
+
+func displayResults(b *briefing, unitSystem string, out output) {
+	if out.narrow {
+		displayNarrow(b, unitSystem, out)
+		return
+	}
+	
+	title := b.Profile.Name + " Takeoff Performance"
+	fmt.Printf("\n%s\n", out.Emphasis(title))
+	if !out.plain {
+		fmt.Printf("%s\n", strings.Repeat("=", len(title)))
+	}
+	
+	// A screen reader reads top to bottom, so plain output leads with the
+	// warnings and the result before the inputs they came from
+	if out.plain {
+		displayAdvisories(b, out)
+		displayPerformance(b, unitSystem, out)
+		displayInputs(b, unitSystem, out)
+	} else {
+		fmt.Printf("\n")
+		displayInputs(b, unitSystem, out)
+		displayPerformance(b, unitSystem, out)
+		if b.Plot != "" {
+			fmt.Printf("\n%s", b.Plot)
+		}
+	}
+	
+	// Display the configuration checklist for the technique
+	out.heading(fmt.Sprintf("Takeoff Configuration (%s)", b.Technique.Name))
+	for _, item := range b.Checklist {
+		fmt.Printf("%s\n", item)
+	}
+	
+	if !out.plain {
+		displayAdvisories(b, out)
+	}
+	if b.Preset != nil {
+		displayPreset(b, out)
+	}
+	if b.Hash != "" {
+		fmt.Printf("\nResult hash: %s\n", b.Hash)
+	}
+	
+	// Safety note
+	fmt.Printf("\nNOTE: Always verify these calculations against the POH and ensure\n")
+	fmt.Printf("      you have adequate runway length with appropriate safety margins.\n")
 }
 
-func displayResults(params performance.TakeoffParams, result *performance.TakeoffResult, unitSystem string) {
-	fmt.Printf("\nPA-28-161 Cherokee Warrior II Takeoff Performance\n")
-	fmt.Printf("=================================================\n\n")
+// output controls how the briefing is printed
+type output struct {
+	termstyle.Styler
+	plain  bool // Screen reader order: warnings and results first, no rules or plots
+	narrow bool // Compact layout for phone terminals
+}
+
+// heading prints a section heading, underlined unless the output is plain
+func (o output) heading(title string) {
+	fmt.Printf("\n%s:\n", title)
+	if !o.plain {
+		fmt.Printf("%s\n", strings.Repeat("-", len(title)))
+	}
+}
+
+// displayInputs prints the inputs of the calculation
+func displayInputs(b *briefing, unitSystem string, out output) {
+	params, rwyWind := b.Params, b.Wind
 	
-	// Display input parameters
-	fmt.Printf("Input Parameters:\n")
-	fmt.Printf("----------------\n")
+	// The first section follows the title directly
+	if out.plain {
+		out.heading("Input Parameters")
+	} else {
+		fmt.Printf("Input Parameters:\n")
+		fmt.Printf("----------------\n")
+	}
 	
-	fmt.Printf("Pressure Altitude: %.0f ft\n", params.PressureAltitude)
+	if b.Departure != nil {
+		fmt.Printf("Departure: %s\n", b.Departure)
+	}
+	fmt.Printf("Pressure Altitude: %.0f ft%s\n", params.PressureAltitude, inMeters(params.PressureAltitude, unitSystem))
 	
 	// Display temperature in appropriate format
 	switch unitSystem {
@@ -103,47 +534,147 @@ func displayResults(params performance.TakeoffParams, result *performance.Takeof
 			params.Temperature, performance.ConvertCelsiusToFahrenheit(params.Temperature))
 	}
 	
-	fmt.Printf("Weight: %.0f lbs\n", params.Weight)
+	if b.Loading != nil {
+		displayLoading(b.Loading, unitSystem)
+	}
+	for _, e := range b.Profile.Installed {
+		fmt.Printf("Equipment: %s\n", e)
+	}
+	fmt.Printf("Weight: %.0f lbs%s\n", params.Weight, inKilograms(params.Weight, unitSystem))
+	if params.FlapSetting != performance.FlapsCharted {
+		fmt.Printf("Flaps: %s° (chart corrected per the POH notes)\n", string(params.FlapSetting))
+	}
 	
 	// Display wind in appropriate format
-	if params.WindComponent > 0 {
-		fmt.Printf("Wind: %.0f knots headwind\n", params.WindComponent)
+	if rwyWind != nil {
+		fmt.Printf("Wind: %s at %.0f knots%s, runway %s (%s)\n", 
+			rwyWind.Wind.From, rwyWind.Wind.Speed, inKilometersPerHour(rwyWind.Wind.Speed, unitSystem), rwyWind.Runway, rwyWind.Heading)
+		displayComponents(rwyWind.Components, unitSystem)
+		if warning := crosswindWarning(b); warning != "" && !out.plain {
+			fmt.Printf("  %s\n", out.Warning(warning))
+		}
+	} else if params.WindComponent > 0 {
+		fmt.Printf("Wind: %.0f knots%s headwind\n", params.WindComponent, inKilometersPerHour(params.WindComponent, unitSystem))
 	} else if params.WindComponent < 0 {
-		fmt.Printf("Wind: %.0f knots tailwind\n", -params.WindComponent)
+		fmt.Printf("Wind: %.0f knots%s tailwind\n", -params.WindComponent, inKilometersPerHour(-params.WindComponent, unitSystem))
 	} else {
 		fmt.Printf("Wind: No wind\n")
 	}
+}
+
+// displayPerformance prints the takeoff result
+func displayPerformance(b *briefing, unitSystem string, out output) {
+	result := b.Result
+	out.heading("Takeoff Performance")
 	
-	fmt.Printf("\n")
+	// The factored distance goes beside the POH figure, never in its place
+	if b.Factor != nil {
+		fmt.Printf("Takeoff Distance (over 50 ft obstacle, POH): %s\n", 
+			out.Emphasis(formatDistance(result.TakeoffDistance, unitSystem) + formatTolerance(result.Tolerance, unitSystem)))
+		fmt.Printf("Factored Takeoff Distance (%s): %s\n", b.Factor, 
+			out.Emphasis(formatDistance(b.Factor.TakeoffDistance(result.TakeoffDistance), unitSystem)))
+	} else {
+		fmt.Printf("Takeoff Distance (over 50 ft obstacle): %s\n", 
+			out.Emphasis(formatDistance(result.TakeoffDistance, unitSystem) + formatTolerance(result.Tolerance, unitSystem)))
+	}
 	
-	// Display results
-	fmt.Printf("Takeoff Performance:\n")
-	fmt.Printf("-------------------\n")
+	if contaminated(b) {
+		fmt.Printf("%s\n", out.Warning("Valid only for wings free of frost, ice and snow; see the icing advisory"))
+	}
 	
-	// Display distances in appropriate format
+	// Display speeds
+	asi := b.Profile.Speeds.ASI
+	fmt.Printf("Lift-off Speed: %s%s\n", asi.Format(result.LiftoffSpeed), inKilometersPerHour(result.LiftoffSpeed, unitSystem))
+	fmt.Printf("50 ft Barrier Speed: %s%s\n", asi.Format(result.BarrierSpeed), inKilometersPerHour(result.BarrierSpeed, unitSystem))
+	if result.GroundRoll > 0 {
+		// Charted, so the runway left at rotation is worth briefing
+		fmt.Printf("Ground Roll: %s\n", formatDistance(result.GroundRoll, unitSystem))
+		if roll := result.Roll; roll != nil {
+			fmt.Printf("Roll Time (estimated): about %.0f s at %.1f kt/s%s average\n", 
+				roll.Time, roll.Acceleration, inMetersPerSecondSquared(roll.Acceleration, unitSystem))
+		}
+		if b.Available > 0 {
+			remaining := b.Available - result.GroundRoll
+			if remaining < 0 {
+				fmt.Printf("%s\n", out.Warning(fmt.Sprintf("Runway Remaining at Lift-off: none, %s short", formatDistance(-remaining, unitSystem))))
+			} else {
+				fmt.Printf("Runway Remaining at Lift-off: %s\n", formatDistance(remaining, unitSystem))
+			}
+		}
+	} else if roll := result.Roll; roll != nil {
+		fmt.Printf("Ground Roll (estimated): %s, about %.0f s at %.1f kt/s%s average\n", 
+			formatDistance(roll.Distance, unitSystem), roll.Time, roll.Acceleration, inMetersPerSecondSquared(roll.Acceleration, unitSystem))
+	}
+	for _, a := range result.Adjustments {
+		fmt.Printf("%s\n", out.Caution(fmt.Sprintf("Adjusted: %s takeoff distance, not from the POH chart", a)))
+	}
+}
+
+// contaminated reports whether the weather may have contaminated the wing,
+// so the charted distance holds only after a contamination check
+func contaminated(b *briefing) bool {
+	for _, a := range b.Advisories {
+		if a.Code == aircraft.CodeIcing {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDistance formats a distance in feet in the unit system
+func formatDistance(feet float64, unitSystem string) string {
 	switch unitSystem {
 	case "metric":
-		fmt.Printf("Takeoff Distance (over 50 ft obstacle): %.0f m (%.0f ft)\n", 
-			feetToMeters(result.TakeoffDistance), result.TakeoffDistance)
-	case "imperial":
-		fmt.Printf("Takeoff Distance (over 50 ft obstacle): %.0f ft\n", result.TakeoffDistance)
-	case "mixed":
-		fmt.Printf("Takeoff Distance (over 50 ft obstacle): %.0f ft (%.0f m)\n", 
-			result.TakeoffDistance, feetToMeters(result.TakeoffDistance))
+		return fmt.Sprintf("%.0f m (%.0f ft)", feetToMeters(feet), feet)
+	case "mixed", dual:
+		return fmt.Sprintf("%.0f ft (%.0f m)", feet, feetToMeters(feet))
 	default:
-		fmt.Printf("Takeoff Distance (over 50 ft obstacle): %.0f ft\n", result.TakeoffDistance)
+		return fmt.Sprintf("%.0f ft", feet)
+	}
+}
+
+// formatTolerance formats the interpolation tolerance of a distance in feet
+// as " ± 10 ft", rounded up to 10 ft (5 m in metric) so the band is never
+// narrower than the estimate, or "" on the chart's lines
+func formatTolerance(feet float64, unitSystem string) string {
+	if feet < 1 {
+		return ""
+	}
+	if unitSystem == "metric" {
+		return fmt.Sprintf(" ± %.0f m", math.Ceil(feetToMeters(feet) / 5) * 5)
+	}
+	band := math.Ceil(feet / 10) * 10
+	return fmt.Sprintf(" ± %.0f ft%s", band, inMeters(band, unitSystem))
+}
+
+// displayAdvisories prints the operational advisories highlighted by
+// severity, and in plain output the crosswind warning too
+func displayAdvisories(b *briefing, out output) {
+	var lines []string
+	if warning := crosswindWarning(b); warning != "" && out.plain {
+		lines = append(lines, warning)
+	}
+	for _, advisory := range b.Advisories {
+		line := advisory.String()
+		switch advisory.Severity {
+		case aircraft.Warning:
+			line = out.Warning(line)
+		case aircraft.Caution:
+			line = out.Caution(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return
 	}
 	
-	// Display speeds
-	fmt.Printf("Lift-off Speed: %.0f KIAS\n", result.LiftoffSpeed)
-	fmt.Printf("50 ft Barrier Speed: %.0f KIAS\n", result.BarrierSpeed)
-	
-	// Safety note
-	fmt.Printf("\nNOTE: Always verify these calculations against the POH and ensure\n")
-	fmt.Printf("      you have adequate runway length with appropriate safety margins.\n")
+	out.heading("Advisories")
+	for _, line := range lines {
+		fmt.Printf("%s\n", line)
+	}
 }
 
 // feetToMeters converts distance from feet to meters
 func feetToMeters(feet float64) float64 {
-	return feet * 0.3048
+	return units.FeetToMeters(feet)
 }
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// narrowColumns is the width of the narrow layout, and narrowThreshold
+// the terminal width below which it is used without -narrow
+const (
+	narrowColumns   = 40
+	narrowThreshold = 60
+)
+
+// displayNarrow prints the briefing in at most narrowColumns columns for
+// a phone terminal: the key numbers first, one short line each, then the
+// advisories, inputs and checklist
+func displayNarrow(b *briefing, unitSystem string, out output) {
+	params, result := b.Params, b.Result
+	
+	fmt.Printf("\n%s\n", out.Emphasis(b.Profile.ID + " TAKEOFF"))
+	distance := func(feet float64) string {
+		switch unitSystem {
+		case "metric":
+			return fmt.Sprintf("%.0f m", feetToMeters(feet))
+		case dual:
+			return fmt.Sprintf("%.0f ft / %.0f m", feet, feetToMeters(feet))
+		}
+		return fmt.Sprintf("%.0f ft", feet)
+	}
+	fmt.Printf("Over 50 ft: %s\n", out.Emphasis(distance(result.TakeoffDistance) + formatTolerance(result.Tolerance, unitSystem)))
+	if b.Factor != nil {
+		printWrapped(nil, fmt.Sprintf("Factored: %s (%s)", distance(b.Factor.TakeoffDistance(result.TakeoffDistance)), b.Factor))
+	}
+	if contaminated(b) {
+		printWrapped(out.Warning, "Clean wing only: check for frost and ice")
+	}
+	asi := b.Profile.Speeds.ASI
+	liftoff, barrier, unit := result.LiftoffSpeed, result.BarrierSpeed, "KIAS"
+	if asi == aircraft.ASIMPH {
+		liftoff, barrier, unit = units.NauticalToStatute(liftoff), units.NauticalToStatute(barrier), "mph IAS"
+	}
+	fmt.Printf("Liftoff %.0f / 50 ft %.0f %s\n", liftoff, barrier, unit)
+	if asi == aircraft.ASIBoth {
+		fmt.Printf("  (%.0f / %.0f mph)\n", units.NauticalToStatute(liftoff), units.NauticalToStatute(barrier))
+	}
+	if roll := result.Roll; roll != nil && result.GroundRoll > 0 {
+		fmt.Printf("Roll %s, ~%.0f s\n", distance(result.GroundRoll), roll.Time)
+	} else if roll != nil {
+		fmt.Printf("Roll ~%s, ~%.0f s\n", distance(roll.Distance), roll.Time)
+	}
+	if unitSystem == dual {
+		fmt.Printf("  (%.0f / %.0f km/h)\n", units.NauticalToKilometers(result.LiftoffSpeed), units.NauticalToKilometers(result.BarrierSpeed))
+	}
+	for _, a := range result.Adjustments {
+		printWrapped(out.Caution, fmt.Sprintf("Adjusted: %s, not POH", a))
+	}
+	
+	if warning := crosswindWarning(b); warning != "" {
+		printWrapped(out.Warning, warning)
+	}
+	for _, advisory := range b.Advisories {
+		style := func(s string) string { return s }
+		switch advisory.Severity {
+		case aircraft.Warning:
+			style = out.Warning
+		case aircraft.Caution:
+			style = out.Caution
+		}
+		printWrapped(style, advisory.String())
+	}
+	
+	fmt.Printf("\n")
+	if b.Departure != nil {
+		fmt.Printf("%s\n", b.Departure)
+	}
+	temperature := fmt.Sprintf("%.0f°C", params.Temperature)
+	switch unitSystem {
+	case "imperial":
+		temperature = fmt.Sprintf("%.0f°F", performance.ConvertCelsiusToFahrenheit(params.Temperature))
+	case dual:
+		temperature += fmt.Sprintf("/%.0f°F", performance.ConvertCelsiusToFahrenheit(params.Temperature))
+	}
+	
+	// Dual units need two lines to stay narrow
+	if unitSystem == dual {
+		fmt.Printf("PA %.0f ft%s  %s\n", params.PressureAltitude, inMeters(params.PressureAltitude, unitSystem), temperature)
+		fmt.Printf("%.0f lbs%s\n", params.Weight, inKilograms(params.Weight, unitSystem))
+	} else {
+		fmt.Printf("PA %.0f ft  %s  %.0f lbs\n", params.PressureAltitude, temperature, params.Weight)
+	}
+	if params.FlapSetting != performance.FlapsCharted {
+		fmt.Printf("Flaps %s°\n", string(params.FlapSetting))
+	}
+	if w := b.Wind; w != nil {
+		fmt.Printf("Wind %s/%.0f%s  RWY %s\n", w.Wind.From, w.Wind.Speed, inKilometersPerHour(w.Wind.Speed, unitSystem), w.Runway)
+		along := fmt.Sprintf("HW %.0f%s", w.Components.Headwind, inKilometersPerHour(w.Components.Headwind, unitSystem))
+		if w.Components.Headwind < 0 {
+			along = fmt.Sprintf("TW %.0f%s", -w.Components.Headwind, inKilometersPerHour(-w.Components.Headwind, unitSystem))
+		}
+		crosswind := math.Abs(w.Components.Crosswind)
+		across := "XW none"
+		if math.Round(crosswind) != 0 {
+			across = fmt.Sprintf("XW %.0f%s %s", crosswind, inKilometersPerHour(crosswind, unitSystem), w.Components.CrosswindSide())
+		}
+		fmt.Printf("%s  %s\n", along, across)
+	} else {
+		fmt.Printf("Wind %+.0f kt%s (+ headwind)\n", params.WindComponent, inKilometersPerHour(params.WindComponent, unitSystem))
+	}
+	for _, e := range b.Profile.Installed {
+		printWrapped(nil, "Equipment: " + e.String())
+	}
+	if p := b.Preset; p != nil {
+		fmt.Printf("\n")
+		printWrapped(out.Emphasis, p.Title)
+		printWrapped(nil, presetRunway(b))
+		printWrapped(nil, p.Lesson)
+	}
+	
+	fmt.Printf("\n")
+	printWrapped(nil, b.Technique.Name)
+	for _, item := range b.Checklist {
+		printWrapped(nil, item.Item + ": " + item.Setting)
+	}
+	if b.Hash != "" {
+		// The full hash is too wide; its start is enough to compare by eye
+		fmt.Printf("\nHash %s\n", b.Hash[:len("sha256:") + 16])
+	}
+	fmt.Printf("\nVerify against the POH.\n")
+}
+
+// printWrapped prints text wrapped at narrowColumns, indenting the
+// continuation lines, with style applied to each line if it is not nil
+func printWrapped(style func(string) string, text string) {
+	printWrappedAt(style, text, narrowColumns)
+}
+
+// printWrappedAt prints text as printWrapped does, wrapped at columns
+func printWrappedAt(style func(string) string, text string, columns int) {
+	var line string
+	for _, word := range strings.Fields(text) {
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line)) + 1 + len([]rune(word)) > columns:
+			printStyled(style, line)
+			line = "  " + word
+		default:
+			line += " " + word
+		}
+	}
+	printStyled(style, line)
+}
+
+// printStyled prints a line with an optional style
+func printStyled(style func(string) string, line string) {
+	if style != nil {
+		line = style(line)
+	}
+	fmt.Printf("%s\n", line)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/termplot"
+)
+
+// plotStep is the temperature step of the plotted curve in °C
+const plotStep = 1.0
+
+// plotTemperature draws takeoff distance against temperature over the
+// chart's temperature range, at the other inputs of params, marking the
+// current temperature. Distances are in meters for metric output.
+func plotTemperature(calculator *performance.TakeoffCalculator, params performance.TakeoffParams, unitSystem string, ascii bool) string {
+	var low, high float64
+	for _, limit := range calculator.Envelope() {
+		if limit.Field == performance.FieldTemperature {
+			low, high = limit.Min, limit.Max
+		}
+	}
+	
+	unit, scale := " ft", func(ft float64) float64 { return ft }
+	if unitSystem == "metric" {
+		unit, scale = " m", feetToMeters
+	}
+	
+	var series termplot.Series
+	for t := low; t <= high; t += plotStep {
+		p := params
+		p.Temperature = t
+		distance := math.NaN()
+		if result, err := calculator.CalculateTakeoff(p); err == nil {
+			distance = scale(result.TakeoffDistance)
+		}
+		series.X = append(series.X, t)
+		series.Y = append(series.Y, distance)
+	}
+	
+	plot := termplot.Plot{ASCII: ascii, XUnit: "°C", YUnit: unit, Series: []termplot.Series{series}}
+	mark := "●"
+	if ascii {
+		mark = "O"
+	}
+	current := ""
+	if result, err := calculator.CalculateTakeoff(params); err == nil {
+		plot.Marks = []termplot.Mark{{X: params.Temperature, Y: scale(result.TakeoffDistance)}}
+		current = fmt.Sprintf(", %s at %.0f°C", mark, params.Temperature)
+	}
+	return fmt.Sprintf("Takeoff distance over 50 ft by temperature (%.0f ft%s, %.0f lbs%s%s):\n%s",
+		params.PressureAltitude, inMeters(params.PressureAltitude, unitSystem),
+		params.Weight, inKilograms(params.Weight, unitSystem), current, plot.Render())
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/policy"
+)
+
+// policyVariables are the names policy rules can use
+var policyVariables = []string{
+	"distance50",        // Takeoff distance over 50 ft in feet
+	"ground_roll",       // Ground roll in feet, when the chart has one
+	"liftoff_speed",     // KIAS
+	"barrier_speed",     // KIAS
+	"tora",              // Available takeoff distance in feet, when known
+	"pressure_altitude", // in feet
+	"density_altitude",  // in feet
+	"temperature_c",     // in °C
+	"weight",            // in pounds
+	"headwind",          // in knots, negative for a tailwind
+	"crosswind",         // in knots either side, when a wind and runway are given
+}
+
+// policyValues returns the values of the policy variables for the briefing
+func policyValues(b *briefing, available float64) map[string]float64 {
+	values := map[string]float64{
+		"distance50":        b.Result.TakeoffDistance,
+		"liftoff_speed":     b.Result.LiftoffSpeed,
+		"barrier_speed":     b.Result.BarrierSpeed,
+		"pressure_altitude": b.Params.PressureAltitude,
+		"density_altitude":  atmosphere.DensityAltitude(b.Params.PressureAltitude, b.Params.Temperature),
+		"temperature_c":     b.Params.Temperature,
+		"weight":            b.Params.Weight,
+		"headwind":          b.Params.WindComponent,
+	}
+	if b.Result.GroundRoll > 0 {
+		values["ground_roll"] = b.Result.GroundRoll
+	}
+	if available > 0 {
+		values["tora"] = available
+	}
+	if b.Wind != nil {
+		values["crosswind"] = math.Abs(b.Wind.Components.Crosswind)
+	}
+	return values
+}
+
+// policyAdvisories returns the policy's findings as advisories: NO-GO as a
+// warning, and rules that could not be checked as cautions
+func policyAdvisories(findings []policy.Finding) []aircraft.Advisory {
+	var advisories []aircraft.Advisory
+	for _, f := range findings {
+		a := aircraft.Advisory{Severity: aircraft.Caution, Message: "Policy: " + f.Rule.Message}
+		switch {
+		case len(f.Missing) > 0:
+			a.Message = "Policy " + f.String()
+		case f.Rule.Verdict == policy.NoGo:
+			a.Severity, a.Message = aircraft.Warning, "Policy NO-GO: "+f.Rule.Message
+		case f.Rule.Verdict == policy.Info:
+			a.Severity = aircraft.Info
+		}
+		advisories = append(advisories, a)
+	}
+	return advisories
+}
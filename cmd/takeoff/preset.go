@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+)
+
+// applyPreset sets the inputs of a training preset that were not given on
+// the command line. The preset's runway stands in for -available unless a
+// distance or a runway was given.
+func applyPreset(p *scenario.Preset, given map[string]bool, pressureAlt, tempC, weight, wind, available *float64) {
+	s := p.Scenario
+	if s.PressureAltitude != nil && !given["altitude"] {
+		*pressureAlt = *s.PressureAltitude
+	}
+	if s.TemperatureC != nil && !given["temp-c"] && !given["temp-f"] {
+		*tempC = *s.TemperatureC
+	}
+	if s.Weight != nil && !given["weight"] {
+		*weight = *s.Weight
+	}
+	if s.WindComponent != nil && !given["wind"] && !given["wind-dir"] && !given["wind-speed"] {
+		*wind = *s.WindComponent
+	}
+	if !given["available"] && !given["runway"] {
+		*available = p.Available
+	}
+}
+
+// listPresets prints the built-in training scenarios
+func listPresets() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, p := range scenario.Presets() {
+		fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Title)
+	}
+	w.Flush()
+}
+
+// presetRunway describes the runway of the briefing's preset with the
+// margin left over, e.g. "Runway: 2000 ft TURF, 268 ft left over"
+func presetRunway(b *briefing) string {
+	if b.Available <= 0 {
+		return "Runway: not given"
+	}
+	runway := fmt.Sprintf("Runway: %.0f ft", b.Available)
+	if b.Preset.Surface != "" {
+		runway += " " + b.Preset.Surface
+	}
+	margin := b.Available - b.Result.TakeoffDistance
+	if margin < 0 {
+		return runway + fmt.Sprintf(", %.0f ft short", -margin)
+	}
+	return runway + fmt.Sprintf(", %.0f ft left over", margin)
+}
+
+// displayPreset prints the preset's lesson after the briefing
+func displayPreset(b *briefing, out output) {
+	out.heading("Training Scenario: " + b.Preset.Title)
+	fmt.Printf("%s\n", presetRunway(b))
+	printWrappedAt(nil, b.Preset.Lesson, 72)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// readback restates the parsed inputs in words, as they would be read back
+// over the radio, with the figures beside them: a weight mistyped as 220
+// is heard as "two hundred twenty pounds", where 220 is easy to read past
+func readback(params performance.TakeoffParams, fromLoading bool, airportID, runwayID string) []string {
+	var lines []string
+	if airportID != "" || runwayID != "" {
+		where := spellIdent(airports.NormalizeIdent(airportID))
+		if runwayID != "" {
+			where = strings.TrimSpace(where + " runway " + spokenRunway(runwayID))
+		}
+		lines = append(lines, "Departure " + where)
+	}
+	lines = append(lines,
+		fmt.Sprintf("Pressure altitude %s feet (%s ft)", spokenNumber(params.PressureAltitude), thousands(params.PressureAltitude)),
+		fmt.Sprintf("Temperature %s degrees Celsius (%.0f °C, %.0f °F)", spokenNumber(params.Temperature),
+			params.Temperature, performance.ConvertCelsiusToFahrenheit(params.Temperature)))
+	weight := fmt.Sprintf("Weight %s pounds (%s lbs)", spokenNumber(params.Weight), thousands(params.Weight))
+	if fromLoading {
+		weight += ", from the loading"
+	}
+	lines = append(lines, weight)
+	switch wind := math.Round(params.WindComponent); {
+	case wind > 0:
+		lines = append(lines, fmt.Sprintf("Wind %s knots headwind (%+.0f kt)", spokenNumber(wind), wind))
+	case wind < 0:
+		lines = append(lines, fmt.Sprintf("Wind %s knots tailwind (%+.0f kt)", spokenNumber(-wind), wind))
+	default:
+		lines = append(lines, "Wind calm (0 kt)")
+	}
+	if params.FlapSetting != performance.FlapsCharted {
+		lines = append(lines, fmt.Sprintf("Flaps %s (%s°)", spokenNumber(params.FlapSetting.Degrees()), string(params.FlapSetting)))
+	}
+	return lines
+}
+
+// confirm writes the readback and asks whether to go on; anything but y or
+// yes, or no answer at all, declines
+func confirm(in io.Reader, out io.Writer, lines []string) bool {
+	fmt.Fprintf(out, "Read back the inputs:\n")
+	for _, line := range lines {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+	fmt.Fprintf(out, "Confirm? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// plausibilityInputs collects the figures entered for the plausibility
+// checks: the weight unless the loading built it up, the reported wind,
+// the METAR's dew point and gusts, and the departure airport's elevation
+func plausibilityInputs(params performance.TakeoffParams, fromLoading bool, w *runwayWind, obs *weather.Observation, airportID string) aircraft.Inputs {
+	in := aircraft.Inputs{
+		PressureAltitude: params.PressureAltitude,
+		Temperature:      params.Temperature,
+	}
+	if !fromLoading {
+		in.Weight = params.Weight
+	}
+	if w != nil {
+		in.WindSpeed, in.WindGust = w.Wind.Speed, w.Wind.Gust
+	}
+	if obs != nil {
+		in.WindSpeed, in.WindGust = obs.Wind.Speed, obs.Wind.Gust
+		in.Dewpoint, in.HasDewpoint = obs.Dewpoint, obs.HasTemperature
+	}
+	if airportID != "" {
+		if airport, err := lookupAirport(airportID); err == nil {
+			in.FieldElevation, in.HasElevation = airport.Elevation, true
+		}
+	}
+	return in
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// speechScript formats the key numbers of the briefing as sentences for a
+// text-to-speech engine, with every number written out in words: "Takeoff
+// distance over fifty feet one thousand eight hundred fifty feet." Distances
+// are rounded up to 10 ft and the margin down, so the spoken figure is never
+// the better one. With ssml the sentences are wrapped in an SSML document,
+// the warnings emphasized; otherwise they are plain text, one a line.
+func speechScript(b *briefing, airportID, runwayID string, available float64, ssml bool) string {
+	params, result := b.Params, b.Result
+	
+	var sentences []string
+	if airportID != "" || runwayID != "" {
+		where := spellIdent(airports.NormalizeIdent(airportID))
+		if runwayID != "" {
+			where = strings.TrimSpace(where + " runway " + spokenRunway(runwayID))
+		}
+		sentences = append(sentences, "Takeoff briefing for " + where + ".")
+	}
+	conditions := fmt.Sprintf("Weight %s pounds, temperature %s degrees Celsius", spokenNumber(params.Weight), spokenNumber(params.Temperature))
+	if params.PressureAltitude != 0 {
+		conditions += ", pressure altitude " + spokenNumber(params.PressureAltitude) + " feet"
+	}
+	if params.FlapSetting != performance.FlapsCharted {
+		conditions += ", flaps " + spokenNumber(params.FlapSetting.Degrees())
+	}
+	switch wind := math.Round(params.WindComponent); {
+	case wind > 0:
+		conditions += ", " + spokenNumber(wind) + " knot headwind"
+	case wind < 0:
+		conditions += ", " + spokenNumber(-wind) + " knot tailwind"
+	default:
+		conditions += ", no wind"
+	}
+	sentences = append(sentences, conditions + ".")
+	
+	distance := "Takeoff distance over fifty feet " + spokenNumber(math.Ceil(result.TakeoffDistance / 10) * 10) + " feet"
+	if len(result.Adjustments) > 0 {
+		distance += ", adjusted, not the POH figure"
+	}
+	if contaminated(b) {
+		distance += ", for a clean wing only"
+	}
+	sentences = append(sentences, distance + ".")
+	if result.GroundRoll > 0 {
+		sentences = append(sentences, "Ground roll " + spokenNumber(math.Ceil(result.GroundRoll / 10) * 10) + " feet.")
+	}
+	
+	var warnings []string
+	if available > 0 {
+		margin := available - result.TakeoffDistance
+		if margin >= 0 {
+			sentences = append(sentences, fmt.Sprintf("Margin %s feet, %s percent of the distance available.",
+				spokenNumber(math.Floor(margin / 10) * 10), spokenNumber(math.Floor(margin / available * 100))))
+		} else {
+			warnings = append(warnings, "Warning. The takeoff distance is " + spokenNumber(math.Ceil(-margin / 10) * 10) + " feet longer than the distance available.")
+		}
+	}
+	asi := b.Profile.Speeds.ASI
+	sentences = append(sentences, fmt.Sprintf("Rotate at %s, fifty feet at %s.", spokenSpeed(result.LiftoffSpeed, asi), spokenSpeed(result.BarrierSpeed, asi)))
+	
+	if crosswindWarning(b) != "" {
+		warnings = append(warnings, "Warning. The crosswind exceeds the " + spokenNumber(b.Profile.Limits.MaxDemonstratedCrosswind) + " knot maximum demonstrated crosswind.")
+	}
+	for _, a := range b.Advisories {
+		if a.Severity == aircraft.Warning {
+			warnings = append(warnings, "Warning. " + strings.TrimSuffix(a.Message, ".") + ".")
+		}
+	}
+	
+	if !ssml {
+		return strings.Join(append(sentences, warnings...), "\n") + "\n"
+	}
+	var s strings.Builder
+	s.WriteString(`<speak version="1.0" xmlns="http://www.w3.org/2001/10/synthesis" xml:lang="en-US">` + "\n<p>\n")
+	for _, sentence := range sentences {
+		s.WriteString("<s>" + escapeXML(sentence) + "</s>\n")
+	}
+	for _, warning := range warnings {
+		s.WriteString(`<break time="500ms"/><s><emphasis level="strong">` + escapeXML(warning) + "</emphasis></s>\n")
+	}
+	s.WriteString("</p>\n</speak>\n")
+	return s.String()
+}
+
+// spokenSpeed reads an indicated airspeed on the airspeed indicator's scale:
+// "forty-eight knots", "fifty-five miles per hour" or both
+func spokenSpeed(kias float64, asi aircraft.ASIUnits) string {
+	knots := spokenNumber(kias) + " knots"
+	mph := spokenNumber(units.NauticalToStatute(kias)) + " miles per hour"
+	switch asi {
+	case aircraft.ASIMPH:
+		return mph
+	case aircraft.ASIBoth:
+		return knots + ", " + mph
+	}
+	return knots
+}
+
+var (
+	ones = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tens = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+)
+
+// spokenNumber writes a number rounded to a whole number out in words, as
+// it is read aloud in American English: 1850 is "one thousand eight hundred
+// fifty" and -5 "minus five"
+func spokenNumber(v float64) string {
+	n := int64(math.Round(v))
+	if n < 0 {
+		return "minus " + spokenNumber(float64(-n))
+	}
+	if n == 0 {
+		return ones[0]
+	}
+	var words []string
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{{1000000, "million"}, {1000, "thousand"}, {100, "hundred"}} {
+		if n >= scale.value {
+			words = append(words, spokenNumber(float64(n / scale.value)), scale.name)
+			n %= scale.value
+		}
+	}
+	switch {
+	case n >= 20 && n % 10 != 0:
+		words = append(words, tens[n / 10] + "-" + ones[n % 10])
+	case n >= 20:
+		words = append(words, tens[n / 10])
+	case n > 0:
+		words = append(words, ones[n])
+	}
+	return strings.Join(words, " ")
+}
+
+// spokenRunway reads a runway designator digit by digit with its side, as
+// on the radio: "17" is "one seven" and "09L" "zero nine left"
+func spokenRunway(id string) string {
+	var words []string
+	for _, r := range strings.ToUpper(strings.TrimSpace(id)) {
+		switch {
+		case r >= '0' && r <= '9':
+			words = append(words, ones[r - '0'])
+		case r == 'L':
+			words = append(words, "left")
+		case r == 'R':
+			words = append(words, "right")
+		case r == 'C':
+			words = append(words, "center")
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+var phonetic = map[rune]string{
+	'A': "Alfa", 'B': "Bravo", 'C': "Charlie", 'D': "Delta", 'E': "Echo", 'F': "Foxtrot", 'G': "Golf",
+	'H': "Hotel", 'I': "India", 'J': "Juliett", 'K': "Kilo", 'L': "Lima", 'M': "Mike", 'N': "November",
+	'O': "Oscar", 'P': "Papa", 'Q': "Quebec", 'R': "Romeo", 'S': "Sierra", 'T': "Tango", 'U': "Uniform",
+	'V': "Victor", 'W': "Whiskey", 'X': "X-ray", 'Y': "Yankee", 'Z': "Zulu",
+}
+
+// spellIdent spells an airport identifier in the ICAO phonetic alphabet,
+// digits as words: "KJYO" is "Kilo Juliett Yankee Oscar"
+func spellIdent(ident string) string {
+	var words []string
+	for _, r := range ident {
+		switch {
+		case phonetic[r] != "":
+			words = append(words, phonetic[r])
+		case r >= '0' && r <= '9':
+			words = append(words, ones[r - '0'])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// escapeXML escapes text for an SSML element
+func escapeXML(text string) string {
+	var s strings.Builder
+	xml.EscapeText(&s, []byte(text))
+	return s.String()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// runwayLength looks up the length of a runway in feet
+func runwayLength(airportID, runwayID string) (float64, error) {
+	rwy, err := lookupRunway(airportID, runwayID)
+	if err != nil {
+		return 0, err
+	}
+	return rwy.Length, nil
+}
+
+// lookupRunway finds a runway in the embedded airport data
+func lookupRunway(airportID, runwayID string) (*airports.Runway, error) {
+	airport, err := lookupAirport(airportID)
+	if err != nil {
+		return nil, err
+	}
+	rwy, _, err := airport.Runway(runwayID)
+	return rwy, err
+}
+
+// lookupAirport finds an airport in the embedded airport data
+func lookupAirport(airportID string) (*airports.Airport, error) {
+	provider, err := airports.Embedded()
+	if err != nil {
+		return nil, err
+	}
+	return airports.Resolve(context.Background(), provider, airportID)
+}
+
+// summary formats the briefing as one line to text to a safety pilot,
+// e.g. "KJYO RWY17, 2200 lbs, 30 °C, 8 kt HW: TO 50 ft 1,850 ft, margin
+// 3,150 ft (63%), Vr 48, V50 54". The margin needs the available distance
+// (0 leaves it out); warnings are appended.
+func summary(b *briefing, airportID, runwayID string, available float64) string {
+	params, result := b.Params, b.Result
+	
+	var parts []string
+	if airportID != "" || runwayID != "" {
+		where := airports.NormalizeIdent(airportID)
+		if runwayID != "" {
+			where = strings.TrimSpace(where + " RWY" + strings.ToUpper(runwayID))
+		}
+		parts = append(parts, where)
+	}
+	parts = append(parts, fmt.Sprintf("%.0f lbs", params.Weight), fmt.Sprintf("%.0f °C", params.Temperature))
+	if params.PressureAltitude != 0 {
+		parts = append(parts, fmt.Sprintf("PA %s ft", thousands(params.PressureAltitude)))
+	}
+	if params.FlapSetting != performance.FlapsCharted {
+		parts = append(parts, "flaps " + string(params.FlapSetting) + "°")
+	}
+	switch wind := math.Round(params.WindComponent); {
+	case wind > 0:
+		parts = append(parts, fmt.Sprintf("%.0f kt HW", wind))
+	case wind < 0:
+		parts = append(parts, fmt.Sprintf("%.0f kt TW", -wind))
+	default:
+		parts = append(parts, "no wind")
+	}
+	
+	figures := []string{"TO 50 ft " + thousands(result.TakeoffDistance) + " ft" + formatTolerance(result.Tolerance, "imperial")}
+	if len(result.Adjustments) > 0 {
+		figures[0] += " (adjusted, not POH)"
+	}
+	if contaminated(b) {
+		figures[0] += " (clean wing only)"
+	}
+	if available > 0 {
+		margin := available - result.TakeoffDistance
+		if margin >= 0 {
+			figures = append(figures, fmt.Sprintf("margin %s ft (%.0f%%)", thousands(margin), margin / available * 100))
+		} else {
+			figures = append(figures, fmt.Sprintf("SHORT by %s ft", thousands(-margin)))
+		}
+	}
+	asi := b.Profile.Speeds.ASI
+	figures = append(figures, "Vr " + asi.Callout(result.LiftoffSpeed), "V50 " + asi.Callout(result.BarrierSpeed))
+	
+	line := strings.Join(parts, ", ") + ": " + strings.Join(figures, ", ")
+	if warning := crosswindWarning(b); warning != "" {
+		line += ". " + warning
+	}
+	for _, a := range b.Advisories {
+		if a.Severity == aircraft.Warning {
+			line += ". " + a.String()
+		}
+	}
+	return line
+}
+
+// thousands formats a rounded number with comma thousands separators
+func thousands(v float64) string {
+	s := strconv.FormatFloat(math.Abs(math.Round(v)), 'f', 0, 64)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if math.Round(v) < 0 {
+		s = "-" + s
+	}
+	return s
+}
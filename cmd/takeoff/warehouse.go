@@ -0,0 +1,55 @@
+package main
+
+import (
+	"strings"
+	"time"
+	
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/policy"
+	"github.com/ryanbmilbourne/otto-perf/warehouse"
+)
+
+// warehouseResult records the briefing for the warehouse. The verdict is
+// NO-GO when a policy rule says so or the takeoff distance exceeds the
+// available distance, CAUTION with any warning or caution, and GO
+// otherwise.
+func warehouseResult(b *briefing, findings []policy.Finding, airportID, runwayID string, available float64) warehouse.Result {
+	r := warehouse.Result{
+		Time:             time.Now().UTC(),
+		Aircraft:         b.Profile.ID,
+		Airport:          strings.ToUpper(airportID),
+		Runway:           runwayID,
+		PressureAltitude: b.Params.PressureAltitude,
+		DensityAltitude:  atmosphere.DensityAltitude(b.Params.PressureAltitude, b.Params.Temperature),
+		Temperature:      b.Params.Temperature,
+		Weight:           b.Params.Weight,
+		Headwind:         b.Params.WindComponent,
+		Distance:         b.Result.TakeoffDistance,
+		Available:        available,
+		Verdict:          warehouse.Go,
+	}
+	
+	if warning := crosswindWarning(b); warning != "" {
+		r.Findings = append(r.Findings, warning)
+	}
+	for _, a := range b.Advisories {
+		if a.Severity >= aircraft.Caution {
+			r.Findings = append(r.Findings, a.String())
+		}
+	}
+	if len(r.Findings) > 0 {
+		r.Verdict = warehouse.Caution
+	}
+	
+	for _, f := range findings {
+		if len(f.Missing) == 0 && f.Rule.Verdict == policy.NoGo {
+			r.Verdict = warehouse.NoGo
+		}
+	}
+	if available > 0 && b.Result.TakeoffDistance > available {
+		r.Verdict = warehouse.NoGo
+		r.Findings = append(r.Findings, "Takeoff distance exceeds the available distance")
+	}
+	return r
+}
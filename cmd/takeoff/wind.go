@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// runwayWind is a reported wind resolved along the departure runway
+type runwayWind struct {
+	Wind       wind.Wind
+	Runway     string
+	Heading    wind.Direction
+	Variation  float64
+	Components wind.Components
+}
+
+// resolveRunwayWind decomposes a reported wind along a runway. With an
+// airport the runway's surveyed true heading and the airport's magnetic
+// variation are used; otherwise the heading comes from the runway number
+// (magnetic) and the variation must be given when the wind is true.
+func resolveRunwayWind(direction, speed float64, reference, airportID, runwayID string, magVar *float64) (*runwayWind, error) {
+	ref, err := wind.ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if runwayID == "" {
+		return nil, fmt.Errorf("-runway is required with -wind-dir/-wind-speed")
+	}
+	
+	rw := &runwayWind{
+		Wind:   wind.Wind{Speed: speed},
+		Runway: runwayID,
+	}
+	if ref == wind.True {
+		rw.Wind.From = wind.TrueDirection(direction)
+	} else {
+		rw.Wind.From = wind.MagneticDirection(direction)
+	}
+	
+	haveVariation := false
+	if airportID != "" {
+		provider, err := airports.Embedded()
+		if err != nil {
+			return nil, err
+		}
+		airport, err := airports.Resolve(context.Background(), provider, airportID)
+		if err != nil {
+			return nil, err
+		}
+		_, end, err := airport.Runway(runwayID)
+		if err != nil {
+			return nil, err
+		}
+		if end == nil {
+			return nil, fmt.Errorf("specify a single runway end (e.g. 17), not %s", runwayID)
+		}
+		
+		rw.Heading = wind.TrueDirection(end.TrueHeading)
+		rw.Variation = airport.MagneticVariation
+		haveVariation = true
+	} else {
+		if rw.Heading, err = wind.RunwayHeading(runwayID); err != nil {
+			return nil, err
+		}
+	}
+	
+	if magVar != nil {
+		rw.Variation = *magVar
+		haveVariation = true
+	}
+	
+	// Mixing references without knowing the variation would skew the crosswind
+	if rw.Wind.From.Reference != rw.Heading.Reference && !haveVariation {
+		return nil, fmt.Errorf("wind is %s but runway heading is %s; give -airport or -magvar so they can be converted",
+			rw.Wind.From.Reference, rw.Heading.Reference)
+	}
+	
+	rw.Components = wind.Decompose(rw.Wind, rw.Heading, rw.Variation)
+	return rw, nil
+}
+
+// displayComponents prints the headwind and crosswind components of a runway wind
+func displayComponents(c wind.Components, unitSystem string) {
+	if c.Headwind >= 0 {
+		fmt.Printf("  Headwind: %.0f knots%s\n", c.Headwind, inKilometersPerHour(c.Headwind, unitSystem))
+	} else {
+		fmt.Printf("  Tailwind: %.0f knots%s\n", -c.Headwind, inKilometersPerHour(-c.Headwind, unitSystem))
+	}
+	
+	crosswind := math.Abs(c.Crosswind)
+	if math.Round(crosswind) == 0 {
+		fmt.Printf("  Crosswind: none\n")
+	} else {
+		fmt.Printf("  Crosswind: %.0f knots%s from the %s\n", crosswind, inKilometersPerHour(crosswind, unitSystem), c.CrosswindSide())
+	}
+}
+
+// crosswindWarning returns the warning for a crosswind above the maximum
+// demonstrated crosswind, or ""
+func crosswindWarning(b *briefing) string {
+	maxCrosswind := b.Profile.Limits.MaxDemonstratedCrosswind
+	if b.Wind == nil || maxCrosswind <= 0 || math.Abs(b.Wind.Components.Crosswind) <= maxCrosswind {
+		return ""
+	}
+	return fmt.Sprintf("WARNING: crosswind exceeds the %.0f knot maximum demonstrated crosswind", maxCrosswind)
+}
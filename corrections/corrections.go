@@ -0,0 +1,162 @@
+// Package corrections loads operator correction factors, such as an
+// insurer's required margin or the effect of an STC, from a JSON rules
+// file and applies them to an aircraft profile's calculators. Applied
+// corrections are listed in every result as adjustments, so they are
+// always disclosed and never mistaken for POH figures.
+package corrections
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// Rule is one operator correction
+type Rule struct {
+	Description     string   `json:"description"`                // Shown with every result it applies to
+	TakeoffDistance float64  `json:"takeoff_distance,omitempty"` // Fractional change in takeoff distance, e.g. 0.15
+	ClimbRate       float64  `json:"climb_rate,omitempty"`       // Fractional change in rate of climb, e.g. -0.05
+	Aircraft        []string `json:"aircraft,omitempty"`         // Profile IDs it applies to; all when empty
+	Surfaces        []string `json:"surfaces,omitempty"`         // Runway surfaces it applies to, e.g. TURF; all when empty
+}
+
+// Rules is a corrections file
+type Rules struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Conditions are what a rule's filters are matched against
+type Conditions struct {
+	Aircraft string // Profile ID
+	Surface  string // Departure runway surface, "" when unknown
+}
+
+// DefaultPath returns the per-user rules file location
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "otto", "corrections.json"), nil
+}
+
+// Load reads the rules file at path (or the default location when path is
+// empty). A missing default file is not an error and gives no rules.
+// Unknown fields are rejected so a misspelt filter cannot silently widen
+// a rule.
+func Load(path string) (*Rules, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return &Rules{}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		return &Rules{}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	rules := &Rules{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(rules); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// validate checks that every rule is described and changes something
+// within reason
+func (r *Rules) validate() error {
+	for i, rule := range r.Rules {
+		switch {
+		case strings.TrimSpace(rule.Description) == "":
+			return fmt.Errorf("rule %d has no description", i+1)
+		case rule.TakeoffDistance == 0 && rule.ClimbRate == 0:
+			return fmt.Errorf("rule %q changes neither takeoff_distance nor climb_rate", rule.Description)
+		case rule.TakeoffDistance <= -1 || rule.ClimbRate <= -1:
+			return fmt.Errorf("rule %q removes the whole distance or climb rate", rule.Description)
+		}
+	}
+	return nil
+}
+
+// Matching returns the rules whose filters match the conditions. Surfaces
+// also match on the code before a dash, so TURF matches NASR's TURF-G. A
+// rule filtered by surface does not match when the surface is unknown.
+func (r *Rules) Matching(c Conditions) []Rule {
+	base, _, _ := strings.Cut(c.Surface, "-")
+	var matched []Rule
+	for _, rule := range r.Rules {
+		if matches(rule.Aircraft, c.Aircraft) && (matches(rule.Surfaces, c.Surface) || matches(rule.Surfaces, base)) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// Apply returns a copy of the profile whose calculators include the
+// matching rules as adjustments
+func (r *Rules) Apply(p *aircraft.Profile, c Conditions) *aircraft.Profile {
+	var takeoff, climb []performance.Adjustment
+	for _, rule := range r.Matching(c) {
+		if rule.TakeoffDistance != 0 {
+			takeoff = append(takeoff, performance.Adjustment{Description: rule.Description, Factor: rule.TakeoffDistance})
+		}
+		if rule.ClimbRate != 0 {
+			climb = append(climb, performance.Adjustment{Description: rule.Description, Factor: rule.ClimbRate})
+		}
+	}
+
+	configured := *p
+	if len(takeoff) > 0 {
+		newTakeoff := p.NewTakeoffCalculator
+		configured.NewTakeoffCalculator = func() *performance.TakeoffCalculator {
+			calc := newTakeoff()
+			for _, a := range takeoff {
+				calc.AdjustDistance(a)
+			}
+			return calc
+		}
+	}
+	if len(climb) > 0 {
+		newClimb := p.NewClimbCalculator
+		configured.NewClimbCalculator = func() *performance.ClimbCalculator {
+			calc := newClimb()
+			for _, a := range climb {
+				calc.AdjustRateOfClimb(a)
+			}
+			return calc
+		}
+	}
+	return &configured
+}
+
+// matches reports whether a value is in a filter list, ignoring case; an
+// empty list matches everything
+func matches(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if strings.EqualFold(strings.TrimSpace(f), value) {
+			return true
+		}
+	}
+	return false
+}
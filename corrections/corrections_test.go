@@ -0,0 +1,99 @@
+package corrections
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func writeRules(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "corrections.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAndApply(t *testing.T) {
+	rules, err := Load(writeRules(t, `{"rules": [
+		{"description": "Insurance margin", "takeoff_distance": 0.25},
+		{"description": "Grass", "takeoff_distance": 0.15, "surfaces": ["turf"]},
+		{"description": "Cessna only", "climb_rate": -0.1, "aircraft": ["c172"]},
+		{"description": "Vortex generators STC", "climb_rate": 0.05, "aircraft": ["PA28-161"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		conditions Conditions
+		want       []string
+	}{
+		{Conditions{Aircraft: "pa28-161", Surface: "ASPH"}, []string{"Insurance margin", "Vortex generators STC"}},
+		{Conditions{Aircraft: "pa28-161", Surface: "TURF-G"}, []string{"Insurance margin", "Grass", "Vortex generators STC"}},
+		{Conditions{Aircraft: "pa28-161"}, []string{"Insurance margin", "Vortex generators STC"}},
+	}
+	for _, tc := range testCases {
+		var got []string
+		for _, r := range rules.Matching(tc.conditions) {
+			got = append(got, r.Description)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%+v: got %v, expected %v", tc.conditions, got, tc.want)
+		}
+	}
+
+	profile, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := performance.TakeoffParams{PressureAltitude: 1000, Temperature: 20, Weight: 2325}
+	base, err := profile.NewTakeoffCalculator().CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	corrected := rules.Apply(profile, Conditions{Aircraft: profile.ID, Surface: "TURF"})
+	result, err := corrected.NewTakeoffCalculator().CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := base.TakeoffDistance * 1.25 * 1.15; math.Abs(result.TakeoffDistance-want) > 1 {
+		t.Errorf("Expected %.0f ft with both corrections, got %.0f", want, result.TakeoffDistance)
+	}
+	if len(result.Adjustments) != 2 || result.Adjustments[0].Description != "Insurance margin" {
+		t.Errorf("Expected the corrections disclosed as adjustments, got %v", result.Adjustments)
+	}
+	if climb := corrected.NewClimbCalculator().RateOfClimb(0, 15); math.Abs(climb-profile.NewClimbCalculator().RateOfClimb(0, 15)*1.05) > 0.1 {
+		t.Errorf("Expected the climb rate raised 5%%, got %.0f fpm", climb)
+	}
+
+	// The original profile is untouched
+	if again, _ := profile.NewTakeoffCalculator().CalculateTakeoff(params); again.TakeoffDistance != base.TakeoffDistance {
+		t.Errorf("Apply changed the original profile")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := map[string]string{
+		`{"rules": [{"description": "Typo", "takeof_distance": 0.1}]}`: "unknown field",
+		`{"rules": [{"takeoff_distance": 0.1}]}`:                       "no description",
+		`{"rules": [{"description": "Nothing"}]}`:                      "changes neither",
+		`{"rules": [{"description": "Too much", "climb_rate": -1}]}`:   "removes the whole",
+	}
+	for contents, want := range testCases {
+		if _, err := Load(writeRules(t, contents)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", contents, want, err)
+		}
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing explicit file")
+	}
+}
@@ -0,0 +1,420 @@
+// Package digitize assembles a regular-grid takeoff chart from points read
+// off a POH figure by hand. The readings need not fall on the chart's
+// lines: a smooth model is fitted through them, pulled onto the readings
+// nearby, and sampled on the grid, so adding an aircraft takes a few dozen
+// readings instead of a value at every line crossing.
+package digitize
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// Point is a zero-wind takeoff distance read off a chart, in the chart's
+// own units
+type Point struct {
+	Line             int // Line of the CSV file, from 1
+	PressureAltitude float64
+	Temperature      float64 // °C
+	Weight           float64
+	Distance         float64
+}
+
+// ReadCSV reads points with the columns pressure_altitude, temperature_c
+// or temperature_f, weight and distance. Lines starting with # are
+// comments.
+func ReadCSV(r io.Reader) ([]Point, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("no points")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	temperature := "temperature_c"
+	if _, ok := columns[temperature]; !ok {
+		temperature = "temperature_f"
+	}
+	for _, name := range []string{"pressure_altitude", temperature, "weight", "distance"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("points are missing the %s column", strings.TrimSuffix(name, "_f")+"_c")
+		}
+	}
+
+	var points []Point
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		values := make(map[string]float64)
+		for _, name := range []string{"pressure_altitude", temperature, "weight", "distance"} {
+			s := ""
+			if i := columns[name]; i < len(record) {
+				s = strings.TrimSpace(record[i])
+			}
+			if values[name], err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", line, name, s)
+			}
+		}
+		p := Point{
+			Line:             line,
+			PressureAltitude: values["pressure_altitude"],
+			Temperature:      values[temperature],
+			Weight:           values["weight"],
+			Distance:         values["distance"],
+		}
+		if temperature == "temperature_f" {
+			p.Temperature = math.Round(performance.ConvertFahrenheitToCelsius(p.Temperature)*10) / 10
+		}
+		if p.Distance <= 0 {
+			return nil, fmt.Errorf("line %d: distance must be positive", line)
+		}
+		points = append(points, p)
+	}
+	if len(points) == 0 {
+		return nil, errors.New("no points")
+	}
+	return points, nil
+}
+
+// Grid is the lines of the chart to assemble, each ascending
+type Grid struct {
+	Altitudes    []float64
+	Temperatures []float64
+	Weights      []float64
+}
+
+// GridOf returns the grid of the distinct values read on each axis
+func GridOf(points []Point) Grid {
+	var g Grid
+	for _, p := range points {
+		g.Altitudes = append(g.Altitudes, p.PressureAltitude)
+		g.Temperatures = append(g.Temperatures, p.Temperature)
+		g.Weights = append(g.Weights, p.Weight)
+	}
+	return Grid{Altitudes: distinct(g.Altitudes), Temperatures: distinct(g.Temperatures), Weights: distinct(g.Weights)}
+}
+
+// distinct returns the sorted distinct values
+func distinct(values []float64) []float64 {
+	sort.Float64s(values)
+	var out []float64
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// axes returns the grid's axes with their names
+func (g Grid) axes() []struct {
+	name   string
+	values []float64
+} {
+	return []struct {
+		name   string
+		values []float64
+	}{{"altitudes", g.Altitudes}, {"temperatures", g.Temperatures}, {"weights", g.Weights}}
+}
+
+// validate checks that every axis ascends and covers the points
+func (g Grid) validate(points []Point) error {
+	for _, axis := range g.axes() {
+		if len(axis.values) < 2 {
+			return fmt.Errorf("the grid needs at least two %s", axis.name)
+		}
+		for i := 1; i < len(axis.values); i++ {
+			if axis.values[i] <= axis.values[i-1] {
+				return fmt.Errorf("grid %s must ascend: %g after %g", axis.name, axis.values[i], axis.values[i-1])
+			}
+		}
+	}
+	var outside []string
+	for _, p := range points {
+		if !within(p.PressureAltitude, g.Altitudes) || !within(p.Temperature, g.Temperatures) || !within(p.Weight, g.Weights) {
+			outside = append(outside, strconv.Itoa(p.Line))
+		}
+	}
+	if len(outside) > 0 {
+		return fmt.Errorf("points outside the grid on lines %s", strings.Join(outside, ", "))
+	}
+	return nil
+}
+
+// within reports whether v is inside the range of an ascending axis
+func within(v float64, axis []float64) bool {
+	return v >= axis[0] && v <= axis[len(axis)-1]
+}
+
+// Fit is a smooth model of the readings, corrected onto them nearby
+type Fit struct {
+	Terms  int // Polynomial terms of the model: 10 quadratic, 4 linear
+	grid   Grid
+	coef   []float64
+	points []Point
+	resid  []float64 // Log residuals of the readings from the model
+}
+
+// bandwidth is the distance, as a fraction of each axis's range, at which a
+// reading's correction of the model has fallen to half
+const bandwidth = 0.15
+
+// NewFit fits a model of the logarithm of the distance, quadratic in
+// altitude, temperature and weight with their cross terms, or linear with
+// fewer than 15 readings. Takeoff distance grows about exponentially with
+// density altitude and weight, so its logarithm is close to a low order
+// polynomial.
+func NewFit(points []Point, grid Grid) (*Fit, error) {
+	if err := grid.validate(points); err != nil {
+		return nil, err
+	}
+	f := &Fit{grid: grid, points: points}
+	for _, terms := range []int{10, 4} {
+		if len(points) < terms+terms/2 {
+			continue
+		}
+		rows := make([][]float64, len(points))
+		ys := make([]float64, len(points))
+		for i, p := range points {
+			rows[i] = f.basis(p.PressureAltitude, p.Temperature, p.Weight)[:terms]
+			ys[i] = math.Log(p.Distance)
+		}
+		if coef, err := leastSquares(rows, ys); err == nil {
+			f.Terms, f.coef = terms, coef
+			break
+		}
+	}
+	if f.coef == nil {
+		return nil, fmt.Errorf("%d points are too few or too alike to fit; read at least 6 spread over every axis", len(points))
+	}
+	for _, p := range points {
+		f.resid = append(f.resid, math.Log(p.Distance)-f.model(p.PressureAltitude, p.Temperature, p.Weight))
+	}
+	return f, nil
+}
+
+// normalize maps the inputs onto [0, 1] over the grid
+func (f *Fit) normalize(alt, temp, weight float64) (a, t, w float64) {
+	scale := func(v float64, axis []float64) float64 {
+		return (v - axis[0]) / (axis[len(axis)-1] - axis[0])
+	}
+	return scale(alt, f.grid.Altitudes), scale(temp, f.grid.Temperatures), scale(weight, f.grid.Weights)
+}
+
+// basis returns the polynomial terms at a point, linear ones first
+func (f *Fit) basis(alt, temp, weight float64) []float64 {
+	a, t, w := f.normalize(alt, temp, weight)
+	return []float64{1, a, t, w, a * a, t * t, w * w, a * t, a * w, t * w}
+}
+
+// model returns the logarithm of the distance by the fitted polynomial
+func (f *Fit) model(alt, temp, weight float64) float64 {
+	var y float64
+	for i, x := range f.basis(alt, temp, weight)[:f.Terms] {
+		y += f.coef[i] * x
+	}
+	return y
+}
+
+// Distance estimates the distance at a point: the model, corrected by the
+// residuals of the readings weighted by their nearness, so the estimate
+// meets a reading at its point and the model away from the readings
+func (f *Fit) Distance(alt, temp, weight float64) float64 {
+	a, t, w := f.normalize(alt, temp, weight)
+	h2 := bandwidth * bandwidth
+	total, sum := 1.0, 0.0 // The model counts as a reading one bandwidth away
+	for i, p := range f.points {
+		pa, pt, pw := f.normalize(p.PressureAltitude, p.Temperature, p.Weight)
+		d2 := ((a-pa)*(a-pa) + (t-pt)*(t-pt) + (w-pw)*(w-pw)) / h2
+		if d2 < 1e-12 {
+			d2 = 1e-12
+		}
+		total += 1 / d2
+		sum += f.resid[i] / d2
+	}
+	return math.Exp(f.model(alt, temp, weight) + sum/total)
+}
+
+// Chart samples the fit on the grid, with distances rounded to whole
+// units. The wind lines, speeds, units and source come from template; its
+// speeds are interpolated onto the grid's weights.
+func (f *Fit) Chart(template *performance.TakeoffChart) *performance.TakeoffChart {
+	chart := &performance.TakeoffChart{
+		Source:          template.Source,
+		Units:           template.Units,
+		Altitudes:       f.grid.Altitudes,
+		Temperatures:    f.grid.Temperatures,
+		Weights:         f.grid.Weights,
+		Headwinds:       template.Headwinds,
+		HeadwindFactors: template.HeadwindFactors,
+		Tailwinds:       template.Tailwinds,
+		TailwindFactors: template.TailwindFactors,
+	}
+	for _, alt := range f.grid.Altitudes {
+		var row []float64
+		for _, weight := range f.grid.Weights {
+			for _, temp := range f.grid.Temperatures {
+				row = append(row, math.Round(f.Distance(alt, temp, weight)))
+			}
+		}
+		chart.Distances = append(chart.Distances, row)
+	}
+	for _, weight := range f.grid.Weights {
+		chart.LiftoffSpeeds = append(chart.LiftoffSpeeds, interpolate(template.Weights, template.LiftoffSpeeds, weight))
+		chart.BarrierSpeeds = append(chart.BarrierSpeeds, interpolate(template.Weights, template.BarrierSpeeds, weight))
+	}
+	return chart
+}
+
+// interpolate reads ys at x along ascending xs, holding the end values
+// beyond them; without values it returns 0
+func interpolate(xs, ys []float64, x float64) float64 {
+	switch {
+	case len(xs) == 0 || len(ys) != len(xs):
+		return 0
+	case x <= xs[0]:
+		return ys[0]
+	case x >= xs[len(xs)-1]:
+		return ys[len(ys)-1]
+	}
+	i := sort.SearchFloat64s(xs, x)
+	frac := (x - xs[i-1]) / (xs[i] - xs[i-1])
+	return math.Round((ys[i-1]+frac*(ys[i]-ys[i-1]))*10) / 10
+}
+
+// Residual is a reading compared with the assembled chart
+type Residual struct {
+	Point    Point
+	Chart    float64 // The chart's distance at the point, in its units
+	Residual float64 // Chart less reading
+}
+
+// Residuals computes each reading's zero-wind distance with the chart's
+// own calculator, so the interpolation used in flight is what is checked
+func Residuals(chart *performance.TakeoffChart, points []Point) ([]Residual, error) {
+	calc, err := performance.NewChartTakeoffCalculator(chart)
+	if err != nil {
+		return nil, err
+	}
+	toFeet, toPounds, fromFeet := same, same, same
+	if chart.Units.Altitude == "m" {
+		toFeet = units.MetersToFeet
+	}
+	if chart.Units.Weight == "kg" {
+		toPounds = units.KilogramsToPounds
+	}
+	if chart.Units.Distance == "m" {
+		fromFeet = units.FeetToMeters
+	}
+
+	residuals := make([]Residual, len(points))
+	for i, p := range points {
+		result, err := calc.CalculateTakeoff(performance.TakeoffParams{
+			PressureAltitude: toFeet(p.PressureAltitude),
+			Temperature:      p.Temperature,
+			Weight:           toPounds(p.Weight),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", p.Line, err)
+		}
+		d := fromFeet(result.TakeoffDistance)
+		residuals[i] = Residual{Point: p, Chart: d, Residual: d - p.Distance}
+	}
+	return residuals, nil
+}
+
+func same(v float64) float64 { return v }
+
+// Monotonicity lists the places where the chart's distance falls as the
+// altitude, temperature or weight rises, which a takeoff chart never does;
+// they point at a misread or a line read with the wrong label
+func Monotonicity(chart *performance.TakeoffChart) []string {
+	nt, nw := len(chart.Temperatures), len(chart.Weights)
+	at := func(a, w, t int) float64 { return chart.Distances[a][w*nt+t] }
+
+	var problems []string
+	for a := range chart.Altitudes {
+		for w := 0; w < nw; w++ {
+			for t := 0; t < nt; t++ {
+				d := at(a, w, t)
+				node := fmt.Sprintf("at %g ft, %g, %g°C", chart.Altitudes[a], chart.Weights[w], chart.Temperatures[t])
+				if a > 0 && d < at(a-1, w, t) {
+					problems = append(problems, fmt.Sprintf("%s: %g is less than %g at the altitude below", node, d, at(a-1, w, t)))
+				}
+				if w > 0 && d < at(a, w-1, t) {
+					problems = append(problems, fmt.Sprintf("%s: %g is less than %g at the weight below", node, d, at(a, w-1, t)))
+				}
+				if t > 0 && d < at(a, w, t-1) {
+					problems = append(problems, fmt.Sprintf("%s: %g is less than %g at the temperature below", node, d, at(a, w, t-1)))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// leastSquares solves the normal equations of rows·x = ys by Gaussian
+// elimination with partial pivoting
+func leastSquares(rows [][]float64, ys []float64) ([]float64, error) {
+	n := len(rows[0])
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n+1)
+		for k, row := range rows {
+			for j := 0; j < n; j++ {
+				m[i][j] += row[i] * row[j]
+			}
+			m[i][n] += row[i] * ys[k]
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-10 {
+			return nil, errors.New("singular system")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		for r := 0; r < n; r++ {
+			if r == col {
+				continue
+			}
+			factor := m[r][col] / m[col][col]
+			for c := col; c <= n; c++ {
+				m[r][c] -= factor * m[col][c]
+			}
+		}
+	}
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = m[i][n] / m[i][i]
+	}
+	return x, nil
+}
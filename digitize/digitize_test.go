@@ -0,0 +1,140 @@
+package digitize
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func TestReadCSV(t *testing.T) {
+	points, err := ReadCSV(strings.NewReader(`pressure_altitude,temperature_f,weight,distance
+# read off Figure 5-7
+2000, 59, 2325, 1800
+4000, 41, 2000, 1500
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 || points[0].Line != 3 || points[0].Temperature != 15 || points[1].Temperature != 5 {
+		t.Errorf("Got %+v", points)
+	}
+
+	for _, csv := range []string{
+		"",
+		"pressure_altitude,weight,distance\n0,2325,1800\n",
+		"pressure_altitude,temperature_c,weight,distance\n0,15,2325,far\n",
+		"pressure_altitude,temperature_c,weight,distance\n0,15,2325,0\n",
+		"pressure_altitude,temperature_c,weight,distance\n",
+	} {
+		if _, err := ReadCSV(strings.NewReader(csv)); err == nil {
+			t.Errorf("%q: expected an error", csv)
+		}
+	}
+}
+
+// TestFit reassembles the PA-28-161 chart from readings taken between its
+// lines and checks it against the original at every node
+func TestFit(t *testing.T) {
+	profile, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calculator := profile.NewTakeoffCalculator()
+	original := calculator.Chart()
+
+	var points []Point
+	for i, alt := range []float64{0, 1500, 2500, 3500, 5000, 6000, 7000} {
+		for j, temp := range []float64{-10, 5, 20, 35} {
+			weight := []float64{1700, 1950, 2150, 2325}[(i+j)%4]
+			result, err := calculator.CalculateTakeoff(performance.TakeoffParams{PressureAltitude: alt, Temperature: temp, Weight: weight})
+			if err != nil {
+				t.Fatal(err)
+			}
+			points = append(points, Point{Line: len(points) + 2, PressureAltitude: alt, Temperature: temp, Weight: weight, Distance: math.Round(result.TakeoffDistance)})
+		}
+	}
+
+	grid := Grid{Altitudes: []float64{0, 2000, 4000, 6000, 7000}, Temperatures: []float64{-10, 0, 10, 20, 30, 35}, Weights: []float64{1700, 2000, 2325}}
+	fit, err := NewFit(points, grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Terms != 10 {
+		t.Errorf("Expected the quadratic model, got %d terms", fit.Terms)
+	}
+	chart := fit.Chart(original)
+	if len(chart.LiftoffSpeeds) != 3 || chart.LiftoffSpeeds[2] != original.LiftoffSpeeds[len(original.LiftoffSpeeds)-1] {
+		t.Errorf("Lift-off speeds %v not interpolated from %v", chart.LiftoffSpeeds, original.LiftoffSpeeds)
+	}
+
+	for a, alt := range grid.Altitudes {
+		for w, weight := range grid.Weights {
+			for k, temp := range grid.Temperatures {
+				want, err := calculator.CalculateTakeoff(performance.TakeoffParams{PressureAltitude: alt, Temperature: temp, Weight: weight})
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := chart.Distances[a][w*len(grid.Temperatures)+k]
+				if math.Abs(got-want.TakeoffDistance) > 0.05*want.TakeoffDistance {
+					t.Errorf("At %g ft, %g°C, %g lbs: %g ft, original %.0f ft", alt, temp, weight, got, want.TakeoffDistance)
+				}
+			}
+		}
+	}
+
+	data, err := json.Marshal(chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, new(performance.TakeoffChart)); err != nil {
+		t.Errorf("Assembled chart does not decode: %v", err)
+	}
+
+	residuals, err := Residuals(chart, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range residuals {
+		if math.Abs(r.Residual) > 0.03*r.Point.Distance {
+			t.Errorf("Line %d: residual %+.0f ft of %.0f ft", r.Point.Line, r.Residual, r.Point.Distance)
+		}
+	}
+	if problems := Monotonicity(chart); len(problems) > 0 {
+		t.Errorf("Unexpected monotonicity problems: %v", problems)
+	}
+}
+
+func TestFitErrors(t *testing.T) {
+	points := []Point{
+		{Line: 2, PressureAltitude: 0, Temperature: 0, Weight: 2000, Distance: 1500},
+		{Line: 3, PressureAltitude: 4000, Temperature: 30, Weight: 2325, Distance: 2800},
+	}
+	if _, err := NewFit(points, GridOf(points)); err == nil {
+		t.Errorf("Expected an error fitting two points")
+	}
+	grid := Grid{Altitudes: []float64{0, 2000}, Temperatures: []float64{0, 30}, Weights: []float64{2000, 2325}}
+	if _, err := NewFit(points, grid); err == nil || !strings.Contains(err.Error(), "lines 3") {
+		t.Errorf("Expected the point outside the grid, got %v", err)
+	}
+	grid.Altitudes = []float64{4000, 0}
+	if _, err := NewFit(points, grid); err == nil {
+		t.Errorf("Expected an error for a descending axis")
+	}
+}
+
+func TestMonotonicity(t *testing.T) {
+	chart := &performance.TakeoffChart{
+		Altitudes:    []float64{0, 2000},
+		Temperatures: []float64{0, 20},
+		Weights:      []float64{2325},
+		Distances:    [][]float64{{1500, 1700}, {1800, 1750}},
+	}
+	problems := Monotonicity(chart)
+	if len(problems) != 1 || !strings.Contains(problems[0], "temperature below") {
+		t.Errorf("Got %v", problems)
+	}
+}
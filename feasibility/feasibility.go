@@ -0,0 +1,164 @@
+// Package feasibility rolls the runway, climb, fuel and weather margins of
+// a planned flight into a single 0-100 score, keeping the breakdown so the
+// number can be explained.
+//
+// The score is an at-a-glance indication for pilots new to the aircraft,
+// not a go/no-go decision: each factor is scored against fixed thresholds
+// that are deliberately simple and are printed with the result.
+package feasibility
+
+import (
+	"fmt"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// Weights of the factors in the total score
+const (
+	RunwayWeight  = 30
+	ClimbWeight   = 20
+	FuelWeight    = 25
+	WeatherWeight = 25
+)
+
+// Factor is the score of one margin with the reason for it
+type Factor struct {
+	Name   string
+	Score  float64 // 0 (no margin) to 100 (ample margin)
+	Weight float64
+	Detail string
+}
+
+// Score is the combined feasibility score of a flight
+type Score struct {
+	Total   float64 // Weighted mean of the factors, 0 if any factor is 0
+	Factors []Factor
+}
+
+// Rating describes the total: "GOOD" from 80, "CAUTION" from 50, else "POOR".
+// A total of 0 means a factor has no margin at all and reads "NOT FEASIBLE".
+func (s Score) Rating() string {
+	switch {
+	case s.Total >= 80:
+		return "GOOD"
+	case s.Total >= 50:
+		return "CAUTION"
+	case s.Total > 0:
+		return "POOR"
+	}
+	return "NOT FEASIBLE"
+}
+
+// Combine weights the factors into a score. A factor with no margin makes
+// the flight not feasible however good the others are, so it zeroes the total.
+func Combine(factors ...Factor) Score {
+	s := Score{Factors: factors}
+	var sum, weights float64
+	for _, f := range factors {
+		if f.Score <= 0 {
+			return s
+		}
+		sum += f.Score * f.Weight
+		weights += f.Weight
+	}
+	if weights > 0 {
+		s.Total = sum / weights
+	}
+	return s
+}
+
+// Runway scores the takeoff distance over a 50 ft obstacle against the
+// distance available: 0 when it does not fit, 100 when it needs at most
+// half of it (the common 1.5 to 2 times safety factor)
+func Runway(required, available float64) Factor {
+	f := Factor{Name: "Runway", Weight: RunwayWeight}
+	if available <= 0 {
+		f.Detail = "no takeoff distance available"
+		return f
+	}
+	used := required / available
+	f.Score = ramp(used, 1, 0.5)
+	f.Detail = fmt.Sprintf("%.0f ft of %.0f ft available (%.0f%%)", required, available, used*100)
+	return f
+}
+
+// Climb scores the lowest rate of climb expected, normally at the cruise
+// altitude: 0 at the service ceiling rate of 100 fpm, 100 from 500 fpm
+func Climb(rate float64) Factor {
+	return Factor{
+		Name:   "Climb",
+		Score:  ramp(rate, performance.ServiceCeilingRate, 500),
+		Weight: ClimbWeight,
+		Detail: fmt.Sprintf("%.0f fpm at the cruise altitude", rate),
+	}
+}
+
+// Fuel scores the fuel left at the destination, in minutes of flight,
+// against the required reserve: 0 below the reserve, 100 from twice it
+func Fuel(remaining, reserve float64) Factor {
+	f := Factor{
+		Name:   "Fuel",
+		Weight: FuelWeight,
+		Detail: fmt.Sprintf("%.0f min remaining at the destination, %.0f min reserve required", remaining, reserve),
+	}
+	switch {
+	case remaining < reserve:
+	case reserve <= 0:
+		f.Score = 100
+	default:
+		f.Score = ramp(remaining, reserve, 2*reserve)
+	}
+	return f
+}
+
+// Conditions are the weather inputs of the weather factor
+type Conditions struct {
+	Category     weather.FlightCategory
+	Crosswind    float64 // Crosswind component at the gust speed in knots
+	MaxCrosswind float64 // Maximum demonstrated crosswind in knots, 0 if unknown
+	GustSpread   float64 // Gust speed minus the steady wind speed in knots
+}
+
+// Weather scores the conditions by their worst item. MVFR scores 50 and
+// IFR or LIFR 0 for a VFR flight; the crosswind scores 100 up to half the
+// maximum demonstrated and 0 at it; a gust spread scores 100 up to 5 kt
+// and 0 from 20 kt.
+func Weather(c Conditions) Factor {
+	f := Factor{Name: "Weather", Score: 100, Weight: WeatherWeight}
+	limit := func(score float64, detail string) {
+		if score < f.Score || f.Detail == "" {
+			f.Score, f.Detail = score, detail
+		}
+	}
+
+	switch c.Category {
+	case weather.VFR:
+		limit(100, "VFR")
+	case weather.MVFR:
+		limit(50, "MVFR")
+	default:
+		limit(0, c.Category.String()+", below VFR")
+	}
+	if c.MaxCrosswind > 0 {
+		limit(ramp(c.Crosswind/c.MaxCrosswind, 1, 0.5),
+			fmt.Sprintf("%.0f kt crosswind of %.0f kt demonstrated", c.Crosswind, c.MaxCrosswind))
+	}
+	if c.GustSpread > 0 {
+		limit(ramp(c.GustSpread, 20, 5), fmt.Sprintf("gusts %.0f kt above the wind", c.GustSpread))
+	}
+	return f
+}
+
+// ramp maps x linearly from a score of 0 at zero to 100 at full, clamped
+// to 0..100; full is less than zero for margins that improve as x falls
+func ramp(x, zero, full float64) float64 {
+	score := (x - zero) / (full - zero) * 100
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
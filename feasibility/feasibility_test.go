@@ -0,0 +1,68 @@
+package feasibility
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+func TestFactors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		factor Factor
+		score  float64
+	}{
+		{"Runway Half Used", Runway(1100, 2200), 100},
+		{"Runway Three Quarters Used", Runway(1650, 2200), 50},
+		{"Runway Too Short", Runway(2300, 2200), 0},
+		{"Runway Unknown", Runway(1100, 0), 0},
+		{"Climb Strong", Climb(600), 100},
+		{"Climb Weak", Climb(300), 50},
+		{"Climb At Ceiling", Climb(100), 0},
+		{"Fuel Double Reserve", Fuel(75, 30), 100},
+		{"Fuel Some Margin", Fuel(45, 30), 50},
+		{"Fuel Below Reserve", Fuel(25, 30), 0},
+		{"Fuel No Reserve", Fuel(10, 0), 100},
+		{"Weather Calm VFR", Weather(Conditions{Category: weather.VFR, MaxCrosswind: 17}), 100},
+		{"Weather MVFR", Weather(Conditions{Category: weather.MVFR}), 50},
+		{"Weather IFR", Weather(Conditions{Category: weather.IFR, Crosswind: 2, MaxCrosswind: 17}), 0},
+		{"Weather Crosswind", Weather(Conditions{Category: weather.VFR, Crosswind: 12.75, MaxCrosswind: 17}), 50},
+		{"Weather Gusts", Weather(Conditions{Category: weather.VFR, GustSpread: 14}), 40},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if math.Abs(tc.factor.Score-tc.score) > 1e-9 {
+				t.Errorf("Score: got %.1f, expected %.1f (%s)", tc.factor.Score, tc.score, tc.factor.Detail)
+			}
+			if tc.factor.Detail == "" {
+				t.Errorf("Missing detail")
+			}
+		})
+	}
+
+	w := Weather(Conditions{Category: weather.MVFR, Crosswind: 15, MaxCrosswind: 17, GustSpread: 3})
+	if w.Detail != "15 kt crosswind of 17 kt demonstrated" {
+		t.Errorf("Weather detail: got %q, expected the limiting crosswind", w.Detail)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	s := Combine(Runway(1650, 2200), Climb(500), Fuel(75, 30), Weather(Conditions{Category: weather.MVFR}))
+	// (50*30 + 100*20 + 100*25 + 50*25) / 100
+	if math.Abs(s.Total-72.5) > 1e-9 || s.Rating() != "CAUTION" || len(s.Factors) != 4 {
+		t.Errorf("Got %.1f %s with %d factors, expected 72.5 CAUTION with 4", s.Total, s.Rating(), len(s.Factors))
+	}
+
+	// Factors left out do not count against the flight
+	s = Combine(Runway(1000, 2200), Climb(500))
+	if s.Total != 100 || s.Rating() != "GOOD" {
+		t.Errorf("Got %.1f %s, expected 100 GOOD", s.Total, s.Rating())
+	}
+
+	s = Combine(Runway(1000, 2200), Fuel(20, 30))
+	if s.Total != 0 || s.Rating() != "NOT FEASIBLE" {
+		t.Errorf("Got %.1f %s, expected 0 NOT FEASIBLE", s.Total, s.Rating())
+	}
+}
@@ -0,0 +1,164 @@
+package flightplan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// maxOptions is the number of cruise altitudes OptimumAltitude reports
+const maxOptions = 3
+
+// Objective selects what OptimumAltitude optimizes
+type Objective int
+
+const (
+	// MinimizeTime ranks altitudes by total trip time, the best groundspeed
+	// after paying for the climb
+	MinimizeTime Objective = iota
+	// MinimizeFuel ranks altitudes by total trip fuel
+	MinimizeFuel
+)
+
+// AltitudeQuery describes a route for the cruise altitude search
+type AltitudeQuery struct {
+	DepartureAltitude    float64    // Departure pressure altitude in feet
+	DepartureTemperature float64    // Departure temperature in °C
+	Distance             float64    // Route distance in nautical miles
+	Course               float64    // True course in degrees
+	Variation            float64    // Magnetic variation in degrees, east positive, for the hemispheric rule
+	Power                float64    // Cruise power in percent
+	Winds                WindsAloft // Forecast winds along the route
+	Objective            Objective
+
+	// Altitudes are the candidate cruise altitudes; if empty, the VFR
+	// cruising altitudes for the magnetic course are used
+	Altitudes []float64
+}
+
+// AltitudeOption is the trip at one candidate cruise altitude. Descent is
+// flown at cruise groundspeed and not counted separately.
+type AltitudeOption struct {
+	Altitude      float64 // Cruise pressure altitude in feet
+	TrueAirspeed  float64 // Cruise true airspeed in knots
+	Headwind      float64 // Cruise headwind component in knots, negative for tailwind
+	Groundspeed   float64 // Cruise groundspeed in knots
+	ClimbTime     float64 // Minutes to climb to altitude
+	ClimbFuel     float64 // US gallons to climb to altitude
+	ClimbDistance float64 // Nautical miles over the ground to top of climb
+	Time          float64 // Total minutes en route
+	Fuel          float64 // Total US gallons en route
+}
+
+// VFRAltitudes returns the VFR cruising altitudes (odd thousands plus 500 ft
+// for magnetic courses 0-179°, even thousands plus 500 ft for 180-359°)
+// between two altitudes
+func VFRAltitudes(magneticCourse, lowest, highest float64) []float64 {
+	start := 3500.0
+	if math.Mod(math.Mod(magneticCourse, 360)+360, 360) >= 180 {
+		start = 4500
+	}
+
+	var altitudes []float64
+	for alt := start; alt <= highest; alt += 2000 {
+		if alt >= lowest {
+			altitudes = append(altitudes, alt)
+		}
+	}
+	return altitudes
+}
+
+// OptimumAltitude evaluates each candidate cruise altitude for the route,
+// including the time and fuel to climb from departure, and returns the best
+// three for the objective, best first
+func OptimumAltitude(climb *performance.ClimbCalculator, cruise *performance.CruiseCalculator, q AltitudeQuery) ([]AltitudeOption, error) {
+	altitudes := q.Altitudes
+	if len(altitudes) == 0 {
+		magneticCourse := wind.TrueDirection(q.Course).ToMagnetic(q.Variation).Degrees
+		altitudes = VFRAltitudes(magneticCourse, q.DepartureAltitude+1000, 17500)
+	}
+
+	var options []AltitudeOption
+	for _, altitude := range altitudes {
+		option, err := evaluateAltitude(climb, cruise, q, altitude)
+		if err != nil {
+			continue
+		}
+		options = append(options, *option)
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no candidate altitude is reachable for a %.0f nm route at %.0f%% power", q.Distance, q.Power)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		if q.Objective == MinimizeFuel && a.Fuel != b.Fuel {
+			return a.Fuel < b.Fuel
+		}
+		if a.Time != b.Time {
+			return a.Time < b.Time
+		}
+		return a.Fuel < b.Fuel
+	})
+
+	if len(options) > maxOptions {
+		options = options[:maxOptions]
+	}
+	return options, nil
+}
+
+// evaluateAltitude computes the trip at one cruise altitude
+func evaluateAltitude(climb *performance.ClimbCalculator, cruise *performance.CruiseCalculator, q AltitudeQuery, altitude float64) (*AltitudeOption, error) {
+	climbResult, err := climb.CalculateClimb(performance.ClimbParams{
+		PressureAltitude: q.DepartureAltitude,
+		Temperature:      q.DepartureTemperature,
+		CruiseAltitude:   altitude,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	temperature, ok := q.Winds.TemperatureAt(altitude)
+	if !ok {
+		temperature = q.DepartureTemperature - atmosphere.LapseRate*(altitude-q.DepartureAltitude)/1000
+	}
+	cruiseResult, err := cruise.CalculateCruise(performance.CruiseParams{
+		PressureAltitude: altitude,
+		Temperature:      temperature,
+		Power:            q.Power,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	course := wind.TrueDirection(q.Course)
+
+	// The climb sees roughly the wind halfway up
+	climbWind := wind.Decompose(q.Winds.At((q.DepartureAltitude+altitude)/2), course, 0)
+	climbDistance := climbResult.Distance - climbWind.Headwind*climbResult.Time/60
+	if climbDistance >= q.Distance {
+		return nil, fmt.Errorf("top of climb to %.0f ft is beyond the destination", altitude)
+	}
+
+	cruiseWind, err := wind.Correct(q.Winds.At(altitude), course, cruiseResult.TrueAirspeed, 0)
+	if err != nil {
+		return nil, fmt.Errorf("at %.0f ft: %v", altitude, err)
+	}
+	cruiseTime := (q.Distance - climbDistance) / cruiseWind.Groundspeed * 60
+
+	return &AltitudeOption{
+		Altitude:      altitude,
+		TrueAirspeed:  cruiseResult.TrueAirspeed,
+		Headwind:      cruiseWind.Components.Headwind,
+		Groundspeed:   cruiseWind.Groundspeed,
+		ClimbTime:     climbResult.Time,
+		ClimbFuel:     climbResult.Fuel,
+		ClimbDistance: climbDistance,
+		Time:          climbResult.Time + cruiseTime,
+		Fuel:          climbResult.Fuel + cruiseResult.FuelFlow*cruiseTime/60,
+	}, nil
+}
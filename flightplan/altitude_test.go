@@ -0,0 +1,89 @@
+package flightplan
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func TestVFRAltitudes(t *testing.T) {
+	if got := VFRAltitudes(90, 1500, 12000); !reflect.DeepEqual(got, []float64{3500, 5500, 7500, 9500, 11500}) {
+		t.Errorf("Eastbound: got %v", got)
+	}
+	if got := VFRAltitudes(270, 5000, 12000); !reflect.DeepEqual(got, []float64{6500, 8500, 10500}) {
+		t.Errorf("Westbound: got %v", got)
+	}
+}
+
+func TestOptimumAltitude(t *testing.T) {
+	climb, cruise := performance.NewClimbCalculator(), performance.NewCruiseCalculator()
+	query := AltitudeQuery{
+		DepartureAltitude:    500,
+		DepartureTemperature: 15,
+		Distance:             150,
+		Course:               90,
+		Power:                65,
+	}
+
+	// A strong westerly aloft rewards climbing high when eastbound
+	query.Winds, _ = ParseWindsAloft("3000:0905,6000:2720+05,9000:2740-01")
+	options, err := OptimumAltitude(climb, cruise, query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(options) != 3 {
+		t.Fatalf("Expected 3 options, got %d", len(options))
+	}
+	if options[0].Altitude != 9500 {
+		t.Errorf("Tailwind aloft: expected 9500 ft first, got %+v", options)
+	}
+	for i := 1; i < len(options); i++ {
+		if options[i].Time < options[i-1].Time {
+			t.Errorf("Options not sorted by time: %+v", options)
+		}
+	}
+
+	// A strong easterly aloft keeps the flight low
+	query.Winds, _ = ParseWindsAloft("3000:2705,6000:0930+05,9000:0950-01")
+	options, err = OptimumAltitude(climb, cruise, query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if options[0].Altitude != 3500 {
+		t.Errorf("Headwind aloft: expected 3500 ft first, got %+v", options)
+	}
+}
+
+func TestOptimumAltitudeFuel(t *testing.T) {
+	climb, cruise := performance.NewClimbCalculator(), performance.NewCruiseCalculator()
+	winds, err := ParseWindsAloft("3000:9900,6000:9900+05,9000:9900-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := AltitudeQuery{
+		DepartureAltitude:    500,
+		DepartureTemperature: 15,
+		Distance:             60,
+		Course:               90,
+		Power:                65,
+		Winds:                winds,
+		Objective:            MinimizeFuel,
+	}
+	options, err := OptimumAltitude(climb, cruise, query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 1; i < len(options); i++ {
+		if options[i].Fuel < options[i-1].Fuel {
+			t.Errorf("Options not sorted by fuel: %+v", options)
+		}
+	}
+
+	// The route is too short to reach any altitude
+	query.Distance = 5
+	if _, err := OptimumAltitude(climb, cruise, query); err == nil {
+		t.Error("Expected error for a route shorter than any climb, but got none")
+	}
+}
@@ -0,0 +1,125 @@
+package flightplan
+
+import (
+	"fmt"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// CeilingMargin is how far below the service ceiling a leg's required
+// altitude must be to pass without being flagged, in feet
+const CeilingMargin = 1000
+
+// CeilingStatus is the result of the terrain check for one leg
+type CeilingStatus int
+
+const (
+	// CeilingClear legs need an altitude well within the aircraft's capability
+	CeilingClear CeilingStatus = iota
+	// CeilingMarginal legs need an altitude within CeilingMargin of the service ceiling
+	CeilingMarginal
+	// CeilingExceeded legs need an altitude above the service ceiling
+	CeilingExceeded
+)
+
+// String returns "OK", "MARGINAL" or "ABOVE CEILING"
+func (s CeilingStatus) String() string {
+	switch s {
+	case CeilingMarginal:
+		return "MARGINAL"
+	case CeilingExceeded:
+		return "ABOVE CEILING"
+	}
+	return "OK"
+}
+
+// LegCeiling is the terrain check of one leg
+type LegCeiling struct {
+	From, To    string
+	Terrain     float64 // Highest terrain or obstacle along the leg in ft MSL
+	Required    float64 // Altitude the leg needs in ft MSL: terrain plus clearance, or the planned altitude if higher
+	Ceiling     float64 // Service ceiling in ft MSL at the forecast temperature
+	RateOfClimb float64 // Charted rate of climb at the required altitude in fpm, 0 above the ceiling
+	Status      CeilingStatus
+}
+
+// CeilingQuery describes the route, terrain and forecast for a terrain check
+type CeilingQuery struct {
+	Waypoints []Waypoint
+	Terrain   []float64  // Highest terrain per leg in ft MSL, e.g. from the sectional maximum elevation figures
+	Clearance float64    // Required clearance above terrain in feet
+	Altitude  float64    // Planned cruise altitude in ft MSL, 0 to use terrain plus clearance only
+	Altimeter float64    // Altimeter setting in inHg for converting to pressure altitude, 0 for standard
+	Winds     WindsAloft // Forecast temperatures aloft; standard temperatures are used without them
+}
+
+// CheckCeiling compares the altitude each leg needs over its terrain with
+// the service ceiling at the forecast temperature. The climb chart is for
+// maximum weight, so at lighter weights the check is conservative.
+func CheckCeiling(climb *performance.ClimbCalculator, q CeilingQuery) ([]LegCeiling, error) {
+	if len(q.Waypoints) < 2 {
+		return nil, fmt.Errorf("a route needs at least two waypoints")
+	}
+	if len(q.Terrain) != len(q.Waypoints)-1 {
+		return nil, fmt.Errorf("%d terrain elevations given for %d legs", len(q.Terrain), len(q.Waypoints)-1)
+	}
+
+	altimeter := q.Altimeter
+	if altimeter == 0 {
+		altimeter = atmosphere.StandardAltimeter
+	}
+	// Pressure altitude is MSL altitude plus this offset
+	offset := atmosphere.PressureAltitude(0, altimeter)
+	temperatureAt := func(pressureAltitude float64) float64 {
+		if t, ok := q.Winds.TemperatureAt(pressureAltitude); ok {
+			return t
+		}
+		return atmosphere.ISATemperature(pressureAltitude)
+	}
+
+	ceiling := ceilingPressureAltitude(climb.ServiceCeiling(), temperatureAt) - offset
+
+	legs := make([]LegCeiling, len(q.Terrain))
+	for i, terrain := range q.Terrain {
+		leg := LegCeiling{
+			From:     q.Waypoints[i].Name,
+			To:       q.Waypoints[i+1].Name,
+			Terrain:  terrain,
+			Required: terrain + q.Clearance,
+			Ceiling:  ceiling,
+		}
+		if q.Altitude > leg.Required {
+			leg.Required = q.Altitude
+		}
+
+		switch {
+		case leg.Required > ceiling:
+			leg.Status = CeilingExceeded
+		case leg.Required > ceiling-CeilingMargin:
+			leg.Status = CeilingMarginal
+		}
+		if leg.Status != CeilingExceeded {
+			pressureAltitude := leg.Required + offset
+			leg.RateOfClimb = climb.RateOfClimb(pressureAltitude, temperatureAt(pressureAltitude))
+		}
+		legs[i] = leg
+	}
+	return legs, nil
+}
+
+// ceilingPressureAltitude finds the pressure altitude at which the density
+// altitude reaches a ceiling, for a temperature profile that makes density
+// altitude increase with height
+func ceilingPressureAltitude(densityAltitude float64, temperatureAt func(float64) float64) float64 {
+	lo, hi := -5000.0, 40000.0
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if atmosphere.DensityAltitude(mid, temperatureAt(mid)) < densityAltitude {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
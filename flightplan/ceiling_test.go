@@ -0,0 +1,61 @@
+package flightplan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func TestCheckCeiling(t *testing.T) {
+	q := CeilingQuery{
+		Waypoints: []Waypoint{{Name: "A"}, {Name: "B"}, {Name: "C"}, {Name: "D"}},
+		Terrain:   []float64{2000, 9500, 10500},
+		Clearance: 1000,
+	}
+	legs, err := CheckCeiling(performance.NewClimbCalculator(), q)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// On a standard day the service ceiling is the charted 11,091 ft density altitude
+	expected := []CeilingStatus{CeilingClear, CeilingMarginal, CeilingExceeded}
+	for i, leg := range legs {
+		if leg.Status != expected[i] {
+			t.Errorf("Leg %s-%s: expected %s, got %s (required %.0f ft, ceiling %.0f ft)",
+				leg.From, leg.To, expected[i], leg.Status, leg.Required, leg.Ceiling)
+		}
+		if math.Abs(leg.Ceiling-11091) > 5 {
+			t.Errorf("Expected a standard day ceiling of 11091 ft, got %.0f ft", leg.Ceiling)
+		}
+	}
+	if legs[0].RateOfClimb < 500 || legs[2].RateOfClimb != 0 {
+		t.Errorf("Unexpected rates of climb %.0f and %.0f fpm", legs[0].RateOfClimb, legs[2].RateOfClimb)
+	}
+
+	// A warm forecast and a low altimeter setting both lower the ceiling
+	q.Winds, _ = ParseWindsAloft("6000:2710+18,9000:2710+12,12000:2710+06")
+	q.Altimeter = 29.62
+	warm, err := CheckCeiling(performance.NewClimbCalculator(), q)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if warm[0].Ceiling > legs[0].Ceiling-1500 {
+		t.Errorf("Expected a much lower ceiling when warm, got %.0f ft", warm[0].Ceiling)
+	}
+	if warm[1].Status != CeilingExceeded {
+		t.Errorf("Expected leg B-C to exceed the warm day ceiling, got %s", warm[1].Status)
+	}
+
+	// The planned altitude applies where it is above the terrain
+	q.Altitude = 8500
+	planned, _ := CheckCeiling(performance.NewClimbCalculator(), q)
+	if planned[0].Required != 8500 || planned[1].Required != 10500 {
+		t.Errorf("Expected required altitudes of 8500 and 10500 ft, got %.0f and %.0f", planned[0].Required, planned[1].Required)
+	}
+
+	q.Terrain = q.Terrain[:2]
+	if _, err := CheckCeiling(performance.NewClimbCalculator(), q); err == nil {
+		t.Error("Expected an error when the terrain does not match the legs")
+	}
+}
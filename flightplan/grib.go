@@ -0,0 +1,64 @@
+package flightplan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/grib"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// WindsAloftFromGRIB builds a winds aloft forecast at a point from the
+// isobaric wind and temperature fields of weather model output valid at a
+// time. Each level is placed at the pressure altitude of its isobaric
+// surface, the altitude the FB forecast levels approximate.
+func WindsAloftFromGRIB(messages []*grib.Message, lat, lon float64, valid time.Time) (WindsAloft, error) {
+	type components struct{ u, v, t *grib.Message }
+	levels := make(map[float64]*components)
+	for _, m := range messages {
+		if m.Level.Type != grib.SurfaceIsobaric || !m.Valid().Equal(valid) {
+			continue
+		}
+		c := levels[m.Level.Value]
+		if c == nil {
+			c = &components{}
+			levels[m.Level.Value] = c
+		}
+		switch m.Parameter {
+		case grib.UWind:
+			c.u = m
+		case grib.VWind:
+			c.v = m
+		case grib.Temperature:
+			c.t = m
+		}
+	}
+
+	var winds WindsAloft
+	for pressure, c := range levels {
+		if c.u == nil || c.v == nil {
+			continue
+		}
+		w, err := grib.WindAt(c.u, c.v, lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		level := WindLevel{Altitude: atmosphere.PressureLevelAltitude(pressure / 100), Wind: w}
+		if c.t != nil {
+			t, err := c.t.At(lat, lon)
+			if err != nil {
+				return nil, err
+			}
+			level.Temperature, level.HasTemperature = units.KelvinToCelsius(t), true
+		}
+		winds = append(winds, level)
+	}
+
+	if len(winds) == 0 {
+		return nil, fmt.Errorf("no isobaric UGRD and VGRD fields valid at %s", valid.UTC().Format("2006-01-02 1504Z"))
+	}
+	sort.Slice(winds, func(i, j int) bool { return winds[i].Altitude < winds[j].Altitude })
+	return winds, nil
+}
@@ -0,0 +1,118 @@
+package flightplan
+
+import (
+	"fmt"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// Waypoint is a point of a route
+type Waypoint struct {
+	Name      string
+	Latitude  float64 // in degrees, north positive
+	Longitude float64 // in degrees, east positive
+	Variation float64 // Magnetic variation in degrees, east positive
+}
+
+// Leg is one line of a navigation log
+type Leg struct {
+	From, To        string
+	TrueCourse      float64   // Initial great circle course in degrees
+	Distance        float64   // in nautical miles
+	Wind            wind.Wind // Forecast wind at cruise altitude, from true
+	WindCorrection  float64   // Wind correction angle in degrees, positive to the right
+	TrueHeading     float64   // in degrees
+	MagneticHeading float64   // in degrees, using the variation at the start of the leg
+	Groundspeed     float64   // in knots
+	Time            float64   // Leg time en route in minutes
+	Fuel            float64   // Leg fuel in US gallons
+	TotalTime       float64   // Cumulative minutes from the first waypoint
+	TotalFuel       float64   // Cumulative US gallons from the first waypoint
+}
+
+// Navlog is a navigation log for a route flown at one cruise altitude and power
+type Navlog struct {
+	Cruise *performance.CruiseResult // Cruise performance used for every leg
+	Legs   []Leg
+}
+
+// NavlogQuery describes the route and cruise condition for a navigation log
+type NavlogQuery struct {
+	Waypoints []Waypoint
+	Altitude  float64    // Cruise pressure altitude in feet
+	Power     float64    // Cruise power in percent
+	Winds     WindsAloft // Forecast winds along the route
+}
+
+// NewNavlog builds the per-leg navigation log for a route, flying every leg
+// at the cruise true airspeed and fuel flow. Climb and descent are not
+// modelled; the climb table covers them separately.
+func NewNavlog(cruise *performance.CruiseCalculator, q NavlogQuery) (*Navlog, error) {
+	if len(q.Waypoints) < 2 {
+		return nil, fmt.Errorf("a route needs at least two waypoints")
+	}
+
+	temperature, ok := q.Winds.TemperatureAt(q.Altitude)
+	if !ok {
+		temperature = atmosphere.ISATemperature(q.Altitude)
+	}
+	cruiseResult, err := cruise.CalculateCruise(performance.CruiseParams{
+		PressureAltitude: q.Altitude,
+		Temperature:      temperature,
+		Power:            q.Power,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	navlog := &Navlog{Cruise: cruiseResult}
+	w := q.Winds.At(q.Altitude)
+	var totalTime, totalFuel float64
+
+	for i := 1; i < len(q.Waypoints); i++ {
+		from, to := q.Waypoints[i-1], q.Waypoints[i]
+		course, distance := greatCircle(from, to)
+
+		correction, err := wind.Correct(w, wind.TrueDirection(course), cruiseResult.TrueAirspeed, from.Variation)
+		if err != nil {
+			return nil, fmt.Errorf("leg %s-%s: %v", from.Name, to.Name, err)
+		}
+
+		legTime := distance / correction.Groundspeed * 60
+		legFuel := cruiseResult.FuelFlow * legTime / 60
+		totalTime += legTime
+		totalFuel += legFuel
+
+		navlog.Legs = append(navlog.Legs, Leg{
+			From:            from.Name,
+			To:              to.Name,
+			TrueCourse:      wind.TrueDirection(course).Degrees,
+			Distance:        distance,
+			Wind:            w,
+			WindCorrection:  correction.WindCorrection,
+			TrueHeading:     correction.TrueHeading.Degrees,
+			MagneticHeading: correction.MagneticHeading.Degrees,
+			Groundspeed:     correction.Groundspeed,
+			Time:            legTime,
+			Fuel:            legFuel,
+			TotalTime:       totalTime,
+			TotalFuel:       totalFuel,
+		})
+	}
+	return navlog, nil
+}
+
+// greatCircle returns the initial true course in degrees and the distance in
+// nautical miles from one waypoint to another
+func greatCircle(from, to Waypoint) (float64, float64) {
+	a, b := from.Position(), to.Position()
+	return geo.InitialCourse(a, b), geo.Distance(a, b)
+}
+
+// Position returns the waypoint's position on the earth
+func (w Waypoint) Position() geo.Point {
+	return geo.Point{Latitude: w.Latitude, Longitude: w.Longitude}
+}
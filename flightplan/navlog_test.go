@@ -0,0 +1,93 @@
+package flightplan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func TestGreatCircle(t *testing.T) {
+	tests := []struct {
+		from, to         Waypoint
+		course, distance float64
+	}{
+		{Waypoint{Latitude: 0, Longitude: 0}, Waypoint{Latitude: 1, Longitude: 0}, 0, 60.04},
+		{Waypoint{Latitude: 0, Longitude: 0}, Waypoint{Latitude: 0, Longitude: 1}, 90, 60.04},
+		{Waypoint{Latitude: 0, Longitude: 1}, Waypoint{Latitude: 0, Longitude: 0}, 270, 60.04},
+		// KJYO to KFDK
+		{Waypoint{Latitude: 39.0780, Longitude: -77.5575}, Waypoint{Latitude: 39.4176, Longitude: -77.3743}, 23, 22.1},
+	}
+
+	for _, tc := range tests {
+		course, distance := greatCircle(tc.from, tc.to)
+		if math.Abs(course-tc.course) > 1 || math.Abs(distance-tc.distance) > 0.1 {
+			t.Errorf("%+v to %+v: expected %.0f° %.2f nm, got %.1f° %.2f nm", tc.from, tc.to, tc.course, tc.distance, course, distance)
+		}
+	}
+}
+
+func TestNavlog(t *testing.T) {
+	winds, err := ParseWindsAloft("3000:3620,6000:3620+05")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	navlog, err := NewNavlog(performance.NewCruiseCalculator(), NavlogQuery{
+		Waypoints: []Waypoint{
+			{Name: "A", Latitude: 0, Longitude: 0, Variation: -10},
+			{Name: "B", Latitude: 0, Longitude: 1, Variation: -10},
+			{Name: "C", Latitude: 1, Longitude: 1, Variation: -10},
+		},
+		Altitude: 4500,
+		Power:    65,
+		Winds:    winds,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(navlog.Legs) != 2 {
+		t.Fatalf("Expected 2 legs, got %d", len(navlog.Legs))
+	}
+	tas := navlog.Cruise.TrueAirspeed
+
+	// Eastbound with a north wind: correct left, groundspeed reduced by the crab
+	east := navlog.Legs[0]
+	wantWCA := -math.Asin(20/tas) * 180 / math.Pi
+	if math.Abs(east.WindCorrection-wantWCA) > 0.01 || math.Abs(east.TrueHeading-(90+wantWCA)) > 0.01 {
+		t.Errorf("East leg: expected WCA %.1f°, got %.1f° heading %.1f°", wantWCA, east.WindCorrection, east.TrueHeading)
+	}
+	if math.Abs(east.MagneticHeading-(east.TrueHeading+10)) > 0.01 {
+		t.Errorf("East leg: expected magnetic heading %.1f°, got %.1f°", east.TrueHeading+10, east.MagneticHeading)
+	}
+	if math.Abs(east.Groundspeed-math.Sqrt(tas*tas-400)) > 0.01 {
+		t.Errorf("East leg: expected groundspeed %.1f kt, got %.1f kt", math.Sqrt(tas*tas-400), east.Groundspeed)
+	}
+
+	// Northbound straight into the wind
+	north := navlog.Legs[1]
+	if math.Abs(north.WindCorrection) > 0.01 || math.Abs(north.Groundspeed-(tas-20)) > 0.01 {
+		t.Errorf("North leg: expected no correction and %.1f kt, got %.1f° and %.1f kt", tas-20, north.WindCorrection, north.Groundspeed)
+	}
+
+	if math.Abs(north.TotalTime-(east.Time+north.Time)) > 1e-9 || math.Abs(north.TotalFuel-(east.Fuel+north.Fuel)) > 1e-9 {
+		t.Errorf("Totals do not add up: %+v", navlog.Legs)
+	}
+	if math.Abs(east.Fuel-navlog.Cruise.FuelFlow*east.Time/60) > 1e-9 {
+		t.Errorf("East leg fuel %.2f gal does not match %.1f gph for %.1f min", east.Fuel, navlog.Cruise.FuelFlow, east.Time)
+	}
+}
+
+func TestNavlogErrors(t *testing.T) {
+	cruise := performance.NewCruiseCalculator()
+	winds, _ := ParseWindsAloft("3000:2710")
+
+	if _, err := NewNavlog(cruise, NavlogQuery{Waypoints: []Waypoint{{Name: "A"}}, Altitude: 4500, Power: 65, Winds: winds}); err == nil {
+		t.Error("Expected error for a single waypoint, but got none")
+	}
+
+	waypoints := []Waypoint{{Name: "A"}, {Name: "B", Latitude: 1}}
+	if _, err := NewNavlog(cruise, NavlogQuery{Waypoints: waypoints, Altitude: 4500, Power: 95, Winds: winds}); err == nil {
+		t.Error("Expected error for power outside the chart, but got none")
+	}
+}
@@ -0,0 +1,201 @@
+// Package flightplan plans cruise altitudes and legs from the performance
+// charts and the winds aloft forecast
+package flightplan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// WindLevel is the forecast wind and temperature at one altitude
+type WindLevel struct {
+	Altitude       float64   // in feet
+	Wind           wind.Wind // Direction is true, as in the winds aloft forecast
+	Temperature    float64   // in °C, only meaningful if HasTemperature
+	HasTemperature bool      // False for levels forecast without a temperature (e.g. 3000 ft)
+}
+
+// WindsAloft is a winds aloft forecast for one station, sorted by altitude
+type WindsAloft []WindLevel
+
+// ParseWindsAloft parses levels written as ALTITUDE:DDSS[±TT], separated by
+// commas, using the wind code of the FB winds and temperatures aloft
+// forecast: "3000:2710,6000:2815+05,9000:9900-02"
+func ParseWindsAloft(s string) (WindsAloft, error) {
+	var winds WindsAloft
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		altitude, code, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid winds aloft level %q: expected ALTITUDE:DDSS[±TT]", part)
+		}
+		alt, err := strconv.ParseFloat(altitude, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid winds aloft altitude %q", altitude)
+		}
+
+		level, err := parseWindCode(code)
+		if err != nil {
+			return nil, err
+		}
+		level.Altitude = alt
+		winds = append(winds, level)
+	}
+
+	if len(winds) == 0 {
+		return nil, fmt.Errorf("no winds aloft levels given")
+	}
+	sort.Slice(winds, func(i, j int) bool { return winds[i].Altitude < winds[j].Altitude })
+	return winds, nil
+}
+
+// String formats the forecast in the form ParseWindsAloft reads, rounding
+// directions to tens of degrees and speeds to knots as the FB forecast does
+func (w WindsAloft) String() string {
+	levels := make([]string, len(w))
+	for i, level := range w {
+		levels[i] = fmt.Sprintf("%.0f:%s", level.Altitude, windCode(level))
+	}
+	return strings.Join(levels, ",")
+}
+
+// windCode encodes a level as a DDSS[±TT] winds aloft group
+func windCode(level WindLevel) string {
+	dd := int(math.Round(level.Wind.From.Degrees/10)) % 36
+	if dd == 0 {
+		dd = 36
+	}
+	ss := int(math.Min(math.Round(level.Wind.Speed), 199))
+
+	var code string
+	switch {
+	case ss < 5:
+		code = "9900"
+	case ss >= 100:
+		code = fmt.Sprintf("%02d%02d", dd+50, ss-100)
+	default:
+		code = fmt.Sprintf("%02d%02d", dd, ss)
+	}
+	if level.HasTemperature {
+		code += fmt.Sprintf("%+03.0f", level.Temperature)
+	}
+	return code
+}
+
+// parseWindCode decodes a DDSS[±TT] winds aloft group
+func parseWindCode(code string) (WindLevel, error) {
+	var level WindLevel
+	if len(code) != 4 && len(code) != 7 {
+		return level, fmt.Errorf("invalid winds aloft group %q: expected DDSS or DDSS±TT", code)
+	}
+
+	dd, err1 := strconv.Atoi(code[:2])
+	ss, err2 := strconv.Atoi(code[2:4])
+	if err1 != nil || err2 != nil {
+		return level, fmt.Errorf("invalid winds aloft group %q", code)
+	}
+
+	switch {
+	case dd == 99 && ss == 0:
+		// Light and variable
+	case dd >= 51 && dd <= 86:
+		// Speeds of 100 knots or more add 50 to the direction
+		level.Wind = wind.Wind{From: wind.TrueDirection(float64(dd-50) * 10), Speed: float64(ss + 100)}
+	case dd >= 1 && dd <= 36:
+		level.Wind = wind.Wind{From: wind.TrueDirection(float64(dd) * 10), Speed: float64(ss)}
+	default:
+		return level, fmt.Errorf("invalid wind direction in winds aloft group %q", code)
+	}
+
+	if len(code) == 7 {
+		temperature, err := strconv.Atoi(code[4:])
+		if err != nil || (code[4] != '+' && code[4] != '-') {
+			return level, fmt.Errorf("invalid temperature in winds aloft group %q", code)
+		}
+		level.Temperature = float64(temperature)
+		level.HasTemperature = true
+	}
+	return level, nil
+}
+
+// At interpolates the forecast wind at an altitude, between the levels
+// either side of it. Winds are interpolated as vectors so that direction
+// changes across north are handled. Outside the forecast levels the
+// nearest level is used.
+func (w WindsAloft) At(altitude float64) wind.Wind {
+	if len(w) == 0 {
+		return wind.Wind{}
+	}
+
+	lo, hi, frac := w.bracket(altitude, func(WindLevel) bool { return true })
+	u1, v1 := vector(w[lo].Wind)
+	u2, v2 := vector(w[hi].Wind)
+	u, v := u1+(u2-u1)*frac, v1+(v2-v1)*frac
+
+	speed := math.Hypot(u, v)
+	if speed < 1e-9 {
+		return wind.Wind{}
+	}
+	from := math.Atan2(u, v) * 180 / math.Pi
+	return wind.Wind{From: wind.TrueDirection(math.Mod(from+360, 360)), Speed: speed}
+}
+
+// TemperatureAt interpolates the forecast temperature at an altitude. It
+// reports false when no level has a temperature. Outside the levels with
+// temperatures, the standard lapse rate is applied from the nearest one.
+func (w WindsAloft) TemperatureAt(altitude float64) (float64, bool) {
+	hasTemperature := func(l WindLevel) bool { return l.HasTemperature }
+
+	lo, hi, frac := w.bracket(altitude, hasTemperature)
+	if lo < 0 {
+		return 0, false
+	}
+	if lo == hi {
+		return w[lo].Temperature - atmosphere.LapseRate*(altitude-w[lo].Altitude)/1000, true
+	}
+	return w[lo].Temperature + (w[hi].Temperature-w[lo].Temperature)*frac, true
+}
+
+// bracket finds the levels matching keep either side of an altitude and
+// the interpolation fraction between them. Outside the matching levels
+// lo == hi is the nearest one; lo is -1 if no level matches.
+func (w WindsAloft) bracket(altitude float64, keep func(WindLevel) bool) (int, int, float64) {
+	lo, hi := -1, -1
+	for i, level := range w {
+		if !keep(level) {
+			continue
+		}
+		if level.Altitude <= altitude {
+			lo = i
+		}
+		if level.Altitude >= altitude && hi < 0 {
+			hi = i
+		}
+	}
+
+	switch {
+	case lo < 0 && hi < 0:
+		return -1, -1, 0
+	case lo < 0:
+		return hi, hi, 0
+	case hi < 0 || lo == hi:
+		return lo, lo, 0
+	}
+	return lo, hi, (altitude - w[lo].Altitude) / (w[hi].Altitude - w[lo].Altitude)
+}
+
+// vector returns the east and north components of the direction a wind blows from
+func vector(w wind.Wind) (float64, float64) {
+	angle := w.From.Degrees * math.Pi / 180
+	return w.Speed * math.Sin(angle), w.Speed * math.Cos(angle)
+}
@@ -0,0 +1,96 @@
+package flightplan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+func TestParseWindsAloft(t *testing.T) {
+	winds, err := ParseWindsAloft("6000:2815+05, 3000:2710,9000:9900-02,12000:7312-08")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(winds) != 4 {
+		t.Fatalf("Expected 4 levels, got %d", len(winds))
+	}
+
+	expected := []struct {
+		altitude, direction, speed, temperature float64
+		hasTemperature                          bool
+	}{
+		{3000, 270, 10, 0, false},
+		{6000, 280, 15, 5, true},
+		{9000, 0, 0, -2, true},
+		{12000, 230, 112, -8, true},
+	}
+	for i, e := range expected {
+		l := winds[i]
+		if l.Altitude != e.altitude || l.Wind.From.Degrees != e.direction || l.Wind.Speed != e.speed ||
+			l.Temperature != e.temperature || l.HasTemperature != e.hasTemperature {
+			t.Errorf("Level %d: expected %+v, got %+v", i, e, l)
+		}
+	}
+
+	for _, bad := range []string{"", "3000", "3000:27", "x:2710", "3000:4010", "3000:2710x05"} {
+		if _, err := ParseWindsAloft(bad); err == nil {
+			t.Errorf("%q: expected error, but got none", bad)
+		}
+	}
+}
+
+func TestWindsAloftString(t *testing.T) {
+	const forecast = "3000:2710,6000:2815+05,9000:9900-02,12000:7312-08"
+	winds, err := ParseWindsAloft(forecast)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := winds.String(); got != forecast {
+		t.Errorf("Expected %q, got %q", forecast, got)
+	}
+
+	// Model winds are rounded to the FB resolution
+	winds = WindsAloft{
+		{Altitude: 4781, Wind: wind.Wind{From: wind.TrueDirection(3), Speed: 12.6}, Temperature: 8.4, HasTemperature: true},
+		{Altitude: 9882, Wind: wind.Wind{From: wind.TrueDirection(254), Speed: 4.4}},
+	}
+	if got, want := winds.String(), "4781:3613+08,9882:9900"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWindsAloftAt(t *testing.T) {
+	winds, err := ParseWindsAloft("3000:3520,6000:0120+05,9000:0120-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Halfway between 350° and 010° is north, not 180°
+	w := winds.At(4500)
+	if math.Abs(w.From.Degrees-360) > 0.5 && w.From.Degrees > 0.5 {
+		t.Errorf("Expected wind from north, got %s", w.From)
+	}
+	if math.Abs(w.Speed-19.7) > 0.1 {
+		t.Errorf("Expected 19.7 kt, got %.1f kt", w.Speed)
+	}
+
+	// Outside the forecast levels the nearest level is used
+	if w := winds.At(1000); math.Abs(w.From.Degrees-350) > 1e-9 || math.Abs(w.Speed-20) > 1e-9 {
+		t.Errorf("Below the lowest level: got %s at %.0f kt", w.From, w.Speed)
+	}
+
+	// No temperature at 3000 ft, so the standard lapse applies below 6000 ft
+	temperature, ok := winds.TemperatureAt(3000)
+	if !ok || math.Abs(temperature-10.94) > 0.01 {
+		t.Errorf("Temperature at 3000 ft: expected 10.94°C, got %.2f (%v)", temperature, ok)
+	}
+	if temperature, _ := winds.TemperatureAt(7500); temperature != 2 {
+		t.Errorf("Temperature at 7500 ft: expected 2°C, got %.2f", temperature)
+	}
+
+	calm, _ := ParseWindsAloft("3000:2710")
+	if _, ok := calm.TemperatureAt(3000); ok {
+		t.Error("Expected no temperature when none is forecast")
+	}
+}
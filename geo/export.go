@@ -0,0 +1,173 @@
+package geo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// Feature is a line or an area to draw on a map layer
+type Feature struct {
+	Name       string
+	Properties map[string]interface{} // GeoJSON properties and KML extended data
+
+	Line      []Point   // A line through the points, or
+	Area      []Point   // the outline of an area, closed when written
+	Altitudes []float64 // Feet MSL of each Line point, or nil for a line on the ground
+}
+
+// WriteGeoJSON writes features as a GeoJSON FeatureCollection (RFC 7946),
+// with altitudes in meters as the third coordinate
+func WriteGeoJSON(w io.Writer, features []Feature) error {
+	type geometry struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string                 `json:"type"`
+		Geometry   geometry               `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+	collection := struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{Type: "FeatureCollection", Features: []feature{}}
+
+	for _, f := range features {
+		props := map[string]interface{}{"name": f.Name}
+		for k, v := range f.Properties {
+			props[k] = v
+		}
+		g := geometry{Type: "LineString"}
+		if f.Area != nil {
+			// Exterior rings run counterclockwise in RFC 7946
+			ring := closeRing(f.Area)
+			if signedArea(ring) < 0 {
+				reversed := make([]Point, len(ring))
+				for i, p := range ring {
+					reversed[len(ring)-1-i] = p
+				}
+				ring = reversed
+			}
+			g = geometry{Type: "Polygon", Coordinates: [][][]float64{positions(ring, nil)}}
+		} else {
+			g.Coordinates = positions(f.Line, f.Altitudes)
+		}
+		collection.Features = append(collection.Features, feature{Type: "Feature", Geometry: g, Properties: props})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(collection)
+}
+
+// WriteKML writes features as a KML document for Google Earth. Lines with
+// altitudes are drawn at those altitudes and extruded to the ground.
+func WriteKML(w io.Writer, name string, features []Feature) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<kml xmlns="http://www.opengis.net/kml/2.2">` + "\n<Document>\n")
+	b.WriteString("<name>" + escape(name) + "</name>\n")
+	b.WriteString(`<Style id="line"><LineStyle><color>ff0000ff</color><width>3</width></LineStyle>` +
+		`<PolyStyle><color>400000ff</color></PolyStyle></Style>` + "\n")
+	b.WriteString(`<Style id="area"><LineStyle><color>ff00ffff</color><width>2</width></LineStyle>` +
+		`<PolyStyle><color>4000ffff</color></PolyStyle></Style>` + "\n")
+
+	for _, f := range features {
+		b.WriteString("<Placemark>\n<name>" + escape(f.Name) + "</name>\n")
+		if len(f.Properties) > 0 {
+			keys := make([]string, 0, len(f.Properties))
+			for k := range f.Properties {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			b.WriteString("<ExtendedData>\n")
+			for _, k := range keys {
+				fmt.Fprintf(&b, `<Data name="%s"><value>%s</value></Data>`+"\n", escape(k), escape(fmt.Sprint(f.Properties[k])))
+			}
+			b.WriteString("</ExtendedData>\n")
+		}
+		if f.Area != nil {
+			b.WriteString("<styleUrl>#area</styleUrl>\n<Polygon><tessellate>1</tessellate><outerBoundaryIs><LinearRing><coordinates>\n")
+			b.WriteString(coordinates(closeRing(f.Area), nil))
+			b.WriteString("</coordinates></LinearRing></outerBoundaryIs></Polygon>\n")
+		} else {
+			b.WriteString("<styleUrl>#line</styleUrl>\n<LineString>")
+			if f.Altitudes != nil {
+				b.WriteString("<extrude>1</extrude><altitudeMode>absolute</altitudeMode>")
+			} else {
+				b.WriteString("<tessellate>1</tessellate>")
+			}
+			b.WriteString("<coordinates>\n" + coordinates(f.Line, f.Altitudes) + "</coordinates></LineString>\n")
+		}
+		b.WriteString("</Placemark>\n")
+	}
+	b.WriteString("</Document>\n</kml>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// positions returns GeoJSON positions, longitude first
+func positions(points []Point, altitudes []float64) [][]float64 {
+	list := make([][]float64, len(points))
+	for i, p := range points {
+		list[i] = []float64{round(p.Longitude, 6), round(p.Latitude, 6)}
+		if altitudes != nil {
+			list[i] = append(list[i], round(units.FeetToMeters(altitudes[i]), 1))
+		}
+	}
+	return list
+}
+
+// coordinates returns the KML coordinates of the points, one tuple a line
+func coordinates(points []Point, altitudes []float64) string {
+	var b strings.Builder
+	for i, p := range points {
+		b.WriteString(strconv.FormatFloat(round(p.Longitude, 6), 'f', -1, 64) + "," +
+			strconv.FormatFloat(round(p.Latitude, 6), 'f', -1, 64))
+		if altitudes != nil {
+			b.WriteString("," + strconv.FormatFloat(round(units.FeetToMeters(altitudes[i]), 1), 'f', -1, 64))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// closeRing returns the outline with its first point repeated at the end
+func closeRing(points []Point) []Point {
+	if len(points) == 0 || points[0] == points[len(points)-1] {
+		return points
+	}
+	return append(append([]Point(nil), points...), points[0])
+}
+
+// signedArea is positive for a counterclockwise ring in longitude and
+// latitude, which is close enough for orientation over small areas
+func signedArea(ring []Point) float64 {
+	area := 0.0
+	for i := 1; i < len(ring); i++ {
+		area += ring[i-1].Longitude*ring[i].Latitude - ring[i].Longitude*ring[i-1].Latitude
+	}
+	return area / 2
+}
+
+// escape returns text with the XML special characters escaped
+func escape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// round rounds v to a number of decimals
+func round(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
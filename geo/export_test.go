@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+var exportFeatures = []Feature{
+	{Name: "Climb <1>", Line: []Point{jyo, fdk}, Altitudes: []float64{389, 1389}, Properties: map[string]interface{}{"gradient": 500}},
+	// Clockwise, to be reversed for GeoJSON
+	{Name: "Area", Area: []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+}
+
+func TestWriteGeoJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteGeoJSON(&b, exportFeatures); err != nil {
+		t.Fatal(err)
+	}
+
+	var collection struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates json.RawMessage
+			}
+			Properties map[string]interface{}
+		}
+	}
+	if err := json.Unmarshal(b.Bytes(), &collection); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Fatalf("Expected a FeatureCollection of 2, got %s of %d", collection.Type, len(collection.Features))
+	}
+
+	line := collection.Features[0]
+	var coords [][]float64
+	json.Unmarshal(line.Geometry.Coordinates, &coords)
+	if line.Geometry.Type != "LineString" || len(coords) != 2 || coords[0][0] != jyo.Longitude || coords[0][1] != jyo.Latitude {
+		t.Errorf("Expected a line from %+v, longitude first, got %s %v", jyo, line.Geometry.Type, coords)
+	}
+	if len(coords[1]) != 3 || coords[1][2] != 423.4 {
+		t.Errorf("Expected 423.4 m altitude, got %v", coords[1])
+	}
+	if line.Properties["name"] != "Climb <1>" || line.Properties["gradient"] != 500.0 {
+		t.Errorf("Unexpected properties %v", line.Properties)
+	}
+
+	area := collection.Features[1]
+	var rings [][][]float64
+	json.Unmarshal(area.Geometry.Coordinates, &rings)
+	if area.Geometry.Type != "Polygon" || len(rings) != 1 || len(rings[0]) != 5 {
+		t.Fatalf("Expected a closed ring of 5 positions, got %s %v", area.Geometry.Type, rings)
+	}
+	// Counterclockwise: (0,0) then (lon 1, lat 0) is east first
+	if ring := rings[0]; ring[1][0] != 1 || ring[1][1] != 0 || ring[4][0] != 0 || ring[4][1] != 0 {
+		t.Errorf("Expected a counterclockwise ring, got %v", ring)
+	}
+}
+
+func TestWriteKML(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteKML(&b, "Test & departure", exportFeatures); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Document struct {
+			Name       string `xml:"name"`
+			Placemarks []struct {
+				Name        string `xml:"name"`
+				Coordinates string `xml:"LineString>coordinates"`
+				Ring        string `xml:"Polygon>outerBoundaryIs>LinearRing>coordinates"`
+			} `xml:"Placemark"`
+		}
+	}
+	if err := xml.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid XML: %v", err)
+	}
+	if doc.Document.Name != "Test & departure" || len(doc.Document.Placemarks) != 2 {
+		t.Fatalf("Unexpected document %+v", doc.Document)
+	}
+	line := strings.Fields(doc.Document.Placemarks[0].Coordinates)
+	if len(line) != 2 || line[0] != "-77.5575,39.078,118.6" {
+		t.Errorf("Unexpected line coordinates %v", line)
+	}
+	if ring := strings.Fields(doc.Document.Placemarks[1].Ring); len(ring) != 5 || ring[0] != ring[4] {
+		t.Errorf("Expected a closed ring, got %v", ring)
+	}
+}
@@ -0,0 +1,93 @@
+// Package geo provides great-circle distance, course and position
+// functions on a spherical earth, shared by route planning, glide
+// footprints and nearest-airport searches
+package geo
+
+import (
+	"math"
+)
+
+// EarthRadius is the mean radius of the earth in nautical miles
+const EarthRadius = 3440.065
+
+// Point is a position on the earth
+type Point struct {
+	Latitude  float64 // in degrees, north positive
+	Longitude float64 // in degrees, east positive
+}
+
+// Distance returns the great-circle distance between two points in
+// nautical miles, by the haversine formula
+func Distance(from, to Point) float64 {
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLat, dLon := lat2-lat1, radians(to.Longitude-from.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// InitialCourse returns the true course in degrees [0, 360) at the start
+// of the great circle from one point to another
+func InitialCourse(from, to Point) float64 {
+	lat1, lat2 := radians(from.Latitude), radians(to.Latitude)
+	dLon := radians(to.Longitude - from.Longitude)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return normalize(degrees(math.Atan2(y, x)))
+}
+
+// Midpoint returns the point halfway along the great circle between two
+// points
+func Midpoint(from, to Point) Point {
+	lat1, lon1 := radians(from.Latitude), radians(from.Longitude)
+	lat2 := radians(to.Latitude)
+	dLon := radians(to.Longitude - from.Longitude)
+
+	bx := math.Cos(lat2) * math.Cos(dLon)
+	by := math.Cos(lat2) * math.Sin(dLon)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Sqrt((math.Cos(lat1)+bx)*(math.Cos(lat1)+bx)+by*by))
+	lon := lon1 + math.Atan2(by, math.Cos(lat1)+bx)
+	return Point{Latitude: degrees(lat), Longitude: longitude(degrees(lon))}
+}
+
+// Destination returns the point reached by following the great circle
+// from a point on an initial true course for a distance in nautical miles
+func Destination(from Point, course, distance float64) Point {
+	lat1, lon1 := radians(from.Latitude), radians(from.Longitude)
+	theta, delta := radians(course), distance/EarthRadius
+
+	lat := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat))
+	return Point{Latitude: degrees(lat), Longitude: longitude(degrees(lon))}
+}
+
+// radians converts degrees to radians
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// degrees converts radians to degrees
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// normalize brings a direction into [0, 360)
+func normalize(d float64) float64 {
+	return math.Mod(math.Mod(d, 360)+360, 360)
+}
+
+// longitude brings a longitude into [-180, 180)
+func longitude(lon float64) float64 {
+	return normalize(lon+180) - 180
+}
+
+// Circle returns n points around a circle of a radius in nautical miles,
+// clockwise from true north
+func Circle(center Point, radius float64, n int) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Destination(center, float64(i)*360/float64(n), radius)
+	}
+	return points
+}
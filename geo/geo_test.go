@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	jyo = Point{Latitude: 39.0780, Longitude: -77.5575}
+	fdk = Point{Latitude: 39.4176, Longitude: -77.3743}
+)
+
+func TestDistanceAndCourse(t *testing.T) {
+	tests := []struct {
+		from, to         Point
+		course, distance float64
+	}{
+		{Point{0, 0}, Point{1, 0}, 0, 60.04},
+		{Point{0, 0}, Point{0, 1}, 90, 60.04},
+		{Point{0, 1}, Point{0, 0}, 270, 60.04},
+		{Point{0, 179.5}, Point{0, -179.5}, 90, 60.04},
+		{jyo, fdk, 23, 22.1},
+	}
+	for _, tc := range tests {
+		course, distance := InitialCourse(tc.from, tc.to), Distance(tc.from, tc.to)
+		if math.Abs(course-tc.course) > 1 || math.Abs(distance-tc.distance) > 0.1 {
+			t.Errorf("%+v to %+v: expected %.0f° %.2f nm, got %.1f° %.2f nm", tc.from, tc.to, tc.course, tc.distance, course, distance)
+		}
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	mid := Midpoint(jyo, fdk)
+	if d1, d2 := Distance(jyo, mid), Distance(mid, fdk); math.Abs(d1-d2) > 0.01 || math.Abs(d1+d2-Distance(jyo, fdk)) > 0.01 {
+		t.Errorf("Midpoint %+v is %.2f and %.2f nm from the ends", mid, d1, d2)
+	}
+
+	// Across the antimeridian
+	if mid := Midpoint(Point{0, 179}, Point{0, -179}); math.Abs(math.Abs(mid.Longitude)-180) > 1e-9 {
+		t.Errorf("Expected the midpoint on the antimeridian, got %+v", mid)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	course, distance := InitialCourse(jyo, fdk), Distance(jyo, fdk)
+	got := Destination(jyo, course, distance)
+	if Distance(got, fdk) > 0.01 {
+		t.Errorf("Expected to reach %+v, got %+v", fdk, got)
+	}
+
+	// Due east along the equator
+	if got := Destination(Point{0, 0}, 90, 60.04); math.Abs(got.Latitude) > 1e-9 || math.Abs(got.Longitude-1) > 0.001 {
+		t.Errorf("Expected 0,1 got %+v", got)
+	}
+}
+
+func TestCircle(t *testing.T) {
+	points := Circle(jyo, 5, 36)
+	if len(points) != 36 {
+		t.Fatalf("Expected 36 points, got %d", len(points))
+	}
+	for i, p := range points {
+		if d := Distance(jyo, p); math.Abs(d-5) > 0.001 {
+			t.Errorf("Point %d is %.3f nm from the center", i, d)
+		}
+		if c := InitialCourse(jyo, p); math.Abs(math.Mod(c-float64(i)*10+540, 360)-180) > 0.01 {
+			t.Errorf("Point %d is on course %.2f°", i, c)
+		}
+	}
+}
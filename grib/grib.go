@@ -0,0 +1,347 @@
+// Package grib reads GRIB2 weather model output, such as GFS and HRRR
+// subsets from the NOMADS grib filter, and samples its fields at a point.
+//
+// Only what planning needs is decoded: analysis and forecast fields on
+// regular latitude/longitude or Lambert conformal grids, packed with simple
+// or complex packing. JPEG2000 and PNG packed fields can be converted with
+// wgrib2 -set_grib_type c3.
+package grib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Parameter identifies a field by its discipline, category and number
+// (GRIB2 code tables 0.0, 4.1 and 4.2)
+type Parameter struct {
+	Discipline int
+	Category   int
+	Number     int
+}
+
+// Parameters used in performance planning
+var (
+	Temperature         = Parameter{0, 0, 0}   // TMP in K
+	UWind               = Parameter{0, 2, 2}   // UGRD in m/s, eastward
+	VWind               = Parameter{0, 2, 3}   // VGRD in m/s, northward
+	SeaLevelPressure    = Parameter{0, 3, 1}   // PRMSL in Pa
+	GeopotentialHeight  = Parameter{0, 3, 5}   // HGT in gpm
+	SeaLevelPressureEta = Parameter{0, 3, 198} // MSLMA in Pa, the NCEP reduction closest to an altimeter setting
+)
+
+// String returns the NCEP abbreviation of the parameter, or its numbers
+func (p Parameter) String() string {
+	switch p {
+	case Temperature:
+		return "TMP"
+	case UWind:
+		return "UGRD"
+	case VWind:
+		return "VGRD"
+	case SeaLevelPressure:
+		return "PRMSL"
+	case GeopotentialHeight:
+		return "HGT"
+	case SeaLevelPressureEta:
+		return "MSLMA"
+	}
+	return fmt.Sprintf("%d.%d.%d", p.Discipline, p.Category, p.Number)
+}
+
+// Fixed surface types (GRIB2 code table 4.5)
+const (
+	SurfaceGround            = 1
+	SurfaceIsobaric          = 100 // Value in Pa
+	SurfaceMeanSeaLevel      = 101
+	SurfaceHeightAboveGround = 103 // Value in m
+)
+
+// Level is the first fixed surface of a field, e.g. {100, 85000} for 850 hPa
+type Level struct {
+	Type  int
+	Value float64 // In the unit of the surface type
+}
+
+// Message is one field of a GRIB2 message. Its values are decoded on first use.
+type Message struct {
+	Parameter Parameter
+	Level     Level
+	Reference time.Time     // Model run time, UTC
+	Forecast  time.Duration // Lead time from Reference
+	Grid      Grid
+
+	representation []byte // Section 5, the data representation
+	bitmap         []byte // Section 6 bitmap, nil if every point has a value
+	data           []byte // Section 7 payload
+	values         []float64
+}
+
+// Valid returns the time the field is valid at
+func (m *Message) Valid() time.Time {
+	return m.Reference.Add(m.Forecast)
+}
+
+// Values decodes the field, one value per grid point in scan order, with
+// NaN at points the bitmap excludes
+func (m *Message) Values() ([]float64, error) {
+	if m.values != nil {
+		return m.values, nil
+	}
+
+	points := m.Grid.Nx * m.Grid.Ny
+	packed, err := unpack(m.representation, m.data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", m.Parameter, err)
+	}
+
+	values := make([]float64, points)
+	if m.bitmap == nil {
+		if len(packed) != points {
+			return nil, fmt.Errorf("%s: %d values for %d grid points", m.Parameter, len(packed), points)
+		}
+		copy(values, packed)
+	} else {
+		n := 0
+		for i := range values {
+			if i/8 >= len(m.bitmap) || m.bitmap[i/8]&(0x80>>(i%8)) == 0 {
+				values[i] = math.NaN()
+				continue
+			}
+			if n >= len(packed) {
+				return nil, fmt.Errorf("%s: bitmap selects more points than the %d values", m.Parameter, len(packed))
+			}
+			values[i] = packed[n]
+			n++
+		}
+	}
+
+	m.values = values
+	return values, nil
+}
+
+// At interpolates the field bilinearly at a latitude and longitude in degrees
+func (m *Message) At(lat, lon float64) (float64, error) {
+	fi, fj, err := m.Grid.position(lat, lon)
+	if err != nil {
+		return 0, err
+	}
+	values, err := m.Values()
+	if err != nil {
+		return 0, err
+	}
+
+	i0, j0 := int(math.Floor(fi)), int(math.Floor(fj))
+	di, dj := fi-float64(i0), fj-float64(j0)
+	i1, j1 := i0+1, j0+1
+	if i1 == m.Grid.Nx {
+		i1 = m.Grid.wrap(i1)
+	}
+	if j1 == m.Grid.Ny {
+		j1 = j0
+	}
+
+	at := func(i, j int) float64 { return values[j*m.Grid.Nx+i] }
+	value := (at(i0, j0)*(1-di)+at(i1, j0)*di)*(1-dj) + (at(i0, j1)*(1-di)+at(i1, j1)*di)*dj
+	if math.IsNaN(value) {
+		return 0, fmt.Errorf("%s: no data at %.3f, %.3f", m.Parameter, lat, lon)
+	}
+	return value, nil
+}
+
+// Read reads every field of the GRIB2 messages in r. Fields with product
+// templates other than analyses and forecasts at a point in time (4.0,
+// 4.1, and the statistically processed 4.8 and 4.11) are skipped.
+func Read(r io.Reader) ([]*Message, error) {
+	br := bufio.NewReader(r)
+	var messages []*Message
+	for n := 1; ; n++ {
+		indicator := make([]byte, 16)
+		if _, err := io.ReadFull(br, indicator); err != nil {
+			if err == io.EOF {
+				return messages, nil
+			}
+			return nil, fmt.Errorf("reading GRIB message %d: %v", n, err)
+		}
+		if !bytes.Equal(indicator[:4], []byte("GRIB")) {
+			return nil, fmt.Errorf("not a GRIB file")
+		}
+		if indicator[7] != 2 {
+			return nil, fmt.Errorf("GRIB edition %d is not supported, only GRIB2", indicator[7])
+		}
+
+		length := binary.BigEndian.Uint64(indicator[8:])
+		if length < 16+4 || length > 1<<31 {
+			return nil, fmt.Errorf("invalid GRIB message length %d", length)
+		}
+		body := make([]byte, length-16)
+		if _, err := io.ReadFull(br, body); err != nil {
+			return nil, fmt.Errorf("reading GRIB message %d: %v", n, err)
+		}
+
+		fields, err := parseMessage(int(indicator[6]), body)
+		if err != nil {
+			return nil, fmt.Errorf("GRIB message %d: %v", n, err)
+		}
+		messages = append(messages, fields...)
+	}
+}
+
+// errSkip marks a product template that Read skips
+var errSkip = errors.New("unsupported product template")
+
+// parseMessage splits the sections of one message into its fields; a
+// message may repeat sections 2 to 7, or 3 to 7, or 4 to 7
+func parseMessage(discipline int, body []byte) ([]*Message, error) {
+	var fields []*Message
+	var reference time.Time
+	var grid Grid
+	var product *Message
+	var representation, bitmap []byte
+
+	for len(body) > 0 {
+		if bytes.HasPrefix(body, []byte("7777")) {
+			return fields, nil
+		}
+		if len(body) < 5 {
+			return nil, fmt.Errorf("truncated section")
+		}
+		length := int(binary.BigEndian.Uint32(body))
+		if length < 5 || length > len(body) {
+			return nil, fmt.Errorf("invalid section length %d", length)
+		}
+		section := body[:length]
+		body = body[length:]
+
+		var err error
+		switch section[4] {
+		case 1:
+			reference, err = parseIdentification(section)
+		case 2:
+			// Local use
+		case 3:
+			grid, err = parseGrid(section)
+		case 4:
+			product, err = parseProduct(section)
+			if err == errSkip {
+				product, err = nil, nil
+			} else if product != nil {
+				product.Parameter.Discipline = discipline
+			}
+		case 5:
+			representation = section
+		case 6:
+			bitmap, err = parseBitmap(section, bitmap)
+		case 7:
+			if product == nil {
+				continue
+			}
+			if representation == nil {
+				return nil, fmt.Errorf("data section without a data representation")
+			}
+			field := *product
+			field.Reference = reference
+			field.Grid = grid
+			field.representation = representation
+			field.bitmap = bitmap
+			field.data = section[5:]
+			fields = append(fields, &field)
+		default:
+			return nil, fmt.Errorf("unknown section %d", section[4])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %v", section[4], err)
+		}
+	}
+	return nil, fmt.Errorf("missing end section")
+}
+
+// parseIdentification returns the reference time of section 1
+func parseIdentification(section []byte) (time.Time, error) {
+	if len(section) < 19 {
+		return time.Time{}, fmt.Errorf("too short")
+	}
+	year := int(binary.BigEndian.Uint16(section[12:]))
+	return time.Date(year, time.Month(section[14]), int(section[15]),
+		int(section[16]), int(section[17]), int(section[18]), 0, time.UTC), nil
+}
+
+// parseProduct decodes the parameter, level and lead time of section 4
+func parseProduct(section []byte) (*Message, error) {
+	if len(section) < 9 {
+		return nil, fmt.Errorf("too short")
+	}
+	switch template := binary.BigEndian.Uint16(section[7:]); template {
+	case 0, 1, 8, 11:
+		// These share the layout of template 4.0 up to the second fixed surface
+	default:
+		return nil, errSkip
+	}
+	if len(section) < 34 {
+		return nil, fmt.Errorf("too short")
+	}
+
+	unit, ok := timeUnits[section[17]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported time unit %d", section[17])
+	}
+	m := &Message{
+		Parameter: Parameter{Category: int(section[9]), Number: int(section[10])},
+		Forecast:  time.Duration(signed(section[18:22])) * unit,
+		Level:     Level{Type: int(section[22]), Value: float64(signed(section[24:28]))},
+	}
+	if scale := section[23]; scale != 0xff {
+		m.Level.Value /= math.Pow(10, float64(signed([]byte{scale})))
+	}
+	return m, nil
+}
+
+// timeUnits maps GRIB2 code table 4.4 to durations
+var timeUnits = map[byte]time.Duration{
+	0:  time.Minute,
+	1:  time.Hour,
+	2:  24 * time.Hour,
+	10: 3 * time.Hour,
+	11: 6 * time.Hour,
+	12: 12 * time.Hour,
+	13: time.Second,
+}
+
+// parseBitmap returns the bitmap of section 6, nil if every point has a
+// value, or the previous bitmap when the section says to reuse it
+func parseBitmap(section, previous []byte) ([]byte, error) {
+	if len(section) < 6 {
+		return nil, fmt.Errorf("too short")
+	}
+	switch section[5] {
+	case 0:
+		return section[6:], nil
+	case 254:
+		if previous == nil {
+			return nil, fmt.Errorf("no previous bitmap to reuse")
+		}
+		return previous, nil
+	case 255:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("predefined bitmap %d is not supported", section[5])
+}
+
+// signed decodes a GRIB2 sign-and-magnitude integer: the high bit is the
+// sign and the remaining bits the magnitude
+func signed(b []byte) int64 {
+	v := int64(b[0] & 0x7f)
+	for _, c := range b[1:] {
+		v = v<<8 | int64(c)
+	}
+	if b[0]&0x80 != 0 {
+		return -v
+	}
+	return v
+}
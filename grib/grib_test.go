@@ -0,0 +1,373 @@
+package grib
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// testField describes a field for the test encoder
+type testField struct {
+	parameter Parameter
+	level     Level
+	hours     uint32   // Forecast lead time in hours
+	present   []bool   // Bitmap, nil for every point
+	values    []uint64 // Packed integers of the present points
+	complex   bool     // Second order spatial differencing instead of simple packing
+}
+
+// encode builds a GRIB2 message with one field per testField on a shared
+// 3 x 3 one-degree grid starting at 41N 80W, scanning south and east
+func encode(reference time.Time, fields ...testField) []byte {
+	var body bytes.Buffer
+	section := func(number byte, content []byte) {
+		binary.Write(&body, binary.BigEndian, uint32(len(content)+5))
+		body.WriteByte(number)
+		body.Write(content)
+	}
+
+	identification := make([]byte, 16)
+	binary.BigEndian.PutUint16(identification[7:], uint16(reference.Year()))
+	identification[9], identification[10] = byte(reference.Month()), byte(reference.Day())
+	identification[11], identification[12] = byte(reference.Hour()), byte(reference.Minute())
+	section(1, identification)
+
+	grid := make([]byte, 67)
+	binary.BigEndian.PutUint32(grid[1:], 9)
+	grid[9] = 6 // Spherical earth
+	binary.BigEndian.PutUint32(grid[25:], 3)
+	binary.BigEndian.PutUint32(grid[29:], 3)
+	binary.BigEndian.PutUint32(grid[41:], 41000000)
+	binary.BigEndian.PutUint32(grid[45:], 280000000)
+	binary.BigEndian.PutUint32(grid[50:], 39000000)
+	binary.BigEndian.PutUint32(grid[54:], 282000000)
+	binary.BigEndian.PutUint32(grid[58:], 1000000)
+	binary.BigEndian.PutUint32(grid[62:], 1000000)
+	section(3, grid)
+
+	for _, f := range fields {
+		product := make([]byte, 29)
+		product[4], product[5] = byte(f.parameter.Category), byte(f.parameter.Number)
+		product[12] = 1 // Hours
+		binary.BigEndian.PutUint32(product[13:], f.hours)
+		product[17] = byte(f.level.Type)
+		binary.BigEndian.PutUint32(product[19:], uint32(f.level.Value))
+		product[23] = 255
+		section(4, product)
+
+		var representation, data []byte
+		if f.complex {
+			representation, data = packComplex(f.values)
+		} else {
+			representation, data = packSimple(f.values)
+		}
+		section(5, representation)
+
+		if f.present == nil {
+			section(6, []byte{255})
+		} else {
+			bitmap := make([]byte, 2)
+			for i, ok := range f.present {
+				if ok {
+					bitmap[i/8] |= 0x80 >> (i % 8)
+				}
+			}
+			section(6, append([]byte{0}, bitmap...))
+		}
+		section(7, data)
+	}
+	body.WriteString("7777")
+
+	message := make([]byte, 16)
+	copy(message, "GRIB")
+	message[7] = 2
+	binary.BigEndian.PutUint64(message[8:], uint64(16+body.Len()))
+	return append(message, body.Bytes()...)
+}
+
+// representation builds section 5 with a reference value of 200, a
+// decimal scale of 1 and 8 bit references
+func representation(template uint16, count int) []byte {
+	r := make([]byte, 16)
+	binary.BigEndian.PutUint32(r, uint32(count))
+	binary.BigEndian.PutUint16(r[4:], template)
+	binary.BigEndian.PutUint32(r[6:], math.Float32bits(200))
+	r[12], r[13] = 0, 1 // Decimal scale factor 1
+	r[14] = 8
+	return r
+}
+
+// packSimple packs values in 8 bits each (template 5.0)
+func packSimple(values []uint64) ([]byte, []byte) {
+	data := make([]byte, len(values))
+	for i, v := range values {
+		data[i] = byte(v)
+	}
+	return representation(0, len(values)), data
+}
+
+// packComplex packs values as second order spatial differences in groups
+// of two (template 5.3)
+func packComplex(values []uint64) ([]byte, []byte) {
+	n := len(values)
+	diffs := make([]int64, n)
+	minimum := int64(math.MaxInt64)
+	for i := 2; i < n; i++ {
+		diffs[i] = int64(values[i]) - 2*int64(values[i-1]) + int64(values[i-2])
+		if diffs[i] < minimum {
+			minimum = diffs[i]
+		}
+	}
+	for i := 2; i < n; i++ {
+		diffs[i] -= minimum
+	}
+
+	var w bitWriter
+	for _, v := range []int64{int64(values[0]), int64(values[1]), minimum} {
+		if v < 0 {
+			w.write(uint64(-v)|0x8000, 16)
+		} else {
+			w.write(uint64(v), 16)
+		}
+	}
+
+	groups := (n + 1) / 2
+	references := make([]int64, groups)
+	widths := make([]int, groups)
+	for g := range references {
+		group := diffs[2*g : min(2*g+2, n)]
+		references[g] = group[0]
+		for _, d := range group {
+			if d < references[g] {
+				references[g] = d
+			}
+		}
+		for _, d := range group {
+			for d-references[g] >= 1<<widths[g] {
+				widths[g]++
+			}
+		}
+	}
+	for _, r := range references {
+		w.write(uint64(r), 8)
+	}
+	w.align()
+	for _, width := range widths {
+		w.write(uint64(width), 4)
+	}
+	w.align()
+	for range references {
+		w.write(0, 1) // Every group has the reference length of 2
+	}
+	w.align()
+	for g, r := range references {
+		for _, d := range diffs[2*g : min(2*g+2, n)] {
+			w.write(uint64(d-r), widths[g])
+		}
+	}
+
+	r := append(representation(3, n), make([]byte, 28)...)
+	binary.BigEndian.PutUint32(r[26:], uint32(groups))
+	r[31] = 4 // Bits per group width
+	binary.BigEndian.PutUint32(r[32:], 2)
+	r[36] = 1 // Group length increment
+	binary.BigEndian.PutUint32(r[37:], uint32(n-2*(groups-1)))
+	r[41] = 1 // Bits per group length
+	r[42], r[43] = 2, 2
+	return r, w.data
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// bitWriter is the inverse of bitReader
+type bitWriter struct {
+	data []byte
+	bits int
+}
+
+func (w *bitWriter) write(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if w.bits%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if v&(1<<i) != 0 {
+			w.data[w.bits/8] |= 0x80 >> (w.bits % 8)
+		}
+		w.bits++
+	}
+}
+
+func (w *bitWriter) align() {
+	w.bits = (w.bits + 7) / 8 * 8
+}
+
+func TestReadSimplePacking(t *testing.T) {
+	reference := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	data := encode(reference, testField{
+		parameter: Temperature,
+		level:     Level{Type: SurfaceHeightAboveGround, Value: 2},
+		hours:     6,
+		values:    []uint64{0, 10, 20, 30, 40, 50, 60, 70, 80},
+	})
+	messages, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 field, got %d", len(messages))
+	}
+
+	m := messages[0]
+	if m.Parameter != Temperature || m.Level != (Level{SurfaceHeightAboveGround, 2}) {
+		t.Errorf("Expected TMP at 2 m, got %s at %+v", m.Parameter, m.Level)
+	}
+	if want := reference.Add(6 * time.Hour); !m.Valid().Equal(want) {
+		t.Errorf("Expected valid time %s, got %s", want, m.Valid())
+	}
+
+	// (200 + x) / 10, rows running south from 41N and columns east from 80W
+	tests := []struct {
+		lat, lon float64
+		expected float64
+	}{
+		{41, -80, 20},
+		{41, -78, 22},
+		{39, -80, 26},
+		{40.5, -79.5, 22},
+		{40, 281, 24},
+	}
+	for _, tc := range tests {
+		got, err := m.At(tc.lat, tc.lon)
+		if err != nil || math.Abs(got-tc.expected) > 1e-6 {
+			t.Errorf("At(%g, %g): expected %g, got %g (%v)", tc.lat, tc.lon, tc.expected, got, err)
+		}
+	}
+
+	if _, err := m.At(42, -80); err == nil {
+		t.Error("Expected an error outside the grid")
+	}
+}
+
+func TestReadComplexPacking(t *testing.T) {
+	reference := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	values := []uint64{50, 53, 61, 62, 70, 85, 80, 81}
+	data := encode(reference, testField{
+		parameter: UWind,
+		level:     Level{Type: SurfaceIsobaric, Value: 85000},
+		present:   []bool{true, true, true, true, false, true, true, true, true},
+		values:    values,
+		complex:   true,
+	})
+
+	messages, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, err := messages[0].Values()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	n := 0
+	for i, v := range got {
+		if i == 4 {
+			if !math.IsNaN(v) {
+				t.Errorf("Expected the point outside the bitmap to be NaN, got %g", v)
+			}
+			continue
+		}
+		if want := (200 + float64(values[n])) / 10; math.Abs(v-want) > 1e-6 {
+			t.Errorf("Point %d: expected %g, got %g", i, want, v)
+		}
+		n++
+	}
+
+	if _, err := messages[0].At(40, -79); err == nil {
+		t.Error("Expected an error next to a missing point")
+	}
+}
+
+func TestReadMultipleFields(t *testing.T) {
+	reference := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	flat := []uint64{0, 0, 0, 0, 0, 0, 0, 0, 0}
+	data := encode(reference,
+		testField{parameter: UWind, level: Level{SurfaceIsobaric, 85000}, values: flat},
+		testField{parameter: VWind, level: Level{SurfaceIsobaric, 85000}, values: flat},
+	)
+	data = append(data, encode(reference.Add(time.Hour), testField{parameter: Temperature, values: flat})...)
+
+	messages, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(messages) != 3 || messages[1].Parameter != VWind || messages[2].Reference.Hour() != 13 {
+		t.Fatalf("Expected UGRD, VGRD and a later TMP, got %d fields", len(messages))
+	}
+
+	// Both components are 20 m/s, so the wind is from the southwest
+	w, err := WindAt(messages[0], messages[1], 40, -79)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(w.From.Degrees-225) > 1e-6 || math.Abs(w.Speed-20*math.Sqrt2*3600/1852) > 1e-6 {
+		t.Errorf("Expected 225° at 55 kt, got %+v", w)
+	}
+
+	if _, err := Read(bytes.NewReader([]byte("GRIB\x00\x00\x00\x01"))); err == nil {
+		t.Error("Expected an error for a truncated file")
+	}
+	if _, err := Read(bytes.NewReader([]byte("not a grib file!"))); err == nil {
+		t.Error("Expected an error for a file that is not GRIB")
+	}
+}
+
+func TestLambertGrid(t *testing.T) {
+	// A coarse version of the HRRR CONUS grid
+	g := Grid{
+		Template: GridLambert, Nx: 180, Ny: 106,
+		La1: 21.138123, Lo1: 237.280472, LoV: 262.5, Latin1: 38.5, Latin2: 38.5,
+		Dx: 30000, Dy: 30000, EarthRadius: 6371229,
+		ScanMode: scanPositiveJ, GridRelativeWinds: true,
+	}
+
+	fi, fj, err := g.position(g.La1, g.Lo1-360)
+	if err != nil || math.Abs(fi) > 1e-6 || math.Abs(fj) > 1e-6 {
+		t.Errorf("Expected the first point at 0, 0, got %g, %g (%v)", fi, fj, err)
+	}
+
+	// On the standard parallel the scale is true, so a degree north along
+	// the orientation meridian is one degree of arc
+	_, j1, _ := g.position(38.5, -97.5)
+	_, j2, _ := g.position(39.5, -97.5)
+	if want := g.EarthRadius * math.Pi / 180 / g.Dy; math.Abs(j2-j1-want) > 0.01 {
+		t.Errorf("Expected one degree north to be %.3f rows, got %.3f", want, j2-j1)
+	}
+
+	if _, _, err := g.position(60, -20); err == nil {
+		t.Error("Expected an error outside the grid")
+	}
+
+	// Grid-relative winds east of the orientation meridian are turned
+	// clockwise: a wind along the grid's y axis blows toward its bearing
+	values := make([]float64, g.Nx*g.Ny)
+	u := &Message{Parameter: UWind, Grid: g, values: values}
+	v := &Message{Parameter: VWind, Grid: g, values: make([]float64, len(values))}
+	for i := range v.values {
+		v.values[i] = 10
+	}
+	w, err := WindAt(u, v, 40, -85)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	theta := math.Sin(38.5*math.Pi/180) * 12.5
+	if math.Abs(w.From.Degrees-(180+theta)) > 1e-6 {
+		t.Errorf("Expected a wind from %.2f°, got %+v", 180+theta, w)
+	}
+}
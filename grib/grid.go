@@ -0,0 +1,209 @@
+package grib
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Grid templates (GRIB2 code table 3.1)
+const (
+	GridLatLon  = 0  // Regular latitude/longitude, e.g. GFS
+	GridLambert = 30 // Lambert conformal, e.g. HRRR and NAM
+)
+
+// Scanning mode flags (GRIB2 flag table 3.4)
+const (
+	scanNegativeI     = 0x80 // Points run west from the first point
+	scanPositiveJ     = 0x40 // Rows run north from the first point
+	scanConsecutiveJ  = 0x20 // Adjacent points are in the j direction
+	scanBoustrophedon = 0x10 // Alternate rows run in opposite directions
+)
+
+// Grid is the grid definition of section 3. Angles are in degrees and the
+// Lambert grid lengths in meters.
+type Grid struct {
+	Template int
+	Nx, Ny   int // Points along a row and number of rows
+
+	La1, Lo1 float64 // First grid point
+	La2, Lo2 float64 // Last grid point, latitude/longitude grids only
+	Di, Dj   float64 // Increments, latitude/longitude grids only
+
+	LoV            float64 // Orientation, Lambert grids only
+	Latin1, Latin2 float64 // Standard parallels, Lambert grids only
+	Dx, Dy         float64 // Grid lengths in meters, Lambert grids only
+	EarthRadius    float64 // Radius of the spherical earth in meters, Lambert grids only
+
+	ScanMode byte
+
+	// GridRelativeWinds is set when the u and v components are along the
+	// grid axes rather than east and north
+	GridRelativeWinds bool
+}
+
+// parseGrid decodes the grid definition of section 3
+func parseGrid(section []byte) (Grid, error) {
+	if len(section) < 14 {
+		return Grid{}, fmt.Errorf("too short")
+	}
+	if section[5] != 0 || section[10] != 0 {
+		return Grid{}, fmt.Errorf("predefined grids and irregular rows are not supported")
+	}
+
+	g := Grid{Template: int(binary.BigEndian.Uint16(section[12:]))}
+	u32 := func(i int) int { return int(binary.BigEndian.Uint32(section[i:])) }
+	angle := func(i int) float64 { return float64(signed(section[i:i+4])) / 1e6 }
+
+	switch g.Template {
+	case GridLatLon:
+		if len(section) < 72 {
+			return Grid{}, fmt.Errorf("too short")
+		}
+		if basic := binary.BigEndian.Uint32(section[38:]); basic != 0 && basic != 0xffffffff {
+			return Grid{}, fmt.Errorf("basic angle %d is not supported", basic)
+		}
+		g.Nx, g.Ny = u32(30), u32(34)
+		g.La1, g.Lo1 = angle(46), angle(50)
+		g.GridRelativeWinds = section[54]&0x08 != 0
+		g.La2, g.Lo2 = angle(55), angle(59)
+		g.Di, g.Dj = float64(u32(63))/1e6, float64(u32(67))/1e6
+		g.ScanMode = section[71]
+	case GridLambert:
+		if len(section) < 81 {
+			return Grid{}, fmt.Errorf("too short")
+		}
+		radius, err := earthRadius(section)
+		if err != nil {
+			return Grid{}, err
+		}
+		if section[63]&0x80 != 0 {
+			return Grid{}, fmt.Errorf("south polar Lambert grids are not supported")
+		}
+		g.EarthRadius = radius
+		g.Nx, g.Ny = u32(30), u32(34)
+		g.La1, g.Lo1 = angle(38), angle(42)
+		g.GridRelativeWinds = section[46]&0x08 != 0
+		g.LoV = angle(51)
+		g.Dx, g.Dy = float64(u32(55))/1e3, float64(u32(59))/1e3
+		g.ScanMode = section[64]
+		g.Latin1, g.Latin2 = angle(65), angle(69)
+	default:
+		// Fields on other grids are read, but cannot be sampled
+		return g, nil
+	}
+
+	if g.ScanMode&(scanConsecutiveJ|scanBoustrophedon) != 0 {
+		return Grid{}, fmt.Errorf("scanning mode %#x is not supported", g.ScanMode)
+	}
+	if g.Nx <= 0 || g.Ny <= 0 {
+		return Grid{}, fmt.Errorf("empty grid")
+	}
+	return g, nil
+}
+
+// earthRadius returns the radius of a spherical earth (code table 3.2)
+func earthRadius(section []byte) (float64, error) {
+	switch section[14] {
+	case 0:
+		return 6367470, nil
+	case 1:
+		scale := float64(signed(section[15:16]))
+		return float64(binary.BigEndian.Uint32(section[16:])) / math.Pow(10, scale), nil
+	case 6:
+		return 6371229, nil
+	}
+	return 0, fmt.Errorf("shape of the earth %d is not supported", section[14])
+}
+
+// position returns the fractional grid indices of a point, i along a row
+// and j across rows in scan order
+func (g Grid) position(lat, lon float64) (float64, float64, error) {
+	var fi, fj float64
+	switch g.Template {
+	case GridLatLon:
+		if g.Di <= 0 || g.Dj <= 0 {
+			return 0, 0, fmt.Errorf("grid has no increments")
+		}
+		if g.ScanMode&scanNegativeI != 0 {
+			fi = math.Mod(g.Lo1-lon+720, 360) / g.Di
+		} else {
+			fi = math.Mod(lon-g.Lo1+720, 360) / g.Di
+		}
+		if g.ScanMode&scanPositiveJ != 0 {
+			fj = (lat - g.La1) / g.Dj
+		} else {
+			fj = (g.La1 - lat) / g.Dj
+		}
+	case GridLambert:
+		x, y := g.lambert(lat, lon)
+		x1, y1 := g.lambert(g.La1, g.Lo1)
+		fi, fj = (x-x1)/g.Dx, (y-y1)/g.Dy
+		if g.ScanMode&scanNegativeI != 0 {
+			fi = -fi
+		}
+		if g.ScanMode&scanPositiveJ == 0 {
+			fj = -fj
+		}
+	default:
+		return 0, 0, fmt.Errorf("grid template 3.%d is not supported", g.Template)
+	}
+
+	maxI := float64(g.Nx - 1)
+	if g.wrap(g.Nx) == 0 {
+		maxI = float64(g.Nx)
+	}
+	if fi < -1e-9 || fj < -1e-9 || fi > maxI+1e-9 || fj > float64(g.Ny-1)+1e-9 {
+		return 0, 0, fmt.Errorf("%.3f, %.3f is outside the grid", lat, lon)
+	}
+	return math.Max(fi, 0), math.Max(fj, 0), nil
+}
+
+// wrap maps the column after the last one back to the first for global
+// latitude/longitude grids, and to the last column otherwise
+func (g Grid) wrap(i int) int {
+	if g.Template == GridLatLon && math.Abs(float64(g.Nx)*g.Di-360) < g.Di/2 {
+		return i % g.Nx
+	}
+	return g.Nx - 1
+}
+
+// lambert projects a point onto the Lambert conformal plane, in meters
+// from the pole
+func (g Grid) lambert(lat, lon float64) (float64, float64) {
+	n, f := g.cone()
+	rho := g.EarthRadius * f / math.Pow(math.Tan(math.Pi/4+radians(lat)/2), n)
+	theta := n * radians(longitudeDelta(lon, g.LoV))
+	return rho * math.Sin(theta), -rho * math.Cos(theta)
+}
+
+// cone returns the cone constant and scale factor of a Lambert grid
+func (g Grid) cone() (float64, float64) {
+	phi1, phi2 := radians(g.Latin1), radians(g.Latin2)
+	n := math.Sin(phi1)
+	if math.Abs(phi1-phi2) > 1e-9 {
+		n = math.Log(math.Cos(phi1)/math.Cos(phi2)) /
+			math.Log(math.Tan(math.Pi/4+phi2/2)/math.Tan(math.Pi/4+phi1/2))
+	}
+	return n, math.Cos(phi1) * math.Pow(math.Tan(math.Pi/4+phi1/2), n) / n
+}
+
+// rotation returns the bearing of the grid's y axis at a point, in radians
+// clockwise from true north, for turning grid-relative winds to earth
+func (g Grid) rotation(lat, lon float64) float64 {
+	if !g.GridRelativeWinds || g.Template != GridLambert {
+		return 0
+	}
+	n, _ := g.cone()
+	return n * radians(longitudeDelta(lon, g.LoV))
+}
+
+// longitudeDelta returns lon - ref normalized to -180 to 180 degrees
+func longitudeDelta(lon, ref float64) float64 {
+	return math.Mod(lon-ref+540, 360) - 180
+}
+
+// radians converts degrees to radians
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
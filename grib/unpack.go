@@ -0,0 +1,240 @@
+package grib
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Data representation templates (GRIB2 code table 5.0)
+const (
+	packingSimple              = 0
+	packingComplex             = 2
+	packingSpatialDifferencing = 3
+)
+
+// unpack decodes the values of section 7 with the data representation of
+// section 5, returning NaN for missing values
+func unpack(representation, data []byte) ([]float64, error) {
+	if len(representation) < 21 {
+		return nil, fmt.Errorf("data representation too short")
+	}
+	count := int(binary.BigEndian.Uint32(representation[5:]))
+	template := binary.BigEndian.Uint16(representation[9:])
+
+	reference := float64(math.Float32frombits(binary.BigEndian.Uint32(representation[11:])))
+	binaryScale := math.Pow(2, float64(signed(representation[15:17])))
+	decimalScale := math.Pow(10, float64(signed(representation[17:19])))
+	bits := int(representation[19])
+
+	var packed []int64
+	var err error
+	switch template {
+	case packingSimple:
+		packed, err = unpackSimple(data, count, bits)
+	case packingComplex, packingSpatialDifferencing:
+		packed, err = unpackComplex(representation, data, count, bits, template == packingSpatialDifferencing)
+	default:
+		return nil, fmt.Errorf("data representation template 5.%d is not supported", template)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, len(packed))
+	for i, x := range packed {
+		if x == missing {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = (reference + float64(x)*binaryScale) / decimalScale
+	}
+	return values, nil
+}
+
+// missing marks a missing value in unpacked integers
+const missing = math.MinInt64
+
+// unpackSimple reads count values of a fixed width (template 5.0)
+func unpackSimple(data []byte, count, bits int) ([]int64, error) {
+	packed := make([]int64, count)
+	if bits == 0 {
+		return packed, nil
+	}
+	r := bitReader{data: data}
+	for i := range packed {
+		x, err := r.read(bits)
+		if err != nil {
+			return nil, err
+		}
+		packed[i] = int64(x)
+	}
+	return packed, nil
+}
+
+// unpackComplex reads values packed in groups, each with its own reference
+// and width (template 5.2), optionally as spatial differences (template 5.3)
+func unpackComplex(representation, data []byte, count, bits int, differenced bool) ([]int64, error) {
+	if len(representation) < 47 || differenced && len(representation) < 49 {
+		return nil, fmt.Errorf("data representation too short")
+	}
+	missingManagement := representation[22]
+	groups := int(binary.BigEndian.Uint32(representation[31:]))
+	widthReference := int64(representation[35])
+	widthBits := int(representation[36])
+	lengthReference := int64(binary.BigEndian.Uint32(representation[37:]))
+	lengthIncrement := int64(representation[41])
+	lastLength := int64(binary.BigEndian.Uint32(representation[42:]))
+	lengthBits := int(representation[46])
+	if missingManagement > 2 {
+		return nil, fmt.Errorf("missing value management %d is not supported", missingManagement)
+	}
+	if groups <= 0 || groups > count {
+		return nil, fmt.Errorf("invalid number of groups %d", groups)
+	}
+
+	r := bitReader{data: data}
+
+	// The spatial differencing descriptors precede the groups
+	var order int
+	var initial [2]int64
+	var minimum int64
+	if differenced {
+		order = int(representation[47])
+		octets := int(representation[48])
+		if order < 1 || order > 2 || octets < 1 || len(data) < (order+1)*octets {
+			return nil, fmt.Errorf("invalid spatial differencing of order %d with %d octets", order, octets)
+		}
+		for i := 0; i < order; i++ {
+			initial[i] = signed(data[i*octets : (i+1)*octets])
+		}
+		minimum = signed(data[order*octets : (order+1)*octets])
+		r.pos = (order + 1) * octets * 8
+	}
+
+	readGroup := func(n, width int, base int64, scale int64) ([]int64, error) {
+		values := make([]int64, n)
+		for i := range values {
+			x, err := r.read(width)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = base + int64(x)*scale
+		}
+		r.align()
+		return values, nil
+	}
+	references, err := readGroup(groups, bits, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	widths, err := readGroup(groups, widthBits, widthReference, 1)
+	if err != nil {
+		return nil, err
+	}
+	lengths, err := readGroup(groups, lengthBits, lengthReference, lengthIncrement)
+	if err != nil {
+		return nil, err
+	}
+	lengths[groups-1] = lastLength
+
+	packed := make([]int64, 0, count)
+	for g := 0; g < groups; g++ {
+		width := int(widths[g])
+		for i := int64(0); i < lengths[g]; i++ {
+			if width == 0 {
+				if missingManagement > 0 && isMissing(uint64(references[g]), bits, missingManagement) {
+					packed = append(packed, missing)
+				} else {
+					packed = append(packed, references[g])
+				}
+				continue
+			}
+			x, err := r.read(width)
+			if err != nil {
+				return nil, err
+			}
+			if missingManagement > 0 && isMissing(x, width, missingManagement) {
+				packed = append(packed, missing)
+			} else {
+				packed = append(packed, references[g]+int64(x))
+			}
+		}
+	}
+	if len(packed) != count {
+		return nil, fmt.Errorf("groups hold %d values, expected %d", len(packed), count)
+	}
+
+	if differenced {
+		undifference(packed, order, initial, minimum)
+	}
+	return packed, nil
+}
+
+// undifference reverses first or second order spatial differencing over
+// the values that are not missing
+func undifference(packed []int64, order int, initial [2]int64, minimum int64) {
+	n := 0
+	var previous [2]int64 // The last and second to last values restored
+	for i, x := range packed {
+		if x == missing {
+			continue
+		}
+		switch {
+		case n < order:
+			x = initial[n]
+		case order == 1:
+			x += minimum + previous[0]
+		default:
+			x += minimum + 2*previous[0] - previous[1]
+		}
+		packed[i] = x
+		previous[1], previous[0] = previous[0], x
+		n++
+	}
+}
+
+// isMissing reports whether a packed value of the given width is the
+// primary, or with management 2 the secondary, missing value
+func isMissing(x uint64, width int, management byte) bool {
+	if width == 0 {
+		return false
+	}
+	all := uint64(1)<<width - 1
+	return x == all || management == 2 && x == all-1
+}
+
+// bitReader reads big-endian unsigned integers of any width from a byte slice
+type bitReader struct {
+	data []byte
+	pos  int // in bits
+}
+
+// read returns the next n bits as an unsigned integer
+func (r *bitReader) read(n int) (uint64, error) {
+	if n > 64 {
+		return 0, fmt.Errorf("invalid bit width %d", n)
+	}
+	var v uint64
+	for n > 0 {
+		i := r.pos / 8
+		if i >= len(r.data) {
+			return 0, fmt.Errorf("data section too short")
+		}
+		available := 8 - r.pos%8
+		take := available
+		if n < take {
+			take = n
+		}
+		bits := uint64(r.data[i]>>(available-take)) & (1<<take - 1)
+		v = v<<take | bits
+		r.pos += take
+		n -= take
+	}
+	return v, nil
+}
+
+// align skips to the next whole byte
+func (r *bitReader) align() {
+	r.pos = (r.pos + 7) / 8 * 8
+}
@@ -0,0 +1,37 @@
+package grib
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// WindAt combines u and v component fields into the wind at a point, from
+// true north in knots, turning grid-relative components to earth-relative
+func WindAt(u, v *Message, lat, lon float64) (wind.Wind, error) {
+	if u.Parameter != UWind || v.Parameter != VWind {
+		return wind.Wind{}, fmt.Errorf("expected UGRD and VGRD fields, got %s and %s", u.Parameter, v.Parameter)
+	}
+	ug, err := u.At(lat, lon)
+	if err != nil {
+		return wind.Wind{}, err
+	}
+	vg, err := v.At(lat, lon)
+	if err != nil {
+		return wind.Wind{}, err
+	}
+
+	theta := u.Grid.rotation(lat, lon)
+	east := ug*math.Cos(theta) + vg*math.Sin(theta)
+	north := vg*math.Cos(theta) - ug*math.Sin(theta)
+
+	speed := units.MetersPerSecondToKnots(math.Hypot(east, north))
+	if speed < 1e-9 {
+		return wind.Wind{}, nil
+	}
+	// The wind blows from the opposite direction of its vector
+	from := math.Atan2(-east, -north) * 180 / math.Pi
+	return wind.Wind{From: wind.TrueDirection(from), Speed: speed}, nil
+}
@@ -0,0 +1,167 @@
+// Package solve provides root finding for the reverse performance solvers.
+//
+// Chart lookups are monotone but only piecewise linear, and they go flat
+// where an input is clamped to the edge of a chart. The routines here are
+// written to stay robust on those flat regions: they never divide by a
+// zero slope and always keep a sign-changing bracket.
+package solve
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNotBracketed is returned when the function does not change sign over the interval
+var ErrNotBracketed = errors.New("solve: root not bracketed by interval")
+
+// ErrInfeasible is returned when no point of the interval satisfies the constraint
+var ErrInfeasible = errors.New("solve: constraint not satisfied anywhere in interval")
+
+// epsilon is the float64 machine epsilon
+const epsilon = 2.220446049250313e-16
+
+// maxIterations bounds every search; bisection halves the interval each
+// step, so this is far more than needed for any float64 interval
+const maxIterations = 200
+
+// Bisect finds x in [lo, hi] with f(x) = 0 by bisection, to within tol.
+// f(lo) and f(hi) must have opposite signs (or one of them be zero).
+func Bisect(f func(float64) float64, lo, hi, tol float64) (float64, error) {
+	flo, fhi := f(lo), f(hi)
+	switch {
+	case flo == 0:
+		return lo, nil
+	case fhi == 0:
+		return hi, nil
+	case math.Signbit(flo) == math.Signbit(fhi):
+		return 0, ErrNotBracketed
+	}
+
+	for i := 0; i < maxIterations && hi-lo > tol; i++ {
+		mid := lo + (hi-lo)/2
+		fmid := f(mid)
+		if fmid == 0 {
+			return mid, nil
+		}
+		if math.Signbit(fmid) == math.Signbit(flo) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo + (hi-lo)/2, nil
+}
+
+// Brent finds x in [lo, hi] with f(x) = 0 using Brent's method, to within
+// tol. It converges much faster than bisection on smooth or piecewise-linear
+// functions and falls back to bisection whenever an interpolation step would
+// be unreliable, such as across a flat region.
+func Brent(f func(float64) float64, lo, hi, tol float64) (float64, error) {
+	a, b := lo, hi
+	fa, fb := f(a), f(b)
+	switch {
+	case fa == 0:
+		return a, nil
+	case fb == 0:
+		return b, nil
+	case math.Signbit(fa) == math.Signbit(fb):
+		return 0, ErrNotBracketed
+	}
+
+	// b is the best estimate, a the previous one and c the bracketing counterpart
+	c, fc := a, fa
+	d := b - a
+	e := d
+
+	for i := 0; i < maxIterations; i++ {
+		if math.Signbit(fb) == math.Signbit(fc) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+
+		t := 2*epsilon*math.Abs(b) + tol/2
+		m := (c - b) / 2
+		if math.Abs(m) <= t || fb == 0 {
+			return b, nil
+		}
+
+		if math.Abs(e) >= t && math.Abs(fa) > math.Abs(fb) {
+			// Attempt inverse quadratic interpolation, or the secant method
+			// when only two distinct points are available
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2 * m * s
+				q = 1 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*m*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			} else {
+				p = -p
+			}
+
+			if 2*p < 3*m*q-math.Abs(t*q) && p < math.Abs(e*q/2) {
+				e = d
+				d = p / q
+			} else {
+				d = m
+				e = m
+			}
+		} else {
+			d = m
+			e = m
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > t {
+			b += d
+		} else if m > 0 {
+			b += t
+		} else {
+			b -= t
+		}
+		fb = f(b)
+	}
+
+	return b, nil
+}
+
+// MaxFeasible finds the largest x in [lo, hi] satisfying ok(x), where ok is
+// true up to some threshold and false beyond it (as for "distance fits the
+// runway" when increasing weight). The result is within tol of the threshold
+// and always satisfies ok. If ok(hi) holds, hi is returned; if ok(lo) does
+// not, ErrInfeasible is returned.
+//
+// Working on the predicate rather than a root means plateaus where the
+// underlying function is flat (for example at a clamped chart edge) can
+// never stall the search or return a point from the wrong side.
+func MaxFeasible(ok func(float64) bool, lo, hi, tol float64) (float64, error) {
+	if ok(hi) {
+		return hi, nil
+	}
+	if !ok(lo) {
+		return 0, ErrInfeasible
+	}
+
+	for i := 0; i < maxIterations && hi-lo > tol; i++ {
+		mid := lo + (hi-lo)/2
+		if ok(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo, nil
+}
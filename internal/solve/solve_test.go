@@ -0,0 +1,107 @@
+package solve
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// clampedLinear mimics a chart lookup that is flat outside [100, 200]
+func clampedLinear(x float64) float64 {
+	x = math.Max(100, math.Min(200, x))
+	return 3*x - 450
+}
+
+func TestRootFinders(t *testing.T) {
+	testCases := []struct {
+		name   string
+		f      func(float64) float64
+		lo, hi float64
+		root   float64
+	}{
+		{"Linear", func(x float64) float64 { return 2*x - 3 }, 0, 10, 1.5},
+		{"Cubic", func(x float64) float64 { return x*x*x - 8 }, 0, 5, 2},
+		{"Flat Below Chart Edge", clampedLinear, 0, 1000, 150},
+		{"Root At Lower Bound", func(x float64) float64 { return x - 1 }, 1, 4, 1},
+		{"Root At Upper Bound", func(x float64) float64 { return x - 4 }, 1, 4, 4},
+		{"Piecewise Kink", func(x float64) float64 {
+			if x < 5 {
+				return x - 6
+			}
+			return 10*x - 51
+		}, 0, 10, 5.1},
+	}
+
+	solvers := map[string]func(func(float64) float64, float64, float64, float64) (float64, error){
+		"Bisect": Bisect,
+		"Brent":  Brent,
+	}
+
+	for _, tc := range testCases {
+		for name, solver := range solvers {
+			t.Run(tc.name+"/"+name, func(t *testing.T) {
+				root, err := solver(tc.f, tc.lo, tc.hi, 1e-9)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if math.Abs(root-tc.root) > 1e-6 {
+					t.Errorf("Got root %.9f, expected %.9f", root, tc.root)
+				}
+			})
+		}
+	}
+}
+
+func TestRootFindersNotBracketed(t *testing.T) {
+	// Entirely on the flat region above the chart edge: no sign change
+	for name, solver := range map[string]func(func(float64) float64, float64, float64, float64) (float64, error){
+		"Bisect": Bisect,
+		"Brent":  Brent,
+	} {
+		if _, err := solver(clampedLinear, 300, 400, 1e-9); !errors.Is(err, ErrNotBracketed) {
+			t.Errorf("%s: expected ErrNotBracketed, got %v", name, err)
+		}
+	}
+}
+
+func TestMaxFeasible(t *testing.T) {
+	// Distance-like function: flat until 100, rising to 200, flat beyond
+	fits := func(limit float64) func(float64) bool {
+		return func(x float64) bool { return clampedLinear(x) <= limit }
+	}
+
+	testCases := []struct {
+		name    string
+		limit   float64
+		lo, hi  float64
+		want    float64
+		wantErr error
+	}{
+		{"Interior Threshold", 0, 0, 1000, 150, nil},
+		{"Whole Interval Feasible", 1000, 0, 1000, 1000, nil},
+		{"Nothing Feasible", -500, 0, 1000, 0, ErrInfeasible},
+		// On the upper plateau the function equals the limit everywhere, so
+		// the largest feasible point is the end of the interval
+		{"Plateau At Limit", 150, 0, 1000, 1000, nil},
+		// On the lower plateau the limit is met exactly; the threshold is where the rise begins
+		{"Threshold At Plateau Edge", -150, 0, 1000, 100, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MaxFeasible(fits(tc.limit), tc.lo, tc.hi, 1e-6)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Got error %v, expected %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if math.Abs(got-tc.want) > 1e-5 {
+				t.Errorf("Got %.6f, expected %.6f", got, tc.want)
+			}
+			if !fits(tc.limit)(got) {
+				t.Errorf("Result %.6f does not satisfy the constraint", got)
+			}
+		})
+	}
+}
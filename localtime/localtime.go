@@ -0,0 +1,59 @@
+// Package localtime reads and prints times for briefings in both the
+// departure airport's local time and Zulu (UTC).
+//
+// Forecasts are issued in Zulu while pilots plan in local time; a time
+// without an explicit zone is always taken as local to the airport, never as
+// the zone of the machine running the tool.
+package localtime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// zonedLayouts are accepted for times carrying a "Z" or numeric UTC offset
+var zonedLayouts = []string{
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04Z07:00",
+}
+
+// localLayouts are accepted for wall-clock times at the airport
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// Parse reads a date and time such as "2026-10-15 09:00" (local to loc) or
+// "2026-10-15T13:00Z" (with an explicit zone). A nil loc accepts only times
+// with an explicit zone.
+func Parse(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range localLayouts {
+		if _, err := time.Parse(layout, value); err != nil {
+			continue
+		}
+		if loc == nil {
+			return time.Time{}, fmt.Errorf("time %q has no zone; add Z for Zulu or give the airport for local time", value)
+		}
+		return time.ParseInLocation(layout, value, loc)
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (expected YYYY-MM-DD HH:MM local, or with Z for Zulu)", value)
+}
+
+// Format prints a time in local time at loc followed by Zulu, e.g.
+// "Thu 15 Oct 09:00 EDT (1300Z)". With a nil loc only Zulu is printed.
+func Format(t time.Time, loc *time.Location) string {
+	zulu := t.UTC().Format("1504Z")
+	if loc == nil {
+		return t.UTC().Format("Mon 02 Jan ") + zulu
+	}
+	return fmt.Sprintf("%s (%s)", t.In(loc).Format("Mon 02 Jan 15:04 MST"), zulu)
+}
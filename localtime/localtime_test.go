@@ -0,0 +1,61 @@
+package localtime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Error loading time zone: %v", err)
+	}
+
+	testCases := []struct {
+		value string
+		loc   *time.Location
+		want  time.Time
+	}{
+		// EDT is UTC-4 in October, EST is UTC-5 in January
+		{"2026-10-15 09:00", eastern, time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)},
+		{"2026-01-15T09:00", eastern, time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC)},
+		{"2026-10-15T13:00Z", eastern, time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)},
+		{"2026-10-15T13:00:00Z", nil, time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)},
+		{"2026-10-15 09:00-04:00", nil, time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		got, err := Parse(tc.value, tc.loc)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.value, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("%s: got %v, expected %v", tc.value, got.UTC(), tc.want)
+		}
+	}
+
+	for _, value := range []string{"tomorrow", "2026-10-15", "09:00"} {
+		if _, err := Parse(value, eastern); err == nil {
+			t.Errorf("%s: expected an error", value)
+		}
+	}
+	if _, err := Parse("2026-10-15 09:00", nil); err == nil {
+		t.Error("Local time without a location: expected an error")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Error loading time zone: %v", err)
+	}
+
+	departure := time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)
+	if got := Format(departure, eastern); got != "Thu 15 Oct 09:00 EDT (1300Z)" {
+		t.Errorf("Got %q", got)
+	}
+	if got := Format(departure, nil); got != "Thu 15 Oct 1300Z" {
+		t.Errorf("Without location: got %q", got)
+	}
+}
@@ -0,0 +1,240 @@
+// Package margins records the takeoff margins of standard scenarios over
+// time, so a school can see a trend such as "our margins on runway 35
+// have been shrinking all summer", and writes them as OpenMetrics time
+// series for Prometheus or any tool that reads the format.
+package margins
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// Sample is a scenario's takeoff margin on one runway end in the weather
+// of one METAR
+type Sample struct {
+	Time     time.Time `json:"time"` // Observation time of the METAR
+	Scenario string    `json:"scenario"`
+	Airport  string    `json:"airport"`
+	Runway   string    `json:"runway"` // Runway end, e.g. "35"
+
+	RunwayLength    float64 `json:"runway_length"`    // in feet
+	TakeoffDistance float64 `json:"takeoff_distance"` // Over a 50 ft obstacle, in feet
+	Margin          float64 `json:"margin"`           // Runway length left over, in feet
+	DensityAltitude float64 `json:"density_altitude"` // in feet
+}
+
+// Compute calculates a takeoff weight's margin on every runway end of an
+// airport in a METAR, with the wind component on each end. Ends outside
+// the chart, such as with more tailwind than it covers, are left out; it
+// is an error when no end can be computed.
+func Compute(calculator *performance.TakeoffCalculator, scenario string, weight float64, airport *airports.Airport, obs *weather.Observation) ([]Sample, error) {
+	if !obs.HasTemperature {
+		return nil, fmt.Errorf("METAR %s has no temperature", obs.Station)
+	}
+	pressureAlt := airport.Elevation
+	if obs.Altimeter > 0 {
+		pressureAlt = atmosphere.PressureAltitude(airport.Elevation, obs.Altimeter)
+	}
+	densityAlt := atmosphere.DensityAltitude(pressureAlt, obs.Temperature)
+
+	var samples []Sample
+	var lastErr error
+	for _, rwy := range airport.Runways {
+		for _, end := range rwy.Ends {
+			params := performance.TakeoffParams{
+				PressureAltitude: pressureAlt,
+				Temperature:      obs.Temperature,
+				Weight:           weight,
+				WindComponent:    wind.Decompose(obs.Wind, wind.TrueDirection(end.TrueHeading), 0).Headwind,
+			}
+			if obs.Variable {
+				params.WindComponent = 0
+			}
+			if errs := calculator.Validate(params); len(errs) > 0 {
+				lastErr = fmt.Errorf("runway %s: %w", end.ID, errs)
+				continue
+			}
+			result, err := calculator.CalculateTakeoff(params)
+			if err != nil {
+				lastErr = fmt.Errorf("runway %s: %w", end.ID, err)
+				continue
+			}
+			samples = append(samples, Sample{
+				Time:            obs.Observed,
+				Scenario:        scenario,
+				Airport:         airport.Ident,
+				Runway:          end.ID,
+				RunwayLength:    rwy.Length,
+				TakeoffDistance: math.Round(result.TakeoffDistance),
+				Margin:          math.Round(rwy.Length - result.TakeoffDistance),
+				DensityAltitude: math.Round(densityAlt),
+			})
+		}
+	}
+	if len(samples) == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no runways known at %s", airport.Ident)
+		}
+		return nil, lastErr
+	}
+	return samples, nil
+}
+
+// Log keeps samples as JSON lines in a file, oldest first. Samples are
+// only ever appended.
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog creates a log in a file, created on the first append
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append adds samples to the log
+func (l *Log) Append(samples []Sample) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, s := range samples {
+		enc.Encode(s)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns the samples in the log from since on; a log not yet
+// created holds none
+func (l *Log) Read(since time.Time) ([]Sample, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", l.path, line, err)
+		}
+		if !s.Time.Before(since) {
+			samples = append(samples, s)
+		}
+	}
+	return samples, scanner.Err()
+}
+
+// Latest keeps the newest sample of each scenario and runway end
+func Latest(samples []Sample) []Sample {
+	type series struct{ scenario, airport, runway string }
+	latest := make(map[series]Sample)
+	for _, s := range samples {
+		key := series{s.Scenario, s.Airport, s.Runway}
+		if prev, ok := latest[key]; !ok || s.Time.After(prev.Time) {
+			latest[key] = s
+		}
+	}
+	out := make([]Sample, 0, len(latest))
+	for _, s := range latest {
+		out = append(out, s)
+	}
+	return out
+}
+
+// ContentType is the media type of the OpenMetrics text format
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// family is one metric written from the samples
+type family struct {
+	name, unit, help string
+	value            func(Sample) float64
+}
+
+// families lists the metrics of each sample
+var families = []family{
+	{"otto_takeoff_margin_feet", "feet", "Runway length left over after the takeoff distance over a 50 ft obstacle",
+		func(s Sample) float64 { return s.Margin }},
+	{"otto_takeoff_margin_ratio", "", "Fraction of the runway length left over after the takeoff distance",
+		func(s Sample) float64 { return s.Margin / s.RunwayLength }},
+	{"otto_takeoff_distance_feet", "feet", "Takeoff distance over a 50 ft obstacle",
+		func(s Sample) float64 { return s.TakeoffDistance }},
+	{"otto_density_altitude_feet", "feet", "Density altitude at the airport",
+		func(s Sample) float64 { return s.DensityAltitude }},
+}
+
+// WriteOpenMetrics writes samples as gauges in the OpenMetrics text
+// format, each with its observation time, series by series in time order
+func WriteOpenMetrics(w io.Writer, samples []Sample) error {
+	sorted := append([]Sample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Scenario != b.Scenario {
+			return a.Scenario < b.Scenario
+		}
+		if a.Airport != b.Airport {
+			return a.Airport < b.Airport
+		}
+		if a.Runway != b.Runway {
+			return a.Runway < b.Runway
+		}
+		return a.Time.Before(b.Time)
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", f.name)
+		if f.unit != "" {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", f.name, f.unit)
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, f.help)
+		for _, s := range sorted {
+			fmt.Fprintf(bw, "%s{scenario=\"%s\",airport=\"%s\",runway=\"%s\"} %s %s\n", f.name,
+				escape(s.Scenario), escape(s.Airport), escape(s.Runway),
+				strconv.FormatFloat(f.value(s), 'g', -1, 64), strconv.FormatInt(s.Time.Unix(), 10))
+		}
+	}
+	fmt.Fprintf(bw, "# EOF\n")
+	return bw.Flush()
+}
+
+// escape escapes a label value
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,124 @@
+package margins
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// testAirport has a 3000 ft north-south runway
+var testAirport = &airports.Airport{
+	Ident: "TST", Elevation: 500,
+	Runways: []airports.Runway{{ID: "18/36", Length: 3000, Ends: []airports.RunwayEnd{
+		{ID: "18", TrueHeading: 180},
+		{ID: "36", TrueHeading: 360},
+	}}},
+}
+
+func testMETAR(t *testing.T, raw string) *weather.Observation {
+	obs, err := weather.ParseMETAR(raw, time.Date(2026, 7, 1, 18, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return obs
+}
+
+func TestCompute(t *testing.T) {
+	profile, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calculator := profile.NewTakeoffCalculator()
+
+	samples, err := Compute(calculator, "solo", 2000, testAirport, testMETAR(t, "METAR TST 011753Z 36004KT 10SM CLR 30/15 A2992"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("Expected a sample per runway end, got %+v", samples)
+	}
+	s18, s36 := samples[0], samples[1]
+	if s36.Runway != "36" || s36.Scenario != "solo" || s36.Airport != "TST" || !s36.Time.Equal(time.Date(2026, 7, 1, 17, 53, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected sample %+v", s36)
+	}
+	if s36.Margin != s36.RunwayLength-s36.TakeoffDistance || s36.Margin <= s18.Margin {
+		t.Errorf("Expected more margin into the wind, got %v on 18 and %v on 36", s18.Margin, s36.Margin)
+	}
+	if s18.DensityAltitude < 2000 {
+		t.Errorf("Expected a hot day's density altitude, got %v", s18.DensityAltitude)
+	}
+
+	samples, err = Compute(calculator, "solo", 2000, testAirport, testMETAR(t, "METAR TST 011753Z 36012KT 10SM CLR 30/15 A2992"))
+	if err != nil || len(samples) != 1 || samples[0].Runway != "36" {
+		t.Errorf("Expected runway 18 left out with more tailwind than the chart covers, got %+v, %v", samples, err)
+	}
+	if _, err := Compute(calculator, "solo", 2000, testAirport, testMETAR(t, "METAR TST 011753Z 36010KT 10SM CLR A2992")); err == nil {
+		t.Errorf("Expected an error without a temperature")
+	}
+	if _, err := Compute(calculator, "heavy", 9000, testAirport, testMETAR(t, "METAR TST 011753Z 36010KT 10SM CLR 30/15 A2992")); err == nil {
+		t.Errorf("Expected an error when no runway end is on the chart")
+	}
+}
+
+func TestLog(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "margins.jsonl"))
+	if samples, err := log.Read(time.Time{}); err != nil || len(samples) != 0 {
+		t.Errorf("Expected an empty log before the first append, got %v, %v", samples, err)
+	}
+
+	june := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	august := time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)
+	for _, batch := range [][]Sample{
+		{{Time: june, Scenario: "solo", Airport: "TST", Runway: "36", RunwayLength: 3000, Margin: 1400}},
+		{{Time: august, Scenario: "solo", Airport: "TST", Runway: "36", RunwayLength: 3000, Margin: 900}},
+	} {
+		if err := log.Append(batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	samples, err := log.Read(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(samples) != 1 || samples[0].Margin != 900 {
+		t.Errorf("Expected the August sample, got %+v, %v", samples, err)
+	}
+	samples, _ = log.Read(time.Time{})
+	if latest := Latest(samples); len(latest) != 1 || !latest[0].Time.Equal(august) {
+		t.Errorf("Expected only the latest sample, got %+v", latest)
+	}
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	june := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	samples := []Sample{
+		{Time: june.Add(24 * time.Hour), Scenario: "solo", Airport: "TST", Runway: "36", RunwayLength: 3000, TakeoffDistance: 2000, Margin: 1000, DensityAltitude: 2500},
+		{Time: june, Scenario: `club "A"`, Airport: "TST", Runway: "18", RunwayLength: 3000, TakeoffDistance: 1500, Margin: 1500, DensityAltitude: 1800},
+		{Time: june, Scenario: "solo", Airport: "TST", Runway: "36", RunwayLength: 3000, TakeoffDistance: 1800, Margin: 1200, DensityAltitude: 1900},
+	}
+	var buf bytes.Buffer
+	if err := WriteOpenMetrics(&buf, samples); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE otto_takeoff_margin_feet gauge\n# UNIT otto_takeoff_margin_feet feet\n",
+		`otto_takeoff_margin_feet{scenario="club \"A\"",airport="TST",runway="18"} 1500 1780315200` + "\n" +
+			`otto_takeoff_margin_feet{scenario="solo",airport="TST",runway="36"} 1200 1780315200` + "\n" +
+			`otto_takeoff_margin_feet{scenario="solo",airport="TST",runway="36"} 1000 1780401600` + "\n",
+		`otto_takeoff_margin_ratio{scenario="club \"A\"",airport="TST",runway="18"} 0.5 1780315200`,
+		`otto_density_altitude_feet{scenario="solo",airport="TST",runway="36"} 2500 1780401600`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "\n# EOF\n") {
+		t.Errorf("Expected the output to end with # EOF, got\n%s", out)
+	}
+}
@@ -0,0 +1,309 @@
+// Package mission matches a planned flight against a fleet: which
+// airframes can fly the route with the people and baggage, within their
+// weight and balance limits, with the fuel reserve and inside the runway
+// and crosswind limits at departure, and with what margins. It answers the
+// question club scheduling software asks before offering a booking.
+//
+// The departure is checked in the current weather at the departure
+// airport; the landing distance at the destination is not checked.
+package mission
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/flightplan"
+	"github.com/ryanbmilbourne/otto-perf/geo"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// Defaults for the optional fields of a mission
+const (
+	DefaultPower   = 65 // Cruise power in percent
+	DefaultReserve = 30 // Fuel reserve in minutes, the day VFR minimum
+)
+
+// Mission is a flight a member wants to book
+type Mission struct {
+	Route     []string  `json:"route"`             // Airport identifiers, departure first and destination last
+	People    []float64 `json:"people,omitempty"`  // Occupant weights in pounds in seat order
+	Baggage   float64   `json:"baggage,omitempty"` // in pounds
+	Departure time.Time `json:"departure"`         // Planned departure time
+
+	Runway   string  `json:"runway,omitempty"`   // Departure runway end (default: the end with the most headwind)
+	Factor   float64 `json:"factor,omitempty"`   // Safety factor applied to the takeoff distance (default: 1)
+	Altitude float64 `json:"altitude,omitempty"` // Cruise pressure altitude in feet (default: the fastest VFR altitude)
+	Power    float64 `json:"power,omitempty"`    // Cruise power in percent (default: DefaultPower)
+	Reserve  float64 `json:"reserve,omitempty"`  // Fuel reserve in minutes at cruise (default: DefaultReserve)
+	Winds    string  `json:"winds,omitempty"`    // Forecast winds aloft, e.g. "3000:2710,6000:2815+05"
+}
+
+// Candidate is one airframe's answer for a mission. The fuel, weight and
+// takeoff figures are for the least fuel that covers the trip and reserve;
+// they are left zero as far as the airframe could not be evaluated.
+type Candidate struct {
+	Tail     string   `json:"tail"`
+	Aircraft string   `json:"aircraft"` // Profile ID
+	Feasible bool     `json:"feasible"`
+	Reasons  []string `json:"reasons,omitempty"` // Why the airframe cannot fly the mission
+
+	Altitude float64   `json:"altitude,omitempty"`      // Cruise pressure altitude in feet
+	TripTime float64   `json:"trip_minutes,omitempty"`  // Minutes en route, including the climb
+	TripFuel float64   `json:"trip_fuel_gal,omitempty"` // US gallons en route, including the climb
+	Arrival  time.Time `json:"arrival"`                 // Departure plus the trip time, to the minute
+
+	Fuel       float64 `json:"fuel_gal,omitempty"`            // Least fuel to load: taxi, trip and reserve
+	MaxFuel    float64 `json:"max_fuel_gal,omitempty"`        // Most fuel that fits with the people and baggage
+	FuelMargin float64 `json:"fuel_margin_minutes,omitempty"` // Endurance beyond the reserve with MaxFuel
+
+	TakeoffWeight   float64 `json:"takeoff_weight,omitempty"`   // in pounds
+	MaxWeight       float64 `json:"max_weight,omitempty"`       // Lower of the structural and runway limited takeoff weight
+	TakeoffDistance float64 `json:"takeoff_distance,omitempty"` // Over a 50 ft obstacle, in feet, with the safety factor
+	RunwayMargin    float64 `json:"runway_margin,omitempty"`    // Fraction of the takeoff distance available left over
+
+	Crosswind    float64 `json:"crosswind"`     // Departure crosswind at the gust speed, in knots
+	MaxCrosswind float64 `json:"max_crosswind"` // Maximum demonstrated crosswind, 0 if unknown
+}
+
+// departure is a departure resolved to a runway end and chart inputs
+type departure struct {
+	runway    *airports.Runway
+	end       *airports.RunwayEnd
+	params    performance.TakeoffParams
+	crosswind float64
+}
+
+// ResolveRoute finds the airports of a mission's route
+func ResolveRoute(ctx context.Context, provider airports.Provider, m Mission) ([]*airports.Airport, error) {
+	if len(m.Route) < 2 {
+		return nil, errors.New("the route needs a departure and a destination")
+	}
+	route := make([]*airports.Airport, len(m.Route))
+	for i, id := range m.Route {
+		a, err := airports.Resolve(ctx, provider, id)
+		if err != nil {
+			return nil, err
+		}
+		route[i] = a
+	}
+	return route, nil
+}
+
+// Match evaluates a mission for every airframe of a fleet, flying the
+// route's airports in order with obs the weather at the first. Feasible
+// airframes come first, those with the most runway margin first among
+// them. It returns an error when the mission itself cannot be evaluated:
+// a route of fewer than two airports, a METAR without a temperature,
+// unreadable winds aloft or no usable departure runway.
+func Match(fleet []aircraft.Tail, m Mission, route []*airports.Airport, obs *weather.Observation) ([]Candidate, error) {
+	if len(route) < 2 {
+		return nil, errors.New("the route needs a departure and a destination")
+	}
+	if m.Power == 0 {
+		m.Power = DefaultPower
+	}
+	if m.Reserve == 0 {
+		m.Reserve = DefaultReserve
+	}
+	if m.Factor == 0 {
+		m.Factor = 1
+	}
+	if m.Factor < 1 {
+		return nil, errors.New("the safety factor must be at least 1")
+	}
+	var winds flightplan.WindsAloft
+	if m.Winds != "" {
+		var err error
+		if winds, err = flightplan.ParseWindsAloft(m.Winds); err != nil {
+			return nil, err
+		}
+	}
+	d, err := resolveDeparture(route[0], obs, m.Runway)
+	if err != nil {
+		return nil, err
+	}
+
+	query := flightplan.AltitudeQuery{
+		DepartureAltitude:    d.params.PressureAltitude,
+		DepartureTemperature: d.params.Temperature,
+		Course:               geo.InitialCourse(route[0].Position(), route[len(route)-1].Position()),
+		Variation:            route[0].MagneticVariation,
+		Power:                m.Power,
+		Winds:                winds,
+	}
+	for i := 1; i < len(route); i++ {
+		query.Distance += geo.Distance(route[i-1].Position(), route[i].Position())
+	}
+	if m.Altitude > 0 {
+		query.Altitudes = []float64{m.Altitude}
+	}
+	available := d.runway.Length / m.Factor
+
+	candidates := make([]Candidate, len(fleet))
+	for i, tail := range fleet {
+		candidates[i] = evaluate(tail, m, query, d, available)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Feasible != b.Feasible {
+			return a.Feasible
+		}
+		return a.RunwayMargin > b.RunwayMargin
+	})
+	return candidates, nil
+}
+
+// evaluate checks a mission for one airframe, collecting every reason it
+// cannot be flown
+func evaluate(tail aircraft.Tail, m Mission, query flightplan.AltitudeQuery, d *departure, available float64) Candidate {
+	weights := tail.WeightBalance
+	c := Candidate{
+		Tail:         tail.Registration,
+		Aircraft:     tail.Profile.ID,
+		Crosswind:    d.crosswind,
+		MaxCrosswind: tail.Profile.Limits.MaxDemonstratedCrosswind,
+	}
+	if c.MaxCrosswind > 0 && c.Crosswind > c.MaxCrosswind {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("the %.0f kt crosswind on runway %s exceeds the %.0f kt demonstrated",
+			c.Crosswind, d.end.ID, c.MaxCrosswind))
+	}
+
+	options, err := flightplan.OptimumAltitude(tail.Profile.NewClimbCalculator(), tail.Profile.NewCruiseCalculator(), query)
+	if err != nil {
+		c.Reasons = append(c.Reasons, err.Error())
+		return c
+	}
+	trip := options[0]
+	c.Altitude, c.TripTime, c.TripFuel = trip.Altitude, trip.Time, trip.Fuel
+	c.Arrival = m.Departure.Add(time.Duration(trip.Time * float64(time.Minute))).Round(time.Minute)
+	fuelFlow := (trip.Fuel - trip.ClimbFuel) / (trip.Time - trip.ClimbTime) * 60
+	c.Fuel = math.Ceil((weights.TaxiFuel+trip.Fuel+fuelFlow*m.Reserve/60)*10) / 10
+
+	calculator := tail.Profile.NewTakeoffCalculator()
+	c.MaxWeight = weights.MaxTakeoffWeight()
+	if limit, err := calculator.MaxWeight(d.params, available); err != nil {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("no takeoff weight fits runway %s: %v", d.end.ID, err))
+		return c
+	} else if c.MaxWeight == 0 || limit.Value < c.MaxWeight {
+		c.MaxWeight = limit.Value
+	}
+
+	c.MaxFuel, err = weights.MaxFuel(m.People, m.Baggage, c.MaxWeight)
+	var violations wb.Violations
+	switch {
+	case errors.As(err, &violations):
+		for _, v := range violations {
+			c.Reasons = append(c.Reasons, v.Message)
+		}
+		return c
+	case err != nil:
+		c.Reasons = append(c.Reasons, err.Error())
+		return c
+	case c.Fuel > weights.FuelCapacity:
+		c.Reasons = append(c.Reasons, fmt.Sprintf("the trip and reserve need %.1f gal, more than the %.0f gal capacity",
+			c.Fuel, weights.FuelCapacity))
+	case c.Fuel > c.MaxFuel:
+		c.Reasons = append(c.Reasons, fmt.Sprintf("the trip and reserve need %.1f gal, but only %.1f gal fit with the people and baggage",
+			c.Fuel, c.MaxFuel))
+	}
+	c.FuelMargin = (c.MaxFuel - c.Fuel) / fuelFlow * 60
+
+	// The planned loading carries the least fuel that will do, or the most
+	// that fits when that is short
+	summary, err := weights.Compute(wb.Loading{FuelGallons: math.Min(c.Fuel, c.MaxFuel), People: m.People, Baggage: m.Baggage})
+	if err != nil {
+		c.Reasons = append(c.Reasons, err.Error())
+		return c
+	}
+	c.TakeoffWeight = summary.TakeoffWeight
+	params := d.params
+	params.Weight = c.TakeoffWeight
+	takeoff, err := calculator.CalculateTakeoff(params)
+	if err != nil {
+		c.Reasons = append(c.Reasons, err.Error())
+		return c
+	}
+	c.TakeoffDistance = takeoff.TakeoffDistance * m.Factor
+	c.RunwayMargin = 1 - c.TakeoffDistance/d.runway.Length
+
+	c.Feasible = len(c.Reasons) == 0
+	return c
+}
+
+// resolveDeparture finds the departure runway end and the chart inputs and
+// crosswind for it in the departure weather
+func resolveDeparture(airport *airports.Airport, obs *weather.Observation, runwayID string) (*departure, error) {
+	if !obs.HasTemperature {
+		return nil, fmt.Errorf("METAR %s has no temperature", obs.Station)
+	}
+	rwy, end, err := departureRunway(airport, runwayID, obs)
+	if err != nil {
+		return nil, err
+	}
+
+	components := wind.Decompose(obs.Wind, wind.TrueDirection(end.TrueHeading), 0)
+	if obs.Variable {
+		// No headwind credit and the full speed across the runway
+		components = wind.Components{Crosswind: obs.Wind.Speed}
+	}
+	crosswind := math.Abs(components.Crosswind)
+	if obs.Wind.Gust > obs.Wind.Speed && obs.Wind.Speed > 0 {
+		crosswind *= obs.Wind.Gust / obs.Wind.Speed
+	}
+	pressureAlt := airport.Elevation
+	if obs.Altimeter > 0 {
+		pressureAlt = atmosphere.PressureAltitude(airport.Elevation, obs.Altimeter)
+	}
+	return &departure{
+		runway: rwy,
+		end:    end,
+		params: performance.TakeoffParams{
+			PressureAltitude: pressureAlt,
+			Temperature:      obs.Temperature,
+			WindComponent:    components.Headwind,
+		},
+		crosswind: crosswind,
+	}, nil
+}
+
+// departureRunway finds the given runway end, or the end with the most headwind
+func departureRunway(airport *airports.Airport, runwayID string, obs *weather.Observation) (*airports.Runway, *airports.RunwayEnd, error) {
+	if runwayID != "" {
+		rwy, end, err := airport.Runway(runwayID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if end == nil {
+			return nil, nil, fmt.Errorf("specify a single runway end (e.g. 17), not %s", runwayID)
+		}
+		return rwy, end, nil
+	}
+
+	var bestRunway *airports.Runway
+	var bestEnd *airports.RunwayEnd
+	best := 0.0
+	for i := range airport.Runways {
+		rwy := &airport.Runways[i]
+		for j := range rwy.Ends {
+			headwind := wind.Decompose(obs.Wind, wind.TrueDirection(rwy.Ends[j].TrueHeading), 0).Headwind
+			// Prefer the longer runway when the wind favours neither
+			if bestEnd == nil || headwind > best+0.5 || (headwind > best-0.5 && rwy.Length > bestRunway.Length) {
+				bestRunway, bestEnd, best = rwy, &rwy.Ends[j], headwind
+			}
+		}
+	}
+	if bestEnd == nil {
+		return nil, nil, fmt.Errorf("no runways known at %s", airport.Ident)
+	}
+	return bestRunway, bestEnd, nil
+}
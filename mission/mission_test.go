@@ -0,0 +1,148 @@
+package mission
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// testRoute is a 60 nm flight from a 3000 ft north-south runway
+var testRoute = []*airports.Airport{
+	{
+		Ident: "DEP", Latitude: 39.0, Longitude: -77.5, Elevation: 400,
+		Runways: []airports.Runway{{ID: "18/36", Length: 3000, Ends: []airports.RunwayEnd{
+			{ID: "18", TrueHeading: 180},
+			{ID: "36", TrueHeading: 360},
+		}}},
+	},
+	{Ident: "ARR", Latitude: 40.0, Longitude: -77.5, Elevation: 500},
+}
+
+func testFleet(t *testing.T, emptyWeights ...float64) []aircraft.Tail {
+	profile, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fleet []aircraft.Tail
+	for i, w := range emptyWeights {
+		tail := aircraft.Tail{Registration: "N10" + string(rune('1'+i)), Profile: profile, WeightBalance: profile.WeightBalance}
+		tail.WeightBalance.EmptyWeight = w
+		fleet = append(fleet, tail)
+	}
+	return fleet
+}
+
+func testMETAR(t *testing.T, raw string) *weather.Observation {
+	obs, err := weather.ParseMETAR(raw, time.Date(2024, 6, 1, 18, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return obs
+}
+
+func TestMatch(t *testing.T) {
+	departure := time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)
+	calm := testMETAR(t, "METAR DEP 011753Z 36005KT 10SM CLR 20/10 A2992")
+
+	testCases := []struct {
+		name     string
+		fleet    []aircraft.Tail
+		mission  Mission
+		obs      *weather.Observation
+		feasible []string // Tails expected feasible, in order
+		reason   string   // Expected in the reasons of the first infeasible tail
+	}{
+		{
+			name:     "Both Fit",
+			fleet:    testFleet(t, 1500, 1450),
+			mission:  Mission{People: []float64{170, 160}, Baggage: 20},
+			obs:      calm,
+			feasible: []string{"N102", "N101"},
+		},
+		{
+			name:     "Heavy Airframe Short Of Fuel",
+			fleet:    testFleet(t, 1620, 1450),
+			mission:  Mission{People: []float64{170, 170, 170, 170}},
+			obs:      calm,
+			feasible: []string{"N102"},
+			reason:   "only",
+		},
+		{
+			name:    "Beyond Capacity",
+			fleet:   testFleet(t, 1450),
+			mission: Mission{People: []float64{170}, Reserve: 400},
+			obs:     calm,
+			reason:  "capacity",
+		},
+		{
+			name:    "Crosswind",
+			fleet:   testFleet(t, 1450),
+			mission: Mission{People: []float64{170}},
+			obs:     testMETAR(t, "METAR DEP 011753Z 27020G25KT 10SM CLR 20/10 A2992"),
+			reason:  "crosswind",
+		},
+		{
+			name:    "Seats",
+			fleet:   testFleet(t, 1450),
+			mission: Mission{People: []float64{100, 100, 100, 100, 100}},
+			obs:     calm,
+			reason:  "seats",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.mission.Departure = departure
+			candidates, err := Match(tc.fleet, tc.mission, testRoute, tc.obs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(candidates) != len(tc.fleet) {
+				t.Fatalf("Got %d candidates, expected %d", len(candidates), len(tc.fleet))
+			}
+
+			for i, c := range candidates {
+				if i < len(tc.feasible) {
+					if !c.Feasible || c.Tail != tc.feasible[i] {
+						t.Errorf("Candidate %d: got %s feasible %v, expected %s feasible (%v)", i, c.Tail, c.Feasible, tc.feasible[i], c.Reasons)
+					}
+					if c.Fuel > c.MaxFuel || c.RunwayMargin <= 0 || c.FuelMargin < 0 {
+						t.Errorf("%s: feasible with fuel %.1f of %.1f gal, runway margin %.2f", c.Tail, c.Fuel, c.MaxFuel, c.RunwayMargin)
+					}
+					if !c.Arrival.After(departure) {
+						t.Errorf("%s: arrival %v is not after the departure", c.Tail, c.Arrival)
+					}
+					continue
+				}
+				if c.Feasible {
+					t.Errorf("%s: got feasible, expected not", c.Tail)
+				}
+				if i == len(tc.feasible) && !strings.Contains(strings.Join(c.Reasons, "; "), tc.reason) {
+					t.Errorf("%s: reasons %q do not mention %q", c.Tail, c.Reasons, tc.reason)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchErrors(t *testing.T) {
+	fleet := testFleet(t, 1450)
+	calm := testMETAR(t, "METAR DEP 011753Z 36005KT 10SM CLR 20/10 A2992")
+
+	if _, err := Match(fleet, Mission{}, testRoute[:1], calm); err == nil {
+		t.Errorf("Expected an error for a route without a destination")
+	}
+	if _, err := Match(fleet, Mission{Winds: "3000"}, testRoute, calm); err == nil {
+		t.Errorf("Expected an error for unreadable winds aloft")
+	}
+	if _, err := Match(fleet, Mission{Runway: "9"}, testRoute, calm); err == nil {
+		t.Errorf("Expected an error for an unknown runway")
+	}
+	if _, err := Match(fleet, Mission{}, testRoute, testMETAR(t, "METAR DEP 011753Z 36005KT 10SM CLR A2992")); err == nil {
+		t.Errorf("Expected an error for a METAR without a temperature")
+	}
+}
@@ -0,0 +1,220 @@
+// Package netconfig configures the network integrations (base URLs, API
+// keys, timeouts and proxies) from a JSON config file and the environment
+package netconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/trace"
+)
+
+// DefaultTimeout applies when neither the config nor the environment sets one
+const DefaultTimeout = 15 * time.Second
+
+// Duration is a time.Duration that reads from JSON strings such as "20s"
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"20s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Endpoint configures a single network integration
+type Endpoint struct {
+	BaseURL      string   `json:"base_url,omitempty"`
+	APIKey       string   `json:"api_key,omitempty"`
+	APIKeyHeader string   `json:"api_key_header,omitempty"` // Header carrying APIKey (default X-API-Key)
+	Timeout      Duration `json:"timeout,omitempty"`
+	Proxy        string   `json:"proxy,omitempty"` // Overrides the global proxy for this endpoint
+}
+
+// Config holds the settings for all network integrations
+type Config struct {
+	Proxy     string              `json:"proxy,omitempty"` // Proxy URL; the standard HTTPS_PROXY/NO_PROXY variables apply when empty
+	Timeout   Duration            `json:"timeout,omitempty"`
+	Endpoints map[string]Endpoint `json:"endpoints,omitempty"` // Keyed by integration name: "weather", "airports" or "webhooks"
+}
+
+// DefaultPath returns the per-user config file location
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "otto", "network.json"), nil
+}
+
+// Load reads the config file at path (or the default location when path is
+// empty) and applies environment overrides. A missing default file is not
+// an error.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			path = ""
+		}
+	}
+
+	cfg := &Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", path, err)
+			}
+		case explicit || !errors.Is(err, os.ErrNotExist):
+			return nil, err
+		}
+	}
+
+	if err := cfg.applyEnv(os.Getenv); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides settings from environment variables:
+//
+//	OTTO_HTTP_PROXY, OTTO_HTTP_TIMEOUT
+//	OTTO_<NAME>_URL, OTTO_<NAME>_API_KEY, OTTO_<NAME>_API_KEY_HEADER,
+//	OTTO_<NAME>_TIMEOUT, OTTO_<NAME>_PROXY
+//
+// where <NAME> is the upper-cased integration name, e.g. OTTO_WEATHER_URL
+func (c *Config) applyEnv(getenv func(string) string) error {
+	if v := getenv("OTTO_HTTP_PROXY"); v != "" {
+		c.Proxy = v
+	}
+	if v := getenv("OTTO_HTTP_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("OTTO_HTTP_TIMEOUT: %w", err)
+		}
+		c.Timeout = Duration(d)
+	}
+
+	for _, name := range knownEndpoints {
+		e := c.Endpoints[name]
+		prefix := "OTTO_" + strings.ToUpper(name) + "_"
+		if v := getenv(prefix + "URL"); v != "" {
+			e.BaseURL = v
+		}
+		if v := getenv(prefix + "API_KEY"); v != "" {
+			e.APIKey = v
+		}
+		if v := getenv(prefix + "API_KEY_HEADER"); v != "" {
+			e.APIKeyHeader = v
+		}
+		if v := getenv(prefix + "PROXY"); v != "" {
+			e.Proxy = v
+		}
+		if v := getenv(prefix + "TIMEOUT"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%sTIMEOUT: %w", prefix, err)
+			}
+			e.Timeout = Duration(d)
+		}
+		if e != (Endpoint{}) {
+			if c.Endpoints == nil {
+				c.Endpoints = make(map[string]Endpoint)
+			}
+			c.Endpoints[name] = e
+		}
+	}
+	return nil
+}
+
+// knownEndpoints lists the integrations that read environment overrides:
+// the weather reports, the airport lookups for airports missing from the
+// local data, and the callbacks otto serve posts webhook briefings to
+var knownEndpoints = []string{"weather", "airports", "webhooks"}
+
+// Endpoint returns the settings for a named integration
+func (c *Config) Endpoint(name string) Endpoint {
+	return c.Endpoints[name]
+}
+
+// BaseURL returns the configured base URL for an integration, or def if none is set
+func (c *Config) BaseURL(name, def string) string {
+	if u := c.Endpoint(name).BaseURL; u != "" {
+		return u
+	}
+	return def
+}
+
+// HTTPClient builds an HTTP client for a named integration with its
+// timeout, proxy and API key applied
+func (c *Config) HTTPClient(name string) (*http.Client, error) {
+	e := c.Endpoint(name)
+
+	timeout := time.Duration(e.Timeout)
+	if timeout == 0 {
+		timeout = time.Duration(c.Timeout)
+	}
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	proxy := e.Proxy
+	if proxy == "" {
+		proxy = c.Proxy
+	}
+	if proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL for %s: %w", name, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	// Requests carry the trace of the operation making them
+	var rt http.RoundTripper = &trace.Transport{Base: transport}
+	if e.APIKey != "" {
+		header := e.APIKeyHeader
+		if header == "" {
+			header = "X-API-Key"
+		}
+		rt = &apiKeyTransport{next: rt, header: header, key: e.APIKey}
+	}
+
+	return &http.Client{Timeout: timeout, Transport: rt}, nil
+}
+
+// apiKeyTransport adds an API key header to every request
+type apiKeyTransport struct {
+	next   http.RoundTripper
+	header string
+	key    string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(t.header, t.key)
+	return t.next.RoundTrip(req)
+}
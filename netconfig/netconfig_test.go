@@ -0,0 +1,111 @@
+package netconfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFileAndEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "network.json")
+	contents := `{
+		"proxy": "http://proxy.example.com:3128",
+		"timeout": "30s",
+		"endpoints": {
+			"weather": {"base_url": "https://wx.example.com/api", "api_key": "file-key"}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("OTTO_WEATHER_API_KEY", "env-key")
+	t.Setenv("OTTO_WEATHER_TIMEOUT", "5s")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Error loading config: %v", err)
+	}
+
+	if cfg.Proxy != "http://proxy.example.com:3128" || time.Duration(cfg.Timeout) != 30*time.Second {
+		t.Errorf("Global settings not loaded: %+v", cfg)
+	}
+
+	weather := cfg.Endpoint("weather")
+	if weather.BaseURL != "https://wx.example.com/api" {
+		t.Errorf("Base URL: got %q", weather.BaseURL)
+	}
+	if weather.APIKey != "env-key" {
+		t.Errorf("Environment should override file API key, got %q", weather.APIKey)
+	}
+	if time.Duration(weather.Timeout) != 5*time.Second {
+		t.Errorf("Endpoint timeout: got %v", time.Duration(weather.Timeout))
+	}
+
+	if got := cfg.BaseURL("airports", "https://default.example.com"); got != "https://default.example.com" {
+		t.Errorf("Unconfigured endpoint should use default, got %q", got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for explicitly named missing file, but got none")
+	}
+}
+
+func TestHTTPClientAPIKey(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization-Token")
+	}))
+	defer server.Close()
+
+	cfg := &Config{Endpoints: map[string]Endpoint{
+		"weather": {APIKey: "secret", APIKeyHeader: "Authorization-Token", Timeout: Duration(time.Second)},
+	}}
+
+	client, err := cfg.HTTPClient("weather")
+	if err != nil {
+		t.Fatalf("Error building client: %v", err)
+	}
+	if client.Timeout != time.Second {
+		t.Errorf("Timeout: got %v, expected 1s", client.Timeout)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if got != "secret" {
+		t.Errorf("API key header: got %q, expected %q", got, "secret")
+	}
+
+	if _, err := (&Config{Proxy: "://bad"}).HTTPClient("weather"); err == nil {
+		t.Error("Expected error for invalid proxy URL, but got none")
+	}
+}
+
+func TestEndpointEnv(t *testing.T) {
+	t.Setenv("OTTO_AIRPORTS_URL", "https://airports.example.com/api")
+	t.Setenv("OTTO_WEBHOOKS_TIMEOUT", "45s")
+
+	cfg := &Config{}
+	if err := cfg.applyEnv(os.Getenv); err != nil {
+		t.Fatalf("Error applying environment: %v", err)
+	}
+	if got := cfg.BaseURL("airports", "https://default.example.com"); got != "https://airports.example.com/api" {
+		t.Errorf("Airports base URL: got %q", got)
+	}
+	client, err := cfg.HTTPClient("webhooks")
+	if err != nil {
+		t.Fatalf("Error building client: %v", err)
+	}
+	if client.Timeout != 45*time.Second {
+		t.Errorf("Webhooks timeout: got %v, expected 45s", client.Timeout)
+	}
+}
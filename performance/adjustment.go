@@ -0,0 +1,26 @@
+package performance
+
+import "fmt"
+
+// Adjustment is a performance penalty for a non-standard configuration that
+// the charts do not cover, such as missing wheel fairings or skis. Results
+// computed with adjustments list them so the change is never hidden.
+type Adjustment struct {
+	Description string  `json:"description"` // The configuration, e.g. "Wheel fairings removed"
+	Factor      float64 `json:"factor"`      // Fractional change, e.g. 0.05 for 5% more
+}
+
+// String describes the adjustment as a signed percentage, e.g. "+5% (Skis installed)"
+func (a Adjustment) String() string {
+	return fmt.Sprintf("%+.0f%% (%s)", a.Factor * 100, a.Description)
+}
+
+// adjustmentFactor returns the combined multiplier of a list of adjustments
+func adjustmentFactor(adjustments []Adjustment) float64 {
+	factor := 1.0
+	for _, a := range adjustments {
+		factor *= 1 + a.Factor
+	}
+	return factor
+}
+
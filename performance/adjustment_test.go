@@ -0,0 +1,95 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTakeoffDistanceAdjustment(t *testing.T) {
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 26.7, Weight: 2325, WindComponent: 5}
+	
+	calc := NewTakeoffCalculator()
+	base, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if base.Adjustments != nil {
+		t.Errorf("Expected no adjustments on a standard configuration, got %v", base.Adjustments)
+	}
+	
+	calc.AdjustDistance(Adjustment{Description: "Skis installed", Factor: 0.2})
+	calc.AdjustDistance(Adjustment{Description: "Wheel fairings removed", Factor: 0.05})
+	adjusted, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if want := base.TakeoffDistance * 1.2 * 1.05; math.Abs(adjusted.TakeoffDistance - want) > 0.01 {
+		t.Errorf("Expected adjusted distance %.1f, got %.1f", want, adjusted.TakeoffDistance)
+	}
+	if len(adjusted.Adjustments) != 2 || adjusted.Adjustments[0].Description != "Skis installed" {
+		t.Errorf("Expected both adjustments to be disclosed, got %v", adjusted.Adjustments)
+	}
+	if adjusted.LiftoffSpeed != base.LiftoffSpeed || adjusted.BarrierSpeed != base.BarrierSpeed {
+		t.Errorf("Expected speeds to be unchanged, got %.1f/%.1f", adjusted.LiftoffSpeed, adjusted.BarrierSpeed)
+	}
+	
+	session, err := calc.NewSession(params).Result()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.TakeoffDistance != adjusted.TakeoffDistance || len(session.Adjustments) != 2 {
+		t.Errorf("Expected the session to apply the adjustments, got %+v", session)
+	}
+}
+
+func TestClimbRateAdjustment(t *testing.T) {
+	params := ClimbParams{PressureAltitude: 500, Temperature: 20, CruiseAltitude: 6500}
+	
+	calc := NewClimbCalculator()
+	base, err := calc.CalculateClimb(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	calc.AdjustRateOfClimb(Adjustment{Description: "Floats installed", Factor: -0.2})
+	adjusted, err := calc.CalculateClimb(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if got, want := adjusted.Rows[0].RateOfClimb, base.Rows[0].RateOfClimb * 0.8; math.Abs(got - want) > 0.01 {
+		t.Errorf("Expected adjusted rate of climb %.1f, got %.1f", want, got)
+	}
+	if want := base.Time / 0.8; math.Abs(adjusted.Time - want) > 0.01 {
+		t.Errorf("Expected time to climb %.2f, got %.2f", want, adjusted.Time)
+	}
+	if len(adjusted.Adjustments) != 1 {
+		t.Errorf("Expected the adjustment to be disclosed, got %v", adjusted.Adjustments)
+	}
+}
+
+func TestAdjustmentString(t *testing.T) {
+	if got := (Adjustment{Description: "Skis installed", Factor: 0.2}).String(); got != "+20% (Skis installed)" {
+		t.Errorf("Expected \"+20%% (Skis installed)\", got %q", got)
+	}
+}
+
+func TestAdjustmentsAreNotShared(t *testing.T) {
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 26.7, Weight: 2325}
+	
+	adjusted := NewTakeoffCalculator()
+	standard := NewTakeoffCalculator()
+	if adjusted.takeoffChart != standard.takeoffChart {
+		t.Error("Expected calculators to share one chart")
+	}
+	
+	adjusted.AdjustDistance(Adjustment{Description: "Skis installed", Factor: 0.2})
+	result, err := standard.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Adjustments != nil {
+		t.Errorf("Expected an adjustment to one calculator to leave the other alone, got %v", result.Adjustments)
+	}
+}
@@ -0,0 +1,135 @@
+package performance
+
+import (
+	"fmt"
+)
+
+// Calculator is implemented by every performance module (takeoff, and
+// later landing, climb and cruise) so reports and servers can handle them
+// uniformly
+type Calculator[P, R any] interface {
+	// Calculate computes the result for the given inputs
+	Calculate(params P) (R, error)
+	// Envelope returns the charted range of every input
+	Envelope() Envelope
+	// Explain shows how the result for the given inputs is derived
+	Explain(params P) (*Explanation, error)
+	// Source identifies the POH chart the module digitizes
+	Source() Source
+}
+
+// Limit is the charted range of one input
+type Limit struct {
+	Field string  `json:"field"`
+	Unit  string  `json:"unit"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// Envelope lists the charted range of every input
+type Envelope []Limit
+
+// Source identifies the POH chart behind a calculation
+type Source struct {
+	Aircraft string `json:"aircraft"`
+	Document string `json:"document"`
+	Figure   string `json:"figure"`
+	Title    string `json:"title"`
+}
+
+// Step is one stage of a calculation, in the order it is applied
+type Step struct {
+	Description string  `json:"description"`
+	Value       float64 `json:"value"`
+	Unit        string  `json:"unit"`
+}
+
+// Explanation shows how a result was derived from the chart
+type Explanation struct {
+	Source Source `json:"source"`
+	Steps  []Step `json:"steps"`
+}
+
+var _ Calculator[TakeoffParams, *TakeoffResult] = (*TakeoffCalculator)(nil)
+
+// Calculate implements Calculator, and is equivalent to CalculateTakeoff
+func (c *TakeoffCalculator) Calculate(params TakeoffParams) (*TakeoffResult, error) {
+	return c.CalculateTakeoff(params)
+}
+
+// Envelope returns the charted range of each takeoff input
+func (c *TakeoffCalculator) Envelope() Envelope {
+	return Envelope{
+		{Field: FieldPressureAltitude, Unit: "ft", Min: c.altitudes[0], Max: c.altitudes[len(c.altitudes)-1]},
+		{Field: FieldTemperature, Unit: "°C", Min: c.temperatures[0], Max: c.temperatures[len(c.temperatures)-1]},
+		{Field: FieldWeight, Unit: "lbs", Min: c.weights[0], Max: c.weights[len(c.weights)-1]},
+		{Field: FieldWindComponent, Unit: "kts", Min: -c.tailwinds[len(c.tailwinds)-1], Max: c.headwinds[len(c.headwinds)-1]},
+	}
+}
+
+// Source identifies the takeoff chart
+func (c *TakeoffCalculator) Source() Source {
+	return c.source
+}
+
+// Explain lists the chart lookup, wind correction and speeds behind a takeoff result
+func (c *TakeoffCalculator) Explain(params TakeoffParams) (*Explanation, error) {
+	result, err := c.CalculateTakeoff(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	baseDistance, err := c.calculateBaseDistance(params)
+	if err != nil {
+		return nil, err
+	}
+	windFactor, err := c.applyWindCorrection(1, params.WindComponent)
+	if err != nil {
+		return nil, err
+	}
+	
+	alt := newBracket(c.altitudes, params.PressureAltitude)
+	temp := newBracket(c.temperatures, params.Temperature)
+	weight := newBracket(c.weights, params.Weight)
+	
+	var wind string
+	switch {
+	case params.WindComponent > 0:
+		wind = fmt.Sprintf("%.0f kts headwind", params.WindComponent)
+	case params.WindComponent < 0:
+		wind = fmt.Sprintf("%.0f kts tailwind", -params.WindComponent)
+	default:
+		wind = "no wind"
+	}
+	
+	steps := []Step{
+		{
+			Description: fmt.Sprintf("Zero-wind distance interpolated between altitude %s ft, temperature %s °C, weight %s lbs",
+				describeBracket(c.altitudes, alt), describeBracket(c.temperatures, temp), describeBracket(c.weights, weight)),
+			Value: baseDistance,
+			Unit:  "ft",
+		},
+		{Description: "Wind correction factor for " + wind, Value: windFactor},
+	}
+	if flaps, _ := c.flaps.correction(params.FlapSetting); flaps.distance != 1 {
+		steps = append(steps, Step{Description: "Flap correction factor for " + params.FlapSetting.String(), Value: flaps.distance})
+	}
+	for _, a := range result.Adjustments {
+		steps = append(steps, Step{Description: "Configuration factor for " + a.Description, Value: 1 + a.Factor})
+	}
+	steps = append(steps,
+		Step{Description: "Takeoff distance over 50 ft barrier", Value: result.TakeoffDistance, Unit: "ft"},
+		Step{Description: fmt.Sprintf("Lift-off speed at %.0f lbs", params.Weight), Value: result.LiftoffSpeed, Unit: "KIAS"},
+		Step{Description: fmt.Sprintf("50 ft barrier speed at %.0f lbs", params.Weight), Value: result.BarrierSpeed, Unit: "KIAS"},
+	)
+	
+	return &Explanation{Source: c.Source(), Steps: steps}, nil
+}
+
+// describeBracket formats the chart lines surrounding an input, e.g. "1000-2000"
+func describeBracket(array []float64, b bracket) string {
+	if b.lo == b.hi || b.frac == 0 {
+		return fmt.Sprintf("%g", array[b.lo])
+	}
+	return fmt.Sprintf("%g-%g", array[b.lo], array[b.hi])
+}
@@ -0,0 +1,76 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTakeoffEnvelopeMatchesValidate(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	
+	for _, limit := range calc.Envelope() {
+		params := TakeoffParams{PressureAltitude: 0, Temperature: 15, Weight: 2000}
+		set := func(v float64) {
+			switch limit.Field {
+			case FieldPressureAltitude:
+				params.PressureAltitude = v
+			case FieldTemperature:
+				params.Temperature = v
+			case FieldWeight:
+				params.Weight = v
+			case FieldWindComponent:
+				params.WindComponent = v
+			default:
+				t.Fatalf("Unknown field %q", limit.Field)
+			}
+		}
+		
+		set(limit.Max)
+		if errs := calc.Validate(params); len(errs) > 0 {
+			t.Errorf("%s: maximum %g rejected: %v", limit.Field, limit.Max, errs)
+		}
+		set(limit.Max + 1)
+		if errs := calc.Validate(params); len(errs) != 1 || errs[0].Field != limit.Field {
+			t.Errorf("%s: expected %g to be rejected, got %v", limit.Field, limit.Max+1, errs)
+		}
+	}
+}
+
+func TestTakeoffExplain(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	params := TakeoffParams{
+		PressureAltitude: 1500,
+		Temperature:      ConvertFahrenheitToCelsius(80),
+		Weight:           2325,
+		WindComponent:    15,
+	}
+	
+	result, err := calc.Calculate(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	explanation, err := calc.Explain(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if explanation.Source.Figure != "5-6" {
+		t.Errorf("Expected figure 5-6, got %q", explanation.Source.Figure)
+	}
+	if len(explanation.Steps) != 5 {
+		t.Fatalf("Expected 5 steps, got %d", len(explanation.Steps))
+	}
+	
+	// The base distance times the wind factor must reproduce the result
+	base, factor := explanation.Steps[0].Value, explanation.Steps[1].Value
+	if math.Abs(base*factor-result.TakeoffDistance) > 1e-9 {
+		t.Errorf("Base %.1f × factor %.3f does not give distance %.1f", base, factor, result.TakeoffDistance)
+	}
+	if explanation.Steps[2].Value != result.TakeoffDistance {
+		t.Errorf("Distance step %.1f does not match result %.1f", explanation.Steps[2].Value, result.TakeoffDistance)
+	}
+	
+	if _, err := calc.Explain(TakeoffParams{PressureAltitude: 9000, Temperature: 15, Weight: 2000}); err == nil {
+		t.Error("Expected error for out-of-range input, but got none")
+	}
+}
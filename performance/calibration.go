@@ -0,0 +1,71 @@
+package performance
+
+import (
+	"math"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+)
+
+// AirspeedCalibration is a POH airspeed calibration table for one flap
+// setting: the calibrated airspeed at each indicated airspeed. The
+// difference is the instrument and position error of the airspeed system.
+type AirspeedCalibration struct {
+	Flaps            float64   `json:"flaps"`             // Flap setting in degrees
+	IndicatedSpeeds  []float64 `json:"indicated_speeds"`  // KIAS, ascending
+	CalibratedSpeeds []float64 `json:"calibrated_speeds"` // KCAS at each indicated airspeed, ascending
+}
+
+// Calibrated converts an indicated airspeed in knots to calibrated. Beyond
+// the ends of the table the error at the nearest end is held; a nil table
+// takes the indicator as having no error.
+func (a *AirspeedCalibration) Calibrated(kias float64) float64 {
+	if a == nil || len(a.IndicatedSpeeds) == 0 {
+		return kias
+	}
+	return convertAirspeed(a.IndicatedSpeeds, a.CalibratedSpeeds, kias)
+}
+
+// Indicated converts a calibrated airspeed in knots to the indicated
+// airspeed to fly, the inverse of Calibrated
+func (a *AirspeedCalibration) Indicated(kcas float64) float64 {
+	if a == nil || len(a.CalibratedSpeeds) == 0 {
+		return kcas
+	}
+	return convertAirspeed(a.CalibratedSpeeds, a.IndicatedSpeeds, kcas)
+}
+
+// convertAirspeed interpolates a speed from one column of a calibration
+// table to the other, holding the end differences outside it
+func convertAirspeed(from, to []float64, speed float64) float64 {
+	last := len(from) - 1
+	switch {
+	case speed <= from[0]:
+		return speed + to[0] - from[0]
+	case speed >= from[last]:
+		return speed + to[last] - from[last]
+	}
+	b := newBracket(from, speed)
+	return lerp(to[b.lo], to[b.hi], b.frac)
+}
+
+// AirspeedCalibrations are a profile's calibration tables, one per flap setting
+type AirspeedCalibrations []AirspeedCalibration
+
+// For returns the table for the flap setting nearest the given degrees, or
+// nil when there are none
+func (c AirspeedCalibrations) For(flaps float64) *AirspeedCalibration {
+	var nearest *AirspeedCalibration
+	for i := range c {
+		if nearest == nil || math.Abs(c[i].Flaps - flaps) < math.Abs(nearest.Flaps - flaps) {
+			nearest = &c[i]
+		}
+	}
+	return nearest
+}
+
+// TrueAirspeed converts a calibrated airspeed in knots to true airspeed at
+// a pressure altitude in feet and temperature in °C. Compressibility is
+// neglected, which is well within a knot at light-aircraft speeds.
+func TrueAirspeed(kcas, pressureAltitude, temperature float64) float64 {
+	return kcas / math.Sqrt(atmosphere.DensityRatio(pressureAltitude, temperature))
+}
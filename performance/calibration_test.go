@@ -0,0 +1,59 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAirspeedCalibration(t *testing.T) {
+	tables := AirspeedCalibrations{
+		{Flaps: 0, IndicatedSpeeds: []float64{50, 60, 70}, CalibratedSpeeds: []float64{56, 63, 71}},
+		{Flaps: 40, IndicatedSpeeds: []float64{40, 50}, CalibratedSpeeds: []float64{48, 55}},
+	}
+	clean := tables.For(10)
+	if clean.Flaps != 0 || tables.For(25).Flaps != 40 {
+		t.Fatalf("Expected the nearest flap tables, got %g and %g", clean.Flaps, tables.For(25).Flaps)
+	}
+	
+	tests := []struct {
+		ias, cas float64
+	}{
+		{55, 59.5}, // Interpolated
+		{60, 63},   // On the table
+		{45, 51},   // Below the table, holding the 6 kt error
+		{80, 81},   // Above the table, holding the 1 kt error
+	}
+	for _, tt := range tests {
+		if got := clean.Calibrated(tt.ias); math.Abs(got - tt.cas) > 1e-9 {
+			t.Errorf("%g KIAS: expected %g KCAS, got %g", tt.ias, tt.cas, got)
+		}
+		if got := clean.Indicated(tt.cas); math.Abs(got - tt.ias) > 1e-9 {
+			t.Errorf("%g KCAS: expected %g KIAS, got %g", tt.cas, tt.ias, got)
+		}
+	}
+	
+	var none *AirspeedCalibration
+	if none.Calibrated(60) != 60 || none.Indicated(60) != 60 || AirspeedCalibrations(nil).For(0) != nil {
+		t.Error("Expected no table to take the indicator as exact")
+	}
+	
+	if got := TrueAirspeed(100, 0, 15); math.Abs(got - 100) > 0.01 {
+		t.Errorf("Expected TAS equal to CAS at sea level standard, got %.2f", got)
+	}
+	if got := TrueAirspeed(100, 8000, 0); got < 110 || got > 115 {
+		t.Errorf("Expected about 112 KTAS at 8000 ft, got %.1f", got)
+	}
+}
+
+func TestCalibratedClimbSpeed(t *testing.T) {
+	params := ClimbParams{PressureAltitude: 0, Temperature: 15, CruiseAltitude: 1000}
+	c := NewClimbCalculator()
+	c.CalibrateAirspeed(&AirspeedCalibration{IndicatedSpeeds: []float64{70, 90}, CalibratedSpeeds: []float64{72, 92}})
+	result, err := c.CalculateClimb(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Rows[0].TrueSpeed; math.Abs(got - 81) > 0.1 {
+		t.Errorf("Expected the 79 KIAS climb at 81 KTAS at sea level with a 2 kt error, got %.1f", got)
+	}
+}
@@ -0,0 +1,238 @@
+package performance
+
+import (
+	"errors"
+	"fmt"
+	
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// ChartUnits declares the units a POH prints on a chart's axes. Empty
+// fields are the US units the calculators work in: feet, pounds and knots.
+type ChartUnits struct {
+	Altitude string `json:"altitude"` // "ft" or "m"
+	Weight   string `json:"weight"`   // "lbs" or "kg"
+	Distance string `json:"distance"` // "ft" or "m"
+	Speed    string `json:"speed"`    // Indicated airspeed: "kts" or "km/h"
+	Wind     string `json:"wind"`     // "kts", "km/h" or "m/s"
+}
+
+// TakeoffChart is a takeoff distance chart as digitized from a POH, in the
+// units the POH prints, so a chart with kilogram and meter axes can be
+// entered verbatim. NewChartTakeoffCalculator converts it once, when the
+// calculator is built; every calculation is in feet, pounds and knots.
+type TakeoffChart struct {
+	Source Source     `json:"source"`
+	Units  ChartUnits `json:"units"`
+	
+	Altitudes    []float64 `json:"altitudes"`    // Pressure altitude lines, ascending
+	Temperatures []float64 `json:"temperatures"` // °C, ascending
+	Weights      []float64 `json:"weights"`      // Ascending
+	
+	// Distances are the zero-wind distances over a 50 ft barrier by
+	// altitude, then weight, then temperature: Distances[a][w*len(Temperatures)+t]
+	Distances [][]float64 `json:"distances"`
+	
+	// The wind lines, starting at 0, and the factor each applies to the
+	// zero-wind distance
+	Headwinds       []float64 `json:"headwinds"`
+	HeadwindFactors []float64 `json:"headwind_factors"`
+	Tailwinds       []float64 `json:"tailwinds"`
+	TailwindFactors []float64 `json:"tailwind_factors"`
+	
+	// Speeds by weight
+	LiftoffSpeeds []float64 `json:"liftoff_speeds"`
+	BarrierSpeeds []float64 `json:"barrier_speeds"`
+}
+
+// Conversions from each supported chart unit to the calculator's unit
+var (
+	lengthUnits = map[string]func(float64) float64{"": same, "ft": same, "m": units.MetersToFeet}
+	weightUnits = map[string]func(float64) float64{"": same, "lbs": same, "kg": units.KilogramsToPounds}
+	speedUnits  = map[string]func(float64) float64{"": same, "kts": same, "km/h": units.KilometersToNautical}
+	windUnits   = map[string]func(float64) float64{"": same, "kts": same, "km/h": units.KilometersToNautical, "m/s": units.MetersPerSecondToKnots}
+)
+
+func same(v float64) float64 { return v }
+
+// NewChartTakeoffCalculator creates a takeoff calculator for a digitized
+// chart, converting its axes to feet, pounds and knots
+func NewChartTakeoffCalculator(chart *TakeoffChart) (*TakeoffCalculator, error) {
+	altitude, err := chartUnit(lengthUnits, "altitude", chart.Units.Altitude)
+	if err != nil {
+		return nil, err
+	}
+	weight, err := chartUnit(weightUnits, "weight", chart.Units.Weight)
+	if err != nil {
+		return nil, err
+	}
+	distance, err := chartUnit(lengthUnits, "distance", chart.Units.Distance)
+	if err != nil {
+		return nil, err
+	}
+	speed, err := chartUnit(speedUnits, "speed", chart.Units.Speed)
+	if err != nil {
+		return nil, err
+	}
+	windSpeed, err := chartUnit(windUnits, "wind", chart.Units.Wind)
+	if err != nil {
+		return nil, err
+	}
+	if err := chart.validate(); err != nil {
+		return nil, err
+	}
+	
+	c := &takeoffChart{
+		source:          chart.Source,
+		altitudes:       convert(chart.Altitudes, altitude),
+		temperatures:    convert(chart.Temperatures, same),
+		weights:         convert(chart.Weights, weight),
+		headwinds:       convert(chart.Headwinds, windSpeed),
+		tailwinds:       convert(chart.Tailwinds, windSpeed),
+		headwindFactors: convert(chart.HeadwindFactors, same),
+		tailwindFactors: convert(chart.TailwindFactors, same),
+		speedsLiftoff:   convert(chart.LiftoffSpeeds, speed),
+		speedsBarrier:   convert(chart.BarrierSpeeds, speed),
+	}
+	for _, row := range chart.Distances {
+		c.baseDistances = append(c.baseDistances, convert(row, distance))
+	}
+	return &TakeoffCalculator{takeoffChart: c}, nil
+}
+
+// WithGroundRollChart returns a copy of the calculator that reads the
+// ground roll from a digitized ground roll chart, such as the one Stitch
+// assembles. The chart must have the calculator's altitude, temperature
+// and weight lines. Its wind lines and speeds are not used: the distance
+// chart's wind lines correct the ground roll too.
+func (c *TakeoffCalculator) WithGroundRollChart(roll *TakeoffChart) (*TakeoffCalculator, error) {
+	altitude, err := chartUnit(lengthUnits, "altitude", roll.Units.Altitude)
+	if err != nil {
+		return nil, err
+	}
+	weight, err := chartUnit(weightUnits, "weight", roll.Units.Weight)
+	if err != nil {
+		return nil, err
+	}
+	distance, err := chartUnit(lengthUnits, "distance", roll.Units.Distance)
+	if err != nil {
+		return nil, err
+	}
+	if err := roll.validate(); err != nil {
+		return nil, fmt.Errorf("ground roll %w", err)
+	}
+	if !sameLines(convert(roll.Altitudes, altitude), c.altitudes) || !sameLines(roll.Temperatures, c.temperatures) ||
+		!sameLines(convert(roll.Weights, weight), c.weights) {
+		return nil, errors.New("ground roll chart lines differ from the distance chart's")
+	}
+	
+	chart := *c.takeoffChart
+	chart.groundRolls = nil
+	for _, row := range roll.Distances {
+		chart.groundRolls = append(chart.groundRolls, convert(row, distance))
+	}
+	calc := *c
+	calc.takeoffChart = &chart
+	calc.adjustments = c.appliedAdjustments()
+	return &calc, nil
+}
+
+// Chart returns the calculator's chart in feet, pounds and knots
+func (c *TakeoffCalculator) Chart() *TakeoffChart {
+	chart := &TakeoffChart{
+		Source:          c.source,
+		Units:           ChartUnits{Altitude: "ft", Weight: "lbs", Distance: "ft", Speed: "kts", Wind: "kts"},
+		Altitudes:       convert(c.altitudes, same),
+		Temperatures:    convert(c.temperatures, same),
+		Weights:         convert(c.weights, same),
+		Headwinds:       convert(c.headwinds, same),
+		HeadwindFactors: convert(c.headwindFactors, same),
+		Tailwinds:       convert(c.tailwinds, same),
+		TailwindFactors: convert(c.tailwindFactors, same),
+		LiftoffSpeeds:   convert(c.speedsLiftoff, same),
+		BarrierSpeeds:   convert(c.speedsBarrier, same),
+	}
+	for _, row := range c.baseDistances {
+		chart.Distances = append(chart.Distances, convert(row, same))
+	}
+	return chart
+}
+
+// UnmarshalJSON decodes a takeoff chart, rejecting missing or unknown fields
+func (t *TakeoffChart) UnmarshalJSON(data []byte) error {
+	type plain TakeoffChart
+	return decodeStrict(data, (*plain)(t),
+		"source", "altitudes", "temperatures", "weights", "distances",
+		"headwinds", "headwind_factors", "tailwinds", "tailwind_factors",
+		"liftoff_speeds", "barrier_speeds")
+}
+
+// validate checks that the axes ascend and the tables match them
+func (t *TakeoffChart) validate() error {
+	axes := []struct {
+		name   string
+		values []float64
+	}{
+		{"altitudes", t.Altitudes}, {"temperatures", t.Temperatures}, {"weights", t.Weights},
+		{"headwinds", t.Headwinds}, {"tailwinds", t.Tailwinds},
+	}
+	for _, axis := range axes {
+		// Interpolation and its slopes need a line on either side of a value
+		if len(axis.values) < 2 {
+			return fmt.Errorf("chart needs at least two %s", axis.name)
+		}
+		for i := 1; i < len(axis.values); i++ {
+			if axis.values[i] <= axis.values[i-1] {
+				return fmt.Errorf("chart %s must ascend: %g after %g", axis.name, axis.values[i], axis.values[i-1])
+			}
+		}
+	}
+	if t.Headwinds[0] != 0 || t.Tailwinds[0] != 0 {
+		return errors.New("chart headwinds and tailwinds must start at 0")
+	}
+	
+	if len(t.Distances) != len(t.Altitudes) {
+		return fmt.Errorf("chart has %d distance tables for %d altitudes", len(t.Distances), len(t.Altitudes))
+	}
+	for i, row := range t.Distances {
+		if want := len(t.Weights) * len(t.Temperatures); len(row) != want {
+			return fmt.Errorf("chart distances at %g have %d values, not %d weights × %d temperatures",
+				t.Altitudes[i], len(row), len(t.Weights), len(t.Temperatures))
+		}
+	}
+	
+	lengths := []struct {
+		name   string
+		values []float64
+		axis   string
+		want   int
+	}{
+		{"headwind_factors", t.HeadwindFactors, "headwinds", len(t.Headwinds)},
+		{"tailwind_factors", t.TailwindFactors, "tailwinds", len(t.Tailwinds)},
+		{"liftoff_speeds", t.LiftoffSpeeds, "weights", len(t.Weights)},
+		{"barrier_speeds", t.BarrierSpeeds, "weights", len(t.Weights)},
+	}
+	for _, l := range lengths {
+		if len(l.values) != l.want {
+			return fmt.Errorf("chart has %d %s for %d %s", len(l.values), l.name, l.want, l.axis)
+		}
+	}
+	return nil
+}
+
+// chartUnit finds the conversion for a chart unit
+func chartUnit(conversions map[string]func(float64) float64, quantity, unit string) (func(float64) float64, error) {
+	if convert, ok := conversions[unit]; ok {
+		return convert, nil
+	}
+	return nil, fmt.Errorf("unsupported chart %s unit %q", quantity, unit)
+}
+
+// convert returns a copy of values with f applied to each
+func convert(values []float64, f func(float64) float64) []float64 {
+	out := make([]float64, len(values))
+	for i, v := range values {
+		out[i] = f(v)
+	}
+	return out
+}
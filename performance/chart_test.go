@@ -0,0 +1,94 @@
+package performance
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// metricChart returns the PA-28-161 chart as a metric POH would print it
+func metricChart() *TakeoffChart {
+	chart := NewTakeoffCalculator().Chart()
+	chart.Units = ChartUnits{Altitude: "m", Weight: "kg", Distance: "m", Speed: "km/h", Wind: "m/s"}
+	chart.Altitudes = convert(chart.Altitudes, units.FeetToMeters)
+	chart.Weights = convert(chart.Weights, units.PoundsToKilograms)
+	for i, row := range chart.Distances {
+		chart.Distances[i] = convert(row, units.FeetToMeters)
+	}
+	toMetersPerSecond := func(kts float64) float64 { return kts / units.MetersPerSecondToKnots(1) }
+	chart.Headwinds = convert(chart.Headwinds, toMetersPerSecond)
+	chart.Tailwinds = convert(chart.Tailwinds, toMetersPerSecond)
+	chart.LiftoffSpeeds = convert(chart.LiftoffSpeeds, units.NauticalToKilometers)
+	chart.BarrierSpeeds = convert(chart.BarrierSpeeds, units.NauticalToKilometers)
+	return chart
+}
+
+func TestMetricChart(t *testing.T) {
+	data, err := json.Marshal(metricChart())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chart TakeoffChart
+	if err := json.Unmarshal(data, &chart); err != nil {
+		t.Fatal(err)
+	}
+	metric, err := NewChartTakeoffCalculator(&chart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metric.Source() != NewTakeoffCalculator().Source() {
+		t.Errorf("Unexpected source %+v", metric.Source())
+	}
+	
+	// Converted at the boundary, the metric chart gives the same results
+	for _, params := range []TakeoffParams{
+		{PressureAltitude: 0, Temperature: 15, Weight: 2325, WindComponent: 0},
+		{PressureAltitude: 1500, Temperature: 26.7, Weight: 2150, WindComponent: 7},
+		{PressureAltitude: 4200, Temperature: -5, Weight: 1900, WindComponent: -3},
+	} {
+		want, err := NewTakeoffCalculator().CalculateTakeoff(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := metric.CalculateTakeoff(params)
+		if err != nil {
+			t.Fatalf("%+v: %v", params, err)
+		}
+		if math.Abs(got.TakeoffDistance - want.TakeoffDistance) > 0.01 || math.Abs(got.LiftoffSpeed - want.LiftoffSpeed) > 0.01 {
+			t.Errorf("%+v: expected %+v, got %+v", params, want, got)
+		}
+	}
+	if limit := metric.Envelope()[2]; limit.Unit != "lbs" || math.Abs(limit.Max - 2325) > 0.01 {
+		t.Errorf("Expected the envelope in pounds, got %+v", limit)
+	}
+}
+
+func TestChartValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*TakeoffChart)
+		want   string
+	}{
+		{"unit", func(c *TakeoffChart) { c.Units.Weight = "stone" }, "weight unit"},
+		{"order", func(c *TakeoffChart) { c.Weights[1] = c.Weights[0] }, "must ascend"},
+		{"wind", func(c *TakeoffChart) { c.Headwinds = c.Headwinds[1:] }, "start at 0"},
+		{"one line", func(c *TakeoffChart) { c.Headwinds, c.HeadwindFactors = []float64{0}, []float64{1} }, "at least two headwinds"},
+		{"table", func(c *TakeoffChart) { c.Distances[3] = c.Distances[3][1:] }, "distances at"},
+		{"speeds", func(c *TakeoffChart) { c.LiftoffSpeeds = c.LiftoffSpeeds[1:] }, "liftoff_speeds"},
+	}
+	for _, tc := range tests {
+		chart := metricChart()
+		tc.modify(chart)
+		if _, err := NewChartTakeoffCalculator(chart); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected an error with %q, got %v", tc.name, tc.want, err)
+		}
+	}
+	
+	var chart TakeoffChart
+	if err := json.Unmarshal([]byte(`{"altitudes": [0]}`), &chart); err == nil {
+		t.Error("Expected an error for a chart missing fields")
+	}
+}
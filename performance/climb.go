@@ -0,0 +1,285 @@
+package performance
+
+import (
+	"fmt"
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+)
+
+// FieldCruiseAltitude is the field name used in climb validation errors
+const FieldCruiseAltitude = "cruise_altitude"
+
+// climbStep is the altitude increment used to integrate the climb, in feet
+const climbStep = 100
+
+// ServiceCeilingRate is the rate of climb that defines the service ceiling, in fpm
+const ServiceCeilingRate = 100
+
+// climbTableInterval is the altitude interval between rows of the climb table, in feet
+const climbTableInterval = 1000
+
+// ClimbParams represents the input parameters for climb performance calculations
+type ClimbParams struct {
+	PressureAltitude float64 `json:"pressure_altitude"` // Departure pressure altitude in feet
+	Temperature      float64 `json:"temperature_c"`     // Departure temperature in °C
+	CruiseAltitude   float64 `json:"cruise_altitude"`   // Cruise pressure altitude in feet
+}
+
+// ClimbRow is one line of the climb table. Time, fuel and distance are
+// cumulative from the departure altitude.
+type ClimbRow struct {
+	PressureAltitude float64 `json:"pressure_altitude"` // in feet
+	Temperature      float64 `json:"temperature_c"`     // Temperature at this altitude, standard lapse from departure, in °C
+	RateOfClimb      float64 `json:"rate_of_climb"`     // in feet per minute
+	IndicatedSpeed   float64 `json:"indicated_speed"`   // Climb speed in KIAS
+	TrueSpeed        float64 `json:"true_speed"`        // Climb speed in KTAS
+	Time             float64 `json:"time"`              // in minutes
+	Fuel             float64 `json:"fuel"`              // in US gallons
+	Distance         float64 `json:"distance"`          // in nautical miles, no wind
+}
+
+// ClimbResult contains the climb table every 1000 ft and the totals to cruise altitude
+type ClimbResult struct {
+	Time     float64    `json:"time"`     // Minutes to cruise altitude
+	Fuel     float64    `json:"fuel"`     // US gallons to cruise altitude
+	Distance float64    `json:"distance"` // Nautical miles to cruise altitude, no wind
+	Rows     []ClimbRow `json:"rows"`     // Departure, every 1000 ft, and cruise altitude
+	
+	// Adjustments lists the configuration penalties included in every rate of climb
+	Adjustments []Adjustment `json:"adjustments,omitempty"`
+}
+
+// ClimbCalculator handles the PA-28-161 climb performance calculations
+type ClimbCalculator struct {
+	// These arrays define the data points on the chart
+	densityAltitudes []float64 // Density altitude in feet
+	ratesOfClimb     []float64 // Rate of climb in fpm at maximum weight
+	fuelFlows        []float64 // Full throttle fuel flow in gph, leaned above 3000 ft
+	temperatures     []float64 // Charted temperature range in °C
+	climbSpeed       float64   // Climb speed in KIAS
+	
+	adjustments []Adjustment         // Configuration penalties applied to the charted rate of climb
+	calibration *AirspeedCalibration // Flaps-up airspeed calibration; nil takes the indicator as exact
+}
+
+var _ Calculator[ClimbParams, *ClimbResult] = (*ClimbCalculator)(nil)
+
+// NewClimbCalculator creates a new climb performance calculator
+func NewClimbCalculator() *ClimbCalculator {
+	return &ClimbCalculator{
+		// Digitized from the climb performance chart, 2325 lbs, full throttle, flaps up
+		densityAltitudes: []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+		ratesOfClimb:     []float64{710, 600, 490, 380, 270, 160, 50},
+		fuelFlows:        []float64{10.0, 9.5, 8.9, 8.3, 7.6, 6.9, 6.3},
+		temperatures:     []float64{-40, 40},
+		climbSpeed:       79,
+	}
+}
+
+// CalculateClimb builds the climb table from the departure altitude to cruise altitude
+func (c *ClimbCalculator) CalculateClimb(params ClimbParams) (*ClimbResult, error) {
+	if errs := c.Validate(params); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	
+	result := &ClimbResult{}
+	var time, fuel, distance float64
+	
+	altitude := params.PressureAltitude
+	result.Rows = append(result.Rows, c.climbRow(params, altitude, 0, 0, 0))
+	
+	for altitude < params.CruiseAltitude {
+		// Integrate up to the next table row, or to cruise altitude
+		next := math.Min((math.Floor(altitude / climbTableInterval) + 1) * climbTableInterval, params.CruiseAltitude)
+		for altitude < next {
+			step := math.Min(climbStep, next - altitude)
+			mid := c.climbRow(params, altitude + step / 2, 0, 0, 0)
+			
+			minutes := step / mid.RateOfClimb
+			time += minutes
+			fuel += c.fuelFlow(params, altitude + step / 2) * minutes / 60
+			distance += mid.TrueSpeed * minutes / 60
+			altitude += step
+		}
+		result.Rows = append(result.Rows, c.climbRow(params, altitude, time, fuel, distance))
+	}
+	
+	result.Time, result.Fuel, result.Distance = time, fuel, distance
+	if len(c.adjustments) > 0 {
+		result.Adjustments = append([]Adjustment(nil), c.adjustments...)
+	}
+	return result, nil
+}
+
+// AdjustRateOfClimb applies a configuration penalty, such as from floats,
+// to every charted rate of climb. Results list it in their Adjustments.
+func (c *ClimbCalculator) AdjustRateOfClimb(a Adjustment) {
+	c.adjustments = append(c.adjustments, a)
+}
+
+// CalibrateAirspeed sets the flaps-up airspeed calibration table, so the
+// true climb speed includes the airspeed system's error
+func (c *ClimbCalculator) CalibrateAirspeed(a *AirspeedCalibration) {
+	c.calibration = a
+}
+
+// RateOfClimb returns the rate of climb in fpm at a pressure altitude and
+// temperature, from the chart at maximum weight and including any
+// configuration adjustments
+func (c *ClimbCalculator) RateOfClimb(pressureAltitude, temperature float64) float64 {
+	da := atmosphere.DensityAltitude(pressureAltitude, temperature)
+	return interpolate(c.ratesOfClimb, newBracket(c.densityAltitudes, da)) * adjustmentFactor(c.adjustments)
+}
+
+// ServiceCeiling returns the density altitude in feet at which the rate of
+// climb falls to ServiceCeilingRate, extrapolating the last chart segment
+// when the chart ends above it
+func (c *ClimbCalculator) ServiceCeiling() float64 {
+	factor := adjustmentFactor(c.adjustments)
+	if c.ratesOfClimb[0] * factor <= ServiceCeilingRate {
+		return c.densityAltitudes[0]
+	}
+	
+	last := len(c.densityAltitudes) - 1
+	for i := 1; i <= last; i++ {
+		lo, hi := c.ratesOfClimb[i-1] * factor, c.ratesOfClimb[i] * factor
+		if hi > ServiceCeilingRate && i < last {
+			continue
+		}
+		if lo <= hi {
+			return c.densityAltitudes[i]
+		}
+		frac := (lo - ServiceCeilingRate) / (lo - hi)
+		return c.densityAltitudes[i-1] + frac * (c.densityAltitudes[i] - c.densityAltitudes[i-1])
+	}
+	return c.densityAltitudes[last]
+}
+
+// Validate checks the climb inputs against the chart limits and returns all
+// violations found, or nil if the parameters are within the envelope
+func (c *ClimbCalculator) Validate(params ClimbParams) ValidationErrors {
+	var errs ValidationErrors
+	
+	minTemp, maxTemp := c.temperatures[0], c.temperatures[len(c.temperatures)-1]
+	if params.Temperature < minTemp || params.Temperature > maxTemp {
+		errs = append(errs, &ValidationError{
+			Field:   FieldTemperature,
+			Code:    rangeCode(params.Temperature, minTemp),
+			Value:   params.Temperature,
+			Min:     minTemp,
+			Max:     maxTemp,
+			Message: fmt.Sprintf("temperature (%.1f°C) outside chart range (%.1f°C to %.1f°C)", 
+				params.Temperature, minTemp, maxTemp),
+		})
+	}
+	
+	maxDensityAltitude := c.densityAltitudes[len(c.densityAltitudes)-1]
+	if params.CruiseAltitude <= params.PressureAltitude {
+		errs = append(errs, &ValidationError{
+			Field:   FieldCruiseAltitude,
+			Code:    CodeBelowMinimum,
+			Value:   params.CruiseAltitude,
+			Min:     params.PressureAltitude,
+			Max:     maxDensityAltitude,
+			Message: fmt.Sprintf("cruise altitude (%.0f ft) must be above the departure altitude (%.0f ft)", 
+				params.CruiseAltitude, params.PressureAltitude),
+		})
+	} else if da := c.densityAltitude(params, params.CruiseAltitude); da > maxDensityAltitude {
+		errs = append(errs, &ValidationError{
+			Field:   FieldCruiseAltitude,
+			Code:    CodeAboveMaximum,
+			Value:   params.CruiseAltitude,
+			Min:     params.PressureAltitude,
+			Max:     maxDensityAltitude,
+			Message: fmt.Sprintf("density altitude at cruise (%.0f ft) exceeds maximum chart value (%.0f ft)", 
+				da, maxDensityAltitude),
+		})
+	}
+	
+	return errs
+}
+
+// Calculate implements Calculator, and is equivalent to CalculateClimb
+func (c *ClimbCalculator) Calculate(params ClimbParams) (*ClimbResult, error) {
+	return c.CalculateClimb(params)
+}
+
+// Envelope returns the charted range of each climb input
+func (c *ClimbCalculator) Envelope() Envelope {
+	maxDensityAltitude := c.densityAltitudes[len(c.densityAltitudes)-1]
+	return Envelope{
+		{Field: FieldPressureAltitude, Unit: "ft", Min: c.densityAltitudes[0], Max: maxDensityAltitude},
+		{Field: FieldTemperature, Unit: "°C", Min: c.temperatures[0], Max: c.temperatures[len(c.temperatures)-1]},
+		{Field: FieldCruiseAltitude, Unit: "ft (density altitude)", Min: c.densityAltitudes[0], Max: maxDensityAltitude},
+	}
+}
+
+// Source identifies the climb chart
+func (c *ClimbCalculator) Source() Source {
+	return Source{
+		Aircraft: "PA-28-161 Cherokee Warrior II",
+		Document: "Pilot's Operating Handbook",
+		Figure:   "5-9",
+		Title:    "Fuel, Time and Distance to Climb",
+	}
+}
+
+// Explain lists the density altitudes, rates of climb and totals behind a climb result
+func (c *ClimbCalculator) Explain(params ClimbParams) (*Explanation, error) {
+	result, err := c.CalculateClimb(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	first, last := result.Rows[0], result.Rows[len(result.Rows)-1]
+	steps := []Step{
+		{Description: "Density altitude at departure", Value: c.densityAltitude(params, params.PressureAltitude), Unit: "ft"},
+	}
+	for _, a := range result.Adjustments {
+		steps = append(steps, Step{Description: "Rate of climb factor for " + a.Description, Value: 1 + a.Factor})
+	}
+	steps = append(steps,
+		Step{Description: "Rate of climb at departure", Value: first.RateOfClimb, Unit: "fpm"},
+		Step{Description: "Density altitude at cruise, standard lapse from departure", Value: c.densityAltitude(params, params.CruiseAltitude), Unit: "ft"},
+		Step{Description: "Rate of climb at cruise altitude", Value: last.RateOfClimb, Unit: "fpm"},
+		Step{Description: fmt.Sprintf("Time to climb at %.0f KIAS, integrated every %d ft", c.climbSpeed, climbStep), Value: result.Time, Unit: "min"},
+		Step{Description: "Fuel to climb", Value: result.Fuel, Unit: "gal"},
+		Step{Description: "Distance to climb, no wind", Value: result.Distance, Unit: "nm"},
+	)
+	
+	return &Explanation{Source: c.Source(), Steps: steps}, nil
+}
+
+// climbRow evaluates the chart at one altitude of the climb
+func (c *ClimbCalculator) climbRow(params ClimbParams, altitude, time, fuel, distance float64) ClimbRow {
+	temperature := c.temperatureAt(params, altitude)
+	
+	return ClimbRow{
+		PressureAltitude: altitude,
+		Temperature:      temperature,
+		RateOfClimb:      c.RateOfClimb(altitude, temperature),
+		IndicatedSpeed:   c.climbSpeed,
+		TrueSpeed:        TrueAirspeed(c.calibration.Calibrated(c.climbSpeed), altitude, temperature),
+		Time:             time,
+		Fuel:             fuel,
+		Distance:         distance,
+	}
+}
+
+// fuelFlow returns the climb fuel flow in gph at an altitude of the climb
+func (c *ClimbCalculator) fuelFlow(params ClimbParams, altitude float64) float64 {
+	da := c.densityAltitude(params, altitude)
+	return interpolate(c.fuelFlows, newBracket(c.densityAltitudes, da))
+}
+
+// densityAltitude returns the density altitude at an altitude of the climb
+func (c *ClimbCalculator) densityAltitude(params ClimbParams, altitude float64) float64 {
+	return atmosphere.DensityAltitude(altitude, c.temperatureAt(params, altitude))
+}
+
+// temperatureAt returns the temperature at an altitude, assuming the standard
+// lapse rate from the departure temperature
+func (c *ClimbCalculator) temperatureAt(params ClimbParams, altitude float64) float64 {
+	return params.Temperature - atmosphere.LapseRate * (altitude - params.PressureAltitude) / 1000
+}
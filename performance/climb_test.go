@@ -0,0 +1,105 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClimbTable(t *testing.T) {
+	calc := NewClimbCalculator()
+	
+	result, err := calc.CalculateClimb(ClimbParams{PressureAltitude: 500, Temperature: 20, CruiseAltitude: 6500})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	// Departure, 1000 through 6000 ft, and cruise altitude
+	expectedAltitudes := []float64{500, 1000, 2000, 3000, 4000, 5000, 6000, 6500}
+	if len(result.Rows) != len(expectedAltitudes) {
+		t.Fatalf("Expected %d rows, got %d", len(expectedAltitudes), len(result.Rows))
+	}
+	for i, row := range result.Rows {
+		if row.PressureAltitude != expectedAltitudes[i] {
+			t.Errorf("Row %d: expected %.0f ft, got %.0f ft", i, expectedAltitudes[i], row.PressureAltitude)
+		}
+		if i > 0 {
+			prev := result.Rows[i-1]
+			if row.Time <= prev.Time || row.Fuel <= prev.Fuel || row.Distance <= prev.Distance {
+				t.Errorf("Row %d: cumulative values must increase, got %+v after %+v", i, row, prev)
+			}
+			if row.RateOfClimb >= prev.RateOfClimb {
+				t.Errorf("Row %d: rate of climb must decrease with altitude, got %.0f after %.0f", i, row.RateOfClimb, prev.RateOfClimb)
+			}
+		}
+	}
+	
+	last := result.Rows[len(result.Rows)-1]
+	if last.Time != result.Time || last.Fuel != result.Fuel || last.Distance != result.Distance {
+		t.Errorf("Totals %.1f min %.1f gal %.1f nm do not match the last row %+v", result.Time, result.Fuel, result.Distance, last)
+	}
+	
+	// Roughly 13 minutes, 2 gallons and 18 nm from 500 ft to 6500 ft on a warm day
+	if math.Abs(result.Time - 13) > 1.5 || math.Abs(result.Fuel - 1.9) > 0.3 || math.Abs(result.Distance - 18) > 2 {
+		t.Errorf("Unexpected totals: %.1f min, %.1f gal, %.1f nm", result.Time, result.Fuel, result.Distance)
+	}
+}
+
+func TestClimbHotterIsSlower(t *testing.T) {
+	calc := NewClimbCalculator()
+	
+	cold, err := calc.CalculateClimb(ClimbParams{PressureAltitude: 0, Temperature: 0, CruiseAltitude: 8000})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hot, err := calc.CalculateClimb(ClimbParams{PressureAltitude: 0, Temperature: 35, CruiseAltitude: 8000})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	if hot.Time <= cold.Time || hot.Distance <= cold.Distance {
+		t.Errorf("Expected a slower, longer climb when hot: cold %.1f min %.1f nm, hot %.1f min %.1f nm", 
+			cold.Time, cold.Distance, hot.Time, hot.Distance)
+	}
+}
+
+func TestServiceCeiling(t *testing.T) {
+	calc := NewClimbCalculator()
+	
+	// The POH gives an 11,000 ft service ceiling at maximum weight
+	if ceiling := calc.ServiceCeiling(); math.Abs(ceiling - 11000) > 200 {
+		t.Errorf("Expected a service ceiling near 11000 ft, got %.0f ft", ceiling)
+	}
+	if roc := calc.RateOfClimb(calc.ServiceCeiling(), 15 - 1.9812 * calc.ServiceCeiling() / 1000); math.Abs(roc - ServiceCeilingRate) > 1 {
+		t.Errorf("Expected %d fpm at the service ceiling, got %.0f fpm", ServiceCeilingRate, roc)
+	}
+	
+	calc.AdjustRateOfClimb(Adjustment{Description: "Floats installed", Factor: -0.5})
+	if ceiling := calc.ServiceCeiling(); math.Abs(ceiling - 9273) > 1 {
+		t.Errorf("Expected the adjusted ceiling at 200 fpm charted, 9273 ft, got %.0f ft", ceiling)
+	}
+}
+
+func TestClimbValidation(t *testing.T) {
+	calc := NewClimbCalculator()
+	
+	tests := []struct {
+		name   string
+		params ClimbParams
+		field  string
+		code   string
+	}{
+		{"cruise below departure", ClimbParams{PressureAltitude: 5000, Temperature: 15, CruiseAltitude: 4000}, FieldCruiseAltitude, CodeBelowMinimum},
+		{"cruise above chart", ClimbParams{PressureAltitude: 0, Temperature: 30, CruiseAltitude: 11500}, FieldCruiseAltitude, CodeAboveMaximum},
+		{"temperature above chart", ClimbParams{PressureAltitude: 0, Temperature: 45, CruiseAltitude: 4000}, FieldTemperature, CodeAboveMaximum},
+	}
+	
+	for _, tc := range tests {
+		errs := calc.Validate(tc.params)
+		if len(errs) != 1 || errs[0].Field != tc.field || errs[0].Code != tc.code {
+			t.Errorf("%s: expected %s %s, got %v", tc.name, tc.field, tc.code, errs)
+		}
+		if _, err := calc.CalculateClimb(tc.params); err == nil {
+			t.Errorf("%s: expected error from CalculateClimb, but got none", tc.name)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package performance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// gapRatio is how much wider than the axis's typical spacing two adjacent
+// chart lines must be for the span between them to count as a gap
+const gapRatio = 1.5
+
+// Axis is one input of a chart and the lines the chart is digitized at
+type Axis struct {
+	Name   string    `json:"name"`
+	Unit   string    `json:"unit"`
+	Values []float64 `json:"values"` // Ascending
+	
+	// RangeOnly is set when the chart draws no lines along the axis and
+	// Values are only its limits
+	RangeOnly bool `json:"range_only,omitempty"`
+}
+
+// Min returns the lowest charted value
+func (a Axis) Min() float64 {
+	return a.Values[0]
+}
+
+// Max returns the highest charted value
+func (a Axis) Max() float64 {
+	return a.Values[len(a.Values)-1]
+}
+
+// Spacing returns the narrowest and widest step between adjacent lines,
+// or zeros for a range
+func (a Axis) Spacing() (narrowest, widest float64) {
+	if a.RangeOnly || len(a.Values) < 2 {
+		return 0, 0
+	}
+	narrowest, widest = math.Inf(1), 0
+	for i := 1; i < len(a.Values); i++ {
+		step := a.Values[i] - a.Values[i-1]
+		narrowest, widest = math.Min(narrowest, step), math.Max(widest, step)
+	}
+	return narrowest, widest
+}
+
+// Gap is a span of an axis where interpolation confidence is low: adjacent
+// lines much further apart than the axis's typical spacing, or a chart
+// value that was never digitized
+type Gap struct {
+	Axis   string  `json:"axis"`
+	From   float64 `json:"from"`
+	To     float64 `json:"to"`
+	Reason string  `json:"reason"`
+}
+
+// Line is one charted curve through its digitized points, for plotting
+type Line struct {
+	Label string    `json:"label"`
+	X     []float64 `json:"x"`
+	Y     []float64 `json:"y"`
+}
+
+// Coverage describes how densely a chart is digitized: its axes, how many
+// points lie on them, the gaps, and its curves along the first axis
+type Coverage struct {
+	Source Source `json:"source"`
+	Axes   []Axis `json:"axes"`
+	Points int    `json:"points"` // Digitized values the result is interpolated from
+	Gaps   []Gap  `json:"gaps,omitempty"`
+	
+	// Lines are the curves along the first axis, the output in Unit
+	Lines []Line `json:"lines"`
+	Unit  string `json:"unit"`
+}
+
+// Coverage returns the axes, grid and gaps of the takeoff chart; the lines
+// are the distance at maximum weight against temperature, one per altitude
+func (c *TakeoffCalculator) Coverage() Coverage {
+	coverage := Coverage{
+		Source: c.source,
+		Axes: []Axis{
+			{Name: "temperature", Unit: "°C", Values: c.temperatures},
+			{Name: "pressure altitude", Unit: "ft", Values: c.altitudes},
+			{Name: "weight", Unit: "lbs", Values: c.weights},
+			{Name: "headwind", Unit: "kts", Values: c.headwinds},
+			{Name: "tailwind", Unit: "kts", Values: c.tailwinds},
+		},
+		Unit: "ft",
+	}
+	
+	heaviest := len(c.weights) - 1
+	for i, altitude := range c.altitudes {
+		line := Line{Label: fmt.Sprintf("%.0f ft", altitude), X: c.temperatures}
+		for j, temperature := range c.temperatures {
+			for k, weight := range c.weights {
+				distance := c.baseDistances[i][k*len(c.temperatures)+j]
+				if distance > 0 && !math.IsNaN(distance) {
+					coverage.Points++
+				} else {
+					coverage.Gaps = append(coverage.Gaps, Gap{
+						Axis:   "weight",
+						From:   weight,
+						To:     weight,
+						Reason: fmt.Sprintf("no distance at %.0f ft, %.0f°C", altitude, temperature),
+					})
+				}
+			}
+			line.Y = append(line.Y, c.baseDistances[i][heaviest*len(c.temperatures)+j])
+		}
+		coverage.Lines = append(coverage.Lines, line)
+	}
+	coverage.Points += len(c.headwindFactors) + len(c.tailwindFactors)
+	coverage.Gaps = append(spacingGaps(coverage.Axes), coverage.Gaps...)
+	return coverage
+}
+
+// Coverage returns the axes, grid and gaps of the landing chart; the lines
+// are the distance over 50 ft at maximum weight against temperature, one
+// per altitude
+func (c *LandingCalculator) Coverage() Coverage {
+	coverage := Coverage{
+		Source: c.Source(),
+		Axes: []Axis{
+			{Name: "temperature", Unit: "°C", Values: c.temperatures},
+			{Name: "pressure altitude", Unit: "ft", Values: c.altitudes},
+			{Name: "weight", Unit: "lbs", Values: c.weights},
+			{Name: "headwind", Unit: "kts", Values: c.headwinds},
+			{Name: "tailwind", Unit: "kts", Values: c.tailwinds},
+		},
+		Unit: "ft",
+	}
+	
+	heaviest := len(c.weights) - 1
+	for i, altitude := range c.altitudes {
+		line := Line{Label: fmt.Sprintf("%.0f ft", altitude), X: c.temperatures}
+		for j := range c.temperatures {
+			line.Y = append(line.Y, c.distances[i][heaviest*len(c.temperatures)+j])
+		}
+		coverage.Lines = append(coverage.Lines, line)
+		coverage.Points += len(c.distances[i]) + len(c.groundRolls[i])
+	}
+	coverage.Points += len(c.headwindFactors) + len(c.tailwindFactors)
+	coverage.Gaps = spacingGaps(coverage.Axes)
+	return coverage
+}
+
+// Coverage returns the axes, grid and gaps of the climb chart; the line is
+// the rate of climb against density altitude. Temperature is a range: it
+// only enters through the density altitude.
+func (c *ClimbCalculator) Coverage() Coverage {
+	coverage := Coverage{
+		Source: c.Source(),
+		Axes: []Axis{
+			{Name: "density altitude", Unit: "ft", Values: c.densityAltitudes},
+			{Name: "temperature", Unit: "°C", Values: c.temperatures, RangeOnly: true},
+		},
+		Points: len(c.ratesOfClimb) + len(c.fuelFlows),
+		Lines:  []Line{{Label: "rate of climb", X: c.densityAltitudes, Y: c.ratesOfClimb}},
+		Unit:   "fpm",
+	}
+	coverage.Gaps = spacingGaps(coverage.Axes)
+	return coverage
+}
+
+// Coverage returns the axes, grid and gaps of the cruise chart; the lines
+// are the true airspeed against density altitude, one per power
+func (c *CruiseCalculator) Coverage() Coverage {
+	coverage := Coverage{
+		Source: c.Source(),
+		Axes: []Axis{
+			{Name: "density altitude", Unit: "ft", Values: c.densityAltitudes},
+			{Name: "power", Unit: "%", Values: c.powers},
+			{Name: "temperature", Unit: "°C", Values: c.temperatures, RangeOnly: true},
+		},
+		Unit: "kt",
+	}
+	for i, power := range c.powers {
+		coverage.Points += len(c.rpms[i]) + len(c.trueAirspeeds[i]) + 1
+		coverage.Lines = append(coverage.Lines, Line{
+			Label: fmt.Sprintf("%.0f%%", power),
+			X:     c.densityAltitudes,
+			Y:     c.trueAirspeeds[i],
+		})
+	}
+	coverage.Gaps = spacingGaps(coverage.Axes)
+	return coverage
+}
+
+// spacingGaps finds the spans of each axis more than gapRatio times wider
+// than its median spacing
+func spacingGaps(axes []Axis) []Gap {
+	var gaps []Gap
+	for _, axis := range axes {
+		if axis.RangeOnly || len(axis.Values) < 3 {
+			continue
+		}
+		steps := make([]float64, 0, len(axis.Values)-1)
+		for i := 1; i < len(axis.Values); i++ {
+			steps = append(steps, axis.Values[i]-axis.Values[i-1])
+		}
+		sorted := append([]float64(nil), steps...)
+		sort.Float64s(sorted)
+		median := sorted[len(sorted)/2]
+		
+		for i, step := range steps {
+			if step > median * gapRatio {
+				gaps = append(gaps, Gap{
+					Axis:   axis.Name,
+					From:   axis.Values[i],
+					To:     axis.Values[i+1],
+					Reason: fmt.Sprintf("%g %s between lines, typically %g", step, axis.Unit, median),
+				})
+			}
+		}
+	}
+	return gaps
+}
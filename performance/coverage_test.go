@@ -0,0 +1,42 @@
+package performance
+
+import (
+	"testing"
+)
+
+func TestTakeoffCoverage(t *testing.T) {
+	coverage := NewTakeoffCalculator().Coverage()
+	if len(coverage.Axes) != 5 {
+		t.Fatalf("Expected 5 axes, got %d", len(coverage.Axes))
+	}
+	// 8 altitudes x 5 temperatures x 5 weights, plus 4 headwind and 2 tailwind factors
+	if coverage.Points != 206 {
+		t.Errorf("Expected 206 digitized points, got %d", coverage.Points)
+	}
+	if len(coverage.Gaps) != 0 {
+		t.Errorf("Expected no gaps in the takeoff chart, got %+v", coverage.Gaps)
+	}
+	if len(coverage.Lines) != 8 || coverage.Lines[0].Y[4] != 2250 {
+		t.Errorf("Expected a line per altitude ending at 2250 ft at sea level, got %+v", coverage.Lines)
+	}
+	
+	weight := coverage.Axes[2]
+	narrowest, widest := weight.Spacing()
+	if weight.Min() != 1600 || weight.Max() != 2325 || narrowest != 125 || widest != 200 {
+		t.Errorf("Expected weights 1600 to 2325 every 125 to 200 lbs, got %g to %g every %g to %g",
+			weight.Min(), weight.Max(), narrowest, widest)
+	}
+}
+
+func TestSpacingGaps(t *testing.T) {
+	gaps := spacingGaps([]Axis{
+		{Name: "pressure altitude", Unit: "ft", Values: []float64{0, 1000, 2000, 5000, 6000}},
+		{Name: "temperature", Unit: "°C", Values: []float64{-40, 40}},
+	})
+	if len(gaps) != 1 {
+		t.Fatalf("Expected 1 gap, got %+v", gaps)
+	}
+	if g := gaps[0]; g.Axis != "pressure altitude" || g.From != 2000 || g.To != 5000 {
+		t.Errorf("Expected the 2000 to 5000 ft gap, got %+v", g)
+	}
+}
@@ -0,0 +1,256 @@
+package performance
+
+import (
+	"fmt"
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/internal/solve"
+)
+
+// FieldPower is the field name used in cruise validation errors
+const FieldPower = "power"
+
+// powerTolerance is the precision of the cruise power solvers, in percent
+const powerTolerance = 0.01
+
+// CruiseParams represents the input parameters for cruise performance calculations
+type CruiseParams struct {
+	PressureAltitude float64 `json:"pressure_altitude"` // Cruise pressure altitude in feet
+	Temperature      float64 `json:"temperature_c"`     // Outside air temperature at cruise altitude in °C
+	Power            float64 `json:"power"`             // Percent of rated power
+}
+
+// CruiseResult contains the power setting and performance for a cruise condition
+type CruiseResult struct {
+	Power           float64 `json:"power"`            // Percent of rated power
+	RPM             float64 `json:"rpm"`              // Engine RPM giving that power
+	TrueAirspeed    float64 `json:"true_airspeed"`    // in KTAS
+	FuelFlow        float64 `json:"fuel_flow"`        // in US gallons per hour, leaned
+	DensityAltitude float64 `json:"density_altitude"` // in feet
+}
+
+// CruiseCalculator handles the PA-28-161 cruise performance calculations
+type CruiseCalculator struct {
+	// These arrays define the data points on the chart
+	densityAltitudes []float64   // Density altitude in feet
+	powers           []float64   // Percent of rated power
+	rpms             [][]float64 // RPM for each power at each density altitude
+	trueAirspeeds    [][]float64 // KTAS for each power at each density altitude
+	fuelFlows        []float64   // Leaned fuel flow in gph for each power
+	temperatures     []float64   // Charted temperature range in °C
+	
+	speedAdjustment float64 // KTAS added to the charted speed for the airframe's configuration
+}
+
+var _ Calculator[CruiseParams, *CruiseResult] = (*CruiseCalculator)(nil)
+
+// NewCruiseCalculator creates a new cruise performance calculator
+func NewCruiseCalculator() *CruiseCalculator {
+	return &CruiseCalculator{
+		// Digitized from the power setting table and speed power chart, 2325 lbs, leaned to best economy
+		densityAltitudes: []float64{0, 2000, 4000, 6000, 8000, 10000, 12000},
+		powers:           []float64{55, 65, 75},
+		rpms: [][]float64{
+			// 0     2000   4000   6000   8000   10000  12000 ft
+			{2250,  2280,  2310,  2340,  2370,  2400,  2430}, // 55%
+			{2390,  2420,  2450,  2480,  2510,  2540,  2570}, // 65%
+			{2520,  2550,  2580,  2610,  2640,  2670,  2700}, // 75%
+		},
+		trueAirspeeds: [][]float64{
+			// 0     2000   4000   6000   8000   10000  12000 ft
+			{97,    99,    101,   103,   105,   107,   109},  // 55%
+			{105,   107,   109,   111,   113,   115,   117},  // 65%
+			{112,   114,   116,   118,   120,   122,   124},  // 75%
+		},
+		fuelFlows:    []float64{6.1, 7.0, 8.1},
+		temperatures: []float64{-40, 40},
+	}
+}
+
+// CalculateCruise finds the RPM, true airspeed and fuel flow for a power setting
+func (c *CruiseCalculator) CalculateCruise(params CruiseParams) (*CruiseResult, error) {
+	if errs := c.Validate(params); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return c.cruise(params), nil
+}
+
+// AdjustTrueAirspeed adds a speed change in knots, such as from removed
+// wheel fairings, to every charted true airspeed
+func (c *CruiseCalculator) AdjustTrueAirspeed(knots float64) {
+	c.speedAdjustment += knots
+}
+
+// PowerForTrueAirspeed finds the power setting that gives a target true
+// airspeed at the altitude and temperature of params. The Power field of
+// params is ignored.
+func (c *CruiseCalculator) PowerForTrueAirspeed(params CruiseParams, trueAirspeed float64) (*CruiseResult, error) {
+	return c.solvePower(params, trueAirspeed, "true airspeed", "KTAS",
+		func(r *CruiseResult) float64 { return r.TrueAirspeed })
+}
+
+// PowerForFuelFlow finds the power setting that burns a target fuel flow at
+// the altitude and temperature of params. The Power field of params is ignored.
+func (c *CruiseCalculator) PowerForFuelFlow(params CruiseParams, fuelFlow float64) (*CruiseResult, error) {
+	return c.solvePower(params, fuelFlow, "fuel flow", "gph",
+		func(r *CruiseResult) float64 { return r.FuelFlow })
+}
+
+// AvailablePower returns the range of power settings charted and achievable
+// at an altitude and temperature, in percent
+func (c *CruiseCalculator) AvailablePower(pressureAltitude, temperature float64) (float64, float64) {
+	// Full throttle power falls with density (Gagg-Ferrar)
+	sigma := atmosphere.DensityRatio(pressureAltitude, temperature)
+	fullThrottle := (1.132 * sigma - 0.132) * 100
+	
+	return c.powers[0], math.Min(c.powers[len(c.powers)-1], fullThrottle)
+}
+
+// Validate checks the cruise inputs against the chart limits and returns all
+// violations found, or nil if the parameters are within the envelope
+func (c *CruiseCalculator) Validate(params CruiseParams) ValidationErrors {
+	var errs ValidationErrors
+	
+	minTemp, maxTemp := c.temperatures[0], c.temperatures[len(c.temperatures)-1]
+	if params.Temperature < minTemp || params.Temperature > maxTemp {
+		errs = append(errs, &ValidationError{
+			Field:   FieldTemperature,
+			Code:    rangeCode(params.Temperature, minTemp),
+			Value:   params.Temperature,
+			Min:     minTemp,
+			Max:     maxTemp,
+			Message: fmt.Sprintf("temperature (%.1f°C) outside chart range (%.1f°C to %.1f°C)", 
+				params.Temperature, minTemp, maxTemp),
+		})
+	}
+	
+	da := atmosphere.DensityAltitude(params.PressureAltitude, params.Temperature)
+	minDA, maxDA := c.densityAltitudes[0], c.densityAltitudes[len(c.densityAltitudes)-1]
+	if da > maxDA {
+		errs = append(errs, &ValidationError{
+			Field:   FieldPressureAltitude,
+			Code:    CodeAboveMaximum,
+			Value:   params.PressureAltitude,
+			Min:     minDA,
+			Max:     maxDA,
+			Message: fmt.Sprintf("density altitude (%.0f ft) exceeds maximum chart value (%.0f ft)", da, maxDA),
+		})
+	}
+	
+	minPower, maxPower := c.AvailablePower(params.PressureAltitude, params.Temperature)
+	if params.Power < minPower || params.Power > maxPower {
+		errs = append(errs, &ValidationError{
+			Field:   FieldPower,
+			Code:    rangeCode(params.Power, minPower),
+			Value:   params.Power,
+			Min:     minPower,
+			Max:     maxPower,
+			Message: fmt.Sprintf("power (%.0f%%) outside the range available at this altitude (%.0f%% to %.0f%%)", 
+				params.Power, minPower, maxPower),
+		})
+	}
+	
+	return errs
+}
+
+// Calculate implements Calculator, and is equivalent to CalculateCruise
+func (c *CruiseCalculator) Calculate(params CruiseParams) (*CruiseResult, error) {
+	return c.CalculateCruise(params)
+}
+
+// Envelope returns the charted range of each cruise input
+func (c *CruiseCalculator) Envelope() Envelope {
+	return Envelope{
+		{Field: FieldPressureAltitude, Unit: "ft (density altitude)", Min: c.densityAltitudes[0], Max: c.densityAltitudes[len(c.densityAltitudes)-1]},
+		{Field: FieldTemperature, Unit: "°C", Min: c.temperatures[0], Max: c.temperatures[len(c.temperatures)-1]},
+		{Field: FieldPower, Unit: "%", Min: c.powers[0], Max: c.powers[len(c.powers)-1]},
+	}
+}
+
+// Source identifies the cruise charts
+func (c *CruiseCalculator) Source() Source {
+	return Source{
+		Aircraft: "PA-28-161 Cherokee Warrior II",
+		Document: "Pilot's Operating Handbook",
+		Figure:   "5-13, 5-15",
+		Title:    "Power Setting Table; Speed Power",
+	}
+}
+
+// Explain lists the density altitude and chart lookups behind a cruise result
+func (c *CruiseCalculator) Explain(params CruiseParams) (*Explanation, error) {
+	result, err := c.CalculateCruise(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	_, maxPower := c.AvailablePower(params.PressureAltitude, params.Temperature)
+	steps := []Step{
+		{Description: "Density altitude", Value: result.DensityAltitude, Unit: "ft"},
+		{Description: "Maximum power available", Value: maxPower, Unit: "%"},
+		{Description: fmt.Sprintf("RPM for %.0f%% power", result.Power), Value: result.RPM, Unit: "RPM"},
+	}
+	if c.speedAdjustment != 0 {
+		steps = append(steps, Step{Description: "Configuration speed adjustment", Value: c.speedAdjustment, Unit: "KTAS"})
+	}
+	steps = append(steps,
+		Step{Description: "True airspeed", Value: result.TrueAirspeed, Unit: "KTAS"},
+		Step{Description: "Fuel flow, leaned", Value: result.FuelFlow, Unit: "gph"},
+	)
+	
+	return &Explanation{Source: c.Source(), Steps: steps}, nil
+}
+
+// cruise evaluates the charts for validated inputs
+func (c *CruiseCalculator) cruise(params CruiseParams) *CruiseResult {
+	da := atmosphere.DensityAltitude(params.PressureAltitude, params.Temperature)
+	alt := newBracket(c.densityAltitudes, da)
+	power := newBracket(c.powers, params.Power)
+	
+	return &CruiseResult{
+		Power:           params.Power,
+		RPM:             interpolateTable(c.rpms, power, alt),
+		TrueAirspeed:    interpolateTable(c.trueAirspeeds, power, alt) + c.speedAdjustment,
+		FuelFlow:        interpolate(c.fuelFlows, power),
+		DensityAltitude: da,
+	}
+}
+
+// solvePower finds the power at which a cruise output reaches a target,
+// relying on the output increasing monotonically with power
+func (c *CruiseCalculator) solvePower(params CruiseParams, target float64, name, unit string,
+	output func(*CruiseResult) float64) (*CruiseResult, error) {
+	minPower, maxPower := c.AvailablePower(params.PressureAltitude, params.Temperature)
+	
+	// The altitude and temperature must be valid on their own
+	params.Power = minPower
+	if errs := c.Validate(params); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	
+	at := func(power float64) *CruiseResult {
+		p := params
+		p.Power = power
+		return c.cruise(p)
+	}
+	
+	lo, hi := output(at(minPower)), output(at(maxPower))
+	if target < lo || target > hi {
+		return nil, fmt.Errorf("%s of %.1f %s is outside the range available at this altitude (%.1f to %.1f %s)", 
+			name, target, unit, lo, hi, unit)
+	}
+	
+	power, err := solve.Brent(func(p float64) float64 { return output(at(p)) - target }, minPower, maxPower, powerTolerance)
+	if err != nil {
+		return nil, err
+	}
+	return at(power), nil
+}
+
+// interpolateTable performs bilinear interpolation in a table indexed [row][column]
+func interpolateTable(table [][]float64, row, column bracket) float64 {
+	lo := interpolate(table[row.lo], column)
+	hi := interpolate(table[row.hi], column)
+	return lo * (1 - row.frac) + hi * row.frac
+}
@@ -0,0 +1,119 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCruiseChart(t *testing.T) {
+	calc := NewCruiseCalculator()
+	
+	// On a standard day the density altitude equals the pressure altitude,
+	// so chart points are reproduced exactly
+	tests := []struct {
+		altitude float64
+		power    float64
+		rpm      float64
+		tas      float64
+		fuelFlow float64
+	}{
+		{0, 55, 2250, 97, 6.1},
+		{4000, 65, 2450, 109, 7.0},
+		{8000, 75, 2640, 120, 8.1},
+	}
+	
+	for _, tc := range tests {
+		params := CruiseParams{PressureAltitude: tc.altitude, Temperature: 15 - 1.9812 * tc.altitude / 1000, Power: tc.power}
+		result, err := calc.CalculateCruise(params)
+		if err != nil {
+			t.Fatalf("%.0f ft %.0f%%: unexpected error: %v", tc.altitude, tc.power, err)
+		}
+		if math.Abs(result.RPM - tc.rpm) > 2 || math.Abs(result.TrueAirspeed - tc.tas) > 0.1 || result.FuelFlow != tc.fuelFlow {
+			t.Errorf("%.0f ft %.0f%%: expected %.0f RPM %.0f KTAS %.1f gph, got %.0f RPM %.1f KTAS %.1f gph", 
+				tc.altitude, tc.power, tc.rpm, tc.tas, tc.fuelFlow, result.RPM, result.TrueAirspeed, result.FuelFlow)
+		}
+	}
+}
+
+func TestCruisePowerSolvers(t *testing.T) {
+	calc := NewCruiseCalculator()
+	params := CruiseParams{PressureAltitude: 6500, Temperature: 2}
+	
+	result, err := calc.PowerForFuelFlow(params, 7.5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(result.FuelFlow - 7.5) > 0.01 {
+		t.Errorf("Expected 7.5 gph, got %.2f gph at %.1f%%", result.FuelFlow, result.Power)
+	}
+	
+	result, err = calc.PowerForTrueAirspeed(params, 115)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(result.TrueAirspeed - 115) > 0.01 {
+		t.Errorf("Expected 115 KTAS, got %.2f KTAS at %.1f%%", result.TrueAirspeed, result.Power)
+	}
+	
+	// The solved power must reproduce the result through CalculateCruise
+	params.Power = result.Power
+	check, err := calc.CalculateCruise(params)
+	if err != nil || check.RPM != result.RPM {
+		t.Errorf("CalculateCruise at solved power: got %+v, %v; expected %+v", check, err, result)
+	}
+}
+
+func TestCruisePowerSolverLimits(t *testing.T) {
+	calc := NewCruiseCalculator()
+	
+	// Faster than 75% power or slower than 55% power cannot be set
+	if _, err := calc.PowerForTrueAirspeed(CruiseParams{PressureAltitude: 2000, Temperature: 11}, 140); err == nil {
+		t.Error("Expected error for an unreachable speed, but got none")
+	}
+	if _, err := calc.PowerForFuelFlow(CruiseParams{PressureAltitude: 2000, Temperature: 11}, 5); err == nil {
+		t.Error("Expected error for an unreachable fuel flow, but got none")
+	}
+	
+	// At 10,000 ft full throttle gives about 70%, so 8.1 gph is out of reach
+	if _, err := calc.PowerForFuelFlow(CruiseParams{PressureAltitude: 10000, Temperature: -5}, 8.1); err == nil {
+		t.Error("Expected error for a fuel flow above full throttle, but got none")
+	}
+	if _, err := calc.CalculateCruise(CruiseParams{PressureAltitude: 10000, Temperature: -5, Power: 75}); err == nil {
+		t.Error("Expected error for power above full throttle, but got none")
+	}
+}
+
+func TestCruiseSpeedAdjustment(t *testing.T) {
+	params := CruiseParams{PressureAltitude: 4000, Temperature: 7.1, Power: 65}
+	standard, err := NewCruiseCalculator().CalculateCruise(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	calc := NewCruiseCalculator()
+	calc.AdjustTrueAirspeed(-2)
+	adjusted, err := calc.CalculateCruise(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(adjusted.TrueAirspeed - (standard.TrueAirspeed - 2)) > 1e-9 || adjusted.RPM != standard.RPM {
+		t.Errorf("Expected %.1f KTAS at the same RPM, got %.1f KTAS", standard.TrueAirspeed - 2, adjusted.TrueAirspeed)
+	}
+	
+	// The speed solver accounts for the adjustment
+	result, err := calc.PowerForTrueAirspeed(params, standard.TrueAirspeed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Power <= params.Power {
+		t.Errorf("Expected more than %.0f%% power to make up the lost speed, got %.1f%%", params.Power, result.Power)
+	}
+	
+	explanation, err := calc.Explain(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(explanation.Steps) != 6 {
+		t.Errorf("Expected the adjustment as an explanation step, got %+v", explanation.Steps)
+	}
+}
@@ -0,0 +1,66 @@
+package performance
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+)
+
+// hashFormat versions what ResultHash covers and how the result is rounded;
+// change it whenever either changes, so an old hash is never silently
+// compared against a different computation
+const hashFormat = "otto-perf/takeoff/2"
+
+// lerp interpolates between a and b at frac. The explicit conversions
+// round each product before the sum, which stops the compiler fusing them
+// into one multiply-add on arm64, ppc64le and s390x, so every platform
+// computes the same bits.
+func lerp(a, b, frac float64) float64 {
+	return float64(a * (1 - frac)) + float64(b * frac)
+}
+
+// SetDeterministic turns on the deterministic calculation mode, in which
+// results are rounded to a fixed resolution (0.1 ft and 0.01 kt) finer than
+// any chart is read to. Platforms whose math routines differ in the last
+// bits then give byte-for-byte identical results, so an archived briefing
+// can be reproduced and its ResultHash checked for an audit.
+func (c *TakeoffCalculator) SetDeterministic(on bool) {
+	c.deterministic = on
+}
+
+// quantize rounds a result in place to the deterministic resolution
+func quantize(r *TakeoffResult) {
+	r.TakeoffDistance = roundTo(r.TakeoffDistance, 10)
+	r.Tolerance = roundTo(r.Tolerance, 10)
+	r.GroundRoll = roundTo(r.GroundRoll, 10)
+	r.LiftoffSpeed = roundTo(r.LiftoffSpeed, 100)
+	r.BarrierSpeed = roundTo(r.BarrierSpeed, 100)
+	if r.Roll != nil {
+		r.Roll.Distance = roundTo(r.Roll.Distance, 10)
+		r.Roll.Time = roundTo(r.Roll.Time, 100)
+		r.Roll.Acceleration = roundTo(r.Roll.Acceleration, 100)
+		r.Roll.LiftoffTrueAirspeed = roundTo(r.Roll.LiftoffTrueAirspeed, 100)
+		r.Roll.LiftoffGroundspeed = roundTo(r.Roll.LiftoffGroundspeed, 100)
+	}
+}
+
+// roundTo rounds a value to the nearest 1/scale
+func roundTo(v, scale float64) float64 {
+	return math.Round(v * scale) / scale
+}
+
+// ResultHash identifies a takeoff calculation: the chart it was read from,
+// its inputs and its result, as "sha256:" and the hex digest. Computed
+// again from the same inputs in deterministic mode, on any platform, it
+// matches, so it proves an archived result is the one the chart gives.
+func ResultHash(source Source, params TakeoffParams, result *TakeoffResult) string {
+	data, _ := json.Marshal(struct {
+		Format string         `json:"format"`
+		Source Source         `json:"source"`
+		Params TakeoffParams  `json:"params"`
+		Result *TakeoffResult `json:"result"`
+	}{hashFormat, source, params, result})
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
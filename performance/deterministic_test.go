@@ -0,0 +1,102 @@
+package performance
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDeterministic(t *testing.T) {
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: ConvertFahrenheitToCelsius(80), Weight: 2325, WindComponent: 7}
+	calc := NewTakeoffCalculator()
+	exact, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calc.SetDeterministic(true)
+	rounded, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	for _, v := range []struct {
+		name       string
+		exact, got float64
+		scale      float64
+	}{
+		{"distance", exact.TakeoffDistance, rounded.TakeoffDistance, 10},
+		{"tolerance", exact.Tolerance, rounded.Tolerance, 10},
+		{"liftoff speed", exact.LiftoffSpeed, rounded.LiftoffSpeed, 100},
+		{"barrier speed", exact.BarrierSpeed, rounded.BarrierSpeed, 100},
+		{"roll distance", exact.Roll.Distance, rounded.Roll.Distance, 10},
+		{"roll time", exact.Roll.Time, rounded.Roll.Time, 100},
+	} {
+		if v.got * v.scale != math.Round(v.got * v.scale) || math.Abs(v.got - v.exact) > 0.5 / v.scale {
+			t.Errorf("Expected the %s %v rounded to 1/%v, got %v", v.name, v.exact, v.scale, v.got)
+		}
+	}
+	
+	// A session gives the same rounded result
+	if got, _ := calc.NewSession(params).Result(); *got.Roll != *rounded.Roll || got.TakeoffDistance != rounded.TakeoffDistance {
+		t.Errorf("Expected the session result %+v, got %+v", rounded, got)
+	}
+	
+	// The hash identifies the chart, inputs and result
+	hash := ResultHash(calc.Source(), params, rounded)
+	if !strings.HasPrefix(hash, "sha256:") || len(hash) != len("sha256:") + 64 {
+		t.Errorf("Unexpected hash %q", hash)
+	}
+	again, _ := calc.CalculateTakeoff(params)
+	if got := ResultHash(calc.Source(), params, again); got != hash {
+		t.Errorf("Expected the same hash recomputed, got %s and %s", hash, got)
+	}
+	heavier := params
+	heavier.Weight++
+	if got := ResultHash(calc.Source(), heavier, rounded); got == hash {
+		t.Errorf("Expected the inputs to change the hash")
+	}
+	if got := ResultHash(calc.Source(), params, exact); got == hash {
+		t.Errorf("Expected the result to change the hash")
+	}
+}
+
+func TestLerp(t *testing.T) {
+	tests := []struct {
+		a, b, frac, want float64
+	}{
+		{1000, 2000, 0, 1000},
+		{1000, 2000, 1, 2000},
+		{1000, 2000, 0.25, 1250},
+		{0.1, 0.3, 0.5, 0.2},
+	}
+	for _, tc := range tests {
+		if got := lerp(tc.a, tc.b, tc.frac); math.Abs(got - tc.want) > 1e-12 {
+			t.Errorf("lerp(%v, %v, %v) = %v, expected %v", tc.a, tc.b, tc.frac, got, tc.want)
+		}
+	}
+}
+
+func TestResultHashPinned(t *testing.T) {
+	// Between the chart's lines, so the tolerance takes the cubic reading,
+	// and between its wind lines; the hash must never change on any platform
+	calc := NewTakeoffCalculator()
+	calc.SetDeterministic(true)
+	for _, tc := range []struct {
+		params TakeoffParams
+		want   string
+	}{
+		{TakeoffParams{PressureAltitude: 3300, Temperature: 27.3, Weight: 2140, WindComponent: 8.5}, "sha256:1bd65e877fdd062ac2c40a2ed3ec22fb6f32f35e4f72acf93badcba0c408235f"},
+		{TakeoffParams{PressureAltitude: 5700, Temperature: 12.6, Weight: 2290, WindComponent: -3.5}, "sha256:6fc27863792ba7cd305411bb80af12f475d0a42bf28e2d95f5fda16deec9c24f"},
+	} {
+		result, err := calc.CalculateTakeoff(tc.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Tolerance == 0 {
+			t.Errorf("%+v: expected a cubic tolerance between the chart's lines", tc.params)
+		}
+		if got := ResultHash(calc.Source(), tc.params, result); got != tc.want {
+			t.Errorf("%+v: expected hash %s, got %s", tc.params, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package performance
+
+import "fmt"
+
+// SafetyFactor is the multiplier that a body's safety guidance applies to
+// POH distances, which are demonstrated by test pilots in new aircraft.
+// Factored distances are shown next to the raw figures, never in place
+// of them, and always with the source of the factor.
+type SafetyFactor struct {
+	Source  string  `json:"source"`  // The guidance the factors come from
+	Takeoff float64 `json:"takeoff"` // Multiplier for the takeoff distance over 50 ft
+	Landing float64 `json:"landing"` // Multiplier for the landing distance over 50 ft
+}
+
+// CAASafetySense holds the factors of the UK CAA's Safety Sense Leaflet 7,
+// also recommended by AOPA UK, for a dry paved runway
+var CAASafetySense = SafetyFactor{
+	Source:  "UK CAA Safety Sense Leaflet 7",
+	Takeoff: 1.33,
+	Landing: 1.43,
+}
+
+// TakeoffDistance returns a charted takeoff distance with the factor applied
+func (f SafetyFactor) TakeoffDistance(distance float64) float64 {
+	return distance * f.Takeoff
+}
+
+// LandingDistance returns a charted landing distance with the factor applied
+func (f SafetyFactor) LandingDistance(distance float64) float64 {
+	return distance * f.Landing
+}
+
+// String labels the takeoff factor with its source, e.g.
+// "×1.33, UK CAA Safety Sense Leaflet 7"
+func (f SafetyFactor) String() string {
+	return fmt.Sprintf("×%.2f, %s", f.Takeoff, f.Source)
+}
@@ -0,0 +1,19 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSafetyFactor(t *testing.T) {
+	f := CAASafetySense
+	if got := f.TakeoffDistance(1500); math.Abs(got - 1995) > 0.001 {
+		t.Errorf("Expected 1995 ft factored takeoff distance, got %.1f", got)
+	}
+	if got := f.LandingDistance(1000); math.Abs(got - 1430) > 0.001 {
+		t.Errorf("Expected 1430 ft factored landing distance, got %.1f", got)
+	}
+	if got, want := f.String(), "×1.33, UK CAA Safety Sense Leaflet 7"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
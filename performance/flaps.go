@@ -0,0 +1,109 @@
+package performance
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldFlapSetting is the validation error field for a flap setting the
+// chart has no correction for
+const FieldFlapSetting = "flap_setting"
+
+// FlapSetting is a flap configuration, named by its degrees as in the POH
+// notes. The zero value is the chart's own configuration.
+type FlapSetting string
+
+// Flap settings with corrections in the POH notes
+const (
+	FlapsCharted FlapSetting = ""   // As charted: 25° for the short field takeoff, 40° for landing
+	Flaps0       FlapSetting = "0"  // Flaps up
+	Flaps25      FlapSetting = "25" // Second notch
+	Flaps40      FlapSetting = "40" // Full flaps
+)
+
+// ParseFlapSetting parses a flap setting in degrees, with or without the
+// degree sign; "up" is 0° and "full" 40°
+func ParseFlapSetting(s string) (FlapSetting, error) {
+	s = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(s)), "°")
+	switch s {
+	case "":
+		return FlapsCharted, nil
+	case "up":
+		return Flaps0, nil
+	case "full":
+		return Flaps40, nil
+	}
+	degrees, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid flap setting %q (0, 25 or 40)", s)
+	}
+	return FlapSetting(strconv.FormatFloat(degrees, 'f', -1, 64)), nil
+}
+
+// Degrees returns the flap setting in degrees, or 0 for FlapsCharted
+func (f FlapSetting) Degrees() float64 {
+	degrees, _ := strconv.ParseFloat(string(f), 64)
+	return degrees
+}
+
+// String formats the flap setting for display, e.g. "flaps 25°"
+func (f FlapSetting) String() string {
+	if f == FlapsCharted {
+		return "charted flaps"
+	}
+	return "flaps " + string(f) + "°"
+}
+
+// flapCorrection is a POH note correcting a chart for another flap setting
+type flapCorrection struct {
+	distance  float64 // Factor on the distances
+	speed     float64 // Knots added to the speeds
+	climbLoss float64 // Rate of climb the flaps cost in fpm, for the go-around
+}
+
+// flapTable lists the flap settings a chart can be corrected for
+type flapTable struct {
+	charted     FlapSetting                    // The chart's own configuration, FlapsCharted if unknown
+	corrections map[FlapSetting]flapCorrection // Corrections from the POH notes, the charted setting's included
+}
+
+// correction returns the correction for a flap setting, and false when the
+// chart has none
+func (t flapTable) correction(f FlapSetting) (flapCorrection, bool) {
+	if f == FlapsCharted {
+		f = t.charted
+	}
+	if c, ok := t.corrections[f]; ok {
+		return c, true
+	}
+	return flapCorrection{distance: 1}, f == t.charted
+}
+
+// validate checks that the chart can be corrected for a flap setting
+func (t flapTable) validate(f FlapSetting, operation string) *ValidationError {
+	if _, ok := t.correction(f); ok {
+		return nil
+	}
+	settings := make([]FlapSetting, 0, len(t.corrections))
+	for s := range t.corrections {
+		settings = append(settings, s)
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Degrees() < settings[j].Degrees()
+	})
+	names := []string{"charted flaps only"}
+	if len(settings) > 0 {
+		names = names[:0]
+		for _, s := range settings {
+			names = append(names, string(s) + "°")
+		}
+	}
+	return &ValidationError{
+		Field:   FieldFlapSetting,
+		Code:    CodeUnsupported,
+		Value:   f.Degrees(),
+		Message: fmt.Sprintf("no %s correction for %s (%s)", operation, f, strings.Join(names, ", ")),
+	}
+}
@@ -0,0 +1,132 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseFlapSetting(t *testing.T) {
+	tests := []struct {
+		in   string
+		want FlapSetting
+	}{
+		{"", FlapsCharted},
+		{"0", Flaps0},
+		{"up", Flaps0},
+		{" 25° ", Flaps25},
+		{"25.0", Flaps25},
+		{"FULL", Flaps40},
+		{"10", FlapSetting("10")},
+	}
+	for _, tc := range tests {
+		if got, err := ParseFlapSetting(tc.in); err != nil || got != tc.want {
+			t.Errorf("ParseFlapSetting(%q) = %q, %v, expected %q", tc.in, got, err, tc.want)
+		}
+	}
+	if _, err := ParseFlapSetting("half"); err == nil {
+		t.Error("Expected an error for a flap setting that is not in degrees")
+	}
+}
+
+func TestTakeoffFlapSetting(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 25, Weight: 2200, WindComponent: 5}
+	charted, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	// The chart is drawn for 25° flaps
+	params.FlapSetting = Flaps25
+	if got, _ := calc.CalculateTakeoff(params); got.TakeoffDistance != charted.TakeoffDistance || got.LiftoffSpeed != charted.LiftoffSpeed {
+		t.Errorf("Expected flaps 25° to be the charted result %+v, got %+v", charted, got)
+	}
+	
+	params.FlapSetting = Flaps0
+	flapsUp, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(flapsUp.TakeoffDistance - charted.TakeoffDistance * 1.15) > 0.01 || math.Abs(flapsUp.GroundRoll - charted.GroundRoll * 1.15) > 0.01 {
+		t.Errorf("Expected the flaps up distances 15%% longer than %+v, got %+v", charted, flapsUp)
+	}
+	if flapsUp.LiftoffSpeed != charted.LiftoffSpeed + 5 || flapsUp.BarrierSpeed != charted.BarrierSpeed + 5 {
+		t.Errorf("Expected the flaps up speeds 5 kts faster than %+v, got %+v", charted, flapsUp)
+	}
+	if got, _ := calc.NewSession(params).Result(); got.TakeoffDistance != flapsUp.TakeoffDistance {
+		t.Errorf("Expected the session to correct for the flaps, got %.0f ft", got.TakeoffDistance)
+	}
+	explanation, _ := calc.Explain(params)
+	if len(explanation.Steps) != 6 || explanation.Steps[2].Value != 1.15 {
+		t.Errorf("Expected a flap correction step, got %+v", explanation.Steps)
+	}
+	
+	// Full flaps are not approved for takeoff
+	params.FlapSetting = Flaps40
+	errs := calc.Validate(params)
+	if len(errs) != 1 || errs[0].Field != FieldFlapSetting || errs[0].Code != CodeUnsupported || errs[0].Value != 40 {
+		t.Errorf("Expected an unsupported flap setting error, got %v", errs)
+	}
+	if _, err := calc.CalculateTakeoff(params); err == nil {
+		t.Error("Expected an error for a flaps 40° takeoff")
+	}
+	
+	// A digitized chart's configuration is unknown, so only its own is accepted
+	chartCalc, err := NewChartTakeoffCalculator(calc.Chart())
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.FlapSetting = FlapsCharted
+	if errs := chartCalc.Validate(params); len(errs) > 0 {
+		t.Errorf("Expected the charted flaps to be valid, got %v", errs)
+	}
+	params.FlapSetting = Flaps0
+	if errs := chartCalc.Validate(params); len(errs) != 1 || errs[0].Field != FieldFlapSetting {
+		t.Errorf("Expected no flap corrections for a digitized chart, got %v", errs)
+	}
+}
+
+func TestLandingFlapSetting(t *testing.T) {
+	calc := NewLandingCalculator()
+	calc.CheckGoAround(NewClimbCalculator())
+	params := LandingParams{PressureAltitude: 0, Temperature: 15, Weight: 2325}
+	full, err := calc.CalculateLanding(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	tests := []struct {
+		flaps    FlapSetting
+		factor   float64
+		approach float64
+		goAround float64
+	}{
+		{Flaps40, 1, 63, 460},
+		{Flaps25, 1.15, 67, 560},
+		{Flaps0, 1.35, 70, 710},
+	}
+	for _, tc := range tests {
+		params.FlapSetting = tc.flaps
+		got, err := calc.CalculateLanding(params)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.flaps, err)
+		}
+		if math.Abs(got.LandingDistance - full.LandingDistance * tc.factor) > 0.01 || math.Abs(got.GroundRoll - full.GroundRoll * tc.factor) > 0.01 {
+			t.Errorf("%s: expected the distances ×%.2f of %+v, got %+v", tc.flaps, tc.factor, full, got)
+		}
+		if got.ApproachSpeed != tc.approach {
+			t.Errorf("%s: expected an approach speed of %.0f KIAS, got %.0f", tc.flaps, tc.approach, got.ApproachSpeed)
+		}
+		if got.GoAround.Speed != tc.approach {
+			t.Errorf("%s: expected the go-around at the approach speed, got %.0f KIAS", tc.flaps, got.GoAround.Speed)
+		}
+		if got.GoAround.RateOfClimb != tc.goAround {
+			t.Errorf("%s: expected a %.0f fpm go-around, got %.0f fpm", tc.flaps, tc.goAround, got.GoAround.RateOfClimb)
+		}
+	}
+	
+	params.FlapSetting = FlapSetting("10")
+	if errs := calc.Validate(params); len(errs) != 1 || errs[0].Field != FieldFlapSetting || errs[0].Message != "no landing correction for flaps 10° (0°, 25°, 40°)" {
+		t.Errorf("Expected an unsupported flap setting error, got %v", errs)
+	}
+}
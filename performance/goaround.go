@@ -0,0 +1,40 @@
+package performance
+
+import (
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+)
+
+// GoAround is the climb a balked landing gives at the landing weight: full
+// power with the landing flaps still down, before they are retracted
+type GoAround struct {
+	DensityAltitude float64 `json:"density_altitude"` // in feet
+	RateOfClimb     float64 `json:"rate_of_climb"`    // in fpm, negative when the aircraft cannot climb
+	Speed           float64 `json:"speed"`            // Balked landing climb speed in KIAS
+	Positive        bool    `json:"positive"`         // Whether the aircraft climbs at all
+}
+
+// CheckGoAround turns on the go-around check, which attaches the balked
+// landing climb to every landing result. It is read from the flaps-up climb
+// chart, with its configuration adjustments, less the rate the landing
+// flaps cost; nil turns the check off.
+func (c *LandingCalculator) CheckGoAround(climb *ClimbCalculator) {
+	c.climb = climb
+}
+
+// goAround estimates the balked landing climb. The climb chart is drawn at
+// the maximum weight, the landing chart's heaviest line; the excess power is
+// taken as unchanged at lighter weights, so the rate rises with the ratio of
+// the weights, which understates the gain.
+func (c *LandingCalculator) goAround(params LandingParams) *GoAround {
+	maxWeight := c.weights[len(c.weights)-1]
+	flaps, _ := c.flaps.correction(params.FlapSetting)
+	rate := c.climb.RateOfClimb(params.PressureAltitude, params.Temperature) * maxWeight / params.Weight - flaps.climbLoss
+	return &GoAround{
+		DensityAltitude: atmosphere.DensityAltitude(math.Max(params.PressureAltitude, 0), params.Temperature),
+		RateOfClimb:     rate,
+		Speed:           c.goAroundSpeed + flaps.speed,
+		Positive:        rate > 0,
+	}
+}
@@ -0,0 +1,105 @@
+package performance
+
+// TakeoffGradient holds the partial derivatives of takeoff distance with
+// respect to each input at a point. The chart model is piecewise linear, so
+// these are exact within a chart cell. On a grid line the derivative of the
+// cell above the point is reported (the cell below at the top edge of the
+// chart), and inputs clamped to the chart edge have a derivative of zero.
+type TakeoffGradient struct {
+	PressureAltitude float64 // Feet of distance per foot of pressure altitude
+	Temperature      float64 // Feet of distance per °C
+	Weight           float64 // Feet of distance per pound
+	WindComponent    float64 // Feet of distance per knot of headwind
+}
+
+// DistanceGradient calculates the partial derivatives of the takeoff distance
+// at the given inputs, for optimizers that need slopes without finite differences
+func (c *TakeoffCalculator) DistanceGradient(params TakeoffParams) (*TakeoffGradient, error) {
+	if err := c.validateInputs(params); err != nil {
+		return nil, err
+	}
+	
+	alt, altSpan := slopeBracket(c.altitudes, params.PressureAltitude)
+	temp, tempSpan := slopeBracket(c.temperatures, params.Temperature)
+	weight, weightSpan := slopeBracket(c.weights, params.Weight)
+	corners := c.baseCorners(alt, temp, weight)
+	
+	// Derivatives of the zero-wind distance, following the same interpolation
+	// order as interpolateCorners: weight, then temperature, then altitude
+	var distances, weightSlopes [2][2]float64
+	for i := 0; i <= 1; i++ {
+		for j := 0; j <= 1; j++ {
+			distances[i][j] = corners[i][j][0] * (1 - weight.frac) + corners[i][j][1] * weight.frac
+			weightSlopes[i][j] = slope(corners[i][j][0], corners[i][j][1], weightSpan)
+		}
+	}
+	
+	var distAlt, tempSlopes, weightSlopesAlt [2]float64
+	for i := 0; i <= 1; i++ {
+		distAlt[i] = distances[i][0] * (1 - temp.frac) + distances[i][1] * temp.frac
+		tempSlopes[i] = slope(distances[i][0], distances[i][1], tempSpan)
+		weightSlopesAlt[i] = weightSlopes[i][0] * (1 - temp.frac) + weightSlopes[i][1] * temp.frac
+	}
+	
+	baseDistance := distAlt[0] * (1 - alt.frac) + distAlt[1] * alt.frac
+	baseGradient := TakeoffGradient{
+		PressureAltitude: slope(distAlt[0], distAlt[1], altSpan),
+		Temperature:      tempSlopes[0] * (1 - alt.frac) + tempSlopes[1] * alt.frac,
+		Weight:           weightSlopesAlt[0] * (1 - alt.frac) + weightSlopesAlt[1] * alt.frac,
+	}
+	
+	// The wind correction, flap correction and configuration adjustments
+	// scale the zero-wind distance
+	factor, factorSlope := c.windFactorAndSlope(params.WindComponent)
+	flaps, _ := c.flaps.correction(params.FlapSetting)
+	adjustment := flaps.distance * adjustmentFactor(c.adjustments)
+	
+	return &TakeoffGradient{
+		PressureAltitude: baseGradient.PressureAltitude * factor * adjustment,
+		Temperature:      baseGradient.Temperature * factor * adjustment,
+		Weight:           baseGradient.Weight * factor * adjustment,
+		WindComponent:    baseDistance * factorSlope * adjustment,
+	}, nil
+}
+
+// windFactorAndSlope returns the wind correction factor at a wind component and
+// its derivative with respect to the wind component (positive for headwind)
+func (c *TakeoffCalculator) windFactorAndSlope(windComponent float64) (float64, float64) {
+	if windComponent >= 0 {
+		b, span := slopeBracket(c.headwinds, windComponent)
+		f1, f2 := c.headwindFactors[b.lo], c.headwindFactors[b.hi]
+		return f1 * (1 - b.frac) + f2 * b.frac, slope(f1, f2, span)
+	}
+	
+	// A stronger tailwind is a more negative wind component
+	b, span := slopeBracket(c.tailwinds, -windComponent)
+	f1, f2 := c.tailwindFactors[b.lo], c.tailwindFactors[b.hi]
+	return f1 * (1 - b.frac) + f2 * b.frac, -slope(f1, f2, span)
+}
+
+// slopeBracket finds the chart cell used for differentiation at a value and
+// the width of that cell along the axis. The width is zero when the value is
+// outside the chart and has been clamped to its edge.
+func slopeBracket(array []float64, value float64) (bracket, float64) {
+	last := len(array) - 1
+	switch {
+	case value < array[0] || value > array[last]:
+		return newBracket(array, value), 0
+	case value == array[last]:
+		return bracket{lo: last - 1, hi: last, frac: 1}, array[last] - array[last-1]
+	case value == array[0]:
+		return bracket{lo: 0, hi: 1, frac: 0}, array[1] - array[0]
+	}
+	
+	b := newBracket(array, value)
+	return b, array[b.hi] - array[b.lo]
+}
+
+// slope returns the rate of change between two values across a cell width,
+// or zero for a clamped (zero-width) cell
+func slope(v1, v2, span float64) float64 {
+	if span == 0 {
+		return 0
+	}
+	return (v2 - v1) / span
+}
@@ -0,0 +1,81 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceGradientMatchesFiniteDifferences(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	
+	// Points chosen away from grid lines so central differences stay within one cell
+	testCases := []TakeoffParams{
+		{PressureAltitude: 1500, Temperature: 26.7, Weight: 2250, WindComponent: 7},
+		{PressureAltitude: 4300, Temperature: -12, Weight: 1710, WindComponent: 0.5},
+		{PressureAltitude: 6800, Temperature: 33, Weight: 2100, WindComponent: -3},
+		{PressureAltitude: 1500, Temperature: 26.7, Weight: 2250, WindComponent: 7, FlapSetting: Flaps0},
+	}
+	
+	distance := func(params TakeoffParams) float64 {
+		result, err := calculator.CalculateTakeoff(params)
+		if err != nil {
+			t.Fatalf("Error calculating takeoff at %+v: %v", params, err)
+		}
+		return result.TakeoffDistance
+	}
+	
+	for _, params := range testCases {
+		gradient, err := calculator.DistanceGradient(params)
+		if err != nil {
+			t.Fatalf("Error calculating gradient at %+v: %v", params, err)
+		}
+		
+		const h = 0.01
+		checks := []struct {
+			name  string
+			got   float64
+			nudge func(p *TakeoffParams, d float64)
+		}{
+			{"altitude", gradient.PressureAltitude, func(p *TakeoffParams, d float64) { p.PressureAltitude += d }},
+			{"temperature", gradient.Temperature, func(p *TakeoffParams, d float64) { p.Temperature += d }},
+			{"weight", gradient.Weight, func(p *TakeoffParams, d float64) { p.Weight += d }},
+			{"wind", gradient.WindComponent, func(p *TakeoffParams, d float64) { p.WindComponent += d }},
+		}
+		
+		for _, check := range checks {
+			up, down := params, params
+			check.nudge(&up, h)
+			check.nudge(&down, -h)
+			want := (distance(up) - distance(down)) / (2 * h)
+			
+			if math.Abs(check.got-want) > 1e-6*math.Max(1, math.Abs(want)) {
+				t.Errorf("%+v: d/d%s got %.6f, expected %.6f", params, check.name, check.got, want)
+			}
+		}
+	}
+}
+
+func TestDistanceGradientAtChartEdges(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	
+	// Below sea level the chart is clamped, so altitude has no effect
+	gradient, err := calculator.DistanceGradient(TakeoffParams{PressureAltitude: -500, Temperature: 15, Weight: 2000})
+	if err != nil {
+		t.Fatalf("Error calculating gradient: %v", err)
+	}
+	if gradient.PressureAltitude != 0 {
+		t.Errorf("Expected zero altitude derivative below sea level, got %.6f", gradient.PressureAltitude)
+	}
+	
+	// At the top edge of each axis the derivative of the last cell is used
+	gradient, err = calculator.DistanceGradient(TakeoffParams{PressureAltitude: 7000, Temperature: 40, Weight: 2325, WindComponent: 15})
+	if err != nil {
+		t.Fatalf("Error calculating gradient: %v", err)
+	}
+	if gradient.PressureAltitude <= 0 || gradient.Temperature <= 0 || gradient.Weight <= 0 {
+		t.Errorf("Expected positive derivatives at the chart maximum, got %+v", *gradient)
+	}
+	if gradient.WindComponent >= 0 {
+		t.Errorf("Expected negative wind derivative (headwind shortens the roll), got %.6f", gradient.WindComponent)
+	}
+}
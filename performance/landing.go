@@ -0,0 +1,352 @@
+package performance
+
+import (
+	"fmt"
+)
+
+// LandingParams represents the input parameters for landing performance calculations
+type LandingParams struct {
+	PressureAltitude float64          `json:"pressure_altitude"`      // in feet
+	Temperature      float64          `json:"temperature_c"`          // in °C
+	Weight           float64          `json:"weight"`                 // in pounds
+	WindComponent    float64          `json:"wind_component"`         // in knots (positive for headwind, negative for tailwind)
+	FlapSetting      FlapSetting      `json:"flap_setting,omitempty"` // Flaps for the landing; empty for the chart's own
+	Technique        LandingTechnique `json:"technique,omitempty"`    // How the landing is flown; empty for the chart's own
+}
+
+// LandingResult contains the calculated landing performance data
+type LandingResult struct {
+	LandingDistance float64 `json:"landing_distance"` // Distance from 50 ft to a stop in feet
+	GroundRoll      float64 `json:"ground_roll"`      // Distance from touchdown to a stop in feet
+	ApproachSpeed   float64 `json:"approach_speed"`   // Approach speed in KIAS
+	
+	// GoAround is the balked landing climb, when the check is on
+	GoAround *GoAround `json:"go_around,omitempty"`
+}
+
+// LandingCalculator handles the PA-28-161 landing performance calculations,
+// for a short field landing with full flaps, power off and maximum braking
+// on a paved, level, dry runway, corrected for other flap settings and for
+// a normal landing
+type LandingCalculator struct {
+	// These arrays define the data points on the chart
+	altitudes       []float64   // Pressure altitude in feet
+	temperatures    []float64   // Temperature in °C
+	weights         []float64   // Weight in pounds
+	headwinds       []float64   // Headwind in knots
+	tailwinds       []float64   // Tailwind in knots
+	headwindFactors []float64   // Distance factor at each headwind
+	tailwindFactors []float64   // Distance factor at each tailwind
+	distances       [][]float64 // Distance over 50 ft with no wind
+	groundRolls     [][]float64 // Ground roll with no wind
+	approachSpeed   float64     // Approach speed in KIAS
+	
+	flaps         flapTable        // Corrections for other flap settings
+	techniques    techniqueTable   // Corrections for other landing techniques
+	goAroundSpeed float64          // Balked landing climb speed in KIAS
+	climb         *ClimbCalculator // Flaps-up climb chart for the go-around check; nil leaves it off
+}
+
+var _ Calculator[LandingParams, *LandingResult] = (*LandingCalculator)(nil)
+
+// NewLandingCalculator creates a new landing performance calculator
+func NewLandingCalculator() *LandingCalculator {
+	return &LandingCalculator{
+		// Digitized from Figure 5-11, full flaps, power off, maximum braking
+		altitudes:       []float64{0, 1000, 2000, 3000, 4000, 5000, 6000, 7000},
+		temperatures:    []float64{-40, -20, 0, 20, 40},
+		weights:         []float64{1600, 1800, 2000, 2200, 2325},
+		headwinds:       []float64{0, 5, 10, 15},
+		tailwinds:       []float64{0, 5},
+		headwindFactors: []float64{1, 0.92, 0.84, 0.76},
+		tailwindFactors: []float64{1, 1.15},
+		approachSpeed:   63,
+		
+		// The chart is drawn for full flaps; the POH notes correct a
+		// partial flap or no-flap landing, flown faster and floating
+		// further. The climb loss is the rate of climb the flaps cost in a
+		// balked landing, at full power before they are retracted.
+		flaps: flapTable{
+			charted: Flaps40,
+			corrections: map[FlapSetting]flapCorrection{
+				Flaps40: {distance: 1, climbLoss: 250},
+				Flaps25: {distance: 1.15, speed: 4, climbLoss: 150},
+				Flaps0:  {distance: 1.35, speed: 7},
+			},
+		},
+		
+		// The chart is drawn for the maximum effort technique that many
+		// pilots do not fly day to day. A normal landing is flown about 7 kt
+		// faster, which floats further, (70/63)² of the air distance, and
+		// stops with moderate braking, taken as 40% more ground roll. These
+		// are rules of thumb, not POH figures.
+		techniques: techniqueTable{
+			charted: TechniqueShortField,
+			corrections: map[LandingTechnique]techniqueCorrection{
+				TechniqueShortField: {airDistance: 1, groundRoll: 1},
+				TechniqueNormal:     {airDistance: 1.25, groundRoll: 1.4, speed: 7},
+			},
+		},
+		goAroundSpeed: 63,
+		
+		// [altitude][weight × temperature], each row a weight and each
+		// column a temperature, as in the takeoff chart
+		distances: [][]float64{
+			// -40°C -20°C  0°C  20°C  40°C  (temperatures)
+			{
+				750, 815, 875, 940, 1005,    // 0 ft, 1600 lbs
+				795, 860, 930, 1000, 1065,   // 1800 lbs
+				835, 910, 980, 1050, 1125,   // 2000 lbs
+				880, 955, 1030, 1105, 1180,  // 2200 lbs
+				900, 980, 1055, 1135, 1210,  // 2325 lbs
+			},
+			{
+				775, 845, 910, 975, 1040,    // 1000 ft
+				825, 895, 965, 1035, 1105,
+				870, 940, 1015, 1090, 1165,
+				910, 990, 1065, 1145, 1220,
+				935, 1015, 1095, 1175, 1255,
+			},
+			{
+				805, 875, 945, 1010, 1080,   // 2000 ft
+				855, 925, 1000, 1075, 1145,
+				900, 975, 1055, 1130, 1210,
+				945, 1025, 1105, 1185, 1270,
+				970, 1055, 1135, 1220, 1305,
+			},
+			{
+				835, 905, 980, 1050, 1120,   // 3000 ft
+				885, 960, 1040, 1115, 1190,
+				935, 1015, 1095, 1175, 1255,
+				980, 1065, 1145, 1230, 1315,
+				1005, 1095, 1180, 1265, 1350,
+			},
+			{
+				865, 940, 1015, 1090, 1165,  // 4000 ft
+				920, 1000, 1075, 1155, 1235,
+				970, 1050, 1135, 1220, 1300,
+				1015, 1105, 1190, 1280, 1365,
+				1045, 1135, 1225, 1315, 1405,
+			},
+			{
+				900, 975, 1055, 1130, 1210,  // 5000 ft
+				955, 1035, 1120, 1200, 1280,
+				1005, 1090, 1180, 1265, 1350,
+				1055, 1145, 1235, 1325, 1415,
+				1085, 1175, 1270, 1365, 1455,
+			},
+			{
+				935, 1015, 1095, 1175, 1255, // 6000 ft
+				990, 1075, 1160, 1245, 1330,
+				1045, 1135, 1225, 1315, 1400,
+				1095, 1190, 1285, 1375, 1470,
+				1125, 1220, 1320, 1415, 1510,
+			},
+			{
+				970, 1055, 1135, 1220, 1305, // 7000 ft
+				1030, 1115, 1205, 1295, 1380,
+				1085, 1175, 1270, 1365, 1455,
+				1135, 1235, 1330, 1430, 1530,
+				1170, 1270, 1370, 1470, 1570,
+			},
+		},
+		groundRolls: [][]float64{
+			// -40°C -20°C  0°C  20°C  40°C  (temperatures)
+			{
+				355, 390, 420, 450, 480, // 0 ft, 1600 lbs
+				390, 425, 460, 495, 525, // 1800 lbs
+				425, 465, 500, 535, 575, // 2000 lbs
+				460, 500, 540, 580, 620, // 2200 lbs
+				480, 525, 565, 605, 645, // 2325 lbs
+			},
+			{
+				370, 400, 435, 465, 495, // 1000 ft
+				405, 440, 475, 510, 545,
+				445, 480, 520, 555, 595,
+				480, 520, 560, 600, 640,
+				500, 540, 585, 630, 670,
+			},
+			{
+				385, 415, 450, 485, 515, // 2000 ft
+				420, 460, 495, 530, 565,
+				460, 500, 540, 575, 615,
+				495, 540, 580, 625, 665,
+				520, 560, 605, 650, 695,
+			},
+			{
+				400, 435, 465, 500, 535, // 3000 ft
+				440, 475, 515, 550, 590,
+				475, 515, 560, 600, 640,
+				515, 560, 600, 645, 690,
+				535, 585, 630, 675, 720,
+			},
+			{
+				415, 450, 485, 520, 555, // 4000 ft
+				455, 495, 530, 570, 610,
+				495, 535, 580, 620, 665,
+				535, 580, 625, 670, 715,
+				555, 605, 655, 700, 750,
+			},
+			{
+				430, 465, 505, 540, 575, // 5000 ft
+				470, 510, 550, 595, 635,
+				515, 555, 600, 645, 690,
+				555, 600, 650, 695, 745,
+				580, 630, 680, 730, 775,
+			},
+			{
+				445, 485, 520, 560, 600, // 6000 ft
+				490, 530, 575, 615, 655,
+				535, 580, 625, 670, 715,
+				575, 625, 675, 725, 770,
+				600, 650, 705, 755, 805,
+			},
+			{
+				465, 500, 540, 580, 620, // 7000 ft
+				510, 550, 595, 640, 685,
+				555, 600, 650, 695, 745,
+				595, 650, 700, 750, 800,
+				625, 675, 730, 785, 840,
+			},
+		},
+	}
+}
+
+// CalculateLanding calculates landing performance based on the input parameters
+func (c *LandingCalculator) CalculateLanding(params LandingParams) (*LandingResult, error) {
+	if errs := c.Validate(params); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	
+	flaps, _ := c.flaps.correction(params.FlapSetting)
+	technique, _ := c.techniques.correction(params.Technique)
+	factor := c.windFactor(params.WindComponent) * flaps.distance
+	distance := c.lookup(c.distances, params) * factor
+	roll := c.lookup(c.groundRolls, params) * factor
+	result := &LandingResult{
+		LandingDistance: (distance - roll) * technique.airDistance + roll * technique.groundRoll,
+		GroundRoll:      roll * technique.groundRoll,
+		ApproachSpeed:   c.approachSpeed + flaps.speed + technique.speed,
+	}
+	if c.climb != nil {
+		result.GoAround = c.goAround(params)
+	}
+	return result, nil
+}
+
+// Validate checks every input parameter against the chart limits and returns
+// all violations found, or nil if the parameters are within the envelope
+func (c *LandingCalculator) Validate(params LandingParams) ValidationErrors {
+	errs := validateChartInputs(c.altitudes, c.temperatures, c.weights, c.headwinds, c.tailwinds,
+		params.PressureAltitude, params.Temperature, params.Weight, params.WindComponent)
+	if err := c.flaps.validate(params.FlapSetting, "landing"); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.techniques.validate(params.Technique); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Calculate implements Calculator, and is equivalent to CalculateLanding
+func (c *LandingCalculator) Calculate(params LandingParams) (*LandingResult, error) {
+	return c.CalculateLanding(params)
+}
+
+// Envelope returns the charted range of each landing input
+func (c *LandingCalculator) Envelope() Envelope {
+	return Envelope{
+		{Field: FieldPressureAltitude, Unit: "ft", Min: c.altitudes[0], Max: c.altitudes[len(c.altitudes)-1]},
+		{Field: FieldTemperature, Unit: "°C", Min: c.temperatures[0], Max: c.temperatures[len(c.temperatures)-1]},
+		{Field: FieldWeight, Unit: "lbs", Min: c.weights[0], Max: c.weights[len(c.weights)-1]},
+		{Field: FieldWindComponent, Unit: "kts", Min: -c.tailwinds[len(c.tailwinds)-1], Max: c.headwinds[len(c.headwinds)-1]},
+	}
+}
+
+// Source identifies the landing chart
+func (c *LandingCalculator) Source() Source {
+	return Source{
+		Aircraft: "PA-28-161 Cherokee Warrior II",
+		Document: "Pilot's Operating Handbook",
+		Figure:   "5-11",
+		Title:    "Landing Distance",
+	}
+}
+
+// Explain lists the chart lookups, wind, flap and technique corrections behind a landing result
+func (c *LandingCalculator) Explain(params LandingParams) (*Explanation, error) {
+	result, err := c.CalculateLanding(params)
+	if err != nil {
+		return nil, err
+	}
+	
+	alt := newBracket(c.altitudes, params.PressureAltitude)
+	temp := newBracket(c.temperatures, params.Temperature)
+	weight := newBracket(c.weights, params.Weight)
+	cell := fmt.Sprintf("altitude %s ft, temperature %s °C, weight %s lbs",
+		describeBracket(c.altitudes, alt), describeBracket(c.temperatures, temp), describeBracket(c.weights, weight))
+	
+	var wind string
+	switch {
+	case params.WindComponent > 0:
+		wind = fmt.Sprintf("%.0f kts headwind", params.WindComponent)
+	case params.WindComponent < 0:
+		wind = fmt.Sprintf("%.0f kts tailwind", -params.WindComponent)
+	default:
+		wind = "no wind"
+	}
+	
+	steps := []Step{
+		{Description: "Zero-wind distance over 50 ft interpolated between " + cell, Value: c.lookup(c.distances, params), Unit: "ft"},
+		{Description: "Zero-wind ground roll interpolated between " + cell, Value: c.lookup(c.groundRolls, params), Unit: "ft"},
+		{Description: "Wind correction factor for " + wind, Value: c.windFactor(params.WindComponent)},
+	}
+	flaps, _ := c.flaps.correction(params.FlapSetting)
+	if flaps.distance != 1 {
+		steps = append(steps, Step{Description: "Flap correction factor for " + params.FlapSetting.String(), Value: flaps.distance})
+	}
+	if technique, _ := c.techniques.correction(params.Technique); technique.airDistance != 1 || technique.groundRoll != 1 {
+		steps = append(steps,
+			Step{Description: "Air distance factor for the " + params.Technique.String(), Value: technique.airDistance},
+			Step{Description: "Ground roll factor for the " + params.Technique.String(), Value: technique.groundRoll},
+		)
+	}
+	steps = append(steps,
+		Step{Description: "Landing distance over 50 ft barrier", Value: result.LandingDistance, Unit: "ft"},
+		Step{Description: "Ground roll", Value: result.GroundRoll, Unit: "ft"},
+		Step{Description: "Approach speed", Value: result.ApproachSpeed, Unit: "KIAS"},
+	)
+	if g := result.GoAround; g != nil {
+		steps = append(steps,
+			Step{Description: "Density altitude for the go-around", Value: g.DensityAltitude, Unit: "ft"},
+			Step{Description: "Flaps-up rate of climb at maximum weight", Value: c.climb.RateOfClimb(params.PressureAltitude, params.Temperature), Unit: "fpm"},
+			Step{Description: fmt.Sprintf("Go-around rate of climb at %.0f lbs, less %.0f fpm for the landing flaps", params.Weight, flaps.climbLoss), Value: g.RateOfClimb, Unit: "fpm"},
+		)
+	}
+	return &Explanation{Source: c.Source(), Steps: steps}, nil
+}
+
+// lookup interpolates a zero-wind distance table at the inputs
+func (c *LandingCalculator) lookup(table [][]float64, params LandingParams) float64 {
+	alt := newBracket(c.altitudes, params.PressureAltitude)
+	temp := newBracket(c.temperatures, params.Temperature)
+	weight := newBracket(c.weights, params.Weight)
+	
+	var corners [2][2][2]float64
+	for i, a := range [2]int{alt.lo, alt.hi} {
+		for j, t := range [2]int{temp.lo, temp.hi} {
+			for k, w := range [2]int{weight.lo, weight.hi} {
+				corners[i][j][k] = table[a][w*len(c.temperatures) + t]
+			}
+		}
+	}
+	return interpolateCorners(corners, alt, temp, weight)
+}
+
+// windFactor returns the distance correction factor for a wind component
+func (c *LandingCalculator) windFactor(windComponent float64) float64 {
+	if windComponent >= 0 {
+		return interpolate(c.headwindFactors, newBracket(c.headwinds, windComponent))
+	}
+	return interpolate(c.tailwindFactors, newBracket(c.tailwinds, -windComponent))
+}
@@ -0,0 +1,172 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLandingPerformance(t *testing.T) {
+	calculator := NewLandingCalculator()
+	
+	testCases := []struct {
+		name               string
+		params             LandingParams
+		expectedDistance   float64
+		expectedGroundRoll float64
+	}{
+		{
+			name:               "Sea Level Standard Day",
+			params:             LandingParams{PressureAltitude: 0, Temperature: 15, Weight: 2325},
+			expectedDistance:   1115,
+			expectedGroundRoll: 595,
+		},
+		{
+			name:               "On the Chart Lines",
+			params:             LandingParams{PressureAltitude: 4000, Temperature: 20, Weight: 2000},
+			expectedDistance:   1220,
+			expectedGroundRoll: 620,
+		},
+		{
+			name:               "Headwind",
+			params:             LandingParams{PressureAltitude: 1500, Temperature: ConvertFahrenheitToCelsius(80), Weight: 2325, WindComponent: 10},
+			expectedDistance:   1225 * 0.84,
+			expectedGroundRoll: 654.2 * 0.84,
+		},
+		{
+			name:               "Tailwind",
+			params:             LandingParams{PressureAltitude: 0, Temperature: 15, Weight: 2325, WindComponent: -5},
+			expectedDistance:   1115 * 1.15,
+			expectedGroundRoll: 595 * 1.15,
+		},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := calculator.CalculateLanding(tc.params)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(result.LandingDistance - tc.expectedDistance) > 1 {
+				t.Errorf("Expected landing distance %.0f ft, got %.1f ft", tc.expectedDistance, result.LandingDistance)
+			}
+			if math.Abs(result.GroundRoll - tc.expectedGroundRoll) > 1 {
+				t.Errorf("Expected ground roll %.0f ft, got %.1f ft", tc.expectedGroundRoll, result.GroundRoll)
+			}
+			if result.GroundRoll >= result.LandingDistance {
+				t.Errorf("Expected the ground roll %.0f ft to be shorter than the landing distance %.0f ft", result.GroundRoll, result.LandingDistance)
+			}
+			if result.ApproachSpeed != 63 {
+				t.Errorf("Expected an approach speed of 63 KIAS, got %.0f", result.ApproachSpeed)
+			}
+		})
+	}
+}
+
+func TestLandingChartIsMonotonic(t *testing.T) {
+	c := NewLandingCalculator()
+	for _, table := range [][][]float64{c.distances, c.groundRolls} {
+		for a := range c.altitudes {
+			for w := range c.weights {
+				for i := range c.temperatures {
+					v := table[a][w*len(c.temperatures) + i]
+					if i > 0 && v <= table[a][w*len(c.temperatures) + i-1] {
+						t.Errorf("Distance must increase with temperature at %.0f ft, %.0f lbs, %.0f°C", c.altitudes[a], c.weights[w], c.temperatures[i])
+					}
+					if w > 0 && v <= table[a][(w-1)*len(c.temperatures) + i] {
+						t.Errorf("Distance must increase with weight at %.0f ft, %.0f lbs, %.0f°C", c.altitudes[a], c.weights[w], c.temperatures[i])
+					}
+					if a > 0 && v <= table[a-1][w*len(c.temperatures) + i] {
+						t.Errorf("Distance must increase with altitude at %.0f ft, %.0f lbs, %.0f°C", c.altitudes[a], c.weights[w], c.temperatures[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLandingValidation(t *testing.T) {
+	calculator := NewLandingCalculator()
+	
+	errs := calculator.Validate(LandingParams{PressureAltitude: 8000, Temperature: 45, Weight: 2400, WindComponent: -10})
+	fields := map[string]bool{}
+	for _, err := range errs {
+		fields[err.Field] = true
+	}
+	for _, field := range []string{FieldPressureAltitude, FieldTemperature, FieldWeight, FieldWindComponent} {
+		if !fields[field] {
+			t.Errorf("Expected a %s error in %v", field, errs)
+		}
+	}
+	
+	if _, err := calculator.CalculateLanding(LandingParams{Weight: 2400}); err == nil {
+		t.Error("Expected an error above the maximum weight")
+	}
+	if errs := calculator.Validate(LandingParams{PressureAltitude: -200, Temperature: 15, Weight: 2325}); len(errs) > 0 {
+		t.Errorf("Expected sea level values below sea level, got %v", errs)
+	}
+}
+
+func TestLandingExplain(t *testing.T) {
+	calculator := NewLandingCalculator()
+	params := LandingParams{PressureAltitude: 1500, Temperature: 25, Weight: 2200, WindComponent: 5}
+	explanation, err := calculator.Explain(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _ := calculator.CalculateLanding(params)
+	if explanation.Source.Figure != "5-11" {
+		t.Errorf("Unexpected source %+v", explanation.Source)
+	}
+	steps := explanation.Steps
+	if len(steps) != 6 || steps[0].Value * steps[2].Value != result.LandingDistance || steps[4].Value != result.GroundRoll {
+		t.Errorf("Steps do not lead to the result %+v: %+v", result, steps)
+	}
+}
+
+func TestGoAround(t *testing.T) {
+	calculator := NewLandingCalculator()
+	params := LandingParams{PressureAltitude: 0, Temperature: 15, Weight: 2325}
+	if result, _ := calculator.CalculateLanding(params); result.GoAround != nil {
+		t.Errorf("Expected no go-around check unless turned on, got %+v", result.GoAround)
+	}
+	
+	calculator.CheckGoAround(NewClimbCalculator())
+	result, err := calculator.CalculateLanding(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 710 fpm flaps up at sea level, less 250 fpm for the flaps
+	if g := result.GoAround; g == nil || g.RateOfClimb != 460 || !g.Positive || g.DensityAltitude != 0 || g.Speed != 63 {
+		t.Errorf("Expected a 460 fpm go-around at sea level, got %+v", g)
+	}
+	
+	params.Weight = 1900
+	if lighter, _ := calculator.CalculateLanding(params); lighter.GoAround.RateOfClimb <= result.GoAround.RateOfClimb {
+		t.Errorf("Expected a better go-around at 1900 lbs than %.0f fpm, got %.0f fpm", result.GoAround.RateOfClimb, lighter.GoAround.RateOfClimb)
+	}
+	
+	hot := LandingParams{PressureAltitude: 7000, Temperature: 40, Weight: 2325}
+	result, err = calculator.CalculateLanding(hot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := result.GoAround; g.Positive || g.RateOfClimb >= 0 {
+		t.Errorf("Expected no climb with full flaps at %.0f ft density altitude, got %+v", g.DensityAltitude, g)
+	}
+	
+	explanation, err := calculator.Explain(hot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps := explanation.Steps; len(steps) != 9 || steps[8].Value != result.GoAround.RateOfClimb {
+		t.Errorf("Expected the go-around steps to lead to %.0f fpm, got %+v", result.GoAround.RateOfClimb, steps)
+	}
+	
+	// The climb chart's configuration adjustments carry over
+	climb := NewClimbCalculator()
+	climb.AdjustRateOfClimb(Adjustment{Description: "Floats installed", Factor: -0.2})
+	calculator.CheckGoAround(climb)
+	if adjusted, _ := calculator.CalculateLanding(LandingParams{Temperature: 15, Weight: 2325}); adjusted.GoAround.RateOfClimb != 710 * 0.8 - 250 {
+		t.Errorf("Expected the adjusted climb rate less the flaps, got %.0f fpm", adjusted.GoAround.RateOfClimb)
+	}
+}
@@ -0,0 +1,63 @@
+package performance
+
+import (
+	"math"
+	
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// groundRollFraction is the ground roll as a fraction of the distance over
+// 50 ft, typical of the PA-28 short-field charts, used to time the roll on
+// charts digitized without their ground roll
+const groundRollFraction = 0.6
+
+// feetPerSecondPerKnot converts knots to feet per second
+const feetPerSecondPerKnot = units.MetersPerNauticalMile / units.MetersPerFoot / 3600
+
+// RollTiming estimates the ground roll from brake release to liftoff at a
+// constant acceleration, for timing the roll and placing the abort point
+type RollTiming struct {
+	Distance            float64 `json:"distance"`              // Ground roll in feet, charted or estimated
+	Time                float64 `json:"time"`                  // Seconds from brake release to liftoff
+	Acceleration        float64 `json:"acceleration"`          // Average, in knots of groundspeed per second
+	LiftoffTrueAirspeed float64 `json:"liftoff_true_airspeed"` // in KTAS
+	LiftoffGroundspeed  float64 `json:"liftoff_groundspeed"`   // in knots, after the wind component
+}
+
+// rollTiming estimates the roll to the calibrated liftoff speed, made true
+// for the density. The acceleration is the one that reaches it over the
+// calm-air ground roll, since the wind does not change the thrust, and the
+// headwind takes its part off the groundspeed to be reached while a
+// tailwind adds to it. The distance is the wind-corrected ground roll,
+// which the chart corrects more cautiously than the groundspeed alone
+// would. Returns nil when the aircraft would be airborne standing still.
+func rollTiming(params TakeoffParams, calmRoll, roll, liftoffSpeed float64) *RollTiming {
+	trueAirspeed := TrueAirspeed(liftoffSpeed, math.Max(params.PressureAltitude, 0), params.Temperature)
+	groundspeed := trueAirspeed - params.WindComponent
+	if groundspeed <= 0 || calmRoll <= 0 {
+		return nil
+	}
+	
+	// Constant acceleration from rest: d = v t / 2
+	calmTime := 2 * calmRoll / (trueAirspeed * feetPerSecondPerKnot)
+	acceleration := trueAirspeed / calmTime
+	return &RollTiming{
+		Distance:            roll,
+		Time:                groundspeed / acceleration,
+		Acceleration:        acceleration,
+		LiftoffTrueAirspeed: trueAirspeed,
+		LiftoffGroundspeed:  groundspeed,
+	}
+}
+
+// TimeToAirspeed returns the seconds into the roll at which a fraction of
+// the liftoff airspeed is reached, such as an abort speed. The wind gives
+// airspeed before the wheels roll, so it is zero for a fraction the wind
+// alone makes good.
+func (r *RollTiming) TimeToAirspeed(fraction float64) float64 {
+	groundspeed := fraction * r.LiftoffTrueAirspeed - (r.LiftoffTrueAirspeed - r.LiftoffGroundspeed)
+	if groundspeed <= 0 {
+		return 0
+	}
+	return groundspeed / r.Acceleration
+}
@@ -0,0 +1,110 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRollTiming(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	calm, err := calc.CalculateTakeoff(TakeoffParams{PressureAltitude: 0, Temperature: 15, Weight: 2325})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roll := calm.Roll
+	if roll == nil {
+		t.Fatal("Expected a roll estimate")
+	}
+	if roll.Distance != calm.GroundRoll {
+		t.Errorf("Expected the charted ground roll %.0f ft, got %.0f ft", calm.GroundRoll, roll.Distance)
+	}
+	// In calm air, constant acceleration from rest covers half the liftoff groundspeed times the time
+	if covered := roll.LiftoffGroundspeed * feetPerSecondPerKnot * roll.Time / 2; math.Abs(covered - roll.Distance) > 0.001 {
+		t.Errorf("Expected %.0f ft covered in %.1f s, got %.0f ft", roll.Distance, roll.Time, covered)
+	}
+	if roll.Time < 15 || roll.Time > 40 {
+		t.Errorf("Expected a roll of 15 to 40 s, got %.1f s", roll.Time)
+	}
+	if got := roll.TimeToAirspeed(1); math.Abs(got - roll.Time) > 0.001 {
+		t.Errorf("Expected liftoff speed at %.1f s, got %.1f s", roll.Time, got)
+	}
+	
+	windy, err := calc.CalculateTakeoff(TakeoffParams{PressureAltitude: 0, Temperature: 15, Weight: 2325, WindComponent: 15})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if windy.Roll.Time >= roll.Time || windy.Roll.Acceleration != roll.Acceleration {
+		t.Errorf("Expected a headwind to shorten the roll at the same acceleration, got %.1f s at %.2f kt/s against %.1f s at %.2f kt/s calm",
+			windy.Roll.Time, windy.Roll.Acceleration, roll.Time, roll.Acceleration)
+	}
+	if got := windy.Roll.TimeToAirspeed(0.2); got != 0 {
+		t.Errorf("Expected the headwind alone to give 20%% of the liftoff speed, got %.1f s", got)
+	}
+	
+	if r := rollTiming(TakeoffParams{Temperature: 15, WindComponent: 60}, 1000, 1000, 50); r != nil {
+		t.Errorf("Expected no roll when the wind exceeds the liftoff speed, got %+v", r)
+	}
+}
+
+func TestGroundRoll(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 0, Temperature: 20, Weight: 2325}
+	calm, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// On the chart lines: 1170 ft of the 2000 ft over 50 ft
+	if calm.GroundRoll != 1170 || calm.TakeoffDistance != 2000 {
+		t.Errorf("Expected a ground roll of 1170 ft of 2000 ft, got %.0f ft of %.0f ft", calm.GroundRoll, calm.TakeoffDistance)
+	}
+	
+	params.WindComponent = 10
+	windy, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := calm.GroundRoll * windy.TakeoffDistance / calm.TakeoffDistance; math.Abs(windy.GroundRoll - want) > 0.001 {
+		t.Errorf("Expected the wind to correct the ground roll as the distance, to %.0f ft, got %.0f ft", want, windy.GroundRoll)
+	}
+	
+	// A digitized chart without its ground roll estimates the roll for timing only
+	chartCalc, err := NewChartTakeoffCalculator(calc.Chart())
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimated, err := chartCalc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimated.GroundRoll != 0 || math.Abs(estimated.Roll.Distance - estimated.TakeoffDistance * groundRollFraction) > 0.001 {
+		t.Errorf("Expected no charted ground roll and a %.0f%% estimate, got %.0f ft and %.0f ft",
+			groundRollFraction * 100, estimated.GroundRoll, estimated.Roll.Distance)
+	}
+	
+	// A ground roll chart read alongside it
+	rollChart := calc.Chart()
+	for _, row := range rollChart.Distances {
+		for i := range row {
+			row[i] /= 2
+		}
+	}
+	withRoll, err := chartCalc.WithGroundRollChart(rollChart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := withRoll.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.GroundRoll - got.TakeoffDistance / 2) > 0.001 || got.Roll.Distance != got.GroundRoll {
+		t.Errorf("Expected the ground roll from the chart, half of %.0f ft, got %.0f ft", got.TakeoffDistance, got.GroundRoll)
+	}
+	if again, _ := chartCalc.CalculateTakeoff(params); again.GroundRoll != 0 {
+		t.Errorf("Expected the original calculator unchanged, got a ground roll of %.0f ft", again.GroundRoll)
+	}
+	
+	rollChart.Weights = rollChart.Weights[1:]
+	if _, err := chartCalc.WithGroundRollChart(rollChart); err == nil {
+		t.Error("Expected an error for a ground roll chart with other weight lines")
+	}
+}
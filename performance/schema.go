@@ -0,0 +1,147 @@
+package performance
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// schemaFiles holds the published JSON Schemas for the param and result types
+//
+//go:embed schema/*.schema.json
+var schemaFiles embed.FS
+
+// Schema returns the JSON Schema document with the given name, e.g. "takeoff_params"
+func Schema(name string) ([]byte, error) {
+	data, err := schemaFiles.ReadFile("schema/" + name + ".schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema %q", name)
+	}
+	return data, nil
+}
+
+// SchemaNames lists the names of the published JSON Schemas
+func SchemaNames() []string {
+	entries, _ := schemaFiles.ReadDir("schema")
+	
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".schema.json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnmarshalJSON decodes takeoff parameters, rejecting documents with missing
+// or unknown fields so that values are never read in the wrong unit
+func (p *TakeoffParams) UnmarshalJSON(data []byte) error {
+	type plain TakeoffParams
+	return decodeStrict(data, (*plain)(p),
+		"pressure_altitude", "temperature_c", "weight", "wind_component")
+}
+
+// UnmarshalJSON decodes a takeoff result, rejecting missing or unknown fields
+func (r *TakeoffResult) UnmarshalJSON(data []byte) error {
+	type plain TakeoffResult
+	return decodeStrict(data, (*plain)(r),
+		"takeoff_distance", "liftoff_speed", "barrier_speed")
+}
+
+// UnmarshalJSON decodes landing parameters, rejecting missing or unknown fields
+func (p *LandingParams) UnmarshalJSON(data []byte) error {
+	type plain LandingParams
+	return decodeStrict(data, (*plain)(p),
+		"pressure_altitude", "temperature_c", "weight", "wind_component")
+}
+
+// UnmarshalJSON decodes a landing result, rejecting missing or unknown fields
+func (r *LandingResult) UnmarshalJSON(data []byte) error {
+	type plain LandingResult
+	return decodeStrict(data, (*plain)(r),
+		"landing_distance", "ground_roll", "approach_speed")
+}
+
+// UnmarshalJSON decodes a reverse solver result, rejecting missing or unknown fields
+func (r *ReverseResult) UnmarshalJSON(data []byte) error {
+	type plain ReverseResult
+	return decodeStrict(data, (*plain)(r),
+		"value", "takeoff_distance", "limit")
+}
+
+// UnmarshalJSON decodes climb parameters, rejecting missing or unknown fields
+func (p *ClimbParams) UnmarshalJSON(data []byte) error {
+	type plain ClimbParams
+	return decodeStrict(data, (*plain)(p),
+		"pressure_altitude", "temperature_c", "cruise_altitude")
+}
+
+// UnmarshalJSON decodes a climb result, rejecting missing or unknown fields
+func (r *ClimbResult) UnmarshalJSON(data []byte) error {
+	type plain ClimbResult
+	return decodeStrict(data, (*plain)(r),
+		"time", "fuel", "distance", "rows")
+}
+
+// UnmarshalJSON decodes cruise parameters, rejecting missing or unknown fields
+func (p *CruiseParams) UnmarshalJSON(data []byte) error {
+	type plain CruiseParams
+	return decodeStrict(data, (*plain)(p),
+		"pressure_altitude", "temperature_c", "power")
+}
+
+// UnmarshalJSON decodes a cruise result, rejecting missing or unknown fields
+func (r *CruiseResult) UnmarshalJSON(data []byte) error {
+	type plain CruiseResult
+	return decodeStrict(data, (*plain)(r),
+		"power", "rpm", "true_airspeed", "fuel_flow", "density_altitude")
+}
+
+// MarshalText encodes the limit as "runway" or "chart"
+func (l SolverLimit) MarshalText() ([]byte, error) {
+	switch l {
+	case LimitRunway:
+		return []byte("runway"), nil
+	case LimitChart:
+		return []byte("chart"), nil
+	default:
+		return nil, fmt.Errorf("unknown solver limit %d", int(l))
+	}
+}
+
+// UnmarshalText decodes a limit encoded by MarshalText
+func (l *SolverLimit) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "runway":
+		*l = LimitRunway
+	case "chart":
+		*l = LimitChart
+	default:
+		return fmt.Errorf("unknown solver limit %q", text)
+	}
+	return nil
+}
+
+// decodeStrict decodes a JSON object into v, requiring each of the named
+// fields and rejecting fields v does not declare
+func decodeStrict(data []byte, v interface{}, required ...string) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, name := range required {
+		if _, ok := fields[name]; !ok {
+			return fmt.Errorf("missing field %q", name)
+		}
+	}
+	
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
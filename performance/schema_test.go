@@ -0,0 +1,189 @@
+package performance
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+)
+
+// schemaObject is the part of a JSON Schema object definition the tests check
+type schemaObject struct {
+	Type       string                     `json:"type"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+	Items      *schemaObject              `json:"items"`
+}
+
+// TestSchemasMatchEncoding checks that every published schema lists exactly
+// the fields its Go type encodes
+func TestSchemasMatchEncoding(t *testing.T) {
+	// Adjust the calculators so that optional fields are encoded too
+	calc := NewTakeoffCalculator()
+	calc.AdjustDistance(Adjustment{Description: "Skis installed", Factor: 0.2})
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 26.7, Weight: 2325, WindComponent: 15, FlapSetting: Flaps25}
+	result, _ := calc.CalculateTakeoff(params)
+	field := atmosphere.NewField(1500, 29.92, 26.7)
+	result.Atmosphere = &field
+	reverse, _ := calc.MaxWeight(params, 2000)
+	explanation, _ := calc.Explain(params)
+	validation := calc.Validate(TakeoffParams{Weight: 3000})
+	climbParams := ClimbParams{PressureAltitude: 500, Temperature: 20, CruiseAltitude: 6500}
+	climbCalc := NewClimbCalculator()
+	climbCalc.AdjustRateOfClimb(Adjustment{Description: "Skis installed", Factor: -0.1})
+	climb, _ := climbCalc.CalculateClimb(climbParams)
+	cruiseParams := CruiseParams{PressureAltitude: 6500, Temperature: 2, Power: 65}
+	cruise, _ := NewCruiseCalculator().CalculateCruise(cruiseParams)
+	landingParams := LandingParams{PressureAltitude: 1500, Temperature: 26.7, Weight: 2200, WindComponent: 5, FlapSetting: Flaps25, Technique: TechniqueNormal}
+	landingCalc := NewLandingCalculator()
+	landingCalc.CheckGoAround(climbCalc)
+	landing, _ := landingCalc.CalculateLanding(landingParams)
+	
+	values := map[string]interface{}{
+		"takeoff_params":    params,
+		"takeoff_result":    result,
+		"reverse_result":    reverse,
+		"validation_errors": validation,
+		"envelope":          calc.Envelope(),
+		"explanation":       explanation,
+		"climb_params":      climbParams,
+		"climb_result":      climb,
+		"cruise_params":     cruiseParams,
+		"cruise_result":     cruise,
+		"landing_params":    landingParams,
+		"landing_result":    landing,
+		"takeoff_chart":     calc.Chart(),
+		"stitched_takeoff_chart": StitchedTakeoffChart{
+			Source:     calc.Source(),
+			Barrier:    []TakeoffChart{*calc.Chart()},
+			GroundRoll: []TakeoffChart{*calc.Chart()},
+		},
+	}
+	
+	for _, name := range SchemaNames() {
+		value, ok := values[name]
+		if !ok {
+			t.Errorf("%s: no value to check the schema against", name)
+			continue
+		}
+		delete(values, name)
+		
+		data, err := Schema(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var schema schemaObject
+		if err := json.Unmarshal(data, &schema); err != nil {
+			t.Fatalf("%s: invalid schema: %v", name, err)
+		}
+		if schema.Items != nil {
+			schema = *schema.Items
+		}
+		
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var object map[string]json.RawMessage
+		var array []map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &array); err == nil {
+			object = array[0]
+		} else if err := json.Unmarshal(encoded, &object); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		
+		if got, want := sortedKeys(object), sortedKeys(schema.Properties); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: encoded fields %v, schema properties %v", name, got, want)
+		}
+		for _, field := range schema.Required {
+			if _, ok := object[field]; !ok {
+				t.Errorf("%s: required field %q not encoded", name, field)
+			}
+		}
+	}
+	
+	for name := range values {
+		t.Errorf("%s: no published schema", name)
+	}
+}
+
+func TestResultRoundTrip(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 1234.5, Temperature: ConvertFahrenheitToCelsius(80), Weight: 2211.1, WindComponent: -3.3}
+	result, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reverse, err := calc.MaxTemperature(params, 3000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	climbParams := ClimbParams{PressureAltitude: 512.3, Temperature: 21.7, CruiseAltitude: 6500}
+	climb, err := NewClimbCalculator().CalculateClimb(climbParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	cruiseParams := CruiseParams{PressureAltitude: 6543.2, Temperature: 1.1, Power: 66.6}
+	cruise, err := NewCruiseCalculator().CalculateCruise(cruiseParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	landingParams := LandingParams{PressureAltitude: 2345.6, Temperature: 12.3, Weight: 2111.1, WindComponent: 4.4}
+	landing, err := NewLandingCalculator().CalculateLanding(landingParams)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	
+	for _, original := range []interface{}{&params, result, reverse, &climbParams, climb, &cruiseParams, cruise, &landingParams, landing} {
+		data, err := json.Marshal(original)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		
+		decoded := reflect.New(reflect.TypeOf(original).Elem()).Interface()
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("Unexpected error decoding %s: %v", data, err)
+		}
+		if !reflect.DeepEqual(original, decoded) {
+			t.Errorf("Round trip changed %+v to %+v", original, decoded)
+		}
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing field", `{"pressure_altitude": 1500, "temperature_c": 20, "weight": 2200}`},
+		{"unknown unit", `{"pressure_altitude": 1500, "temperature_f": 68, "weight": 2200, "wind_component": 0}`},
+		{"extra field", `{"pressure_altitude": 1500, "temperature_c": 20, "weight": 2200, "wind_component": 0, "runway": 3000}`},
+	}
+	
+	for _, tc := range tests {
+		var params TakeoffParams
+		if err := json.Unmarshal([]byte(tc.json), &params); err == nil {
+			t.Errorf("%s: expected error, but got none", tc.name)
+		}
+	}
+	
+	var reverse ReverseResult
+	if err := json.Unmarshal([]byte(`{"value": 2100, "takeoff_distance": 2000, "limit": "obstacle"}`), &reverse); err == nil {
+		t.Error("Expected error for unknown solver limit, but got none")
+	}
+}
+
+// sortedKeys returns the keys of a JSON object in order
+func sortedKeys(object map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
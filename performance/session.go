@@ -0,0 +1,104 @@
+package performance
+
+// Session supports cheap recomputation of takeoff performance when inputs
+// change a little at a time, as they do when a user drags a slider in a
+// TUI or web front-end. It caches the chart cell surrounding the current
+// inputs and only looks up new corner values when an input moves into a
+// different cell. A Session is not safe for concurrent use.
+type Session struct {
+	calc   *TakeoffCalculator
+	params TakeoffParams
+	
+	alt     bracket
+	temp    bracket
+	weight  bracket
+	corners [2][2][2]float64 // Chart values surrounding the current inputs
+	rolls   [2][2][2]float64 // Ground roll values surrounding them, where charted
+}
+
+// NewSession starts a recomputation session at the given inputs
+func (c *TakeoffCalculator) NewSession(params TakeoffParams) *Session {
+	s := &Session{calc: c}
+	s.SetParams(params)
+	return s
+}
+
+// Params returns the session's current inputs
+func (s *Session) Params() TakeoffParams {
+	return s.params
+}
+
+// SetParams replaces all inputs at once
+func (s *Session) SetParams(params TakeoffParams) {
+	s.params = params
+	s.alt = newBracket(s.calc.altitudes, params.PressureAltitude)
+	s.temp = newBracket(s.calc.temperatures, params.Temperature)
+	s.weight = newBracket(s.calc.weights, params.Weight)
+	s.lookupCorners()
+}
+
+// lookupCorners reads the chart values of the cell the inputs are in
+func (s *Session) lookupCorners() {
+	s.corners = s.calc.baseCorners(s.alt, s.temp, s.weight)
+	if s.calc.groundRolls != nil {
+		s.rolls = s.calc.groundRollCorners(s.alt, s.temp, s.weight)
+	}
+}
+
+// SetPressureAltitude changes the pressure altitude in feet
+func (s *Session) SetPressureAltitude(altitude float64) {
+	s.params.PressureAltitude = altitude
+	if s.alt.update(s.calc.altitudes, altitude) {
+		s.lookupCorners()
+	}
+}
+
+// SetTemperature changes the temperature in °C
+func (s *Session) SetTemperature(temperature float64) {
+	s.params.Temperature = temperature
+	if s.temp.update(s.calc.temperatures, temperature) {
+		s.lookupCorners()
+	}
+}
+
+// SetWeight changes the aircraft weight in pounds
+func (s *Session) SetWeight(weight float64) {
+	s.params.Weight = weight
+	if s.weight.update(s.calc.weights, weight) {
+		s.lookupCorners()
+	}
+}
+
+// SetWindComponent changes the wind component in knots (positive for headwind)
+func (s *Session) SetWindComponent(wind float64) {
+	s.params.WindComponent = wind
+}
+
+// Result calculates takeoff performance for the current inputs. It returns
+// exactly what CalculateTakeoff would for the same parameters.
+func (s *Session) Result() (*TakeoffResult, error) {
+	if err := s.calc.validateInputs(s.params); err != nil {
+		return nil, err
+	}
+	
+	baseDistance := interpolateCorners(s.corners, s.alt, s.temp, s.weight)
+	finalDistance, err := s.calc.applyWindCorrection(baseDistance, s.params.WindComponent)
+	if err != nil {
+		return nil, err
+	}
+	
+	var baseRoll float64
+	if s.calc.groundRolls != nil {
+		baseRoll = interpolateCorners(s.rolls, s.alt, s.temp, s.weight)
+	}
+	
+	// Speeds only depend on weight, so reuse the weight bracket
+	liftoffSpeed := interpolate(s.calc.speedsLiftoff, s.weight)
+	barrierSpeed := interpolate(s.calc.speedsBarrier, s.weight)
+	return s.calc.result(s.params, baseDistance, finalDistance, baseRoll, liftoffSpeed, barrierSpeed), nil
+}
+
+// interpolate evaluates a one-dimensional chart column at a bracket
+func interpolate(values []float64, b bracket) float64 {
+	return lerp(values[b.lo], values[b.hi], b.frac)
+}
@@ -0,0 +1,89 @@
+package performance
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSessionMatchesCalculateTakeoff(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	session := calculator.NewSession(TakeoffParams{
+		PressureAltitude: 1500,
+		Temperature:      15,
+		Weight:           2200,
+		WindComponent:    5,
+	})
+	
+	// Sweep each input across several chart cells, including the chart edges
+	steps := []func(i int){
+		func(i int) { session.SetPressureAltitude(-500 + float64(i)*375) },
+		func(i int) { session.SetTemperature(-40 + float64(i)*4) },
+		func(i int) { session.SetWeight(1600 + float64(i)*36.25) },
+		func(i int) { session.SetWindComponent(-5 + float64(i)) },
+	}
+	
+	for _, step := range steps {
+		for i := 0; i <= 20; i++ {
+			step(i)
+			
+			got, err := session.Result()
+			if err != nil {
+				t.Fatalf("Error from session at %+v: %v", session.Params(), err)
+			}
+			want, err := calculator.CalculateTakeoff(session.Params())
+			if err != nil {
+				t.Fatalf("Error from calculator at %+v: %v", session.Params(), err)
+			}
+			
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Session result differs at %+v: got %+v, expected %+v", session.Params(), *got, *want)
+			}
+		}
+	}
+}
+
+func TestSessionValidation(t *testing.T) {
+	session := NewTakeoffCalculator().NewSession(TakeoffParams{
+		PressureAltitude: 1500,
+		Temperature:      15,
+		Weight:           2200,
+	})
+	
+	session.SetWeight(2400)
+	if _, err := session.Result(); err == nil {
+		t.Errorf("Expected error for weight above chart range, but got none")
+	}
+	
+	session.SetWeight(2300)
+	if _, err := session.Result(); err != nil {
+		t.Errorf("Expected no error after returning to chart range, but got: %v", err)
+	}
+}
+
+func BenchmarkCalculateTakeoffSlider(b *testing.B) {
+	calculator := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 15, Weight: 2200, WindComponent: 5}
+	
+	for i := 0; i < b.N; i++ {
+		params.Temperature = 15 + float64(i%100)*0.05
+		if _, err := calculator.CalculateTakeoff(params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSessionSlider(b *testing.B) {
+	session := NewTakeoffCalculator().NewSession(TakeoffParams{
+		PressureAltitude: 1500,
+		Temperature:      15,
+		Weight:           2200,
+		WindComponent:    5,
+	})
+	
+	for i := 0; i < b.N; i++ {
+		session.SetTemperature(15 + float64(i%100)*0.05)
+		if _, err := session.Result(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package performance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	
+	"github.com/ryanbmilbourne/otto-perf/internal/solve"
+)
+
+// Solver tolerances for each solved input
+const (
+	weightTolerance      = 0.1  // pounds
+	temperatureTolerance = 0.01 // °C
+	departureTolerance   = time.Minute
+)
+
+// SolverLimit identifies what bounds the answer of a reverse solver
+type SolverLimit int
+
+const (
+	// LimitRunway means the available distance limits the answer
+	LimitRunway SolverLimit = iota
+	// LimitChart means the answer is the chart boundary and the runway is not limiting
+	LimitChart
+)
+
+// String returns a short description of the limit
+func (l SolverLimit) String() string {
+	switch l {
+	case LimitRunway:
+		return "runway limiting"
+	case LimitChart:
+		return "chart limit, runway not limiting"
+	default:
+		return fmt.Sprintf("SolverLimit(%d)", int(l))
+	}
+}
+
+// ReverseResult contains the answer from a reverse solver
+type ReverseResult struct {
+	Value           float64     `json:"value"`            // The solved input (pounds for MaxWeight, °C for MaxTemperature)
+	TakeoffDistance float64     `json:"takeoff_distance"` // Distance over 50ft barrier at Value, in feet
+	Limit           SolverLimit `json:"limit"`            // Whether the runway or the chart boundary limits Value
+}
+
+// MaxWeight finds the heaviest weight at which the takeoff distance over a
+// 50ft obstacle fits within the available distance, holding the other
+// inputs fixed. The Weight field of params is ignored.
+func (c *TakeoffCalculator) MaxWeight(params TakeoffParams, availableDistance float64) (*ReverseResult, error) {
+	return c.solveMax(params, availableDistance, c.weights, weightTolerance, "weight",
+		func(p *TakeoffParams, weight float64) { p.Weight = weight })
+}
+
+// MaxTemperature finds the highest temperature at which the takeoff distance
+// over a 50ft obstacle fits within the available distance, holding the other
+// inputs fixed. The Temperature field of params is ignored.
+func (c *TakeoffCalculator) MaxTemperature(params TakeoffParams, availableDistance float64) (*ReverseResult, error) {
+	return c.solveMax(params, availableDistance, c.temperatures, temperatureTolerance, "temperature",
+		func(p *TakeoffParams, temperature float64) { p.Temperature = temperature })
+}
+
+// solveMax finds the largest value along a chart axis for which the takeoff
+// distance fits, relying on distance increasing monotonically along the axis
+func (c *TakeoffCalculator) solveMax(params TakeoffParams, availableDistance float64, axis []float64,
+	tolerance float64, name string, set func(*TakeoffParams, float64)) (*ReverseResult, error) {
+	lo, hi := axis[0], axis[len(axis)-1]
+	
+	// The fixed inputs must be valid on their own
+	probe := params
+	set(&probe, lo)
+	if err := c.validateInputs(probe); err != nil {
+		return nil, err
+	}
+	
+	at := func(value float64) TakeoffParams {
+		p := params
+		set(&p, value)
+		return p
+	}
+	
+	value, err := solve.MaxFeasible(func(v float64) bool {
+		result, err := c.CalculateTakeoff(at(v))
+		return err == nil && result.TakeoffDistance <= availableDistance
+	}, lo, hi, tolerance)
+	if errors.Is(err, solve.ErrInfeasible) {
+		return nil, fmt.Errorf("takeoff distance exceeds available distance (%.0f ft) even at the minimum chart %s",
+			availableDistance, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	result, err := c.CalculateTakeoff(at(value))
+	if err != nil {
+		return nil, err
+	}
+	
+	// MaxFeasible only returns the top of the axis when the whole axis fits
+	limit := LimitRunway
+	if value == hi {
+		limit = LimitChart
+	}
+	
+	return &ReverseResult{
+		Value:           value,
+		TakeoffDistance: result.TakeoffDistance,
+		Limit:           limit,
+	}, nil
+}
+
+// DepartureResult contains the answer from LatestDeparture
+type DepartureResult struct {
+	Time            time.Time `json:"time"`             // Latest departure that fits
+	Temperature     float64   `json:"temperature"`      // Temperature at Time, in °C
+	TakeoffDistance float64   `json:"takeoff_distance"` // Distance over 50ft barrier at Time, in feet
+	RunwayLimited   bool      `json:"runway_limited"`   // False when the departure fits to the end of the window
+}
+
+// LatestDeparture finds the latest time between earliest and latest at
+// which the takeoff distance over a 50ft obstacle fits within the available
+// distance, as the temperature rises through the day. temperature gives the
+// forecast °C at a time and must not fall over the window, as from the
+// morning to the afternoon high; the Temperature field of params is
+// ignored. A temperature beyond the chart counts as not fitting.
+//
+// The forecast is often a step function, such as hourly points held
+// between them, so the search bisects on the sign of the margin, which
+// stays correct across its flat regions.
+func (c *TakeoffCalculator) LatestDeparture(params TakeoffParams, availableDistance float64,
+	temperature func(time.Time) float64, earliest, latest time.Time) (*DepartureResult, error) {
+	if latest.Before(earliest) {
+		return nil, fmt.Errorf("departure window ends (%s) before it starts (%s)", latest.Format(time.RFC3339), earliest.Format(time.RFC3339))
+	}
+	
+	at := func(seconds float64) time.Time {
+		return earliest.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	calculate := func(t time.Time) (*TakeoffResult, TakeoffParams, error) {
+		p := params
+		p.Temperature = temperature(t)
+		result, err := c.CalculateTakeoff(p)
+		return result, p, err
+	}
+	
+	// The fixed inputs, and the earliest temperature, must be valid on their own
+	first, p, err := calculate(earliest)
+	if err != nil {
+		return nil, err
+	}
+	if first.TakeoffDistance > availableDistance {
+		return nil, fmt.Errorf("takeoff distance %.0f ft exceeds available distance (%.0f ft) even at the earliest departure, %.0f°C",
+			first.TakeoffDistance, availableDistance, p.Temperature)
+	}
+	if result, p, err := calculate(latest); err == nil && result.TakeoffDistance <= availableDistance {
+		return &DepartureResult{Time: latest, Temperature: p.Temperature, TakeoffDistance: result.TakeoffDistance}, nil
+	}
+	
+	margin := func(seconds float64) float64 {
+		result, _, err := calculate(at(seconds))
+		if err != nil {
+			return math.Inf(1)
+		}
+		return result.TakeoffDistance - availableDistance
+	}
+	tolerance := departureTolerance.Seconds()
+	seconds, err := solve.Bisect(margin, 0, latest.Sub(earliest).Seconds(), tolerance)
+	if err != nil {
+		return nil, err
+	}
+	
+	// Bisect answers from within the last bracket; step back across it so
+	// the time returned is on the side that fits
+	seconds = math.Max(seconds-tolerance, 0)
+	t := at(seconds)
+	result, p, err := calculate(t)
+	if err != nil {
+		return nil, err
+	}
+	if result.TakeoffDistance > availableDistance {
+		return nil, fmt.Errorf("temperature falls within the departure window; no single latest departure")
+	}
+	
+	return &DepartureResult{
+		Time:            t,
+		Temperature:     p.Temperature,
+		TakeoffDistance: result.TakeoffDistance,
+		RunwayLimited:   true,
+	}, nil
+}
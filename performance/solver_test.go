@@ -0,0 +1,211 @@
+package performance
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaxWeight(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 25, WindComponent: 0}
+	
+	testCases := []struct {
+		name        string
+		available   float64
+		expectError bool
+		expectMax   bool
+	}{
+		{name: "Runway Limited", available: 1800},
+		{name: "Chart Limited", available: 5000, expectMax: true},
+		{name: "Infeasible", available: 500, expectError: true},
+	}
+	
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := calculator.MaxWeight(params, tc.available)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("Expected error, but got %+v", *result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error solving max weight: %v", err)
+			}
+			
+			if result.TakeoffDistance > tc.available {
+				t.Errorf("Distance %.1f ft at %.1f lbs exceeds available %.0f ft",
+					result.TakeoffDistance, result.Value, tc.available)
+			}
+			if tc.expectMax {
+				if result.Value != 2325 || result.Limit != LimitChart {
+					t.Errorf("Expected chart maximum weight (%v), got %.1f lbs (%v)", LimitChart, result.Value, result.Limit)
+				}
+				return
+			}
+			if result.Limit != LimitRunway {
+				t.Errorf("Expected %v, got %v", LimitRunway, result.Limit)
+			}
+			
+			// Slightly heavier must no longer fit
+			heavier := params
+			heavier.Weight = result.Value + 2*weightTolerance
+			check, err := calculator.CalculateTakeoff(heavier)
+			if err != nil {
+				t.Fatalf("Error checking heavier weight: %v", err)
+			}
+			if check.TakeoffDistance <= tc.available {
+				t.Errorf("%.1f lbs still fits (%.1f ft); solver result %.1f lbs is not the maximum",
+					heavier.Weight, check.TakeoffDistance, result.Value)
+			}
+		})
+	}
+}
+
+func TestMaxTemperature(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 3000, Weight: 2200, WindComponent: 5}
+	
+	result, err := calculator.MaxTemperature(params, 2200)
+	if err != nil {
+		t.Fatalf("Error solving max temperature: %v", err)
+	}
+	if result.Value <= -40 || result.Value >= 40 || result.Limit != LimitRunway {
+		t.Fatalf("Expected a runway-limited temperature inside the chart, got %.2f°C", result.Value)
+	}
+	
+	hotter := params
+	hotter.Temperature = result.Value + 2*temperatureTolerance
+	check, err := calculator.CalculateTakeoff(hotter)
+	if err != nil {
+		t.Fatalf("Error checking hotter temperature: %v", err)
+	}
+	if result.TakeoffDistance > 2200 || check.TakeoffDistance <= 2200 {
+		t.Errorf("Max temperature %.2f°C does not sit on the 2200 ft threshold (%.1f ft, %.1f ft just above)",
+			result.Value, result.TakeoffDistance, check.TakeoffDistance)
+	}
+	
+	// Invalid fixed inputs are reported rather than solved around
+	params.PressureAltitude = 9000
+	if _, err := calculator.MaxTemperature(params, 2200); err == nil {
+		t.Errorf("Expected error for altitude outside chart, but got none")
+	}
+}
+
+func TestMaxTemperatureChartLimited(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	
+	result, err := calculator.MaxTemperature(TakeoffParams{PressureAltitude: 0, Weight: 1600}, 3000)
+	if err != nil {
+		t.Fatalf("Error solving max temperature: %v", err)
+	}
+	if result.Value != 40 || result.Limit != LimitChart {
+		t.Errorf("Expected 40°C limited by the chart, got %.2f°C (%v)", result.Value, result.Limit)
+	}
+	if got := result.Limit.String(); got != "chart limit, runway not limiting" {
+		t.Errorf("Unexpected limit description %q", got)
+	}
+}
+
+func TestSolverLimitJSON(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 1500, Temperature: 25}
+	
+	for _, tc := range []struct {
+		available float64
+		want      string
+	}{
+		{1800, `"limit":"runway"`},
+		{5000, `"limit":"chart"`},
+	} {
+		result, err := calculator.MaxWeight(params, tc.available)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), tc.want) {
+			t.Errorf("Expected %s in %s", tc.want, data)
+		}
+		
+		var decoded ReverseResult
+		if err := json.Unmarshal(data, &decoded); err != nil || decoded.Limit != result.Limit {
+			t.Errorf("Expected %s to decode to %v, got %v (%v)", data, result.Limit, decoded.Limit, err)
+		}
+	}
+	
+	if _, err := SolverLimit(7).MarshalText(); err == nil {
+		t.Error("Expected an error for an unknown limit")
+	}
+}
+
+func TestLatestDeparture(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	params := TakeoffParams{PressureAltitude: 3000, Weight: 2200, WindComponent: 5}
+	start := time.Date(2026, 7, 1, 6, 0, 0, 0, time.UTC)
+	
+	// Hourly forecast points held until the next, 10°C at 06:00 rising 3°C an hour
+	hourly := func(at time.Time) float64 {
+		return 10 + 3 * math.Floor(at.Sub(start).Hours())
+	}
+	limit, err := calculator.MaxTemperature(params, 2500)
+	if err != nil {
+		t.Fatalf("Error solving max temperature: %v", err)
+	}
+	step := start.Add(time.Duration(math.Ceil((limit.Value - 10) / 3)) * time.Hour)
+	
+	result, err := calculator.LatestDeparture(params, 2500, hourly, start, start.Add(12 * time.Hour))
+	if err != nil {
+		t.Fatalf("Error solving latest departure: %v", err)
+	}
+	// The margin is flat within each hour, so the answer is just before the hour it stops fitting
+	if !result.RunwayLimited || !result.Time.Before(step) || step.Sub(result.Time) > 2 * departureTolerance {
+		t.Errorf("Expected a runway-limited departure just before %s, got %+v", step.Format("15:04"), result)
+	}
+	if result.TakeoffDistance > 2500 || result.Temperature != hourly(result.Time) {
+		t.Errorf("Unexpected departure %+v", result)
+	}
+	
+	// Too late even at the start
+	if _, err := calculator.LatestDeparture(params, 1500, hourly, start, start.Add(12 * time.Hour)); err == nil {
+		t.Errorf("Expected error when the earliest departure does not fit, but got none")
+	}
+}
+
+func TestLatestDepartureClampedEdge(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	start := time.Date(2026, 7, 1, 6, 0, 0, 0, time.UTC)
+	
+	// Rising to the 40°C edge of the chart by 12:00 and held there
+	clamped := func(at time.Time) float64 {
+		return math.Min(10 + 5 * at.Sub(start).Hours(), 40)
+	}
+	
+	// Fits at the clamped edge, so the whole window fits
+	result, err := calculator.LatestDeparture(TakeoffParams{PressureAltitude: 0, Weight: 1600}, 3000, clamped, start, start.Add(12 * time.Hour))
+	if err != nil {
+		t.Fatalf("Error solving latest departure: %v", err)
+	}
+	if result.RunwayLimited || !result.Time.Equal(start.Add(12 * time.Hour)) || result.Temperature != 40 {
+		t.Errorf("Expected the end of the window at 40°C, got %+v", result)
+	}
+	
+	// Does not fit anywhere on the flat edge, so the answer is on the rise before it
+	params := TakeoffParams{PressureAltitude: 3000, Weight: 2200, WindComponent: 5}
+	limit, err := calculator.MaxTemperature(params, 2500)
+	if err != nil {
+		t.Fatalf("Error solving max temperature: %v", err)
+	}
+	result, err = calculator.LatestDeparture(params, 2500, clamped, start, start.Add(12 * time.Hour))
+	if err != nil {
+		t.Fatalf("Error solving latest departure: %v", err)
+	}
+	if !result.RunwayLimited || result.Temperature > limit.Value || limit.Value - result.Temperature > 0.2 {
+		t.Errorf("Expected a departure at about %.2f°C, got %+v", limit.Value, result)
+	}
+}
@@ -0,0 +1,367 @@
+package performance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// seamTolerance is the largest relative step in distance allowed where two
+// panels of a chart meet; beyond it one of them is probably misread
+const seamTolerance = 0.03
+
+// seamSpeedTolerance is the largest difference in knots allowed between
+// the speeds two weight panels give at their shared weight
+const seamSpeedTolerance = 1
+
+// StitchedTakeoffChart is one logical takeoff chart printed across several
+// POH figures: weight, altitude or temperature panels of the distance over
+// a 50 ft barrier, and optionally a separate ground roll figure split the
+// same way. Each panel is a complete chart of its figure.
+type StitchedTakeoffChart struct {
+	Source     Source         `json:"source"`
+	Barrier    []TakeoffChart `json:"barrier"`
+	GroundRoll []TakeoffChart `json:"ground_roll,omitempty"`
+}
+
+// UnmarshalJSON decodes a stitched chart, rejecting missing or unknown fields
+func (s *StitchedTakeoffChart) UnmarshalJSON(data []byte) error {
+	type plain StitchedTakeoffChart
+	return decodeStrict(data, (*plain)(s), "source", "barrier")
+}
+
+// Stitch composes the panels into one chart of the distance over the
+// barrier and, when there are ground roll panels, one of the ground roll,
+// both with the stitched chart's source. Panels of a part must share their
+// units, wind lines and two of their axes, and meet or leave a gap along
+// the third. Where they meet the distances must agree within 3%, averaged
+// into the seam line; across a gap each panel's trend must reach the
+// other's. Every discontinuity, and every ground roll not shorter than the
+// barrier distance, is reported together.
+func (s *StitchedTakeoffChart) Stitch() (barrier, groundRoll *TakeoffChart, err error) {
+	if len(s.Barrier) == 0 {
+		return nil, nil, errors.New("stitched chart has no barrier panels")
+	}
+	var problems []string
+	if barrier, err = s.stitchPart("barrier", s.Barrier, &problems); err != nil {
+		return nil, nil, err
+	}
+	if len(s.GroundRoll) > 0 {
+		if groundRoll, err = s.stitchPart("ground roll", s.GroundRoll, &problems); err != nil {
+			return nil, nil, err
+		}
+		problems = append(problems, rollProblems(barrier, groundRoll)...)
+	}
+	if len(problems) > 0 {
+		return nil, nil, errors.New(strings.Join(problems, "\n"))
+	}
+	return barrier, groundRoll, nil
+}
+
+// chartAxes indexes a chart's distance axes in the order of its table:
+// altitude, weight, temperature
+var chartAxes = []struct {
+	name string
+	get  func(*TakeoffChart) *[]float64
+}{
+	{"altitude", func(c *TakeoffChart) *[]float64 { return &c.Altitudes }},
+	{"weight", func(c *TakeoffChart) *[]float64 { return &c.Weights }},
+	{"temperature", func(c *TakeoffChart) *[]float64 { return &c.Temperatures }},
+}
+
+// at returns the chart's distance at line indexes i of its axes
+func (t *TakeoffChart) at(i [3]int) float64 {
+	return t.Distances[i[0]][i[1]*len(t.Temperatures)+i[2]]
+}
+
+// panelName names a panel by its figure, or by its place in the part
+func panelName(part string, i int, c *TakeoffChart) string {
+	if c.Source.Figure != "" {
+		return "Figure " + c.Source.Figure
+	}
+	return fmt.Sprintf("%s panel %d", part, i+1)
+}
+
+// stitchPart joins the panels of one part along the axis they split,
+// appending any discontinuities at the seams to problems
+func (s *StitchedTakeoffChart) stitchPart(part string, panels []TakeoffChart, problems *[]string) (*TakeoffChart, error) {
+	for i := range panels {
+		if err := panels[i].validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", panelName(part, i, &panels[i]), err)
+		}
+	}
+	first := &panels[0]
+	for i := 1; i < len(panels); i++ {
+		p := &panels[i]
+		if p.Units != first.Units {
+			return nil, fmt.Errorf("%s: units %+v differ from %s's %+v", panelName(part, i, p), p.Units, panelName(part, 0, first), first.Units)
+		}
+		if !sameLines(p.Headwinds, first.Headwinds) || !sameLines(p.HeadwindFactors, first.HeadwindFactors) ||
+			!sameLines(p.Tailwinds, first.Tailwinds) || !sameLines(p.TailwindFactors, first.TailwindFactors) {
+			return nil, fmt.Errorf("%s: wind lines differ from %s's", panelName(part, i, p), panelName(part, 0, first))
+		}
+	}
+	
+	stitched := &TakeoffChart{
+		Source:          s.Source,
+		Units:           first.Units,
+		Altitudes:       first.Altitudes,
+		Temperatures:    first.Temperatures,
+		Weights:         first.Weights,
+		Distances:       first.Distances,
+		Headwinds:       first.Headwinds,
+		HeadwindFactors: first.HeadwindFactors,
+		Tailwinds:       first.Tailwinds,
+		TailwindFactors: first.TailwindFactors,
+		LiftoffSpeeds:   first.LiftoffSpeeds,
+		BarrierSpeeds:   first.BarrierSpeeds,
+	}
+	if len(panels) == 1 {
+		return stitched, nil
+	}
+	
+	// The split axis is the one the panels do not share
+	split := -1
+	for a, axis := range chartAxes {
+		for i := 1; i < len(panels); i++ {
+			if !sameLines(*axis.get(&panels[i]), *axis.get(first)) {
+				if split >= 0 && split != a {
+					return nil, fmt.Errorf("%s panels differ in their %s and %s lines; panels may split one axis only", part, chartAxes[split].name, axis.name)
+				}
+				split = a
+			}
+		}
+	}
+	if split < 0 {
+		return nil, fmt.Errorf("%s panels repeat the same lines", part)
+	}
+	if split != 1 {
+		for i := 1; i < len(panels); i++ {
+			if !sameLines(panels[i].LiftoffSpeeds, first.LiftoffSpeeds) || !sameLines(panels[i].BarrierSpeeds, first.BarrierSpeeds) {
+				return nil, fmt.Errorf("%s: speeds differ from %s's at the same weights", panelName(part, i, &panels[i]), panelName(part, 0, first))
+			}
+		}
+	}
+	
+	order := make([]int, len(panels))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return (*chartAxes[split].get(&panels[order[i]]))[0] < (*chartAxes[split].get(&panels[order[j]]))[0]
+	})
+	
+	// Each line of the split axis is read from the panel that draws it, or
+	// the mean of the two that share it at a seam
+	type seamLine struct {
+		value         float64
+		panels, lines []int
+	}
+	var lines []seamLine
+	unit := axisUnit(split, first.Units)
+	for n, i := range order {
+		p := &panels[i]
+		values := *chartAxes[split].get(p)
+		if n > 0 {
+			prevIndex := order[n-1]
+			prev := &panels[prevIndex]
+			last := lines[len(lines)-1].value
+			switch {
+			case values[0] < last:
+				return nil, fmt.Errorf("%s and %s overlap between %g and %g %s", panelName(part, prevIndex, prev), panelName(part, i, p), values[0], last, unit)
+			case values[0] == last:
+				lines[len(lines)-1].panels = append(lines[len(lines)-1].panels, i)
+				lines[len(lines)-1].lines = append(lines[len(lines)-1].lines, 0)
+				*problems = append(*problems, seamProblems(part, split, unit, prevIndex, prev, i, p)...)
+				values = values[1:]
+				for k := range values {
+					lines = append(lines, seamLine{value: values[k], panels: []int{i}, lines: []int{k + 1}})
+				}
+				continue
+			default:
+				*problems = append(*problems, gapProblems(part, split, unit, prevIndex, prev, i, p)...)
+			}
+		}
+		for k, v := range values {
+			lines = append(lines, seamLine{value: v, panels: []int{i}, lines: []int{k}})
+		}
+	}
+	
+	axis := make([]float64, len(lines))
+	for k, l := range lines {
+		axis[k] = l.value
+	}
+	*chartAxes[split].get(stitched) = axis
+	stitched.Distances = nil
+	for a := range stitched.Altitudes {
+		row := make([]float64, len(stitched.Weights)*len(stitched.Temperatures))
+		for w := range stitched.Weights {
+			for t := range stitched.Temperatures {
+				node := [3]int{a, w, t}
+				l := lines[node[split]]
+				var sum float64
+				for k, i := range l.panels {
+					node[split] = l.lines[k]
+					sum += panels[i].at(node)
+				}
+				row[w*len(stitched.Temperatures)+t] = sum / float64(len(l.panels))
+			}
+		}
+		stitched.Distances = append(stitched.Distances, row)
+	}
+	
+	if split == 1 {
+		stitched.LiftoffSpeeds, stitched.BarrierSpeeds = nil, nil
+		for _, l := range lines {
+			var liftoff, barrier []float64
+			for k, i := range l.panels {
+				liftoff = append(liftoff, panels[i].LiftoffSpeeds[l.lines[k]])
+				barrier = append(barrier, panels[i].BarrierSpeeds[l.lines[k]])
+			}
+			if len(l.panels) == 2 && (math.Abs(liftoff[0] - liftoff[1]) > seamSpeedTolerance || math.Abs(barrier[0] - barrier[1]) > seamSpeedTolerance) {
+				*problems = append(*problems, fmt.Sprintf("%s weight seam at %g %s: speeds %g/%g in %s, %g/%g in %s",
+					part, l.value, unit, liftoff[0], barrier[0], panelName(part, l.panels[0], &panels[l.panels[0]]),
+					liftoff[1], barrier[1], panelName(part, l.panels[1], &panels[l.panels[1]])))
+			}
+			stitched.LiftoffSpeeds = append(stitched.LiftoffSpeeds, mean(liftoff))
+			stitched.BarrierSpeeds = append(stitched.BarrierSpeeds, mean(barrier))
+		}
+	}
+	return stitched, nil
+}
+
+// axisUnit returns the unit of a chart axis, for messages
+func axisUnit(axis int, u ChartUnits) string {
+	unit := []string{u.Altitude, u.Weight, "°C"}[axis]
+	if unit == "" {
+		unit = []string{"ft", "lbs", "°C"}[axis]
+	}
+	return unit
+}
+
+// seamProblems compares two panels along the line they share: the last of
+// prev and the first of next
+func seamProblems(part string, split int, unit string, prevIndex int, prev *TakeoffChart, nextIndex int, next *TakeoffChart) []string {
+	var problems []string
+	last := len(*chartAxes[split].get(prev)) - 1
+	seam := (*chartAxes[split].get(prev))[last]
+	eachNode(next, split, func(node [3]int, where string) {
+		node[split] = last
+		a := prev.at(node)
+		node[split] = 0
+		b := next.at(node)
+		if math.Abs(a - b) > seamTolerance * math.Max(a, b) {
+			problems = append(problems, fmt.Sprintf("%s %s seam at %g %s, %s: %g in %s, %g in %s",
+				part, chartAxes[split].name, seam, unit, where, a, panelName(part, prevIndex, prev), b, panelName(part, nextIndex, next)))
+		}
+	})
+	return problems
+}
+
+// gapProblems checks that the trends of two panels either side of a gap
+// meet in its middle, each extended from its two lines nearest the gap
+func gapProblems(part string, split int, unit string, prevIndex int, prev *TakeoffChart, nextIndex int, next *TakeoffChart) []string {
+	var problems []string
+	before, after := *chartAxes[split].get(prev), *chartAxes[split].get(next)
+	middle := (before[len(before)-1] + after[0]) / 2
+	eachNode(next, split, func(node [3]int, where string) {
+		trend := func(c *TakeoffChart, lines []float64, near, far int) float64 {
+			node[split] = near
+			v := c.at(node)
+			if far < 0 || far >= len(lines) {
+				return v
+			}
+			node[split] = far
+			slope := (v - c.at(node)) / (lines[near] - lines[far])
+			return v + slope * (middle - lines[near])
+		}
+		a := trend(prev, before, len(before)-1, len(before)-2)
+		b := trend(next, after, 0, 1)
+		if math.Abs(a - b) > seamTolerance * math.Max(a, b) {
+			problems = append(problems, fmt.Sprintf("%s %s gap at %g %s, %s: %s reaches %.0f, %s reaches %.0f",
+				part, chartAxes[split].name, middle, unit, where, panelName(part, prevIndex, prev), a, panelName(part, nextIndex, next), b))
+		}
+	})
+	return problems
+}
+
+// eachNode calls f at every node of the two axes other than split, with
+// the node's lines and a description of it
+func eachNode(c *TakeoffChart, split int, f func(node [3]int, where string)) {
+	for a := range c.Altitudes {
+		for w := range c.Weights {
+			for t := range c.Temperatures {
+				if (split == 0 && a > 0) || (split == 1 && w > 0) || (split == 2 && t > 0) {
+					continue
+				}
+				var where []string
+				if split != 0 {
+					where = append(where, fmt.Sprintf("%g %s", c.Altitudes[a], axisUnit(0, c.Units)))
+				}
+				if split != 1 {
+					where = append(where, fmt.Sprintf("%g %s", c.Weights[w], axisUnit(1, c.Units)))
+				}
+				if split != 2 {
+					where = append(where, fmt.Sprintf("%g°C", c.Temperatures[t]))
+				}
+				f([3]int{a, w, t}, strings.Join(where, ", "))
+			}
+		}
+	}
+}
+
+// rollProblems lists the nodes the two charts share where the ground roll
+// is not shorter than the distance over the barrier
+func rollProblems(barrier, roll *TakeoffChart) []string {
+	if barrier.Units.Distance != roll.Units.Distance || barrier.Units.Altitude != roll.Units.Altitude || barrier.Units.Weight != roll.Units.Weight {
+		return []string{fmt.Sprintf("ground roll units %+v differ from the barrier's %+v", roll.Units, barrier.Units)}
+	}
+	var problems []string
+	index := func(lines []float64, v float64) int {
+		for i, line := range lines {
+			if line == v {
+				return i
+			}
+		}
+		return -1
+	}
+	for a, altitude := range roll.Altitudes {
+		for w, weight := range roll.Weights {
+			for t, temperature := range roll.Temperatures {
+				node := [3]int{index(barrier.Altitudes, altitude), index(barrier.Weights, weight), index(barrier.Temperatures, temperature)}
+				if node[0] < 0 || node[1] < 0 || node[2] < 0 {
+					continue
+				}
+				if r, d := roll.at([3]int{a, w, t}), barrier.at(node); r >= d {
+					problems = append(problems, fmt.Sprintf("ground roll %g is not shorter than the barrier distance %g at %g %s, %g %s, %g°C",
+						r, d, altitude, axisUnit(0, roll.Units), weight, axisUnit(1, roll.Units), temperature))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// sameLines reports whether two lists of chart lines are equal
+func sameLines(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mean returns the average of values
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,170 @@
+package performance
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// panel cuts lines [from, to) of one axis out of the PA-28-161 chart, as a
+// POH printing it across figures would
+func panel(axis, from, to int, figure string) TakeoffChart {
+	chart := NewTakeoffCalculator().Chart()
+	chart.Source.Figure = figure
+	keep := func(i [3]int) bool { return i[axis] >= from && i[axis] < to }
+	
+	var distances [][]float64
+	for a := range chart.Altitudes {
+		var row []float64
+		for w := range chart.Weights {
+			for t := range chart.Temperatures {
+				if keep([3]int{a, w, t}) {
+					row = append(row, chart.at([3]int{a, w, t}))
+				}
+			}
+		}
+		if len(row) > 0 {
+			distances = append(distances, row)
+		}
+	}
+	chart.Distances = distances
+	lines := chartAxes[axis].get(chart)
+	*lines = (*lines)[from:to]
+	if axis == 1 {
+		chart.LiftoffSpeeds = chart.LiftoffSpeeds[from:to]
+		chart.BarrierSpeeds = chart.BarrierSpeeds[from:to]
+	}
+	return *chart
+}
+
+// scaled returns the chart with its distances multiplied by factor
+func scaled(c TakeoffChart, factor float64) TakeoffChart {
+	var distances [][]float64
+	for _, row := range c.Distances {
+		distances = append(distances, convert(row, func(v float64) float64 { return v * factor }))
+	}
+	c.Distances = distances
+	return c
+}
+
+func TestStitch(t *testing.T) {
+	original := NewTakeoffCalculator().Chart()
+	source := Source{Aircraft: "PA-28-161", Document: "POH", Figure: "5-6a/5-6b", Title: "Takeoff Distance"}
+	
+	tests := []struct {
+		name   string
+		panels []TakeoffChart
+	}{
+		{"Weight Panels", []TakeoffChart{panel(1, 2, 5, "5-6b"), panel(1, 0, 3, "5-6a")}},
+		{"Altitude Panels", []TakeoffChart{panel(0, 0, 4, "5-6a"), panel(0, 3, 8, "5-6b")}},
+		{"Temperature Panels", []TakeoffChart{panel(2, 0, 3, "5-6a"), panel(2, 2, 5, "5-6b")}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := StitchedTakeoffChart{Source: source, Barrier: tc.panels}
+			barrier, roll, err := s.Stitch()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if roll != nil {
+				t.Errorf("Expected no ground roll chart")
+			}
+			want := *original
+			want.Source = source
+			if !reflect.DeepEqual(*barrier, want) {
+				t.Errorf("Stitched chart differs from the original:\n%+v\n%+v", *barrier, want)
+			}
+		})
+	}
+	
+	// A gap between panels is bridged by their trends
+	s := StitchedTakeoffChart{Source: source, Barrier: []TakeoffChart{panel(0, 0, 3, "5-6a"), panel(0, 4, 8, "5-6b")}}
+	barrier, _, err := s.Stitch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(barrier.Altitudes) != 7 || barrier.Altitudes[3] != 4000 {
+		t.Errorf("Unexpected altitudes %v", barrier.Altitudes)
+	}
+	if _, err := NewChartTakeoffCalculator(barrier); err != nil {
+		t.Errorf("Stitched chart is invalid: %v", err)
+	}
+	
+	// The ground roll figure is stitched the same way and checked against
+	// the barrier distance
+	s = StitchedTakeoffChart{
+		Source:     source,
+		Barrier:    []TakeoffChart{*original},
+		GroundRoll: []TakeoffChart{scaled(panel(1, 0, 3, "5-5a"), 0.6), scaled(panel(1, 2, 5, "5-5b"), 0.6)},
+	}
+	_, roll, err := s.Stitch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roll == nil || roll.Distances[0][0] != 0.6 * original.Distances[0][0] || roll.Source != source {
+		t.Errorf("Unexpected ground roll chart %+v", roll)
+	}
+}
+
+func TestStitchProblems(t *testing.T) {
+	misread := panel(1, 2, 5, "5-6b")
+	misread.Distances[3][1] *= 1.1
+	speeds := panel(1, 2, 5, "5-6b")
+	speeds.LiftoffSpeeds[0] += 3
+	wind := panel(1, 2, 5, "5-6b")
+	wind.HeadwindFactors = append([]float64(nil), wind.HeadwindFactors...)
+	wind.HeadwindFactors[1] = 0.5
+	units := panel(1, 2, 5, "5-6b")
+	units.Units.Distance = "m"
+	
+	tests := []struct {
+		name  string
+		chart StitchedTakeoffChart
+		want  []string
+	}{
+		{"Seam", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, "5-6a"), misread}},
+			[]string{"barrier weight seam at 2000 lbs, 3000 ft, -20°C", "in Figure 5-6a", "in Figure 5-6b"}},
+		{"Gap", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(0, 0, 3, ""), scaled(panel(0, 5, 8, ""), 1.2)}},
+			[]string{"barrier altitude gap at 3500 ft", "barrier panel 1 reaches"}},
+		{"Speeds", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, "5-6a"), speeds}}, []string{"speeds 46/52 in Figure 5-6a, 49/52 in Figure 5-6b"}},
+		{"Ground Roll", StitchedTakeoffChart{Barrier: []TakeoffChart{*NewTakeoffCalculator().Chart()}, GroundRoll: []TakeoffChart{scaled(panel(0, 0, 2, ""), 1.1)}},
+			[]string{"is not shorter than the barrier distance", "at 0 ft, 1600 lbs, -40°C"}},
+		{"Overlap", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, ""), panel(1, 1, 5, "")}}, []string{"overlap between 1800 and 2000 lbs"}},
+		{"Two Axes", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, ""), panel(0, 0, 3, "")}}, []string{"differ in their altitude and weight lines"}},
+		{"Repeated", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, ""), panel(1, 0, 3, "")}}, []string{"repeat the same lines"}},
+		{"Wind", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, "5-6a"), wind}}, []string{"Figure 5-6b: wind lines differ"}},
+		{"Units", StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, "5-6a"), units}}, []string{"Figure 5-6b: units"}},
+		{"Empty", StitchedTakeoffChart{}, []string{"no barrier panels"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := tc.chart.Stitch()
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected %q in %q", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStitchedChartJSON(t *testing.T) {
+	var s StitchedTakeoffChart
+	if err := json.Unmarshal([]byte(`{"barrier": []}`), &s); err == nil || !strings.Contains(err.Error(), "source") {
+		t.Errorf("Expected a missing source error, got %v", err)
+	}
+	data, err := json.Marshal(StitchedTakeoffChart{Barrier: []TakeoffChart{panel(1, 0, 3, "5-6a"), panel(1, 2, 5, "5-6b")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Barrier) != 2 || s.Barrier[1].Source.Figure != "5-6b" {
+		t.Errorf("Unexpected panels %+v", s.Barrier)
+	}
+}
@@ -1,41 +1,98 @@
+// Package performance implements the POH chart calculations.
+//
+// This package may be reorganized between releases; library users should
+// depend on the stable API in performance/v1.
 package performance
 
 import (
 	"fmt"
-	"math"
+	"sync"
+	
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/units"
 )
 
 // TakeoffParams represents the input parameters for takeoff performance calculations
 type TakeoffParams struct {
-	PressureAltitude float64 // in feet
-	Temperature      float64 // in °C
-	Weight           float64 // in pounds
-	WindComponent    float64 // in knots (positive for headwind, negative for tailwind)
+	PressureAltitude float64     `json:"pressure_altitude"`      // in feet
+	Temperature      float64     `json:"temperature_c"`          // in °C
+	Weight           float64     `json:"weight"`                 // in pounds
+	WindComponent    float64     `json:"wind_component"`         // in knots (positive for headwind, negative for tailwind)
+	FlapSetting      FlapSetting `json:"flap_setting,omitempty"` // Flaps for the takeoff; empty for the chart's own
 }
 
 // TakeoffResult contains the calculated takeoff performance data
 type TakeoffResult struct {
-	TakeoffDistance float64 // Distance over 50ft barrier in feet
-	LiftoffSpeed    float64 // Liftoff speed in KIAS
-	BarrierSpeed    float64 // 50ft barrier crossing speed in KIAS
+	TakeoffDistance float64 `json:"takeoff_distance"`    // Distance over 50ft barrier in feet
+	Tolerance       float64 `json:"tolerance,omitempty"` // ± estimated interpolation error of TakeoffDistance in feet
+	GroundRoll      float64 `json:"ground_roll,omitempty"` // Brake release to liftoff in feet, 0 when the chart has no ground roll table
+	LiftoffSpeed    float64 `json:"liftoff_speed"`       // Liftoff speed in KIAS
+	BarrierSpeed    float64 `json:"barrier_speed"`       // 50ft barrier crossing speed in KIAS
+	
+	// Roll estimates the time and acceleration of the ground roll
+	Roll *RollTiming `json:"roll,omitempty"`
+	
+	// Adjustments lists the configuration penalties included in TakeoffDistance
+	Adjustments []Adjustment `json:"adjustments,omitempty"`
+	
+	// Atmosphere shows how the pressure altitude was derived from the field,
+	// when the caller derived it from an elevation and altimeter setting
+	Atmosphere *atmosphere.Field `json:"atmosphere,omitempty"`
 }
 
 // TakeoffCalculator handles the PA-28-161 takeoff performance calculations
 type TakeoffCalculator struct {
+	*takeoffChart
+	
+	adjustments   []Adjustment         // Configuration penalties applied to the charted distance
+	calibration   *AirspeedCalibration // Takeoff flap airspeed calibration; nil takes the indicator as exact
+	deterministic bool                 // Round results to a fixed resolution; see SetDeterministic
+}
+
+// takeoffChart holds the digitized takeoff chart. It is never changed once
+// built, so it is built on first use and shared by every calculator.
+type takeoffChart struct {
+	source Source
+	
 	// These arrays define the data points on the chart
-	altitudes      []float64    // Pressure altitude in feet
-	temperatures   []float64    // Temperature in °C
-	weights        []float64    // Weight in pounds
-	headwinds      []float64    // Headwind in knots
-	tailwinds      []float64    // Tailwind in knots
-	baseDistances  [][]float64  // Base distances with no wind
-	speedsLiftoff  []float64    // Liftoff speeds at different weights
-	speedsBarrier  []float64    // 50ft barrier speeds at different weights
+	altitudes       []float64    // Pressure altitude in feet
+	temperatures    []float64    // Temperature in °C
+	weights         []float64    // Weight in pounds
+	headwinds       []float64    // Headwind in knots
+	tailwinds       []float64    // Tailwind in knots
+	headwindFactors []float64    // Distance factor at each headwind
+	tailwindFactors []float64    // Distance factor at each tailwind
+	baseDistances   [][]float64  // Base distances with no wind
+	groundRolls     [][]float64  // Ground rolls with no wind, laid out as baseDistances; nil if not charted
+	speedsLiftoff   []float64    // Liftoff speeds at different weights
+	speedsBarrier   []float64    // 50ft barrier speeds at different weights
+	flaps           flapTable    // Corrections for other flap settings
 }
 
+// The shared takeoff chart
+var (
+	takeoffChartOnce   sync.Once
+	sharedTakeoffChart *takeoffChart
+)
+
 // NewTakeoffCalculator creates a new takeoff performance calculator
 func NewTakeoffCalculator() *TakeoffCalculator {
-	calc := &TakeoffCalculator{
+	takeoffChartOnce.Do(func() {
+		sharedTakeoffChart = newTakeoffChart()
+	})
+	return &TakeoffCalculator{takeoffChart: sharedTakeoffChart}
+}
+
+// newTakeoffChart builds the takeoff chart from the digitized data
+func newTakeoffChart() *takeoffChart {
+	chart := &takeoffChart{
+		source: Source{
+			Aircraft: "PA-28-161 Cherokee Warrior II",
+			Document: "Pilot's Operating Handbook",
+			Figure:   "5-6",
+			Title:    "Normal Short Field Takeoff Distance",
+		},
+		
 		// Chart data points
 		altitudes:    []float64{0, 1000, 2000, 3000, 4000, 5000, 6000, 7000},
 		temperatures: []float64{-40, -20, 0, 20, 40},
@@ -48,97 +105,211 @@ func NewTakeoffCalculator() *TakeoffCalculator {
 		
 		// 50ft barrier speeds from the chart (KIAS)
 		speedsBarrier: []float64{48, 50, 52, 54, 55},
+		
+		// The chart is drawn for 25° flaps; the POH notes correct a
+		// normal, flaps up takeoff. 40° is not approved for takeoff.
+		flaps: flapTable{
+			charted: Flaps25,
+			corrections: map[FlapSetting]flapCorrection{
+				Flaps25: {distance: 1},
+				Flaps0:  {distance: 1.15, speed: 5},
+			},
+		},
 	}
-
+	for _, w := range chart.headwinds {
+		chart.headwindFactors = append(chart.headwindFactors, headwindFactor(w))
+	}
+	for _, w := range chart.tailwinds {
+		chart.tailwindFactors = append(chart.tailwindFactors, tailwindFactor(w))
+	}
+	
 	// Initialize the base distance matrix [altitude][temperature][weight]
 	// This represents the takeoff distance with no wind correction
-	calc.baseDistances = make([][]float64, len(calc.altitudes))
+	chart.baseDistances = make([][]float64, len(chart.altitudes))
 	
 	// Digitized data from Figure 5-6
 	// These values represent the takeoff distance over a 50ft barrier 
 	// with no wind at different combinations of altitude, temperature, and weight
 	
 	// Sea level (0 ft)
-	calc.baseDistances[0] = []float64{
+	chart.baseDistances[0] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		900,     1050,   1200,   1350,   1500,  // 1600 lbs
-		1050,    1200,   1350,   1500,   1650,  // 1800 lbs
-		1200,    1350,   1500,   1650,   1800,  // 2000 lbs
-		1350,    1500,   1650,   1800,   1950,  // 2200 lbs
-		1450,    1600,   1750,   1900,   2050,  // 2325 lbs
+		575,     700,    825,    950,    1050,  // 1600 lbs
+		750,     900,    1050,   1200,   1350,  // 1800 lbs
+		925,     1100,   1275,   1475,   1650,  // 2000 lbs
+		1100,    1325,   1550,   1775,   2000,  // 2200 lbs
+		1225,    1500,   1750,   2000,   2250,  // 2325 lbs
 	}
 	
 	// 1000 ft
-	calc.baseDistances[1] = []float64{
+	chart.baseDistances[1] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1000,    1150,   1300,   1450,   1600,  // 1600 lbs
-		1150,    1300,   1450,   1600,   1750,  // 1800 lbs
-		1300,    1450,   1600,   1750,   1900,  // 2000 lbs
-		1450,    1600,   1750,   1900,   2050,  // 2200 lbs
-		1550,    1700,   1850,   2000,   2150,  // 2325 lbs
+		675,     800,    925,    1075,   1200,  // 1600 lbs
+		850,     1000,   1175,   1350,   1525,  // 1800 lbs
+		1025,    1250,   1450,   1675,   1875,  // 2000 lbs
+		1250,    1500,   1775,   2025,   2275,  // 2200 lbs
+		1400,    1675,   1975,   2250,   2525,  // 2325 lbs
 	}
 	
 	// 2000 ft
-	calc.baseDistances[2] = []float64{
+	chart.baseDistances[2] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1100,    1250,   1400,   1550,   1700,  // 1600 lbs
-		1250,    1400,   1550,   1700,   1850,  // 1800 lbs
-		1400,    1550,   1700,   1850,   2000,  // 2000 lbs
-		1550,    1700,   1850,   2000,   2150,  // 2200 lbs
-		1650,    1800,   1950,   2100,   2250,  // 2325 lbs
+		750,     900,    1050,   1200,   1350,  // 1600 lbs
+		950,     1150,   1350,   1525,   1725,  // 1800 lbs
+		1175,    1425,   1650,   1900,   2125,  // 2000 lbs
+		1425,    1700,   2000,   2275,   2575,  // 2200 lbs
+		1600,    1900,   2225,   2550,   2875,  // 2325 lbs
 	}
 	
 	// 3000 ft
-	calc.baseDistances[3] = []float64{
+	chart.baseDistances[3] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1200,    1350,   1500,   1650,   1800,  // 1600 lbs
-		1350,    1500,   1650,   1800,   1950,  // 1800 lbs
-		1500,    1650,   1800,   1950,   2100,  // 2000 lbs
-		1650,    1800,   1950,   2100,   2250,  // 2200 lbs
-		1750,    1900,   2050,   2200,   2350,  // 2325 lbs
+		850,     1025,   1200,   1375,   1550,  // 1600 lbs
+		1075,    1300,   1525,   1725,   1950,  // 1800 lbs
+		1325,    1600,   1875,   2150,   2400,  // 2000 lbs
+		1600,    1950,   2275,   2600,   2925,  // 2200 lbs
+		1800,    2175,   2525,   2900,   3250,  // 2325 lbs
 	}
 	
 	// 4000 ft
-	calc.baseDistances[4] = []float64{
+	chart.baseDistances[4] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1300,    1450,   1600,   1750,   1900,  // 1600 lbs
-		1450,    1600,   1750,   1900,   2050,  // 1800 lbs
-		1600,    1750,   1900,   2050,   2200,  // 2000 lbs
-		1750,    1900,   2050,   2200,   2350,  // 2200 lbs
-		1850,    2000,   2150,   2300,   2450,  // 2325 lbs
+		975,     1150,   1350,   1550,   1750,  // 1600 lbs
+		1225,    1475,   1725,   1975,   2200,  // 1800 lbs
+		1500,    1825,   2125,   2425,   2725,  // 2000 lbs
+		1825,    2200,   2575,   2925,   3300,  // 2200 lbs
+		2050,    2450,   2875,   3275,   3700,  // 2325 lbs
 	}
 	
 	// 5000 ft
-	calc.baseDistances[5] = []float64{
+	chart.baseDistances[5] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1450,    1600,   1750,   1900,   2050,  // 1600 lbs
-		1600,    1750,   1900,   2050,   2200,  // 1800 lbs
-		1750,    1900,   2050,   2200,   2350,  // 2000 lbs
-		1900,    2050,   2200,   2350,   2500,  // 2200 lbs
-		2000,    2150,   2300,   2450,   2600,  // 2325 lbs
+		1100,    1325,   1550,   1750,   1975,  // 1600 lbs
+		1375,    1675,   1950,   2225,   2500,  // 1800 lbs
+		1700,    2050,   2400,   2750,   3100,  // 2000 lbs
+		2075,    2500,   2900,   3325,   3750,  // 2200 lbs
+		2300,    2775,   3250,   3725,   4175,  // 2325 lbs
 	}
 	
 	// 6000 ft
-	calc.baseDistances[6] = []float64{
+	chart.baseDistances[6] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1600,    1750,   1900,   2050,   2200,  // 1600 lbs
-		1750,    1900,   2050,   2200,   2350,  // 1800 lbs
-		1900,    2050,   2200,   2350,   2500,  // 2000 lbs
-		2050,    2200,   2350,   2500,   2650,  // 2200 lbs
-		2150,    2300,   2450,   2600,   2750,  // 2325 lbs
+		1250,    1500,   1750,   2000,   2250,  // 1600 lbs
+		1575,    1900,   2200,   2525,   2850,  // 1800 lbs
+		1950,    2325,   2725,   3125,   3500,  // 2000 lbs
+		2350,    2825,   3300,   3775,   4250,  // 2200 lbs
+		2625,    3150,   3675,   4200,   4750,  // 2325 lbs
 	}
 	
 	// 7000 ft
-	calc.baseDistances[7] = []float64{
+	chart.baseDistances[7] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		1400,    1700,   1975,   2250,   2550,  // 1600 lbs
+		1775,    2150,   2500,   2850,   3225,  // 1800 lbs
+		2200,    2650,   3075,   3525,   3975,  // 2000 lbs
+		2650,    3200,   3725,   4275,   4800,  // 2200 lbs
+		2975,    3575,   4175,   4775,   5375,  // 2325 lbs
+	}
+	
+	// Digitized ground roll from the same figure, brake release to liftoff
+	// with no wind, laid out as the distances
+	chart.groundRolls = make([][]float64, len(chart.altitudes))
+	
+	// Sea level (0 ft)
+	chart.groundRolls[0] = []float64{
 		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
-		1750,    1900,   2050,   2200,   2350,  // 1600 lbs
-		1900,    2050,   2200,   2350,   2500,  // 1800 lbs
-		2050,    2200,   2350,   2500,   2650,  // 2000 lbs
-		2200,    2350,   2500,   2650,   2800,  // 2200 lbs
-		2300,    2450,   2600,   2750,   2900,  // 2325 lbs
+		315,     390,    460,    535,    600,   // 1600 lbs
+		415,     505,    595,    685,    775,   // 1800 lbs
+		520,     620,    725,    850,    955,   // 2000 lbs
+		620,     755,    890,    1030,   1170,  // 2200 lbs
+		700,     865,    1015,   1170,   1330,  // 2325 lbs
 	}
+	
+	// 1000 ft
+	chart.groundRolls[1] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		375,     445,    520,    610,    690,   // 1600 lbs
+		475,     565,    670,    775,    885,   // 1800 lbs
+		580,     710,    835,    970,    1095,  // 2000 lbs
+		710,     860,    1030,   1185,   1340,  // 2200 lbs
+		805,     970,    1155,   1325,   1500,  // 2325 lbs
+	}
+	
+	// 2000 ft
+	chart.groundRolls[2] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		420,     505,    595,    690,    780,   // 1600 lbs
+		535,     655,    775,    880,    1005,  // 1800 lbs
+		670,     815,    955,    1110,   1250,  // 2000 lbs
+		815,     985,    1165,   1340,   1530,  // 2200 lbs
+		925,     1110,   1310,   1515,   1720,  // 2325 lbs
+	}
+	
+	// 3000 ft
+	chart.groundRolls[3] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		480,     580,    685,    795,    905,   // 1600 lbs
+		610,     745,    880,    1005,   1145,  // 1800 lbs
+		760,     925,    1095,   1265,   1425,  // 2000 lbs
+		925,     1135,   1335,   1540,   1750,  // 2200 lbs
+		1050,    1280,   1495,   1735,   1960,  // 2325 lbs
+	}
+	
+	// 4000 ft
+	chart.groundRolls[4] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		555,     660,    780,    900,    1030,  // 1600 lbs
+		700,     850,    1005,   1160,   1305,  // 1800 lbs
+		865,     1060,   1250,   1435,   1625,  // 2000 lbs
+		1060,    1290,   1525,   1745,   1985,  // 2200 lbs
+		1205,    1450,   1715,   1970,   2245,  // 2325 lbs
+	}
+	
+	// 5000 ft
+	chart.groundRolls[5] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		630,     765,    900,    1025,   1170,  // 1600 lbs
+		795,     975,    1145,   1315,   1490,  // 1800 lbs
+		990,     1200,   1420,   1640,   1865,  // 2000 lbs
+		1215,    1480,   1730,   2000,   2275,  // 2200 lbs
+		1360,    1655,   1955,   2260,   2555,  // 2325 lbs
+	}
+	
+	// 6000 ft
+	chart.groundRolls[6] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		720,     870,    1025,   1180,   1340,  // 1600 lbs
+		915,     1115,   1300,   1505,   1710,  // 1800 lbs
+		1140,    1375,   1625,   1875,   2120,  // 2000 lbs
+		1390,    1685,   1980,   2285,   2595,  // 2200 lbs
+		1565,    1890,   2225,   2565,   2925,  // 2325 lbs
+	}
+	
+	// 7000 ft
+	chart.groundRolls[7] = []float64{
+		// -40°C   -20°C    0°C    20°C    40°C  (temperatures)
+		810,     995,    1165,   1340,   1530,  // 1600 lbs
+		1040,    1270,   1490,   1710,   1950,  // 1800 lbs
+		1300,    1575,   1845,   2135,   2425,  // 2000 lbs
+		1575,    1920,   2255,   2610,   2950,  // 2200 lbs
+		1785,    2165,   2545,   2935,   3335,  // 2325 lbs
+	}
+	
+	return chart
+}
+
+// AdjustDistance applies a configuration penalty, such as from skis or
+// removed wheel fairings, to every takeoff distance. Results list it in
+// their Adjustments.
+func (c *TakeoffCalculator) AdjustDistance(a Adjustment) {
+	c.adjustments = append(c.adjustments, a)
+}
 
-	return calc
+// CalibrateAirspeed sets the airspeed calibration table for the takeoff
+// flap setting, so the true liftoff speed timing the roll includes the
+// airspeed system's error
+func (c *TakeoffCalculator) CalibrateAirspeed(a *AirspeedCalibration) {
+	c.calibration = a
 }
 
 // CalculateTakeoff calculates takeoff performance based on the input parameters
@@ -160,106 +331,243 @@ func (c *TakeoffCalculator) CalculateTakeoff(params TakeoffParams) (*TakeoffResu
 		return nil, err
 	}
 	
+	// Step 3: Read the ground roll the same way, where it is charted
+	baseRoll := c.calculateBaseGroundRoll(params)
+	
 	// Calculate speeds
 	liftoffSpeed := c.calculateLiftoffSpeed(params.Weight)
 	barrierSpeed := c.calculateBarrierSpeed(params.Weight)
 	
-	return &TakeoffResult{
-		TakeoffDistance: finalDistance,
+	return c.result(params, baseDistance, finalDistance, baseRoll, liftoffSpeed, barrierSpeed), nil
+}
+
+// result assembles a takeoff result from the chart readings, applying the
+// flap correction and adjustments and, in deterministic mode, rounding it. A baseRoll of 0
+// means the chart has no ground roll, which is then estimated for the
+// roll timing only.
+func (c *TakeoffCalculator) result(params TakeoffParams, baseDistance, finalDistance, baseRoll, liftoffSpeed, barrierSpeed float64) *TakeoffResult {
+	flaps, _ := c.flaps.correction(params.FlapSetting)
+	liftoffSpeed += flaps.speed
+	barrierSpeed += flaps.speed
+	factor := flaps.distance * adjustmentFactor(c.adjustments)
+	distance := finalDistance * factor
+	
+	// The chart's wind lines correct the ground roll as they do the distance
+	calmRoll, roll := baseDistance * groundRollFraction, finalDistance * groundRollFraction
+	var groundRoll float64
+	if baseRoll > 0 {
+		calmRoll = baseRoll
+		roll, _ = c.applyWindCorrection(baseRoll, params.WindComponent)
+		groundRoll = roll * factor
+	}
+	
+	r := &TakeoffResult{
+		TakeoffDistance: distance,
+		Tolerance:       c.tolerance(params, baseDistance, finalDistance),
+		GroundRoll:      groundRoll,
 		LiftoffSpeed:    liftoffSpeed,
 		BarrierSpeed:    barrierSpeed,
-	}, nil
+		Roll:            rollTiming(params, calmRoll * factor, roll * factor, c.calibration.Calibrated(liftoffSpeed)),
+		Adjustments:     c.appliedAdjustments(),
+	}
+	if c.deterministic {
+		quantize(r)
+	}
+	return r
 }
 
-// validateInputs ensures all input parameters are within chart limits
-func (c *TakeoffCalculator) validateInputs(params TakeoffParams) error {
+// appliedAdjustments returns a copy of the adjustments for a result, or nil
+func (c *TakeoffCalculator) appliedAdjustments() []Adjustment {
+	if len(c.adjustments) == 0 {
+		return nil
+	}
+	return append([]Adjustment(nil), c.adjustments...)
+}
+
+// Validate checks every input parameter against the chart limits and returns
+// all violations found, or nil if the parameters are within the envelope
+func (c *TakeoffCalculator) Validate(params TakeoffParams) ValidationErrors {
+	errs := validateChartInputs(c.altitudes, c.temperatures, c.weights, c.headwinds, c.tailwinds,
+		params.PressureAltitude, params.Temperature, params.Weight, params.WindComponent)
+	if err := c.flaps.validate(params.FlapSetting, "takeoff"); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateChartInputs checks the inputs of a chart with pressure altitude,
+// temperature, weight and wind lines against its limits, as Validate does
+func validateChartInputs(altitudes, temperatures, weights, headwinds, tailwinds []float64,
+	pressureAltitude, temperature, weight, wind float64) ValidationErrors {
+	var errs ValidationErrors
+	
 	// Use sea level values for pressure altitudes below 0
-	adjustedAltitude := params.PressureAltitude
+	adjustedAltitude := pressureAltitude
 	if adjustedAltitude < 0 {
 		adjustedAltitude = 0
 	}
 	
 	// Check pressure altitude (maximum 7000 ft)
-	if adjustedAltitude > c.altitudes[len(c.altitudes)-1] {
-		return fmt.Errorf("pressure altitude (%.0f ft) exceeds maximum chart value (%.0f ft)", 
-			params.PressureAltitude, c.altitudes[len(c.altitudes)-1])
+	maxAltitude := altitudes[len(altitudes)-1]
+	if adjustedAltitude > maxAltitude {
+		errs = append(errs, &ValidationError{
+			Field:   FieldPressureAltitude,
+			Code:    CodeAboveMaximum,
+			Value:   pressureAltitude,
+			Min:     altitudes[0],
+			Max:     maxAltitude,
+			Message: fmt.Sprintf("pressure altitude (%.0f ft) exceeds maximum chart value (%.0f ft)", 
+				pressureAltitude, maxAltitude),
+		})
 	}
 	
 	// Check temperature (-40°C to 40°C)
-	if params.Temperature < c.temperatures[0] || params.Temperature > c.temperatures[len(c.temperatures)-1] {
-		return fmt.Errorf("temperature (%.1f°C) outside chart range (%.1f°C to %.1f°C)", 
-			params.Temperature, c.temperatures[0], c.temperatures[len(c.temperatures)-1])
+	minTemp, maxTemp := temperatures[0], temperatures[len(temperatures)-1]
+	if temperature < minTemp || temperature > maxTemp {
+		errs = append(errs, &ValidationError{
+			Field:   FieldTemperature,
+			Code:    rangeCode(temperature, minTemp),
+			Value:   temperature,
+			Min:     minTemp,
+			Max:     maxTemp,
+			Message: fmt.Sprintf("temperature (%.1f°C) outside chart range (%.1f°C to %.1f°C)", 
+				temperature, minTemp, maxTemp),
+		})
 	}
 	
 	// Check weight (1600 lbs to 2325 lbs)
-	if params.Weight < c.weights[0] || params.Weight > c.weights[len(c.weights)-1] {
-		return fmt.Errorf("weight (%.0f lbs) outside chart range (%.0f lbs to %.0f lbs)", 
-			params.Weight, c.weights[0], c.weights[len(c.weights)-1])
+	minWeight, maxWeight := weights[0], weights[len(weights)-1]
+	if weight < minWeight || weight > maxWeight {
+		errs = append(errs, &ValidationError{
+			Field:   FieldWeight,
+			Code:    rangeCode(weight, minWeight),
+			Value:   weight,
+			Min:     minWeight,
+			Max:     maxWeight,
+			Message: fmt.Sprintf("weight (%.0f lbs) outside chart range (%.0f lbs to %.0f lbs)", 
+				weight, minWeight, maxWeight),
+		})
 	}
 	
 	// Check wind component
-	if params.WindComponent > c.headwinds[len(c.headwinds)-1] {
-		return fmt.Errorf("headwind component (%.0f kts) exceeds maximum chart value (%.0f kts)", 
-			params.WindComponent, c.headwinds[len(c.headwinds)-1])
+	maxHeadwind, maxTailwind := headwinds[len(headwinds)-1], tailwinds[len(tailwinds)-1]
+	if wind > maxHeadwind {
+		errs = append(errs, &ValidationError{
+			Field:   FieldWindComponent,
+			Code:    CodeAboveMaximum,
+			Value:   wind,
+			Min:     -maxTailwind,
+			Max:     maxHeadwind,
+			Message: fmt.Sprintf("headwind component (%.0f kts) exceeds maximum chart value (%.0f kts)", 
+				wind, maxHeadwind),
+		})
 	}
-	if params.WindComponent < -c.tailwinds[len(c.tailwinds)-1] {
-		return fmt.Errorf("tailwind component (%.0f kts) exceeds maximum chart value (%.0f kts)", 
-			-params.WindComponent, c.tailwinds[len(c.tailwinds)-1])
+	if wind < -maxTailwind {
+		errs = append(errs, &ValidationError{
+			Field:   FieldWindComponent,
+			Code:    CodeBelowMinimum,
+			Value:   wind,
+			Min:     -maxTailwind,
+			Max:     maxHeadwind,
+			Message: fmt.Sprintf("tailwind component (%.0f kts) exceeds maximum chart value (%.0f kts)", 
+				-wind, maxTailwind),
+		})
 	}
 	
+	return errs
+}
+
+// validateInputs ensures all input parameters are within chart limits
+func (c *TakeoffCalculator) validateInputs(params TakeoffParams) error {
+	if errs := c.Validate(params); len(errs) > 0 {
+		return errs[0]
+	}
 	return nil
 }
 
 // calculateBaseDistance determines the zero-wind takeoff distance
 func (c *TakeoffCalculator) calculateBaseDistance(params TakeoffParams) (float64, error) {
-	// Step 1: Find indices for altitude interpolation
-	altIdx1, altIdx2, altFrac := findInterpolationIndices(c.altitudes, params.PressureAltitude)
+	// Step 1: Find the chart cell surrounding the inputs
+	alt := newBracket(c.altitudes, params.PressureAltitude)
+	temp := newBracket(c.temperatures, params.Temperature)
+	weight := newBracket(c.weights, params.Weight)
 	
-	// Step 2: Find indices for temperature interpolation
-	tempIdx1, tempIdx2, tempFrac := findInterpolationIndices(c.temperatures, params.Temperature)
+	// Step 2: Look up the chart values at the corners of the cell
+	corners := c.baseCorners(alt, temp, weight)
 	
-	// Step 3: Find indices for weight interpolation
-	weightIdx1, weightIdx2, weightFrac := findInterpolationIndices(c.weights, params.Weight)
+	// Step 3: Perform trilinear interpolation to get the base distance
+	return interpolateCorners(corners, alt, temp, weight), nil
+}
+
+// calculateBaseGroundRoll determines the zero-wind ground roll, or 0 when
+// the chart has none
+func (c *TakeoffCalculator) calculateBaseGroundRoll(params TakeoffParams) float64 {
+	if c.groundRolls == nil {
+		return 0
+	}
+	alt := newBracket(c.altitudes, params.PressureAltitude)
+	temp := newBracket(c.temperatures, params.Temperature)
+	weight := newBracket(c.weights, params.Weight)
+	return interpolateCorners(c.groundRollCorners(alt, temp, weight), alt, temp, weight)
+}
+
+// baseCorners retrieves the eight chart values surrounding a point, indexed [altitude][temperature][weight]
+func (c *TakeoffCalculator) baseCorners(alt, temp, weight bracket) [2][2][2]float64 {
+	return c.cellCorners(c.baseDistances, alt, temp, weight)
+}
+
+// groundRollCorners retrieves the eight ground roll values surrounding a
+// point, as baseCorners does the distances
+func (c *TakeoffCalculator) groundRollCorners(alt, temp, weight bracket) [2][2][2]float64 {
+	return c.cellCorners(c.groundRolls, alt, temp, weight)
+}
+
+// cellCorners retrieves the eight values of a chart table surrounding a point
+func (c *TakeoffCalculator) cellCorners(table [][]float64, alt, temp, weight bracket) [2][2][2]float64 {
+	var corners [2][2][2]float64
 	
-	// Step 4: Perform trilinear interpolation to get the base distance
+	altIndices := [2]int{alt.lo, alt.hi}
+	tempIndices := [2]int{temp.lo, temp.hi}
+	weightIndices := [2]int{weight.lo, weight.hi}
+	
+	for i := 0; i <= 1; i++ {
+		for j := 0; j <= 1; j++ {
+			for k := 0; k <= 1; k++ {
+				corners[i][j][k] = c.getChartValue(table, altIndices[i], tempIndices[j], weightIndices[k])
+			}
+		}
+	}
+	
+	return corners
+}
+
+// interpolateCorners performs trilinear interpolation across a chart cell
+func interpolateCorners(corners [2][2][2]float64, alt, temp, weight bracket) float64 {
 	// First, interpolate across weight for each altitude and temperature combination
 	var distances [2][2]float64
 	
 	for i := 0; i <= 1; i++ {
 		for j := 0; j <= 1; j++ {
-			// Calculate matrix index for weights
-			altIndex := altIdx1
-			if i == 1 && altIdx1 != altIdx2 {
-				altIndex = altIdx2
-			}
-			
-			tempIndex := tempIdx1
-			if j == 1 && tempIdx1 != tempIdx2 {
-				tempIndex = tempIdx2
-			}
-			
-			// Get values for the weight endpoints
-			val1 := c.getBaseDistance(altIndex, tempIndex, weightIdx1)
-			val2 := c.getBaseDistance(altIndex, tempIndex, weightIdx2)
-			
-			// Interpolate across weight
-			distances[i][j] = val1 * (1 - weightFrac) + val2 * weightFrac
+			distances[i][j] = lerp(corners[i][j][0], corners[i][j][1], weight.frac)
 		}
 	}
 	
 	// Next, interpolate across temperature
 	var distAlt [2]float64
-	distAlt[0] = distances[0][0] * (1 - tempFrac) + distances[0][1] * tempFrac
-	distAlt[1] = distances[1][0] * (1 - tempFrac) + distances[1][1] * tempFrac
+	distAlt[0] = lerp(distances[0][0], distances[0][1], temp.frac)
+	distAlt[1] = lerp(distances[1][0], distances[1][1], temp.frac)
 	
 	// Finally, interpolate across altitude
-	baseDistance := distAlt[0] * (1 - altFrac) + distAlt[1] * altFrac
-	
-	return baseDistance, nil
+	return lerp(distAlt[0], distAlt[1], alt.frac)
 }
 
 // getBaseDistance safely retrieves a value from the baseDistances array
 func (c *TakeoffCalculator) getBaseDistance(altIndex, tempIndex, weightIndex int) float64 {
+	return c.getChartValue(c.baseDistances, altIndex, tempIndex, weightIndex)
+}
+
+// getChartValue safely retrieves a value from a table laid out as the baseDistances array
+func (c *TakeoffCalculator) getChartValue(table [][]float64, altIndex, tempIndex, weightIndex int) float64 {
 	// Convert to flat index using the layout of the baseDistances array
 	// Each altitude has a 2D array of [temperature][weight]
 	
@@ -268,18 +576,18 @@ func (c *TakeoffCalculator) getBaseDistance(altIndex, tempIndex, weightIndex int
 	// and each column is a temperature
 	
 	// Ensure the indices are valid to prevent panic
-	if altIndex < 0 || altIndex >= len(c.baseDistances) {
+	if altIndex < 0 || altIndex >= len(table) {
 		return 0
 	}
 	
 	// For temperature and weight, access the flattened 2D matrix
 	flatIndex := weightIndex*len(c.temperatures) + tempIndex
 	
-	if flatIndex < 0 || flatIndex >= len(c.baseDistances[altIndex]) {
+	if flatIndex < 0 || flatIndex >= len(table[altIndex]) {
 		return 0
 	}
 	
-	return c.baseDistances[altIndex][flatIndex]
+	return table[altIndex][flatIndex]
 }
 
 // applyWindCorrection adjusts the base takeoff distance for wind
@@ -294,14 +602,10 @@ func (c *TakeoffCalculator) applyWindCorrection(baseDistance, windComponent floa
 		// Find indices for headwind interpolation
 		windIdx1, windIdx2, windFrac := findInterpolationIndices(c.headwinds, windComponent)
 		
-		// Calculate the correction factors for the bracket headwind values
-		// Chart shows approximately 9-10% reduction per 15 knots of headwind
-		// Simplified formula: correction = distance * (1 - wind/15 * 0.10)
-		
 		// Calculate correction for each bracket value and interpolate
-		factor1 := 1.0 - (c.headwinds[windIdx1] / 15.0) * 0.10
-		factor2 := 1.0 - (c.headwinds[windIdx2] / 15.0) * 0.10
-		finalFactor := factor1 * (1 - windFrac) + factor2 * windFrac
+		factor1 := c.headwindFactors[windIdx1]
+		factor2 := c.headwindFactors[windIdx2]
+		finalFactor := lerp(factor1, factor2, windFrac)
 		
 		return baseDistance * finalFactor, nil
 	}
@@ -313,18 +617,30 @@ func (c *TakeoffCalculator) applyWindCorrection(baseDistance, windComponent floa
 	// Find indices for tailwind interpolation
 	windIdx1, windIdx2, windFrac := findInterpolationIndices(c.tailwinds, tailwind)
 	
-	// Calculate the correction factors for the bracket tailwind values
-	// Chart shows approximately 10% increase per 5 knots of tailwind
-	// Simplified formula: correction = distance * (1 + wind/5 * 0.10)
-	
 	// Calculate correction for each bracket value and interpolate
-	factor1 := 1.0 + (c.tailwinds[windIdx1] / 5.0) * 0.10
-	factor2 := 1.0 + (c.tailwinds[windIdx2] / 5.0) * 0.10
-	finalFactor := factor1 * (1 - windFrac) + factor2 * windFrac
+	factor1 := c.tailwindFactors[windIdx1]
+	factor2 := c.tailwindFactors[windIdx2]
+	finalFactor := lerp(factor1, factor2, windFrac)
 	
 	return baseDistance * finalFactor, nil
 }
 
+// headwindFactor returns the distance correction factor at a charted headwind value
+func headwindFactor(headwind float64) float64 {
+	// Chart shows approximately 16% reduction per 15 knots of headwind
+	// Simplified formula: correction = distance * (1 - wind/15 * 0.16),
+	// with the product rounded as in lerp so it is never fused
+	return 1.0 - float64((headwind / 15.0) * 0.16)
+}
+
+// tailwindFactor returns the distance correction factor at a charted tailwind value
+func tailwindFactor(tailwind float64) float64 {
+	// Chart shows approximately 10% increase per 5 knots of tailwind
+	// Simplified formula: correction = distance * (1 + wind/5 * 0.10),
+	// with the product rounded as in lerp so it is never fused
+	return 1.0 + float64((tailwind / 5.0) * 0.10)
+}
+
 // calculateLiftoffSpeed determines the appropriate liftoff speed based on weight
 func (c *TakeoffCalculator) calculateLiftoffSpeed(weight float64) float64 {
 	// Find indices for weight interpolation
@@ -334,7 +650,7 @@ func (c *TakeoffCalculator) calculateLiftoffSpeed(weight float64) float64 {
 	speed1 := c.speedsLiftoff[weightIdx1]
 	speed2 := c.speedsLiftoff[weightIdx2]
 	
-	return speed1 * (1 - weightFrac) + speed2 * weightFrac
+	return lerp(speed1, speed2, weightFrac)
 }
 
 // calculateBarrierSpeed determines the appropriate 50ft barrier speed based on weight
@@ -346,7 +662,7 @@ func (c *TakeoffCalculator) calculateBarrierSpeed(weight float64) float64 {
 	speed1 := c.speedsBarrier[weightIdx1]
 	speed2 := c.speedsBarrier[weightIdx2]
 	
-	return speed1 * (1 - weightFrac) + speed2 * weightFrac
+	return lerp(speed1, speed2, weightFrac)
 }
 
 // findInterpolationIndices finds the bracketing indices and interpolation fraction
@@ -374,12 +690,45 @@ func findInterpolationIndices(array []float64, value float64) (int, int, float64
 	return 0, 0, 0.0
 }
 
+// bracket holds the chart indices surrounding an input value and the
+// interpolation fraction between them
+type bracket struct {
+	lo, hi int
+	frac   float64
+}
+
+// newBracket finds the bracket for a value along a chart axis
+func newBracket(array []float64, value float64) bracket {
+	lo, hi, frac := findInterpolationIndices(array, value)
+	return bracket{lo: lo, hi: hi, frac: frac}
+}
+
+// within reports whether a value still falls inside the bracket's grid cell,
+// in which case only the fraction needs to be recomputed
+func (b bracket) within(array []float64, value float64) bool {
+	return b.lo != b.hi && value >= array[b.lo] && value < array[b.hi]
+}
+
+// update moves the bracket to a new value, reusing the current cell when
+// possible. It reports whether the cell changed.
+func (b *bracket) update(array []float64, value float64) bool {
+	if b.within(array, value) {
+		b.frac = (value - array[b.lo]) / (array[b.hi] - array[b.lo])
+		return false
+	}
+	
+	next := newBracket(array, value)
+	changed := next.lo != b.lo || next.hi != b.hi
+	*b = next
+	return changed
+}
+
 // ConvertFahrenheitToCelsius converts temperature from °F to °C
 func ConvertFahrenheitToCelsius(fahrenheit float64) float64 {
-	return (fahrenheit - 32) * 5 / 9
+	return units.FahrenheitToCelsius(fahrenheit)
 }
 
 // ConvertCelsiusToFahrenheit converts temperature from °C to °F
 func ConvertCelsiusToFahrenheit(celsius float64) float64 {
-	return (celsius * 9 / 5) + 32
+	return units.CelsiusToFahrenheit(celsius)
 }
@@ -291,4 +291,40 @@ func TestTemperatureConversion(t *testing.T) {
 			t.Errorf("C to F conversion: got %.1f°F, expected %.1f°F for %.1f°C", 
 				gotF, tc.fahrenheit, tc.celsius)
 		}
-	}
\ No newline at end of file
+	}
+}
+
+func TestValidateReportsAllViolations(t *testing.T) {
+	calculator := NewTakeoffCalculator()
+	
+	errs := calculator.Validate(TakeoffParams{
+		PressureAltitude: 8000,
+		Temperature:      50,
+		Weight:           1500,
+		WindComponent:    20,
+	})
+	
+	expected := []struct {
+		field string
+		code  string
+	}{
+		{FieldPressureAltitude, CodeAboveMaximum},
+		{FieldTemperature, CodeAboveMaximum},
+		{FieldWeight, CodeBelowMinimum},
+		{FieldWindComponent, CodeAboveMaximum},
+	}
+	
+	if len(errs) != len(expected) {
+		t.Fatalf("Got %d errors, expected %d: %v", len(errs), len(expected), errs)
+	}
+	for i, exp := range expected {
+		if errs[i].Field != exp.field || errs[i].Code != exp.code {
+			t.Errorf("Error %d: got %s/%s, expected %s/%s", i, errs[i].Field, errs[i].Code, exp.field, exp.code)
+		}
+	}
+	
+	// Valid inputs produce no errors
+	if errs := calculator.Validate(TakeoffParams{PressureAltitude: 1000, Temperature: 15, Weight: 2000}); errs != nil {
+		t.Errorf("Expected no errors for valid inputs, but got: %v", errs)
+	}
+}
@@ -0,0 +1,92 @@
+package performance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldLandingTechnique is the validation error field for a landing
+// technique the chart has no correction for
+const FieldLandingTechnique = "technique"
+
+// LandingTechnique is how a landing is flown. The zero value is the chart's
+// own, the maximum effort short field technique.
+type LandingTechnique string
+
+// Landing techniques
+const (
+	TechniqueCharted    LandingTechnique = ""            // As charted: short field for the PA-28-161
+	TechniqueShortField LandingTechnique = "short-field" // Slowest approach speed, touchdown at the threshold and maximum braking
+	TechniqueNormal     LandingTechnique = "normal"      // Normal approach speed, some float and moderate braking
+)
+
+// ParseLandingTechnique parses a landing technique; "short" and
+// "shortfield" are short-field
+func ParseLandingTechnique(s string) (LandingTechnique, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return TechniqueCharted, nil
+	case "short-field", "short", "shortfield":
+		return TechniqueShortField, nil
+	case "normal":
+		return TechniqueNormal, nil
+	}
+	return "", fmt.Errorf("invalid landing technique %q (short-field or normal)", s)
+}
+
+// String formats the technique for display, e.g. "normal technique"
+func (t LandingTechnique) String() string {
+	if t == TechniqueCharted {
+		return "charted technique"
+	}
+	return string(t) + " technique"
+}
+
+// techniqueCorrection corrects a landing chart for another technique.
+// The air distance from 50 ft to touchdown and the ground roll are
+// corrected apart, as a faster approach floats further and lighter
+// braking lengthens only the roll.
+type techniqueCorrection struct {
+	airDistance float64 // Factor on the distance from 50 ft to touchdown
+	groundRoll  float64 // Factor on the ground roll
+	speed       float64 // Knots added to the approach speed
+}
+
+// techniqueTable lists the techniques a landing chart can be corrected for
+type techniqueTable struct {
+	charted     LandingTechnique                         // The chart's own technique
+	corrections map[LandingTechnique]techniqueCorrection // Corrections, the charted technique's included
+}
+
+// correction returns the correction for a technique, and false when the
+// chart has none
+func (t techniqueTable) correction(technique LandingTechnique) (techniqueCorrection, bool) {
+	if technique == TechniqueCharted {
+		technique = t.charted
+	}
+	if c, ok := t.corrections[technique]; ok {
+		return c, true
+	}
+	return techniqueCorrection{airDistance: 1, groundRoll: 1}, technique == t.charted
+}
+
+// validate checks that the chart can be corrected for a technique
+func (t techniqueTable) validate(technique LandingTechnique) *ValidationError {
+	if _, ok := t.correction(technique); ok {
+		return nil
+	}
+	names := make([]string, 0, len(t.corrections))
+	for s := range t.corrections {
+		names = append(names, string(s))
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		names = append(names, "charted technique only")
+	}
+	return &ValidationError{
+		Field:   FieldLandingTechnique,
+		Code:    CodeUnsupported,
+		Message: fmt.Sprintf("no landing correction for %s (%s)", technique, strings.Join(names, ", ")),
+	}
+}
@@ -0,0 +1,72 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseLandingTechnique(t *testing.T) {
+	tests := []struct {
+		in   string
+		want LandingTechnique
+	}{
+		{"", TechniqueCharted},
+		{"short-field", TechniqueShortField},
+		{" Short ", TechniqueShortField},
+		{"NORMAL", TechniqueNormal},
+	}
+	for _, tc := range tests {
+		if got, err := ParseLandingTechnique(tc.in); err != nil || got != tc.want {
+			t.Errorf("ParseLandingTechnique(%q) = %q, %v, expected %q", tc.in, got, err, tc.want)
+		}
+	}
+	if _, err := ParseLandingTechnique("soft-field"); err == nil {
+		t.Error("Expected an error for an unknown technique")
+	}
+}
+
+func TestLandingTechnique(t *testing.T) {
+	calc := NewLandingCalculator()
+	params := LandingParams{PressureAltitude: 1000, Temperature: 20, Weight: 2200, WindComponent: 5}
+	charted, err := calc.CalculateLanding(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	// The chart is drawn for the short field technique
+	params.Technique = TechniqueShortField
+	if got, _ := calc.CalculateLanding(params); *got != *charted {
+		t.Errorf("Expected the short field technique to be the charted result %+v, got %+v", charted, got)
+	}
+	
+	// A normal landing floats further and brakes lighter
+	params.Technique = TechniqueNormal
+	normal, err := calc.CalculateLanding(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	air := charted.LandingDistance - charted.GroundRoll
+	if math.Abs(normal.GroundRoll - charted.GroundRoll * 1.4) > 0.01 || math.Abs(normal.LandingDistance - (air * 1.25 + normal.GroundRoll)) > 0.01 {
+		t.Errorf("Expected the normal distances from %+v, got %+v", charted, normal)
+	}
+	if normal.ApproachSpeed != 70 {
+		t.Errorf("Expected a normal approach speed of 70 KIAS, got %.0f", normal.ApproachSpeed)
+	}
+	explanation, _ := calc.Explain(params)
+	if len(explanation.Steps) != 8 || explanation.Steps[3].Value != 1.25 || explanation.Steps[4].Value != 1.4 {
+		t.Errorf("Expected technique correction steps, got %+v", explanation.Steps)
+	}
+	
+	// The flap and technique corrections combine
+	params.FlapSetting = Flaps25
+	if got, _ := calc.CalculateLanding(params); got.ApproachSpeed != 74 || got.GroundRoll <= normal.GroundRoll {
+		t.Errorf("Expected a faster, longer normal landing with flaps 25°, got %+v", got)
+	}
+	
+	params.Technique = LandingTechnique("soft-field")
+	errs := calc.Validate(params)
+	if len(errs) != 1 || errs[0].Field != FieldLandingTechnique || errs[0].Code != CodeUnsupported ||
+		errs[0].Message != "no landing correction for soft-field technique (normal, short-field)" {
+		t.Errorf("Expected an unsupported technique error, got %v", errs)
+	}
+}
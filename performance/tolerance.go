@@ -0,0 +1,82 @@
+package performance
+
+import (
+	"math"
+)
+
+// stencil is the chart lines along one axis that an interpolated value is
+// taken from, and the weight of each
+type stencil struct {
+	indices []int
+	weights []float64
+}
+
+// linearStencil weights the two lines bracketing a value
+func linearStencil(b bracket) stencil {
+	return stencil{indices: []int{b.lo, b.hi}, weights: []float64{1 - b.frac, b.frac}}
+}
+
+// cubicStencil weights the four lines nearest a value by the cubic through
+// them, moving the four inward at the ends of the axis. On a line, or on an
+// axis of fewer than four lines, it is the linear stencil.
+func cubicStencil(axis []float64, value float64) stencil {
+	b := newBracket(axis, value)
+	if len(axis) < 4 || b.lo == b.hi || b.frac == 0 {
+		return linearStencil(b)
+	}
+	
+	start := b.lo - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > len(axis) - 4 {
+		start = len(axis) - 4
+	}
+	
+	// Lagrange basis polynomials of the four lines at the value
+	s := stencil{indices: make([]int, 4), weights: make([]float64, 4)}
+	for i := 0; i < 4; i++ {
+		weight := 1.0
+		for j := 0; j < 4; j++ {
+			if j != i {
+				weight *= (value - axis[start + j]) / (axis[start + i] - axis[start + j])
+			}
+		}
+		s.indices[i], s.weights[i] = start + i, weight
+	}
+	return s
+}
+
+// cubicBaseDistance interpolates the zero-wind takeoff distance with cubics
+// along altitude, temperature and weight instead of straight lines. Each
+// term is rounded before it is summed, as in lerp, so the sum is never
+// fused into multiply-adds.
+func (c *TakeoffCalculator) cubicBaseDistance(params TakeoffParams) float64 {
+	alt := cubicStencil(c.altitudes, params.PressureAltitude)
+	temp := cubicStencil(c.temperatures, params.Temperature)
+	weight := cubicStencil(c.weights, params.Weight)
+	
+	distance := 0.0
+	for i, a := range alt.indices {
+		for j, t := range temp.indices {
+			for k, w := range weight.indices {
+				distance += float64(alt.weights[i] * temp.weights[j] * weight.weights[k] * c.getBaseDistance(a, t, w))
+			}
+		}
+	}
+	return distance
+}
+
+// tolerance estimates the interpolation error of a takeoff distance as the
+// difference between the linear and cubic readings of the chart, carried
+// through the same wind correction, flap correction and adjustments. It grows with the grid
+// spacing and the curvature of the chart lines around the inputs, and is
+// zero where the inputs fall on the chart's lines.
+func (c *TakeoffCalculator) tolerance(params TakeoffParams, baseDistance, finalDistance float64) float64 {
+	if baseDistance <= 0 {
+		return 0
+	}
+	flaps, _ := c.flaps.correction(params.FlapSetting)
+	difference := math.Abs(c.cubicBaseDistance(params) - baseDistance)
+	return difference * finalDistance / baseDistance * flaps.distance * adjustmentFactor(c.adjustments)
+}
@@ -0,0 +1,68 @@
+package performance
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCubicStencil(t *testing.T) {
+	// The cubic through four lines reproduces any cubic exactly, even unevenly spaced and at the ends of the axis
+	axis := []float64{1600, 1800, 2000, 2200, 2325}
+	f := func(x float64) float64 { x /= 1000; return x*x*x - 2*x*x + 3 }
+	for _, value := range []float64{1650, 1900, 2100, 2300} {
+		s := cubicStencil(axis, value)
+		got := 0.0
+		for i, index := range s.indices {
+			got += s.weights[i] * f(axis[index])
+		}
+		if math.Abs(got - f(value)) > 1e-9 {
+			t.Errorf("At %g: expected %g, got %g", value, f(value), got)
+		}
+	}
+	
+	if s := cubicStencil([]float64{0, 5}, 2.5); len(s.indices) != 2 || s.weights[0] != 0.5 {
+		t.Errorf("Expected the linear stencil on a two-line axis, got %+v", s)
+	}
+}
+
+func TestTakeoffTolerance(t *testing.T) {
+	calc := NewTakeoffCalculator()
+	
+	onGrid, err := calc.CalculateTakeoff(TakeoffParams{PressureAltitude: 2000, Temperature: 20, Weight: 2200, WindComponent: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onGrid.Tolerance != 0 {
+		t.Errorf("Expected no tolerance on the chart's lines, got %.1f ft", onGrid.Tolerance)
+	}
+	
+	params := TakeoffParams{PressureAltitude: 6500, Temperature: 35, Weight: 2250, WindComponent: -3}
+	result, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tolerance <= 0 || result.Tolerance > result.TakeoffDistance * 0.05 {
+		t.Errorf("Expected a small positive tolerance between chart lines, got %.1f ft on %.0f ft",
+			result.Tolerance, result.TakeoffDistance)
+	}
+	
+	// The flaps up correction widens the band with the distance it brackets
+	flapsParams := params
+	flapsParams.FlapSetting = Flaps0
+	flapsUp, err := calc.CalculateTakeoff(flapsParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := flapsUp.Tolerance / result.Tolerance, flapsUp.TakeoffDistance / result.TakeoffDistance; math.Abs(got - want) > 1e-9 {
+		t.Errorf("Expected the flaps up tolerance to grow by %.4f with the distance, got %.4f", want, got)
+	}
+	
+	session := calc.NewSession(params)
+	sessionResult, err := session.Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sessionResult.Tolerance != result.Tolerance {
+		t.Errorf("Expected the session tolerance %.3f to match %.3f", sessionResult.Tolerance, result.Tolerance)
+	}
+}
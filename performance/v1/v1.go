@@ -0,0 +1,125 @@
+// Package v1 is the stable API of the performance library.
+//
+// Integrators (EFB apps, automation) should import this package rather than
+// performance directly. The names, fields, signatures and JSON field names
+// declared here will not change incompatibly within v1. The performance
+// package is the implementation and may be reorganized; when that happens,
+// the declarations here are kept working as aliases or shims over the new
+// types. Anything not declared here (sessions, gradients) is experimental.
+package v1
+
+import (
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// APIVersion is the major version of this API surface
+const APIVersion = 1
+
+// TakeoffParams represents the input parameters for takeoff performance calculations
+type TakeoffParams = performance.TakeoffParams
+
+// FlapSetting is a flap configuration in degrees; the zero value is the chart's own
+type FlapSetting = performance.FlapSetting
+
+// Flap settings with corrections in the POH notes
+const (
+	FlapsCharted = performance.FlapsCharted
+	Flaps0       = performance.Flaps0
+	Flaps25      = performance.Flaps25
+	Flaps40      = performance.Flaps40
+)
+
+// ParseFlapSetting parses a flap setting in degrees
+func ParseFlapSetting(s string) (FlapSetting, error) {
+	return performance.ParseFlapSetting(s)
+}
+
+// TakeoffResult contains the calculated takeoff performance data
+type TakeoffResult = performance.TakeoffResult
+
+// Adjustment is a configuration penalty disclosed in a takeoff result
+type Adjustment = performance.Adjustment
+
+// ReverseResult contains the answer from a reverse solver
+type ReverseResult = performance.ReverseResult
+
+// SolverLimit identifies what bounds the answer of a reverse solver
+type SolverLimit = performance.SolverLimit
+
+// Reverse solver limits
+const (
+	LimitRunway = performance.LimitRunway
+	LimitChart  = performance.LimitChart
+)
+
+// ValidationError describes a single input that is missing or outside the chart envelope
+type ValidationError = performance.ValidationError
+
+// ValidationErrors collects every validation failure for a set of inputs
+type ValidationErrors = performance.ValidationErrors
+
+// Field names used in validation errors
+const (
+	FieldPressureAltitude = performance.FieldPressureAltitude
+	FieldTemperature      = performance.FieldTemperature
+	FieldWeight           = performance.FieldWeight
+	FieldWindComponent    = performance.FieldWindComponent
+	FieldFlapSetting      = performance.FieldFlapSetting
+)
+
+// Validation error codes
+const (
+	CodeMissing      = performance.CodeMissing
+	CodeBelowMinimum = performance.CodeBelowMinimum
+	CodeAboveMaximum = performance.CodeAboveMaximum
+	CodeUnsupported  = performance.CodeUnsupported
+)
+
+// TakeoffCalculator computes takeoff performance for one aircraft type
+type TakeoffCalculator interface {
+	// CalculateTakeoff computes the takeoff distance and speeds
+	CalculateTakeoff(params TakeoffParams) (*TakeoffResult, error)
+	// Validate reports every input outside the chart envelope
+	Validate(params TakeoffParams) ValidationErrors
+	// MaxWeight finds the heaviest weight that fits the available distance
+	MaxWeight(params TakeoffParams, availableDistance float64) (*ReverseResult, error)
+	// MaxTemperature finds the highest temperature that fits the available distance
+	MaxTemperature(params TakeoffParams, availableDistance float64) (*ReverseResult, error)
+}
+
+// NewTakeoffCalculator creates a PA-28-161 takeoff performance calculator
+func NewTakeoffCalculator() TakeoffCalculator {
+	return performance.NewTakeoffCalculator()
+}
+
+// AirspeedCalibration is a POH airspeed calibration table (IAS to CAS) for one flap setting
+type AirspeedCalibration = performance.AirspeedCalibration
+
+// AirspeedCalibrations are an aircraft's calibration tables, one per flap setting
+type AirspeedCalibrations = performance.AirspeedCalibrations
+
+// TrueAirspeed converts a calibrated airspeed in knots to true airspeed at a
+// pressure altitude in feet and temperature in °C
+func TrueAirspeed(kcas, pressureAltitude, temperature float64) float64 {
+	return performance.TrueAirspeed(kcas, pressureAltitude, temperature)
+}
+
+// ConvertFahrenheitToCelsius converts temperature from °F to °C
+func ConvertFahrenheitToCelsius(fahrenheit float64) float64 {
+	return performance.ConvertFahrenheitToCelsius(fahrenheit)
+}
+
+// ConvertCelsiusToFahrenheit converts temperature from °C to °F
+func ConvertCelsiusToFahrenheit(celsius float64) float64 {
+	return performance.ConvertCelsiusToFahrenheit(celsius)
+}
+
+// Schema returns the published JSON Schema with the given name, e.g. "takeoff_params"
+func Schema(name string) ([]byte, error) {
+	return performance.Schema(name)
+}
+
+// SchemaNames lists the names of the published JSON Schemas
+func SchemaNames() []string {
+	return performance.SchemaNames()
+}
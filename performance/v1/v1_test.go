@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestTakeoffCalculator exercises the stable surface the way an integrator would
+func TestTakeoffCalculator(t *testing.T) {
+	calc := NewTakeoffCalculator()
+
+	params := TakeoffParams{
+		PressureAltitude: 1500,
+		Temperature:      ConvertFahrenheitToCelsius(80),
+		Weight:           2325,
+		WindComponent:    15,
+	}
+	result, err := calc.CalculateTakeoff(params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TakeoffDistance < 2050 || result.TakeoffDistance > 2150 {
+		t.Errorf("Takeoff distance: expected ~2100 ft, got %.0f ft", result.TakeoffDistance)
+	}
+
+	params.Weight = 0
+	errs := calc.Validate(params)
+	if len(errs) != 1 || errs[0].Field != FieldWeight || errs[0].Code != CodeBelowMinimum {
+		t.Errorf("Validate: got %v", errs)
+	}
+
+	reverse, err := calc.MaxWeight(params, 5000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reverse.Limit != LimitChart {
+		t.Errorf("MaxWeight limit: expected %v, got %v", LimitChart, reverse.Limit)
+	}
+}
+
+// TestValidationErrorJSON pins the JSON field names of the stable surface
+func TestValidationErrorJSON(t *testing.T) {
+	data, err := json.Marshal(&ValidationError{Field: FieldWeight, Code: CodeAboveMaximum, Value: 2400, Min: 1600, Max: 2325, Message: "m"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := `{"field":"weight","code":"above_maximum","value":2400,"min":1600,"max":2325,"message":"m"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+// TestTakeoffJSON pins the JSON field names of the params and result types
+func TestTakeoffJSON(t *testing.T) {
+	data, err := json.Marshal(TakeoffParams{PressureAltitude: 1500, Temperature: 20, Weight: 2200, WindComponent: -5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `{"pressure_altitude":1500,"temperature_c":20,"weight":2200,"wind_component":-5}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	data, err = json.Marshal(TakeoffResult{TakeoffDistance: 2100, LiftoffSpeed: 50, BarrierSpeed: 55})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = `{"takeoff_distance":2100,"liftoff_speed":50,"barrier_speed":55}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
@@ -0,0 +1,55 @@
+package performance
+
+import "strings"
+
+// Field names used in validation errors, matching the scenario file keys
+const (
+	FieldPressureAltitude = "pressure_altitude"
+	FieldTemperature      = "temperature"
+	FieldWeight           = "weight"
+	FieldWindComponent    = "wind_component"
+)
+
+// Validation error codes
+const (
+	CodeMissing      = "missing"       // A required input was not supplied
+	CodeBelowMinimum = "below_minimum" // The input is below the chart minimum
+	CodeAboveMaximum = "above_maximum" // The input is above the chart maximum
+	CodeUnsupported  = "unsupported"   // The chart has no correction for the input
+)
+
+// ValidationError describes a single input that is missing or outside the chart envelope.
+// Value, Min and Max are only meaningful for range violations.
+type ValidationError struct {
+	Field   string  `json:"field"`
+	Code    string  `json:"code"`
+	Value   float64 `json:"value"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Message string  `json:"message"`
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors collects every validation failure for a set of inputs
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface, joining the individual messages
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// rangeCode returns the error code for a value found outside [min, max]
+func rangeCode(value, min float64) string {
+	if value < min {
+		return CodeBelowMinimum
+	}
+	return CodeAboveMaximum
+}
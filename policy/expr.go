@@ -0,0 +1,349 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// kind is the type of an expression: a number or a truth value
+type kind int
+
+const (
+	number kind = iota
+	boolean
+)
+
+func (k kind) String() string {
+	if k == boolean {
+		return "true/false"
+	}
+	return "a number"
+}
+
+// node is a compiled expression. Truth values evaluate to 1 or 0.
+type node interface {
+	eval(vars map[string]float64) float64
+	kind() kind
+}
+
+type constant float64
+
+func (c constant) eval(map[string]float64) float64 { return float64(c) }
+func (c constant) kind() kind                      { return number }
+
+type variable string
+
+func (v variable) eval(vars map[string]float64) float64 { return vars[string(v)] }
+func (v variable) kind() kind                           { return number }
+
+type unary struct {
+	op      string
+	operand node
+}
+
+func (u *unary) eval(vars map[string]float64) float64 {
+	x := u.operand.eval(vars)
+	if u.op == "-" {
+		return -x
+	}
+	return truth(x == 0)
+}
+
+func (u *unary) kind() kind { return u.operand.kind() }
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b *binary) eval(vars map[string]float64) float64 {
+	x := b.left.eval(vars)
+	// Short-circuit the logical operators
+	switch b.op {
+	case "&&":
+		return truth(x != 0 && b.right.eval(vars) != 0)
+	case "||":
+		return truth(x != 0 || b.right.eval(vars) != 0)
+	}
+
+	y := b.right.eval(vars)
+	switch b.op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	case "/":
+		return x / y
+	case "<":
+		return truth(x < y)
+	case "<=":
+		return truth(x <= y)
+	case ">":
+		return truth(x > y)
+	case ">=":
+		return truth(x >= y)
+	case "==":
+		return truth(x == y)
+	default: // "!="
+		return truth(x != y)
+	}
+}
+
+func (b *binary) kind() kind {
+	switch b.op {
+	case "+", "-", "*", "/":
+		return number
+	}
+	return boolean
+}
+
+// truth converts a truth value to 1 or 0
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// token is a lexical element of an expression
+type token struct {
+	text string
+	pos  int // Byte offset in the expression, for error messages
+}
+
+// tokenize splits an expression into numbers, names and operators. The
+// words and, or and not are read as &&, || and !.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(expr) && (unicode.IsDigit(rune(expr[i])) || expr[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{expr[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(expr) && (unicode.IsLetter(rune(expr[i])) || unicode.IsDigit(rune(expr[i])) || expr[i] == '_') {
+				i++
+			}
+			word := strings.ToLower(expr[start:i])
+			switch word {
+			case "and":
+				word = "&&"
+			case "or":
+				word = "||"
+			case "not":
+				word = "!"
+			}
+			tokens = append(tokens, token{word, start})
+		default:
+			op := ""
+			for _, candidate := range []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "+", "-", "*", "/", "(", ")", "!"} {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at column %d", c, i+1)
+			}
+			tokens = append(tokens, token{op, i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// parser compiles an expression by recursive descent, lowest precedence
+// first: ||, &&, !, comparisons, + and -, * and /, unary minus
+type parser struct {
+	tokens []token
+	pos    int
+	known  map[string]bool
+	used   map[string]bool
+}
+
+// compile parses an expression over the known variable names
+func compile(expr string, known map[string]bool) (node, []string, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &parser{tokens: tokens, known: known, used: make(map[string]bool)}
+	n, err := p.or()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, nil, p.errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	var used []string
+	for name := range p.used {
+		used = append(used, name)
+	}
+	return n, used, nil
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	column := 0
+	if p.pos < len(p.tokens) {
+		column = p.tokens[p.pos].pos + 1
+	}
+	msg := fmt.Sprintf(format, args...)
+	if column == 0 {
+		return fmt.Errorf("%s at the end of the expression", msg)
+	}
+	return fmt.Errorf("%s at column %d", msg, column)
+}
+
+// operands checks the kinds on both sides of an operator
+func (p *parser) operands(op string, want kind, left, right node) error {
+	if left.kind() != want || right.kind() != want {
+		return fmt.Errorf("%s needs %s on both sides", op, want)
+	}
+	return nil
+}
+
+func (p *parser) or() (node, error) {
+	return p.binaryLevel([]string{"||"}, boolean, p.and)
+}
+
+func (p *parser) and() (node, error) {
+	return p.binaryLevel([]string{"&&"}, boolean, p.not)
+}
+
+func (p *parser) not() (node, error) {
+	if p.peek() != "!" {
+		return p.comparison()
+	}
+	p.pos++
+	operand, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind() != boolean {
+		return nil, fmt.Errorf("! needs %s", boolean)
+	}
+	return &unary{op: "!", operand: operand}, nil
+}
+
+func (p *parser) comparison() (node, error) {
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	switch op := p.peek(); op {
+	case "<", "<=", ">", ">=", "==", "!=":
+		p.pos++
+		right, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.operands(op, number, left, right); err != nil {
+			return nil, err
+		}
+		return &binary{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) sum() (node, error) {
+	return p.binaryLevel([]string{"+", "-"}, number, p.product)
+}
+
+func (p *parser) product() (node, error) {
+	return p.binaryLevel([]string{"*", "/"}, number, p.negation)
+}
+
+// binaryLevel parses a left-associative chain of operators of one precedence
+func (p *parser) binaryLevel(ops []string, want kind, next func() (node, error)) (node, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		found := false
+		for _, o := range ops {
+			found = found || o == op
+		}
+		if !found {
+			return left, nil
+		}
+		p.pos++
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.operands(op, want, left, right); err != nil {
+			return nil, err
+		}
+		left = &binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) negation() (node, error) {
+	if p.peek() != "-" {
+		return p.primary()
+	}
+	p.pos++
+	operand, err := p.negation()
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind() != number {
+		return nil, fmt.Errorf("- needs %s", number)
+	}
+	return &unary{op: "-", operand: operand}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	text := p.peek()
+	switch {
+	case text == "":
+		return nil, p.errorf("missing value")
+	case text == "(":
+		p.pos++
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case unicode.IsDigit(rune(text[0])) || text[0] == '.':
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", text)
+		}
+		p.pos++
+		return constant(v), nil
+	case unicode.IsLetter(rune(text[0])) || text[0] == '_':
+		if !p.known[text] {
+			return nil, p.errorf("unknown variable %q", text)
+		}
+		p.pos++
+		p.used[text] = true
+		return variable(text), nil
+	}
+	return nil, p.errorf("unexpected %q", text)
+}
@@ -0,0 +1,187 @@
+// Package policy evaluates operator go/no-go rules written as small
+// expressions over the results of a calculation, such as
+//
+//	distance50 * 1.3 > tora -> NOGO "insufficient margin"
+//
+// so club and school rules can change without code changes. A rules file
+// has one rule a line; blank lines and lines starting with # are ignored.
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Verdict is what a rule says when its condition holds
+type Verdict int
+
+const (
+	// Info is a reminder
+	Info Verdict = iota
+	// Caution calls for extra care or a second opinion
+	Caution
+	// NoGo means the flight must not go as planned
+	NoGo
+)
+
+// verdicts are the verdict names accepted in rules
+var verdicts = map[string]Verdict{"INFO": Info, "CAUTION": Caution, "NOGO": NoGo, "NO-GO": NoGo}
+
+// String returns the name of the verdict
+func (v Verdict) String() string {
+	switch v {
+	case Info:
+		return "INFO"
+	case Caution:
+		return "CAUTION"
+	case NoGo:
+		return "NO-GO"
+	default:
+		return fmt.Sprintf("Verdict(%d)", int(v))
+	}
+}
+
+// Rule is one compiled policy rule
+type Rule struct {
+	Line      int    // Line of the rules file, from 1
+	Condition string // The expression as written
+	Verdict   Verdict
+	Message   string
+
+	expr node
+	vars []string // Variables the condition uses, sorted
+}
+
+// Policy is a list of rules
+type Policy struct {
+	Rules []*Rule
+}
+
+// Finding is a rule whose condition held, or that could not be evaluated
+// because a value it needs is unknown
+type Finding struct {
+	Rule    *Rule
+	Missing []string // Unknown variables; the condition was not evaluated
+}
+
+// String describes the finding, e.g. "NO-GO: insufficient margin"
+func (f Finding) String() string {
+	if len(f.Missing) > 0 {
+		return fmt.Sprintf("%s rule on line %d not checked, %s unknown: %s",
+			f.Rule.Verdict, f.Rule.Line, strings.Join(f.Missing, ", "), f.Rule.Message)
+	}
+	return f.Rule.Verdict.String() + ": " + f.Rule.Message
+}
+
+// Load reads a rules file; see Parse
+func Load(path string, variables []string) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p, err := Parse(f, variables)
+	if err != nil {
+		lines := strings.Split(err.Error(), "\n")
+		for i := range lines {
+			lines[i] = path + ":" + lines[i]
+		}
+		return nil, fmt.Errorf("%s", strings.Join(lines, "\n"))
+	}
+	return p, nil
+}
+
+// Parse reads rules of the form CONDITION -> VERDICT "MESSAGE", where the
+// verdict is NOGO, CAUTION or INFO. Conditions may use the given variable
+// names, numbers, + - * /, comparisons, and, or, not and parentheses. All
+// the problems found are reported together, one a line.
+func Parse(r io.Reader, variables []string) (*Policy, error) {
+	known := make(map[string]bool, len(variables))
+	for _, v := range variables {
+		known[v] = true
+	}
+
+	p := &Policy{}
+	var problems []string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		rule, err := parseRule(text, known)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%d: %v", line, err))
+			continue
+		}
+		rule.Line = line
+		p.Rules = append(p.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+	return p, nil
+}
+
+// parseRule compiles one rule
+func parseRule(text string, known map[string]bool) (*Rule, error) {
+	condition, outcome, ok := strings.Cut(text, "->")
+	if !ok {
+		return nil, fmt.Errorf(`expected CONDITION -> VERDICT "MESSAGE"`)
+	}
+	outcome = strings.TrimSpace(outcome)
+	name, message, _ := strings.Cut(outcome, " ")
+	verdict, ok := verdicts[strings.ToUpper(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown verdict %q, expected NOGO, CAUTION or INFO", name)
+	}
+	message = strings.TrimSpace(message)
+	if len(message) < 2 || message[0] != '"' || message[len(message)-1] != '"' {
+		return nil, fmt.Errorf("the message after %s must be in double quotes", name)
+	}
+
+	expr, vars, err := compile(condition, known)
+	if err != nil {
+		return nil, err
+	}
+	if expr.kind() != boolean {
+		return nil, fmt.Errorf("the condition must be a comparison, not just a number")
+	}
+	sort.Strings(vars)
+	return &Rule{
+		Condition: strings.TrimSpace(condition),
+		Verdict:   verdict,
+		Message:   message[1 : len(message)-1],
+		expr:      expr,
+		vars:      vars,
+	}, nil
+}
+
+// Evaluate checks every rule against the values of the variables, and
+// returns the rules that hold and those that need a value not given
+func (p *Policy) Evaluate(values map[string]float64) []Finding {
+	var findings []Finding
+	for _, rule := range p.Rules {
+		var missing []string
+		for _, v := range rule.vars {
+			if _, ok := values[v]; !ok {
+				missing = append(missing, v)
+			}
+		}
+		switch {
+		case len(missing) > 0:
+			findings = append(findings, Finding{Rule: rule, Missing: missing})
+		case rule.expr.eval(values) != 0:
+			findings = append(findings, Finding{Rule: rule})
+		}
+	}
+	return findings
+}
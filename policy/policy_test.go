@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+var testVariables = []string{"distance50", "tora", "crosswind", "gust", "density_altitude"}
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse(strings.NewReader(`
+# Club rules
+distance50 * 1.3 > tora -> NOGO "insufficient margin"
+crosswind > 12 and not (gust < crosswind + 5) -> CAUTION "gusty crosswind, brief the go-around"
+density_altitude >= 5000 -> info "lean for takeoff"
+-distance50 < -2000 || tora / 2 < 1000 -> CAUTION "long roll or short runway"
+`), testVariables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules) != 4 || p.Rules[0].Line != 3 || p.Rules[2].Verdict != Info {
+		t.Fatalf("Unexpected rules %+v", p.Rules)
+	}
+
+	testCases := []struct {
+		name   string
+		values map[string]float64
+		want   []string
+	}{
+		{"all clear", map[string]float64{"distance50": 1500, "tora": 5500, "crosswind": 5, "gust": 0, "density_altitude": 1200}, nil},
+		{"tight runway", map[string]float64{"distance50": 2100, "tora": 2600, "crosswind": 13, "gust": 20, "density_altitude": 5000}, []string{
+			"NO-GO: insufficient margin",
+			"CAUTION: gusty crosswind, brief the go-around",
+			"INFO: lean for takeoff",
+			"CAUTION: long roll or short runway",
+		}},
+		{"no runway", map[string]float64{"distance50": 1500, "crosswind": 5, "gust": 0, "density_altitude": 1200}, []string{
+			"NO-GO rule on line 3 not checked, tora unknown: insufficient margin",
+			"CAUTION rule on line 6 not checked, tora unknown: long roll or short runway",
+		}},
+	}
+	for _, tc := range testCases {
+		var got []string
+		for _, f := range p.Evaluate(tc.values) {
+			got = append(got, f.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Errorf("%s: got\n%s\nexpected\n%s", tc.name, strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+		}
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	vars := map[string]float64{"tora": 10}
+	for expr, want := range map[string]bool{
+		"2 + 3 * 4 == 14":             true,
+		"(2 + 3) * 4 == 20":           true,
+		"10 - 4 - 3 == 3":             true,
+		"tora / 5 / 2 == 1":           true,
+		"--tora == tora":              true,
+		"1 < 2 || 1 > 2 && 1 > 2":     true,
+		"not 1 < 2 or 3 > 4":          false,
+		"!(tora != 10) && tora >= 10": true,
+	} {
+		n, _, err := compile(expr, map[string]bool{"tora": true})
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+			continue
+		}
+		if got := n.eval(vars) != 0; got != want {
+			t.Errorf("%s: got %v, expected %v", expr, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse(strings.NewReader(strings.Join([]string{
+		`distance50 > tora`,
+		`distance50 > tora -> STOP "x"`,
+		`distance50 > tora -> NOGO no quotes`,
+		`distance50 > runway -> NOGO "x"`,
+		`distance50 * 1.3 -> NOGO "x"`,
+		`(distance50 > tora -> NOGO "x"`,
+		`distance50 > tora + (1 < 2) -> NOGO "x"`,
+		`distance50 $ 3 -> NOGO "x"`,
+	}, "\n")), testVariables)
+	if err == nil {
+		t.Fatal("Expected errors")
+	}
+
+	want := []string{
+		"1: expected CONDITION",
+		`2: unknown verdict "STOP"`,
+		"3: the message after NOGO must be in double quotes",
+		`4: unknown variable "runway" at column 14`,
+		"5: the condition must be a comparison",
+		"6: missing ) at the end",
+		"7: + needs a number on both sides",
+		`8: unexpected '$' at column 12`,
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d problems, got:\n%v", len(want), err)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(lines[i], w) {
+			t.Errorf("Expected %q, got %q", w, lines[i])
+		}
+	}
+}
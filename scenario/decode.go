@@ -0,0 +1,337 @@
+package scenario
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// schema is the published JSON Schema of scenario files
+//
+//go:embed schema/scenario.schema.json
+var schema []byte
+
+// Schema returns the JSON Schema that scenario files are checked against
+func Schema() []byte {
+	return schema
+}
+
+// FieldError is a problem with one field of a scenario file
+type FieldError struct {
+	Line    int    // Line in the file, 0 if not known
+	Field   string // Key of the field, empty for problems with the whole file
+	Message string
+}
+
+// Error returns e.g. "line 3: weight: expected number with unit suffix (lb, kg), got 'full'"
+func (e *FieldError) Error() string {
+	var b strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+	if e.Field != "" {
+		b.WriteString(e.Field + ": ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// LoadError lists every problem found in a scenario file
+type LoadError struct {
+	Path   string
+	Errors []*FieldError
+}
+
+// Error returns one "path:line: field: message" line per problem
+func (e *LoadError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		prefix := e.Path
+		if fe.Line > 0 {
+			prefix += ":" + strconv.Itoa(fe.Line)
+		}
+		msg := fe.Message
+		if fe.Field != "" {
+			msg = fe.Field + ": " + msg
+		}
+		lines[i] = prefix + ": " + msg
+	}
+	return strings.Join(lines, "\n")
+}
+
+// entry is one key of a scenario document with its decoded value: a
+// float64, string, bool, []interface{}, map[string]interface{} or nil
+type entry struct {
+	key   string
+	value interface{}
+	line  int
+}
+
+// unit is a suffix accepted on a quantity and its conversion to the field's unit
+type unit struct {
+	suffix  string
+	convert func(float64) float64 // nil for the field's own unit
+}
+
+// Units accepted on quantities, the field's own unit first
+var (
+	altitudeUnits   = []unit{{"ft", nil}, {"m", units.MetersToFeet}}
+	celsiusUnits    = []unit{{"C", nil}, {"°C", nil}}
+	fahrenheitUnits = []unit{{"F", nil}, {"°F", nil}}
+	weightUnits     = []unit{{"lb", nil}, {"lbs", nil}, {"kg", units.KilogramsToPounds}}
+	windUnits       = []unit{{"kt", nil}, {"kts", nil}}
+)
+
+// fields lists the keys of a scenario document and how each is stored;
+// it mirrors the properties of the published schema
+var fields = map[string]func(s *Scenario, value interface{}) string{
+	"pressure_altitude": quantityField(altitudeUnits, func(s *Scenario, v float64) { s.PressureAltitude = &v }),
+	"temperature_c":     quantityField(celsiusUnits, func(s *Scenario, v float64) { s.TemperatureC = &v }),
+	"temperature_f":     quantityField(fahrenheitUnits, func(s *Scenario, v float64) { s.TemperatureF = &v }),
+	"weight":            quantityField(weightUnits, func(s *Scenario, v float64) { s.Weight = &v }),
+	"wind_component":    quantityField(windUnits, func(s *Scenario, v float64) { s.WindComponent = &v }),
+	"airport": func(s *Scenario, value interface{}) string {
+		text, ok := value.(string)
+		if !ok {
+			return "expected an airport identifier, got " + describe(value)
+		}
+		s.Airport = text
+		return ""
+	},
+	"departure": func(s *Scenario, value interface{}) string {
+		text, ok := value.(string)
+		if ok {
+			_, err := localtime.Parse(text, time.UTC)
+			ok = err == nil
+		}
+		if !ok {
+			return "expected a time such as '2026-10-15 09:00' or '2026-10-15T13:00Z', got " + describe(value)
+		}
+		s.Departure = text
+		return ""
+	},
+	"variant": func(s *Scenario, value interface{}) string {
+		text, ok := value.(string)
+		if !ok {
+			return "expected a variant ID, got " + describe(value)
+		}
+		s.Variant = text
+		return ""
+	},
+	"equipment": func(s *Scenario, value interface{}) string {
+		switch v := value.(type) {
+		case string:
+			s.Equipment = []string{v}
+			return ""
+		case []interface{}:
+			ids := make([]string, len(v))
+			for i, item := range v {
+				id, ok := item.(string)
+				if !ok {
+					return fmt.Sprintf("item %d: expected an equipment ID, got %s", i+1, describe(item))
+				}
+				ids[i] = id
+			}
+			s.Equipment = ids
+			return ""
+		}
+		return "expected a list of equipment IDs, got " + describe(value)
+	},
+}
+
+// quantityField stores a number, or a string with one of the unit suffixes
+// converted to the field's unit
+func quantityField(accepted []unit, set func(*Scenario, float64)) func(*Scenario, interface{}) string {
+	return func(s *Scenario, value interface{}) string {
+		suffixes := make([]string, len(accepted))
+		for i, u := range accepted {
+			suffixes[i] = u.suffix
+		}
+		invalid := fmt.Sprintf("expected number with unit suffix (%s), got %s", strings.Join(suffixes, ", "), describe(value))
+
+		switch v := value.(type) {
+		case float64:
+			set(s, v)
+			return ""
+		case string:
+			text := strings.TrimSpace(v)
+			for _, u := range accepted {
+				if !strings.HasSuffix(text, u.suffix) {
+					continue
+				}
+				x, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(text, u.suffix)), 64)
+				if err != nil {
+					continue
+				}
+				if u.convert != nil {
+					x = u.convert(x)
+				}
+				set(s, x)
+				return ""
+			}
+			if x, err := strconv.ParseFloat(text, 64); err == nil {
+				set(s, x)
+				return ""
+			}
+		}
+		return invalid
+	}
+}
+
+// describe quotes a decoded value for an error message
+func describe(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "'" + v + "'"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return "null"
+}
+
+// decode checks the entries of a document against the schema and fills in
+// a scenario, collecting every problem rather than stopping at the first
+func decode(entries []entry) (*Scenario, []*FieldError) {
+	s := &Scenario{}
+	var errs []*FieldError
+	seen := make(map[string]int)
+	for _, e := range entries {
+		if first, ok := seen[e.key]; ok {
+			errs = append(errs, &FieldError{e.line, e.key, fmt.Sprintf("given twice, first on line %d", first)})
+			continue
+		}
+		seen[e.key] = e.line
+
+		set, ok := fields[e.key]
+		if !ok {
+			msg := "unknown field"
+			if near := suggest(e.key); near != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", near)
+			}
+			errs = append(errs, &FieldError{e.line, e.key, msg})
+			continue
+		}
+		if e.value == nil {
+			continue
+		}
+		if msg := set(s, e.value); msg != "" {
+			errs = append(errs, &FieldError{e.line, e.key, msg})
+		}
+	}
+	return s, errs
+}
+
+// suggest finds the known field closest to a misspelled key, if any is
+// within two edits
+func suggest(key string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if d := editDistance(key, name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// minInt returns the smallest of its arguments
+func minInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
+
+// parseJSON reads the top-level keys of a JSON document with the line
+// each is on
+func parseJSON(data []byte) ([]entry, *FieldError) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	syntax := func(err error) *FieldError {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			return &FieldError{Line: lineAt(data, se.Offset), Message: se.Error()}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return &FieldError{Line: lineAt(data, int64(len(data))), Message: "unexpected end of file"}
+		}
+		return &FieldError{Line: lineAt(data, dec.InputOffset()), Message: err.Error()}
+	}
+
+	token, err := dec.Token()
+	if err != nil {
+		return nil, syntax(err)
+	}
+	if token != json.Delim('{') {
+		return nil, &FieldError{Line: lineAt(data, dec.InputOffset()), Message: "expected an object of scenario fields"}
+	}
+
+	var entries []entry
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, syntax(err)
+		}
+		key, _ := token.(string)
+		e := entry{key: key, line: lineAt(data, dec.InputOffset())}
+		if err := dec.Decode(&e.value); err != nil {
+			return nil, syntax(err)
+		}
+		entries = append(entries, e)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, syntax(err)
+	}
+	return entries, nil
+}
+
+// lineAt returns the line of a byte offset
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,86 @@
+package scenario
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Preset is a built-in training scenario, run by name for ground school
+// demonstrations
+type Preset struct {
+	Name      string
+	Title     string
+	Lesson    string // What the scenario demonstrates
+	Scenario  Scenario
+	Available float64 // Runway length in feet, for the margin
+	Surface   string  // Runway surface for operator corrections, e.g. TURF; empty for paved
+}
+
+// presets are the built-in training scenarios by name
+var presets = map[string]*Preset{
+	"max-gross-hot": {
+		Name:  "max-gross-hot",
+		Title: "Maximum gross weight on a hot day",
+		Lesson: "Full seats and fuel near sea level at 38°C. Heat alone raises the density altitude " +
+			"by thousands of feet, and at maximum gross weight the takeoff distance grows with it; " +
+			"compare the distance with the same loading at 15°C.",
+		Scenario:  Scenario{PressureAltitude: float(500), TemperatureC: float(38), Weight: float(2325), WindComponent: float(0)},
+		Available: 2500,
+	},
+	"hot-high": {
+		Name:  "hot-high",
+		Title: "High density altitude airport",
+		Lesson: "A 5000 ft field on a 30°C afternoon puts the density altitude near 8000 ft. " +
+			"The engine makes less power and the wing needs a higher true airspeed, so the distance " +
+			"roughly doubles from sea level; lean for best power before the takeoff roll.",
+		Scenario:  Scenario{PressureAltitude: float(5000), TemperatureC: float(30), Weight: float(2325), WindComponent: float(0)},
+		Available: 5000,
+	},
+	"short-grass": {
+		Name:  "short-grass",
+		Title: "Short grass strip",
+		Lesson: "A 2000 ft turf strip. The POH chart is for a paved, level, dry runway; the UK CAA " +
+			"Safety Sense Leaflet 7 adds 20% for dry grass and 30% for wet grass, on top of its 1.33 " +
+			"safety factor. Operator corrections for TURF apply to this preset (see -corrections).",
+		Scenario:  Scenario{PressureAltitude: float(800), TemperatureC: float(25), Weight: float(2325), WindComponent: float(0)},
+		Available: 2000,
+		Surface:   "TURF",
+	},
+	"night-tailwind": {
+		Name:  "night-tailwind",
+		Title: "Night departure with the maximum charted tailwind",
+		Lesson: "A 5 kt tailwind, the most the chart covers, on a night departure where an unlit " +
+			"windsock hides it. Each knot of tailwind costs about twice the distance a knot of " +
+			"headwind saves; compare with -wind 5.",
+		Scenario:  Scenario{PressureAltitude: float(1000), TemperatureC: float(15), Weight: float(2325), WindComponent: float(-5)},
+		Available: 2800,
+	},
+}
+
+// Presets returns the built-in training scenarios sorted by name
+func Presets() []*Preset {
+	list := make([]*Preset, 0, len(presets))
+	for _, p := range presets {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// LookupPreset finds a built-in training scenario by name
+func LookupPreset(name string) (*Preset, error) {
+	if p, ok := presets[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return p, nil
+	}
+	names := make([]string, 0, len(presets))
+	for _, p := range Presets() {
+		names = append(names, p.Name)
+	}
+	return nil, fmt.Errorf("unknown preset %q (expected one of %s)", name, strings.Join(names, ", "))
+}
+
+// float returns a pointer to v, for the optional fields of a preset
+func float(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,122 @@
+package scenario
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Review statuses a CFI or dispatcher can give a scenario
+const (
+	Reviewed = "reviewed" // Looked at, no decision
+	Approved = "approved" // Cleared for dispatch
+	Rejected = "rejected" // Not to be flown as planned
+)
+
+// Review is a sign-off of a saved scenario
+type Review struct {
+	Status   string    `json:"status"`
+	Reviewer string    `json:"reviewer"`          // Name and role, e.g. "J. Smith, CFI"
+	Comment  string    `json:"comment,omitempty"` // e.g. "Approved for solo with 1.5 factor"
+	Time     time.Time `json:"time"`
+	Digest   string    `json:"digest"` // Digest of the scenario inputs that were reviewed
+}
+
+// Validate checks that a review has a known status and a reviewer
+func (r *Review) Validate() error {
+	switch r.Status {
+	case Reviewed, Approved, Rejected:
+	default:
+		return fmt.Errorf("status must be %s, %s or %s, not %q", Reviewed, Approved, Rejected, r.Status)
+	}
+	if strings.TrimSpace(r.Reviewer) == "" {
+		return errors.New("reviewer is required")
+	}
+	return nil
+}
+
+// Digest identifies a scenario's inputs, so a review can be told to be of
+// the scenario as it is now or of an earlier version
+func Digest(s *Scenario) string {
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ReviewLog keeps the reviews of each scenario, oldest first, in a JSON
+// file per scenario in a directory. Reviews are only ever added, so the
+// file is the audit trail of who signed off what.
+type ReviewLog struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewReviewLog creates a review log in a directory, created on the first
+// review
+func NewReviewLog(dir string) *ReviewLog {
+	return &ReviewLog{dir: dir}
+}
+
+// Add records a review of a scenario
+func (l *ReviewLog) Add(name string, r Review) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	history, err := l.read(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(append(history, r), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+
+	// Replace the file in one step so a crash cannot truncate the history
+	path := l.path(name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// History returns the reviews of a scenario, oldest first
+func (l *ReviewLog) History(name string) ([]Review, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.read(name)
+}
+
+// read loads the reviews of a scenario; none is not an error
+func (l *ReviewLog) read(name string) ([]Review, error) {
+	data, err := os.ReadFile(l.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var history []Review
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("reading reviews of %s: %w", name, err)
+	}
+	return history, nil
+}
+
+// path returns the file of a scenario's reviews
+func (l *ReviewLog) path(name string) string {
+	return filepath.Join(l.dir, filepath.Base(name)+".json")
+}
@@ -0,0 +1,164 @@
+// Package scenario loads and checks saved sets of performance inputs
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// Scenario holds the takeoff inputs for a planned departure. Fields are
+// pointers so that inputs left out of a scenario file can be told apart
+// from inputs explicitly set to zero.
+type Scenario struct {
+	PressureAltitude *float64 `json:"pressure_altitude,omitempty"` // in feet
+	TemperatureC     *float64 `json:"temperature_c,omitempty"`     // in °C
+	TemperatureF     *float64 `json:"temperature_f,omitempty"`     // in °F (overrides temperature_c)
+	Weight           *float64 `json:"weight,omitempty"`            // in pounds
+	WindComponent    *float64 `json:"wind_component,omitempty"`    // in knots (positive for headwind)
+
+	Airport   string `json:"airport,omitempty"`   // Departure airport identifier
+	Departure string `json:"departure,omitempty"` // "YYYY-MM-DD HH:MM" local to the airport, or with Z for Zulu
+
+	Variant   string   `json:"variant,omitempty"`   // ID of the profile's engine or STC variant
+	Equipment []string `json:"equipment,omitempty"` // IDs of the profile's optional equipment installed
+}
+
+// Load reads a scenario from a JSON file, or a YAML file if the name ends
+// in .yaml or .yml, and checks it against the published schema. Problems
+// are reported together as a *LoadError with the line of each field.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	var syntaxErr *FieldError
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		entries, syntaxErr = parseYAML(data)
+	} else {
+		entries, syntaxErr = parseJSON(data)
+	}
+	if syntaxErr != nil {
+		return nil, &LoadError{Path: path, Errors: []*FieldError{syntaxErr}}
+	}
+
+	s, errs := decode(entries)
+	if len(errs) > 0 {
+		return nil, &LoadError{Path: path, Errors: errs}
+	}
+	return s, nil
+}
+
+// Decode builds a scenario from field values already decoded from JSON,
+// such as values picked out of another system's document, and checks them
+// as Load does. Problems are reported together as a *LoadError with source
+// in place of the file name.
+func Decode(source string, values map[string]interface{}) (*Scenario, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]entry, len(keys))
+	for i, key := range keys {
+		entries[i] = entry{key: key, value: values[key]}
+	}
+
+	s, errs := decode(entries)
+	if len(errs) > 0 {
+		return nil, &LoadError{Path: source, Errors: errs}
+	}
+	return s, nil
+}
+
+// Temperature returns the scenario temperature in °C, preferring the
+// Fahrenheit value when both are present
+func (s *Scenario) Temperature() (float64, bool) {
+	if s.TemperatureF != nil {
+		return performance.ConvertFahrenheitToCelsius(*s.TemperatureF), true
+	}
+	if s.TemperatureC != nil {
+		return *s.TemperatureC, true
+	}
+	return 0, false
+}
+
+// DepartureTime returns the planned departure time, reading times without
+// a zone as local time at loc (normally the departure airport's zone).
+// It reports false if the scenario has no departure time.
+func (s *Scenario) DepartureTime(loc *time.Location) (time.Time, bool, error) {
+	if s.Departure == "" {
+		return time.Time{}, false, nil
+	}
+	t, err := localtime.Parse(s.Departure, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// Missing reports an error for each required input the scenario does not supply.
+// The wind component is optional and defaults to calm.
+func (s *Scenario) Missing() performance.ValidationErrors {
+	var errs performance.ValidationErrors
+
+	if s.PressureAltitude == nil {
+		errs = append(errs, missing(performance.FieldPressureAltitude, "pressure altitude"))
+	}
+	if _, ok := s.Temperature(); !ok {
+		errs = append(errs, missing(performance.FieldTemperature, "temperature"))
+	}
+	if s.Weight == nil {
+		errs = append(errs, missing(performance.FieldWeight, "weight"))
+	}
+
+	return errs
+}
+
+// TakeoffParams converts the scenario into calculator inputs. It fails with
+// the list of missing inputs if the scenario is incomplete.
+func (s *Scenario) TakeoffParams() (performance.TakeoffParams, error) {
+	if errs := s.Missing(); len(errs) > 0 {
+		return performance.TakeoffParams{}, errs
+	}
+
+	temperature, _ := s.Temperature()
+	params := performance.TakeoffParams{
+		PressureAltitude: *s.PressureAltitude,
+		Temperature:      temperature,
+		Weight:           *s.Weight,
+	}
+	if s.WindComponent != nil {
+		params.WindComponent = *s.WindComponent
+	}
+	return params, nil
+}
+
+// Validate checks the scenario for completeness and, once complete, for
+// compliance with the calculator's chart envelope. It returns nil if the
+// scenario can be computed.
+func (s *Scenario) Validate(calc *performance.TakeoffCalculator) performance.ValidationErrors {
+	if errs := s.Missing(); len(errs) > 0 {
+		return errs
+	}
+
+	params, _ := s.TakeoffParams()
+	return calc.Validate(params)
+}
+
+// missing builds the validation error for an absent required input
+func missing(field, name string) *performance.ValidationError {
+	return &performance.ValidationError{
+		Field:   field,
+		Code:    performance.CodeMissing,
+		Message: fmt.Sprintf("%s is required", name),
+	}
+}
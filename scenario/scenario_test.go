@@ -0,0 +1,328 @@
+package scenario
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func writeScenario(t *testing.T, contents string) string {
+	t.Helper()
+	return writeScenarioFile(t, "scenario.json", contents)
+}
+
+func writeScenarioFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAndValidate(t *testing.T) {
+	calculator := performance.NewTakeoffCalculator()
+
+	testCases := []struct {
+		name       string
+		contents   string
+		wantFields []string
+		wantCodes  []string
+	}{
+		{
+			name:     "Complete And Valid",
+			contents: `{"pressure_altitude": 1500, "temperature_f": 80, "weight": 2200, "wind_component": 10}`,
+		},
+		{
+			name:     "Wind Optional",
+			contents: `{"pressure_altitude": 0, "temperature_c": 15, "weight": 2000}`,
+		},
+		{
+			name:       "Missing Inputs",
+			contents:   `{"pressure_altitude": 1500}`,
+			wantFields: []string{performance.FieldTemperature, performance.FieldWeight},
+			wantCodes:  []string{performance.CodeMissing, performance.CodeMissing},
+		},
+		{
+			name:       "Outside Envelope",
+			contents:   `{"pressure_altitude": 8000, "temperature_c": 15, "weight": 1500, "wind_component": -10}`,
+			wantFields: []string{performance.FieldPressureAltitude, performance.FieldWeight, performance.FieldWindComponent},
+			wantCodes:  []string{performance.CodeAboveMaximum, performance.CodeBelowMinimum, performance.CodeBelowMinimum},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := Load(writeScenario(t, tc.contents))
+			if err != nil {
+				t.Fatalf("Error loading scenario: %v", err)
+			}
+
+			errs := s.Validate(calculator)
+			if len(errs) != len(tc.wantFields) {
+				t.Fatalf("Got %d errors (%v), expected %d", len(errs), errs, len(tc.wantFields))
+			}
+			for i, err := range errs {
+				if err.Field != tc.wantFields[i] || err.Code != tc.wantCodes[i] {
+					t.Errorf("Error %d: got %s/%s, expected %s/%s",
+						i, err.Field, err.Code, tc.wantFields[i], tc.wantCodes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTakeoffParams(t *testing.T) {
+	s, err := Load(writeScenario(t, `{"pressure_altitude": 1500, "temperature_c": 30, "temperature_f": 68, "weight": 2200}`))
+	if err != nil {
+		t.Fatalf("Error loading scenario: %v", err)
+	}
+
+	params, err := s.TakeoffParams()
+	if err != nil {
+		t.Fatalf("Error converting scenario: %v", err)
+	}
+
+	// Fahrenheit takes precedence over Celsius
+	if params.Temperature != 20 {
+		t.Errorf("Temperature: got %.1f°C, expected 20.0°C", params.Temperature)
+	}
+	if params.WindComponent != 0 {
+		t.Errorf("Wind component: got %.0f, expected calm", params.WindComponent)
+	}
+}
+
+func TestLoadInvalidJSON(t *testing.T) {
+	if _, err := Load(writeScenario(t, `{"weight": "heavy"}`)); err == nil {
+		t.Error("Expected error for malformed scenario, but got none")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		file     string
+		contents string
+		want     []string
+	}{
+		{
+			name:     "Wrong Types",
+			file:     "scenario.json",
+			contents: "{\n  \"pressure_altitude\": 1500,\n  \"weight\": \"full\",\n  \"equipment\": [\"adsb-out\", 3]\n}",
+			want: []string{
+				"line 3: weight: expected number with unit suffix (lb, lbs, kg), got 'full'",
+				"line 4: equipment: item 2: expected an equipment ID, got 3",
+			},
+		},
+		{
+			name:     "Unknown And Duplicate",
+			file:     "scenario.json",
+			contents: "{\"wieght\": 2200,\n\"airport\": \"KJYO\",\n\"airport\": \"KFDK\"}",
+			want: []string{
+				`line 1: wieght: unknown field (did you mean "weight"?)`,
+				"line 3: airport: given twice, first on line 2",
+			},
+		},
+		{
+			name:     "Syntax",
+			file:     "scenario.json",
+			contents: "{\n  \"weight\": 2200,\n  \"airport\" \"KJYO\"\n}",
+			want:     []string{"line 3: invalid character '\"' after object key"},
+		},
+		{
+			name:     "Not An Object",
+			file:     "scenario.json",
+			contents: "[2200]",
+			want:     []string{"line 1: expected an object of scenario fields"},
+		},
+		{
+			name:     "YAML",
+			file:     "scenario.yaml",
+			contents: "# Departure\nweight: 2200 lb\ndeparture: tomorrow\ntemperature_c: 25F\n",
+			want: []string{
+				"line 3: departure: expected a time such as '2026-10-15 09:00' or '2026-10-15T13:00Z', got 'tomorrow'",
+				"line 4: temperature_c: expected number with unit suffix (C, °C), got '25F'",
+			},
+		},
+		{
+			name:     "YAML Nested",
+			file:     "scenario.yml",
+			contents: "weight: 2200\nwind:\n  speed: 10\n",
+			want:     []string{"line 3: nested mappings are not supported"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Load(writeScenarioFile(t, tc.file, tc.contents))
+			loadErr, ok := err.(*LoadError)
+			if !ok {
+				t.Fatalf("Got %v, expected a *LoadError", err)
+			}
+			if len(loadErr.Errors) != len(tc.want) {
+				t.Fatalf("Got %d errors (%v), expected %d", len(loadErr.Errors), err, len(tc.want))
+			}
+			for i, fe := range loadErr.Errors {
+				if fe.Error() != tc.want[i] {
+					t.Errorf("Error %d: got %q, expected %q", i, fe.Error(), tc.want[i])
+				}
+			}
+			if !strings.HasPrefix(err.Error(), loadErr.Path+":") {
+				t.Errorf("Error %q does not start with the file name", err)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	s, err := Decode("booking", map[string]interface{}{"airport": "KJYO", "weight": "1050 kg", "temperature_c": 21.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Airport != "KJYO" || s.Weight == nil || math.Abs(*s.Weight-2314.85) > 0.1 || s.TemperatureC == nil || *s.TemperatureC != 21 {
+		t.Errorf("Unexpected scenario %+v", s)
+	}
+
+	_, err = Decode("booking", map[string]interface{}{"weight": "full", "wieght": 2300.0})
+	if err == nil || err.Error() != "booking: weight: expected number with unit suffix (lb, lbs, kg), got 'full'\n"+
+		"booking: wieght: unknown field (did you mean \"weight\"?)" {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestLoadYAMLWithUnits(t *testing.T) {
+	s, err := Load(writeScenarioFile(t, "trip.yaml", `---
+pressure_altitude: 500 m   # field elevation
+temperature_f: "77 °F"
+weight: 1000 kg
+wind_component: -5 kt
+airport: KJYO
+departure: '2026-10-15 09:00'
+variant: 180hp
+equipment:
+  - adsb-out
+  - no-fairings
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if math.Abs(*s.PressureAltitude-1640.42) > 0.01 || *s.TemperatureF != 77 ||
+		math.Abs(*s.Weight-2204.62) > 0.01 || *s.WindComponent != -5 {
+		t.Errorf("Quantities: got %.2f ft, %.0f°F, %.2f lbs, %.0f kt",
+			*s.PressureAltitude, *s.TemperatureF, *s.Weight, *s.WindComponent)
+	}
+	if s.Airport != "KJYO" || s.Departure != "2026-10-15 09:00" || s.Variant != "180hp" ||
+		!reflect.DeepEqual(s.Equipment, []string{"adsb-out", "no-fairings"}) {
+		t.Errorf("Got %q, %q, %q, %v", s.Airport, s.Departure, s.Variant, s.Equipment)
+	}
+	if s.TemperatureC != nil {
+		t.Errorf("Temperature C: got %v, expected unset", *s.TemperatureC)
+	}
+}
+
+func TestSchemaMatchesFields(t *testing.T) {
+	var doc struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(Schema(), &doc); err != nil {
+		t.Fatalf("Invalid schema: %v", err)
+	}
+	for name := range doc.Properties {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("Schema property %q is not decoded", name)
+		}
+	}
+	for name := range fields {
+		if _, ok := doc.Properties[name]; !ok {
+			t.Errorf("Field %q is missing from the schema", name)
+		}
+	}
+}
+
+func TestDepartureTime(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Fatalf("Error loading time zone: %v", err)
+	}
+
+	s, err := Load(writeScenario(t, `{"airport": "KAPA", "departure": "2026-07-04 07:30"}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// MDT is UTC-6
+	got, ok, err := s.DepartureTime(denver)
+	if err != nil || !ok || !got.Equal(time.Date(2026, 7, 4, 13, 30, 0, 0, time.UTC)) {
+		t.Errorf("Got %v, %v, %v; expected 1330Z", got.UTC(), ok, err)
+	}
+
+	if _, ok, err := (&Scenario{}).DepartureTime(denver); ok || err != nil {
+		t.Errorf("No departure: got %v, %v", ok, err)
+	}
+	if _, _, err := (&Scenario{Departure: "soon"}).DepartureTime(denver); err == nil {
+		t.Error("Expected an error for an invalid departure time")
+	}
+}
+
+func TestReviewLog(t *testing.T) {
+	log := NewReviewLog(filepath.Join(t.TempDir(), "reviews"))
+	weight := 2200.0
+	s := &Scenario{Airport: "KJYO", Weight: &weight}
+
+	if history, err := log.History("lesson"); err != nil || len(history) != 0 {
+		t.Fatalf("Expected no reviews, got %v (%v)", history, err)
+	}
+	if err := log.Add("lesson", Review{Status: "ok", Reviewer: "CFI"}); err == nil {
+		t.Errorf("Expected an unknown status to be rejected")
+	}
+	if err := log.Add("lesson", Review{Status: Approved}); err == nil {
+		t.Errorf("Expected a review without a reviewer to be rejected")
+	}
+
+	at := time.Date(2026, time.October, 15, 14, 0, 0, 0, time.UTC)
+	for _, r := range []Review{
+		{Status: Reviewed, Reviewer: "A. Dispatcher", Time: at, Digest: Digest(s)},
+		{Status: Approved, Reviewer: "J. Smith, CFI", Comment: "Solo OK", Time: at.Add(time.Hour), Digest: Digest(s)},
+	} {
+		if err := log.Add("lesson", r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err := log.History("lesson")
+	if err != nil || len(history) != 2 || history[1].Status != Approved || !history[1].Time.Equal(at.Add(time.Hour)) {
+		t.Fatalf("Unexpected history %+v (%v)", history, err)
+	}
+
+	if Digest(s) != history[1].Digest {
+		t.Errorf("Digest changed for the same scenario")
+	}
+	weight = 2300
+	if Digest(s) == history[1].Digest {
+		t.Errorf("Expected the digest to change with the weight")
+	}
+}
+
+func TestPresets(t *testing.T) {
+	calc := performance.NewTakeoffCalculator()
+	for _, p := range Presets() {
+		if errs := p.Scenario.Validate(calc); len(errs) > 0 {
+			t.Errorf("%s: %v", p.Name, errs)
+		}
+		if p.Title == "" || p.Lesson == "" || p.Available <= 0 {
+			t.Errorf("%s: expected a title, a lesson and a runway, got %+v", p.Name, p)
+		}
+	}
+	if p, err := LookupPreset(" Hot-High "); err != nil || p.Name != "hot-high" {
+		t.Errorf("Got %v, %v", p, err)
+	}
+	if _, err := LookupPreset("cold-low"); err == nil || !strings.Contains(err.Error(), "hot-high") {
+		t.Errorf("Expected an error listing the presets, got %v", err)
+	}
+}
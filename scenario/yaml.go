@@ -0,0 +1,109 @@
+package scenario
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseYAML reads the flat subset of YAML that scenario files use: one
+// "key: value" per line, comments, quoted strings, and lists either as
+// [a, b] or as "- item" lines under a key with no value. Nested mappings,
+// anchors and multi-line strings are not supported.
+func parseYAML(data []byte) ([]entry, *FieldError) {
+	var entries []entry
+	var list *entry // The key whose "- item" lines are being read
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := i + 1
+		text := strings.TrimRight(stripComment(raw), " \t\r")
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		if item := strings.TrimPrefix(trimmed, "-"); item != trimmed && (item == "" || item[0] == ' ') {
+			if list == nil {
+				return nil, &FieldError{Line: line, Message: "list item without a key"}
+			}
+			items, _ := list.value.([]interface{})
+			list.value = append(items, yamlScalar(strings.TrimSpace(item)))
+			continue
+		}
+		if text != trimmed {
+			return nil, &FieldError{Line: line, Message: "nested mappings are not supported"}
+		}
+		list = nil
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, &FieldError{Line: line, Message: "expected 'key: value'"}
+		}
+		e := entry{key: strings.TrimSpace(key), line: line}
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+			// A list may follow; without items the field is left out
+		case strings.HasPrefix(value, "["):
+			if !strings.HasSuffix(value, "]") {
+				return nil, &FieldError{Line: line, Field: e.key, Message: "unterminated list"}
+			}
+			items := []interface{}{}
+			if inner := strings.TrimSpace(value[1 : len(value)-1]); inner != "" {
+				for _, item := range strings.Split(inner, ",") {
+					items = append(items, yamlScalar(strings.TrimSpace(item)))
+				}
+			}
+			e.value = items
+		case strings.HasPrefix(value, "{"):
+			return nil, &FieldError{Line: line, Field: e.key, Message: "nested mappings are not supported"}
+		default:
+			e.value = yamlScalar(value)
+		}
+		entries = append(entries, e)
+		if value == "" {
+			list = &entries[len(entries)-1]
+		}
+	}
+	return entries, nil
+}
+
+// yamlScalar decodes a plain or quoted scalar as a float64, bool, string or nil
+func yamlScalar(s string) interface{} {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		if s[0] == '"' {
+			if unquoted, err := strconv.Unquote(s); err == nil {
+				return unquoted
+			}
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	switch s {
+	case "null", "~", "":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if x, err := strconv.ParseFloat(s, 64); err == nil {
+		return x
+	}
+	return s
+}
+
+// stripComment removes a "#" comment that is not inside quotes
+func stripComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
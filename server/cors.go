@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// corsMaxAge is how long, in seconds, a browser may cache a preflight answer
+const corsMaxAge = "600"
+
+// cors adds the CORS headers for an allowed origin and answers preflight
+// requests, reporting whether the request has been answered. Requests
+// from other origins are served without the headers, so the browser
+// withholds the response.
+func (s *Server) cors(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	w.Header().Add("Vary", "Origin")
+	if !s.allowedOrigin(origin) {
+		return false
+	}
+
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		return false
+	}
+
+	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	headers := r.Header.Get("Access-Control-Request-Headers")
+	if headers == "" {
+		headers = "Accept, Content-Type, traceparent"
+	}
+	h.Set("Access-Control-Allow-Headers", headers)
+	h.Set("Access-Control-Max-Age", corsMaxAge)
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// allowedOrigin reports whether an origin may call the API
+func (s *Server) allowedOrigin(origin string) bool {
+	for _, allowed := range s.cfg.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimRight(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
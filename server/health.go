@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/weather"
+)
+
+// Dataset is data the server answers from, reported by /readyz with its age
+type Dataset struct {
+	Name string
+
+	// Updated returns when the data was published; a zero time means the
+	// data is built in and does not age
+	Updated func() (time.Time, error)
+
+	// MaxAge is the age at which the data is reported stale (0 for never)
+	MaxAge time.Duration
+}
+
+// Check statuses
+const (
+	StatusOK    = "ok"
+	StatusStale = "stale"
+	StatusFail  = "fail"
+)
+
+// Check is the outcome of one readiness check
+type Check struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Detail     string     `json:"detail,omitempty"`
+	Updated    *time.Time `json:"updated,omitempty"`     // When a dataset was published
+	AgeSeconds float64    `json:"age_seconds,omitempty"` // Age of a dataset
+	Checked    time.Time  `json:"checked"`
+}
+
+// Readiness is the body of /readyz
+type Readiness struct {
+	// Status is "ready", "degraded" when data is stale but every check
+	// passed, "not ready" when a check failed, or "draining" when the
+	// server is shutting down
+	Status string  `json:"status"`
+	Checks []Check `json:"checks"`
+}
+
+// handleHealth reports that the process is up
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":         StatusOK,
+		"uptime_seconds": int(s.cfg.Now().Sub(s.started).Seconds()),
+	})
+}
+
+// handleReady runs the readiness checks. A failed check answers 503 so a
+// load balancer stops routing to the server; stale data still answers 200
+// so an old dataset does not take every instance out of service, and is
+// left to alerting on the body.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, Readiness{Status: "draining", Checks: []Check{}})
+		return
+	}
+
+	var checks []Check
+	if s.cfg.Weather != nil {
+		checks = append(checks, s.weatherCheck(r.Context()))
+	}
+	for _, d := range s.cfg.Datasets {
+		checks = append(checks, s.datasetCheck(d))
+	}
+
+	ready := Readiness{Status: "ready", Checks: checks}
+	status := http.StatusOK
+	for _, c := range checks {
+		switch c.Status {
+		case StatusFail:
+			ready.Status, status = "not ready", http.StatusServiceUnavailable
+		case StatusStale:
+			if status == http.StatusOK {
+				ready.Status = "degraded"
+			}
+		}
+	}
+	writeJSON(w, status, ready)
+}
+
+// weatherCheck fetches a METAR from the weather provider, reusing the
+// last result for the probe interval
+func (s *Server) weatherCheck(ctx context.Context) Check {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.cfg.Now()
+	if s.probe != nil && now.Sub(s.probe.Checked) < s.cfg.ProbeInterval {
+		return *s.probe
+	}
+
+	started := time.Now()
+	report, err := s.cfg.Weather.Fetch(ctx, weather.METAR, s.cfg.ProbeStation)
+	c := Check{Name: "weather", Status: StatusOK, Checked: now}
+	switch {
+	case err != nil:
+		c.Status, c.Detail = StatusFail, fmt.Sprintf("%s METAR: %v", s.cfg.ProbeStation, err)
+	case report.Stale:
+		c.Status, c.Detail = StatusFail, "provider unreachable, serving cached reports"
+	default:
+		c.Detail = fmt.Sprintf("%s METAR in %s", s.cfg.ProbeStation, time.Since(started).Round(time.Millisecond))
+	}
+
+	// A probe cut short by the caller says nothing about the provider
+	if ctx.Err() == nil {
+		s.probe = &c
+	}
+	return c
+}
+
+// datasetCheck reports the age of a dataset
+func (s *Server) datasetCheck(d Dataset) Check {
+	now := s.cfg.Now()
+	c := Check{Name: d.Name, Status: StatusOK, Checked: now}
+	updated, err := d.Updated()
+	switch {
+	case err != nil:
+		c.Status, c.Detail = StatusFail, err.Error()
+	case updated.IsZero():
+		c.Detail = "built in"
+	default:
+		age := now.Sub(updated)
+		c.Updated, c.AgeSeconds = &updated, age.Seconds()
+		c.Detail = fmt.Sprintf("published %s, %.0f days ago", updated.Format("2006-01-02"), age.Hours()/24)
+		if d.MaxAge > 0 && age > d.MaxAge {
+			c.Status = StatusStale
+			c.Detail += fmt.Sprintf(", older than %.0f days", d.MaxAge.Hours()/24)
+		}
+	}
+	return c
+}
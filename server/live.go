@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// DefaultPollInterval is how often a live scenario checks for a new METAR
+const DefaultPollInterval = 2 * time.Minute
+
+// scenariosPath is where the saved scenarios are served
+const scenariosPath = "/v1/scenarios/"
+
+// liveResult is the data of a result event: the takeoff performance for
+// a saved scenario in the conditions of a METAR, or the problem with it
+type liveResult struct {
+	Scenario string    `json:"scenario"`
+	Airport  string    `json:"airport"`
+	Runway   string    `json:"runway,omitempty"`
+	METAR    string    `json:"metar"`
+	Observed time.Time `json:"observed"`
+	Stale    bool      `json:"stale,omitempty"` // The provider could not be reached and the METAR is the last one cached
+
+	Params  performance.TakeoffParams  `json:"params"`
+	Result  *performance.TakeoffResult `json:"result,omitempty"`
+	Problem *Problem                   `json:"problem,omitempty"`
+
+	// Review is the scenario's sign-off status, without the history
+	Review *reviewState `json:"review,omitempty"`
+}
+
+// liveScenario is a saved scenario resolved for a live stream
+type liveScenario struct {
+	name     string
+	scenario *scenario.Scenario
+	airport  *airports.Airport
+	end      *airports.RunwayEnd // nil to take the wind component from the scenario
+	profile  *aircraft.Profile
+}
+
+// handleScenarios lists the saved scenarios, streams the live results of
+// one from /v1/scenarios/{name}/events, and shows and records its reviews
+// at /v1/scenarios/{name}/review
+func (s *Server) handleScenarios(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, scenariosPath)
+	if rest == "" {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeProblem(w, r, "method-not-allowed", "use GET")
+			return
+		}
+		names := make([]string, 0, len(s.cfg.Scenarios))
+		for name := range s.cfg.Scenarios {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		writeJSON(w, http.StatusOK, map[string][]string{"scenarios": names})
+		return
+	}
+
+	name, view, _ := strings.Cut(rest, "/")
+	sc, ok := s.cfg.Scenarios[name]
+	if !ok {
+		writeProblem(w, r, "unknown-scenario", "no saved scenario "+name)
+		return
+	}
+	switch {
+	case view == "events" && s.cfg.Reports != nil && s.cfg.Airports != nil:
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeProblem(w, r, "method-not-allowed", "use GET")
+			return
+		}
+		live, ok := s.resolveScenario(w, r, name, sc)
+		if !ok {
+			return
+		}
+		s.streamScenario(w, r, live)
+	case view == "review" && s.cfg.Reviews != nil:
+		s.handleReview(w, r, name, sc)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// resolveScenario finds the aircraft, airport and runway of a scenario,
+// writing the problem if one is missing
+func (s *Server) resolveScenario(w http.ResponseWriter, r *http.Request, name string, sc *scenario.Scenario) (*liveScenario, bool) {
+	if sc.Airport == "" || sc.Weight == nil {
+		writeProblem(w, r, "unusable-scenario", name+" needs an airport and a weight")
+		return nil, false
+	}
+	profile, ok := queryProfile(w, r)
+	if !ok {
+		return nil, false
+	}
+	live, problem, err := s.newLiveScenario(r.Context(), name, sc, profile, r.URL.Query().Get("runway"))
+	if err != nil {
+		writeProblem(w, r, problem, err.Error())
+		return nil, false
+	}
+	return live, true
+}
+
+// newLiveScenario resolves a scenario's variant, equipment, airport and
+// runway end, if one is given, returning the problem type with the error
+// if one fails
+func (s *Server) newLiveScenario(ctx context.Context, name string, sc *scenario.Scenario, profile *aircraft.Profile, runwayID string) (*liveScenario, string, error) {
+	profile, err := profile.WithVariant(sc.Variant)
+	if err == nil {
+		profile, err = profile.WithEquipment(sc.Equipment)
+	}
+	if err != nil {
+		return nil, "unusable-scenario", err
+	}
+	airport, err := airports.Resolve(ctx, s.cfg.Airports, sc.Airport)
+	if err != nil {
+		return nil, "unusable-scenario", err
+	}
+
+	live := &liveScenario{name: name, scenario: sc, airport: airport, profile: profile}
+	if runwayID != "" {
+		_, end, err := airport.Runway(runwayID)
+		if err == nil && end == nil {
+			err = fmt.Errorf("specify a single runway end (e.g. 17), not %s", runwayID)
+		}
+		if err != nil {
+			return nil, "unknown-runway", err
+		}
+		live.end = end
+	}
+	return live, "", nil
+}
+
+// latestObservation fetches and decodes the latest METAR at an airport,
+// which must have a temperature for the takeoff chart
+func (s *Server) latestObservation(ctx context.Context, airport *airports.Airport) (*weather.Report, *weather.Observation, error) {
+	station := airport.ICAO
+	if station == "" {
+		station = airport.Ident
+	}
+	report, err := s.cfg.Reports.Fetch(ctx, weather.METAR, station)
+	if err != nil {
+		return nil, nil, err
+	}
+	obs, err := weather.ParseMETAR(report.Raw, s.cfg.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if !obs.HasTemperature {
+		return nil, nil, fmt.Errorf("METAR %s has no temperature", obs.Station)
+	}
+	return report, obs, nil
+}
+
+// streamScenario pushes the scenario's takeoff performance as server-sent
+// events: a result event with the METAR observation time as its id
+// whenever a new METAR arrives, a problem event when the weather cannot
+// be had, and a comment on the other polls to keep proxies from closing
+// the connection. A client reconnecting with the Last-Event-ID header is
+// only sent a result for a newer METAR. The stream ends when the client
+// goes away or the server drains.
+func (s *Server) streamScenario(w http.ResponseWriter, r *http.Request, live *liveScenario) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx := r.Context()
+	calculator := live.profile.NewTakeoffCalculator()
+	last := r.Header.Get("Last-Event-ID")
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		report, obs, err := s.latestObservation(ctx, live.airport)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			writeEvent(w, "problem", "", newProblem(r, "weather-unavailable", err.Error()))
+		case obs.Observed.Format(time.RFC3339) != last:
+			last = obs.Observed.Format(time.RFC3339)
+			result := live.calculate(r, calculator, obs)
+			result.METAR, result.Stale = strings.TrimSpace(report.Raw), report.Stale
+			if s.cfg.Reviews != nil {
+				if state, err := s.reviewState(live.name, live.scenario); err == nil {
+					state.History = nil
+					result.Review = state
+				}
+			}
+			writeEvent(w, "result", last, result)
+		default:
+			fmt.Fprintf(w, ": no new METAR\n\n")
+		}
+		flusher.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.drained:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// calculate computes the scenario's takeoff performance in the conditions
+// of a METAR. The METAR gives the temperature and, with its altimeter
+// setting, the pressure altitude, shown with the density altitude in the
+// result's atmosphere; the wind component is the headwind on the chosen
+// runway, or the scenario's without one.
+func (l *liveScenario) calculate(r *http.Request, calculator *performance.TakeoffCalculator, obs *weather.Observation) liveResult {
+	result := liveResult{
+		Scenario: l.name,
+		Airport:  l.airport.Ident,
+		Observed: obs.Observed,
+		Params: performance.TakeoffParams{
+			PressureAltitude: l.airport.Elevation,
+			Temperature:      obs.Temperature,
+			Weight:           *l.scenario.Weight,
+		},
+	}
+	var field *atmosphere.Field
+	switch {
+	case obs.Altimeter > 0:
+		f := atmosphere.NewField(l.airport.Elevation, obs.Altimeter, obs.Temperature)
+		field, result.Params.PressureAltitude = &f, f.PressureAltitude
+	case l.scenario.PressureAltitude != nil:
+		result.Params.PressureAltitude = *l.scenario.PressureAltitude
+	}
+	if l.end != nil {
+		result.Runway = l.end.ID
+		result.Params.WindComponent = wind.Decompose(obs.Wind, wind.TrueDirection(l.end.TrueHeading), 0).Headwind
+	} else if l.scenario.WindComponent != nil {
+		result.Params.WindComponent = *l.scenario.WindComponent
+	}
+
+	takeoff, err := calculateTakeoff(calculator, result.Params)
+	if err != nil {
+		p := calculationProblem(r, err)
+		result.Problem = &p
+	}
+	if takeoff != nil {
+		takeoff.Atmosphere = field
+	}
+	result.Result = takeoff
+	return result
+}
+
+// writeEvent writes a server-sent event with v as its JSON data
+func writeEvent(w io.Writer, event, id string, v interface{}) {
+	data, _ := json.Marshal(v)
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/margins"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+)
+
+// DefaultMarginInterval is how often the saved scenarios' margins are
+// recorded
+const DefaultMarginInterval = 24 * time.Hour
+
+// RecordMargins records the takeoff margins of the saved scenarios with an
+// airport and a weight, on every runway end in the latest METAR, at once
+// and then every MarginInterval until ctx is done. A METAR already
+// recorded for a scenario is not recorded again.
+func (s *Server) RecordMargins(ctx context.Context) {
+	recorded := make(map[string]time.Time)
+	if samples, err := s.cfg.Margins.Read(time.Time{}); err == nil {
+		for _, sample := range margins.Latest(samples) {
+			if sample.Time.After(recorded[sample.Scenario]) {
+				recorded[sample.Scenario] = sample.Time
+			}
+		}
+	}
+
+	ticker := time.NewTicker(s.cfg.MarginInterval)
+	defer ticker.Stop()
+	for {
+		s.recordMargins(ctx, recorded)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordMargins records one round of margins, traced as a span with the
+// error of any scenario that could not be recorded
+func (s *Server) recordMargins(ctx context.Context, recorded map[string]time.Time) {
+	ctx, span := trace.Start(ctx, "margins.record")
+	defer span.End()
+
+	names := make([]string, 0, len(s.cfg.Scenarios))
+	for name := range s.cfg.Scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	profile, _ := aircraft.Lookup("pa28-161")
+	for _, name := range names {
+		sc := s.cfg.Scenarios[name]
+		if sc.Airport == "" || sc.Weight == nil {
+			continue
+		}
+		live, _, err := s.newLiveScenario(ctx, name, sc, profile, "")
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+		_, obs, err := s.latestObservation(ctx, live.airport)
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+		if !obs.Observed.After(recorded[name]) {
+			continue
+		}
+		samples, err := margins.Compute(live.profile.NewTakeoffCalculator(), name, *sc.Weight, live.airport, obs)
+		if err == nil {
+			err = s.cfg.Margins.Append(samples)
+		}
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+		recorded[name] = obs.Observed
+	}
+}
+
+// handleMetrics serves the latest recorded margin of each scenario and
+// runway end in the OpenMetrics text format, for Prometheus to scrape, or
+// every margin recorded from the "since" query parameter on
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeProblem(w, r, "method-not-allowed", "use GET")
+		return
+	}
+
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		var err error
+		if since, err = localtime.Parse(v, time.UTC); err != nil {
+			writeProblem(w, r, "malformed-request", err.Error())
+			return
+		}
+	}
+	samples, err := s.cfg.Margins.Read(since)
+	if err != nil {
+		writeProblem(w, r, "margins-unavailable", err.Error())
+		return
+	}
+	if since.IsZero() {
+		samples = margins.Latest(samples)
+	}
+	w.Header().Set("Content-Type", margins.ContentType)
+	margins.WriteOpenMetrics(w, samples)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/mission"
+)
+
+// missionPath is where scheduling software asks which aircraft can fly a
+// mission
+const missionPath = "/v1/missions:match"
+
+// missionResponse is the fleet's answer to a mission, with the departure
+// weather it was checked in
+type missionResponse struct {
+	Airport    string              `json:"airport"`
+	METAR      string              `json:"metar"`
+	Observed   time.Time           `json:"observed"`
+	Candidates []mission.Candidate `json:"candidates"`
+}
+
+// handleMission evaluates a mission for every airframe of the fleet in
+// the latest METAR at the departure airport
+func (s *Server) handleMission(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeProblem(w, r, "method-not-allowed", "use POST")
+		return
+	}
+
+	var m mission.Mission
+	if err := decodeBody(r, &m); err != nil {
+		writeProblem(w, r, "malformed-request", err.Error())
+		return
+	}
+	route, err := mission.ResolveRoute(r.Context(), s.cfg.Airports, m)
+	if err != nil {
+		writeProblem(w, r, "unusable-mission", err.Error())
+		return
+	}
+
+	report, obs, err := s.latestObservation(r.Context(), route[0])
+	if err != nil {
+		writeProblem(w, r, "weather-unavailable", err.Error())
+		return
+	}
+
+	candidates, err := mission.Match(s.cfg.Fleet, m, route, obs)
+	if err != nil {
+		writeProblem(w, r, "unusable-mission", err.Error())
+		return
+	}
+	writeResult(w, r, missionResponse{
+		Airport:    route[0].Ident,
+		METAR:      strings.TrimSpace(report.Raw),
+		Observed:   obs.Observed,
+		Candidates: candidates,
+	})
+}
+
+// Table implements Renderer
+func (m missionResponse) Table() (header []string, rows [][]string) {
+	header = []string{"tail", "aircraft", "feasible", "fuel_gal", "max_fuel_gal", "fuel_margin_minutes", "trip_minutes",
+		"arrival", "takeoff_weight", "max_weight", "takeoff_distance", "runway_margin", "crosswind", "reasons"}
+	number := func(v float64, prec int) string { return strconv.FormatFloat(v, 'f', prec, 64) }
+	for _, c := range m.Candidates {
+		rows = append(rows, []string{
+			c.Tail, c.Aircraft, strconv.FormatBool(c.Feasible), number(c.Fuel, 1), number(c.MaxFuel, 1),
+			number(c.FuelMargin, 0), number(c.TripTime, 0), c.Arrival.Format(time.RFC3339),
+			number(c.TakeoffWeight, 0), number(c.MaxWeight, 0), number(c.TakeoffDistance, 0), number(c.RunwayMargin, 2),
+			number(c.Crosswind, 0), strings.Join(c.Reasons, "; "),
+		})
+	}
+	return header, rows
+}
+
+// Text implements Renderer
+func (m missionResponse) Text() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "METAR: %s\n", m.METAR)
+	for _, c := range m.Candidates {
+		if !c.Feasible {
+			fmt.Fprintf(&s, "%s %s: no, %s\n", c.Tail, c.Aircraft, strings.Join(c.Reasons, "; "))
+			continue
+		}
+		fmt.Fprintf(&s, "%s %s: yes, %.1f gal (%.0f min spare), %.0f lbs, %.0f%% of the runway spare, arriving %s\n",
+			c.Tail, c.Aircraft, c.Fuel, c.FuelMargin, c.TakeoffWeight, c.RunwayMargin*100, c.Arrival.UTC().Format("15:04Z"))
+	}
+	return s.String()
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Media types results can be rendered in, the default first
+const (
+	mediaJSON = "application/json"
+	mediaCSV  = "text/csv"
+	mediaText = "text/plain"
+)
+
+// mediaTypes lists the media types of results in order of preference
+var mediaTypes = []string{mediaJSON, mediaCSV, mediaText}
+
+// Renderer is implemented by results that can be written as CSV or text
+// besides JSON
+type Renderer interface {
+	// Table returns the CSV header and rows for the result
+	Table() (header []string, rows [][]string)
+	// Text returns the result as plain text lines
+	Text() string
+}
+
+// negotiate picks the media type for a response from the Accept header,
+// returning "" when none of the accepted types can be produced
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return mediaJSON
+	}
+
+	type candidate struct {
+		media string
+		q     float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		media := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if v, err := strconv.ParseFloat(value, 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{media, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		for _, m := range mediaTypes {
+			if c.media == m || c.media == "*/*" || (strings.HasSuffix(c.media, "/*") && strings.HasPrefix(m, strings.TrimSuffix(c.media, "*"))) {
+				return m
+			}
+		}
+	}
+	return ""
+}
+
+// writeResult writes a result in the media type the client accepts, or a
+// not-acceptable problem
+func writeResult(w http.ResponseWriter, r *http.Request, result Renderer) {
+	w.Header().Add("Vary", "Accept")
+	switch negotiate(r.Header.Get("Accept")) {
+	case mediaJSON:
+		writeJSON(w, http.StatusOK, result)
+	case mediaCSV:
+		header, rows := result.Table()
+		var b bytes.Buffer
+		cw := csv.NewWriter(&b)
+		cw.Write(header)
+		cw.WriteAll(rows)
+		w.Header().Set("Content-Type", mediaCSV+"; charset=utf-8")
+		w.Write(b.Bytes())
+	case mediaText:
+		w.Header().Set("Content-Type", mediaText+"; charset=utf-8")
+		fmt.Fprint(w, result.Text())
+	default:
+		writeProblem(w, r, "not-acceptable", "results are available as "+strings.Join(mediaTypes, ", "))
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// problemPath is where the problem types are described; type URIs are
+// relative to the server so they resolve to that description
+const problemPath = "/problems/"
+
+// Problem is an error response in the RFC 7807 problem details format
+type Problem struct {
+	Type     string `json:"type"`  // URI of the problem type, e.g. "/problems/outside-envelope"
+	Title    string `json:"title"` // Summary of the problem type
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`   // This occurrence of the problem
+	Instance string `json:"instance,omitempty"` // The request path
+
+	// Violations lists each input outside the chart envelope
+	Violations []Violation `json:"violations,omitempty"`
+
+	// Limits lists each station of a loading outside the aircraft's limits
+	Limits wb.Violations `json:"limits,omitempty"`
+}
+
+// Violation is one input outside the chart envelope, with the URI of its
+// problem type
+type Violation struct {
+	Type string `json:"type"`
+	*performance.ValidationError
+}
+
+// problemType describes a kind of problem
+type problemType struct {
+	title       string
+	status      int
+	description string
+}
+
+// problemTypes lists the problems the API reports, by name
+var problemTypes = map[string]problemType{
+	"malformed-request": {"Malformed request", http.StatusBadRequest,
+		"The request body is not valid JSON for the endpoint, or has unknown or missing fields."},
+	"unknown-aircraft": {"Unknown aircraft profile", http.StatusNotFound,
+		"The aircraft query parameter does not name an installed profile."},
+	"method-not-allowed": {"Method not allowed", http.StatusMethodNotAllowed,
+		"The endpoint does not accept the request method; the Allow header lists the ones it does."},
+	"not-acceptable": {"Not acceptable", http.StatusNotAcceptable,
+		"None of the media types in the Accept header can be produced. Results are available as " + strings.Join(mediaTypes, ", ") + "."},
+	"outside-envelope": {"Inputs outside the chart envelope", http.StatusUnprocessableEntity,
+		"One or more inputs are missing or outside the range the POH chart covers. Each is listed under violations with its own type."},
+	"outside-limits": {"Loading outside the aircraft's limits", http.StatusUnprocessableEntity,
+		"The fuel, occupants or baggage exceed a station limit of the aircraft profile, or the CG fits none of its envelopes. Each is listed under limits."},
+	"calculation-failed": {"Calculation failed", http.StatusUnprocessableEntity,
+		"The inputs passed validation but the result could not be computed from the chart."},
+	"unknown-scenario": {"Unknown scenario", http.StatusNotFound,
+		"No saved scenario has the name in the path; GET /v1/scenarios/ lists them."},
+	"unusable-scenario": {"Scenario cannot be computed live", http.StatusUnprocessableEntity,
+		"Live results need a saved scenario with a departure airport and a weight, whose airport and equipment are known."},
+	"unknown-runway": {"Unknown runway", http.StatusNotFound,
+		"The runway query parameter does not name a single runway end at the scenario's airport."},
+	"unusable-submission": {"Submission cannot be briefed", http.StatusUnprocessableEntity,
+		"The scenario picked out of the submission by the webhook mapping is invalid, lacks an airport, a weight or a callback URL, or names an unknown aircraft, airport or runway."},
+	"bad-signature": {"Bad signature", http.StatusUnauthorized,
+		"The submission's X-Otto-Signature header is missing or is not the HMAC-SHA256 of the body under the shared secret."},
+	"unusable-mission": {"Mission cannot be evaluated", http.StatusUnprocessableEntity,
+		"The mission's route needs at least two known airports, a departure runway and readable winds aloft."},
+	"weather-unavailable": {"Weather unavailable", http.StatusBadGateway,
+		"The METAR for the departure airport of the scenario, mission or submission could not be fetched or has no temperature. Live results resume with the next usable report."},
+	"review-unavailable": {"Reviews unavailable", http.StatusInternalServerError,
+		"The scenario's reviews could not be read or recorded on the server."},
+	"margins-unavailable": {"Margins unavailable", http.StatusInternalServerError,
+		"The recorded takeoff margins could not be read on the server."},
+	"batch-too-large": {"Batch too large", http.StatusRequestEntityTooLarge,
+		fmt.Sprintf("A batch request may hold at most %d parameter sets; split it into several requests.", maxBatchItems)},
+
+	// Types of the individual envelope violations
+	"missing-input": {"Missing input", http.StatusUnprocessableEntity,
+		"A required input was not given."},
+	"below-minimum": {"Input below the chart minimum", http.StatusUnprocessableEntity,
+		"The input is below the lowest value the chart covers; min gives the limit."},
+	"above-maximum": {"Input above the chart maximum", http.StatusUnprocessableEntity,
+		"The input is above the highest value the chart covers; max gives the limit."},
+	"unsupported-input": {"Input the chart cannot be corrected for", http.StatusUnprocessableEntity,
+		"The chart has no correction for the input, such as a flap setting it was not drawn for."},
+}
+
+// violationTypes maps validation error codes to problem type names
+var violationTypes = map[string]string{
+	performance.CodeMissing:      "missing-input",
+	performance.CodeBelowMinimum: "below-minimum",
+	performance.CodeAboveMaximum: "above-maximum",
+	performance.CodeUnsupported:  "unsupported-input",
+}
+
+// writeProblem writes a problem of a named type as the response
+func writeProblem(w http.ResponseWriter, r *http.Request, name, detail string) {
+	p := newProblem(r, name, detail)
+	writeProblemResponse(w, &p)
+}
+
+// writeCalculationProblem writes the problem for an error from a
+// calculation
+func writeCalculationProblem(w http.ResponseWriter, r *http.Request, err error) {
+	p := calculationProblem(r, err)
+	writeProblemResponse(w, &p)
+}
+
+// newProblem creates a problem of a named type
+func newProblem(r *http.Request, name, detail string) Problem {
+	t := problemTypes[name]
+	return Problem{
+		Type:     problemPath + name,
+		Title:    t.title,
+		Status:   t.status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+}
+
+// calculationProblem creates the problem for an error from a calculation:
+// outside-envelope listing every violation for ValidationErrors, and
+// calculation-failed otherwise
+func calculationProblem(r *http.Request, err error) Problem {
+	var errs performance.ValidationErrors
+	if !errors.As(err, &errs) {
+		return newProblem(r, "calculation-failed", err.Error())
+	}
+	p := newProblem(r, "outside-envelope", errs.Error())
+	p.Violations = make([]Violation, len(errs))
+	for i, e := range errs {
+		p.Violations[i] = Violation{Type: problemPath + violationTypes[e.Code], ValidationError: e}
+	}
+	return p
+}
+
+// writeProblemResponse writes a problem as the response
+func writeProblemResponse(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(p)
+}
+
+// handleProblems describes a problem type, or lists them all
+func (s *Server) handleProblems(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, problemPath)
+	w.Header().Set("Content-Type", mediaText+"; charset=utf-8")
+	if name == "" {
+		names := make([]string, 0, len(problemTypes))
+		for name := range problemTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "%s%s: %s\n", problemPath, name, problemTypes[name].title)
+		}
+		return
+	}
+
+	t, ok := problemTypes[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, "%s (HTTP %d)\n\n%s\n", t.title, t.status, t.description)
+}
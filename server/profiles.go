@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/bundle"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+)
+
+// DefaultProfileInterval is how often the profiles directory is checked
+// for new, changed or removed chart bundles
+const DefaultProfileInterval = 10 * time.Second
+
+// profilesPath lists the loaded profiles and rejected bundles
+const profilesPath = "/v1/admin/profiles"
+
+// loadedBundle is a chart bundle installed from the profiles directory
+type loadedBundle struct {
+	ID       string
+	File     string
+	SHA256   string
+	LoadedAt time.Time
+}
+
+// rejectedBundle is a chart bundle that failed to parse or lint
+type rejectedBundle struct {
+	File       string    `json:"file"`
+	SHA256     string    `json:"sha256"`
+	Error      string    `json:"error"`
+	RejectedAt time.Time `json:"rejected_at"`
+}
+
+// WatchProfiles loads the chart bundles in ProfileDir at once and then
+// reloads them every ProfileInterval until ctx is done. A new or changed
+// bundle is installed only if it passes its lint; otherwise it is
+// rejected and the version already loaded from the file, if any, keeps
+// being served. A removed bundle is uninstalled.
+func (s *Server) WatchProfiles(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ProfileInterval)
+	defer ticker.Stop()
+	for {
+		s.reloadProfiles(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reloadProfiles checks the profiles directory once, traced as a span with
+// the error of any bundle rejected
+func (s *Server) reloadProfiles(ctx context.Context) {
+	_, span := trace.Start(ctx, "profiles.reload")
+	defer span.End()
+
+	entries, err := os.ReadDir(s.cfg.ProfileDir)
+	if err != nil {
+		// Keep serving what is loaded; the directory may be mid-deploy
+		span.RecordError(err)
+		return
+	}
+
+	s.profilesMu.Lock()
+	defer s.profilesMu.Unlock()
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".json") {
+			continue
+		}
+		file := e.Name()
+		seen[file] = true
+		data, err := os.ReadFile(filepath.Join(s.cfg.ProfileDir, file))
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		digest := hex.EncodeToString(sum[:])
+		if s.loaded[file].SHA256 == digest {
+			// Changed back to the version loaded
+			delete(s.rejected, file)
+			continue
+		}
+		if s.rejected[file].SHA256 == digest {
+			continue
+		}
+		if err := s.installBundle(file, digest, data); err != nil {
+			span.RecordError(fmt.Errorf("%s: %w", file, err))
+			s.rejected[file] = rejectedBundle{File: file, SHA256: digest, Error: err.Error(), RejectedAt: s.cfg.Now()}
+			continue
+		}
+		delete(s.rejected, file)
+	}
+
+	for file, b := range s.loaded {
+		if !seen[file] {
+			aircraft.Uninstall(b.ID)
+			delete(s.loaded, file)
+		}
+	}
+	for file := range s.rejected {
+		if !seen[file] {
+			delete(s.rejected, file)
+		}
+	}
+}
+
+// installBundle lints a bundle and installs its profile in place of the
+// one loaded from the same file
+func (s *Server) installBundle(file, digest string, data []byte) error {
+	b, err := bundle.Parse(data)
+	if err != nil {
+		return err
+	}
+	p, err := b.Profile()
+	if err != nil {
+		return err
+	}
+	for other, loaded := range s.loaded {
+		if other != file && loaded.ID == p.ID {
+			return fmt.Errorf("profile %s is already loaded from %s", p.ID, other)
+		}
+	}
+	if err := aircraft.Install(p); err != nil {
+		return err
+	}
+	if previous, ok := s.loaded[file]; ok && previous.ID != p.ID {
+		aircraft.Uninstall(previous.ID)
+	}
+	s.loaded[file] = loadedBundle{ID: p.ID, File: file, SHA256: digest, LoadedAt: s.cfg.Now()}
+	return nil
+}
+
+// profileStatus is a profile served, and the bundle it came from if any
+type profileStatus struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Version  string     `json:"version,omitempty"`
+	Builtin  bool       `json:"builtin"`
+	File     string     `json:"file,omitempty"`
+	SHA256   string     `json:"sha256,omitempty"`
+	LoadedAt *time.Time `json:"loaded_at,omitempty"`
+}
+
+// handleProfiles lists the profiles served, built in and loaded from
+// bundles, with the bundles rejected at their last change
+func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeProblem(w, r, "method-not-allowed", "use GET")
+		return
+	}
+
+	s.profilesMu.Lock()
+	defer s.profilesMu.Unlock()
+	byID := make(map[string]loadedBundle, len(s.loaded))
+	for _, b := range s.loaded {
+		byID[b.ID] = b
+	}
+	var resp struct {
+		Profiles []profileStatus  `json:"profiles"`
+		Rejected []rejectedBundle `json:"rejected"`
+	}
+	for _, p := range aircraft.Profiles() {
+		status := profileStatus{ID: p.ID, Name: p.Name, Version: p.Version, Builtin: aircraft.Builtin(p.ID)}
+		if b, ok := byID[p.ID]; ok {
+			loadedAt := b.LoadedAt
+			status.File, status.SHA256, status.LoadedAt = b.File, b.SHA256, &loadedAt
+		}
+		resp.Profiles = append(resp.Profiles, status)
+	}
+	resp.Rejected = make([]rejectedBundle, 0, len(s.rejected))
+	for _, b := range s.rejected {
+		resp.Rejected = append(resp.Rejected, b)
+	}
+	sort.Slice(resp.Rejected, func(i, j int) bool { return resp.Rejected[i].File < resp.Rejected[j].File })
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+)
+
+// unreviewed and outdated are the review statuses of a scenario with no
+// review, and with a review of an earlier version of its inputs
+const (
+	unreviewed = "unreviewed"
+	outdated   = "outdated"
+)
+
+// reviewState is the sign-off status of a saved scenario
+type reviewState struct {
+	Status  string            `json:"status"` // The latest review's status, unreviewed or outdated
+	Latest  *scenario.Review  `json:"latest,omitempty"`
+	History []scenario.Review `json:"history,omitempty"`
+}
+
+// reviewRequest is the body of a review POST
+type reviewRequest struct {
+	Status   string `json:"status"`
+	Reviewer string `json:"reviewer"`
+	Comment  string `json:"comment"`
+}
+
+// handleReview shows the reviews of a scenario, or records a new one
+func (s *Server) handleReview(w http.ResponseWriter, r *http.Request, name string, sc *scenario.Scenario) {
+	switch r.Method {
+	case http.MethodGet:
+		state, err := s.reviewState(name, sc)
+		if err != nil {
+			writeProblem(w, r, "review-unavailable", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, state)
+	case http.MethodPost:
+		var req reviewRequest
+		if err := decodeBody(r, &req); err != nil {
+			writeProblem(w, r, "malformed-request", err.Error())
+			return
+		}
+		review := scenario.Review{
+			Status:   strings.ToLower(strings.TrimSpace(req.Status)),
+			Reviewer: strings.TrimSpace(req.Reviewer),
+			Comment:  strings.TrimSpace(req.Comment),
+			Time:     s.cfg.Now().UTC(),
+			Digest:   scenario.Digest(sc),
+		}
+		if err := review.Validate(); err != nil {
+			writeProblem(w, r, "malformed-request", err.Error())
+			return
+		}
+		if err := s.cfg.Reviews.Add(name, review); err != nil {
+			writeProblem(w, r, "review-unavailable", err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, review)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		writeProblem(w, r, "method-not-allowed", "use GET or POST")
+	}
+}
+
+// reviewState reads the reviews of a scenario. A review of different
+// inputs than the scenario's now is outdated, so an approval never
+// carries over to an edited scenario.
+func (s *Server) reviewState(name string, sc *scenario.Scenario) (*reviewState, error) {
+	history, err := s.cfg.Reviews.History(name)
+	if err != nil {
+		return nil, err
+	}
+	state := &reviewState{Status: unreviewed, History: history}
+	if len(history) > 0 {
+		latest := history[len(history)-1]
+		state.Latest, state.Status = &latest, latest.Status
+		if latest.Digest != scenario.Digest(sc) {
+			state.Status = outdated
+		}
+	}
+	return state, nil
+}
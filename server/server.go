@@ -0,0 +1,377 @@
+// Package server serves the performance calculators over HTTP for hosted
+// deployments, with health and readiness endpoints for the operator
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/margins"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/webhook"
+)
+
+// DefaultProbeInterval is how long the result of a weather provider probe
+// is reused, so frequent readiness checks do not hammer the provider
+const DefaultProbeInterval = time.Minute
+
+// maxBodySize limits request bodies
+const maxBodySize = 1 << 20
+
+// Config configures a server
+type Config struct {
+	// Weather is probed by /readyz with a METAR request for ProbeStation.
+	// Pass the provider's client rather than a cache, or the probe only
+	// shows what the cache holds. Nil skips the check.
+	Weather       weather.Fetcher
+	ProbeStation  string
+	ProbeInterval time.Duration // default: DefaultProbeInterval
+
+	// Datasets are reported by /readyz with their age
+	Datasets []Dataset
+
+	// AllowedOrigins are the browser origins allowed to call the API,
+	// e.g. "https://efb.example.com", or "*" for any; none by default
+	AllowedOrigins []string
+
+	// Scenarios are saved scenarios, by name, whose results are pushed to
+	// live dashboards as new METARs arrive from Reports for their airports,
+	// found in Airports. Reports should be a cache so several dashboards
+	// do not each reach the provider. Without Reports and Airports the
+	// scenario endpoints are off.
+	Scenarios    map[string]*scenario.Scenario
+	Reports      weather.Fetcher
+	Airports     airports.Provider
+	PollInterval time.Duration // default: DefaultPollInterval
+
+	// Fleet is offered to scheduling software, which asks which of its
+	// airframes can fly a mission; with Reports and Airports it turns the
+	// mission endpoint on
+	Fleet []aircraft.Tail
+
+	// Webhook maps scenario submissions pushed by other systems, such as
+	// a booking in the club's scheduling software, and signs the briefings
+	// posted back with Callbacks (default: a client with a 30s timeout).
+	// With Reports and Airports it turns the webhook endpoint on.
+	Webhook   *webhook.Mapping
+	Callbacks *http.Client
+
+	// Reviews keeps the CFI or dispatcher sign-offs of the saved
+	// scenarios; nil turns the review endpoint off
+	Reviews *scenario.ReviewLog
+
+	// Margins records the takeoff margins of the saved scenarios every
+	// MarginInterval, run by RecordMargins, and serves them at /metrics
+	// for trend analysis; nil turns both off
+	Margins        *margins.Log
+	MarginInterval time.Duration // default: DefaultMarginInterval
+
+	// ProfileDir is a directory of chart bundles (JSON) loaded by
+	// WatchProfiles every ProfileInterval, each gated on its lint, and
+	// listed with the built-in profiles at /v1/admin/profiles; empty
+	// turns both off
+	ProfileDir      string
+	ProfileInterval time.Duration // default: DefaultProfileInterval
+
+	Now func() time.Time // Clock for data ages (default: time.Now)
+}
+
+// Server handles the API requests
+type Server struct {
+	cfg     Config
+	mux     *http.ServeMux
+	started time.Time
+
+	mu    sync.Mutex
+	probe *Check // Last weather provider probe
+
+	profilesMu sync.Mutex
+	loaded     map[string]loadedBundle   // Bundles installed from ProfileDir, by file name
+	rejected   map[string]rejectedBundle // Bundles whose last change failed, by file name
+
+	draining atomic.Bool   // Set at shutdown to fail readiness
+	drained  chan struct{} // Closed at shutdown to end live streams
+}
+
+// New creates a server
+func New(cfg Config) *Server {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = DefaultProbeInterval
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.MarginInterval <= 0 {
+		cfg.MarginInterval = DefaultMarginInterval
+	}
+	if cfg.ProfileInterval <= 0 {
+		cfg.ProfileInterval = DefaultProfileInterval
+	}
+	if cfg.Callbacks == nil {
+		cfg.Callbacks = &http.Client{Timeout: 30 * time.Second, Transport: &trace.Transport{}}
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		mux:      http.NewServeMux(),
+		started:  cfg.Now(),
+		loaded:   make(map[string]loadedBundle),
+		rejected: make(map[string]rejectedBundle),
+		drained:  make(chan struct{}),
+	}
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.HandleFunc("/readyz", s.handleReady)
+	s.mux.HandleFunc("/v1/takeoff", s.handleTakeoff)
+	s.mux.HandleFunc("/v1/takeoff:batch", s.handleTakeoffBatch)
+	s.mux.HandleFunc("/v1/wb", s.handleWB)
+	s.mux.HandleFunc(problemPath, s.handleProblems)
+	if len(cfg.Fleet) > 0 && cfg.Reports != nil && cfg.Airports != nil {
+		s.mux.HandleFunc(missionPath, s.handleMission)
+	}
+	if cfg.Webhook != nil && cfg.Reports != nil && cfg.Airports != nil {
+		s.mux.HandleFunc(webhookPath, s.handleWebhook)
+	}
+	if (cfg.Reports != nil && cfg.Airports != nil) || cfg.Reviews != nil {
+		s.mux.HandleFunc(scenariosPath, s.handleScenarios)
+	}
+	if cfg.Margins != nil {
+		s.mux.HandleFunc("/metrics", s.handleMetrics)
+	}
+	if cfg.ProfileDir != "" {
+		s.mux.HandleFunc(profilesPath, s.handleProfiles)
+	}
+	return s
+}
+
+// Drain makes /readyz answer 503 so a load balancer stops sending new
+// requests ahead of a shutdown, and ends live streams so their clients
+// reconnect elsewhere; other requests are still served
+func (s *Server) Drain() {
+	if s.draining.CompareAndSwap(false, true) {
+		close(s.drained)
+	}
+}
+
+// ServeHTTP implements http.Handler, tracing each request as a child of
+// the caller's trace when it sends a traceparent header
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.Start(trace.Extract(r.Context(), r.Header), "HTTP "+r.Method+" "+r.URL.Path)
+	if span == nil {
+		s.serve(w, r)
+		return
+	}
+	defer span.End()
+	span.SetAttribute("http.method", r.Method)
+	span.SetAttribute("http.target", r.URL.RequestURI())
+
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.serve(rec, r.WithContext(ctx))
+	span.SetAttribute("http.status_code", strconv.Itoa(rec.status))
+	if rec.status >= http.StatusInternalServerError {
+		span.RecordError(errors.New(http.StatusText(rec.status)))
+	}
+}
+
+// serve answers CORS preflight requests and routes the others
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if s.cors(w, r) {
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// statusRecorder keeps the status code of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush implements http.Flusher so streamed responses can be traced
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// handleTakeoff computes takeoff performance for the parameters in the
+// body, for the aircraft in the "aircraft" query parameter (default:
+// pa28-161)
+func (s *Server) handleTakeoff(w http.ResponseWriter, r *http.Request) {
+	profile, ok := postProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var params performance.TakeoffParams
+	if err := decodeBody(r, &params); err != nil {
+		writeProblem(w, r, "malformed-request", err.Error())
+		return
+	}
+
+	_, span := trace.Start(r.Context(), "takeoff.calculate")
+	span.SetAttribute("aircraft", profile.ID)
+	result, err := calculateTakeoff(profile.NewTakeoffCalculator(), params)
+	span.RecordError(err)
+	span.End()
+
+	if err != nil {
+		writeCalculationProblem(w, r, err)
+		return
+	}
+	writeResult(w, r, takeoffResponse{result})
+}
+
+// postProfile checks that a calculation request is a POST and looks up
+// the aircraft it names, writing the problem if either fails
+func postProfile(w http.ResponseWriter, r *http.Request) (*aircraft.Profile, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeProblem(w, r, "method-not-allowed", "use POST")
+		return nil, false
+	}
+	return queryProfile(w, r)
+}
+
+// queryProfile looks up the aircraft in the "aircraft" query parameter
+// (default: pa28-161), writing the problem if there is none
+func queryProfile(w http.ResponseWriter, r *http.Request) (*aircraft.Profile, bool) {
+	id := r.URL.Query().Get("aircraft")
+	if id == "" {
+		id = "pa28-161"
+	}
+	profile, err := aircraft.Lookup(id)
+	if err != nil {
+		writeProblem(w, r, "unknown-aircraft", err.Error())
+		return nil, false
+	}
+	return profile, true
+}
+
+// takeoffResponse renders a takeoff result as CSV or text
+type takeoffResponse struct {
+	*performance.TakeoffResult
+}
+
+// Table implements Renderer
+func (t takeoffResponse) Table() (header []string, rows [][]string) {
+	return takeoffColumns, [][]string{takeoffRow(t.TakeoffResult)}
+}
+
+// Text implements Renderer
+func (t takeoffResponse) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Takeoff distance over 50 ft: %.0f ft", t.TakeoffDistance)
+	if t.Tolerance >= 1 {
+		fmt.Fprintf(&b, " ± %.0f ft", math.Ceil(t.Tolerance/10)*10)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Liftoff speed: %.0f KIAS\n", t.LiftoffSpeed)
+	fmt.Fprintf(&b, "Barrier speed: %.0f KIAS\n", t.BarrierSpeed)
+	switch roll := t.Roll; {
+	case t.GroundRoll > 0:
+		fmt.Fprintf(&b, "Ground roll: %.0f ft\n", t.GroundRoll)
+		if roll != nil {
+			fmt.Fprintf(&b, "Roll time (estimated): about %.0f s at %.1f kt/s average\n", roll.Time, roll.Acceleration)
+		}
+	case roll != nil:
+		fmt.Fprintf(&b, "Ground roll (estimated): %.0f ft, about %.0f s at %.1f kt/s average\n", roll.Distance, roll.Time, roll.Acceleration)
+	}
+	for _, a := range t.Adjustments {
+		fmt.Fprintf(&b, "Adjusted: %s takeoff distance, not from the POH chart\n", a)
+	}
+	return b.String()
+}
+
+// takeoffColumns are the CSV columns of a takeoff result. The columns
+// after adjustments were added later, so they go last for clients that
+// read the columns by position.
+var takeoffColumns = []string{"takeoff_distance", "liftoff_speed", "barrier_speed", "adjustments",
+	"tolerance", "ground_roll", "ground_roll_estimated", "roll_time", "roll_acceleration"}
+
+// takeoffRow formats a takeoff result as a CSV row. The ground roll is the
+// charted one, or the estimate when the chart has none; the roll columns
+// are empty when the aircraft would be airborne standing still.
+func takeoffRow(result *performance.TakeoffResult) []string {
+	adjustments := make([]string, len(result.Adjustments))
+	for i, a := range result.Adjustments {
+		adjustments[i] = a.String()
+	}
+	groundRoll, estimated, rollTime, acceleration := "", "", "", ""
+	if result.GroundRoll > 0 {
+		groundRoll, estimated = strconv.FormatFloat(result.GroundRoll, 'f', 0, 64), "false"
+	}
+	if roll := result.Roll; roll != nil {
+		if groundRoll == "" {
+			groundRoll, estimated = strconv.FormatFloat(roll.Distance, 'f', 0, 64), "true"
+		}
+		rollTime = strconv.FormatFloat(roll.Time, 'f', 1, 64)
+		acceleration = strconv.FormatFloat(roll.Acceleration, 'f', 2, 64)
+	}
+	return []string{
+		strconv.FormatFloat(result.TakeoffDistance, 'f', 0, 64),
+		strconv.FormatFloat(result.LiftoffSpeed, 'f', 1, 64),
+		strconv.FormatFloat(result.BarrierSpeed, 'f', 1, 64),
+		strings.Join(adjustments, "; "),
+		strconv.FormatFloat(result.Tolerance, 'f', 0, 64),
+		groundRoll,
+		estimated,
+		rollTime,
+		acceleration,
+	}
+}
+
+// calculateTakeoff checks the parameters against the chart envelope,
+// returning every violation as ValidationErrors, and computes the result
+func calculateTakeoff(calculator *performance.TakeoffCalculator, params performance.TakeoffParams) (*performance.TakeoffResult, error) {
+	if errs := calculator.Validate(params); len(errs) > 0 {
+		return nil, errs
+	}
+	return calculator.CalculateTakeoff(params)
+}
+
+// decodeBody decodes a JSON request body into v
+func decodeBody(r *http.Request, v interface{}) error {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxBodySize {
+		return errors.New("request body too large")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,736 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/airports"
+	"github.com/ryanbmilbourne/otto-perf/bundle"
+	"github.com/ryanbmilbourne/otto-perf/margins"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+	"github.com/ryanbmilbourne/otto-perf/wb"
+	"github.com/ryanbmilbourne/otto-perf/weather"
+	"github.com/ryanbmilbourne/otto-perf/webhook"
+)
+
+// fakeFetcher returns a fixed report or error and counts calls
+type fakeFetcher struct {
+	err   error
+	stale bool
+	calls int
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, product weather.Product, station string) (*weather.Report, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &weather.Report{Station: station, Product: product, Raw: station + " 151353Z 17008KT 10SM CLR 24/12 A3002", Stale: f.stale}, nil
+}
+
+// do sends a request to the server and decodes the JSON response into v
+func do(t *testing.T, s *Server, method, target, body string, v interface{}) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", method, target, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+func TestTakeoff(t *testing.T) {
+	s := New(Config{})
+
+	var result performance.TakeoffResult
+	status := do(t, s, http.MethodPost, "/v1/takeoff",
+		`{"pressure_altitude": 1500, "temperature_c": 26.7, "weight": 2325, "wind_component": 15}`, &result)
+	if status != http.StatusOK || result.TakeoffDistance < 2000 || result.TakeoffDistance > 2200 {
+		t.Errorf("Got %d %+v", status, result)
+	}
+
+	var failed Problem
+	status = do(t, s, http.MethodPost, "/v1/takeoff",
+		`{"pressure_altitude": 9000, "temperature_c": 60, "weight": 2325, "wind_component": 0}`, &failed)
+	if status != http.StatusUnprocessableEntity || failed.Type != "/problems/outside-envelope" || failed.Status != status ||
+		failed.Instance != "/v1/takeoff" || len(failed.Violations) != 2 {
+		t.Fatalf("Expected both envelope violations, got %d %+v", status, failed)
+	}
+	if v := failed.Violations[0]; v.Type != "/problems/above-maximum" || v.Field != performance.FieldPressureAltitude || v.Max != 7000 {
+		t.Errorf("Unexpected violation %+v", *v.ValidationError)
+	}
+
+	tests := []struct {
+		method, target, body string
+		problem              string
+	}{
+		{http.MethodPost, "/v1/takeoff", `{"weight": "heavy"}`, "/problems/malformed-request"},
+		{http.MethodPost, "/v1/takeoff?aircraft=c172", `{}`, "/problems/unknown-aircraft"},
+		{http.MethodGet, "/v1/takeoff", "", "/problems/method-not-allowed"},
+	}
+	for _, tt := range tests {
+		var p Problem
+		status := do(t, s, tt.method, tt.target, tt.body, &p)
+		if p.Type != tt.problem || p.Status != status || p.Title == "" {
+			t.Errorf("%s %s: got %d %+v, expected %s", tt.method, tt.target, status, p, tt.problem)
+		}
+	}
+
+	// Every problem type is described at its URI
+	for name := range problemTypes {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, problemPath+name, nil))
+		if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), problemTypes[name].title) {
+			t.Errorf("%s: got %d %q", name, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// spans keeps exported spans
+type spans []*trace.SpanData
+
+func (s *spans) Export(span *trace.SpanData) {
+	*s = append(*s, span)
+}
+
+func TestTracing(t *testing.T) {
+	var exported spans
+	trace.SetExporter(&exported)
+	defer trace.SetExporter(nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/takeoff", strings.NewReader(`{"pressure_altitude": 9000, "temperature_c": 15, "weight": 2325, "wind_component": 0}`))
+	req.Header.Set(trace.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	New(Config{}).ServeHTTP(rec, req)
+
+	if len(exported) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(exported))
+	}
+	calculate, request := exported[0], exported[1]
+	if calculate.Name != "takeoff.calculate" || calculate.Parent != request.SpanID || calculate.Status != trace.StatusError {
+		t.Errorf("Unexpected calculation span %+v", *calculate)
+	}
+	if request.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || request.Attributes["http.status_code"] != "422" {
+		t.Errorf("Unexpected request span %+v", *request)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+	fetcher := &fakeFetcher{}
+	published := now.AddDate(0, 0, -20)
+	s := New(Config{
+		Weather:      fetcher,
+		ProbeStation: "KJYO",
+		Datasets: []Dataset{
+			{Name: "airports", Updated: func() (time.Time, error) { return published, nil }, MaxAge: 56 * 24 * time.Hour},
+			{Name: "charts", Updated: func() (time.Time, error) { return time.Time{}, nil }},
+		},
+		Now: func() time.Time { return now },
+	})
+
+	var ready Readiness
+	if status := do(t, s, http.MethodGet, "/readyz", "", &ready); status != http.StatusOK || ready.Status != "ready" {
+		t.Fatalf("Got %d %+v", status, ready)
+	}
+	if len(ready.Checks) != 3 || ready.Checks[1].AgeSeconds != 20*24*3600 || ready.Checks[2].Detail != "built in" {
+		t.Errorf("Unexpected checks %+v", ready.Checks)
+	}
+
+	// Stale data degrades without failing
+	published = now.AddDate(0, 0, -60)
+	if status := do(t, s, http.MethodGet, "/readyz", "", &ready); status != http.StatusOK || ready.Status != "degraded" || ready.Checks[1].Status != StatusStale {
+		t.Errorf("Expected stale airport data to degrade, got %d %+v", status, ready)
+	}
+
+	// The probe is reused within the interval, then repeated
+	fetcher.err = errors.New("connection refused")
+	if status := do(t, s, http.MethodGet, "/readyz", "", &ready); status != http.StatusOK || fetcher.calls != 1 {
+		t.Errorf("Expected the probe to be reused, got %d after %d probes", status, fetcher.calls)
+	}
+	now = now.Add(DefaultProbeInterval)
+	if status := do(t, s, http.MethodGet, "/readyz", "", &ready); status != http.StatusServiceUnavailable || ready.Status != "not ready" {
+		t.Errorf("Expected an unreachable provider to fail, got %d %+v", status, ready)
+	}
+
+	s.Drain()
+	now = now.Add(-DefaultProbeInterval)
+	if status := do(t, s, http.MethodGet, "/readyz", "", &ready); status != http.StatusServiceUnavailable || ready.Status != "draining" {
+		t.Errorf("Expected a draining server not to be ready, got %d %+v", status, ready)
+	}
+
+	var health map[string]interface{}
+	if status := do(t, s, http.MethodGet, "/healthz", "", &health); status != http.StatusOK || health["status"] != StatusOK {
+		t.Errorf("Got %d %v", status, health)
+	}
+}
+
+func TestContentNegotiation(t *testing.T) {
+	s := New(Config{})
+	body := `{"pressure_altitude": 0, "temperature_c": 15, "weight": 2000, "wind_component": 0}`
+	tests := []struct {
+		accept   string
+		status   int
+		content  string
+		prefix   string
+		contains string
+	}{
+		{"", http.StatusOK, "application/json", "{", `"ground_roll": 818.75`},
+		{"text/csv", http.StatusOK, "text/csv; charset=utf-8",
+			"takeoff_distance,liftoff_speed,barrier_speed,adjustments,tolerance,ground_roll,ground_roll_estimated,roll_time,roll_acceleration\n" +
+				"1425,46.0,52.0,,0,819,false,18.6,2.81\n", ""},
+		{"text/plain;q=0.5, text/csv;q=0.4", http.StatusOK, "text/plain; charset=utf-8", "Takeoff distance over 50 ft: 1425 ft\n",
+			"Ground roll: 819 ft\nRoll time (estimated): about 19 s at 2.8 kt/s average\n"},
+		{"text/*", http.StatusOK, "text/csv; charset=utf-8", "takeoff_distance", ""},
+		{"application/xml", http.StatusNotAcceptable, "application/problem+json", "{", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/takeoff", strings.NewReader(body))
+		req.Header.Set("Accept", tt.accept)
+		s.ServeHTTP(rec, req)
+		if rec.Code != tt.status || rec.Header().Get("Content-Type") != tt.content || !strings.HasPrefix(rec.Body.String(), tt.prefix) ||
+			!strings.Contains(rec.Body.String(), tt.contains) {
+			t.Errorf("Accept %q: got %d %s %q", tt.accept, rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+		}
+	}
+
+	// Between the chart's lines the distance carries a tolerance band
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/takeoff",
+		strings.NewReader(`{"pressure_altitude": 1500, "temperature_c": 26.7, "weight": 2325, "wind_component": 15}`))
+	req.Header.Set("Accept", "text/plain")
+	s.ServeHTTP(rec, req)
+	if first, _, _ := strings.Cut(rec.Body.String(), "\n"); !strings.HasSuffix(first, "0 ft") || !strings.Contains(first, " ± ") {
+		t.Errorf("Expected a tolerance band, got %q", rec.Body.String())
+	}
+}
+
+func TestCORS(t *testing.T) {
+	s := New(Config{AllowedOrigins: []string{"https://efb.example.com"}})
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/v1/takeoff", nil)
+	preflight.Header.Set("Origin", "https://efb.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", "POST")
+	preflight.Header.Set("Access-Control-Request-Headers", "content-type")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, preflight)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://efb.example.com" ||
+		rec.Header().Get("Access-Control-Allow-Headers") != "content-type" {
+		t.Errorf("Unexpected preflight answer %d %v", rec.Code, rec.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/takeoff", strings.NewReader(`{"pressure_altitude": 0, "temperature_c": 15, "weight": 2000, "wind_component": 0}`))
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected no CORS headers for another origin, got %v", rec.Header())
+	}
+}
+
+func TestTakeoffBatch(t *testing.T) {
+	s := New(Config{})
+	body := `[
+		{"pressure_altitude": 0, "temperature_c": 15, "weight": 2000, "wind_component": 0},
+		{"pressure_altitude": 9000, "temperature_c": 15, "weight": 2000, "wind_component": 0},
+		{"pressure_altitude": "high"},
+		{"pressure_altitude": 1000, "temperature_c": 20, "weight": 2200, "wind_component": 0}
+	]`
+
+	var response takeoffBatchResponse
+	if status := do(t, s, http.MethodPost, "/v1/takeoff:batch", body, &response); status != http.StatusOK || len(response.Results) != 4 {
+		t.Fatalf("Got %d %+v", status, response)
+	}
+	for i, item := range response.Results {
+		if item.Index != i {
+			t.Errorf("Item %d has index %d", i, item.Index)
+		}
+	}
+	r := response.Results
+	if r[0].Result == nil || r[3].Result == nil || r[3].Result.TakeoffDistance != 2025 {
+		t.Errorf("Expected results for items 0 and 3, got %+v %+v", r[0], r[3])
+	}
+	if r[1].Problem == nil || r[1].Problem.Type != "/problems/outside-envelope" || len(r[1].Problem.Violations) != 1 {
+		t.Errorf("Expected an envelope problem for item 1, got %+v", r[1].Problem)
+	}
+	if r[2].Problem == nil || r[2].Problem.Type != "/problems/malformed-request" {
+		t.Errorf("Expected a malformed item 2, got %+v", r[2].Problem)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/takeoff:batch", strings.NewReader(body))
+	req.Header.Set("Accept", "text/csv")
+	s.ServeHTTP(rec, req)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if len(lines) != 5 || lines[4] != "3,1000,20,2200,0,2025,48.0,54.0,,0,1185,false,25.5,2.16," {
+		t.Errorf("Unexpected CSV %q", rec.Body.String())
+	}
+
+	var p Problem
+	if status := do(t, s, http.MethodPost, "/v1/takeoff:batch", `{"weight": 2000}`, &p); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a body that is not an array, got %d %+v", status, p)
+	}
+	tooMany := "[" + strings.TrimSuffix(strings.Repeat("{},", maxBatchItems+1), ",") + "]"
+	if status := do(t, s, http.MethodPost, "/v1/takeoff:batch", tooMany, &p); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for too many items, got %d %+v", status, p)
+	}
+}
+
+func TestWB(t *testing.T) {
+	s := New(Config{})
+
+	var summary wb.Summary
+	status := do(t, s, http.MethodPost, "/v1/wb", `{"fuel": "20+5gal", "people": [170, 150], "empty_arm": 86.0}`, &summary)
+	if status != http.StatusOK || summary.FuelGallons != 25 || summary.TakeoffCG < 83 || summary.TakeoffCG > 86.5 {
+		t.Fatalf("Got %d %+v", status, summary)
+	}
+	if len(summary.Categories) != 2 || !summary.Categories[0].Qualified || !summary.Categories[1].Qualified {
+		t.Errorf("Expected the loading to qualify for both categories, got %+v", summary.Categories)
+	}
+
+	var p Problem
+	status = do(t, s, http.MethodPost, "/v1/wb", `{"fuel_gal": 60, "people": [170], "baggage": 210}`, &p)
+	if status != http.StatusUnprocessableEntity || p.Type != "/problems/outside-limits" || len(p.Limits) != 2 {
+		t.Fatalf("Expected both station limits, got %d %+v", status, p)
+	}
+	if v := p.Limits[0]; v.Station != wb.StationFuel || v.Code != wb.CodeAboveMaximum || v.Limit != 48 {
+		t.Errorf("Unexpected violation %+v", *v)
+	}
+	if status := do(t, s, http.MethodPost, "/v1/wb", `{"fuel": "brim"}`, &p); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown fuel state, got %d %+v", status, p)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/wb", strings.NewReader(`{"fuel_gal": 48, "people": [170, 160], "baggage": 40}`))
+	req.Header.Set("Accept", "text/plain")
+	s.ServeHTTP(rec, req)
+	if body := rec.Body.String(); !strings.Contains(body, "normal category: qualifies") || !strings.Contains(body, "utility category: no") {
+		t.Errorf("Unexpected text %q", body)
+	}
+}
+
+func TestMission(t *testing.T) {
+	provider, err := airports.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fleet := []aircraft.Tail{
+		{Registration: "N101", Profile: profile, WeightBalance: profile.WeightBalance},
+		{Registration: "N102", Profile: profile, WeightBalance: profile.WeightBalance},
+	}
+	fleet[1].WeightBalance.EmptyWeight = 1620
+
+	if status := do(t, New(Config{}), http.MethodPost, "/v1/missions:match", `{}`, nil); status != http.StatusNotFound {
+		t.Errorf("Expected no mission endpoint without a fleet, got %d", status)
+	}
+	s := New(Config{
+		Fleet:    fleet,
+		Reports:  &fakeFetcher{},
+		Airports: provider,
+		Now:      func() time.Time { return time.Date(2026, time.October, 15, 14, 0, 0, 0, time.UTC) },
+	})
+
+	var result missionResponse
+	status := do(t, s, http.MethodPost, "/v1/missions:match",
+		`{"route": ["KJYO", "KFDK"], "people": [170, 170, 170, 170], "departure": "2026-10-15T15:00:00Z"}`, &result)
+	if status != http.StatusOK || result.Airport != "JYO" || len(result.Candidates) != 2 {
+		t.Fatalf("Got %d %+v", status, result)
+	}
+	if c := result.Candidates[0]; c.Tail != "N101" || !c.Feasible || !c.Arrival.After(time.Date(2026, time.October, 15, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected N101 feasible first, got %+v", c)
+	}
+	if c := result.Candidates[1]; c.Tail != "N102" || c.Feasible || len(c.Reasons) == 0 {
+		t.Errorf("Expected N102 not feasible with reasons, got %+v", c)
+	}
+
+	var p Problem
+	if status := do(t, s, http.MethodPost, "/v1/missions:match", `{"route": ["KJYO"]}`, &p); status != http.StatusUnprocessableEntity || p.Type != "/problems/unusable-mission" {
+		t.Errorf("Expected an unusable mission, got %d %+v", status, p)
+	}
+	if status := do(t, s, http.MethodPost, "/v1/missions:match", `{"route": ["KJYO", "KXYZ"]}`, &p); status != http.StatusUnprocessableEntity || p.Type != "/problems/unusable-mission" {
+		t.Errorf("Expected an unusable mission for an unknown airport, got %d %+v", status, p)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/missions:match", strings.NewReader(`{"route": ["KJYO", "KFDK"], "people": [170]}`))
+	req.Header.Set("Accept", "text/csv")
+	s.ServeHTTP(rec, req)
+	if lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n"); len(lines) != 3 || !strings.HasPrefix(lines[0], "tail,aircraft,feasible") {
+		t.Errorf("Unexpected CSV %q", rec.Body.String())
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	provider, err := airports.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	callbacks := make(chan webhookBriefing, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(webhook.SignatureHeader) != webhook.Sign("s3cret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var b webhookBriefing
+		json.Unmarshal(body, &b)
+		callbacks <- b
+	}))
+	defer receiver.Close()
+
+	s := New(Config{
+		Webhook: &webhook.Mapping{
+			Fields:      map[string]string{"airport": "booking.from", "weight": "booking.weight"},
+			ID:          "booking.id",
+			Runway:      "booking.runway",
+			CallbackURL: receiver.URL,
+			Secret:      "s3cret",
+		},
+		Reports:  &fakeFetcher{},
+		Airports: provider,
+		Now:      func() time.Time { return time.Date(2026, time.October, 15, 14, 0, 0, 0, time.UTC) },
+	})
+	submit := func(body, signature string, v interface{}) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/webhooks/scenarios", strings.NewReader(body))
+		req.Header.Set(webhook.SignatureHeader, signature)
+		s.ServeHTTP(rec, req)
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("Decoding %q: %v", rec.Body.String(), err)
+		}
+		return rec.Code
+	}
+
+	body := `{"booking": {"id": "B-17", "from": "KJYO", "runway": "17", "weight": "1040 kg"}}`
+	var accepted webhookAccepted
+	if status := submit(body, webhook.Sign("s3cret", []byte(body)), &accepted); status != http.StatusAccepted || accepted.ID != "B-17" {
+		t.Fatalf("Got %d %+v", status, accepted)
+	}
+	select {
+	case b := <-callbacks:
+		if b.ID != "B-17" || b.Briefing == nil || b.Briefing.Runway != "17" || b.Briefing.Result == nil || b.Briefing.METAR == "" {
+			t.Errorf("Unexpected briefing %+v", b)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No callback")
+	}
+
+	var p Problem
+	if status := submit(body, webhook.Sign("guess", []byte(body)), &p); status != http.StatusUnauthorized || p.Type != "/problems/bad-signature" {
+		t.Errorf("Expected a bad signature, got %d %+v", status, p)
+	}
+	for _, bad := range []string{
+		`{"booking": {"id": "B-18", "from": "KJYO"}}`,
+		`{"booking": {"from": "KJYO", "weight": "heavy"}}`,
+		`{"booking": {"from": "KJYO", "runway": "9", "weight": 2300}}`,
+	} {
+		if status := submit(bad, webhook.Sign("s3cret", []byte(bad)), &p); status != http.StatusUnprocessableEntity || p.Type != "/problems/unusable-submission" {
+			t.Errorf("%s: expected an unusable submission, got %d %+v", bad, status, p)
+		}
+	}
+}
+
+// metarFetcher returns METARs in turn, repeating the last
+type metarFetcher struct {
+	mu     sync.Mutex
+	metars []string
+}
+
+func (f *metarFetcher) Fetch(ctx context.Context, product weather.Product, station string) (*weather.Report, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw := f.metars[0]
+	if len(f.metars) > 1 {
+		f.metars = f.metars[1:]
+	}
+	return &weather.Report{Station: station, Product: product, Raw: raw}, nil
+}
+
+func TestLiveScenario(t *testing.T) {
+	provider, err := airports.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	weight := 2325.0
+	s := New(Config{
+		Scenarios: map[string]*scenario.Scenario{
+			"lesson":   {Airport: "KJYO", Weight: &weight},
+			"anywhere": {Weight: &weight},
+		},
+		Reports: &metarFetcher{metars: []string{
+			"KJYO 151353Z 17008KT 10SM CLR 24/12 A3002",
+			"KJYO 151353Z 17008KT 10SM CLR 24/12 A3002",
+			"KJYO 151453Z 35010KT 10SM CLR 26/12 A3000",
+		}},
+		Airports:     provider,
+		PollInterval: 10 * time.Millisecond,
+		Now:          func() time.Time { return time.Date(2026, time.October, 15, 15, 0, 0, 0, time.UTC) },
+	})
+
+	var list map[string][]string
+	if status := do(t, s, http.MethodGet, "/v1/scenarios/", "", &list); status != http.StatusOK || strings.Join(list["scenarios"], ",") != "anywhere,lesson" {
+		t.Errorf("Got %d %v", status, list)
+	}
+	var p Problem
+	if status := do(t, s, http.MethodGet, "/v1/scenarios/missing/events", "", &p); status != http.StatusNotFound || p.Type != "/problems/unknown-scenario" {
+		t.Errorf("Expected an unknown scenario, got %d %+v", status, p)
+	}
+	if status := do(t, s, http.MethodGet, "/v1/scenarios/anywhere/events", "", &p); status != http.StatusUnprocessableEntity || p.Type != "/problems/unusable-scenario" {
+		t.Errorf("Expected an unusable scenario, got %d %+v", status, p)
+	}
+	if status := do(t, s, http.MethodGet, "/v1/scenarios/lesson/events?runway=18", "", &p); status != http.StatusNotFound || p.Type != "/problems/unknown-runway" {
+		t.Errorf("Expected an unknown runway, got %d %+v", status, p)
+	}
+
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	resp, err := http.Get(ts.URL + "/v1/scenarios/lesson/events?runway=17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Unexpected content type %q", ct)
+	}
+
+	// A result for each new METAR, and comments in between
+	var ids []string
+	var results []liveResult
+	var comments int
+	lines := bufio.NewScanner(resp.Body)
+	for len(results) < 2 && lines.Scan() {
+		line := lines.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			ids = append(ids, strings.TrimPrefix(line, "id: "))
+		case strings.HasPrefix(line, "data: "):
+			var result liveResult
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &result); err != nil {
+				t.Fatal(err)
+			}
+			results = append(results, result)
+		case strings.HasPrefix(line, ":"):
+			comments++
+		}
+	}
+	if len(results) != 2 || comments == 0 || strings.Join(ids, ",") != "2026-10-15T13:53:00Z,2026-10-15T14:53:00Z" {
+		t.Fatalf("Got ids %v, %d comments, results %+v", ids, comments, results)
+	}
+	first, second := results[0], results[1]
+	if first.Runway != "17" || first.Result == nil || first.Params.Temperature != 24 || first.Params.WindComponent < 7 {
+		t.Errorf("Unexpected first result %+v", first)
+	}
+	if a := first.Result.Atmosphere; a == nil || a.Altimeter != 30.02 || a.PressureAltitude != first.Params.PressureAltitude || a.DensityAltitude <= a.PressureAltitude {
+		t.Errorf("Expected the atmosphere of the field, got %+v", a)
+	}
+	if second.Params.Temperature != 26 || second.Params.WindComponent > -9 || second.Problem == nil || second.Problem.Type != "/problems/outside-envelope" {
+		t.Errorf("Expected a tailwind outside the chart, got %+v", second)
+	}
+
+	// Draining ends the stream
+	s.Drain()
+	for lines.Scan() {
+	}
+	if err := lines.Err(); err != nil {
+		t.Errorf("Expected the stream to end, got %v", err)
+	}
+}
+
+func TestScenarioReview(t *testing.T) {
+	weight := 2325.0
+	lesson := &scenario.Scenario{Airport: "KJYO", Weight: &weight}
+	s := New(Config{
+		Scenarios: map[string]*scenario.Scenario{"lesson": lesson},
+		Reviews:   scenario.NewReviewLog(t.TempDir()),
+		Now:       func() time.Time { return time.Date(2026, time.October, 15, 15, 0, 0, 0, time.UTC) },
+	})
+
+	var state reviewState
+	if status := do(t, s, http.MethodGet, "/v1/scenarios/lesson/review", "", &state); status != http.StatusOK || state.Status != unreviewed {
+		t.Errorf("Expected an unreviewed scenario, got %d %+v", status, state)
+	}
+	var p Problem
+	if status := do(t, s, http.MethodPost, "/v1/scenarios/lesson/review", `{"status": "approved"}`, &p); status != http.StatusBadRequest {
+		t.Errorf("Expected a review without a reviewer to be refused, got %d %+v", status, p)
+	}
+	if status := do(t, s, http.MethodGet, "/v1/scenarios/lesson/events", "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected no live results without weather, got %d", status)
+	}
+
+	var review scenario.Review
+	body := `{"status": "Approved", "reviewer": "J. Smith, CFI", "comment": "Solo with a 1.5 factor"}`
+	if status := do(t, s, http.MethodPost, "/v1/scenarios/lesson/review", body, &review); status != http.StatusCreated || review.Status != scenario.Approved || review.Digest != scenario.Digest(lesson) {
+		t.Errorf("Got %d %+v", status, review)
+	}
+	if do(t, s, http.MethodGet, "/v1/scenarios/lesson/review", "", &state); state.Status != scenario.Approved || len(state.History) != 1 || state.Latest.Reviewer != "J. Smith, CFI" {
+		t.Errorf("Expected an approval, got %+v", state)
+	}
+
+	// Editing the scenario outdates the approval
+	weight = 2440
+	if do(t, s, http.MethodGet, "/v1/scenarios/lesson/review", "", &state); state.Status != outdated {
+		t.Errorf("Expected an outdated approval, got %+v", state)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	provider, err := airports.Embedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	weight := 2325.0
+	reports := &fakeFetcher{}
+	log := margins.NewLog(filepath.Join(t.TempDir(), "margins.jsonl"))
+	if status := do(t, New(Config{}), http.MethodGet, "/metrics", "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected no metrics endpoint without a margin log, got %d", status)
+	}
+	s := New(Config{
+		Scenarios: map[string]*scenario.Scenario{
+			"lesson":     {Airport: "KJYO", Weight: &weight},
+			"no-airport": {Weight: &weight},
+		},
+		Reports:  reports,
+		Airports: provider,
+		Margins:  log,
+		Now:      func() time.Time { return time.Date(2026, time.October, 15, 14, 0, 0, 0, time.UTC) },
+	})
+
+	recorded := make(map[string]time.Time)
+	s.recordMargins(context.Background(), recorded)
+	s.recordMargins(context.Background(), recorded)
+	samples, err := log.Read(time.Time{})
+	if err != nil || len(samples) == 0 || reports.calls != 2 {
+		t.Fatalf("Expected one round of samples from two fetches, got %+v, %v after %d calls", samples, err, reports.calls)
+	}
+	for _, sample := range samples {
+		if sample.Scenario != "lesson" || !sample.Time.Equal(time.Date(2026, time.October, 15, 13, 53, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected sample %+v", sample)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != margins.ContentType {
+		t.Fatalf("Got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, `otto_takeoff_margin_feet{scenario="lesson",airport="JYO",runway="17"}`) || !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("Unexpected metrics\n%s", body)
+	}
+
+	var p Problem
+	if status := do(t, s, http.MethodGet, "/metrics?since=yesterday", "", &p); status != http.StatusBadRequest {
+		t.Errorf("Expected a bad since to be refused, got %d %+v", status, p)
+	}
+}
+
+// writeBundle writes a chart bundle of the PA-28-161 chart, with its
+// takeoff distances scaled
+func writeBundle(t *testing.T, path, id, version string, scale float64) {
+	t.Helper()
+	base, err := aircraft.Lookup("pa28-161")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chart := base.NewTakeoffCalculator().Chart()
+	for _, row := range chart.Distances {
+		for i := range row {
+			row[i] *= scale
+		}
+	}
+	b := bundle.Bundle{ID: id, Base: base.ID, Version: version, TakeoffChart: chart}
+	for _, c := range base.Golden {
+		b.Golden = append(b.Golden, bundle.Case{Name: c.Name, Params: c.Params, TakeoffDistance: c.TakeoffDistance, Tolerance: c.Tolerance})
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchProfiles(t *testing.T) {
+	if status := do(t, New(Config{}), http.MethodGet, "/v1/admin/profiles", "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected no admin endpoint without a profiles directory, got %d", status)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "n8123x.json")
+	writeBundle(t, path, "N8123X", "2026-10-01", 1)
+	s := New(Config{ProfileDir: dir})
+	defer s.reloadProfiles(context.Background())
+	defer os.Remove(path)
+
+	type listing struct {
+		Profiles []profileStatus
+		Rejected []rejectedBundle
+	}
+	list := func() listing {
+		var l listing
+		if status := do(t, s, http.MethodGet, "/v1/admin/profiles", "", &l); status != http.StatusOK {
+			t.Fatalf("Got %d", status)
+		}
+		return l
+	}
+	version := func(l listing, id string) string {
+		for _, p := range l.Profiles {
+			if p.ID == id {
+				return p.Version
+			}
+		}
+		return ""
+	}
+
+	s.reloadProfiles(context.Background())
+	l := list()
+	if version(l, "n8123x") != "2026-10-01" || len(l.Rejected) != 0 {
+		t.Fatalf("Expected the bundle loaded, got %+v", l)
+	}
+	if status := do(t, s, http.MethodPost, "/v1/takeoff?aircraft=n8123x", `{"pressure_altitude": 1500, "temperature_c": 26.7, "weight": 2325, "wind_component": 15}`, nil); status != http.StatusOK {
+		t.Errorf("Expected the loaded profile served, got %d", status)
+	}
+
+	// A chart that no longer matches its reference points is rejected and
+	// the loaded version kept
+	writeBundle(t, path, "N8123X", "2026-10-02", 1.2)
+	s.reloadProfiles(context.Background())
+	l = list()
+	if version(l, "n8123x") != "2026-10-01" || len(l.Rejected) != 1 || !strings.Contains(l.Rejected[0].Error, "takeoff distance") {
+		t.Errorf("Expected the change rejected, got %+v", l)
+	}
+
+	writeBundle(t, path, "N8123X", "2026-10-03", 1)
+	s.reloadProfiles(context.Background())
+	if l = list(); version(l, "n8123x") != "2026-10-03" || len(l.Rejected) != 0 {
+		t.Errorf("Expected the new version loaded, got %+v", l)
+	}
+
+	os.Remove(path)
+	s.reloadProfiles(context.Background())
+	if _, err := aircraft.Lookup("n8123x"); err == nil {
+		t.Error("Expected the removed bundle uninstalled")
+	}
+}
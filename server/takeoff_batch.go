@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/batch"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+)
+
+// maxBatchItems limits the parameter sets in one batch request
+const maxBatchItems = 10000
+
+// batchItem is the outcome of one parameter set of a batch: a result or
+// the problem with it
+type batchItem struct {
+	Index   int                        `json:"index"`
+	Result  *performance.TakeoffResult `json:"result,omitempty"`
+	Problem *Problem                   `json:"problem,omitempty"`
+
+	params *performance.TakeoffParams // nil when the item could not be decoded
+}
+
+// takeoffBatchResponse lists the outcome of every parameter set in order
+type takeoffBatchResponse struct {
+	Results []batchItem `json:"results"`
+}
+
+// handleTakeoffBatch computes takeoff performance for an array of
+// parameter sets, so a client can build a table in one round trip. Each
+// set gets its result or its own problem; the request only fails as a
+// whole when it is not an array or is too large.
+func (s *Server) handleTakeoffBatch(w http.ResponseWriter, r *http.Request) {
+	profile, ok := postProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var raw []json.RawMessage
+	if err := decodeBody(r, &raw); err != nil {
+		writeProblem(w, r, "malformed-request", "expected an array of takeoff parameter sets: "+err.Error())
+		return
+	}
+	if len(raw) > maxBatchItems {
+		writeProblem(w, r, "batch-too-large", fmt.Sprintf("%d parameter sets given, at most %d allowed", len(raw), maxBatchItems))
+		return
+	}
+
+	ctx, span := trace.Start(r.Context(), "takeoff.batch")
+	span.SetAttribute("aircraft", profile.ID)
+	span.SetAttribute("batch.items", strconv.Itoa(len(raw)))
+	defer span.End()
+
+	calculator := profile.NewTakeoffCalculator()
+	items := make([]batchItem, len(raw))
+	err := batch.Each(ctx, len(raw), func(i int) {
+		item := batchItem{Index: i}
+		var params performance.TakeoffParams
+		if err := json.Unmarshal(raw[i], &params); err != nil {
+			p := newProblem(r, "malformed-request", err.Error())
+			item.Problem = &p
+		} else if result, err := calculateTakeoff(calculator, params); err != nil {
+			p := calculationProblem(r, err)
+			item.params, item.Problem = &params, &p
+		} else {
+			item.params, item.Result = &params, result
+		}
+		items[i] = item
+	}, batch.Options{})
+	if err != nil {
+		// The client went away
+		span.RecordError(err)
+		return
+	}
+	writeResult(w, r, takeoffBatchResponse{items})
+}
+
+// Table implements Renderer with a row per parameter set
+func (b takeoffBatchResponse) Table() (header []string, rows [][]string) {
+	header = append([]string{"index", "pressure_altitude", "temperature_c", "weight", "wind_component"}, takeoffColumns...)
+	header = append(header, "error")
+	number := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, item := range b.Results {
+		row := []string{strconv.Itoa(item.Index), "", "", "", ""}
+		if p := item.params; p != nil {
+			row = append(row[:1], number(p.PressureAltitude), number(p.Temperature), number(p.Weight), number(p.WindComponent))
+		}
+		if item.Result != nil {
+			row = append(row, takeoffRow(item.Result)...)
+			row = append(row, "")
+		} else {
+			row = append(row, make([]string, len(takeoffColumns))...)
+			row = append(row, item.Problem.Detail)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// Text implements Renderer with a line per parameter set
+func (b takeoffBatchResponse) Text() string {
+	var s strings.Builder
+	for _, item := range b.Results {
+		if item.Result == nil {
+			fmt.Fprintf(&s, "%d: %s: %s\n", item.Index, item.Problem.Title, item.Problem.Detail)
+			continue
+		}
+		fmt.Fprintf(&s, "%d: %.0f ft over 50 ft, liftoff %.0f KIAS, barrier %.0f KIAS\n",
+			item.Index, item.Result.TakeoffDistance, item.Result.LiftoffSpeed, item.Result.BarrierSpeed)
+	}
+	return s.String()
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ryanbmilbourne/otto-perf/wb"
+)
+
+// wbRequest is the body of a weight and balance request: the loading, and
+// the airframe's own empty weight and CG from its W&B record where they
+// differ from the profile's typical values
+type wbRequest struct {
+	wb.Loading
+	Fuel        string  `json:"fuel,omitempty"` // Fuel state instead of fuel_gal, e.g. "tabs+1hr"
+	EmptyWeight float64 `json:"empty_weight,omitempty"`
+	EmptyArm    float64 `json:"empty_arm,omitempty"`
+}
+
+// handleWB builds up the weight and CG of a loading for the aircraft in the
+// "aircraft" query parameter (default: pa28-161), with no weather or runway
+func (s *Server) handleWB(w http.ResponseWriter, r *http.Request) {
+	profile, ok := postProfile(w, r)
+	if !ok {
+		return
+	}
+
+	var req wbRequest
+	if err := decodeBody(r, &req); err != nil {
+		writeProblem(w, r, "malformed-request", err.Error())
+		return
+	}
+
+	weights := profile.WeightBalance
+	if req.EmptyWeight > 0 {
+		weights.EmptyWeight = req.EmptyWeight
+	}
+	if req.EmptyArm > 0 {
+		weights.EmptyArm = req.EmptyArm
+	}
+	if req.Fuel != "" {
+		gallons, err := weights.FuelState(req.Fuel)
+		if err != nil {
+			writeProblem(w, r, "malformed-request", err.Error())
+			return
+		}
+		req.FuelGallons = gallons
+	}
+
+	summary, err := weights.Compute(req.Loading)
+	var violations wb.Violations
+	if errors.As(err, &violations) {
+		p := newProblem(r, "outside-limits", violations.Error())
+		p.Limits = violations
+		writeProblemResponse(w, &p)
+		return
+	} else if err != nil {
+		writeProblem(w, r, "calculation-failed", err.Error())
+		return
+	}
+	writeResult(w, r, wbResponse{summary})
+}
+
+// wbResponse renders a weight and balance summary as CSV or text
+type wbResponse struct {
+	*wb.Summary
+}
+
+// Table implements Renderer
+func (b wbResponse) Table() (header []string, rows [][]string) {
+	header = []string{"empty_weight", "fuel_gal", "fuel", "people", "baggage", "ramp_weight", "taxi_fuel", "takeoff_weight", "takeoff_cg"}
+	number := func(v float64, prec int) string { return strconv.FormatFloat(v, 'f', prec, 64) }
+	row := []string{
+		number(b.EmptyWeight, 0), number(b.FuelGallons, 1), number(b.Fuel, 0), number(b.People, 0), number(b.Baggage, 0),
+		number(b.RampWeight, 0), number(b.TaxiFuel, 0), number(b.TakeoffWeight, 0), number(b.TakeoffCG, 1),
+	}
+	for _, q := range b.Categories {
+		header = append(header, q.Category)
+		row = append(row, strconv.FormatBool(q.Qualified))
+	}
+	return header, [][]string{row}
+}
+
+// Text implements Renderer
+func (b wbResponse) Text() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "Ramp weight: %.0f lbs\n", b.RampWeight)
+	fmt.Fprintf(&s, "Takeoff weight: %.0f lbs\n", b.TakeoffWeight)
+	if b.TakeoffCG > 0 {
+		fmt.Fprintf(&s, "Takeoff CG: %.1f in\n", b.TakeoffCG)
+	}
+	for _, q := range b.Categories {
+		if q.Qualified {
+			fmt.Fprintf(&s, "%s category: qualifies\n", q.Category)
+		} else {
+			fmt.Fprintf(&s, "%s category: no, %s\n", q.Category, q.Reason)
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/aircraft"
+	"github.com/ryanbmilbourne/otto-perf/trace"
+	"github.com/ryanbmilbourne/otto-perf/webhook"
+)
+
+// webhookPath is where other systems push scenario submissions
+const webhookPath = "/v1/webhooks/scenarios"
+
+// callbackTimeout bounds the briefing and its callback, retries included
+const callbackTimeout = 2 * time.Minute
+
+// webhookAccepted answers a submission that will be briefed
+type webhookAccepted struct {
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+}
+
+// webhookBriefing is posted to a submission's callback URL: the takeoff
+// performance in the latest METAR at its airport, or the problem when
+// there is no usable METAR
+type webhookBriefing struct {
+	ID       string      `json:"id,omitempty"` // The sender's reference from the submission
+	Briefing *liveResult `json:"briefing,omitempty"`
+	Problem  *Problem    `json:"problem,omitempty"`
+}
+
+// handleWebhook takes a scenario submission in the sender's own JSON,
+// picks the scenario out of it with the configured mapping, answers 202
+// and posts the briefing to the submission's callback URL
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeProblem(w, r, "method-not-allowed", "use POST")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err == nil && len(body) > maxBodySize {
+		err = errors.New("request body too large")
+	}
+	if err != nil {
+		writeProblem(w, r, "malformed-request", err.Error())
+		return
+	}
+	if !s.cfg.Webhook.Verify(body, r.Header.Get(webhook.SignatureHeader)) {
+		writeProblem(w, r, "bad-signature", "the "+webhook.SignatureHeader+" header does not match the body")
+		return
+	}
+
+	sub, err := s.cfg.Webhook.Extract(body)
+	if err != nil {
+		writeProblem(w, r, "unusable-submission", err.Error())
+		return
+	}
+	if sub.Scenario.Airport == "" || sub.Scenario.Weight == nil {
+		writeProblem(w, r, "unusable-submission", "the submission needs an airport and a weight")
+		return
+	}
+	if sub.Aircraft == "" {
+		sub.Aircraft = "pa28-161"
+	}
+	profile, err := aircraft.Lookup(sub.Aircraft)
+	if err != nil {
+		writeProblem(w, r, "unusable-submission", err.Error())
+		return
+	}
+	live, _, err := s.newLiveScenario(r.Context(), sub.ID, sub.Scenario, profile, sub.Runway)
+	if err != nil {
+		writeProblem(w, r, "unusable-submission", err.Error())
+		return
+	}
+
+	// The briefing outlives the request but stays in its trace
+	ctx, span := trace.Start(detached{r.Context()}, "webhook.briefing")
+	span.SetAttribute("webhook.id", sub.ID)
+	go func() {
+		defer span.End()
+		ctx, cancel := context.WithTimeout(ctx, callbackTimeout)
+		defer cancel()
+		span.RecordError(s.brief(ctx, r, sub, live))
+	}()
+	writeJSON(w, http.StatusAccepted, webhookAccepted{ID: sub.ID, Status: "accepted"})
+}
+
+// brief computes a submission's briefing and posts it to the callback URL
+func (s *Server) brief(ctx context.Context, r *http.Request, sub *webhook.Submission, live *liveScenario) error {
+	callback := webhookBriefing{ID: sub.ID}
+	report, obs, err := s.latestObservation(ctx, live.airport)
+	if err != nil {
+		p := newProblem(r, "weather-unavailable", err.Error())
+		callback.Problem = &p
+	} else {
+		result := live.calculate(r, live.profile.NewTakeoffCalculator(), obs)
+		result.METAR, result.Stale = strings.TrimSpace(report.Raw), report.Stale
+		callback.Briefing = &result
+	}
+	return s.cfg.Webhook.Post(ctx, s.cfg.Callbacks, sub.Callback, callback)
+}
+
+// detached keeps a request context's values, such as its trace span,
+// without its cancellation, for work that continues after the response
+type detached struct{ context.Context }
+
+// Deadline implements context.Context
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+// Done implements context.Context
+func (detached) Done() <-chan struct{} { return nil }
+
+// Err implements context.Context
+func (detached) Err() error { return nil }
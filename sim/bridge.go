@@ -0,0 +1,137 @@
+package sim
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+// DefaultInterval is how often a bridge publishes by default
+const DefaultInterval = time.Second
+
+// maxDatagram is the largest datagram read from the simulator
+const maxDatagram = 64 << 10
+
+// Decoder turns the datagrams from a simulator into its State, reporting
+// whether the State has every input yet
+type Decoder interface {
+	Decode(packet []byte) (State, bool, error)
+}
+
+// JSONDecoder decodes the JSON State a helper sends, one per datagram
+type JSONDecoder struct{}
+
+// Decode implements Decoder
+func (JSONDecoder) Decode(packet []byte) (State, bool, error) {
+	s, err := ParseState(packet)
+	return s, err == nil, err
+}
+
+// Bridge reads a simulator's State and publishes the takeoff performance
+// for it. The simulator may send many times a second; the bridge publishes
+// the latest State at most once an interval, and only when a new one has
+// arrived.
+type Bridge struct {
+	Calculator *performance.TakeoffCalculator
+	Decoder    Decoder
+	Interval   time.Duration // How often to publish at most; 0 for DefaultInterval
+	Weight     float64       // in pounds, in place of the simulator's when above 0
+	Targets    []net.Addr    // UDP addresses to send each update to as JSON
+	Hub        *Hub          // WebSocket clients to send each update to as JSON; nil for none
+	OnUpdate   func(Update)  // Called with each update; nil for none
+	OnInvalid  func(error)   // Called for each datagram that cannot be decoded; nil to skip them silently
+}
+
+// received is a State and when it was read
+type received struct {
+	state State
+	at    time.Time
+}
+
+// Run reads datagrams from conn and publishes updates, sending the UDP
+// ones from conn too, until ctx is done or reading fails. The WebSocket
+// clients are sent a close frame when ctx is done.
+func (b *Bridge) Run(ctx context.Context, conn net.PacketConn) error {
+	interval := b.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	var mu sync.Mutex
+	var latest *received
+	readErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, maxDatagram)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			state, ready, err := b.Decoder.Decode(buf[:n])
+			if err != nil {
+				if b.OnInvalid != nil {
+					b.OnInvalid(err)
+				}
+				continue
+			}
+			if ready {
+				mu.Lock()
+				latest = &received{state, time.Now()}
+				mu.Unlock()
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var published time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			conn.SetReadDeadline(time.Now())
+			if b.Hub != nil {
+				b.Hub.Close()
+			}
+			return nil
+		case err := <-readErr:
+			return err
+		case <-ticker.C:
+		}
+
+		mu.Lock()
+		r := latest
+		mu.Unlock()
+		if r == nil || !r.at.After(published) {
+			continue
+		}
+		published = r.at
+		if b.Weight > 0 {
+			r.state.Weight = b.Weight
+		}
+		b.publish(conn, Compute(b.Calculator, r.state, r.at, time.Now()))
+	}
+}
+
+// publish sends an update to the UDP targets, the WebSocket clients and
+// OnUpdate. A target that cannot be reached is skipped, as it may not be
+// listening yet.
+func (b *Bridge) publish(conn net.PacketConn, u Update) {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return
+	}
+	for _, addr := range b.Targets {
+		conn.WriteTo(data, addr)
+	}
+	if b.Hub != nil {
+		b.Hub.Broadcast(data)
+	}
+	if b.OnUpdate != nil {
+		b.OnUpdate(u)
+	}
+}
@@ -0,0 +1,100 @@
+// Package sim computes takeoff performance for the conditions in a flight
+// simulator, for training devices. A Bridge reads the simulated weather,
+// weight and position over UDP, from X-Plane's dataref subscription or as
+// JSON from a helper such as a SimConnect client for MSFS, and publishes
+// the performance for them as JSON over UDP and WebSocket as they change.
+package sim
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/atmosphere"
+	"github.com/ryanbmilbourne/otto-perf/performance"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// State is the simulated aircraft and its weather. It is also the JSON a
+// helper sends for a simulator without a native protocol, one object per
+// datagram.
+type State struct {
+	PressureAltitude float64 `json:"pressure_altitude"` // in feet
+	Temperature      float64 `json:"temperature_c"`     // Outside air temperature in °C
+	Weight           float64 `json:"weight"`            // in pounds
+	Heading          float64 `json:"heading"`           // in degrees, in the same reference as the wind
+	WindDirection    float64 `json:"wind_direction"`    // Direction the wind blows from in degrees
+	WindSpeed        float64 `json:"wind_speed"`        // in knots
+	Latitude         float64 `json:"latitude"`          // in degrees
+	Longitude        float64 `json:"longitude"`         // in degrees
+	OnGround         bool    `json:"on_ground"`
+}
+
+// ParseState decodes the JSON state sent by a helper, which must have at
+// least the pressure altitude, temperature and weight
+func ParseState(data []byte) (State, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return State{}, fmt.Errorf("invalid state: %w", err)
+	}
+	for _, name := range []string{"pressure_altitude", "temperature_c", "weight"} {
+		if _, ok := fields[name]; !ok {
+			return State{}, fmt.Errorf("invalid state: missing %s", name)
+		}
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, fmt.Errorf("invalid state: %w", err)
+	}
+	return s, nil
+}
+
+// Params are the takeoff inputs for the state: the wind component is the
+// headwind along the aircraft's heading, so the aircraft should be lined up
+// on the runway for it to be the runway's
+func (s State) Params() performance.TakeoffParams {
+	components := wind.Decompose(wind.Wind{From: wind.TrueDirection(s.WindDirection), Speed: s.WindSpeed},
+		wind.TrueDirection(s.Heading), 0)
+	return performance.TakeoffParams{
+		PressureAltitude: s.PressureAltitude,
+		Temperature:      s.Temperature,
+		Weight:           s.Weight,
+		WindComponent:    math.Round(components.Headwind*10) / 10,
+	}
+}
+
+// Update is what the bridge publishes: the takeoff performance for the
+// latest state, or why there is none
+type Update struct {
+	Time            time.Time                    `json:"time"`
+	Received        time.Time                    `json:"received"` // When the state was read from the simulator
+	State           State                        `json:"state"`
+	Params          performance.TakeoffParams    `json:"params"`
+	DensityAltitude float64                      `json:"density_altitude"` // in feet
+	Result          *performance.TakeoffResult   `json:"result,omitempty"`
+	Errors          performance.ValidationErrors `json:"errors,omitempty"` // The inputs outside the chart, when there is no result
+	Error           string                       `json:"error,omitempty"`  // Why there is no result otherwise
+}
+
+// Compute works out the takeoff performance for a state
+func Compute(calculator *performance.TakeoffCalculator, s State, received, now time.Time) Update {
+	u := Update{
+		Time:            now,
+		Received:        received,
+		State:           s,
+		Params:          s.Params(),
+		DensityAltitude: atmosphere.DensityAltitude(s.PressureAltitude, s.Temperature),
+	}
+	if errs := calculator.Validate(u.Params); len(errs) > 0 {
+		u.Errors = errs
+		return u
+	}
+	result, err := calculator.CalculateTakeoff(u.Params)
+	if err != nil {
+		u.Error = err.Error()
+		return u
+	}
+	u.Result = result
+	return u
+}
@@ -0,0 +1,214 @@
+package sim
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/performance"
+)
+
+func TestParseState(t *testing.T) {
+	s, err := ParseState([]byte(`{"pressure_altitude": 1500, "temperature_c": 25, "weight": 2200, "heading": 170, "wind_direction": 200, "wind_speed": 10}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := s.Params()
+	// 10 kt from 30° right of the nose
+	if params.PressureAltitude != 1500 || params.Temperature != 25 || params.Weight != 2200 || params.WindComponent != 8.7 {
+		t.Errorf("Unexpected params %+v", params)
+	}
+
+	for _, data := range []string{`{"pressure_altitude": 1500, "temperature_c": 25}`, `not json`, `{"weight": "heavy"}`} {
+		if _, err := ParseState([]byte(data)); err == nil {
+			t.Errorf("Expected an error for %s", data)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	calculator := performance.NewTakeoffCalculator()
+	u := Compute(calculator, State{PressureAltitude: 1500, Temperature: 25, Weight: 2200}, time.Now(), time.Now())
+	if u.Result == nil || u.Errors != nil || u.DensityAltitude < 2900 {
+		t.Errorf("Expected a result, got %+v", u)
+	}
+	u = Compute(calculator, State{PressureAltitude: 9000, Temperature: 25, Weight: 2600}, time.Now(), time.Now())
+	if u.Result != nil || len(u.Errors) != 2 {
+		t.Errorf("Expected the altitude and weight outside the chart, got %+v", u)
+	}
+}
+
+// rrefPacket encodes values as X-Plane sends them, keyed by index
+func rrefPacket(values map[int32]float32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RREF,")
+	for index, value := range values {
+		binary.Write(&buf, binary.LittleEndian, index)
+		binary.Write(&buf, binary.LittleEndian, value)
+	}
+	return buf.Bytes()
+}
+
+func TestXPlaneDecoder(t *testing.T) {
+	var d XPlaneDecoder
+	if _, ready, err := d.Decode(rrefPacket(map[int32]float32{0: 1500, 1: 25})); err != nil || ready {
+		t.Errorf("Expected no state before the weight, got %v, %v", ready, err)
+	}
+	s, ready, err := d.Decode(rrefPacket(map[int32]float32{2: 1000, 3: 170, 4: 170, 5: 12, 8: 1}))
+	if err != nil || !ready {
+		t.Fatalf("Expected a state, got %v, %v", ready, err)
+	}
+	if s.PressureAltitude != 1500 || math.Abs(s.Weight-2204.6) > 0.1 || s.WindSpeed != 12 || !s.OnGround || s.Params().WindComponent != 12 {
+		t.Errorf("Unexpected state %+v", s)
+	}
+
+	for _, packet := range [][]byte{[]byte("DATA\x00"), append(rrefPacket(nil), 1, 2, 3)} {
+		if _, _, err := d.Decode(packet); err == nil {
+			t.Errorf("Expected an error for %q", packet)
+		}
+	}
+}
+
+func TestSubscribeXPlane(t *testing.T) {
+	xplane, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer xplane.Close()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := SubscribeXPlane(conn, xplane.LocalAddr(), 5); err != nil {
+		t.Fatal(err)
+	}
+	xplane.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1024)
+	n, _, err := xplane.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := buf[:n]
+	if n != 413 || string(request[:5]) != "RREF\x00" || binary.LittleEndian.Uint32(request[5:]) != 5 ||
+		binary.LittleEndian.Uint32(request[9:]) != 0 || !bytes.HasPrefix(request[13:], []byte(Datarefs[0]+"\x00")) {
+		t.Errorf("Unexpected subscription request %q", request)
+	}
+}
+
+func TestBridge(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	target, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+
+	var invalid []error
+	b := &Bridge{
+		Calculator: performance.NewTakeoffCalculator(),
+		Decoder:    JSONDecoder{},
+		Interval:   10 * time.Millisecond,
+		Weight:     2100,
+		Targets:    []net.Addr{target.LocalAddr()},
+		OnInvalid:  func(err error) { invalid = append(invalid, err) },
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- b.Run(ctx, conn) }()
+
+	helper, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer helper.Close()
+	helper.Write([]byte("garbage"))
+	helper.Write([]byte(`{"pressure_altitude": 1500, "temperature_c": 25, "weight": 2325}`))
+
+	target.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, maxDatagram)
+	n, _, err := target.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u Update
+	if err := json.Unmarshal(buf[:n], &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Params.Weight != 2100 || u.Result == nil || u.Result.TakeoffDistance <= 0 {
+		t.Errorf("Expected a result at the overriding weight, got %+v", u)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected a clean stop, got %v", err)
+	}
+	if len(invalid) != 1 {
+		t.Errorf("Expected the garbage datagram reported, got %v", invalid)
+	}
+}
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	if resp, err := http.Get(srv.URL); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a plain GET to be refused, got %v, %v", resp, err)
+	}
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	// The handshake example of RFC 6455
+	conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"))
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected handshake response %v", resp)
+	}
+
+	for hub.Clients() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	hub.Broadcast([]byte(`{"time":"now"}`))
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, header[1])
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	if header[0] != 0x81 || string(payload) != `{"time":"now"}` {
+		t.Errorf("Unexpected frame %x %q", header, payload)
+	}
+
+	// A masked close frame from the client ends the connection
+	conn.Write([]byte{0x88, 0x80, 1, 2, 3, 4})
+	for hub.Clients() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
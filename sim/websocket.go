@@ -0,0 +1,230 @@
+package sim
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is appended to the client's key for the handshake (RFC 6455)
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// maxClientFrame is the largest frame read from a client, which only
+// sends control frames
+const maxClientFrame = 1 << 16
+
+// clientQueue is how many updates wait for a slow client before the
+// newest are dropped
+const clientQueue = 4
+
+// Hub serves the published updates to WebSocket clients, each as a text
+// message. Clients only listen; a client too slow to keep up misses
+// updates rather than holding up the others.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[chan frame]struct{}
+}
+
+// frame is a WebSocket frame to send
+type frame struct {
+	opcode  byte
+	payload []byte
+}
+
+// NewHub creates a hub without clients
+func NewHub() *Hub {
+	return &Hub{clients: make(map[chan frame]struct{})}
+}
+
+// Broadcast sends a message to every client
+func (h *Hub) Broadcast(message []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		select {
+		case c <- frame{opText, message}:
+		default:
+		}
+	}
+}
+
+// Close sends every client a close frame, which ends its connection
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		select {
+		case c <- frame{opClose, nil}:
+		default:
+		}
+	}
+}
+
+// Clients returns the number of connected clients
+func (h *Hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// ServeHTTP upgrades the request to a WebSocket and sends it the updates
+// until the client closes it
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || !headerContains(r.Header, "Connection", "upgrade") ||
+		!headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "expected a WebSocket upgrade", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket unsupported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+		base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	frames := make(chan frame, clientQueue)
+	h.mu.Lock()
+	h.clients[frames] = struct{}{}
+	h.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		readControl(rw.Reader, frames)
+	}()
+	defer func() {
+		h.mu.Lock()
+		delete(h.clients, frames)
+		h.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		case f := <-frames:
+			if err := writeFrame(conn, f); err != nil || f.opcode == opClose {
+				return
+			}
+		}
+	}
+}
+
+// readControl reads the client's frames, answering pings, until the client
+// closes the connection or it fails
+func readControl(r *bufio.Reader, frames chan<- frame) {
+	for {
+		opcode, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case opPing:
+			select {
+			case frames <- frame{opPong, payload}:
+			default:
+			}
+		case opClose:
+			select {
+			case frames <- frame{opClose, nil}:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// readFrame reads a masked client frame
+func readFrame(r io.Reader) (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxClientFrame {
+		return 0, nil, fmt.Errorf("client frame of %d bytes", length)
+	}
+	var mask [4]byte
+	if header[1]&0x80 != 0 {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// writeFrame writes an unmasked, unfragmented server frame
+func writeFrame(w io.Writer, f frame) error {
+	header := []byte{0x80 | f.opcode}
+	switch n := len(f.payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, byte(n>>8), byte(n))
+	default:
+		header = append(header, 127)
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	_, err := w.Write(append(header, f.payload...))
+	return err
+}
+
+// headerContains reports whether a comma-separated header has a token,
+// ignoring case
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
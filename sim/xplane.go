@@ -0,0 +1,117 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// XPlanePort is the UDP port X-Plane receives dataref subscriptions on
+const XPlanePort = 49000
+
+// Datarefs are the X-Plane datarefs a State is read from, in the order of
+// their subscription indexes. The heading and wind are magnetic, as the
+// cockpit shows them; the weight is in kilograms.
+var Datarefs = []string{
+	"sim/flightmodel2/position/pressure_altitude",
+	"sim/weather/temperature_ambient_c",
+	"sim/flightmodel/weight/m_total",
+	"sim/flightmodel/position/mag_psi",
+	"sim/cockpit2/gauges/indicators/wind_heading_deg_mag",
+	"sim/cockpit2/gauges/indicators/wind_speed_kts",
+	"sim/flightmodel/position/latitude",
+	"sim/flightmodel/position/longitude",
+	"sim/flightmodel/failures/onground_any",
+}
+
+// rrefPathSize is the length of the dataref path in a subscription request
+const rrefPathSize = 400
+
+// rrefHeader starts the datagrams X-Plane sends with subscribed values;
+// the byte after it is a comma or a NUL, depending on the version
+var rrefHeader = []byte("RREF")
+
+// SubscribeXPlane asks X-Plane at addr to send the datarefs of a State
+// rate times a second to the address conn is bound to; a rate of 0 ends
+// the subscription
+func SubscribeXPlane(conn net.PacketConn, addr net.Addr, rate int) error {
+	for i, dataref := range Datarefs {
+		if _, err := conn.WriteTo(rrefRequest(int32(rate), int32(i), dataref), addr); err != nil {
+			return fmt.Errorf("subscribing to %s: %w", dataref, err)
+		}
+	}
+	return nil
+}
+
+// rrefRequest encodes a subscription request: "RREF", a NUL, the rate and
+// index as little-endian 32-bit integers and the NUL-padded dataref path
+func rrefRequest(rate, index int32, dataref string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("RREF\x00")
+	binary.Write(&buf, binary.LittleEndian, rate)
+	binary.Write(&buf, binary.LittleEndian, index)
+	path := make([]byte, rrefPathSize)
+	copy(path, dataref)
+	buf.Write(path)
+	return buf.Bytes()
+}
+
+// XPlaneDecoder builds up a State from X-Plane's RREF datagrams. Each
+// datagram carries some of the subscribed values; the State is ready once
+// the temperature, pressure altitude and weight have all arrived.
+type XPlaneDecoder struct {
+	state State
+	seen  [3]bool // Pressure altitude, temperature and weight
+}
+
+// Decode applies a datagram of subscribed values to the State, returning it
+// and whether it is ready
+func (d *XPlaneDecoder) Decode(packet []byte) (State, bool, error) {
+	if len(packet) < len(rrefHeader)+1 || !bytes.Equal(packet[:len(rrefHeader)], rrefHeader) {
+		return d.state, d.ready(), errors.New("not an X-Plane RREF datagram")
+	}
+	values := packet[len(rrefHeader)+1:]
+	if len(values)%8 != 0 {
+		return d.state, d.ready(), fmt.Errorf("RREF datagram of %d bytes is not whole values", len(packet))
+	}
+	for ; len(values) > 0; values = values[8:] {
+		index := int32(binary.LittleEndian.Uint32(values))
+		value := float64(math.Float32frombits(binary.LittleEndian.Uint32(values[4:])))
+		d.apply(index, value)
+	}
+	return d.state, d.ready(), nil
+}
+
+// apply sets the field of the dataref at an index
+func (d *XPlaneDecoder) apply(index int32, value float64) {
+	switch index {
+	case 0:
+		d.state.PressureAltitude, d.seen[0] = value, true
+	case 1:
+		d.state.Temperature, d.seen[1] = value, true
+	case 2:
+		d.state.Weight, d.seen[2] = units.KilogramsToPounds(value), true
+	case 3:
+		d.state.Heading = value
+	case 4:
+		d.state.WindDirection = value
+	case 5:
+		d.state.WindSpeed = value
+	case 6:
+		d.state.Latitude = value
+	case 7:
+		d.state.Longitude = value
+	case 8:
+		d.state.OnGround = value != 0
+	}
+}
+
+// ready reports whether every value the takeoff inputs need has arrived
+func (d *XPlaneDecoder) ready() bool {
+	return d.seen[0] && d.seen[1] && d.seen[2]
+}
@@ -0,0 +1,218 @@
+// Package termplot draws coarse line plots as text, in braille dots for
+// terminals with Unicode or plain ASCII for the rest, so results can be
+// seen where no image viewer is available
+package termplot
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Default plot size in characters
+const (
+	DefaultWidth  = 60
+	DefaultHeight = 12
+)
+
+// Series is a line through points; a NaN Y breaks the line
+type Series struct {
+	X, Y []float64
+}
+
+// Mark is a point highlighted on the plot, such as the current conditions
+type Mark struct {
+	X, Y float64
+}
+
+// Plot is a line plot of one or more series
+type Plot struct {
+	Width, Height int // Plot area in characters (defaults: DefaultWidth, DefaultHeight)
+	ASCII         bool
+	XUnit, YUnit  string // Appended to the axis labels, e.g. "°C", " ft"
+	Series        []Series
+	Marks         []Mark
+}
+
+// ASCII characters of each series in turn, and of marks
+var asciiSeries = []rune{'*', '+', 'x', '#'}
+
+const (
+	asciiMark   = 'O'
+	brailleMark = '●'
+)
+
+// brailleBits are the dots of a braille cell by column and row
+var brailleBits = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// Render draws the plot with the Y range labelled on the left and the X
+// range below, or returns "" when there is nothing to plot
+func (p *Plot) Render() string {
+	width, height := p.Width, p.Height
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+
+	xmin, xmax, ymin, ymax := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	extend := func(x, y float64) {
+		if math.IsNaN(x) || math.IsNaN(y) {
+			return
+		}
+		xmin, xmax = math.Min(xmin, x), math.Max(xmax, x)
+		ymin, ymax = math.Min(ymin, y), math.Max(ymax, y)
+	}
+	for _, s := range p.Series {
+		for i := range s.X {
+			extend(s.X[i], s.Y[i])
+		}
+	}
+	for _, m := range p.Marks {
+		extend(m.X, m.Y)
+	}
+	if math.IsInf(xmin, 1) {
+		return ""
+	}
+	if xmax == xmin {
+		xmin, xmax = xmin-1, xmax+1
+	}
+	if ymax == ymin {
+		ymin, ymax = ymin-1, ymax+1
+	}
+
+	// Each character is 2x4 dots in braille, one in ASCII
+	dx, dy := 2, 4
+	if p.ASCII {
+		dx, dy = 1, 1
+	}
+	pw, ph := width*dx, height*dy
+	px := func(x float64) int { return int(math.Round((x - xmin) / (xmax - xmin) * float64(pw-1))) }
+	py := func(y float64) int { return int(math.Round((ymax - y) / (ymax - ymin) * float64(ph-1))) }
+
+	cells := make([][]rune, height)
+	for i := range cells {
+		cells[i] = make([]rune, width)
+	}
+	dot := func(series, x, y int) {
+		row, col := cells[y/dy], x/dx
+		if p.ASCII {
+			row[col] = asciiSeries[series%len(asciiSeries)]
+			return
+		}
+		row[col] |= brailleBits[x%2][y%4]
+	}
+	for n, s := range p.Series {
+		for i := range s.X {
+			if math.IsNaN(s.Y[i]) {
+				continue
+			}
+			x, y := px(s.X[i]), py(s.Y[i])
+			if i == 0 || math.IsNaN(s.Y[i-1]) {
+				dot(n, x, y)
+				continue
+			}
+			line(px(s.X[i-1]), py(s.Y[i-1]), x, y, func(x, y int) { dot(n, x, y) })
+		}
+	}
+
+	mark := brailleMark
+	if p.ASCII {
+		mark = asciiMark
+	}
+	for _, m := range p.Marks {
+		if !math.IsNaN(m.X) && !math.IsNaN(m.Y) {
+			cells[py(m.Y)/dy][px(m.X)/dx] = mark
+		}
+	}
+
+	// Label the top, middle and bottom rows
+	labels := map[int]string{
+		0:          format(ymax) + p.YUnit,
+		height / 2: format(ymax-(ymax-ymin)*float64(height/2)/float64(height-1)) + p.YUnit,
+		height - 1: format(ymin) + p.YUnit,
+	}
+	margin := 0
+	for _, l := range labels {
+		if n := len([]rune(l)); n > margin {
+			margin = n
+		}
+	}
+
+	axis, tick, corner, rule, blank := "│", "┤", "└", "─", rune(0x2800)
+	if p.ASCII {
+		axis, tick, corner, rule, blank = "|", "+", "+", "-", ' '
+	}
+	var b strings.Builder
+	for i, row := range cells {
+		label, ok := labels[i]
+		b.WriteString(strings.Repeat(" ", margin-len([]rune(label))) + label + " ")
+		if ok {
+			b.WriteString(tick)
+		} else {
+			b.WriteString(axis)
+		}
+		for _, c := range row {
+			switch {
+			case c == 0:
+				c = blank
+			case !p.ASCII && c != brailleMark:
+				c += 0x2800
+			}
+			b.WriteRune(c)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat(" ", margin+1) + corner + strings.Repeat(rule, width) + "\n")
+	left, right := format(xmin)+p.XUnit, format(xmax)+p.XUnit
+	gap := width + 1 - len([]rune(left)) - len([]rune(right))
+	if gap < 1 {
+		gap = 1
+	}
+	b.WriteString(strings.Repeat(" ", margin+1) + left + strings.Repeat(" ", gap) + right + "\n")
+	return b.String()
+}
+
+// line calls set for each dot on the line between two dots
+func line(x0, y0, x1, y1 int, set func(x, y int)) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// format labels an axis value with no more decimals than it needs
+func format(v float64) string {
+	if math.Abs(v) >= 100 {
+		return strconv.FormatFloat(math.Round(v), 'f', 0, 64)
+	}
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package termplot
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRenderASCII(t *testing.T) {
+	p := Plot{
+		Width: 5, Height: 3, ASCII: true, XUnit: "°C", YUnit: " ft",
+		Series: []Series{{X: []float64{0, 10, 20, 30, 40}, Y: []float64{1000, 1100, math.NaN(), 1300, 1400}}},
+		Marks:  []Mark{{X: 10, Y: 1100}},
+	}
+	expected := "" +
+		"1400 ft +    *\n" +
+		"1200 ft +   * \n" +
+		"1000 ft +*O   \n" +
+		"        +-----\n" +
+		"        0°C 40°C\n"
+	if got := p.Render(); got != expected {
+		t.Errorf("Got\n%s\nexpected\n%s", got, expected)
+	}
+}
+
+func TestRenderBraille(t *testing.T) {
+	p := Plot{Width: 4, Height: 2, Series: []Series{{X: []float64{0, 1}, Y: []float64{0, 1}}}}
+	lines := strings.Split(strings.TrimSuffix(p.Render(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 2 rows and the axis, got %q", lines)
+	}
+
+	// The diagonal rises from the bottom left dot to the top right dot
+	top, bottom := []rune(lines[0]), []rune(lines[1])
+	if first := bottom[len(bottom)-4]; first&0x40 == 0 {
+		t.Errorf("Expected the bottom left dot set, got %q", first)
+	}
+	if last := top[len(top)-1]; last&0x08 == 0 {
+		t.Errorf("Expected the top right dot set, got %q", last)
+	}
+}
+
+func TestRenderEmpty(t *testing.T) {
+	p := Plot{Series: []Series{{X: []float64{0}, Y: []float64{math.NaN()}}}}
+	if got := p.Render(); got != "" {
+		t.Errorf("Expected nothing to plot, got %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Package termstyle highlights warnings and margins in terminal output
+// with ANSI escape codes. Briefings are read under stress and in bright
+// sun, so besides color there is a high-contrast mode that relies on bold
+// and reverse video rather than hue, and highlighting is left off when the
+// output is not a terminal or NO_COLOR is set. Width reports the terminal
+// width so commands can switch to a narrow layout on a phone.
+package termstyle
+
+import (
+	"os"
+)
+
+// Mode selects how text is highlighted
+type Mode int
+
+const (
+	// Plain output has no escape codes
+	Plain Mode = iota
+	// Color highlights with red, yellow and green
+	Color
+	// HighContrast highlights with bold, underline and reverse video only
+	HighContrast
+)
+
+// Styler highlights text in a mode
+type Styler struct {
+	Mode Mode
+}
+
+// Detect picks the mode for output to f: Plain when noColor is set, the
+// NO_COLOR environment variable is set, TERM is "dumb" or f is not a
+// terminal, otherwise HighContrast or Color
+func Detect(f *os.File, noColor, highContrast bool) Styler {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" || !IsTerminal(f) {
+		return Styler{Plain}
+	}
+	if highContrast {
+		return Styler{HighContrast}
+	}
+	return Styler{Color}
+}
+
+// IsTerminal reports whether f is a character device such as a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Escape codes by mode: color, then high contrast
+var (
+	warningCodes  = [...]string{"1;31", "1;7"}
+	cautionCodes  = [...]string{"33", "1;4"}
+	goodCodes     = [...]string{"32", "1"}
+	emphasisCodes = [...]string{"1", "1"}
+)
+
+// Warning highlights a limit that is exceeded
+func (s Styler) Warning(text string) string {
+	return s.apply(warningCodes, text)
+}
+
+// Caution highlights something that needs attention before flight
+func (s Styler) Caution(text string) string {
+	return s.apply(cautionCodes, text)
+}
+
+// Good highlights a margin that is met
+func (s Styler) Good(text string) string {
+	return s.apply(goodCodes, text)
+}
+
+// Emphasis highlights a key figure
+func (s Styler) Emphasis(text string) string {
+	return s.apply(emphasisCodes, text)
+}
+
+// apply wraps text in the escape code for the mode
+func (s Styler) apply(codes [2]string, text string) string {
+	if s.Mode == Plain || text == "" {
+		return text
+	}
+	return "\x1b[" + codes[s.Mode-Color] + "m" + text + "\x1b[0m"
+}
@@ -0,0 +1,38 @@
+package termstyle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStyles(t *testing.T) {
+	tests := []struct {
+		styler   Styler
+		got      func(Styler) string
+		expected string
+	}{
+		{Styler{Plain}, func(s Styler) string { return s.Warning("NO") }, "NO"},
+		{Styler{Color}, func(s Styler) string { return s.Warning("NO") }, "\x1b[1;31mNO\x1b[0m"},
+		{Styler{HighContrast}, func(s Styler) string { return s.Warning("NO") }, "\x1b[1;7mNO\x1b[0m"},
+		{Styler{Color}, func(s Styler) string { return s.Good("GO") }, "\x1b[32mGO\x1b[0m"},
+		{Styler{HighContrast}, func(s Styler) string { return s.Caution("CAUTION") }, "\x1b[1;4mCAUTION\x1b[0m"},
+		{Styler{Color}, func(s Styler) string { return s.Emphasis("") }, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.got(tt.styler); got != tt.expected {
+			t.Errorf("Mode %d: got %q, expected %q", tt.styler.Mode, got, tt.expected)
+		}
+	}
+}
+
+func TestDetectFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if s := Detect(f, false, true); s.Mode != Plain {
+		t.Errorf("Expected plain output to a file, got mode %d", s.Mode)
+	}
+}
@@ -0,0 +1,19 @@
+package termstyle
+
+import (
+	"os"
+	"strconv"
+)
+
+// Width returns the width of the terminal f writes to in columns, from
+// the COLUMNS environment variable or the terminal itself, or 0 when it
+// is not known (output to a file or pipe, or an unsupported platform)
+func Width(f *os.File) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if !IsTerminal(f) {
+		return 0
+	}
+	return terminalWidth(f)
+}
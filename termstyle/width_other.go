@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package termstyle
+
+import "os"
+
+// terminalWidth is not known on this platform
+func terminalWidth(f *os.File) int {
+	return 0
+}
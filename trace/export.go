@@ -0,0 +1,26 @@
+package trace
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONExporter writes one JSON object per finished span, for a log
+// shipper or collector that reads newline-delimited JSON
+type JSONExporter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONExporter creates an exporter writing to w
+func NewJSONExporter(w io.Writer) *JSONExporter {
+	return &JSONExporter{enc: json.NewEncoder(w)}
+}
+
+// Export implements Exporter
+func (e *JSONExporter) Export(span *SpanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.enc.Encode(span)
+}
@@ -0,0 +1,77 @@
+package trace
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// TraceparentHeader carries the trace context between processes
+const TraceparentHeader = "traceparent"
+
+// Inject sets the traceparent header for the span in ctx, so the next
+// service continues the trace
+func Inject(ctx context.Context, h http.Header) {
+	s := FromContext(ctx)
+	if s == nil {
+		return
+	}
+	h.Set(TraceparentHeader, "00-"+s.data.TraceID.String()+"-"+s.data.SpanID.String()+"-01")
+}
+
+// Extract returns a context whose next span continues the trace named in
+// a traceparent header. A missing or malformed header starts a new trace.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	parts := strings.Split(strings.TrimSpace(h.Get(TraceparentHeader)), "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return ctx
+	}
+	var r remote
+	if !decodeHex(r.trace[:], parts[1]) || !decodeHex(r.span[:], parts[2]) || !r.trace.IsValid() || !r.span.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey{}, r)
+}
+
+// decodeHex fills dst from exactly len(dst) bytes of hex digits
+func decodeHex(dst []byte, s string) bool {
+	if len(s) != 2*len(dst) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// Transport wraps an http.RoundTripper with a client span per request and
+// passes the trace on in the traceparent header
+type Transport struct {
+	Base http.RoundTripper // default: http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := Start(req.Context(), "HTTP "+req.Method+" "+req.URL.Host)
+	if span == nil {
+		return base.RoundTrip(req)
+	}
+	defer span.End()
+	span.SetAttribute("http.method", req.Method)
+	span.SetAttribute("http.url", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+
+	req = req.Clone(ctx)
+	Inject(ctx, req.Header)
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.status_code", strconv.Itoa(resp.StatusCode))
+	return resp, nil
+}
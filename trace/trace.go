@@ -0,0 +1,193 @@
+// Package trace records spans around server requests, weather fetches and
+// calculations so latency in a hosted deployment can be pinned on the
+// right integration. Spans follow the OpenTelemetry data model and are
+// propagated with W3C Trace Context (traceparent) headers, so they join
+// traces started by an instrumented load balancer or client and can be
+// forwarded to an OpenTelemetry collector by an Exporter.
+//
+// Until an exporter is set, Start returns a nil span and costs next to
+// nothing, so the calculators can be instrumented unconditionally.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// String returns the ID as 32 hex digits
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is not all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// MarshalText writes the ID as hex in JSON
+func (id TraceID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the ID as 16 hex digits
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is not all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// MarshalText writes the ID as hex in JSON
+func (id SpanID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// Status codes of a finished span
+const (
+	StatusUnset = "unset"
+	StatusError = "error"
+)
+
+// SpanData is a finished span as passed to an Exporter
+type SpanData struct {
+	TraceID    TraceID           `json:"trace_id"`
+	SpanID     SpanID            `json:"span_id"`
+	Parent     SpanID            `json:"parent_span_id"`
+	Name       string            `json:"name"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Status     string            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Exporter receives finished spans. Export is called from the goroutine
+// that ends the span and must not block for long.
+type Exporter interface {
+	Export(span *SpanData)
+}
+
+// exporter is the installed exporter, nil when tracing is off
+var exporter struct {
+	sync.RWMutex
+	e Exporter
+}
+
+// SetExporter installs the exporter for every span; nil turns tracing off
+func SetExporter(e Exporter) {
+	exporter.Lock()
+	defer exporter.Unlock()
+	exporter.e = e
+}
+
+// current returns the installed exporter
+func current() Exporter {
+	exporter.RLock()
+	defer exporter.RUnlock()
+	return exporter.e
+}
+
+// Span is an operation being timed. A nil span is valid and does nothing.
+type Span struct {
+	mu       sync.Mutex
+	data     SpanData
+	exporter Exporter
+	ended    bool
+}
+
+// spanKey is the context key of the current span
+type spanKey struct{}
+
+// remoteKey is the context key of a parent span from another process
+type remoteKey struct{}
+
+// remote is the trace context received in a traceparent header
+type remote struct {
+	trace TraceID
+	span  SpanID
+}
+
+// Start begins a span as a child of the span in ctx, or of a remote parent
+// extracted from a request, and returns a context carrying it
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	e := current()
+	if e == nil {
+		return ctx, nil
+	}
+
+	s := &Span{exporter: e, data: SpanData{Name: name, Start: time.Now(), Status: StatusUnset}}
+	if parent := FromContext(ctx); parent != nil {
+		s.data.TraceID, s.data.Parent = parent.data.TraceID, parent.data.SpanID
+	} else if r, ok := ctx.Value(remoteKey{}).(remote); ok {
+		s.data.TraceID, s.data.Parent = r.trace, r.span
+	} else {
+		rand.Read(s.data.TraceID[:])
+	}
+	rand.Read(s.data.SpanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// FromContext returns the span in ctx, or nil
+func FromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SetAttribute records a key and value on the span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Attributes == nil {
+		s.data.Attributes = make(map[string]string)
+	}
+	s.data.Attributes[key] = value
+}
+
+// RecordError marks the span as failed; a nil error is ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Status, s.data.Error = StatusError, err.Error()
+}
+
+// End finishes the span and exports it. Later calls do nothing.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+	s.exporter.Export(&data)
+}
+
+// TraceID returns the ID of the span's trace, zero for a nil span
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.data.TraceID
+}
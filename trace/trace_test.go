@@ -0,0 +1,83 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// collector keeps exported spans
+type collector struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+func (c *collector) Export(span *SpanData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spans = append(c.spans, span)
+}
+
+func TestSpans(t *testing.T) {
+	if _, span := Start(context.Background(), "off"); span != nil {
+		t.Fatal("Expected no span without an exporter")
+	}
+
+	c := &collector{}
+	SetExporter(c)
+	defer SetExporter(nil)
+
+	// Continue a trace started by the caller
+	incoming := http.Header{}
+	incoming.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx, request := Start(Extract(context.Background(), incoming), "request")
+
+	var outgoing http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outgoing = r.Header.Clone()
+	}))
+	defer upstream.Close()
+
+	fetchCtx, fetch := Start(ctx, "fetch")
+	fetch.SetAttribute("station", "KJYO")
+	client := &http.Client{Transport: &Transport{}}
+	req, _ := http.NewRequestWithContext(fetchCtx, http.MethodGet, upstream.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	fetch.RecordError(errors.New("no METAR available"))
+	fetch.End()
+	request.End()
+	request.End()
+
+	if len(c.spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(c.spans))
+	}
+	httpSpan, fetchSpan, requestSpan := c.spans[0], c.spans[1], c.spans[2]
+	for _, s := range c.spans {
+		if s.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Span %s is in trace %s", s.Name, s.TraceID)
+		}
+	}
+	if requestSpan.Parent.String() != "00f067aa0ba902b7" || fetchSpan.Parent != requestSpan.SpanID || httpSpan.Parent != fetchSpan.SpanID {
+		t.Errorf("Unexpected parents: request %s, fetch %s, http %s", requestSpan.Parent, fetchSpan.Parent, httpSpan.Parent)
+	}
+	if fetchSpan.Status != StatusError || fetchSpan.Attributes["station"] != "KJYO" || httpSpan.Attributes["http.status_code"] != "200" {
+		t.Errorf("Unexpected spans %+v %+v", *fetchSpan, *httpSpan)
+	}
+	if want := "00-" + httpSpan.TraceID.String() + "-" + httpSpan.SpanID.String() + "-01"; outgoing.Get(TraceparentHeader) != want {
+		t.Errorf("Sent traceparent %q, expected %q", outgoing.Get(TraceparentHeader), want)
+	}
+
+	// A malformed header starts a new trace
+	incoming.Set(TraceparentHeader, "00-zz-00f067aa0ba902b7-01")
+	_, fresh := Start(Extract(context.Background(), incoming), "request")
+	if !fresh.TraceID().IsValid() || fresh.data.Parent.IsValid() {
+		t.Errorf("Expected a new root span, got %+v", fresh.data)
+	}
+}
@@ -0,0 +1,141 @@
+// Package units converts between the units used in flight planning. Every
+// tool in the module converts through here so the factors live in one place.
+package units
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Conversion factors
+const (
+	MetersPerFoot          = 0.3048
+	MetersPerNauticalMile  = 1852.0
+	MetersPerStatuteMile   = 1609.344
+	LitersPerGallon        = 3.785411784 // US gallon
+	KilogramsPerPound      = 0.45359237
+	HectopascalsPerInchHg  = 33.8638866667
+	KilometersPerNautical  = MetersPerNauticalMile / 1000
+	StatutePerNauticalMile = MetersPerNauticalMile / MetersPerStatuteMile
+)
+
+// FeetToMeters converts feet to meters
+func FeetToMeters(feet float64) float64 {
+	return feet * MetersPerFoot
+}
+
+// MetersToFeet converts meters to feet
+func MetersToFeet(meters float64) float64 {
+	return meters / MetersPerFoot
+}
+
+// NauticalToStatute converts nautical miles (or knots) to statute miles (or mph)
+func NauticalToStatute(nm float64) float64 {
+	return nm * StatutePerNauticalMile
+}
+
+// StatuteToNautical converts statute miles (or mph) to nautical miles (or knots)
+func StatuteToNautical(sm float64) float64 {
+	return sm / StatutePerNauticalMile
+}
+
+// NauticalToKilometers converts nautical miles (or knots) to kilometers (or km/h)
+func NauticalToKilometers(nm float64) float64 {
+	return nm * KilometersPerNautical
+}
+
+// KilometersToNautical converts kilometers (or km/h) to nautical miles (or knots)
+func KilometersToNautical(km float64) float64 {
+	return km / KilometersPerNautical
+}
+
+// CelsiusToFahrenheit converts °C to °F
+func CelsiusToFahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+// FahrenheitToCelsius converts °F to °C
+func FahrenheitToCelsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+// KelvinToCelsius converts kelvin, as used in weather model output, to °C
+func KelvinToCelsius(kelvin float64) float64 {
+	return kelvin - 273.15
+}
+
+// MetersPerSecondToKnots converts a speed in m/s to knots
+func MetersPerSecondToKnots(mps float64) float64 {
+	return mps * 3600 / MetersPerNauticalMile
+}
+
+// GallonsToLiters converts US gallons to liters
+func GallonsToLiters(gallons float64) float64 {
+	return gallons * LitersPerGallon
+}
+
+// LitersToGallons converts liters to US gallons
+func LitersToGallons(liters float64) float64 {
+	return liters / LitersPerGallon
+}
+
+// PoundsToKilograms converts pounds to kilograms
+func PoundsToKilograms(pounds float64) float64 {
+	return pounds * KilogramsPerPound
+}
+
+// KilogramsToPounds converts kilograms to pounds
+func KilogramsToPounds(kilograms float64) float64 {
+	return kilograms / KilogramsPerPound
+}
+
+// weightUnits are the suffixes ParseWeight accepts, with their size in pounds
+var weightUnits = []struct {
+	suffix string
+	pounds float64
+}{
+	{"kg", 1 / KilogramsPerPound},
+	{"lbs", 1},
+	{"lb", 1},
+}
+
+// ParseWeight parses a weight with an optional unit suffix, "kg", "lb" or
+// "lbs", such as "77kg", and returns it in pounds. A bare number is pounds.
+func ParseWeight(s string) (float64, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	factor := 1.0
+	for _, u := range weightUnits {
+		if strings.HasSuffix(text, u.suffix) {
+			text, factor = strings.TrimSpace(strings.TrimSuffix(text, u.suffix)), u.pounds
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid weight %q, expected pounds or a number with kg or lbs", s)
+	}
+	return v * factor, nil
+}
+
+// FuelWeight returns the weight in pounds of a fuel volume in US gallons
+// at a density in pounds per gallon
+func FuelWeight(gallons, density float64) float64 {
+	return gallons * density
+}
+
+// FuelVolume returns the volume in US gallons of a fuel weight in pounds
+// at a density in pounds per gallon
+func FuelVolume(pounds, density float64) float64 {
+	return pounds / density
+}
+
+// InchesHgToHectopascals converts an altimeter setting in inches of mercury to hPa
+func InchesHgToHectopascals(inHg float64) float64 {
+	return inHg * HectopascalsPerInchHg
+}
+
+// HectopascalsToInchesHg converts an altimeter setting in hPa to inches of mercury
+func HectopascalsToInchesHg(hPa float64) float64 {
+	return hPa / HectopascalsPerInchHg
+}
@@ -0,0 +1,78 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"1000 ft in m", FeetToMeters(1000), 304.8},
+		{"100 m in ft", MetersToFeet(100), 328.084},
+		{"100 nm in sm", NauticalToStatute(100), 115.078},
+		{"100 sm in nm", StatuteToNautical(100), 86.898},
+		{"100 nm in km", NauticalToKilometers(100), 185.2},
+		{"100 km in nm", KilometersToNautical(100), 53.996},
+		{"-40°C in °F", CelsiusToFahrenheit(-40), -40},
+		{"212°F in °C", FahrenheitToCelsius(212), 100},
+		{"288.15 K in °C", KelvinToCelsius(288.15), 15},
+		{"10 m/s in kt", MetersPerSecondToKnots(10), 19.438},
+		{"48 gal in l", GallonsToLiters(48), 181.700},
+		{"100 l in gal", LitersToGallons(100), 26.417},
+		{"2325 lbs in kg", PoundsToKilograms(2325), 1054.603},
+		{"1000 kg in lbs", KilogramsToPounds(1000), 2204.623},
+		{"48 gal of avgas in lbs", FuelWeight(48, 6), 288},
+		{"60 lbs of avgas in gal", FuelVolume(60, 6), 10},
+		{"29.92 inHg in hPa", InchesHgToHectopascals(29.92), 1013.21},
+		{"1013.25 hPa in inHg", HectopascalsToInchesHg(1013.25), 29.921},
+	}
+
+	for _, tc := range tests {
+		if math.Abs(tc.got-tc.expected) > 0.001*math.Max(1, math.Abs(tc.expected)) {
+			t.Errorf("%s: expected %.3f, got %.3f", tc.name, tc.expected, tc.got)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, v := range []float64{-40, 0, 1, 123.456} {
+		if got := FahrenheitToCelsius(CelsiusToFahrenheit(v)); math.Abs(got-v) > 1e-9 {
+			t.Errorf("Temperature round trip of %.3f: got %.3f", v, got)
+		}
+		if got := StatuteToNautical(NauticalToStatute(v)); math.Abs(got-v) > 1e-9 {
+			t.Errorf("Distance round trip of %.3f: got %.3f", v, got)
+		}
+	}
+}
+
+func TestParseWeight(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected float64
+	}{
+		{"170", 170},
+		{"170lbs", 170},
+		{"170 lb", 170},
+		{"77kg", 169.756},
+		{" 72 KG ", 158.733},
+		{"0", 0},
+	}
+	for _, tc := range tests {
+		got, err := ParseWeight(tc.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tc.in, err)
+		} else if math.Abs(got-tc.expected) > 0.001 {
+			t.Errorf("%q: expected %.3f lbs, got %.3f", tc.in, tc.expected, got)
+		}
+	}
+
+	for _, in := range []string{"", "kg", "77 stone", "heavy"} {
+		if _, err := ParseWeight(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
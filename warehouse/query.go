@@ -0,0 +1,271 @@
+package warehouse
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numbers are the numeric fields of a result, by name; ok is false when
+// the result does not have the value
+var numbers = map[string]func(Result) (value float64, ok bool){
+	"pressure_altitude": func(r Result) (float64, bool) { return r.PressureAltitude, true },
+	"density_altitude":  func(r Result) (float64, bool) { return r.DensityAltitude, true },
+	"temperature_c":     func(r Result) (float64, bool) { return r.Temperature, true },
+	"weight":            func(r Result) (float64, bool) { return r.Weight, true },
+	"headwind":          func(r Result) (float64, bool) { return r.Headwind, true },
+	"distance":          func(r Result) (float64, bool) { return r.Distance, true },
+	"available":         func(r Result) (float64, bool) { return r.Available, r.Available > 0 },
+	"margin":            func(r Result) (float64, bool) { return r.Available - r.Distance, r.Available > 0 },
+}
+
+// keys are the fields results can be grouped and matched by; times are
+// taken in UTC
+var keys = map[string]func(Result) string{
+	"year":     func(r Result) string { return r.Time.UTC().Format("2006") },
+	"month":    func(r Result) string { return r.Time.UTC().Format("2006-01") },
+	"day":      func(r Result) string { return r.Time.UTC().Format("2006-01-02") },
+	"aircraft": func(r Result) string { return r.Aircraft },
+	"airport":  func(r Result) string { return r.Airport },
+	"runway":   func(r Result) string { return r.Runway },
+	"verdict":  func(r Result) string { return r.Verdict },
+}
+
+// Fields lists the numeric fields and the keys, for usage messages
+func Fields() (numeric, key []string) {
+	for name := range numbers {
+		numeric = append(numeric, name)
+	}
+	for name := range keys {
+		key = append(key, name)
+	}
+	sort.Strings(numeric)
+	sort.Strings(key)
+	return numeric, key
+}
+
+// Condition matches results by a field, e.g. "verdict=NO-GO" or
+// "distance>2000". Keys are compared without regard to case with = and
+// != only.
+type Condition struct {
+	Field string
+	Op    string // =, !=, <, <=, > or >=
+	Value string
+}
+
+// operators are tried longest first, so "<=" is not read as "<"
+var operators = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// ParseCondition reads a condition such as "distance>=2000"
+func ParseCondition(s string) (Condition, error) {
+	i := strings.IndexAny(s, "!=<>")
+	if i <= 0 {
+		return Condition{}, fmt.Errorf("invalid condition %q (expected e.g. verdict=NO-GO or distance>2000)", s)
+	}
+	c := Condition{Field: strings.ToLower(strings.TrimSpace(s[:i]))}
+	for _, op := range operators {
+		if strings.HasPrefix(s[i:], op) {
+			c.Op, c.Value = op, strings.TrimSpace(s[i+len(op):])
+			break
+		}
+	}
+	if c.Op == "" || c.Value == "" {
+		return Condition{}, fmt.Errorf("invalid condition %q (expected e.g. verdict=NO-GO or distance>2000)", s)
+	}
+	if _, ok := numbers[c.Field]; ok {
+		if _, err := strconv.ParseFloat(c.Value, 64); err != nil {
+			return Condition{}, fmt.Errorf("condition %q: %s is not a number", s, c.Value)
+		}
+		return c, nil
+	}
+	if _, ok := keys[c.Field]; !ok {
+		return Condition{}, fmt.Errorf("condition %q: unknown field %s", s, c.Field)
+	}
+	if c.Op != "=" && c.Op != "!=" {
+		return Condition{}, fmt.Errorf("condition %q: %s can only be compared with = or !=", s, c.Field)
+	}
+	return c, nil
+}
+
+// matches reports whether a result meets the condition; a result without
+// a numeric value never does
+func (c Condition) matches(r Result) bool {
+	if key, ok := keys[c.Field]; ok {
+		return strings.EqualFold(key(r), c.Value) == (c.Op == "=")
+	}
+	v, ok := numbers[c.Field](r)
+	if !ok {
+		return false
+	}
+	want, _ := strconv.ParseFloat(c.Value, 64)
+	switch c.Op {
+	case "=":
+		return v == want
+	case "!=":
+		return v != want
+	case "<":
+		return v < want
+	case "<=":
+		return v <= want
+	case ">":
+		return v > want
+	default:
+		return v >= want
+	}
+}
+
+// Aggregate is a count of results, or the average, minimum, maximum or
+// sum of a numeric field over them
+type Aggregate struct {
+	Func  string // count, avg, min, max or sum
+	Field string // Empty for count
+}
+
+// String returns the aggregate as written, e.g. "avg(distance)"
+func (a Aggregate) String() string {
+	if a.Field == "" {
+		return a.Func
+	}
+	return a.Func + "(" + a.Field + ")"
+}
+
+// ParseAggregates reads a comma-separated list such as
+// "count,avg(distance)"
+func ParseAggregates(s string) ([]Aggregate, error) {
+	var aggregates []Aggregate
+	for _, item := range strings.Split(s, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "count" {
+			aggregates = append(aggregates, Aggregate{Func: "count"})
+			continue
+		}
+		fn, rest, ok := strings.Cut(item, "(")
+		if !ok || !strings.HasSuffix(rest, ")") {
+			return nil, fmt.Errorf("invalid aggregate %q (expected count or e.g. avg(distance))", item)
+		}
+		a := Aggregate{Func: fn, Field: strings.TrimSpace(strings.TrimSuffix(rest, ")"))}
+		switch a.Func {
+		case "avg", "min", "max", "sum":
+		default:
+			return nil, fmt.Errorf("aggregate %q: unknown function %s (expected count, avg, min, max or sum)", item, a.Func)
+		}
+		if _, ok := numbers[a.Field]; !ok {
+			return nil, fmt.Errorf("aggregate %q: %s is not a numeric field", item, a.Field)
+		}
+		aggregates = append(aggregates, a)
+	}
+	return aggregates, nil
+}
+
+// Query selects results and aggregates them by group
+type Query struct {
+	Where      []Condition
+	Since      time.Time   // Results from this time on (zero: from the first)
+	Until      time.Time   // Results before this time (zero: to the last)
+	GroupBy    []string    // Keys, e.g. month
+	Aggregates []Aggregate // Default: count
+}
+
+// Row is the aggregates of one group, in the order of the query.
+// Aggregates of a field no result in the group has are NaN.
+type Row struct {
+	Keys   []string
+	Values []float64
+}
+
+// Run answers the query over results, one row per group in key order
+func (q *Query) Run(results []Result) ([]Row, error) {
+	for _, name := range q.GroupBy {
+		if _, ok := keys[name]; !ok {
+			return nil, fmt.Errorf("cannot group by %s", name)
+		}
+	}
+	aggregates := q.Aggregates
+	if len(aggregates) == 0 {
+		aggregates = []Aggregate{{Func: "count"}}
+	}
+
+	type group struct {
+		keys    []string
+		results []Result
+	}
+	groups := make(map[string]*group)
+	for _, r := range results {
+		if !q.selects(r) {
+			continue
+		}
+		g := &group{}
+		for _, name := range q.GroupBy {
+			g.keys = append(g.keys, keys[name](r))
+		}
+		id := strings.Join(g.keys, "\x00")
+		if existing, ok := groups[id]; ok {
+			g = existing
+		} else {
+			groups[id] = g
+		}
+		g.results = append(g.results, r)
+	}
+
+	rows := make([]Row, 0, len(groups))
+	for _, g := range groups {
+		row := Row{Keys: g.keys}
+		for _, a := range aggregates {
+			row.Values = append(row.Values, a.over(g.results))
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return strings.Join(rows[i].Keys, "\x00") < strings.Join(rows[j].Keys, "\x00")
+	})
+	return rows, nil
+}
+
+// selects reports whether a result is in the query's time range and meets
+// every condition
+func (q *Query) selects(r Result) bool {
+	if (!q.Since.IsZero() && r.Time.Before(q.Since)) || (!q.Until.IsZero() && !r.Time.Before(q.Until)) {
+		return false
+	}
+	for _, c := range q.Where {
+		if !c.matches(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// over computes the aggregate over a group's results
+func (a Aggregate) over(results []Result) float64 {
+	if a.Func == "count" {
+		return float64(len(results))
+	}
+	var n int
+	var sum float64
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, r := range results {
+		v, ok := numbers[a.Field](r)
+		if !ok {
+			continue
+		}
+		n++
+		sum += v
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	switch a.Func {
+	case "avg":
+		return sum / float64(n)
+	case "min":
+		return lo
+	case "max":
+		return hi
+	default:
+		return sum
+	}
+}
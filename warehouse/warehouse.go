@@ -0,0 +1,109 @@
+// Package warehouse keeps the results of takeoff calculations for safety
+// program reporting, such as the average takeoff distance by month or the
+// number of NO-GO verdicts, and answers simple queries over them. Results
+// are kept as JSON lines in a single file, so the warehouse needs no
+// database server and can be copied, archived or loaded into one.
+//
+// JSON lines stand in for the SQLite database first asked for: the module
+// depends on the standard library alone and builds without cgo, and a
+// SQLite driver would be either a cgo package or its first dependency. The
+// sqlite3 shell can load the file as it is; see the README.
+package warehouse
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Verdicts of a result
+const (
+	Go      = "GO"
+	Caution = "CAUTION"
+	NoGo    = "NO-GO"
+)
+
+// Result is one takeoff calculation
+type Result struct {
+	Time     time.Time `json:"time"`
+	Aircraft string    `json:"aircraft"`          // Profile ID
+	Airport  string    `json:"airport,omitempty"` // Departure airport, when given
+	Runway   string    `json:"runway,omitempty"`
+
+	PressureAltitude float64 `json:"pressure_altitude"` // in feet
+	DensityAltitude  float64 `json:"density_altitude"`  // in feet
+	Temperature      float64 `json:"temperature_c"`
+	Weight           float64 `json:"weight"`   // in pounds
+	Headwind         float64 `json:"headwind"` // in knots, negative for a tailwind
+	Distance         float64 `json:"distance"` // Takeoff distance over a 50 ft obstacle, in feet
+	Available        float64 `json:"available,omitempty"`
+
+	Verdict  string   `json:"verdict"`            // GO, CAUTION or NO-GO
+	Findings []string `json:"findings,omitempty"` // The warnings and cautions behind the verdict
+}
+
+// Warehouse is a file of results, oldest first. Results are only ever
+// appended.
+type Warehouse struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates a warehouse in a file, created on the first result
+func New(path string) *Warehouse {
+	return &Warehouse{path: path}
+}
+
+// Add appends a result
+func (w *Warehouse) Add(r Result) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Results returns every result; a warehouse not yet created holds none
+func (w *Warehouse) Results() ([]Result, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.Open(w.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var results []Result
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var r Result
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", w.path, line, err)
+		}
+		results = append(results, r)
+	}
+	return results, scanner.Err()
+}
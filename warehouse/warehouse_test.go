@@ -0,0 +1,118 @@
+package warehouse
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testResults span two months, one of them with a NO-GO
+var testResults = []Result{
+	{Time: time.Date(2026, 6, 3, 14, 0, 0, 0, time.UTC), Aircraft: "pa28-161", Airport: "KJYO", Runway: "35", Distance: 1600, Available: 5500, Verdict: Go},
+	{Time: time.Date(2026, 6, 20, 14, 0, 0, 0, time.UTC), Aircraft: "pa28-161", Distance: 2000, Verdict: Caution},
+	{Time: time.Date(2026, 7, 9, 14, 0, 0, 0, time.UTC), Aircraft: "pa28-181", Airport: "W00", Runway: "14", Distance: 2500, Available: 2100, Verdict: NoGo},
+	{Time: time.Date(2026, 7, 30, 14, 0, 0, 0, time.UTC), Aircraft: "pa28-161", Airport: "KJYO", Runway: "17", Distance: 1900, Available: 5500, Verdict: Go},
+}
+
+func TestWarehouse(t *testing.T) {
+	w := New(filepath.Join(t.TempDir(), "results.jsonl"))
+	if results, err := w.Results(); err != nil || len(results) != 0 {
+		t.Errorf("Expected an empty warehouse before the first result, got %v, %v", results, err)
+	}
+	for _, r := range testResults {
+		if err := w.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := w.Results()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results, testResults) {
+		t.Errorf("Got %+v, expected %+v", results, testResults)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	parse := func(conditions ...string) []Condition {
+		var where []Condition
+		for _, s := range conditions {
+			c, err := ParseCondition(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			where = append(where, c)
+		}
+		return where
+	}
+	aggregates := func(s string) []Aggregate {
+		a, err := ParseAggregates(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	testCases := []struct {
+		name  string
+		query Query
+		rows  []Row
+	}{
+		{"Count", Query{}, []Row{{Values: []float64{4}}}},
+		{"Average By Month", Query{GroupBy: []string{"month"}, Aggregates: aggregates("count,avg(distance)")}, []Row{
+			{Keys: []string{"2026-06"}, Values: []float64{2, 1800}},
+			{Keys: []string{"2026-07"}, Values: []float64{2, 2200}},
+		}},
+		{"NO-GO Verdicts", Query{Where: parse("verdict=no-go")}, []Row{{Values: []float64{1}}}},
+		{"Margins", Query{GroupBy: []string{"aircraft"}, Aggregates: aggregates("min(margin),max(distance)")}, []Row{
+			{Keys: []string{"pa28-161"}, Values: []float64{3600, 2000}},
+			{Keys: []string{"pa28-181"}, Values: []float64{-400, 2500}},
+		}},
+		{"Conditions", Query{Where: parse("distance>=1900", "airport!=W00")}, []Row{{Values: []float64{2}}}},
+		{"Time Range", Query{Since: time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC), Until: time.Date(2026, 7, 30, 14, 0, 0, 0, time.UTC)}, []Row{{Values: []float64{2}}}},
+		{"No Match", Query{Where: parse("weight<0")}, []Row{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := tc.query.Run(testResults)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, tc.rows) {
+				t.Errorf("Got %+v, expected %+v", rows, tc.rows)
+			}
+		})
+	}
+
+	q := Query{Where: parse("airport=XYZ"), Aggregates: aggregates("count,avg(margin)")}
+	rows, _ := q.Run(testResults)
+	if len(rows) != 0 {
+		t.Errorf("Expected no rows, got %+v", rows)
+	}
+	q = Query{Where: parse("runway=35"), Aggregates: aggregates("avg(available)")}
+	if rows, _ := q.Run(testResults[:2]); len(rows) != 1 || rows[0].Values[0] != 5500 {
+		t.Errorf("Got %+v", rows)
+	}
+	q = Query{Where: parse("verdict=CAUTION"), Aggregates: aggregates("avg(margin)")}
+	if rows, _ := q.Run(testResults); len(rows) != 1 || !math.IsNaN(rows[0].Values[0]) {
+		t.Errorf("Expected NaN for a field no result has, got %+v", rows)
+	}
+	if _, err := (&Query{GroupBy: []string{"weight"}}).Run(testResults); err == nil {
+		t.Errorf("Expected an error grouping by a numeric field")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{"verdict", "=GO", "distance>", "distance>far", "colour=red", "verdict>GO"} {
+		if _, err := ParseCondition(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+	for _, s := range []string{"median(distance)", "avg(verdict)", "avg(distance", "total"} {
+		if _, err := ParseAggregates(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package wb
+
+import (
+	"fmt"
+	"math"
+)
+
+// Certification categories with CG envelopes
+const (
+	CategoryNormal  = "normal"
+	CategoryUtility = "utility" // Limited maneuvers such as spins
+)
+
+// EnvelopePoint is a corner of a CG envelope
+type EnvelopePoint struct {
+	Weight float64 // in pounds
+	Arm    float64 // CG in inches aft of the datum
+}
+
+// Envelope is the CG envelope of one certification category, a polygon of
+// weight against CG with any loading restrictions the category adds
+type Envelope struct {
+	Category  string
+	Points    []EnvelopePoint // Corners in order around the envelope
+	Seats     int             // Seats that may be occupied, from the front; 0 for all
+	NoBaggage bool            // The baggage compartment must be empty
+}
+
+// MaxWeight returns the heaviest weight in the envelope
+func (e Envelope) MaxWeight() float64 {
+	max := 0.0
+	for _, p := range e.Points {
+		max = math.Max(max, p.Weight)
+	}
+	return max
+}
+
+// Contains reports whether a weight and CG are inside the envelope or on
+// its edge
+func (e Envelope) Contains(weight, arm float64) bool {
+	const epsilon = 1e-9
+	inside := false
+	for i := range e.Points {
+		a, b := e.Points[i], e.Points[(i+1)%len(e.Points)]
+
+		// On the edge counts as inside
+		cross := (b.Arm-a.Arm)*(weight-a.Weight) - (b.Weight-a.Weight)*(arm-a.Arm)
+		if math.Abs(cross) < epsilon &&
+			arm >= math.Min(a.Arm, b.Arm)-epsilon && arm <= math.Max(a.Arm, b.Arm)+epsilon &&
+			weight >= math.Min(a.Weight, b.Weight)-epsilon && weight <= math.Max(a.Weight, b.Weight)+epsilon {
+			return true
+		}
+
+		// Cast a ray toward increasing arm and count the edges it crosses
+		if (a.Weight > weight) != (b.Weight > weight) {
+			crossing := a.Arm + (weight-a.Weight)*(b.Arm-a.Arm)/(b.Weight-a.Weight)
+			if arm < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Qualification is whether a loading meets one envelope's category
+type Qualification struct {
+	Category  string `json:"category"`
+	Qualified bool   `json:"qualified"`
+	Reason    string `json:"reason,omitempty"` // Why not, when not qualified
+}
+
+// qualify checks a loading's takeoff weight and CG against the envelope
+// and its loading restrictions
+func (e Envelope) qualify(l Loading, weight, arm float64) Qualification {
+	q := Qualification{Category: e.Category}
+	switch {
+	case weight > e.MaxWeight():
+		q.Reason = fmt.Sprintf("weight %.0f lbs is over %.0f lbs", weight, e.MaxWeight())
+	case !e.Contains(weight, arm):
+		q.Reason = fmt.Sprintf("CG %.1f in is outside the envelope at %.0f lbs", arm, weight)
+	case e.NoBaggage && l.Baggage > 0:
+		q.Reason = "baggage must be empty"
+	case e.Seats > 0 && occupiedBeyond(l.People, e.Seats):
+		q.Reason = fmt.Sprintf("only the first %d seats may be occupied", e.Seats)
+	default:
+		q.Qualified = true
+	}
+	return q
+}
+
+// occupiedBeyond reports whether anyone sits past the first n seats; a
+// weight of zero is an empty seat
+func occupiedBeyond(people []float64, n int) bool {
+	for i := n; i < len(people); i++ {
+		if people[i] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// balance returns the takeoff weight and CG of a loading, with the taxi
+// fuel burned off at the fuel arm. The CG is zero when the aircraft has no
+// arms.
+func (a Aircraft) balance(l Loading) (weight, arm float64) {
+	fuel := (l.FuelGallons - a.TaxiFuel) * a.FuelDensity
+	weight = a.EmptyWeight + fuel + l.Baggage
+	moment := a.EmptyWeight*a.EmptyArm + fuel*a.FuelArm + l.Baggage*a.BaggageArm
+	for i, person := range l.People {
+		weight += person
+		if i < len(a.SeatArms) {
+			moment += person * a.SeatArms[i]
+		}
+	}
+	if a.EmptyArm == 0 || weight <= 0 {
+		return weight, 0
+	}
+	return weight, moment / weight
+}
+
+// Qualify checks a loading against each of the aircraft's envelopes in
+// turn, reporting which categories it may be flown in
+func (a Aircraft) Qualify(l Loading) []Qualification {
+	if a.EmptyArm == 0 {
+		return nil
+	}
+	weight, arm := a.balance(l)
+	qualifications := make([]Qualification, len(a.Envelopes))
+	for i, e := range a.Envelopes {
+		qualifications[i] = e.qualify(l, weight, arm)
+	}
+	return qualifications
+}
@@ -0,0 +1,115 @@
+package wb
+
+import (
+	"math"
+	"testing"
+)
+
+var testEnvelopes = Aircraft{
+	EmptyWeight:   1500,
+	FuelDensity:   AvgasDensity,
+	FuelCapacity:  48,
+	Seats:         4,
+	MaxBaggage:    200,
+	MaxRampWeight: 2332,
+	EmptyArm:      86.5,
+	FuelArm:       95.0,
+	SeatArms:      []float64{80.5, 80.5, 118.1, 118.1},
+	BaggageArm:    142.8,
+	Envelopes: []Envelope{
+		{Category: CategoryNormal, Points: []EnvelopePoint{{1200, 83.0}, {1950, 83.0}, {2325, 87.0}, {2325, 93.0}, {1200, 93.0}}},
+		{Category: CategoryUtility, Points: []EnvelopePoint{{1200, 83.0}, {1950, 83.0}, {2020, 83.8}, {2020, 86.5}, {1200, 86.5}}, Seats: 2, NoBaggage: true},
+	},
+}
+
+func TestEnvelopeContains(t *testing.T) {
+	normal := testEnvelopes.Envelopes[0]
+	tests := []struct {
+		weight, arm float64
+		want        bool
+	}{
+		{2000, 88, true},
+		{1950, 83.0, true},   // On a corner
+		{2325, 90, true},     // On the maximum weight
+		{2137.5, 85.0, true}, // On the sloped forward limit
+		{2137.5, 84.9, false},
+		{2000, 93.1, false},
+		{2330, 90, false},
+	}
+	for _, tc := range tests {
+		if got := normal.Contains(tc.weight, tc.arm); got != tc.want {
+			t.Errorf("%.1f lbs at %.1f in: expected %v, got %v", tc.weight, tc.arm, tc.want, got)
+		}
+	}
+}
+
+func TestQualify(t *testing.T) {
+	tests := []struct {
+		name    string
+		loading Loading
+		normal  bool
+		utility bool
+	}{
+		// Instructor and student with partial fuel, for spin training
+		{"spin training", Loading{FuelGallons: 25, People: []float64{180, 160}}, true, true},
+		{"full fuel", Loading{FuelGallons: 48, People: []float64{180, 170}}, true, false},
+		{"rear seat", Loading{FuelGallons: 20, People: []float64{170, 0, 50}}, true, false},
+		{"empty rear seats", Loading{FuelGallons: 20, People: []float64{170, 150, 0, 0}}, true, true},
+		{"baggage", Loading{FuelGallons: 20, People: []float64{170, 150}, Baggage: 10}, true, false},
+	}
+	for _, tc := range tests {
+		q := testEnvelopes.Qualify(tc.loading)
+		if len(q) != 2 {
+			t.Fatalf("%s: expected two categories, got %+v", tc.name, q)
+		}
+		if q[0].Qualified != tc.normal || q[1].Qualified != tc.utility {
+			t.Errorf("%s: expected normal %v and utility %v, got %+v", tc.name, tc.normal, tc.utility, q)
+		}
+		if !q[1].Qualified && q[1].Reason == "" {
+			t.Errorf("%s: expected a reason for not qualifying", tc.name)
+		}
+	}
+
+	s, err := testEnvelopes.Compute(Loading{FuelGallons: 25, People: []float64{180, 160}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.TakeoffCG < 83 || s.TakeoffCG > 86.5 || len(s.Categories) != 2 {
+		t.Errorf("Expected a utility category CG and both categories, got %.1f in and %+v", s.TakeoffCG, s.Categories)
+	}
+
+	_, err = testEnvelopes.Compute(Loading{FuelGallons: 10, People: []float64{150, 0, 200, 200}, Baggage: 150})
+	violations, ok := err.(Violations)
+	if !ok || len(violations) != 1 || violations[0].Station != StationCG || violations[0].Code != CodeNoCategory {
+		t.Errorf("Expected an aft CG to fit no category, got %v", err)
+	}
+}
+
+func TestWorksheet(t *testing.T) {
+	aircraft := testEnvelopes
+	aircraft.TaxiFuel = 1.2
+	loading := Loading{FuelGallons: 48, People: []float64{170, 160, 0, 120}, Baggage: 30}
+	w, err := aircraft.Worksheet(loading)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty weight, two rows of seats, fuel and baggage
+	if len(w.Stations) != 5 || w.Stations[1].Weight != 330 || w.Stations[2].Weight != 120 || w.Stations[2].Arm != 118.1 {
+		t.Fatalf("Unexpected stations %+v", w.Stations)
+	}
+	for _, l := range append(w.Stations, w.TaxiFuel) {
+		if math.Abs(l.Moment-l.Weight*l.Arm) > 1e-9 {
+			t.Errorf("%s: moment %.1f is not weight times arm", l.Station, l.Moment)
+		}
+	}
+	s, _ := aircraft.Compute(loading)
+	if math.Abs(w.Ramp.Weight-s.RampWeight) > 1e-9 || math.Abs(w.Takeoff.Weight-s.TakeoffWeight) > 1e-9 ||
+		math.Abs(w.Takeoff.Arm-s.TakeoffCG) > 1e-9 {
+		t.Errorf("Expected the totals to match the summary %+v, got ramp %+v and takeoff %+v", s, w.Ramp, w.Takeoff)
+	}
+
+	if _, err := testAircraft.Worksheet(loading); err == nil {
+		t.Error("Expected an error for an aircraft without arms")
+	}
+}
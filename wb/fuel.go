@@ -0,0 +1,81 @@
+package wb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FuelPreset is a named fuel state, e.g. filled to the tabs
+type FuelPreset struct {
+	Name    string
+	Gallons float64 // Usable fuel in US gallons
+}
+
+// FuelState resolves a fuel state to usable gallons. A state is a number of
+// gallons, "full" or one of the aircraft's presets, optionally followed by
+// additions of "+Ngal" or "+Nhr" (hours at the planning fuel flow), e.g.
+// "tabs+1hr" or "30+5gal". The result must fit the usable capacity.
+func (a Aircraft) FuelState(state string) (float64, error) {
+	parts := strings.Split(strings.ToLower(strings.ReplaceAll(state, " ", "")), "+")
+
+	gallons, err := a.baseFuel(parts[0])
+	if err != nil {
+		return 0, err
+	}
+	for _, extra := range parts[1:] {
+		switch {
+		case strings.HasSuffix(extra, "hr"):
+			hours, err := strconv.ParseFloat(strings.TrimSuffix(extra, "hr"), 64)
+			if err != nil || hours < 0 {
+				return 0, fmt.Errorf("invalid fuel state %q: bad hours %q", state, extra)
+			}
+			if a.PlanningFuelFlow <= 0 {
+				return 0, fmt.Errorf("invalid fuel state %q: no planning fuel flow for hours", state)
+			}
+			gallons += hours * a.PlanningFuelFlow
+		case strings.HasSuffix(extra, "gal"):
+			more, err := strconv.ParseFloat(strings.TrimSuffix(extra, "gal"), 64)
+			if err != nil || more < 0 {
+				return 0, fmt.Errorf("invalid fuel state %q: bad gallons %q", state, extra)
+			}
+			gallons += more
+		default:
+			return 0, fmt.Errorf("invalid fuel state %q: %q should end in gal or hr", state, extra)
+		}
+	}
+
+	if gallons > a.FuelCapacity {
+		return 0, fmt.Errorf("fuel state %q is %.1f gal, more than the usable capacity of %.0f gal", state, gallons, a.FuelCapacity)
+	}
+	return gallons, nil
+}
+
+// FuelStateNames lists the names accepted by FuelState besides gallons
+func (a Aircraft) FuelStateNames() []string {
+	names := []string{"full"}
+	for _, p := range a.FuelPresets {
+		if p.Name != "full" {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// baseFuel resolves a preset name or number of gallons
+func (a Aircraft) baseFuel(name string) (float64, error) {
+	for _, p := range a.FuelPresets {
+		if strings.EqualFold(p.Name, name) {
+			return p.Gallons, nil
+		}
+	}
+	if name == "full" {
+		return a.FuelCapacity, nil
+	}
+
+	gallons, err := strconv.ParseFloat(name, 64)
+	if err != nil || gallons < 0 {
+		return 0, fmt.Errorf("unknown fuel state %q (expected gallons or one of %s)", name, strings.Join(a.FuelStateNames(), ", "))
+	}
+	return gallons, nil
+}
@@ -0,0 +1,44 @@
+package wb
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFuelState(t *testing.T) {
+	a := testAircraft
+	a.FuelPresets = []FuelPreset{{Name: "tabs", Gallons: 34}}
+	a.PlanningFuelFlow = 8
+
+	testCases := map[string]float64{
+		"full":        48,
+		"tabs":        34,
+		"TABS":        34,
+		"tabs+1hr":    42,
+		"tabs+0.5hr":  38,
+		"tabs + 5gal": 39,
+		"30":          30,
+		"20+1hr+4gal": 32,
+	}
+	for state, want := range testCases {
+		got, err := a.FuelState(state)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", state, err)
+			continue
+		}
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s: got %.2f gal, expected %.2f gal", state, got, want)
+		}
+	}
+
+	for _, state := range []string{"", "half", "full+1hr", "tabs+2", "tabs+xhr", "-5", "60"} {
+		if _, err := a.FuelState(state); err == nil {
+			t.Errorf("%q: expected an error", state)
+		}
+	}
+
+	// Hours need a planning fuel flow
+	if _, err := testAircraft.FuelState("full+0hr"); err == nil {
+		t.Error("Expected an error for hours without a planning fuel flow")
+	}
+}
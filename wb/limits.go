@@ -0,0 +1,125 @@
+package wb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stations named in violations
+const (
+	StationFuel       = "fuel"
+	StationTaxiFuel   = "taxi_fuel"
+	StationSeats      = "seats"
+	StationOccupant   = "occupant"
+	StationBaggage    = "baggage"
+	StationRampWeight = "ramp_weight"
+	StationCG         = "center_of_gravity"
+)
+
+// Violation codes
+const (
+	CodeNegative     = "negative"      // A quantity below zero
+	CodeAboveMaximum = "above_maximum" // A quantity over the profile's limit
+	CodeNoCategory   = "no_category"   // The CG fits none of the envelopes
+)
+
+// Violation describes a single station loaded outside the profile's limits.
+// Value and Limit are in Unit; Index is the seat for occupant violations.
+type Violation struct {
+	Station string  `json:"station"`
+	Code    string  `json:"code"`
+	Index   int     `json:"index,omitempty"`
+	Value   float64 `json:"value"`
+	Limit   float64 `json:"limit"`
+	Unit    string  `json:"unit"`
+	Message string  `json:"message"`
+}
+
+// Error implements the error interface
+func (v *Violation) Error() string {
+	return v.Message
+}
+
+// Violations collects every limit a loading breaks
+type Violations []*Violation
+
+// Error implements the error interface, joining the individual messages
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Check returns every station of a loading outside the aircraft's limits:
+// usable fuel, taxi fuel, seats, occupants, the baggage compartment, the
+// ramp weight and the CG envelopes. It returns nil for a loading that can
+// be flown.
+func (a Aircraft) Check(l Loading) Violations {
+	var v Violations
+	add := func(station, code string, value, limit float64, unit, format string, args ...interface{}) {
+		v = append(v, &Violation{Station: station, Code: code, Value: value, Limit: limit, Unit: unit, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case l.FuelGallons < 0:
+		add(StationFuel, CodeNegative, l.FuelGallons, 0, "gal", "fuel (%.1f gal) is negative", l.FuelGallons)
+	case l.FuelGallons > a.FuelCapacity:
+		add(StationFuel, CodeAboveMaximum, l.FuelGallons, a.FuelCapacity, "gal",
+			"fuel (%.1f gal) exceeds usable capacity (%.0f gal)", l.FuelGallons, a.FuelCapacity)
+	}
+	switch {
+	case a.TaxiFuel < 0:
+		add(StationTaxiFuel, CodeNegative, a.TaxiFuel, 0, "gal", "taxi fuel allowance (%.1f gal) is negative", a.TaxiFuel)
+	case a.TaxiFuel > l.FuelGallons && l.FuelGallons >= 0:
+		add(StationTaxiFuel, CodeAboveMaximum, a.TaxiFuel, l.FuelGallons, "gal",
+			"taxi fuel allowance (%.1f gal) exceeds fuel on board (%.1f gal)", a.TaxiFuel, l.FuelGallons)
+	}
+	if len(l.People) > a.Seats {
+		add(StationSeats, CodeAboveMaximum, float64(len(l.People)), float64(a.Seats), "people",
+			"%d people exceeds %d seats", len(l.People), a.Seats)
+	}
+	for i, person := range l.People {
+		if person < 0 {
+			v = append(v, &Violation{Station: StationOccupant, Code: CodeNegative, Index: i + 1, Value: person, Unit: "lbs",
+				Message: fmt.Sprintf("occupant %d weight (%.0f lbs) is negative", i+1, person)})
+		}
+	}
+	switch {
+	case l.Baggage < 0:
+		add(StationBaggage, CodeNegative, l.Baggage, 0, "lbs", "baggage (%.0f lbs) is negative", l.Baggage)
+	case l.Baggage > a.MaxBaggage:
+		add(StationBaggage, CodeAboveMaximum, l.Baggage, a.MaxBaggage, "lbs",
+			"baggage (%.0f lbs) exceeds compartment limit (%.0f lbs)", l.Baggage, a.MaxBaggage)
+	}
+
+	if ramp := a.rampWeight(l); a.MaxRampWeight > 0 && ramp > a.MaxRampWeight {
+		add(StationRampWeight, CodeAboveMaximum, ramp, a.MaxRampWeight, "lbs",
+			"ramp weight (%.0f lbs) exceeds maximum (%.0f lbs)", ramp, a.MaxRampWeight)
+	}
+	if q := a.Qualify(l); len(q) > 0 && !qualifiesForAny(q) {
+		_, arm := a.balance(l)
+		add(StationCG, CodeNoCategory, arm, 0, "in", "loading fits no category (%s: %s)", q[0].Category, q[0].Reason)
+	}
+	return v
+}
+
+// rampWeight adds up a loading without checking it
+func (a Aircraft) rampWeight(l Loading) float64 {
+	weight := a.EmptyWeight + l.FuelGallons*a.FuelDensity + l.Baggage
+	for _, person := range l.People {
+		weight += person
+	}
+	return weight
+}
+
+// qualifiesForAny reports whether any category is met
+func qualifiesForAny(q []Qualification) bool {
+	for _, c := range q {
+		if c.Qualified {
+			return true
+		}
+	}
+	return false
+}
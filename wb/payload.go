@@ -0,0 +1,48 @@
+package wb
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxTakeoffWeight returns the heaviest weight of any CG envelope, the
+// structural limit on takeoff weight, or 0 without envelopes
+func (a Aircraft) MaxTakeoffWeight() float64 {
+	max := 0.0
+	for _, e := range a.Envelopes {
+		max = math.Max(max, e.MaxWeight())
+	}
+	return max
+}
+
+// MaxPayload returns the most people and baggage in pounds that fit with
+// the fuel under maxWeight at takeoff, the lower of the structural and any
+// performance limit, and under the maximum ramp weight. It is negative
+// when the fuel alone is too heavy.
+func (a Aircraft) MaxPayload(fuelGallons, maxWeight float64) float64 {
+	fuel := fuelGallons * a.FuelDensity
+	payload := maxWeight - (a.EmptyWeight + fuel - a.TaxiFuel*a.FuelDensity)
+	if a.MaxRampWeight > 0 {
+		payload = math.Min(payload, a.MaxRampWeight-a.EmptyWeight-fuel)
+	}
+	return payload
+}
+
+// MaxFuel returns the most usable fuel in gallons, to the tenth, that can
+// be loaded with the people and baggage: within the capacity and every
+// limit Check enforces, including the CG envelopes, and at most maxWeight
+// at takeoff. When no fuel fits it returns the violations of a loading
+// with just the taxi fuel, or an error when only maxWeight is exceeded.
+func (a Aircraft) MaxFuel(people []float64, baggage, maxWeight float64) (float64, error) {
+	for tenths := math.Floor(a.FuelCapacity*10 + 1e-9); tenths >= 0; tenths-- {
+		l := Loading{FuelGallons: tenths / 10, People: people, Baggage: baggage}
+		if a.Check(l) == nil && a.rampWeight(l)-a.TaxiFuel*a.FuelDensity <= maxWeight {
+			return l.FuelGallons, nil
+		}
+	}
+
+	if v := a.Check(Loading{FuelGallons: a.TaxiFuel, People: people, Baggage: baggage}); v != nil {
+		return 0, v
+	}
+	return 0, fmt.Errorf("the people and baggage leave no room for fuel under %.0f lbs", maxWeight)
+}
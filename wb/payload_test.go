@@ -0,0 +1,57 @@
+package wb
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaxPayload(t *testing.T) {
+	aircraft := testEnvelopes
+	aircraft.TaxiFuel = 1.2
+	if got := aircraft.MaxTakeoffWeight(); got != 2325 {
+		t.Errorf("Expected a 2325 lbs maximum, got %.0f", got)
+	}
+
+	// 2100 lbs less the empty weight and 48 gal less the taxi fuel
+	if got := aircraft.MaxPayload(48, 2100); math.Abs(got-319.2) > 0.001 {
+		t.Errorf("Expected 319.2 lbs under a 2100 lbs runway limit, got %.1f", got)
+	}
+	// At maximum weight the 2332 lbs ramp weight binds, 0.2 lbs short of 2325 lbs after taxi
+	if got := aircraft.MaxPayload(48, 2325); math.Abs(got-544) > 0.001 {
+		t.Errorf("Expected the ramp weight to leave 544 lbs, got %.1f", got)
+	}
+	if got := aircraft.MaxPayload(48, 1700); got >= 0 {
+		t.Errorf("Expected no payload with full fuel under 1700 lbs, got %.1f", got)
+	}
+}
+
+func TestMaxFuel(t *testing.T) {
+	aircraft := testEnvelopes
+	aircraft.TaxiFuel = 1.2
+	people := []float64{190, 180, 170}
+
+	fuel, err := aircraft.MaxFuel(people, 20, 2325)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := aircraft.Compute(Loading{FuelGallons: fuel, People: people, Baggage: 20})
+	if err != nil || s.TakeoffWeight > 2325 || s.TakeoffWeight < 2325-aircraft.FuelDensity*0.1 {
+		t.Errorf("Expected %.1f gal to fill up to 2325 lbs, got %+v %v", fuel, s, err)
+	}
+
+	// A runway limit leaves less room for fuel
+	if limited, err := aircraft.MaxFuel(people, 20, 2200); err != nil || limited >= fuel {
+		t.Errorf("Expected less than %.1f gal under 2200 lbs, got %.1f %v", fuel, limited, err)
+	}
+	if full, err := aircraft.MaxFuel([]float64{170}, 0, 2325); err != nil || full != 48 {
+		t.Errorf("Expected full fuel with one person, got %.1f %v", full, err)
+	}
+
+	// Heavy in the back with the baggage full: aft of every envelope with any fuel
+	if _, err := aircraft.MaxFuel([]float64{150, 0, 220, 220}, 200, 2325); err == nil {
+		t.Error("Expected no fuel to fit an aft loading")
+	}
+	if _, err := aircraft.MaxFuel([]float64{220, 220, 220, 220}, 0, 2325); err == nil {
+		t.Error("Expected no fuel to fit four heavy people")
+	}
+}
@@ -0,0 +1,61 @@
+package wb
+
+import (
+	"fmt"
+	"time"
+)
+
+// Trip is a flight from takeoff to landing, for the fuel burned on the way
+type Trip struct {
+	TakeoffWeight float64       // Weight at brake release in pounds
+	FlightTime    time.Duration // Takeoff to landing
+	FuelFlow      float64       // Average fuel flow in US gallons per hour; 0 for the planning fuel flow
+	FuelGallons   float64       // Usable fuel at takeoff in US gallons, 0 when not known
+}
+
+// Arrival is the weight a trip lands at
+type Arrival struct {
+	FuelFlow      float64 `json:"fuel_flow"`                    // in US gallons per hour
+	BurnGallons   float64 `json:"burn_gal"`                     // Fuel burned from takeoff to landing in US gallons
+	Burn          float64 `json:"burn"`                         // in pounds
+	FuelRemaining float64 `json:"fuel_remaining_gal,omitempty"` // Usable fuel at landing in US gallons, 0 when the fuel at takeoff is not known
+	LandingWeight float64 `json:"landing_weight"`               // in pounds
+}
+
+// Arrival subtracts the fuel burned on a trip from the takeoff weight to
+// get the landing weight, so the landing is computed from the same loading
+// as the takeoff. The burn must fit the fuel at takeoff when it is known,
+// or the usable capacity when it is not.
+func (a Aircraft) Arrival(t Trip) (*Arrival, error) {
+	if t.FuelFlow == 0 {
+		t.FuelFlow = a.PlanningFuelFlow
+	}
+	switch {
+	case t.FlightTime < 0:
+		return nil, fmt.Errorf("negative flight time %s", t.FlightTime)
+	case t.FuelFlow <= 0:
+		return nil, fmt.Errorf("no fuel flow for the trip and no planning fuel flow")
+	}
+
+	arrival := &Arrival{
+		FuelFlow:    t.FuelFlow,
+		BurnGallons: t.FlightTime.Hours() * t.FuelFlow,
+	}
+	arrival.Burn = arrival.BurnGallons * a.FuelDensity
+	arrival.LandingWeight = t.TakeoffWeight - arrival.Burn
+
+	available, of := t.FuelGallons, "usable at takeoff"
+	if available <= 0 {
+		available, of = a.FuelCapacity, "usable capacity"
+	}
+	if available > 0 && arrival.BurnGallons > available {
+		return nil, fmt.Errorf("the trip burns %.1f gal at %.1f gph, more than the %.1f gal %s", arrival.BurnGallons, t.FuelFlow, available, of)
+	}
+	if t.FuelGallons > 0 {
+		arrival.FuelRemaining = t.FuelGallons - arrival.BurnGallons
+	}
+	if arrival.LandingWeight < a.EmptyWeight {
+		return nil, fmt.Errorf("landing weight %.0f lbs is below the empty weight of %.0f lbs", arrival.LandingWeight, a.EmptyWeight)
+	}
+	return arrival, nil
+}
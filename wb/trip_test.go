@@ -0,0 +1,45 @@
+package wb
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestArrival(t *testing.T) {
+	a := testAircraft
+	a.PlanningFuelFlow = 8.5
+
+	// 1.5 hours at 9 gph is 13.5 gal, 81 lbs
+	arrival, err := a.Arrival(Trip{TakeoffWeight: 2300, FlightTime: 90 * time.Minute, FuelFlow: 9, FuelGallons: 40})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(arrival.BurnGallons-13.5) > 1e-9 || math.Abs(arrival.Burn-81) > 1e-9 ||
+		math.Abs(arrival.LandingWeight-2219) > 1e-9 || math.Abs(arrival.FuelRemaining-26.5) > 1e-9 {
+		t.Errorf("Unexpected arrival %+v", arrival)
+	}
+
+	// The planning fuel flow is used when none is given
+	arrival, err = a.Arrival(Trip{TakeoffWeight: 2300, FlightTime: 2 * time.Hour})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if arrival.FuelFlow != 8.5 || arrival.LandingWeight != 2300-17*AvgasDensity || arrival.FuelRemaining != 0 {
+		t.Errorf("Unexpected arrival at the planning fuel flow %+v", arrival)
+	}
+
+	for name, trip := range map[string]Trip{
+		"more than the fuel at takeoff":  {TakeoffWeight: 2300, FlightTime: 3 * time.Hour, FuelFlow: 9, FuelGallons: 20},
+		"more than the usable capacity":  {TakeoffWeight: 2300, FlightTime: 6 * time.Hour, FuelFlow: 9},
+		"negative flight time":           {TakeoffWeight: 2300, FlightTime: -time.Hour, FuelFlow: 9},
+		"landing below the empty weight": {TakeoffWeight: 1520, FlightTime: time.Hour, FuelFlow: 9},
+	} {
+		if _, err := a.Arrival(trip); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := testAircraft.Arrival(Trip{TakeoffWeight: 2300, FlightTime: time.Hour}); err == nil {
+		t.Error("Expected an error without a fuel flow")
+	}
+}
@@ -0,0 +1,73 @@
+// Package wb computes aircraft weight from a loading of fuel, people and baggage
+package wb
+
+// AvgasDensity is the standard weight of 100LL in pounds per US gallon
+const AvgasDensity = 6.0
+
+// Aircraft holds the weight data for an aircraft
+type Aircraft struct {
+	EmptyWeight   float64 // Basic empty weight in pounds, including unusable fuel and full oil
+	FuelDensity   float64 // Fuel weight in pounds per US gallon
+	FuelCapacity  float64 // Usable fuel in US gallons
+	Seats         int     // Number of occupant seats
+	MaxBaggage    float64 // Baggage compartment limit in pounds
+	MaxRampWeight float64 // Maximum ramp weight in pounds
+	TaxiFuel      float64 // Taxi and run-up fuel allowance in US gallons
+
+	FuelPresets      []FuelPreset // Named fuel states such as "tabs"
+	PlanningFuelFlow float64      // Fuel flow in gph for "+Nhr" fuel states
+
+	// Arms in inches aft of the datum, for the CG; none without an empty arm
+	EmptyArm   float64
+	FuelArm    float64
+	SeatArms   []float64 // One per seat, in the order occupants are listed
+	BaggageArm float64
+	Envelopes  []Envelope // CG envelopes, normal category first
+}
+
+// Loading is what is put into the aircraft for a flight
+type Loading struct {
+	FuelGallons float64   `json:"fuel_gal"` // Usable fuel at engine start in US gallons
+	People      []float64 `json:"people"`   // Weight of each occupant in pounds
+	Baggage     float64   `json:"baggage"`  // Baggage weight in pounds
+}
+
+// Summary is the weight build-up for a loading
+type Summary struct {
+	EmptyWeight   float64 `json:"empty_weight"`         // in pounds
+	FuelGallons   float64 `json:"fuel_gal"`             // in US gallons
+	Fuel          float64 `json:"fuel"`                 // in pounds
+	People        float64 `json:"people"`               // in pounds
+	Baggage       float64 `json:"baggage"`              // in pounds
+	RampWeight    float64 `json:"ramp_weight"`          // Weight at engine start in pounds
+	TaxiFuel      float64 `json:"taxi_fuel"`            // Taxi and run-up fuel burned before takeoff, in pounds
+	TakeoffWeight float64 `json:"takeoff_weight"`       // Weight at brake release in pounds
+	TakeoffCG     float64 `json:"takeoff_cg,omitempty"` // in inches aft of the datum, 0 when the arms are unknown
+
+	Categories []Qualification `json:"categories,omitempty"` // Whether the loading meets each envelope's category
+}
+
+// Compute builds up the ramp weight for a loading and subtracts the taxi
+// fuel allowance to get the takeoff weight. A loading outside the
+// aircraft's limits returns every violation as Violations.
+func (a Aircraft) Compute(l Loading) (*Summary, error) {
+	if v := a.Check(l); v != nil {
+		return nil, v
+	}
+
+	s := &Summary{
+		EmptyWeight: a.EmptyWeight,
+		FuelGallons: l.FuelGallons,
+		Fuel:        l.FuelGallons * a.FuelDensity,
+		Baggage:     l.Baggage,
+		TaxiFuel:    a.TaxiFuel * a.FuelDensity,
+	}
+	for _, person := range l.People {
+		s.People += person
+	}
+	s.RampWeight = s.EmptyWeight + s.Fuel + s.People + s.Baggage
+	s.TakeoffWeight = s.RampWeight - s.TaxiFuel
+	_, s.TakeoffCG = a.balance(l)
+	s.Categories = a.Qualify(l)
+	return s, nil
+}
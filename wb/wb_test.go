@@ -0,0 +1,105 @@
+package wb
+
+import (
+	"errors"
+	"testing"
+)
+
+var testAircraft = Aircraft{
+	EmptyWeight:   1500,
+	FuelDensity:   AvgasDensity,
+	FuelCapacity:  48,
+	Seats:         4,
+	MaxBaggage:    200,
+	MaxRampWeight: 2332,
+}
+
+func TestCompute(t *testing.T) {
+	s, err := testAircraft.Compute(Loading{FuelGallons: 48, People: []float64{170, 160}, Baggage: 40})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if s.Fuel != 288 {
+		t.Errorf("Fuel: expected 288 lbs, got %.1f", s.Fuel)
+	}
+	if s.People != 330 {
+		t.Errorf("People: expected 330 lbs, got %.1f", s.People)
+	}
+	if s.RampWeight != 2158 || s.TakeoffWeight != 2158 {
+		t.Errorf("Expected ramp and takeoff weight 2158 lbs, got %.1f and %.1f", s.RampWeight, s.TakeoffWeight)
+	}
+}
+
+func TestComputeTaxiFuel(t *testing.T) {
+	aircraft := testAircraft
+	aircraft.TaxiFuel = 1.5
+
+	s, err := aircraft.Compute(Loading{FuelGallons: 48, People: []float64{170, 160}, Baggage: 40})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.TaxiFuel != 9 {
+		t.Errorf("Taxi fuel: expected 9 lbs, got %.1f", s.TaxiFuel)
+	}
+	if s.RampWeight != 2158 || s.TakeoffWeight != 2149 {
+		t.Errorf("Expected ramp weight 2158 lbs and takeoff weight 2149 lbs, got %.1f and %.1f", s.RampWeight, s.TakeoffWeight)
+	}
+
+	if _, err := aircraft.Compute(Loading{FuelGallons: 1}); err == nil {
+		t.Error("Expected error when taxi fuel exceeds fuel on board, but got none")
+	}
+}
+
+func TestComputeLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		loading Loading
+	}{
+		{"too much fuel", Loading{FuelGallons: 50}},
+		{"negative fuel", Loading{FuelGallons: -1}},
+		{"too many people", Loading{People: []float64{170, 170, 170, 170, 170}}},
+		{"negative person", Loading{People: []float64{-170}}},
+		{"too much baggage", Loading{Baggage: 250}},
+		{"over ramp weight", Loading{FuelGallons: 48, People: []float64{200, 200, 200}}},
+	}
+
+	for _, tc := range tests {
+		if _, err := testAircraft.Compute(tc.loading); err == nil {
+			t.Errorf("%s: expected error, but got none", tc.name)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if v := testAircraft.Check(Loading{FuelGallons: 48, People: []float64{170, 160}, Baggage: 40}); v != nil {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+
+	v := testAircraft.Check(Loading{FuelGallons: 52, People: []float64{170, -5}, Baggage: 240})
+	want := []struct {
+		station, code string
+		limit         float64
+	}{
+		{StationFuel, CodeAboveMaximum, 48},
+		{StationOccupant, CodeNegative, 0},
+		{StationBaggage, CodeAboveMaximum, 200},
+	}
+	if len(v) != len(want) {
+		t.Fatalf("Expected %d violations, got %d: %v", len(want), len(v), v)
+	}
+	for i, w := range want {
+		if v[i].Station != w.station || v[i].Code != w.code || v[i].Limit != w.limit {
+			t.Errorf("Violation %d: expected %s %s at %.0f, got %+v", i, w.station, w.code, w.limit, v[i])
+		}
+	}
+	if v[1].Index != 2 {
+		t.Errorf("Expected the second occupant to be named, got %d", v[1].Index)
+	}
+
+	_, err := testAircraft.Compute(Loading{FuelGallons: 52, Baggage: 240})
+	var violations Violations
+	if !errors.As(err, &violations) || len(violations) != 2 {
+		t.Errorf("Expected Compute to return both violations, got %v", err)
+	}
+}
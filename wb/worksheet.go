@@ -0,0 +1,92 @@
+package wb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WorksheetLine is one station of a weight and balance worksheet
+type WorksheetLine struct {
+	Station string  `json:"station"`
+	Weight  float64 `json:"weight"` // in pounds
+	Arm     float64 `json:"arm"`    // in inches aft of the datum
+	Moment  float64 `json:"moment"` // in inch-pounds
+}
+
+// Worksheet is a loading laid out like the POH sample loading problem: a
+// line per station, the ramp weight, the taxi fuel burned and the takeoff
+// weight, each with its arm and moment
+type Worksheet struct {
+	Stations   []WorksheetLine `json:"stations"`
+	Ramp       WorksheetLine   `json:"ramp"`
+	TaxiFuel   WorksheetLine   `json:"taxi_fuel"`
+	Takeoff    WorksheetLine   `json:"takeoff"`
+	Categories []Qualification `json:"categories,omitempty"`
+}
+
+// Worksheet builds the weight and balance worksheet for a loading. It
+// returns the Violations for a loading outside the aircraft's limits, and
+// an error when the aircraft has no arms.
+func (a Aircraft) Worksheet(l Loading) (*Worksheet, error) {
+	if a.EmptyArm == 0 {
+		return nil, errors.New("the aircraft has no station arms for a worksheet")
+	}
+	s, err := a.Compute(l)
+	if err != nil {
+		return nil, err
+	}
+
+	line := func(station string, weight, arm float64) WorksheetLine {
+		return WorksheetLine{Station: station, Weight: weight, Arm: arm, Moment: weight * arm}
+	}
+	w := &Worksheet{Categories: s.Categories}
+	w.Stations = append(w.Stations, line("Basic empty weight", a.EmptyWeight, a.EmptyArm))
+	w.Stations = append(w.Stations, a.seatRows(l.People)...)
+	w.Stations = append(w.Stations,
+		line(fmt.Sprintf("Fuel (%.0f gal maximum)", a.FuelCapacity), s.Fuel, a.FuelArm),
+		line(fmt.Sprintf("Baggage (%.0f lbs maximum)", a.MaxBaggage), s.Baggage, a.BaggageArm))
+
+	w.Ramp = total("Ramp weight", w.Stations)
+	w.TaxiFuel = line("Start, taxi and run-up fuel", -s.TaxiFuel, a.FuelArm)
+	w.Takeoff = total("Takeoff weight", []WorksheetLine{w.Ramp, w.TaxiFuel})
+	return w, nil
+}
+
+// seatRows combines the occupants into a line per row of seats, a row
+// being the seats that share an arm, as the POH worksheet does
+func (a Aircraft) seatRows(people []float64) []WorksheetLine {
+	var rows []WorksheetLine
+	for i, arm := range a.SeatArms {
+		if i == 0 || arm != a.SeatArms[i-1] {
+			rows = append(rows, WorksheetLine{Arm: arm})
+		}
+		if i < len(people) {
+			rows[len(rows)-1].Weight += people[i]
+		}
+	}
+	for i := range rows {
+		rows[i].Moment = rows[i].Weight * rows[i].Arm
+		switch {
+		case i == 0:
+			rows[i].Station = "Front seats"
+		case i == len(rows)-1:
+			rows[i].Station = "Rear seats"
+		default:
+			rows[i].Station = fmt.Sprintf("Seat row %d", i+1)
+		}
+	}
+	return rows
+}
+
+// total sums worksheet lines, with the arm of the total moment
+func total(station string, lines []WorksheetLine) WorksheetLine {
+	t := WorksheetLine{Station: station}
+	for _, l := range lines {
+		t.Weight += l.Weight
+		t.Moment += l.Moment
+	}
+	if t.Weight > 0 {
+		t.Arm = t.Moment / t.Weight
+	}
+	return t
+}
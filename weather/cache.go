@@ -0,0 +1,153 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/trace"
+)
+
+// DefaultTTL is how long a cached report is used before fetching again
+const DefaultTTL = 10 * time.Minute
+
+// cacheKeep is how many reports are kept per station and product
+const cacheKeep = 24
+
+// Cache wraps a Fetcher with an on-disk cache. Reports are stored per
+// product and station, one file per issue time, so iterating on scenarios
+// reuses recent data and a briefly unreachable provider falls back to the
+// last report fetched.
+type Cache struct {
+	Dir     string
+	TTL     time.Duration
+	Fetcher Fetcher
+
+	now func() time.Time
+}
+
+// NewCache creates a cache in dir in front of a fetcher
+func NewCache(dir string, ttl time.Duration, fetcher Fetcher) *Cache {
+	return &Cache{Dir: dir, TTL: ttl, Fetcher: fetcher, now: time.Now}
+}
+
+// DefaultCacheDir returns the per-user cache directory for weather reports
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "otto", "weather"), nil
+}
+
+// Fetch returns a cached report if it was fetched within the TTL, and
+// otherwise fetches a new one. If fetching fails and an older report is
+// cached, that report is returned marked as stale.
+func (c *Cache) Fetch(ctx context.Context, product Product, station string) (*Report, error) {
+	station = normalizeStation(station)
+	ctx, span := trace.Start(ctx, "weather.cache")
+	defer span.End()
+
+	cached, _ := c.latest(product, station)
+	if cached != nil && c.now().Sub(cached.Fetched) < c.TTL {
+		span.SetAttribute("cache.result", "hit")
+		return cached, nil
+	}
+
+	report, err := c.Fetcher.Fetch(ctx, product, station)
+	if err != nil {
+		if cached != nil {
+			span.SetAttribute("cache.result", "stale")
+			cached.Stale = true
+			return cached, nil
+		}
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// A cache that cannot be written, such as on a full or read-only disk,
+	// must not cost a report that was fetched
+	span.SetAttribute("cache.result", "miss")
+	if err := c.store(report); err != nil {
+		span.RecordError(err)
+	}
+	return report, nil
+}
+
+// dir returns the directory holding reports for a product and station
+func (c *Cache) dir(product Product, station string) string {
+	return filepath.Join(c.Dir, string(product), station)
+}
+
+// entries lists the cached report files for a station, oldest issue first
+func (c *Cache) entries(product Product, station string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir(product, station), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// latest loads the most recently issued cached report, if any
+func (c *Cache) latest(product Product, station string) (*Report, error) {
+	files, err := c.entries(product, station)
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(files[len(files)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// store writes a report to the cache, keyed by its issue time, and prunes old entries
+func (c *Cache) store(report *Report) error {
+	dir := c.dir(report.Product, report.Station)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := report.Issued.UTC().Format("20060102T1504Z") + ".json"
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return err
+	}
+
+	files, err := c.entries(report.Product, report.Station)
+	if err != nil {
+		return err
+	}
+	for len(files) > cacheKeep {
+		os.Remove(files[0])
+		files = files[1:]
+	}
+	return nil
+}
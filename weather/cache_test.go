@@ -0,0 +1,104 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeFetcher returns canned reports and counts calls
+type fakeFetcher struct {
+	calls  int
+	report Report
+	err    error
+}
+
+func (f *fakeFetcher) Fetch(ctx context.Context, product Product, station string) (*Report, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	r := f.report
+	return &r, nil
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 18, 0, 0, 0, time.UTC)
+	fetcher := &fakeFetcher{report: Report{
+		Station: "KJYO",
+		Product: METAR,
+		Issued:  now.Add(-7 * time.Minute),
+		Fetched: now,
+		Raw:     "KJYO 151753Z 17008KT 10SM CLR 24/12 A3002",
+	}}
+
+	cache := NewCache(t.TempDir(), 10*time.Minute, fetcher)
+	cache.now = func() time.Time { return now }
+
+	// First fetch goes to the provider, the second within the TTL does not
+	for i := 0; i < 2; i++ {
+		report, err := cache.Fetch(context.Background(), METAR, "kjyo")
+		if err != nil {
+			t.Fatalf("Fetch %d: %v", i, err)
+		}
+		if report.Raw != fetcher.report.Raw || report.Stale {
+			t.Errorf("Fetch %d: unexpected report %+v", i, *report)
+		}
+	}
+	if fetcher.calls != 1 {
+		t.Errorf("Expected 1 provider call within TTL, got %d", fetcher.calls)
+	}
+
+	// After the TTL the provider is asked again
+	now = now.Add(11 * time.Minute)
+	fetcher.report.Fetched = now
+	if _, err := cache.Fetch(context.Background(), METAR, "KJYO"); err != nil {
+		t.Fatalf("Fetch after TTL: %v", err)
+	}
+	if fetcher.calls != 2 {
+		t.Errorf("Expected provider call after TTL, got %d calls", fetcher.calls)
+	}
+
+	// When the provider is unreachable the cached report is served as stale
+	now = now.Add(time.Hour)
+	fetcher.err = errors.New("network unreachable")
+	report, err := cache.Fetch(context.Background(), METAR, "KJYO")
+	if err != nil {
+		t.Fatalf("Expected stale report while offline, got error: %v", err)
+	}
+	if !report.Stale {
+		t.Error("Expected report to be marked stale")
+	}
+
+	// With nothing cached the provider error is returned
+	if _, err := cache.Fetch(context.Background(), TAF, "KJYO"); err == nil {
+		t.Error("Expected error with empty cache and unreachable provider")
+	}
+}
+
+func TestCacheUnwritable(t *testing.T) {
+	// A file where the cache directory should be cannot be written to, even
+	// by root, as a read-only or full disk cannot
+	dir := filepath.Join(t.TempDir(), "weather")
+	if err := os.WriteFile(dir, nil, 0o444); err != nil {
+		t.Fatal(err)
+	}
+	fetcher := &fakeFetcher{report: Report{
+		Station: "KJYO",
+		Product: METAR,
+		Issued:  time.Date(2026, time.October, 15, 17, 53, 0, 0, time.UTC),
+		Raw:     "KJYO 151753Z 17008KT 10SM CLR 24/12 A3002",
+	}}
+	cache := NewCache(dir, 10*time.Minute, fetcher)
+
+	report, err := cache.Fetch(context.Background(), METAR, "KJYO")
+	if err != nil {
+		t.Fatalf("Expected the fetched report despite the cache, got error: %v", err)
+	}
+	if report.Raw != fetcher.report.Raw || report.Stale {
+		t.Errorf("Unexpected report %+v", *report)
+	}
+}
@@ -0,0 +1,134 @@
+package weather
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/trace"
+)
+
+// DefaultBaseURL is the aviationweather.gov data API
+const DefaultBaseURL = "https://aviationweather.gov/api/data"
+
+// Client fetches raw reports from the aviationweather.gov data API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the default endpoint
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Fetch retrieves the latest report of a product for a station. For winds
+// aloft the station is the three-letter forecast site (e.g. IAD).
+func (c *Client) Fetch(ctx context.Context, product Product, station string) (report *Report, err error) {
+	station = normalizeStation(station)
+	ctx, span := trace.Start(ctx, "weather.fetch")
+	span.SetAttribute("weather.product", string(product))
+	span.SetAttribute("weather.station", station)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	query := url.Values{}
+	switch product {
+	case METAR, TAF:
+		query.Set("ids", station)
+		query.Set("format", "raw")
+	case WindsAloft:
+		query.Set("region", "all")
+		query.Set("level", "low")
+		query.Set("fcst", "06")
+	default:
+		return nil, fmt.Errorf("unsupported weather product %q", product)
+	}
+
+	body, err := c.get(ctx, string(product), query)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := strings.TrimSpace(body)
+	if product == WindsAloft {
+		raw, err = extractWindsAloft(body, station)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("no %s available for %s", product, station)
+	}
+
+	fetched := time.Now().UTC()
+	issued, err := parseIssueTime(raw, fetched)
+	if err != nil {
+		return nil, fmt.Errorf("%s for %s: %w", product, station, err)
+	}
+
+	return &Report{
+		Station: station,
+		Product: product,
+		Issued:  issued,
+		Fetched: fetched,
+		Raw:     raw,
+	}, nil
+}
+
+// get performs a GET request against an API endpoint and returns the body
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values) (string, error) {
+	u := strings.TrimRight(c.BaseURL, "/") + "/" + endpoint + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("weather request %s: %s", endpoint, resp.Status)
+	}
+	return string(body), nil
+}
+
+// extractWindsAloft keeps the "DATA BASED ON" header and the column header
+// of an FB winds forecast along with the line for a single station
+func extractWindsAloft(body, station string) (string, error) {
+	var header []string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " ")
+		fields := strings.Fields(line)
+		switch {
+		case strings.HasPrefix(line, "DATA BASED ON"), strings.HasPrefix(line, "FT "):
+			header = append(header, line)
+		case len(fields) > 0 && fields[0] == station:
+			return strings.Join(append(header, line), "\n"), nil
+		}
+	}
+	return "", fmt.Errorf("no winds aloft forecast for %s", station)
+}
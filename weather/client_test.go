@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const windsAloftBody = `(Extracted from FBUS31 KWNO 151358)
+FD1US1
+DATA BASED ON 151200Z
+VALID 151800Z   FOR USE 1400-2100Z. TEMPS NEG ABV 24000
+
+FT  3000    6000    9000   12000   18000   24000  30000  34000  39000
+BOS 2714 2725+03 2735-02 2745-07 2765-19 2785-31 790345 780852 780656
+IAD 2510 2618+08 2628+03 2638-03 2658-15 2678-27 269842 750453 259955
+`
+
+func TestClientFetch(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/metar":
+			w.Write([]byte("KJYO 151753Z 17008KT 10SM CLR 24/12 A3002\n"))
+		case "/windtemp":
+			w.Write([]byte(windsAloftBody))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+
+	report, err := client.Fetch(context.Background(), METAR, "kjyo")
+	if err != nil {
+		t.Fatalf("Error fetching METAR: %v", err)
+	}
+	if report.Station != "KJYO" || report.Raw != "KJYO 151753Z 17008KT 10SM CLR 24/12 A3002" {
+		t.Errorf("Unexpected report %+v", *report)
+	}
+	if report.Issued.Day() != 15 || report.Issued.Hour() != 17 || report.Issued.Minute() != 53 {
+		t.Errorf("Unexpected issue time %v", report.Issued)
+	}
+	if !strings.Contains(paths[0], "ids=KJYO") {
+		t.Errorf("Station not passed to API: %s", paths[0])
+	}
+
+	report, err = client.Fetch(context.Background(), WindsAloft, "IAD")
+	if err != nil {
+		t.Fatalf("Error fetching winds aloft: %v", err)
+	}
+	lines := strings.Split(report.Raw, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[2], "IAD 2510") {
+		t.Errorf("Unexpected winds aloft extract:\n%s", report.Raw)
+	}
+
+	if _, err := client.Fetch(context.Background(), TAF, "KJYO"); err == nil {
+		t.Error("Expected error for failed request, but got none")
+	}
+}
@@ -0,0 +1,177 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// DemoFetcher makes up plausible reports instead of fetching them, for
+// demos, workshops and integration tests without a network. The same seed
+// always gives the same weather for a station and product; only the issue
+// times follow the clock, so the reports never look stale.
+type DemoFetcher struct {
+	Seed int64
+	Now  func() time.Time // Clock for the issue times (default: time.Now)
+}
+
+// NewDemoFetcher creates a demo fetcher with a seed
+func NewDemoFetcher(seed int64) *DemoFetcher {
+	return &DemoFetcher{Seed: seed}
+}
+
+// Fetch returns a made-up report of a product for any station
+func (d *DemoFetcher) Fetch(ctx context.Context, product Product, station string) (*Report, error) {
+	station = normalizeStation(station)
+	if station == "" {
+		return nil, fmt.Errorf("no station given")
+	}
+
+	now := time.Now
+	if d.Now != nil {
+		now = d.Now
+	}
+	fetched := now().UTC()
+	r := d.random(product, station)
+
+	var raw string
+	var issued time.Time
+	switch product {
+	case METAR:
+		// Routine observations are taken at 53 minutes past the hour
+		issued = fetched.Truncate(time.Hour).Add(-7 * time.Minute)
+		raw = demoMETAR(r, station, issued)
+	case TAF:
+		issued = fetched.Truncate(6 * time.Hour).Add(-40 * time.Minute)
+		raw = demoTAF(r, station, issued)
+	case WindsAloft:
+		issued = fetched.Truncate(6 * time.Hour).Add(-6 * time.Hour)
+		raw = demoWindsAloft(r, station, issued)
+	default:
+		return nil, fmt.Errorf("unsupported weather product %q", product)
+	}
+
+	return &Report{
+		Station: station,
+		Product: product,
+		Issued:  issued,
+		Fetched: fetched,
+		Raw:     raw,
+	}, nil
+}
+
+// random returns the generator for one station and product
+func (d *DemoFetcher) random(product Product, station string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(string(product) + "/" + station))
+	return rand.New(rand.NewSource(d.Seed ^ int64(h.Sum64())))
+}
+
+// demoSurface is the made-up surface weather shared by the METAR and TAF
+type demoSurface struct {
+	direction, speed, gust int
+	visibility             int // in statute miles
+	sky                    string
+	temperature, dewpoint  int
+	altimeter              int // in hundredths of inHg
+}
+
+// demoConditions draws surface weather typical of a VFR day in the month
+func demoConditions(r *rand.Rand, month time.Month) demoSurface {
+	s := demoSurface{
+		direction: 10 * (1 + r.Intn(36)),
+		speed:     r.Intn(16),
+		altimeter: 2980 + r.Intn(45),
+	}
+	if s.speed >= 10 && r.Intn(3) == 0 {
+		s.gust = s.speed + 6 + r.Intn(8)
+	}
+
+	// Warmest in July, coldest in January
+	seasonal := 14 - 12*math.Cos(2*math.Pi*float64(month-1)/12)
+	s.temperature = int(math.Round(seasonal)) + r.Intn(11) - 5
+	s.dewpoint = s.temperature - 2 - r.Intn(12)
+
+	switch n := r.Intn(10); {
+	case n < 5:
+		s.visibility, s.sky = 10, "CLR"
+	case n < 8:
+		s.visibility, s.sky = 10, fmt.Sprintf("FEW%03d SCT%03d", 30+r.Intn(20), 50+r.Intn(30))
+	default:
+		s.visibility, s.sky = 4+r.Intn(3), fmt.Sprintf("BKN%03d", 25+r.Intn(20))
+	}
+	return s
+}
+
+// wind formats the surface wind group, e.g. "21012G20KT"
+func (s demoSurface) wind() string {
+	if s.speed == 0 {
+		return "00000KT"
+	}
+	if s.gust > 0 {
+		return fmt.Sprintf("%03d%02dG%02dKT", s.direction, s.speed, s.gust)
+	}
+	return fmt.Sprintf("%03d%02dKT", s.direction, s.speed)
+}
+
+// demoMETAR formats a made-up routine observation
+func demoMETAR(r *rand.Rand, station string, observed time.Time) string {
+	s := demoConditions(r, observed.Month())
+	return fmt.Sprintf("%s %s %s %dSM %s %s/%s A%04d RMK AO2 DEMO",
+		station, observed.Format("021504Z"), s.wind(), s.visibility, s.sky,
+		demoDegrees(s.temperature), demoDegrees(s.dewpoint), s.altimeter)
+}
+
+// demoTAF formats a made-up 24 hour terminal forecast
+func demoTAF(r *rand.Rand, station string, issued time.Time) string {
+	s := demoConditions(r, issued.Month())
+	start := issued.Add(40 * time.Minute)
+	end := start.Add(24 * time.Hour)
+	visibility := "P6SM"
+	if s.visibility < 6 {
+		visibility = fmt.Sprintf("%dSM", s.visibility)
+	}
+	return fmt.Sprintf("TAF %s %s %s/%s %s %s %s",
+		station, issued.Format("021504Z"), start.Format("0215"), end.Format("0215"), s.wind(), visibility, s.sky)
+}
+
+// demoWindsAloft formats a made-up FB winds forecast line for a site
+// with its headers; 3000 ft has no temperature
+func demoWindsAloft(r *rand.Rand, station string, issued time.Time) string {
+	direction := 20 + r.Intn(12)  // Tens of degrees, westerly
+	speed := 5 + r.Intn(15)       // at 3000 ft
+	temperature := 8 - r.Intn(16) // at 6000 ft
+
+	levels := []int{3000, 6000, 9000, 12000, 18000}
+	header := "FT"
+	line := station
+	if len(line) == 4 && line[0] == 'K' {
+		line = line[1:]
+	}
+	for i, level := range levels {
+		header += fmt.Sprintf(" %7d", level)
+		group := fmt.Sprintf("%02d%02d", direction+i/2, speed+5*i)
+		if level > 3000 {
+			// 2°C colder per 1000 ft above 6000 ft
+			t := temperature - 2*(level-6000)/1000
+			if t < 0 {
+				group += fmt.Sprintf("-%02d", -t)
+			} else {
+				group += fmt.Sprintf("+%02d", t)
+			}
+		}
+		line += fmt.Sprintf(" %7s", group)
+	}
+	return fmt.Sprintf("DATA BASED ON %s\n%s\n%s", issued.Format("021504Z"), header, line)
+}
+
+// demoDegrees formats a METAR temperature, e.g. "M05"
+func demoDegrees(t int) string {
+	if t < 0 {
+		return fmt.Sprintf("M%02d", -t)
+	}
+	return fmt.Sprintf("%02d", t)
+}
@@ -0,0 +1,60 @@
+package weather
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDemoFetcher(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 18, 5, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	demo := &DemoFetcher{Seed: 7, Now: clock}
+
+	metar, err := demo.Fetch(context.Background(), METAR, "kjyo")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	obs, err := ParseMETAR(metar.Raw, now)
+	if err != nil {
+		t.Fatalf("Demo METAR %q does not decode: %v", metar.Raw, err)
+	}
+	if obs.Station != "KJYO" || !obs.Observed.Equal(time.Date(2026, time.October, 15, 17, 53, 0, 0, time.UTC)) ||
+		!obs.HasTemperature || obs.Altimeter < 29.8 || obs.Altimeter > 30.25 || obs.Category() > MVFR {
+		t.Errorf("Implausible demo METAR %q: %+v", metar.Raw, obs)
+	}
+	if !metar.Issued.Equal(obs.Observed) {
+		t.Errorf("Issued %v, observed %v", metar.Issued, obs.Observed)
+	}
+
+	// The same seed gives the same weather; another seed or station differs
+	again, _ := (&DemoFetcher{Seed: 7, Now: clock}).Fetch(context.Background(), METAR, "KJYO")
+	other, _ := (&DemoFetcher{Seed: 8, Now: clock}).Fetch(context.Background(), METAR, "KJYO")
+	elsewhere, _ := demo.Fetch(context.Background(), METAR, "KFDK")
+	if again.Raw != metar.Raw {
+		t.Errorf("Same seed: got %q and %q", metar.Raw, again.Raw)
+	}
+	if other.Raw == metar.Raw || elsewhere.Raw[5:] == metar.Raw[5:] {
+		t.Errorf("Seeds and stations should vary the weather: %q, %q, %q", metar.Raw, other.Raw, elsewhere.Raw)
+	}
+
+	for _, product := range []Product{TAF, WindsAloft} {
+		report, err := demo.Fetch(context.Background(), product, "KJYO")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", product, err)
+		}
+		issued, err := parseIssueTime(report.Raw, now)
+		if err != nil || !issued.Equal(report.Issued) || issued.After(now) {
+			t.Errorf("%s: issue time %v (%v), report says %v", product, issued, err, report.Issued)
+		}
+	}
+	winds, _ := demo.Fetch(context.Background(), WindsAloft, "KJYO")
+	if lines := strings.Split(winds.Raw, "\n"); len(lines) != 3 || !strings.HasPrefix(lines[2], "JYO ") {
+		t.Errorf("Winds aloft: got %q", winds.Raw)
+	}
+
+	if _, err := demo.Fetch(context.Background(), Product("pirep"), "KJYO"); err == nil {
+		t.Error("Expected an error for an unsupported product")
+	}
+}
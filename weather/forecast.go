@@ -0,0 +1,141 @@
+package weather
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/localtime"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// ForecastPoint is the forecast surface weather at one time
+type ForecastPoint struct {
+	Time        time.Time
+	Temperature float64   // in °C
+	Wind        wind.Wind // from true, as in TAF and MOS
+	Variable    bool      // Direction forecast as VRB
+	Altimeter   float64   // in inHg, 0 if not forecast
+}
+
+// Forecast is a station's forecast surface weather, in time order
+type Forecast []ForecastPoint
+
+// ParseForecastCSV reads a multi-day point forecast, such as one sampled
+// from model output, for use offline or beyond the TAF. The columns are time (with a zone,
+// e.g. 2026-10-15T18:00Z), temp_c, wind_dir and wind_speed in knots, with
+// optional wind_gust and altimeter (inHg) columns.
+func ParseForecastCSV(r io.Reader) (Forecast, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty forecast")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"time", "temp_c", "wind_dir", "wind_speed"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("forecast is missing the %s column", name)
+		}
+	}
+
+	var forecast Forecast
+	for n, record := range records[1:] {
+		line := n + 2
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		number := func(name string) (float64, error) {
+			s := field(name)
+			if s == "" {
+				return 0, nil
+			}
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: invalid %s %q", line, name, s)
+			}
+			return v, nil
+		}
+
+		t, err := localtime.Parse(field("time"), nil)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		p := ForecastPoint{Time: t}
+		if field("temp_c") == "" {
+			return nil, fmt.Errorf("line %d: temp_c is required", line)
+		}
+		if p.Temperature, err = number("temp_c"); err != nil {
+			return nil, err
+		}
+		direction, err := number("wind_dir")
+		if err != nil {
+			return nil, err
+		}
+		p.Wind.From = wind.TrueDirection(direction)
+		if p.Wind.Speed, err = number("wind_speed"); err != nil {
+			return nil, err
+		}
+		if p.Wind.Gust, err = number("wind_gust"); err != nil {
+			return nil, err
+		}
+		if p.Altimeter, err = number("altimeter"); err != nil {
+			return nil, err
+		}
+		forecast = append(forecast, p)
+	}
+
+	sort.Slice(forecast, func(i, j int) bool { return forecast[i].Time.Before(forecast[j].Time) })
+	return forecast, nil
+}
+
+// WriteCSV writes the forecast in the format ParseForecastCSV reads,
+// leaving the altimeter empty for points without one
+func (f Forecast) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"time", "temp_c", "wind_dir", "wind_speed", "wind_gust", "altimeter"})
+	for _, p := range f {
+		record := []string{
+			p.Time.UTC().Format("2006-01-02T15:04Z"),
+			strconv.FormatFloat(p.Temperature, 'f', 1, 64),
+			strconv.FormatFloat(p.Wind.From.Degrees, 'f', 0, 64),
+			strconv.FormatFloat(p.Wind.Speed, 'f', 0, 64),
+			"",
+			"",
+		}
+		if p.Wind.Gust > 0 {
+			record[4] = strconv.FormatFloat(p.Wind.Gust, 'f', 0, 64)
+		}
+		if p.Altimeter > 0 {
+			record[5] = strconv.FormatFloat(p.Altimeter, 'f', 2, 64)
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Between returns the forecast points from start (inclusive) to end (exclusive)
+func (f Forecast) Between(start, end time.Time) Forecast {
+	var points Forecast
+	for _, p := range f {
+		if !p.Time.Before(start) && p.Time.Before(end) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
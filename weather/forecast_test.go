@@ -0,0 +1,69 @@
+package weather
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseForecastCSV(t *testing.T) {
+	data := `time,temp_c,wind_dir,wind_speed,wind_gust,altimeter
+2026-10-16T18:00Z,24,180,12,20,29.95
+2026-10-15T18:00Z,21,270,8,,
+`
+	forecast, err := ParseForecastCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(forecast) != 2 {
+		t.Fatalf("Expected 2 points, got %d", len(forecast))
+	}
+
+	// Sorted by time
+	first, second := forecast[0], forecast[1]
+	if !first.Time.Equal(time.Date(2026, 10, 15, 18, 0, 0, 0, time.UTC)) || first.Temperature != 21 || first.Wind.Speed != 8 || first.Altimeter != 0 {
+		t.Errorf("Unexpected first point %+v", first)
+	}
+	if second.Wind.From.Degrees != 180 || second.Wind.Gust != 20 || second.Altimeter != 29.95 {
+		t.Errorf("Unexpected second point %+v", second)
+	}
+
+	day := forecast.Between(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC))
+	if len(day) != 1 || day[0].Temperature != 24 {
+		t.Errorf("Between: got %+v", day)
+	}
+}
+
+func TestForecastWriteCSV(t *testing.T) {
+	data := `time,temp_c,wind_dir,wind_speed,wind_gust,altimeter
+2026-10-15T18:00Z,21.0,270,8,,
+2026-10-16T18:00Z,24.5,180,12,20,29.95
+`
+	forecast, err := ParseForecastCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out strings.Builder
+	if err := forecast.WriteCSV(&out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != data {
+		t.Errorf("Expected\n%s\ngot\n%s", data, out.String())
+	}
+}
+
+func TestParseForecastCSVErrors(t *testing.T) {
+	testCases := map[string]string{
+		"Missing Column": "time,temp_c,wind_dir\n2026-10-15T18:00Z,21,270\n",
+		"Local Time":     "time,temp_c,wind_dir,wind_speed\n2026-10-15 18:00,21,270,8\n",
+		"Bad Number":     "time,temp_c,wind_dir,wind_speed\n2026-10-15T18:00Z,warm,270,8\n",
+		"No Temperature": "time,temp_c,wind_dir,wind_speed\n2026-10-15T18:00Z,,270,8\n",
+		"Empty":          "",
+	}
+	for name, data := range testCases {
+		if _, err := ParseForecastCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
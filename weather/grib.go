@@ -0,0 +1,73 @@
+package weather
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/grib"
+	"github.com/ryanbmilbourne/otto-perf/units"
+)
+
+// ForecastFromGRIB builds a point forecast from the 2 m temperature, 10 m
+// wind and sea level pressure fields of weather model output, one point per
+// valid time that has a temperature and wind. The altimeter comes from the
+// NCEP MSLMA reduction where present, otherwise from PRMSL, and is left out
+// when the model gives neither.
+func ForecastFromGRIB(messages []*grib.Message, lat, lon float64) (Forecast, error) {
+	type surface struct{ t, u, v, pressure, eta *grib.Message }
+	times := make(map[time.Time]*surface)
+	for _, m := range messages {
+		s := times[m.Valid()]
+		if s == nil {
+			s = &surface{}
+			times[m.Valid()] = s
+		}
+		switch {
+		case m.Parameter == grib.Temperature && m.Level == grib.Level{Type: grib.SurfaceHeightAboveGround, Value: 2}:
+			s.t = m
+		case m.Parameter == grib.UWind && m.Level == grib.Level{Type: grib.SurfaceHeightAboveGround, Value: 10}:
+			s.u = m
+		case m.Parameter == grib.VWind && m.Level == grib.Level{Type: grib.SurfaceHeightAboveGround, Value: 10}:
+			s.v = m
+		case m.Parameter == grib.SeaLevelPressure && m.Level.Type == grib.SurfaceMeanSeaLevel:
+			s.pressure = m
+		case m.Parameter == grib.SeaLevelPressureEta && m.Level.Type == grib.SurfaceMeanSeaLevel:
+			s.eta = m
+		}
+	}
+
+	var forecast Forecast
+	for valid, s := range times {
+		if s.t == nil || s.u == nil || s.v == nil {
+			continue
+		}
+		temperature, err := s.t.At(lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		w, err := grib.WindAt(s.u, s.v, lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		point := ForecastPoint{Time: valid, Temperature: units.KelvinToCelsius(temperature), Wind: w}
+
+		if s.eta != nil {
+			s.pressure = s.eta
+		}
+		if s.pressure != nil {
+			pressure, err := s.pressure.At(lat, lon)
+			if err != nil {
+				return nil, err
+			}
+			point.Altimeter = units.HectopascalsToInchesHg(pressure / 100)
+		}
+		forecast = append(forecast, point)
+	}
+
+	if len(forecast) == 0 {
+		return nil, fmt.Errorf("no 2 m TMP with 10 m UGRD and VGRD fields")
+	}
+	sort.Slice(forecast, func(i, j int) bool { return forecast[i].Time.Before(forecast[j].Time) })
+	return forecast, nil
+}
@@ -0,0 +1,238 @@
+package weather
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// Observation holds the fields of a METAR used for performance
+type Observation struct {
+	Station        string
+	Observed       time.Time
+	Wind           wind.Wind // from true; calm winds have zero speed
+	Variable       bool      // Direction reported as VRB
+	Temperature    float64   // in °C
+	Dewpoint       float64   // in °C
+	HasTemperature bool
+	Altimeter      float64 // in inHg, 0 if not reported
+	Visibility     float64 // in statute miles
+	HasVisibility  bool
+	Ceiling        float64  // Lowest broken or overcast layer or vertical visibility in ft AGL
+	HasCeiling     bool     // False when no layer forms a ceiling
+	Weather        []string // Present weather groups as reported, e.g. "-RA", "VCTS", "BR"
+	Convective     bool     // A cloud layer is reported as CB or TCU
+}
+
+var (
+	metarWind        = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS)$`)
+	metarTemperature = regexp.MustCompile(`^(M?\d{2})/(M?\d{2})?$`)
+	metarAltimeter   = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	metarVisibility  = regexp.MustCompile(`^([MP])?(?:(\d+)/(\d+)|(\d+))SM$`)
+	metarMeters      = regexp.MustCompile(`^(\d{4})$`)
+	metarWhole       = regexp.MustCompile(`^\d$`)
+	metarCeiling     = regexp.MustCompile(`^(BKN|OVC|VV)(\d{3})`)
+	metarConvective  = regexp.MustCompile(`^(FEW|SCT|BKN|OVC)\d{3}(CB|TCU)$`)
+	metarWeather     = regexp.MustCompile(`^(?:[-+]|VC)?(?:MI|PR|BC|DR|BL|SH|TS|FZ)?(?:DZ|RA|SN|SG|IC|PL|GR|GS|UP|BR|FG|FU|VA|DU|SA|HZ|PY|PO|SQ|FC|SS|DS)*$`)
+)
+
+// metersPerStatuteMile converts visibility reported in meters
+const metersPerStatuteMile = 1609.344
+
+// knotsPerMeterPerSecond converts wind speeds reported in MPS
+const knotsPerMeterPerSecond = 3600 / units.MetersPerNauticalMile
+
+// ParseMETAR decodes the station, observation time, wind, temperature and
+// altimeter setting, visibility, ceiling and present weather from a raw
+// METAR or SPECI. The observation month and year are taken relative to
+// ref, as for cached reports. Remarks are ignored.
+func ParseMETAR(raw string, ref time.Time) (*Observation, error) {
+	fields := strings.Fields(raw)
+	for len(fields) > 0 && (fields[0] == "METAR" || fields[0] == "SPECI") {
+		fields = fields[1:]
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("METAR too short: %q", raw)
+	}
+
+	m := &Observation{Station: normalizeStation(fields[0])}
+	observed, err := parseIssueTime(fields[1], ref)
+	if err != nil {
+		return nil, fmt.Errorf("METAR %s: %w", m.Station, err)
+	}
+	m.Observed = observed
+
+	haveWind := false
+	whole := 0.0 // The whole miles of a visibility such as "1 1/2SM"
+	for _, field := range fields[2:] {
+		if field == "RMK" {
+			break
+		}
+
+		if v := metarVisibility.FindStringSubmatch(field); v != nil && !m.HasVisibility {
+			if v[4] != "" {
+				m.Visibility, _ = strconv.ParseFloat(v[4], 64)
+			} else {
+				numerator, _ := strconv.ParseFloat(v[2], 64)
+				denominator, _ := strconv.ParseFloat(v[3], 64)
+				if denominator > 0 {
+					m.Visibility = whole + numerator/denominator
+				}
+			}
+			m.HasVisibility = true
+			continue
+		}
+		if metarWhole.MatchString(field) && !m.HasVisibility {
+			whole, _ = strconv.ParseFloat(field, 64)
+			continue
+		}
+		if v := metarMeters.FindStringSubmatch(field); v != nil && !m.HasVisibility {
+			meters, _ := strconv.ParseFloat(v[1], 64)
+			m.Visibility = meters / metersPerStatuteMile
+			m.HasVisibility = true
+			continue
+		}
+
+		if metarConvective.MatchString(field) {
+			m.Convective = true
+		}
+		if c := metarCeiling.FindStringSubmatch(field); c != nil && !m.HasCeiling {
+			hundreds, _ := strconv.ParseFloat(c[2], 64)
+			m.Ceiling = hundreds * 100
+			m.HasCeiling = true
+			continue
+		}
+
+		if w := metarWind.FindStringSubmatch(field); w != nil && !haveWind {
+			haveWind = true
+			scale := 1.0
+			if w[4] == "MPS" {
+				scale = knotsPerMeterPerSecond
+			}
+			speed, _ := strconv.ParseFloat(w[2], 64)
+			m.Wind.Speed = speed * scale
+			if w[3] != "" {
+				gust, _ := strconv.ParseFloat(w[3], 64)
+				m.Wind.Gust = gust * scale
+			}
+			if w[1] == "VRB" {
+				m.Variable = true
+			} else {
+				direction, _ := strconv.ParseFloat(w[1], 64)
+				m.Wind.From = wind.TrueDirection(direction)
+			}
+			continue
+		}
+
+		if len(strings.TrimLeft(field, "-+")) >= 2 && metarWeather.MatchString(field) {
+			m.Weather = append(m.Weather, field)
+			continue
+		}
+
+		if t := metarTemperature.FindStringSubmatch(field); t != nil {
+			m.Temperature = metarDegrees(t[1])
+			m.Dewpoint = metarDegrees(t[2])
+			m.HasTemperature = true
+			continue
+		}
+
+		if a := metarAltimeter.FindStringSubmatch(field); a != nil {
+			value, _ := strconv.ParseFloat(a[2], 64)
+			if a[1] == "A" {
+				m.Altimeter = value / 100
+			} else {
+				m.Altimeter = units.HectopascalsToInchesHg(value)
+			}
+		}
+	}
+
+	if !haveWind {
+		return nil, fmt.Errorf("METAR %s: no wind group", m.Station)
+	}
+	return m, nil
+}
+
+// HasWeather reports whether a present weather group contains the
+// phenomenon or descriptor, e.g. "TS" for "+TSRA" or "VCTS"
+func (o *Observation) HasWeather(code string) bool {
+	for _, w := range o.Weather {
+		if strings.Contains(strings.TrimLeft(w, "-+"), code) {
+			return true
+		}
+	}
+	return false
+}
+
+// FlightCategory is the FAA flight category of an observation
+type FlightCategory int
+
+// Flight categories, from best to worst
+const (
+	VFR  FlightCategory = iota // Ceiling above 3000 ft and visibility above 5 SM
+	MVFR                       // Ceiling 1000 to 3000 ft or visibility 3 to 5 SM
+	IFR                        // Ceiling 500 to below 1000 ft or visibility 1 to below 3 SM
+	LIFR                       // Ceiling below 500 ft or visibility below 1 SM
+)
+
+// String returns the category's abbreviation, e.g. "MVFR"
+func (c FlightCategory) String() string {
+	switch c {
+	case MVFR:
+		return "MVFR"
+	case IFR:
+		return "IFR"
+	case LIFR:
+		return "LIFR"
+	}
+	return "VFR"
+}
+
+// Category returns the flight category of the observation. A visibility
+// or ceiling that is not reported does not lower the category.
+func (o *Observation) Category() FlightCategory {
+	category := VFR
+	worse := func(c FlightCategory) {
+		if c > category {
+			category = c
+		}
+	}
+	if o.HasCeiling {
+		switch {
+		case o.Ceiling < 500:
+			worse(LIFR)
+		case o.Ceiling < 1000:
+			worse(IFR)
+		case o.Ceiling <= 3000:
+			worse(MVFR)
+		}
+	}
+	if o.HasVisibility {
+		switch {
+		case o.Visibility < 1:
+			worse(LIFR)
+		case o.Visibility < 3:
+			worse(IFR)
+		case o.Visibility <= 5:
+			worse(MVFR)
+		}
+	}
+	return category
+}
+
+// metarDegrees decodes a METAR temperature such as "24" or "M05"
+func metarDegrees(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	negative := strings.HasPrefix(s, "M")
+	v, _ := strconv.ParseFloat(strings.TrimPrefix(s, "M"), 64)
+	if negative {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,100 @@
+package weather
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMETAR(t *testing.T) {
+	ref := time.Date(2026, time.October, 15, 18, 5, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		raw         string
+		direction   float64
+		speed, gust float64
+		variable    bool
+		temperature float64
+		altimeter   float64
+	}{
+		{"Routine", "KJYO 151753Z 17008KT 10SM CLR 24/12 A3002 RMK AO2", 170, 8, 0, false, 24, 30.02},
+		{"Gusts And Prefix", "METAR KIAD 151752Z 31015G25KT 280V340 10SM FEW050 M05/M12 A2992", 310, 15, 25, false, -5, 29.92},
+		{"Variable", "KFDK 151750Z AUTO VRB03KT 10SM CLR 18/06 A3010", 0, 3, 0, true, 18, 30.10},
+		{"Metric", "EGLL 151750Z 25005MPS 9999 SCT030 14/09 Q1013", 250, 5 * 3600 / 1852.0, 0, false, 14, 1013 / 33.8638866667},
+		{"Calm", "KAPA 151753Z 00000KT 10SM SKC 05/M03 A3021", 0, 0, 0, false, 5, 30.21},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ParseMETAR(tc.raw, ref)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.speed > 0 && !tc.variable && m.Wind.From.Degrees != tc.direction {
+				t.Errorf("Direction: got %v, expected %.0f", m.Wind.From, tc.direction)
+			}
+			if math.Abs(m.Wind.Speed-tc.speed) > 1e-9 || m.Wind.Gust != tc.gust || m.Variable != tc.variable {
+				t.Errorf("Wind: got %+v variable %v", m.Wind, m.Variable)
+			}
+			if !m.HasTemperature || m.Temperature != tc.temperature {
+				t.Errorf("Temperature: got %.0f (%v), expected %.0f", m.Temperature, m.HasTemperature, tc.temperature)
+			}
+			if math.Abs(m.Altimeter-tc.altimeter) > 1e-9 {
+				t.Errorf("Altimeter: got %.2f, expected %.2f", m.Altimeter, tc.altimeter)
+			}
+		})
+	}
+
+	m, err := ParseMETAR("KJYO 151753Z 17008KT 10SM CLR 24/12 A3002", ref)
+	if err != nil || m.Station != "KJYO" || !m.Observed.Equal(time.Date(2026, time.October, 15, 17, 53, 0, 0, time.UTC)) {
+		t.Errorf("Station and time: got %+v (%v)", m, err)
+	}
+
+	m, err = ParseMETAR("KJYO 151753Z 22012G30KT 3SM -SHRA VCTS BR BKN030CB OVC080 24/20 A3002 RMK TSB25", ref)
+	if err != nil || strings.Join(m.Weather, " ") != "-SHRA VCTS BR" || !m.Convective || !m.HasWeather("TS") || m.HasWeather("SN") {
+		t.Errorf("Present weather: got %q convective %v (%v)", m.Weather, m.Convective, err)
+	}
+
+	for _, raw := range []string{"", "KJYO", "KJYO 151753Z 10SM CLR", "KJYO TODAY 17008KT"} {
+		if _, err := ParseMETAR(raw, ref); err == nil {
+			t.Errorf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestFlightCategory(t *testing.T) {
+	ref := time.Date(2026, time.October, 15, 18, 5, 0, 0, time.UTC)
+
+	testCases := []struct {
+		raw        string
+		visibility float64
+		ceiling    float64
+		category   FlightCategory
+	}{
+		{"KJYO 151753Z 17008KT 10SM CLR 24/12 A3002", 10, 0, VFR},
+		{"KJYO 151753Z 17008KT 10SM FEW030 SCT045 BKN070 24/12 A3002", 10, 7000, VFR},
+		{"KJYO 151753Z 17008KT 4SM BR OVC050 24/12 A3002", 4, 5000, MVFR},
+		{"KJYO 151753Z 17008KT 10SM SCT008 BKN025 OVC040 24/12 A3002", 10, 2500, MVFR},
+		{"KJYO 151753Z 17008KT 1 1/2SM BR OVC012 24/12 A3002", 1.5, 1200, IFR},
+		{"KJYO 151753Z 17008KT M1/4SM FG VV002 24/12 A3002", 0.25, 200, LIFR},
+		{"EGLL 151750Z 25005MPS 9999 BKN008 14/09 Q1013", 9999 / 1609.344, 800, IFR},
+	}
+
+	for _, tc := range testCases {
+		m, err := ParseMETAR(tc.raw, ref)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.raw, err)
+		}
+		if !m.HasVisibility || math.Abs(m.Visibility-tc.visibility) > 1e-9 {
+			t.Errorf("%s: visibility %.2f (%v), expected %.2f", tc.raw, m.Visibility, m.HasVisibility, tc.visibility)
+		}
+		if m.HasCeiling != (tc.ceiling > 0) || m.Ceiling != tc.ceiling {
+			t.Errorf("%s: ceiling %.0f (%v), expected %.0f", tc.raw, m.Ceiling, m.HasCeiling, tc.ceiling)
+		}
+		if got := m.Category(); got != tc.category {
+			t.Errorf("%s: category %s, expected %s", tc.raw, got, tc.category)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Recorder wraps a Fetcher and saves each report it returns in Dir, one
+// file per product and station, so that a briefing can later be computed
+// again with exactly the same weather by a Replay of the directory
+type Recorder struct {
+	Dir     string
+	Fetcher Fetcher
+}
+
+// Fetch fetches a report and records it, replacing any earlier recording
+// of the product for the station
+func (r *Recorder) Fetch(ctx context.Context, product Product, station string) (*Report, error) {
+	report, err := r.Fetcher.Fetch(ctx, product, station)
+	if err != nil {
+		return nil, err
+	}
+
+	path := recordingPath(r.Dir, report.Product, report.Station)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Replay serves the reports recorded in Dir, without the network
+type Replay struct {
+	Dir string
+}
+
+// Fetch returns the recorded report of a product for a station
+func (r *Replay) Fetch(ctx context.Context, product Product, station string) (*Report, error) {
+	station = normalizeStation(station)
+	data, err := os.ReadFile(recordingPath(r.Dir, product, station))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no %s for %s recorded in %s", product, station, r.Dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("recorded %s for %s: %v", product, station, err)
+	}
+	return &report, nil
+}
+
+// recordingPath is the file a recorded report is kept in
+func recordingPath(dir string, product Product, station string) string {
+	return filepath.Join(dir, "weather", string(product), station+".json")
+}
@@ -0,0 +1,45 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, time.October, 17, 14, 0, 0, 0, time.UTC)
+	fetcher := &fakeFetcher{report: Report{
+		Station: "KJYO",
+		Product: METAR,
+		Issued:  now.Add(-7 * time.Minute),
+		Fetched: now,
+		Raw:     "KJYO 171353Z 17008KT 10SM CLR 24/12 A3002",
+	}}
+
+	recorder := &Recorder{Dir: dir, Fetcher: fetcher}
+	if _, err := recorder.Fetch(context.Background(), METAR, "KJYO"); err != nil {
+		t.Fatalf("Recording: %v", err)
+	}
+
+	// Failures are passed on and not recorded
+	fetcher.err = errors.New("provider unreachable")
+	if _, err := recorder.Fetch(context.Background(), TAF, "KJYO"); err == nil {
+		t.Error("Expected the provider error")
+	}
+
+	replay := &Replay{Dir: dir}
+	report, err := replay.Fetch(context.Background(), METAR, "kjyo")
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if report.Raw != fetcher.report.Raw || !report.Issued.Equal(fetcher.report.Issued) || !report.Fetched.Equal(now) {
+		t.Errorf("Replayed %+v, expected %+v", *report, fetcher.report)
+	}
+
+	if _, err := replay.Fetch(context.Background(), TAF, "KJYO"); err == nil || !strings.Contains(err.Error(), "no taf for KJYO recorded") {
+		t.Errorf("Expected a not recorded error, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package weather
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/units"
+	"github.com/ryanbmilbourne/otto-perf/wind"
+)
+
+// TerminalForecast holds the fields of a terminal forecast used for performance
+type TerminalForecast struct {
+	Station      string
+	Issued       time.Time
+	Start        time.Time // Valid period
+	End          time.Time
+	Groups       []TAFGroup       // The base forecast, then the change groups in order
+	Temperatures []TAFTemperature // From the TX and TN groups, in time order
+}
+
+// TAFGroup is the base forecast of a TAF or one of its change groups
+type TAFGroup struct {
+	Change    string    // "" for the base forecast, "FM", "BECMG", "TEMPO", "PROB30" or "PROB40"
+	Start     time.Time // For FM the group runs to the next FM or the end of the TAF
+	End       time.Time
+	Wind      wind.Wind // from true
+	Variable  bool      // Direction forecast as VRB
+	HasWind   bool
+	Altimeter float64 // in inHg from a QNH group, 0 if not forecast
+}
+
+// TAFTemperature is a forecast maximum (TX) or minimum (TN) temperature
+type TAFTemperature struct {
+	Time        time.Time
+	Temperature float64 // in °C
+	Max         bool
+}
+
+var (
+	tafPeriod      = regexp.MustCompile(`^(\d{2})(\d{2})/(\d{2})(\d{2})$`)
+	tafFrom        = regexp.MustCompile(`^FM(\d{2})(\d{2})(\d{2})$`)
+	tafTemperature = regexp.MustCompile(`^(TX|TN)(M?\d{2})/(\d{2})(\d{2})Z$`)
+	tafQNH         = regexp.MustCompile(`^QNH(\d{4})INS$`)
+)
+
+// ParseTAF decodes the valid period, the wind and QNH of the base forecast
+// and each change group, and the TX and TN temperatures of a raw TAF. The
+// month and year are taken relative to ref, as for cached reports.
+// Remarks are ignored.
+func ParseTAF(raw string, ref time.Time) (*TerminalForecast, error) {
+	fields := strings.Fields(raw)
+	for len(fields) > 0 && (fields[0] == "TAF" || fields[0] == "AMD" || fields[0] == "COR") {
+		fields = fields[1:]
+	}
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("TAF too short: %q", raw)
+	}
+
+	t := &TerminalForecast{Station: normalizeStation(fields[0])}
+	issued, err := parseIssueTime(fields[1], ref)
+	if err != nil {
+		return nil, fmt.Errorf("TAF %s: %w", t.Station, err)
+	}
+	t.Issued = issued
+	p := tafPeriod.FindStringSubmatch(fields[2])
+	if p == nil {
+		return nil, fmt.Errorf("TAF %s: no valid period", t.Station)
+	}
+	t.Start, t.End = tafTime(issued, p[1], p[2], "00"), tafTime(issued, p[3], p[4], "00")
+
+	group := TAFGroup{Start: t.Start, End: t.End}
+	for i := 3; i < len(fields); i++ {
+		field := fields[i]
+		if field == "RMK" {
+			break
+		}
+
+		// A new change group ends the one before
+		change := ""
+		switch {
+		case tafFrom.MatchString(field):
+			change = "FM"
+		case field == "BECMG", field == "TEMPO", field == "PROB30", field == "PROB40":
+			change = field
+		}
+		if change != "" {
+			t.Groups = append(t.Groups, group)
+			group = TAFGroup{Change: change, Start: t.Start, End: t.End}
+			if m := tafFrom.FindStringSubmatch(field); m != nil {
+				group.Start = tafTime(issued, m[1], m[2], m[3])
+				continue
+			}
+			// PROB30 TEMPO is one group
+			if strings.HasPrefix(change, "PROB") && i+1 < len(fields) && fields[i+1] == "TEMPO" {
+				i++
+			}
+			if i+1 < len(fields) {
+				if m := tafPeriod.FindStringSubmatch(fields[i+1]); m != nil {
+					group.Start, group.End = tafTime(issued, m[1], m[2], "00"), tafTime(issued, m[3], m[4], "00")
+					i++
+				}
+			}
+			continue
+		}
+
+		if w := metarWind.FindStringSubmatch(field); w != nil && !group.HasWind {
+			group.HasWind = true
+			scale := 1.0
+			if w[4] == "MPS" {
+				scale = knotsPerMeterPerSecond
+			}
+			speed, _ := strconv.ParseFloat(w[2], 64)
+			group.Wind.Speed = speed * scale
+			if w[3] != "" {
+				gust, _ := strconv.ParseFloat(w[3], 64)
+				group.Wind.Gust = gust * scale
+			}
+			if w[1] == "VRB" {
+				group.Variable = true
+			} else {
+				direction, _ := strconv.ParseFloat(w[1], 64)
+				group.Wind.From = wind.TrueDirection(direction)
+			}
+			continue
+		}
+		if m := tafQNH.FindStringSubmatch(field); m != nil {
+			value, _ := strconv.ParseFloat(m[1], 64)
+			group.Altimeter = value / 100
+			continue
+		}
+		if m := metarAltimeter.FindStringSubmatch(field); m != nil && m[1] == "Q" {
+			value, _ := strconv.ParseFloat(m[2], 64)
+			group.Altimeter = units.HectopascalsToInchesHg(value)
+			continue
+		}
+		if m := tafTemperature.FindStringSubmatch(field); m != nil {
+			t.Temperatures = append(t.Temperatures, TAFTemperature{
+				Time:        tafTime(issued, m[3], m[4], "00"),
+				Temperature: metarDegrees(m[2]),
+				Max:         m[1] == "TX",
+			})
+		}
+	}
+	t.Groups = append(t.Groups, group)
+
+	if !t.Groups[0].HasWind {
+		return nil, fmt.Errorf("TAF %s: no wind group", t.Station)
+	}
+	sort.Slice(t.Temperatures, func(i, j int) bool { return t.Temperatures[i].Time.Before(t.Temperatures[j].Time) })
+	return t, nil
+}
+
+// tafTime resolves the ddhh or ddhhmm of a TAF group against the issue
+// time. The forecast runs at most a few days past the issue, so the day is
+// taken in the issue month unless that puts it well before the issue; hour
+// 24 is midnight at the end of the day.
+func tafTime(issued time.Time, day, hour, minute string) time.Time {
+	d, _ := strconv.Atoi(day)
+	h, _ := strconv.Atoi(hour)
+	m, _ := strconv.Atoi(minute)
+	at := func(months int) time.Time {
+		first := time.Date(issued.Year(), issued.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, months, 0)
+		return first.AddDate(0, 0, d-1).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	t := at(0)
+	if t.Before(issued.Add(-24 * time.Hour)) {
+		t = at(1)
+	}
+	return t
+}
+
+// prevailing returns the forecast wind and altimeter at a time: the base
+// forecast as changed by the FM groups begun and the BECMG groups
+// completed by then. TEMPO and PROB groups are not the prevailing
+// conditions and are left out.
+func (t *TerminalForecast) prevailing(at time.Time) TAFGroup {
+	current := t.Groups[0]
+	for _, g := range t.Groups[1:] {
+		switch g.Change {
+		case "FM":
+			if !at.Before(g.Start) {
+				current = g
+			}
+		case "BECMG":
+			if at.Before(g.End) {
+				continue
+			}
+			if g.HasWind {
+				current.Wind, current.Variable, current.HasWind = g.Wind, g.Variable, true
+			}
+			if g.Altimeter > 0 {
+				current.Altimeter = g.Altimeter
+			}
+		}
+	}
+	return current
+}
+
+// temperatureAt interpolates the TX and TN temperatures linearly in time,
+// holding the first and last beyond them; without them it returns fallback
+func (t *TerminalForecast) temperatureAt(at time.Time, fallback float64) float64 {
+	temps := t.Temperatures
+	switch {
+	case len(temps) == 0:
+		return fallback
+	case !at.After(temps[0].Time):
+		return temps[0].Temperature
+	case !at.Before(temps[len(temps)-1].Time):
+		return temps[len(temps)-1].Temperature
+	}
+	for i := 1; i < len(temps); i++ {
+		if at.Before(temps[i].Time) {
+			a, b := temps[i-1], temps[i]
+			fraction := at.Sub(a.Time).Hours() / b.Time.Sub(a.Time).Hours()
+			return a.Temperature + fraction*(b.Temperature-a.Temperature)
+		}
+	}
+	return temps[len(temps)-1].Temperature
+}
+
+// Forecast samples the TAF every step over its valid period, with the
+// prevailing wind and altimeter at each time. The temperature is
+// interpolated between the TX and TN groups; a TAF without them, as most
+// US TAFs are, forecasts no temperature, and temperature is used instead.
+func (t *TerminalForecast) Forecast(step time.Duration, temperature float64) Forecast {
+	var forecast Forecast
+	for at := t.Start; at.Before(t.End); at = at.Add(step) {
+		g := t.prevailing(at)
+		forecast = append(forecast, ForecastPoint{
+			Time:        at,
+			Temperature: t.temperatureAt(at, temperature),
+			Wind:        g.Wind,
+			Variable:    g.Variable,
+			Altimeter:   g.Altimeter,
+		})
+	}
+	return forecast
+}
@@ -0,0 +1,72 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseTAF(t *testing.T) {
+	ref := time.Date(2026, time.October, 30, 18, 5, 0, 0, time.UTC)
+	raw := "TAF AMD KIAD 301720Z 3018/3124 18010KT P6SM SCT050 QNH2992INS TX24/3020Z TN12/3110Z " +
+		"TEMPO 3020/3024 22015G25KT " +
+		"FM310200 VRB05KT P6SM SKC " +
+		"BECMG 3112/3114 27012KT QNH3005INS " +
+		"PROB30 TEMPO 3118/3122 TSRA"
+	taf, err := ParseTAF(raw, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if taf.Station != "KIAD" || !taf.Start.Equal(time.Date(2026, time.October, 30, 18, 0, 0, 0, time.UTC)) ||
+		!taf.End.Equal(time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected header %+v", taf)
+	}
+	if len(taf.Groups) != 5 || taf.Groups[1].Change != "TEMPO" || taf.Groups[4].Change != "PROB30" ||
+		!taf.Groups[2].Start.Equal(time.Date(2026, time.October, 31, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected groups %+v", taf.Groups)
+	}
+	if len(taf.Temperatures) != 2 || !taf.Temperatures[0].Max || taf.Temperatures[1].Temperature != 12 {
+		t.Errorf("Unexpected temperatures %+v", taf.Temperatures)
+	}
+
+	forecast := taf.Forecast(time.Hour, 15)
+	if len(forecast) != 30 {
+		t.Fatalf("Expected hourly points over the 30 h period, got %d", len(forecast))
+	}
+	tests := []struct {
+		hour      int // after the start of the TAF
+		from      float64
+		speed     float64
+		variable  bool
+		altimeter float64
+		temp      float64
+	}{
+		{0, 180, 10, false, 29.92, 24}, // Before the TX time
+		{2, 180, 10, false, 29.92, 24}, // TEMPO gusts are not prevailing
+		{8, 0, 5, true, 0, 24 - 12.0*6/14},
+		{19, 0, 5, true, 0, 12},         // BECMG not yet complete
+		{20, 270, 12, false, 30.05, 12}, // After the TN time
+	}
+	for _, tc := range tests {
+		p := forecast[tc.hour]
+		if p.Wind.From.Degrees != tc.from || p.Wind.Speed != tc.speed || p.Variable != tc.variable || p.Altimeter != tc.altimeter ||
+			math.Abs(p.Temperature-tc.temp) > 0.01 {
+			t.Errorf("Hour %d: unexpected point %+v", tc.hour, p)
+		}
+	}
+
+	// Without TX and TN the given temperature is used
+	plain, err := ParseTAF("TAF KJYO 301720Z 3018/3118 17008KT P6SM SKC", ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := plain.Forecast(3*time.Hour, 21); len(f) != 8 || f[7].Temperature != 21 || f[7].Wind.Speed != 8 {
+		t.Errorf("Unexpected forecast %+v", f)
+	}
+
+	for _, raw := range []string{"TAF KJYO", "TAF KJYO 301720Z P6SM", "TAF KJYO 301720Z 3018/3118 P6SM SKC"} {
+		if _, err := ParseTAF(raw, ref); err == nil {
+			t.Errorf("Expected an error for %q", raw)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Package weather fetches raw aviation weather products (METAR, TAF and
+// winds/temperatures aloft) and caches them on disk
+package weather
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Product identifies a weather product
+type Product string
+
+// Supported products
+const (
+	METAR      Product = "metar"
+	TAF        Product = "taf"
+	WindsAloft Product = "windtemp"
+)
+
+// Report is a single raw weather product for a station
+type Report struct {
+	Station string    `json:"station"`
+	Product Product   `json:"product"`
+	Issued  time.Time `json:"issued"`  // Observation or issue time decoded from the report
+	Fetched time.Time `json:"fetched"` // When the report was retrieved from the provider
+	Raw     string    `json:"raw"`
+	Stale   bool      `json:"-"` // Served from cache after the provider could not be reached
+}
+
+// Fetcher retrieves raw weather reports
+type Fetcher interface {
+	Fetch(ctx context.Context, product Product, station string) (*Report, error)
+}
+
+// issuePattern matches the ddhhmmZ group of a METAR/TAF or the
+// "DATA BASED ON ddhhmmZ" header of a winds aloft forecast
+var issuePattern = regexp.MustCompile(`\b(\d{2})(\d{2})(\d{2})Z\b`)
+
+// parseIssueTime decodes the first ddhhmmZ group in a report. The month and
+// year are not part of the report, so the most recent matching day on or
+// before the reference time (allowing for small clock skew) is used.
+func parseIssueTime(raw string, ref time.Time) (time.Time, error) {
+	m := issuePattern.FindStringSubmatch(raw)
+	if m == nil {
+		return time.Time{}, fmt.Errorf("no issue time found in report")
+	}
+
+	day, _ := strconv.Atoi(m[1])
+	hour, _ := strconv.Atoi(m[2])
+	minute, _ := strconv.Atoi(m[3])
+	if day < 1 || day > 31 || hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid issue time %q", m[0])
+	}
+
+	ref = ref.UTC()
+	limit := ref.Add(time.Hour)
+	for months := 0; months < 12; months++ {
+		year, month, _ := ref.AddDate(0, -months, 0).Date()
+		t := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+		// Skip days that overflow a short month (e.g. the 31st in April)
+		if t.Day() != day {
+			continue
+		}
+		if !t.After(limit) {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid issue time %q", m[0])
+}
+
+// normalizeStation upper-cases and trims a station identifier
+func normalizeStation(station string) string {
+	return strings.ToUpper(strings.TrimSpace(station))
+}
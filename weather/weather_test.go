@@ -0,0 +1,38 @@
+package weather
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseIssueTime(t *testing.T) {
+	ref := time.Date(2026, time.October, 15, 18, 5, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		raw  string
+		want time.Time
+	}{
+		{"METAR Same Day", "KJYO 151753Z 17008KT 10SM CLR 24/12 A3002", time.Date(2026, time.October, 15, 17, 53, 0, 0, time.UTC)},
+		{"TAF", "TAF KIAD 151720Z 1518/1624 18010KT P6SM SCT050", time.Date(2026, time.October, 15, 17, 20, 0, 0, time.UTC)},
+		{"Previous Month", "KJYO 302353Z 00000KT 10SM CLR", time.Date(2026, time.September, 30, 23, 53, 0, 0, time.UTC)},
+		{"Winds Aloft Header", "DATA BASED ON 151200Z\nVALID 151800Z", time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)},
+		{"Slight Clock Skew", "KJYO 151815Z 17008KT", time.Date(2026, time.October, 15, 18, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseIssueTime(tc.raw, ref)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("Got %v, expected %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := parseIssueTime("KJYO AUTO 17008KT", ref); err == nil {
+		t.Error("Expected error for report without issue time, but got none")
+	}
+}
@@ -0,0 +1,239 @@
+// Package webhook takes scenario submissions pushed by other systems, such
+// as a club's scheduling software when a booking is made. A mapping file
+// says where in the sender's own JSON each scenario field is, so the
+// sender does not have to speak the scenario format, and where to send
+// the briefing back. Submissions and callbacks are signed with a shared
+// secret.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryanbmilbourne/otto-perf/scenario"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a body under the shared
+// secret, as "sha256=" and the hex digest
+const SignatureHeader = "X-Otto-Signature"
+
+// callbackAttempts is how many times a callback is tried before giving up
+const callbackAttempts = 3
+
+// retryPause is the pause before the first callback retry, doubled for each
+// one after
+var retryPause = time.Second
+
+// Mapping says where the parts of a submission are in the sender's JSON.
+// Each location is a path of object keys and array indexes separated by
+// dots, e.g. "booking.legs.0.departure".
+type Mapping struct {
+	Fields   map[string]string `json:"fields"`             // Scenario field, e.g. "weight", to the path of its value
+	ID       string            `json:"id,omitempty"`       // Path of the sender's reference, echoed in the callback
+	Aircraft string            `json:"aircraft,omitempty"` // Path of the aircraft profile ID (default: pa28-161)
+	Runway   string            `json:"runway,omitempty"`   // Path of the departure runway end
+
+	// The briefing is posted to the URL at Callback in the submission,
+	// or to CallbackURL when the sender always uses the same one
+	Callback    string `json:"callback,omitempty"`
+	CallbackURL string `json:"callback_url,omitempty"`
+
+	// Secret signs callbacks and, when set, must sign every submission
+	Secret string `json:"secret,omitempty"`
+}
+
+// Submission is a scenario picked out of a sender's document
+type Submission struct {
+	ID       string
+	Aircraft string
+	Runway   string
+	Callback string
+	Scenario *scenario.Scenario
+}
+
+// Load reads a mapping file. Unknown fields are rejected so a misspelt key
+// cannot silently drop a value.
+func Load(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &Mapping{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// validate checks that the mapping can produce a scenario and a callback
+func (m *Mapping) validate() error {
+	if len(m.Fields) == 0 {
+		return errors.New("the mapping has no fields")
+	}
+	if m.Callback == "" && m.CallbackURL == "" {
+		return errors.New("the mapping needs a callback path or a callback_url")
+	}
+	for field, path := range m.Fields {
+		if path == "" {
+			return fmt.Errorf("field %s has no path", field)
+		}
+	}
+	return nil
+}
+
+// Extract picks a submission out of a sender's document. Values are
+// checked as in a scenario file, so quantities may carry units such as
+// "1050 kg"; paths missing from the document leave their fields out.
+func (m *Mapping) Extract(body []byte) (*Submission, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("invalid submission: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	for field, path := range m.Fields {
+		if v, ok := lookup(doc, path); ok {
+			values[field] = v
+		}
+	}
+	s, err := scenario.Decode("submission", values)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &Submission{Scenario: s, Callback: m.CallbackURL}
+	for _, f := range []struct {
+		path string
+		into *string
+	}{{m.ID, &sub.ID}, {m.Aircraft, &sub.Aircraft}, {m.Runway, &sub.Runway}, {m.Callback, &sub.Callback}} {
+		if f.path == "" {
+			continue
+		}
+		v, ok := lookup(doc, f.path)
+		if !ok {
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			*f.into = v
+		case float64:
+			*f.into = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%s: expected a string or number", f.path)
+		}
+	}
+	if sub.Callback == "" {
+		return nil, fmt.Errorf("%s: no callback URL in the submission", m.Callback)
+	}
+	return sub, nil
+}
+
+// lookup follows a dotted path of keys and array indexes into a decoded
+// JSON document
+func lookup(doc interface{}, path string) (interface{}, bool) {
+	v := doc
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if v, ok = node[key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+// Sign returns the signature header value of a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a submission's signature header against the mapping's
+// secret; without a secret every submission is accepted
+func (m *Mapping) Verify(body []byte, signature string) bool {
+	if m.Secret == "" {
+		return true
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(m.Secret, body)))
+}
+
+// Post sends v as JSON to a callback URL, signed with the mapping's
+// secret. Network errors and 5xx answers are retried with a growing
+// pause; any other non-2xx answer fails at once.
+func (m *Mapping) Post(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	pause := retryPause
+	for attempt := 1; ; attempt++ {
+		err = post(ctx, client, url, m.Secret, body)
+		var status statusError
+		if err == nil || attempt == callbackAttempts || (errors.As(err, &status) && status < 500) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+		pause *= 2
+	}
+}
+
+// statusError is a non-2xx callback answer
+type statusError int
+
+// Error returns e.g. "callback answered 404 Not Found"
+func (e statusError) Error() string {
+	return fmt.Sprintf("callback answered %d %s", int(e), http.StatusText(int(e)))
+}
+
+// post makes one callback request
+func post(ctx context.Context, client *http.Client, url, secret string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return statusError(resp.StatusCode)
+	}
+	return nil
+}
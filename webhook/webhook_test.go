@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testMapping picks the scenario out of a booking system's document
+var testMapping = &Mapping{
+	Fields: map[string]string{
+		"airport":       "booking.legs.0.from",
+		"departure":     "booking.start",
+		"weight":        "booking.load.takeoff_weight",
+		"temperature_c": "booking.forecast.temperature",
+	},
+	ID:       "booking.id",
+	Aircraft: "booking.aircraft.type",
+	Callback: "hooks.briefing",
+	Secret:   "s3cret",
+}
+
+func TestExtract(t *testing.T) {
+	sub, err := testMapping.Extract([]byte(`{
+		"booking": {
+			"id": 4711,
+			"start": "2026-10-15T14:00Z",
+			"legs": [{"from": "KJYO", "to": "KFDK"}],
+			"aircraft": {"type": "pa28-161"},
+			"load": {"takeoff_weight": "1040 kg"}
+		},
+		"hooks": {"briefing": "https://club.example.com/briefings"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sub.Scenario
+	if sub.ID != "4711" || sub.Aircraft != "pa28-161" || sub.Callback != "https://club.example.com/briefings" || sub.Runway != "" {
+		t.Errorf("Unexpected submission %+v", sub)
+	}
+	if s.Airport != "KJYO" || s.Departure != "2026-10-15T14:00Z" || s.Weight == nil || int(*s.Weight) != 2292 || s.TemperatureC != nil {
+		t.Errorf("Unexpected scenario %+v", s)
+	}
+
+	testCases := []struct {
+		name  string
+		body  string
+		error string
+	}{
+		{"Not JSON", `{"booking":`, "invalid submission"},
+		{"Bad Value", `{"booking": {"load": {"takeoff_weight": "heavy"}}, "hooks": {"briefing": "https://x"}}`, "submission: weight: expected number"},
+		{"No Callback", `{"booking": {"legs": [{"from": "KJYO"}]}}`, "no callback URL"},
+		{"Bad ID", `{"booking": {"id": {"n": 1}}, "hooks": {"briefing": "https://x"}}`, "booking.id: expected a string or number"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := testMapping.Extract([]byte(tc.body))
+			if err == nil || !strings.Contains(err.Error(), tc.error) {
+				t.Errorf("Got %v, expected an error containing %q", err, tc.error)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	m, err := Load(write("ok.json", `{"fields": {"airport": "from"}, "callback_url": "https://x"}`))
+	if err != nil || m.Fields["airport"] != "from" || m.CallbackURL != "https://x" {
+		t.Errorf("Got %+v, %v", m, err)
+	}
+	for name, content := range map[string]string{
+		"unknown.json":     `{"fields": {"airport": "from"}, "callback_url": "https://x", "secert": "x"}`,
+		"no-fields.json":   `{"callback_url": "https://x"}`,
+		"no-callback.json": `{"fields": {"airport": "from"}}`,
+		"empty-path.json":  `{"fields": {"airport": ""}, "callback_url": "https://x"}`,
+	} {
+		if _, err := Load(write(name, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	body := []byte(`{"booking": {"id": 1}}`)
+	if !testMapping.Verify(body, Sign("s3cret", body)) {
+		t.Errorf("Expected the signature to verify")
+	}
+	if testMapping.Verify(body, Sign("guess", body)) || testMapping.Verify(body, "") {
+		t.Errorf("Expected a wrong or missing signature to fail")
+	}
+	if !(&Mapping{}).Verify(body, "") {
+		t.Errorf("Expected any submission to verify without a secret")
+	}
+}
+
+func TestPost(t *testing.T) {
+	retryPause = time.Millisecond
+	defer func() { retryPause = time.Second }()
+
+	var calls int
+	var got map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(SignatureHeader) != Sign("s3cret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/flaky") && calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/gone") {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		json.Unmarshal(body, &got)
+	}))
+	defer ts.Close()
+
+	if err := testMapping.Post(context.Background(), ts.Client(), ts.URL+"/flaky", map[string]string{"id": "4711"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || got["id"] != "4711" {
+		t.Errorf("Got %d calls and %v, expected a retry and the body", calls, got)
+	}
+
+	calls = 0
+	err := testMapping.Post(context.Background(), ts.Client(), ts.URL+"/gone", map[string]string{})
+	if err == nil || err.Error() != "callback answered 410 Gone" || calls != 1 {
+		t.Errorf("Got %v after %d calls, expected no retry of a 410", err, calls)
+	}
+}
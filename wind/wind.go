@@ -0,0 +1,189 @@
+// Package wind decomposes winds into runway components, keeping track of
+// whether each direction is referenced to true or magnetic north.
+//
+// Runway numbers, ATIS and tower-reported winds are magnetic, while METAR,
+// TAF and winds aloft directions are true. Mixing the two silently skews
+// every crosswind by the local magnetic variation, so directions here
+// always carry their reference and are converted explicitly.
+package wind
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Reference is the north reference of a direction
+type Reference int
+
+const (
+	// True directions are referenced to true (geographic) north
+	True Reference = iota
+	// Magnetic directions are referenced to magnetic north
+	Magnetic
+)
+
+// String returns "true" or "magnetic"
+func (r Reference) String() string {
+	if r == Magnetic {
+		return "magnetic"
+	}
+	return "true"
+}
+
+// ParseReference parses "true"/"T" or "magnetic"/"M"
+func ParseReference(s string) (Reference, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "t":
+		return True, nil
+	case "magnetic", "mag", "m":
+		return Magnetic, nil
+	}
+	return True, fmt.Errorf("invalid direction reference %q (expected 'true' or 'magnetic')", s)
+}
+
+// Direction is a direction in degrees with an explicit north reference
+type Direction struct {
+	Degrees   float64
+	Reference Reference
+}
+
+// TrueDirection creates a direction referenced to true north
+func TrueDirection(degrees float64) Direction {
+	return Direction{Degrees: normalize(degrees), Reference: True}
+}
+
+// MagneticDirection creates a direction referenced to magnetic north
+func MagneticDirection(degrees float64) Direction {
+	return Direction{Degrees: normalize(degrees), Reference: Magnetic}
+}
+
+// ToTrue converts the direction to true using the magnetic variation in
+// degrees (east positive): true = magnetic + variation
+func (d Direction) ToTrue(variation float64) Direction {
+	if d.Reference == True {
+		return d
+	}
+	return TrueDirection(d.Degrees + variation)
+}
+
+// ToMagnetic converts the direction to magnetic using the magnetic
+// variation in degrees (east positive): magnetic = true - variation
+func (d Direction) ToMagnetic(variation float64) Direction {
+	if d.Reference == Magnetic {
+		return d
+	}
+	return MagneticDirection(d.Degrees - variation)
+}
+
+// String formats the direction, e.g. "170°M"
+func (d Direction) String() string {
+	suffix := "T"
+	if d.Reference == Magnetic {
+		suffix = "M"
+	}
+	return fmt.Sprintf("%03.0f°%s", d.Degrees, suffix)
+}
+
+// Wind is a wind blowing from a direction
+type Wind struct {
+	From  Direction
+	Speed float64 // in knots
+	Gust  float64 // peak gust in knots, 0 if none
+}
+
+// Components are a wind resolved relative to a heading
+type Components struct {
+	Headwind  float64 // in knots, negative for a tailwind
+	Crosswind float64 // in knots, positive from the right, negative from the left
+}
+
+// CrosswindSide describes which side the crosswind comes from
+func (c Components) CrosswindSide() string {
+	switch {
+	case c.Crosswind > 0:
+		return "right"
+	case c.Crosswind < 0:
+		return "left"
+	default:
+		return "none"
+	}
+}
+
+// Decompose resolves a wind into headwind and crosswind components along a
+// heading. Both directions are converted to true with the given magnetic
+// variation (east positive) before they are compared, so a true wind and a
+// magnetic runway heading can be combined safely.
+func Decompose(w Wind, heading Direction, variation float64) Components {
+	from := w.From.ToTrue(variation).Degrees
+	hdg := heading.ToTrue(variation).Degrees
+
+	angle := (from - hdg) * math.Pi / 180
+	return Components{
+		Headwind:  w.Speed * math.Cos(angle),
+		Crosswind: w.Speed * math.Sin(angle),
+	}
+}
+
+// Correction is the solution of the wind triangle for a course
+type Correction struct {
+	WindCorrection  float64   // Wind correction angle in degrees, positive to the right
+	TrueHeading     Direction // Heading to fly, referenced to true north
+	MagneticHeading Direction // Heading to fly, referenced to magnetic north
+	Groundspeed     float64   // in knots
+	Components      Components
+}
+
+// Correct solves the wind triangle for the heading and groundspeed that
+// track a course at a true airspeed. As with Decompose, the wind and course
+// may use either reference and are converted with the given magnetic
+// variation (east positive). It fails if the crosswind exceeds the true
+// airspeed or the aircraft cannot make headway along the course.
+func Correct(w Wind, course Direction, trueAirspeed, variation float64) (Correction, error) {
+	if trueAirspeed <= 0 {
+		return Correction{}, fmt.Errorf("true airspeed must be positive, got %.0f", trueAirspeed)
+	}
+
+	c := Decompose(w, course, variation)
+	sinWCA := c.Crosswind / trueAirspeed
+	if math.Abs(sinWCA) >= 1 {
+		return Correction{}, fmt.Errorf("crosswind of %.0f kt exceeds the true airspeed of %.0f kt", math.Abs(c.Crosswind), trueAirspeed)
+	}
+
+	groundspeed := trueAirspeed*math.Sqrt(1-sinWCA*sinWCA) - c.Headwind
+	if groundspeed <= 0 {
+		return Correction{}, fmt.Errorf("no headway against a %.0f kt headwind at %.0f kt", c.Headwind, trueAirspeed)
+	}
+
+	wca := math.Asin(sinWCA) * 180 / math.Pi
+	heading := TrueDirection(course.ToTrue(variation).Degrees + wca)
+	return Correction{
+		WindCorrection:  wca,
+		TrueHeading:     heading,
+		MagneticHeading: heading.ToMagnetic(variation),
+		Groundspeed:     groundspeed,
+		Components:      c,
+	}, nil
+}
+
+// RunwayHeading returns the magnetic heading implied by a runway end
+// designator such as "17", "09" or "35L"
+func RunwayHeading(designator string) (Direction, error) {
+	number := strings.TrimRight(strings.ToUpper(strings.TrimSpace(designator)), "LCR")
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > 36 {
+		return Direction{}, fmt.Errorf("invalid runway designator %q", designator)
+	}
+	return MagneticDirection(float64(n) * 10), nil
+}
+
+// normalize wraps degrees into (0, 360], using 360 rather than 0 for north
+// as is conventional for headings and wind directions
+func normalize(degrees float64) float64 {
+	d := math.Mod(degrees, 360)
+	if d <= 0 {
+		d += 360
+	}
+	return d
+}
@@ -0,0 +1,114 @@
+package wind
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	testCases := []struct {
+		name      string
+		wind      Wind
+		heading   Direction
+		variation float64
+		headwind  float64
+		crosswind float64
+	}{
+		{"Straight Down Runway", Wind{From: MagneticDirection(170), Speed: 10}, MagneticDirection(170), -10, 10, 0},
+		{"Direct Crosswind From Right", Wind{From: MagneticDirection(260), Speed: 10}, MagneticDirection(170), -10, 0, 10},
+		{"Tailwind", Wind{From: TrueDirection(340), Speed: 5}, TrueDirection(160), 0, -5, 0},
+		// METAR wind of 170 true on runway 17 (170 magnetic, 160 true) with 10°W
+		// variation is 10° off the nose, from the right
+		{"True Wind On Magnetic Runway", Wind{From: TrueDirection(170), Speed: 20}, MagneticDirection(170), -10, 20 * math.Cos(10*math.Pi/180), 20 * math.Sin(10*math.Pi/180)},
+		{"Across North", Wind{From: TrueDirection(10), Speed: 10}, TrueDirection(350), 0, 10 * math.Cos(20*math.Pi/180), 10 * math.Sin(20*math.Pi/180)},
+		{"From Left", Wind{From: MagneticDirection(130), Speed: 10}, MagneticDirection(170), 5, 10 * math.Cos(40*math.Pi/180), -10 * math.Sin(40*math.Pi/180)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Decompose(tc.wind, tc.heading, tc.variation)
+			if math.Abs(got.Headwind-tc.headwind) > 1e-9 || math.Abs(got.Crosswind-tc.crosswind) > 1e-9 {
+				t.Errorf("Got %+v, expected headwind %.3f crosswind %.3f", got, tc.headwind, tc.crosswind)
+			}
+		})
+	}
+}
+
+func TestDirectionConversion(t *testing.T) {
+	mag := MagneticDirection(5)
+	tru := mag.ToTrue(-10)
+	if tru.Degrees != 355 || tru.Reference != True {
+		t.Errorf("005°M with 10°W variation: got %v, expected 355°T", tru)
+	}
+	if back := tru.ToMagnetic(-10); back != mag {
+		t.Errorf("Round trip: got %v, expected %v", back, mag)
+	}
+	if got := TrueDirection(0).String(); got != "360°T" {
+		t.Errorf("North formatting: got %q, expected 360°T", got)
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	testCases := []struct {
+		name        string
+		wind        Wind
+		course      Direction
+		variation   float64
+		wca         float64
+		trueHeading float64
+		magHeading  float64
+		groundspeed float64
+	}{
+		{"Calm", Wind{From: TrueDirection(360), Speed: 0}, TrueDirection(90), -10, 0, 90, 100, 100},
+		{"Headwind", Wind{From: TrueDirection(90), Speed: 20}, TrueDirection(90), 0, 0, 90, 90, 80},
+		// 20 kt from the north on an eastbound course: crab left by asin(0.2)
+		{"Crosswind From Left", Wind{From: TrueDirection(360), Speed: 20}, TrueDirection(90), 0, -11.537, 78.463, 78.463, math.Sqrt(100*100 - 400)},
+		// Magnetic course 100 with 10°W variation is 090 true
+		{"Magnetic Course", Wind{From: TrueDirection(180), Speed: 20}, MagneticDirection(100), -10, 11.537, 101.537, 111.537, math.Sqrt(100*100 - 400)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Correct(tc.wind, tc.course, 100, tc.variation)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if math.Abs(got.WindCorrection-tc.wca) > 0.001 || math.Abs(got.TrueHeading.Degrees-tc.trueHeading) > 0.001 ||
+				math.Abs(got.MagneticHeading.Degrees-tc.magHeading) > 0.001 || math.Abs(got.Groundspeed-tc.groundspeed) > 1e-9 {
+				t.Errorf("Got WCA %.3f TH %v MH %v GS %.3f, expected %.3f %.3f %.3f %.3f",
+					got.WindCorrection, got.TrueHeading, got.MagneticHeading, got.Groundspeed,
+					tc.wca, tc.trueHeading, tc.magHeading, tc.groundspeed)
+			}
+			if got.TrueHeading.Reference != True || got.MagneticHeading.Reference != Magnetic {
+				t.Errorf("Unexpected heading references %v, %v", got.TrueHeading, got.MagneticHeading)
+			}
+		})
+	}
+
+	if _, err := Correct(Wind{From: TrueDirection(360), Speed: 120}, TrueDirection(90), 100, 0); err == nil {
+		t.Error("Expected an error when the crosswind exceeds the true airspeed")
+	}
+	if _, err := Correct(Wind{From: TrueDirection(90), Speed: 120}, TrueDirection(90), 100, 0); err == nil {
+		t.Error("Expected an error with no headway")
+	}
+}
+
+func TestRunwayHeading(t *testing.T) {
+	testCases := map[string]float64{"17": 170, "09": 90, "35L": 350, "1C": 10, "36": 360}
+	for designator, want := range testCases {
+		got, err := RunwayHeading(designator)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", designator, err)
+			continue
+		}
+		if got.Degrees != want || got.Reference != Magnetic {
+			t.Errorf("%s: got %v, expected %.0f°M", designator, got, want)
+		}
+	}
+
+	for _, bad := range []string{"", "37", "0", "H1"} {
+		if _, err := RunwayHeading(bad); err == nil {
+			t.Errorf("%q: expected error, but got none", bad)
+		}
+	}
+}